@@ -4,26 +4,40 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
 	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"strings"
+	"sync"
 	"syscall"
+	"text/tabwriter"
 	"time"
 
 	"github.com/MicahParks/keyfunc/v3"
+	"github.com/fsnotify/fsnotify"
+	"github.com/honeybadger-io/honeybadger-mcp-server/internal/buildinfo"
 	"github.com/honeybadger-io/honeybadger-mcp-server/internal/config"
 	"github.com/honeybadger-io/honeybadger-mcp-server/internal/hbmcp"
 	"github.com/honeybadger-io/honeybadger-mcp-server/internal/httptransport"
 	"github.com/honeybadger-io/honeybadger-mcp-server/internal/logging"
+	"github.com/honeybadger-io/honeybadger-mcp-server/internal/telemetry"
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
-// Set via -ldflags "-X main.version=..." at Docker build time.
-var version = "dev"
+// Set via -ldflags "-X main.version=... -X main.commit=..." at build time.
+// Without commit, the revision the Go toolchain records for builds from a
+// git checkout is reported instead.
+var (
+	version = "dev"
+	commit  string
+)
 
 var (
 	cfgFile string
@@ -53,6 +67,60 @@ By default the server runs in stateless mode, suitable for horizontally scaled
 deployments behind a load balancer (e.g. AWS Fargate behind an ALB).`,
 		RunE: runHTTP,
 	}
+
+	listenCmd = &cobra.Command{
+		Use:   "listen",
+		Short: "Run the MCP server in STDIO mode with a webhook receiver",
+		Long: `Run the MCP server on standard input/output, like stdio, along with an HTTP
+receiver for the payloads of a Honeybadger webhook integration. Each payload is
+pushed to the client as a notifications/message and listed in the
+honeybadger://webhooks/events resource, so an agent can react to new faults
+without polling.`,
+		RunE: runListen,
+	}
+
+	doctorCmd = &cobra.Command{
+		Use:   "doctor",
+		Short: "Check the configured token and API connection",
+		Long: `Validate the configured auth token against the Honeybadger API, report the
+accounts and projects it can access, measure API latency, and show whether
+read-only mode is on. Exits non-zero when the API can't be reached with the
+configured credentials.`,
+		RunE: runDoctor,
+	}
+
+	configCmd = &cobra.Command{
+		Use:   "config",
+		Short: "Inspect the server configuration",
+	}
+
+	healthcheckCmd = &cobra.Command{
+		Use:   "healthcheck",
+		Short: "Check that the server can start, for container health checks",
+		Long: `Validate the configuration the same way stdio does, without starting the
+server or making an MCP handshake, and exit non-zero if it is invalid. With
+--url, probe a running http-mode server's /healthz or /readyz endpoint instead,
+for images that have no curl or wget. Use doctor to check the API connection.`,
+		Args: cobra.NoArgs,
+		RunE: runHealthcheck,
+	}
+
+	versionCmd = &cobra.Command{
+		Use:   "version",
+		Short: "Print the server's version and the commit it was built from",
+		Args:  cobra.NoArgs,
+		RunE:  runVersion,
+	}
+
+	configValidateCmd = &cobra.Command{
+		Use:   "validate",
+		Short: "Validate the configuration and print the effective settings",
+		Long: `Check the config file for unknown keys and mistyped values, validate the
+configuration combined from flags, environment variables, and the config file,
+and print the effective value of every setting along with where it came from.
+Exits non-zero when the configuration is invalid.`,
+		RunE: runConfigValidate,
+	}
 )
 
 func init() {
@@ -63,8 +131,15 @@ func init() {
 
 	addCommonFlags(stdioCmd)
 	addCommonFlags(httpCmd)
+	addCommonFlags(listenCmd)
+	addCommonFlags(doctorCmd)
+	addCommonFlags(healthcheckCmd)
 	// stdio-only: http mode gates on token scope instead.
 	stdioCmd.Flags().Bool("read-only", true, "Run in read-only mode, excluding destructive tools")
+	listenCmd.Flags().Bool("read-only", true, "Run in read-only mode, excluding destructive tools")
+	doctorCmd.Flags().Bool("read-only", true, "Read-only setting to report (the same one stdio uses)")
+	healthcheckCmd.Flags().Bool("read-only", true, "Read-only setting to validate (the same one stdio uses)")
+	healthcheckCmd.Flags().String("url", "", "Probe this health endpoint of a running http-mode server (e.g. http://localhost:8080/readyz) instead of validating the configuration")
 
 	// HTTP-specific flags (bound to viper here since only httpCmd defines them)
 	httpCmd.Flags().String("address", ":8080", "Address to listen on (e.g. :8080)")
@@ -72,31 +147,163 @@ func init() {
 	httpCmd.Flags().Bool("stateless", true, "Run in stateless mode (recommended for horizontally scaled deployments)")
 	httpCmd.Flags().String("public-url", "", "Public origin of this MCP server (e.g. https://mcp.honeybadger.io). Required to advertise OAuth Protected Resource Metadata and serve the 401 discovery challenge")
 	httpCmd.Flags().String("authorization-server", "", "OAuth authorization server origin (e.g. https://app.honeybadger.io). Required when --public-url is set")
+	httpCmd.Flags().Bool("stdio", false, "Also serve MCP on standard input/output, for a local client sharing this process's caches and limits. Calls over stdio use --auth-token and the read-only setting, as in stdio mode, and the server stops when the stdio client disconnects")
 	httpCmd.Flags().String("resource-url", "", "OAuth resource identifier advertised in PRM and required as the token aud claim. Must match the authorization server's configured resource URL (default: public-url + endpoint-path)")
 	_ = viper.BindPFlag("resource-url", httpCmd.Flags().Lookup("resource-url"))
 	_ = viper.BindPFlag("address", httpCmd.Flags().Lookup("address"))
 	_ = viper.BindPFlag("endpoint-path", httpCmd.Flags().Lookup("endpoint-path"))
 	_ = viper.BindPFlag("stateless", httpCmd.Flags().Lookup("stateless"))
+	_ = viper.BindPFlag("stdio", httpCmd.Flags().Lookup("stdio"))
 	_ = viper.BindPFlag("public-url", httpCmd.Flags().Lookup("public-url"))
 	_ = viper.BindPFlag("authorization-server", httpCmd.Flags().Lookup("authorization-server"))
 
-	rootCmd.AddCommand(stdioCmd, httpCmd)
+	// Webhook receiver flags (bound to viper here since only listenCmd defines them)
+	listenCmd.Flags().String("webhook-address", "127.0.0.1:8090", "Address the webhook receiver listens on (e.g. :8090 for every interface, which needs --webhook-secret)")
+	listenCmd.Flags().String("webhook-path", "/webhooks/honeybadger", "HTTP path the webhook receiver accepts payloads on")
+	listenCmd.Flags().String("webhook-secret", "", "Shared secret webhook requests must carry in the token query parameter or the "+hbmcp.WebhookSecretHeader+" header")
+	_ = viper.BindPFlag("webhook-address", listenCmd.Flags().Lookup("webhook-address"))
+	_ = viper.BindPFlag("webhook-path", listenCmd.Flags().Lookup("webhook-path"))
+	_ = viper.BindPFlag("webhook-secret", listenCmd.Flags().Lookup("webhook-secret"))
+
+	addCommonFlags(configValidateCmd)
+	configValidateCmd.Flags().Bool("read-only", true, "Read-only setting to validate (the same one stdio uses)")
+	configValidateCmd.Flags().String("transport", config.TransportStdio, "Transport to validate for (stdio or http)")
+	configCmd.AddCommand(configValidateCmd)
+
+	rootCmd.AddCommand(stdioCmd, httpCmd, listenCmd, doctorCmd, healthcheckCmd, configCmd, versionCmd)
 }
 
 func addCommonFlags(cmd *cobra.Command) {
-	cmd.Flags().String("auth-token", "", "Honeybadger API token (required)")
-	cmd.Flags().String("api-url", "https://app.honeybadger.io", "Honeybadger API URL")
+	cmd.Flags().String("auth-token", "", "Honeybadger API token (required), or a reference to it: keychain:SERVICE[/ACCOUNT], op://VAULT/ITEM/FIELD, or exec:COMMAND")
+	cmd.Flags().String("api-url", config.RegionAPIURLs[config.RegionUS], "Honeybadger API URL")
+	cmd.Flags().String("region", "", "Honeybadger region whose API URL to use instead of --api-url: us or eu")
+	cmd.Flags().String("api-path-prefix", config.DefaultAPIPathPrefix, "Path the Data API is served under, for self-hosted installs that serve it elsewhere or at another version")
+	cmd.Flags().Bool("detect-capabilities", false, "Probe the API at startup and hide the tools of features it doesn't serve, such as Insights on older self-hosted installs")
 	cmd.Flags().String("instructions-url", config.DefaultInstructionsURL, "Base URL the LLM reference topics are fetched from")
 	cmd.Flags().String("log-level", "info", "Log level (debug, info, warn, error)")
+	cmd.Flags().String("read-only-behavior", config.ReadOnlyHide, "What read-only mode does with write tools: hide (leave them out of the tool list) or error (list them, but refuse calls with a read-only error)")
+	cmd.Flags().String("enabled-tools", "", "Comma-separated tool name globs to expose (e.g. list_*,get_*); all tools when empty")
+	cmd.Flags().String("disabled-tools", "", "Comma-separated tool name globs to hide, applied after --enabled-tools")
+	cmd.Flags().String("allowed-project-ids", "", "Comma-separated project IDs tools may act on; all projects the token can access when empty")
+	cmd.Flags().String("denied-project-ids", "", "Comma-separated project IDs tools may not act on, applied after --allowed-project-ids")
+	cmd.Flags().Bool("defer-tools", false, "Advertise only search_tools and invoke_tool; full tool schemas are fetched on demand through search_tools")
+	cmd.Flags().String("audit-log", "", "Append a JSON line per tool call (secrets redacted) to this file")
+	cmd.Flags().String("proxy", "", "Proxy URL for outbound requests (http, https, or socks5); overrides HTTPS_PROXY/HTTP_PROXY")
+	cmd.Flags().String("ca-bundle", "", "PEM file of extra CA certificates to trust for outbound requests")
+	cmd.Flags().Duration("api-timeout", config.DefaultAPITimeout, "Time limit for each Honeybadger API request, retries included")
+	cmd.Flags().Int("api-retries", 0, "Retries for API requests that fail with a network error, 429, 502, 503, or 504")
+	cmd.Flags().Duration("api-retry-backoff", 500*time.Millisecond, "Wait before the first API retry; doubles for each retry after that")
+	cmd.Flags().String("auth-style", "", "How the token is sent to the API: basic-username, basic-password, or bearer (default basic-username, or bearer for http)")
+	cmd.Flags().Bool("lenient-decoding", false, "Coerce notice, fault, and project fields of unexpected types instead of failing the tool call")
+	cmd.Flags().Bool("confirm-destructive", false, "Make destructive tools return a confirmation token and summary first, and act only when called again with the token")
+	cmd.Flags().String("reference-cache-dir", config.DefaultReferenceCacheDir(), "Directory reference topics are cached in across restarts, for fast and offline get_reference calls; empty disables the cache")
+	cmd.Flags().Bool("refresh-reference", false, "Refetch every reference topic into the cache at startup")
+	cmd.Flags().Bool("raw-api", false, "Register raw_api_request, which sends requests to any Honeybadger Data API endpoint; it is a write tool, so read-only mode hides it")
+	cmd.Flags().Bool("not-found-lookup", false, "When get_fault or get_project gets a 404, look through the token's accounts for the project or fault and name where it is in the error")
+	cmd.Flags().Int("insights-preflight-rows", config.DefaultInsightsPreflightRows, "Row count above which a query_insights preflight holds the query back")
+	cmd.Flags().Float64("api-log-sample-rate", config.DefaultAPILogSampleRate, "Fraction (0-1) of successful API requests logged at debug level; failed ones are always logged")
+	cmd.Flags().Int("session-max-api-calls", 0, "Honeybadger API requests each session may make before its tool calls are refused (0 for no limit)")
+	cmd.Flags().Int("session-max-rows", 0, "Result rows each session's tool calls may return before further calls are refused (0 for no limit)")
+	cmd.Flags().Int("max-concurrent-calls", config.DefaultMaxConcurrentCalls, "Tool calls that may run at once; others wait for a slot (0 for no limit)")
+	cmd.Flags().Duration("queue-timeout", config.DefaultQueueTimeout, "How long a tool call waits for a slot before it fails")
+	cmd.Flags().Int("max-result-tokens", 0, "Estimated tokens above which a tool result is replaced by a summary with instructions for a narrower call (0 for no limit)")
+	cmd.Flags().String("fixtures", "", "Answer API requests from the recorded JSON responses in this directory instead of the network; no auth token needed")
+	cmd.Flags().Float64("chaos", 0, "Developer mode: fraction (0-1) of API requests to fail with an injected 429, 500, or timeout")
+}
+
+// loadConfigFromFlags loads the config the server runs with, resolving an
+// auth-token reference to the token.
+func loadConfigFromFlags(cmd *cobra.Command, transportMode string) (*config.Config, error) {
+	return loadConfig(cmd, transportMode, resolveAuthToken)
+}
+
+// checkConfigFromFlags loads the config only to check it, so an auth-token
+// reference is left unresolved rather than running its provider.
+func checkConfigFromFlags(cmd *cobra.Command, transportMode string) (*config.Config, error) {
+	return loadConfig(cmd, transportMode, func(value string) (string, error) { return value, nil })
+}
+
+// resolvedToken is the token the auth-token reference ref resolved to.
+// Config reloads reuse it rather than running a password manager or
+// command again each time; a reference that changes is resolved afresh.
+var resolvedToken struct {
+	sync.Mutex
+	ref, token string
+}
+
+func resolveAuthToken(value string) (string, error) {
+	if !config.IsTokenReference(value) {
+		return value, nil
+	}
+	resolvedToken.Lock()
+	defer resolvedToken.Unlock()
+	if resolvedToken.ref == value {
+		return resolvedToken.token, nil
+	}
+	token, err := config.ResolveToken(context.Background(), value)
+	if err != nil {
+		return "", err
+	}
+	resolvedToken.ref, resolvedToken.token = value, token
+	return token, nil
 }
 
 // Bound to viper here (not in addCommonFlags) so the inactive subcommand's
 // empty default doesn't shadow the active subcommand's user-supplied value.
-func loadConfigFromFlags(cmd *cobra.Command, transportMode string) (*config.Config, error) {
+func loadConfig(cmd *cobra.Command, transportMode string, resolveToken func(string) (string, error)) (*config.Config, error) {
 	_ = viper.BindPFlag("auth-token", cmd.Flags().Lookup("auth-token"))
 	_ = viper.BindPFlag("api-url", cmd.Flags().Lookup("api-url"))
 	_ = viper.BindPFlag("instructions-url", cmd.Flags().Lookup("instructions-url"))
 	_ = viper.BindPFlag("log-level", cmd.Flags().Lookup("log-level"))
+	_ = viper.BindPFlag("chaos", cmd.Flags().Lookup("chaos"))
+	_ = viper.BindPFlag("read-only-behavior", cmd.Flags().Lookup("read-only-behavior"))
+	_ = viper.BindPFlag("enabled-tools", cmd.Flags().Lookup("enabled-tools"))
+	_ = viper.BindPFlag("disabled-tools", cmd.Flags().Lookup("disabled-tools"))
+	_ = viper.BindPFlag("allowed-project-ids", cmd.Flags().Lookup("allowed-project-ids"))
+	_ = viper.BindPFlag("denied-project-ids", cmd.Flags().Lookup("denied-project-ids"))
+	_ = viper.BindPFlag("defer-tools", cmd.Flags().Lookup("defer-tools"))
+	_ = viper.BindPFlag("audit-log", cmd.Flags().Lookup("audit-log"))
+	_ = viper.BindPFlag("proxy", cmd.Flags().Lookup("proxy"))
+	_ = viper.BindPFlag("ca-bundle", cmd.Flags().Lookup("ca-bundle"))
+	_ = viper.BindPFlag("api-timeout", cmd.Flags().Lookup("api-timeout"))
+	_ = viper.BindPFlag("api-retries", cmd.Flags().Lookup("api-retries"))
+	_ = viper.BindPFlag("api-retry-backoff", cmd.Flags().Lookup("api-retry-backoff"))
+	_ = viper.BindPFlag("auth-style", cmd.Flags().Lookup("auth-style"))
+	_ = viper.BindPFlag("lenient-decoding", cmd.Flags().Lookup("lenient-decoding"))
+	_ = viper.BindPFlag("fixtures", cmd.Flags().Lookup("fixtures"))
+	_ = viper.BindPFlag("confirm-destructive", cmd.Flags().Lookup("confirm-destructive"))
+	_ = viper.BindPFlag("reference-cache-dir", cmd.Flags().Lookup("reference-cache-dir"))
+	_ = viper.BindPFlag("refresh-reference", cmd.Flags().Lookup("refresh-reference"))
+	_ = viper.BindPFlag("raw-api", cmd.Flags().Lookup("raw-api"))
+	_ = viper.BindPFlag("not-found-lookup", cmd.Flags().Lookup("not-found-lookup"))
+	_ = viper.BindPFlag("insights-preflight-rows", cmd.Flags().Lookup("insights-preflight-rows"))
+	_ = viper.BindPFlag("api-log-sample-rate", cmd.Flags().Lookup("api-log-sample-rate"))
+	_ = viper.BindPFlag("session-max-api-calls", cmd.Flags().Lookup("session-max-api-calls"))
+	_ = viper.BindPFlag("session-max-rows", cmd.Flags().Lookup("session-max-rows"))
+	_ = viper.BindPFlag("max-concurrent-calls", cmd.Flags().Lookup("max-concurrent-calls"))
+	_ = viper.BindPFlag("queue-timeout", cmd.Flags().Lookup("queue-timeout"))
+	_ = viper.BindPFlag("max-result-tokens", cmd.Flags().Lookup("max-result-tokens"))
+	_ = viper.BindPFlag("region", cmd.Flags().Lookup("region"))
+	_ = viper.BindPFlag("api-path-prefix", cmd.Flags().Lookup("api-path-prefix"))
+	_ = viper.BindPFlag("detect-capabilities", cmd.Flags().Lookup("detect-capabilities"))
+
+	if err := checkConfigFile(); err != nil {
+		return nil, err
+	}
+
+	allowedProjects, err := projectIDs("allowed-project-ids")
+	if err != nil {
+		return nil, err
+	}
+	deniedProjects, err := projectIDs("denied-project-ids")
+	if err != nil {
+		return nil, err
+	}
+
+	authToken, err := resolveToken(viper.GetString("auth-token"))
+	if err != nil {
+		return nil, fmt.Errorf("auth-token: %w", err)
+	}
 
 	// Resolve manually: CLI flag wins, otherwise env/config/default.
 	readOnly := viper.GetBool("read-only")
@@ -104,15 +311,173 @@ func loadConfigFromFlags(cmd *cobra.Command, transportMode string) (*config.Conf
 		readOnly, _ = cmd.Flags().GetBool("read-only")
 	}
 	return config.Load(
-		viper.GetString("auth-token"),
-		viper.GetString("api-url"),
+		authToken,
+		apiURLSetting(cmd),
 		viper.GetString("instructions-url"),
 		viper.GetString("log-level"),
 		readOnly,
 		transportMode,
+		config.WithChaosRate(viper.GetFloat64("chaos")),
+		config.WithReadOnlyBehavior(viper.GetString("read-only-behavior")),
+		config.WithEnabledTools(toolPatterns("enabled-tools")),
+		config.WithDisabledTools(toolPatterns("disabled-tools")),
+		config.WithAllowedProjectIDs(allowedProjects),
+		config.WithDeniedProjectIDs(deniedProjects),
+		config.WithDeferTools(viper.GetBool("defer-tools")),
+		config.WithAuditLog(viper.GetString("audit-log")),
+		config.WithProxy(viper.GetString("proxy")),
+		config.WithCABundle(viper.GetString("ca-bundle")),
+		config.WithAPITimeout(viper.GetDuration("api-timeout")),
+		config.WithAPIRetries(viper.GetInt("api-retries")),
+		config.WithAPIRetryBackoff(viper.GetDuration("api-retry-backoff")),
+		config.WithAuthStyle(viper.GetString("auth-style")),
+		config.WithLenientDecoding(viper.GetBool("lenient-decoding")),
+		config.WithFixtures(viper.GetString("fixtures")),
+		config.WithConfirmDestructive(viper.GetBool("confirm-destructive")),
+		config.WithReferenceCache(viper.GetString("reference-cache-dir"), viper.GetBool("refresh-reference")),
+		config.WithRawAPI(viper.GetBool("raw-api")),
+		config.WithNotFoundLookup(viper.GetBool("not-found-lookup")),
+		config.WithInsightsPreflightRows(viper.GetInt("insights-preflight-rows")),
+		config.WithAPILogSampleRate(viper.GetFloat64("api-log-sample-rate")),
+		config.WithSessionBudget(viper.GetInt("session-max-api-calls"), viper.GetInt("session-max-rows")),
+		config.WithConcurrencyLimit(viper.GetInt("max-concurrent-calls"), viper.GetDuration("queue-timeout")),
+		config.WithMaxResultTokens(viper.GetInt("max-result-tokens")),
+		config.WithRegion(viper.GetString("region")),
+		config.WithAPIPathPrefix(viper.GetString("api-path-prefix")),
+		config.WithDetectCapabilities(viper.GetBool("detect-capabilities")),
+		config.WithServeStdio(transportMode == config.TransportHTTP && viper.GetBool("stdio")),
 	)
 }
 
+// checkConfigFile reports unknown keys and mistyped values in the config
+// file, which viper would otherwise ignore, along with references to unset
+// environment variables. A missing file is not an error, matching
+// initConfig.
+func checkConfigFile() error {
+	path := viper.ConfigFileUsed()
+	if path == "" {
+		return nil
+	}
+	settings, err := fileSettings(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("config file %s:\n%w", path, err)
+	}
+	if err := config.CheckFileSettings(settings); err != nil {
+		return fmt.Errorf("config file %s:\n%w", path, err)
+	}
+	return nil
+}
+
+// fileSettings reads the config file at path with ${VAR} references
+// expanded. It is read on its own so flags, environment variables, and
+// defaults aren't mistaken for file keys.
+func fileSettings(path string) (map[string]any, error) {
+	file := viper.New()
+	file.SetConfigFile(path)
+	if err := file.ReadInConfig(); err != nil {
+		return nil, err
+	}
+	settings := file.AllSettings()
+	return settings, config.ExpandEnv(settings, os.LookupEnv)
+}
+
+// readConfigFile reads the config file into viper with ${VAR} references
+// expanded.
+func readConfigFile() error {
+	if err := viper.ReadInConfig(); err != nil {
+		return err
+	}
+	return expandConfigFile()
+}
+
+// expandConfigFile merges the config file's expanded settings over what
+// viper read, which it reads as is. On an error viper is left with the
+// unexpanded file.
+func expandConfigFile() error {
+	settings, err := fileSettings(viper.ConfigFileUsed())
+	if err != nil {
+		return err
+	}
+	return viper.MergeConfigMap(settings)
+}
+
+// toolPatterns reads a tool glob list given either as a comma-separated
+// string (flag/env) or a YAML list in the config file.
+func toolPatterns(key string) []string {
+	var patterns []string
+	for _, item := range viper.GetStringSlice(key) {
+		for p := range strings.SplitSeq(item, ",") {
+			if p = strings.TrimSpace(p); p != "" {
+				patterns = append(patterns, p)
+			}
+		}
+	}
+	return patterns
+}
+
+// projectIDs reads a project ID list given either as a comma-separated
+// string (flag/env) or a YAML list in the config file.
+func projectIDs(key string) ([]int, error) {
+	ids, err := config.ParseIDs(viper.GetStringSlice(key))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", key, err)
+	}
+	return ids, nil
+}
+
+// envVars maps each setting to the environment variable it is read from.
+var envVars = map[string]string{
+	"auth-token":              "HONEYBADGER_PERSONAL_AUTH_TOKEN",
+	"api-url":                 "HONEYBADGER_API_URL",
+	"instructions-url":        "HONEYBADGER_INSTRUCTIONS_URL",
+	"log-level":               "LOG_LEVEL",
+	"read-only":               "HONEYBADGER_READ_ONLY",
+	"read-only-behavior":      "HONEYBADGER_READ_ONLY_BEHAVIOR",
+	"chaos":                   "HONEYBADGER_CHAOS",
+	"enabled-tools":           "HONEYBADGER_ENABLED_TOOLS",
+	"disabled-tools":          "HONEYBADGER_DISABLED_TOOLS",
+	"allowed-project-ids":     "HONEYBADGER_ALLOWED_PROJECT_IDS",
+	"denied-project-ids":      "HONEYBADGER_DENIED_PROJECT_IDS",
+	"defer-tools":             "HONEYBADGER_DEFER_TOOLS",
+	"audit-log":               "HONEYBADGER_AUDIT_LOG",
+	"proxy":                   "HONEYBADGER_PROXY",
+	"ca-bundle":               "HONEYBADGER_CA_BUNDLE",
+	"api-timeout":             "HONEYBADGER_API_TIMEOUT",
+	"api-retries":             "HONEYBADGER_API_RETRIES",
+	"api-retry-backoff":       "HONEYBADGER_API_RETRY_BACKOFF",
+	"auth-style":              "HONEYBADGER_AUTH_STYLE",
+	"lenient-decoding":        "HONEYBADGER_LENIENT_DECODING",
+	"fixtures":                "HONEYBADGER_FIXTURES",
+	"confirm-destructive":     "HONEYBADGER_CONFIRM_DESTRUCTIVE",
+	"reference-cache-dir":     "HONEYBADGER_REFERENCE_CACHE_DIR",
+	"refresh-reference":       "HONEYBADGER_REFRESH_REFERENCE",
+	"raw-api":                 "HONEYBADGER_RAW_API",
+	"not-found-lookup":        "HONEYBADGER_NOT_FOUND_LOOKUP",
+	"insights-preflight-rows": "HONEYBADGER_INSIGHTS_PREFLIGHT_ROWS",
+	"api-log-sample-rate":     "HONEYBADGER_API_LOG_SAMPLE_RATE",
+	"session-max-api-calls":   "HONEYBADGER_SESSION_MAX_API_CALLS",
+	"session-max-rows":        "HONEYBADGER_SESSION_MAX_ROWS",
+	"max-concurrent-calls":    "HONEYBADGER_MAX_CONCURRENT_CALLS",
+	"queue-timeout":           "HONEYBADGER_QUEUE_TIMEOUT",
+	"max-result-tokens":       "HONEYBADGER_MAX_RESULT_TOKENS",
+	"region":                  "HONEYBADGER_REGION",
+	"api-path-prefix":         "HONEYBADGER_API_PATH_PREFIX",
+	"detect-capabilities":     "HONEYBADGER_DETECT_CAPABILITIES",
+	"address":                 "MCP_ADDRESS",
+	"endpoint-path":           "MCP_ENDPOINT_PATH",
+	"stateless":               "MCP_STATELESS",
+	"stdio":                   "MCP_STDIO",
+	"public-url":              "MCP_PUBLIC_URL",
+	"authorization-server":    "MCP_AUTHORIZATION_SERVER_URL",
+	"resource-url":            "MCP_RESOURCE_URL",
+	"webhook-address":         "HONEYBADGER_WEBHOOK_ADDRESS",
+	"webhook-path":            "HONEYBADGER_WEBHOOK_PATH",
+	"webhook-secret":          "HONEYBADGER_WEBHOOK_SECRET",
+}
+
 func initConfig() {
 	if cfgFile != "" {
 		viper.SetConfigFile(cfgFile)
@@ -134,21 +499,15 @@ func initConfig() {
 	viper.SetDefault("read-only", true)
 
 	// Bind specific environment variables
-	_ = viper.BindEnv("auth-token", "HONEYBADGER_PERSONAL_AUTH_TOKEN")
-	_ = viper.BindEnv("api-url", "HONEYBADGER_API_URL")
-	_ = viper.BindEnv("instructions-url", "HONEYBADGER_INSTRUCTIONS_URL")
-	_ = viper.BindEnv("log-level", "LOG_LEVEL")
-	_ = viper.BindEnv("read-only", "HONEYBADGER_READ_ONLY")
-	_ = viper.BindEnv("address", "MCP_ADDRESS")
-	_ = viper.BindEnv("endpoint-path", "MCP_ENDPOINT_PATH")
-	_ = viper.BindEnv("stateless", "MCP_STATELESS")
-	_ = viper.BindEnv("public-url", "MCP_PUBLIC_URL")
-	_ = viper.BindEnv("authorization-server", "MCP_AUTHORIZATION_SERVER_URL")
-	_ = viper.BindEnv("resource-url", "MCP_RESOURCE_URL")
-
-	// Read config file if it exists
+	for key, env := range envVars {
+		_ = viper.BindEnv(key, env)
+	}
+
+	// Read config file if it exists. checkConfigFile reports a reference
+	// to an unset variable when the config is loaded.
 	if err := viper.ReadInConfig(); err == nil {
 		fmt.Fprintln(os.Stderr, "Using config file:", viper.ConfigFileUsed())
+		_ = expandConfigFile()
 	}
 }
 
@@ -165,12 +524,20 @@ func runStdio(cmd *cobra.Command, args []string) error {
 	logger := logging.SetupLogger(cfg.LogLevel)
 	logger.Info("Starting Honeybadger MCP Server",
 		"version", version,
+		"commit", buildinfo.Get().Commit,
 		"transport", "stdio",
 		"log_level", cfg.LogLevel,
 		"api_url", cfg.APIURL,
 		"read_only", cfg.ReadOnly)
 
-	mcpServer := hbmcp.NewServer(cfg, version)
+	shutdownTracing, err := startTracing(logger)
+	if err != nil {
+		return err
+	}
+	defer shutdownTracing()
+
+	mcpServer, _, reloader := hbmcp.NewReloadableServer(cfg, version)
+	defer watchConfigReloads(cmd, config.TransportStdio, reloader, logger)()
 
 	logger.Info("Server ready, listening on stdio")
 	// ServeStdio returns nil on client EOF and context.Canceled on
@@ -185,6 +552,329 @@ func runStdio(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// loopbackAddress reports whether address only accepts connections from
+// this host. An empty host listens on every interface.
+func loopbackAddress(address string) bool {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// runListen runs the stdio server along with a webhook receiver, which
+// pushes each payload to the stdio client.
+func runListen(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	// Anyone who can reach an unauthenticated receiver can push events
+	// into the client's context, so one is only served on loopback.
+	address := viper.GetString("webhook-address")
+	if viper.GetString("webhook-secret") == "" && !loopbackAddress(address) {
+		return fmt.Errorf("configuration error: --webhook-secret is required when the webhook receiver listens beyond loopback (--webhook-address %q)", address)
+	}
+
+	cfg, err := loadConfigFromFlags(cmd, config.TransportStdio)
+	if err != nil {
+		return fmt.Errorf("configuration error: %w", err)
+	}
+	webhookPath := httptransport.NormalizeEndpointPath(viper.GetString("webhook-path"))
+
+	logger := logging.SetupLogger(cfg.LogLevel)
+	logger.Info("Starting Honeybadger MCP Server",
+		"version", version,
+		"commit", buildinfo.Get().Commit,
+		"transport", "stdio",
+		"webhook_address", address,
+		"webhook_path", webhookPath,
+		"log_level", cfg.LogLevel,
+		"api_url", cfg.APIURL,
+		"read_only", cfg.ReadOnly)
+	if viper.GetString("webhook-secret") == "" {
+		logger.Warn("No --webhook-secret set; any local process can push events to the client")
+	}
+
+	shutdownTracing, err := startTracing(logger)
+	if err != nil {
+		return err
+	}
+	defer shutdownTracing()
+
+	mcpServer, _, reloader := hbmcp.NewReloadableServer(cfg, version)
+	defer watchConfigReloads(cmd, config.TransportStdio, reloader, logger)()
+
+	mux := http.NewServeMux()
+	mux.Handle(webhookPath, hbmcp.NewWebhookReceiver(mcpServer, reloader.Current, viper.GetString("webhook-secret"), logger))
+	mux.HandleFunc("/healthz", httptransport.HealthHandler)
+	webhookServer := &http.Server{
+		Addr:              address,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       30 * time.Second,
+	}
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return fmt.Errorf("webhook receiver: %w", err)
+	}
+	go func() {
+		if err := webhookServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("Webhook receiver error", "error", err)
+		}
+	}()
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = webhookServer.Shutdown(ctx)
+	}()
+
+	logger.Info("Server ready, listening on stdio", "webhook_url", "http://"+listener.Addr().String()+webhookPath)
+	if err := server.ServeStdio(mcpServer); err != nil && !errors.Is(err, context.Canceled) {
+		logger.Error("Server error", "error", err)
+		return err
+	}
+
+	logger.Info("Server stopped")
+	return nil
+}
+
+// startTracing installs the OTLP tracer provider when the standard
+// OTEL_EXPORTER_OTLP_* variables configure one. The returned func flushes
+// buffered spans and is safe to defer either way.
+func startTracing(logger *slog.Logger) (func(), error) {
+	shutdown, err := telemetry.Setup(context.Background(), version)
+	if err != nil {
+		return nil, fmt.Errorf("configuration error: OpenTelemetry: %w", err)
+	}
+	if telemetry.Enabled() {
+		logger.Info("OpenTelemetry tracing enabled")
+	}
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdown(ctx); err != nil {
+			logger.Warn("Failed to flush traces", "error", err)
+		}
+	}, nil
+}
+
+// watchConfigReloads re-applies the reloadable settings (tool selection,
+// read-only, and project scope) on SIGHUP and whenever the config file changes. Flags and
+// environment variables are fixed for the life of the process, so a reload
+// only picks up config file edits. An invalid config is logged and the
+// running one kept. The returned func stops listening for SIGHUP.
+func watchConfigReloads(cmd *cobra.Command, transportMode string, reloader *hbmcp.Reloader, logger *slog.Logger) func() {
+	var mu sync.Mutex
+	reload := func(reason string, reread bool) {
+		mu.Lock()
+		defer mu.Unlock()
+		if reread && viper.ConfigFileUsed() != "" {
+			if err := readConfigFile(); err != nil {
+				logger.Error("Ignoring config reload: failed to read config file", "reason", reason, "error", err)
+				return
+			}
+		}
+		cfg, err := loadConfigFromFlags(cmd, transportMode)
+		if err != nil {
+			logger.Error("Ignoring config reload: invalid configuration", "reason", reason, "error", err)
+			return
+		}
+		logger.Info("Reloading configuration", "reason", reason)
+		reloader.Reload(cfg)
+	}
+
+	if path := viper.ConfigFileUsed(); path != "" {
+		if _, err := os.Stat(path); err == nil {
+			// viper has already re-read the file by the time this runs,
+			// but without expanding ${VAR} references, so it's re-read.
+			viper.OnConfigChange(func(fsnotify.Event) { reload("config file changed", true) })
+			viper.WatchConfig()
+		}
+	}
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-hup:
+				reload("SIGHUP", true)
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(hup)
+		close(done)
+	}
+}
+
+func runConfigValidate(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	transport, _ := cmd.Flags().GetString("transport")
+	if transport != config.TransportStdio && transport != config.TransportHTTP {
+		return fmt.Errorf("--transport must be %q or %q", config.TransportStdio, config.TransportHTTP)
+	}
+	_, loadErr := checkConfigFromFlags(cmd, transport)
+
+	out := cmd.OutOrStdout()
+	configFile := viper.ConfigFileUsed()
+	if configFile == "" {
+		configFile = "none"
+	}
+	fmt.Fprintf(out, "Config file: %s\n\n", configFile)
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for _, key := range config.FileKeys {
+		fmt.Fprintf(tw, "%s\t%s\t(%s)\n", key.Name, effectiveSetting(cmd, key.Name), settingSource(cmd, key.Name))
+	}
+	_ = tw.Flush()
+	fmt.Fprintln(out)
+
+	if loadErr != nil {
+		return fmt.Errorf("configuration error: %w", loadErr)
+	}
+	fmt.Fprintln(out, "Configuration is valid.")
+	return nil
+}
+
+// effectiveSetting formats the value a setting resolves to, with the auth
+// token masked.
+func effectiveSetting(cmd *cobra.Command, key string) string {
+	switch key {
+	case "auth-token":
+		token := viper.GetString(key)
+		if token == "" {
+			return "(not set)"
+		}
+		if config.IsTokenReference(token) {
+			return token
+		}
+		if len(token) <= 8 {
+			return "****"
+		}
+		return "****" + token[len(token)-4:]
+	case "webhook-secret":
+		if viper.GetString(key) == "" {
+			return "(not set)"
+		}
+		return "****"
+	case "read-only":
+		if cmd.Flags().Changed(key) {
+			readOnly, _ := cmd.Flags().GetBool(key)
+			return strconv.FormatBool(readOnly)
+		}
+	case "enabled-tools", "disabled-tools":
+		return strings.Join(toolPatterns(key), ",")
+	case "api-url":
+		return apiURLSetting(cmd)
+	}
+	return viper.GetString(key)
+}
+
+// apiURLSetting returns the API URL to use: the region's, when a region is
+// set and api-url isn't, and otherwise api-url, which Validate then checks
+// agrees with the region.
+func apiURLSetting(cmd *cobra.Command) string {
+	if regionURL, ok := config.RegionAPIURLs[viper.GetString("region")]; ok && settingSource(cmd, "api-url") == "default" {
+		return regionURL
+	}
+	return viper.GetString("api-url")
+}
+
+// settingSource names where a setting's value came from, in viper's order
+// of precedence.
+func settingSource(cmd *cobra.Command, key string) string {
+	if f := cmd.Flags().Lookup(key); f != nil && f.Changed {
+		return "flag --" + key
+	}
+	if env, ok := envVars[key]; ok {
+		if _, set := os.LookupEnv(env); set {
+			return "env " + env
+		}
+	}
+	if viper.InConfig(key) {
+		return "config file"
+	}
+	return "default"
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	cfg, err := loadConfigFromFlags(cmd, config.TransportStdio)
+	if err != nil {
+		return fmt.Errorf("configuration error: %w", err)
+	}
+
+	logger := logging.SetupLogger(cfg.LogLevel)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	client := hbmcp.NewClientFactory(cfg, logger)(ctx)
+	report := hbmcp.CheckConnection(ctx, client, cfg.APIURL, cfg.ReadOnly)
+
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "API URL:    %s\n", report.APIURL)
+	fmt.Fprintf(out, "Latency:    %dms\n", report.LatencyMS)
+	fmt.Fprintf(out, "Read-only:  %t\n", report.ReadOnly)
+	if report.OK {
+		fmt.Fprintf(out, "Token:      ok (%d projects)\n", report.ProjectCount)
+		for _, a := range report.Accounts {
+			fmt.Fprintf(out, "Account:    %s (%s)\n", a.Name, a.ID)
+		}
+	} else {
+		fmt.Fprintf(out, "Token:      FAILED: %s\n", report.Error)
+	}
+	for _, h := range report.Hints {
+		fmt.Fprintf(out, "  - %s\n", h)
+	}
+
+	if !report.OK {
+		return errors.New("connection check failed")
+	}
+	return nil
+}
+
+func runHealthcheck(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	if url, _ := cmd.Flags().GetString("url"); url != "" {
+		return probeHealth(cmd, url)
+	}
+	if _, err := checkConfigFromFlags(cmd, config.TransportStdio); err != nil {
+		return fmt.Errorf("configuration error: %w", err)
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), "ok")
+	return nil
+}
+
+// probeHealth fails unless url answers 200 within five seconds.
+func probeHealth(cmd *cobra.Command, url string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("health check failed: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("health check failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("health check failed: %s returned %s: %s", url, resp.Status, strings.TrimSpace(string(body)))
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), "ok")
+	return nil
+}
+
 func runHTTP(cmd *cobra.Command, args []string) error {
 	// Flags parsed fine if we got here; a runtime error doesn't warrant
 	// the usage dump (flag-parse errors still get it).
@@ -198,14 +888,20 @@ func runHTTP(cmd *cobra.Command, args []string) error {
 	// Token scope is the only write-access control in http mode; reject an
 	// explicit read-only setting rather than let it be silently ignored.
 	// Sources are checked directly because viper.IsSet also reports the
-	// SetDefault value, which must not trip this.
-	if os.Getenv("HONEYBADGER_READ_ONLY") != "" || viper.InConfig("read-only") {
+	// SetDefault value, which must not trip this. With --stdio the setting
+	// applies to stdio calls.
+	if !cfg.ServeStdio && (os.Getenv("HONEYBADGER_READ_ONLY") != "" || viper.InConfig("read-only")) {
 		return errors.New("configuration error: read-only (HONEYBADGER_READ_ONLY) is not supported in http mode; write access is granted per-token by OAuth scope")
 	}
 
 	address := viper.GetString("address")
 	endpointPath := httptransport.NormalizeEndpointPath(viper.GetString("endpoint-path"))
 	stateless := viper.GetBool("stateless")
+	// Stateless calls have no session ID, so every client would draw on
+	// one budget that never resets.
+	if stateless && (cfg.SessionMaxAPICalls > 0 || cfg.SessionMaxRows > 0) {
+		return errors.New("configuration error: session-max-api-calls and session-max-rows need sessions; run http mode with --stateless=false to use them")
+	}
 
 	publicURL, err := httptransport.NormalizePublicURL(viper.GetString("public-url"))
 	if err != nil {
@@ -217,12 +913,12 @@ func runHTTP(cmd *cobra.Command, args []string) error {
 	if publicURL == "" || authServer == "" {
 		return errors.New("configuration error: --public-url and --authorization-server are required for http mode")
 	}
-	// /, /healthz, and /.well-known/* are reserved (landing page, health
-	// checks, and PRM); a collision would otherwise panic the mux with a
-	// duplicate-pattern error at registration time instead of a clean
-	// configuration error.
-	if endpointPath == "/" || endpointPath == "/healthz" || endpointPath == "/.well-known" || strings.HasPrefix(endpointPath, "/.well-known/") {
-		return fmt.Errorf("configuration error: --endpoint-path %q collides with a reserved path (/, /healthz, /.well-known/...)", endpointPath)
+	// /, /healthz, /readyz, and /.well-known/* are reserved (landing page,
+	// health checks, and PRM); a collision would otherwise panic the mux
+	// with a duplicate-pattern error at registration time instead of a
+	// clean configuration error.
+	if endpointPath == "/" || endpointPath == "/healthz" || endpointPath == "/readyz" || endpointPath == "/.well-known" || strings.HasPrefix(endpointPath, "/.well-known/") {
+		return fmt.Errorf("configuration error: --endpoint-path %q collides with a reserved path (/, /healthz, /readyz, /.well-known/...)", endpointPath)
 	}
 	// The identifier the AS binds tokens to (aud) and hosts send as resource=.
 	// Must match the AS's configured resource URL exactly, so an explicit
@@ -239,6 +935,7 @@ func runHTTP(cmd *cobra.Command, args []string) error {
 	logger := logging.SetupLogger(cfg.LogLevel)
 	logger.Info("Starting Honeybadger MCP Server",
 		"version", version,
+		"commit", buildinfo.Get().Commit,
 		"transport", "streamable-http",
 		"address", address,
 		"endpoint_path", endpointPath,
@@ -246,10 +943,21 @@ func runHTTP(cmd *cobra.Command, args []string) error {
 		"public_url", publicURL,
 		"resource", resource,
 		"authorization_server", authServer,
+		"stdio", cfg.ServeStdio,
 		"log_level", cfg.LogLevel,
 		"api_url", cfg.APIURL)
+	if cfg.ServeStdio {
+		logger.Info("Also serving stdio", "read_only", cfg.ReadOnly)
+	}
 
-	mcpServer, toolCatalog := hbmcp.NewServerWithCatalog(cfg, version)
+	shutdownTracing, err := startTracing(logger)
+	if err != nil {
+		return err
+	}
+	defer shutdownTracing()
+
+	mcpServer, toolCatalog, reloader := hbmcp.NewReloadableServer(cfg, version)
+	defer watchConfigReloads(cmd, config.TransportHTTP, reloader, logger)()
 
 	// Both WithStateLess and WithStateful are no-ops when their arg is false.
 	sessionOpt := server.WithStateLess(true)
@@ -310,6 +1018,24 @@ func runHTTP(cmd *cobra.Command, args []string) error {
 	rootHandler.Handle(httptransport.WellKnownPRMPath, handler)
 	rootHandler.Handle(endpointPath, httptransport.ValidateMiddleware(prmAbsURL, jwks.Keyfunc, md.Issuer, resource, mcpHandler))
 	rootHandler.HandleFunc("/healthz", httptransport.HealthHandler)
+	// Ready until shutdown starts, and only while the JWKS still holds a
+	// key to verify bearer tokens with; the background refresh can empty it.
+	rootHandler.Handle("/readyz", httptransport.ReadyHandler(func() error {
+		if baseCtx.Err() != nil {
+			return errors.New("shutting down")
+		}
+		keys, err := jwks.Storage().KeyReadAll(baseCtx)
+		if err != nil {
+			return fmt.Errorf("read JWKS: %w", err)
+		}
+		if len(keys) == 0 {
+			return errors.New("JWKS has no usable keys")
+		}
+		return nil
+	}))
+	// Stats cover every caller, so they need a valid token like the MCP
+	// endpoint does.
+	rootHandler.Handle("/stats", httptransport.ValidateMiddleware(prmAbsURL, jwks.Keyfunc, md.Issuer, resource, hbmcp.StatsHandler(mcpServer)))
 	landing, err := httptransport.NewLandingHandler(httptransport.LandingData{
 		MCPURL:  resource,
 		AppURL:  authServer,
@@ -343,17 +1069,22 @@ func runHTTP(cmd *cobra.Command, args []string) error {
 		}
 	}()
 
+	// stdioDone stays nil, and so never ready, without --stdio.
+	var stdioDone chan error
+	if cfg.ServeStdio {
+		stdioDone = make(chan error, 1)
+		stdioServer := server.NewStdioServer(mcpServer)
+		stdioServer.SetContextFunc(hbmcp.WithStdioTransport)
+		go func() {
+			logger.Info("Server ready, listening on stdio")
+			stdioDone <- stdioServer.Listen(baseCtx, os.Stdin, os.Stdout)
+		}()
+	}
+
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
 
-	select {
-	case err := <-errCh:
-		if err != nil {
-			logger.Error("Server error", "error", err)
-			return err
-		}
-	case sig := <-sigCh:
-		logger.Info("Shutdown signal received", "signal", sig.String())
+	shutdown := func() {
 		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 		// Mounted as an http.Handler, mcp-go's Shutdown never touches live
@@ -372,14 +1103,53 @@ func runHTTP(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	select {
+	case err := <-errCh:
+		if err != nil {
+			logger.Error("Server error", "error", err)
+			return err
+		}
+	case err := <-stdioDone:
+		// Like stdio mode, the process belongs to the client that started
+		// it, so it stops when that client goes away.
+		shutdown()
+		if err != nil && !errors.Is(err, context.Canceled) {
+			logger.Error("Server error", "error", err)
+			return err
+		}
+		logger.Info("Stdio client disconnected")
+	case sig := <-sigCh:
+		logger.Info("Shutdown signal received", "signal", sig.String())
+		shutdown()
+	}
+
 	logger.Info("Server stopped")
 	return nil
 }
 
+// runVersion prints the build of this binary. It needs no configuration,
+// so it works before a token is set up.
+func runVersion(cmd *cobra.Command, args []string) error {
+	info := buildinfo.Get()
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "honeybadger-mcp-server %s\n", info.Version)
+	switch {
+	case info.Commit == "":
+		fmt.Fprintf(out, "Commit:  unknown\n")
+	case info.Modified:
+		fmt.Fprintf(out, "Commit:  %s (with uncommitted changes)\n", info.Commit)
+	default:
+		fmt.Fprintf(out, "Commit:  %s\n", info.Commit)
+	}
+	fmt.Fprintf(out, "Go:      %s\n", info.GoVersion)
+	return nil
+}
+
 func main() {
 	// The build may inject either 1.0.0 or the raw v1.0.0 tag name;
 	// normalize so displays that prepend "v" don't render "vv1.0.0".
 	version = strings.TrimPrefix(version, "v")
+	buildinfo.Version, buildinfo.Commit = version, commit
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
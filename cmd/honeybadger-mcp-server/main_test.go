@@ -1,9 +1,17 @@
 package main
 
 import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 
+	"github.com/honeybadger-io/honeybadger-mcp-server/internal/buildinfo"
 	"github.com/spf13/viper"
 )
 
@@ -40,9 +48,58 @@ func TestRunHTTPRejectsConfigFileReadOnly(t *testing.T) {
 	}
 }
 
+func TestRunHTTPRejectsStatelessSessionBudgets(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	viper.Set("stateless", true)
+	viper.Set("session-max-rows", 100)
+
+	err := runHTTP(httpCmd, nil)
+	if err == nil || !strings.Contains(err.Error(), "--stateless=false") {
+		t.Errorf("expected session budgets refused in stateless mode, got: %v", err)
+	}
+
+	viper.Set("stateless", false)
+	if err := runHTTP(httpCmd, nil); err == nil || strings.Contains(err.Error(), "--stateless=false") {
+		t.Errorf("expected session budgets allowed with sessions, got: %v", err)
+	}
+}
+
+func TestRunListenRequiresSecretBeyondLoopback(t *testing.T) {
+	for _, tt := range []struct {
+		address string
+		refused bool
+	}{
+		{":8090", true},
+		{"0.0.0.0:8090", true},
+		{"192.0.2.1:8090", true},
+		{"127.0.0.1:8090", false},
+		{"localhost:8090", false},
+		{"[::1]:8090", false},
+	} {
+		t.Run(tt.address, func(t *testing.T) {
+			viper.Reset()
+			t.Cleanup(viper.Reset)
+			viper.Set("webhook-address", tt.address)
+			// With no auth token, a permitted address fails on the config
+			// instead of starting.
+			err := runListen(listenCmd, nil)
+			if refused := err != nil && strings.Contains(err.Error(), "--webhook-secret is required"); refused != tt.refused {
+				t.Errorf("expected refused=%v, got: %v", tt.refused, err)
+			}
+
+			viper.Set("webhook-secret", "s3cret")
+			if err := runListen(listenCmd, nil); err != nil && strings.Contains(err.Error(), "--webhook-secret is required") {
+				t.Errorf("expected a secret to allow %s, got: %v", tt.address, err)
+			}
+		})
+	}
+}
+
 func TestRunHTTPRejectsReservedEndpointPaths(t *testing.T) {
 	for _, path := range []string{
 		"/healthz",
+		"/readyz",
 		"/.well-known",
 		"/.well-known/oauth-protected-resource",
 		"/.well-known/anything",
@@ -83,3 +140,345 @@ func TestRunHTTPAllowsDefaultReadOnly(t *testing.T) {
 		t.Errorf("expected missing public-url error, got: %v", err)
 	}
 }
+
+// With --stdio the read-only setting applies to stdio calls, so it is
+// accepted, and the startup token they use is required.
+func TestRunHTTPWithStdio(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	t.Setenv("HONEYBADGER_READ_ONLY", "true")
+	viper.Set("stdio", true)
+
+	err := runHTTP(httpCmd, nil)
+	if err == nil || !strings.Contains(err.Error(), "auth-token is required to serve stdio") {
+		t.Errorf("expected the stdio side to need a token, got: %v", err)
+	}
+
+	viper.Set("auth-token", "test-token")
+	err = runHTTP(httpCmd, nil)
+	if err == nil || !strings.Contains(err.Error(), "--public-url") {
+		t.Errorf("expected read-only accepted and the missing public-url reported, got: %v", err)
+	}
+}
+
+func TestRunDoctor(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v2/projects":
+			_, _ = w.Write([]byte(`{"results": [{"id": 1, "name": "Web"}], "links": {}}`))
+		case "/v2/accounts":
+			_, _ = w.Write([]byte(`{"results": [{"id": "abc123", "name": "Acme"}]}`))
+		}
+	}))
+	defer api.Close()
+
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	viper.Set("auth-token", "test-token")
+	viper.Set("api-url", api.URL)
+
+	var out bytes.Buffer
+	doctorCmd.SetOut(&out)
+	t.Cleanup(func() { doctorCmd.SetOut(nil) })
+
+	if err := runDoctor(doctorCmd, nil); err != nil {
+		t.Fatalf("runDoctor() error = %v", err)
+	}
+	for _, want := range []string{"Token:      ok (1 projects)", "Account:    Acme (abc123)"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected %q in output:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestRunHealthcheck(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	var out bytes.Buffer
+	healthcheckCmd.SetOut(&out)
+	t.Cleanup(func() { healthcheckCmd.SetOut(nil) })
+
+	if err := runHealthcheck(healthcheckCmd, nil); err == nil || !strings.Contains(err.Error(), "configuration error") {
+		t.Errorf("expected a configuration error without a token, got %v", err)
+	}
+
+	viper.Set("auth-token", "test-token")
+	if err := runHealthcheck(healthcheckCmd, nil); err != nil {
+		t.Fatalf("runHealthcheck() error = %v", err)
+	}
+	if out.String() != "ok\n" {
+		t.Errorf("expected ok, got %q", out.String())
+	}
+}
+
+func TestAuthTokenReferenceResolvedOnce(t *testing.T) {
+	dir := t.TempDir()
+	runs := filepath.Join(dir, "runs")
+	script := filepath.Join(dir, "token.sh")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\necho run >> "+runs+"\necho hbp_resolved\n"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	countRuns := func() int {
+		data, _ := os.ReadFile(runs)
+		return strings.Count(string(data), "run")
+	}
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	t.Cleanup(func() { resolvedToken.ref, resolvedToken.token = "", "" })
+	viper.Set("auth-token", "exec:"+script)
+
+	// Checking the config never runs the command.
+	configValidateCmd.SetOut(io.Discard)
+	t.Cleanup(func() { configValidateCmd.SetOut(nil) })
+	if err := runConfigValidate(configValidateCmd, nil); err != nil {
+		t.Fatalf("runConfigValidate() error = %v", err)
+	}
+	healthcheckCmd.SetOut(io.Discard)
+	t.Cleanup(func() { healthcheckCmd.SetOut(nil) })
+	if err := runHealthcheck(healthcheckCmd, nil); err != nil {
+		t.Fatalf("runHealthcheck() error = %v", err)
+	}
+	if n := countRuns(); n != 0 {
+		t.Errorf("expected checking the config not to run the command, ran it %d times", n)
+	}
+
+	// Reloads reuse the token resolved at startup.
+	for range 3 {
+		cfg, err := loadConfigFromFlags(stdioCmd, "stdio")
+		if err != nil {
+			t.Fatalf("loadConfigFromFlags() error = %v", err)
+		}
+		if cfg.AuthToken != "hbp_resolved" {
+			t.Errorf("expected the resolved token, got %q", cfg.AuthToken)
+		}
+	}
+	if n := countRuns(); n != 1 {
+		t.Errorf("expected the command to run once, ran it %d times", n)
+	}
+}
+
+func TestRunHealthcheckURL(t *testing.T) {
+	ready := true
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !ready {
+			http.Error(w, "shutting down", http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	if err := healthcheckCmd.Flags().Set("url", srv.URL+"/readyz"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = healthcheckCmd.Flags().Set("url", "") })
+	healthcheckCmd.SetOut(&bytes.Buffer{})
+	t.Cleanup(func() { healthcheckCmd.SetOut(nil) })
+
+	if err := runHealthcheck(healthcheckCmd, nil); err != nil {
+		t.Errorf("runHealthcheck() error = %v", err)
+	}
+	ready = false
+	err := runHealthcheck(healthcheckCmd, nil)
+	if err == nil || !strings.Contains(err.Error(), "503") || !strings.Contains(err.Error(), "shutting down") {
+		t.Errorf("expected the 503 and its reason, got %v", err)
+	}
+}
+
+func TestRunVersion(t *testing.T) {
+	origVersion, origCommit := buildinfo.Version, buildinfo.Commit
+	t.Cleanup(func() { buildinfo.Version, buildinfo.Commit = origVersion, origCommit })
+	buildinfo.Version, buildinfo.Commit = "1.4.0", "0123abc"
+
+	var out bytes.Buffer
+	versionCmd.SetOut(&out)
+	t.Cleanup(func() { versionCmd.SetOut(nil) })
+
+	if err := runVersion(versionCmd, nil); err != nil {
+		t.Fatalf("runVersion() error = %v", err)
+	}
+	for _, want := range []string{"honeybadger-mcp-server 1.4.0\n", "Commit:  0123abc", "Go:      go"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected %q in output, got:\n%s", want, out.String())
+		}
+	}
+}
+
+func TestRunConfigValidate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("auth-token: hbp_secret1234\nread-only: false\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	viper.SetConfigFile(path)
+	if err := viper.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("LOG_LEVEL", "debug")
+	_ = viper.BindEnv("log-level", "LOG_LEVEL")
+
+	var out bytes.Buffer
+	configValidateCmd.SetOut(&out)
+	t.Cleanup(func() { configValidateCmd.SetOut(nil) })
+
+	if err := runConfigValidate(configValidateCmd, nil); err != nil {
+		t.Fatalf("runConfigValidate() error = %v\n%s", err, out.String())
+	}
+	got := out.String()
+	if strings.Contains(got, "hbp_secret1234") {
+		t.Errorf("auth token should be masked:\n%s", got)
+	}
+	for _, want := range []string{"****1234", "(config file)", "(env LOG_LEVEL)", "Configuration is valid."} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in output:\n%s", want, got)
+		}
+	}
+}
+
+func TestRunConfigValidateRegion(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		want    string
+		wantErr string
+	}{
+		{"region alone", "auth-token: token\nregion: eu\n", "https://eu-app.honeybadger.io", ""},
+		{"matching api-url", "auth-token: token\nregion: eu\napi-url: https://eu-app.honeybadger.io\n", "https://eu-app.honeybadger.io", ""},
+		{"conflicting api-url", "auth-token: token\nregion: eu\napi-url: https://honeybadger.internal\n", "https://honeybadger.internal", "set one or the other"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(path, []byte(tt.file), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			viper.Reset()
+			t.Cleanup(viper.Reset)
+			viper.SetConfigFile(path)
+			if err := viper.ReadInConfig(); err != nil {
+				t.Fatal(err)
+			}
+
+			var out bytes.Buffer
+			configValidateCmd.SetOut(&out)
+			t.Cleanup(func() { configValidateCmd.SetOut(nil) })
+
+			err := runConfigValidate(configValidateCmd, nil)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("runConfigValidate() error = %v\n%s", err, out.String())
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("expected an error containing %q, got %v", tt.wantErr, err)
+			}
+			if !strings.Contains(out.String(), "api-url") || !strings.Contains(out.String(), tt.want) {
+				t.Errorf("expected api-url %s in output:\n%s", tt.want, out.String())
+			}
+		})
+	}
+}
+
+func TestRunConfigValidateRejectsUnknownKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("auth-token: token\nlog_level: debug\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	viper.SetConfigFile(path)
+	if err := viper.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	configValidateCmd.SetOut(&out)
+	t.Cleanup(func() { configValidateCmd.SetOut(nil) })
+
+	err := runConfigValidate(configValidateCmd, nil)
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if !strings.Contains(err.Error(), `unknown key "log_level" (did you mean "log-level"?)`) {
+		t.Errorf("expected unknown key error, got: %v", err)
+	}
+}
+
+func TestRunConfigValidateExpandsEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("auth-token: ${HB_TEST_TOKEN}\napi-url: ${HB_TEST_API_URL:-https://eu-app.honeybadger.io}\nread-only: ${HB_TEST_READ_ONLY}\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	viper.SetConfigFile(path)
+	t.Setenv("HB_TEST_TOKEN", "hbp_secret1234")
+
+	var out bytes.Buffer
+	configValidateCmd.SetOut(&out)
+	t.Cleanup(func() { configValidateCmd.SetOut(nil) })
+
+	// An unset variable without a default is reported, not left empty.
+	_ = readConfigFile()
+	err := runConfigValidate(configValidateCmd, nil)
+	if err == nil || !strings.Contains(err.Error(), "environment variable HB_TEST_READ_ONLY is not set") {
+		t.Fatalf("expected an unset variable error, got: %v", err)
+	}
+
+	t.Setenv("HB_TEST_READ_ONLY", "false")
+	if err := readConfigFile(); err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	if err := runConfigValidate(configValidateCmd, nil); err != nil {
+		t.Fatalf("runConfigValidate() error = %v\n%s", err, out.String())
+	}
+	for _, want := range []string{"****1234", "https://eu-app.honeybadger.io", "Configuration is valid."} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected %q in output:\n%s", want, out.String())
+		}
+	}
+	if viper.GetBool("read-only") {
+		t.Error("expected read-only expanded to false")
+	}
+}
+
+func TestRunDoctorFailsOnRejectedToken(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer api.Close()
+
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	viper.Set("auth-token", "bad-token")
+	viper.Set("api-url", api.URL)
+
+	var out bytes.Buffer
+	doctorCmd.SetOut(&out)
+	t.Cleanup(func() { doctorCmd.SetOut(nil) })
+
+	if err := runDoctor(doctorCmd, nil); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if !strings.Contains(out.String(), "FAILED") {
+		t.Errorf("expected failure in output:\n%s", out.String())
+	}
+}
+
+func TestToolPatterns(t *testing.T) {
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+
+	viper.Set("enabled-tools", "list_*, get_*,")
+	if got := toolPatterns("enabled-tools"); !slices.Equal(got, []string{"list_*", "get_*"}) {
+		t.Errorf("comma-separated patterns = %q", got)
+	}
+
+	viper.SetConfigType("yaml")
+	if err := viper.ReadConfig(strings.NewReader("disabled-tools:\n  - delete_*\n  - update_project\n")); err != nil {
+		t.Fatal(err)
+	}
+	if got := toolPatterns("disabled-tools"); !slices.Equal(got, []string{"delete_*", "update_project"}) {
+		t.Errorf("YAML list patterns = %q", got)
+	}
+}
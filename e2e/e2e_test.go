@@ -34,7 +34,7 @@ func TestListTools(t *testing.T) {
 		t.Fatalf("Failed to list tools: %v", err)
 	}
 
-	expectedToolCount := 34 // create_alarm, create_check_in, create_dashboard, create_project, delete_alarm, delete_check_in, delete_dashboard, delete_project, get_alarm, get_alarm_history, get_check_in, get_dashboard, get_fault, get_fault_counts, get_reference, get_project, get_project_integrations, get_project_occurrence_counts, get_project_report, list_alarms, list_check_ins, list_dashboards, list_fault_affected_users, list_fault_notices, list_faults, list_projects, list_streams, query_insights, search_tools, update_alarm, update_check_in, update_dashboard, update_fault, update_project
+	expectedToolCount := 84 // aggregate_fault_notices, analyze_fault_trend, build_insights_widget, check_connection, check_source_maps, compare_fault_counts, compare_fault_notices, create_alarm, create_check_in, create_dashboard, create_project, create_status_page, create_team_invitation, delete_alarm, delete_check_in, delete_dashboard, delete_project, delete_status_page, delete_team_invitation, evaluate_alarm_query, export_fault_graph, export_fault_notices, find_project_by_token, find_similar_faults, generate_error_digest, get_account_fault_counts, get_alarm, get_alarm_history, get_alarm_notifications, get_check_in, get_dashboard, get_default_project, get_fault, get_fault_context_keys, get_fault_counts, get_fault_occurrence_counts, get_insights_schema, get_install_instructions, get_project_settings_diff, get_reference, get_project, get_project_integrations, get_project_occurrence_counts, get_project_report, get_server_info, get_status_page, invite_account_user, list_account_users, list_alarms, list_check_ins, list_dashboards, list_fault_affected_users, list_fault_notices, list_faults, list_insights_query_history, list_projects, list_status_pages, list_streams, list_team_invitations, query_insights, query_insights_batch, remove_account_user, resolve_backtrace_source, resolve_user, search_project_notices, search_tools, search_user_impact, set_default_project, setup_project, stats, summarize_notice_patterns, test_project_integration, unwatch_faults, update_alarm, update_alarm_notifications, update_check_in, update_dashboard, update_fault, update_project, update_status_page, upload_source_map, validate_insights_query, watch_faults, whoami
 	if len(tools) != expectedToolCount {
 		t.Errorf("Expected %d tools, got %d", expectedToolCount, len(tools))
 	}
@@ -57,7 +57,7 @@ func TestListTools(t *testing.T) {
 	}
 
 	// Verify all expected tools are present
-	expectedTools := []string{"create_alarm", "create_check_in", "create_dashboard", "create_project", "delete_alarm", "delete_check_in", "delete_dashboard", "delete_project", "get_alarm", "get_alarm_history", "get_check_in", "get_dashboard", "get_fault", "get_fault_counts", "get_reference", "get_project", "get_project_integrations", "get_project_occurrence_counts", "get_project_report", "list_alarms", "list_check_ins", "list_dashboards", "list_fault_affected_users", "list_fault_notices", "list_faults", "list_projects", "list_streams", "query_insights", "search_tools", "update_alarm", "update_check_in", "update_dashboard", "update_fault", "update_project"}
+	expectedTools := []string{"aggregate_fault_notices", "analyze_fault_trend", "build_insights_widget", "check_connection", "check_source_maps", "compare_fault_counts", "compare_fault_notices", "create_alarm", "create_check_in", "create_dashboard", "create_project", "create_status_page", "create_team_invitation", "delete_alarm", "delete_check_in", "delete_dashboard", "delete_project", "delete_status_page", "delete_team_invitation", "evaluate_alarm_query", "export_fault_graph", "export_fault_notices", "find_project_by_token", "find_similar_faults", "generate_error_digest", "get_account_fault_counts", "get_alarm", "get_alarm_history", "get_alarm_notifications", "get_check_in", "get_dashboard", "get_default_project", "get_fault", "get_fault_context_keys", "get_fault_counts", "get_fault_occurrence_counts", "get_insights_schema", "get_install_instructions", "get_project_settings_diff", "get_reference", "get_project", "get_project_integrations", "get_project_occurrence_counts", "get_project_report", "get_server_info", "get_status_page", "invite_account_user", "list_account_users", "list_alarms", "list_check_ins", "list_dashboards", "list_fault_affected_users", "list_fault_notices", "list_faults", "list_insights_query_history", "list_projects", "list_status_pages", "list_streams", "list_team_invitations", "query_insights", "query_insights_batch", "remove_account_user", "resolve_backtrace_source", "resolve_user", "search_project_notices", "search_tools", "search_user_impact", "set_default_project", "setup_project", "stats", "summarize_notice_patterns", "test_project_integration", "unwatch_faults", "update_alarm", "update_alarm_notifications", "update_check_in", "update_dashboard", "update_fault", "update_project", "update_status_page", "upload_source_map", "validate_insights_query", "watch_faults", "whoami"}
 	for _, expectedTool := range expectedTools {
 		found := false
 		for _, foundTool := range foundTools {
@@ -94,7 +94,7 @@ func TestListToolsReadOnly(t *testing.T) {
 		t.Fatalf("Failed to list tools: %v", err)
 	}
 
-	expectedToolCount := 21 // get_alarm, get_alarm_history, get_check_in, get_dashboard, get_fault, get_fault_counts, get_reference, get_project, get_project_integrations, get_project_occurrence_counts, get_project_report, list_alarms, list_check_ins, list_dashboards, list_fault_affected_users, list_fault_notices, list_faults, list_projects, list_streams, query_insights, search_tools
+	expectedToolCount := 59 // aggregate_fault_notices, analyze_fault_trend, build_insights_widget, check_connection, check_source_maps, compare_fault_counts, compare_fault_notices, evaluate_alarm_query, export_fault_graph, find_project_by_token, find_similar_faults, generate_error_digest, get_account_fault_counts, get_alarm, get_alarm_history, get_alarm_notifications, get_check_in, get_dashboard, get_default_project, get_fault, get_fault_context_keys, get_fault_counts, get_fault_occurrence_counts, get_insights_schema, get_install_instructions, get_project_settings_diff, get_reference, get_project, get_project_integrations, get_project_occurrence_counts, get_project_report, get_server_info, get_status_page, list_account_users, list_alarms, list_check_ins, list_dashboards, list_fault_affected_users, list_fault_notices, list_faults, list_insights_query_history, list_projects, list_status_pages, list_streams, list_team_invitations, query_insights, query_insights_batch, resolve_backtrace_source, resolve_user, search_project_notices, search_tools, search_user_impact, set_default_project, stats, summarize_notice_patterns, unwatch_faults, validate_insights_query, watch_faults, whoami
 	if len(tools) != expectedToolCount {
 		t.Errorf("Expected %d tools in read-only mode, got %d", expectedToolCount, len(tools))
 	}
@@ -112,7 +112,7 @@ func TestListToolsReadOnly(t *testing.T) {
 	}
 
 	// Verify only read-only tools are present
-	expectedReadOnlyTools := []string{"get_alarm", "get_alarm_history", "get_check_in", "get_dashboard", "get_fault", "get_fault_counts", "get_reference", "get_project", "get_project_integrations", "get_project_occurrence_counts", "get_project_report", "list_alarms", "list_check_ins", "list_dashboards", "list_fault_affected_users", "list_fault_notices", "list_faults", "list_projects", "list_streams", "query_insights", "search_tools"}
+	expectedReadOnlyTools := []string{"aggregate_fault_notices", "analyze_fault_trend", "build_insights_widget", "check_connection", "check_source_maps", "compare_fault_counts", "compare_fault_notices", "evaluate_alarm_query", "export_fault_graph", "find_project_by_token", "find_similar_faults", "generate_error_digest", "get_account_fault_counts", "get_alarm", "get_alarm_history", "get_alarm_notifications", "get_check_in", "get_dashboard", "get_default_project", "get_fault", "get_fault_context_keys", "get_fault_counts", "get_fault_occurrence_counts", "get_insights_schema", "get_install_instructions", "get_project_settings_diff", "get_reference", "get_project", "get_project_integrations", "get_project_occurrence_counts", "get_project_report", "get_server_info", "get_status_page", "list_account_users", "list_alarms", "list_check_ins", "list_dashboards", "list_fault_affected_users", "list_fault_notices", "list_faults", "list_insights_query_history", "list_projects", "list_status_pages", "list_streams", "list_team_invitations", "query_insights", "query_insights_batch", "resolve_backtrace_source", "resolve_user", "search_project_notices", "search_tools", "search_user_impact", "set_default_project", "stats", "summarize_notice_patterns", "unwatch_faults", "validate_insights_query", "watch_faults", "whoami"}
 	for _, expectedTool := range expectedReadOnlyTools {
 		found := false
 		for _, foundTool := range foundTools {
@@ -127,7 +127,7 @@ func TestListToolsReadOnly(t *testing.T) {
 	}
 
 	// Verify destructive tools are NOT present
-	destructiveTools := []string{"create_alarm", "create_check_in", "create_dashboard", "create_project", "delete_alarm", "delete_check_in", "delete_dashboard", "delete_project", "update_alarm", "update_check_in", "update_dashboard", "update_fault", "update_project"}
+	destructiveTools := []string{"create_alarm", "create_check_in", "create_dashboard", "create_project", "create_status_page", "create_team_invitation", "delete_alarm", "delete_check_in", "delete_dashboard", "delete_project", "delete_status_page", "delete_team_invitation", "export_fault_notices", "invite_account_user", "remove_account_user", "setup_project", "test_project_integration", "update_alarm", "update_alarm_notifications", "update_check_in", "update_dashboard", "update_fault", "update_project", "update_status_page", "upload_source_map"}
 	for _, destructiveTool := range destructiveTools {
 		for _, foundTool := range foundTools {
 			if foundTool == destructiveTool {
@@ -0,0 +1,100 @@
+package e2e
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// MockAPI is an in-process stand-in for the Honeybadger API. It serves
+// JSON fixtures from a directory laid out like the server's --fixtures
+// mode: the response to GET /v2/projects/1/faults is
+// <dir>/GET/projects/1/faults.json. Query strings are ignored, and a
+// request without a fixture gets a 404.
+type MockAPI struct {
+	URL string
+
+	server *httptest.Server
+	dir    string
+	token  string
+
+	mu       sync.Mutex
+	requests []MockRequest
+}
+
+// MockRequest records a request the mock API received.
+type MockRequest struct {
+	Method string
+	Path   string
+	Query  string
+}
+
+// StartMockAPI starts a mock API serving the fixtures in dir. Requests
+// must authenticate with token as the Basic auth username, as the server
+// does over stdio by default. The mock is closed when the test ends.
+func StartMockAPI(t *testing.T, dir, token string) *MockAPI {
+	t.Helper()
+	if token == "" {
+		token = "test-token"
+	}
+
+	api := &MockAPI{dir: dir, token: token}
+	api.server = httptest.NewServer(http.HandlerFunc(api.serve))
+	api.URL = api.server.URL
+	t.Cleanup(api.server.Close)
+	return api
+}
+
+func (m *MockAPI) serve(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/v2")
+	m.mu.Lock()
+	m.requests = append(m.requests, MockRequest{Method: r.Method, Path: path, Query: r.URL.RawQuery})
+	token := m.token
+	m.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	if user, _, ok := r.BasicAuth(); !ok || user != token {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = fmt.Fprint(w, `{"errors": "Invalid authentication token"}`)
+		return
+	}
+
+	name := filepath.Join(m.dir, r.Method, filepath.FromSlash(strings.Trim(path, "/"))+".json")
+	body, err := os.ReadFile(name)
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = fmt.Fprintf(w, `{"errors": "no fixture for %s %s"}`, r.Method, path)
+		return
+	}
+	_, _ = w.Write(body)
+}
+
+// SetToken changes the token the mock API accepts.
+func (m *MockAPI) SetToken(token string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.token = token
+}
+
+// Requests returns the requests received so far, oldest first.
+func (m *MockAPI) Requests() []MockRequest {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]MockRequest(nil), m.requests...)
+}
+
+// Received reports whether a request for method and path (without the /v2
+// prefix or query string) was received.
+func (m *MockAPI) Received(method, path string) bool {
+	for _, r := range m.Requests() {
+		if r.Method == method && r.Path == path {
+			return true
+		}
+	}
+	return false
+}
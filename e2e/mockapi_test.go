@@ -0,0 +1,129 @@
+package e2e
+
+import (
+	"strings"
+	"testing"
+)
+
+// resultText returns the first text content of a tool result, failing the
+// test if the call errored.
+func resultText(t *testing.T, result interface{}) string {
+	t.Helper()
+	resultMap, ok := result.(map[string]interface{})
+	if !ok {
+		t.Fatalf("Unexpected result type: %T", result)
+	}
+	content, ok := resultMap["content"].([]interface{})
+	if !ok || len(content) == 0 {
+		t.Fatalf("No content in result: %v", resultMap)
+	}
+	item, _ := content[0].(map[string]interface{})
+	text, _ := item["text"].(string)
+	if isError, _ := resultMap["isError"].(bool); isError {
+		t.Fatalf("Tool returned an error: %s", text)
+	}
+	return text
+}
+
+// TestMockAPIToolResponses verifies tools return the API's data when the
+// server is pointed at the mock API
+func TestMockAPIToolResponses(t *testing.T) {
+	api := StartMockAPI(t, "testdata/api", "test-token")
+	server, err := StartTestServerWithAPI(t, api, true)
+	if err != nil {
+		t.Fatalf("Failed to start server: %v", err)
+	}
+	defer server.Stop()
+
+	tests := []struct {
+		tool    string
+		args    map[string]interface{}
+		path    string
+		method  string
+		expects []string
+	}{
+		{
+			tool:    "list_projects",
+			args:    map[string]interface{}{},
+			method:  "GET",
+			path:    "/projects",
+			expects: []string{`"name":"Storefront"`},
+		},
+		{
+			tool:    "get_project",
+			args:    map[string]interface{}{"id": 1},
+			method:  "GET",
+			path:    "/projects/1",
+			expects: []string{`"name":"Storefront"`, `"unresolved_fault_count":1`},
+		},
+		{
+			tool:    "list_faults",
+			args:    map[string]interface{}{"project_id": 1},
+			method:  "GET",
+			path:    "/projects/1/faults",
+			expects: []string{"NoMethodError", "Timeout::Error"},
+		},
+		{
+			tool:    "get_fault",
+			args:    map[string]interface{}{"project_id": 1, "fault_id": 10},
+			method:  "GET",
+			path:    "/projects/1/faults/10",
+			expects: []string{"NoMethodError", `"notices_count":3`},
+		},
+		{
+			tool:    "list_fault_notices",
+			args:    map[string]interface{}{"project_id": 1, "fault_id": 10},
+			method:  "GET",
+			path:    "/projects/1/faults/10/notices",
+			expects: []string{`"id":"n3"`, `"id":"n1"`},
+		},
+		{
+			tool:    "query_insights",
+			args:    map[string]interface{}{"project_id": 1, "query": "stats count() by event_type"},
+			method:  "POST",
+			path:    "/projects/1/insights/queries",
+			expects: []string{`"event_type":"request"`, `"count":42`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tool, func(t *testing.T) {
+			result, err := server.CallTool(tt.tool, tt.args)
+			if err != nil {
+				t.Fatalf("Failed to call %s: %v", tt.tool, err)
+			}
+			text := resultText(t, result)
+			for _, want := range tt.expects {
+				if !strings.Contains(text, want) {
+					t.Errorf("Expected %s in %s result, got: %s", want, tt.tool, text)
+				}
+			}
+			if !api.Received(tt.method, tt.path) {
+				t.Errorf("Expected %s %s to reach the API, got: %v", tt.method, tt.path, api.Requests())
+			}
+		})
+	}
+}
+
+// TestMockAPIUnauthorized verifies an API auth failure surfaces as a tool
+// error
+func TestMockAPIUnauthorized(t *testing.T) {
+	api := StartMockAPI(t, "testdata/api", "test-token")
+	server, err := StartTestServerWithAPI(t, api, true)
+	if err != nil {
+		t.Fatalf("Failed to start server: %v", err)
+	}
+	defer server.Stop()
+
+	// The server's token is no longer accepted
+	api.SetToken("rotated-token")
+
+	result, err := server.CallTool("list_projects", map[string]interface{}{})
+	if err != nil {
+		t.Fatalf("Failed to call list_projects: %v", err)
+	}
+	resultMap, _ := result.(map[string]interface{})
+	if isError, _ := resultMap["isError"].(bool); !isError {
+		t.Errorf("Expected an error result for a rejected token, got: %v", resultMap)
+	}
+}
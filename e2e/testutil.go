@@ -42,6 +42,18 @@ func StartTestServer(t *testing.T, apiToken string) (*MCPTestServer, error) {
 
 // StartTestServerWithReadOnly starts a new MCP server subprocess for testing with configurable read-only mode
 func StartTestServerWithReadOnly(t *testing.T, apiToken string, readOnly bool) (*MCPTestServer, error) {
+	return startTestServer(t, apiToken, readOnly, nil)
+}
+
+// StartTestServerWithAPI starts a new MCP server subprocess for testing
+// that calls api instead of the real Honeybadger API
+func StartTestServerWithAPI(t *testing.T, api *MockAPI, readOnly bool) (*MCPTestServer, error) {
+	return startTestServer(t, api.token, readOnly, []string{"HONEYBADGER_API_URL=" + api.URL})
+}
+
+// startTestServer builds and starts the server with env added to the
+// test's environment
+func startTestServer(t *testing.T, apiToken string, readOnly bool, env []string) (*MCPTestServer, error) {
 	if apiToken == "" {
 		apiToken = "test-token"
 	}
@@ -58,6 +70,9 @@ func StartTestServerWithReadOnly(t *testing.T, apiToken string, readOnly bool) (
 		args = append(args, "--read-only=false")
 	}
 	cmd := exec.Command("../honeybadger-mcp-server-test", args...)
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
 
 	stdin, err := cmd.StdinPipe()
 	if err != nil {
@@ -78,12 +93,17 @@ func StartTestServerWithReadOnly(t *testing.T, apiToken string, readOnly bool) (
 		return nil, fmt.Errorf("failed to start server: %w", err)
 	}
 
+	// tools/list with every tool is one line well past the Scanner's
+	// default 64KB limit.
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4<<20)
+
 	server := &MCPTestServer{
 		cmd:      cmd,
 		stdin:    stdin,
 		stdout:   stdout,
 		stderr:   stderr,
-		scanner:  bufio.NewScanner(stdout),
+		scanner:  scanner,
 		apiToken: apiToken,
 		t:        t,
 	}
@@ -0,0 +1,45 @@
+// Package buildinfo describes the build of the running server: its
+// version and the commit it was built from.
+package buildinfo
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// Version and Commit are set by main from the values injected with
+// -ldflags at build time. Commit falls back to the revision the Go
+// toolchain stamps into builds from a git checkout.
+var (
+	Version = "dev"
+	Commit  string
+)
+
+// Info is the build of the running server.
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	Modified  bool   `json:"modified,omitempty"`
+	GoVersion string `json:"go_version"`
+}
+
+// Get returns the build of the running server. Modified reports a build
+// from a checkout with uncommitted changes, when the toolchain recorded it.
+func Get() Info {
+	info := Info{Version: Version, Commit: Commit, GoVersion: runtime.Version()}
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	for _, s := range bi.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			if info.Commit == "" {
+				info.Commit = s.Value
+			}
+		case "vcs.modified":
+			info.Modified = s.Value == "true"
+		}
+	}
+	return info
+}
@@ -1,8 +1,16 @@
 package config
 
 import (
+	"crypto/x509"
 	"errors"
 	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 )
 
 const (
@@ -10,10 +18,78 @@ const (
 	TransportHTTP  = "http"
 )
 
+// Auth styles: how the token is presented to the Honeybadger API.
+const (
+	// AuthStyleBasicUsername sends the token as the Basic auth username
+	// with an empty password, which the public API expects.
+	AuthStyleBasicUsername = "basic-username"
+	// AuthStyleBasicPassword sends the token as the Basic auth password
+	// with an empty username.
+	AuthStyleBasicPassword = "basic-password"
+	// AuthStyleBearer sends the token in a Bearer Authorization header.
+	AuthStyleBearer = "bearer"
+)
+
+// Read-only behaviors: what happens to write tools in read-only mode.
+const (
+	// ReadOnlyHide leaves write tools out of the tool list.
+	ReadOnlyHide = "hide"
+	// ReadOnlyError lists write tools but refuses calls to them with an
+	// error saying the server is read-only.
+	ReadOnlyError = "error"
+)
+
+// Regions: the Honeybadger regions --region selects the API URL of.
+const (
+	RegionUS = "us"
+	RegionEU = "eu"
+)
+
+// RegionAPIURLs is the API URL of each region.
+var RegionAPIURLs = map[string]string{
+	RegionUS: "https://app.honeybadger.io",
+	RegionEU: "https://eu-app.honeybadger.io",
+}
+
+// DefaultAPIPathPrefix is the path the Data API is served under.
+const DefaultAPIPathPrefix = "/v2"
+
 // DefaultInstructionsURL is where the docs site publishes the LLM
 // instruction sets (index.json plus one .txt per set).
 const DefaultInstructionsURL = "https://docs.honeybadger.io/resources/llms/instructions"
 
+// DefaultReferenceCacheDir returns the directory the CLI caches reference
+// topics in by default, under the user's cache directory, or "" when
+// there is none.
+func DefaultReferenceCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "honeybadger-mcp-server")
+}
+
+// DefaultAPITimeout is used when no APITimeout is configured.
+const DefaultAPITimeout = 30 * time.Second
+
+// DefaultInsightsPreflightRows is used when no InsightsPreflightRows is
+// configured.
+const DefaultInsightsPreflightRows = 10000
+
+// DefaultAPILogSampleRate is used when no APILogSampleRate is configured:
+// every API request is logged at debug level.
+const DefaultAPILogSampleRate = 1.0
+
+// DefaultMaxConcurrentCalls is the --max-concurrent-calls default.
+const DefaultMaxConcurrentCalls = 8
+
+// DefaultQueueTimeout is used when no QueueTimeout is configured.
+const DefaultQueueTimeout = 30 * time.Second
+
+// MaxAPIRetries bounds APIRetries so a misconfiguration can't turn one
+// tool call into a long retry storm.
+const MaxAPIRetries = 10
+
 type Config struct {
 	AuthToken       string
 	APIURL          string
@@ -21,20 +97,492 @@ type Config struct {
 	LogLevel        string
 	ReadOnly        bool
 	TransportMode   string
+
+	// ServeStdio, in http mode, also serves MCP on standard input/output,
+	// sharing the process's caches, budgets, and concurrency limit. Calls
+	// over stdio use AuthToken and ReadOnly as in stdio mode.
+	ServeStdio bool
+
+	// ChaosRate is the fraction (0-1) of API requests that fail with an
+	// injected 429, 500, or timeout. Developer-only; 0 disables it.
+	ChaosRate float64
+
+	// EnabledTools and DisabledTools are glob patterns (path.Match syntax,
+	// e.g. "list_*") over tool names. When EnabledTools is non-empty only
+	// matching tools are registered; DisabledTools then removes matches.
+	// Applied independently of ReadOnly.
+	EnabledTools  []string
+	DisabledTools []string
+
+	// DeferTools advertises only search_tools and invoke_tool in tools/list;
+	// search_tools returns full input schemas and invoke_tool calls any
+	// other tool by name.
+	DeferTools bool
+
+	// AuditLogPath, when set, is a file every tool call is appended to as a
+	// JSON line (arguments with secrets redacted, result size, duration,
+	// error status).
+	AuditLogPath string
+
+	// ProxyURL, when set, is the proxy outbound requests go through instead
+	// of the one HTTPS_PROXY/HTTP_PROXY/NO_PROXY would select.
+	ProxyURL string
+
+	// CABundlePath is a PEM file of CA certificates trusted for outbound
+	// requests in addition to the system roots, for private CAs and
+	// TLS-intercepting proxies.
+	CABundlePath string
+
+	// APITimeout bounds each Honeybadger API request, retries included.
+	APITimeout time.Duration
+
+	// APIRetries is how many times a failed API request is retried
+	// (network errors, 429, and 502/503/504); 0 disables retries.
+	// APIRetryBackoff is the wait before the first retry, doubling after.
+	APIRetries      int
+	APIRetryBackoff time.Duration
+
+	// AuthStyle is one of the AuthStyle constants; empty selects the
+	// transport's default (see ResolvedAuthStyle).
+	AuthStyle string
+
+	// AllowedProjectIDs and DeniedProjectIDs scope tools to a subset of
+	// the projects the token can reach. When AllowedProjectIDs is
+	// non-empty only those projects are allowed; DeniedProjectIDs then
+	// removes projects.
+	AllowedProjectIDs []int
+	DeniedProjectIDs  []int
+
+	// LenientDecoding coerces notice, fault, and project responses whose
+	// fields don't match the API client's types instead of failing the
+	// tool call.
+	LenientDecoding bool
+
+	// FixturesDir, when set, is a directory of recorded API responses that
+	// requests are answered from instead of the network, for demos and
+	// offline development. No auth token is needed.
+	FixturesDir string
+
+	// ReadOnlyBehavior is one of the ReadOnly behavior constants; empty
+	// means ReadOnlyHide.
+	ReadOnlyBehavior string
+
+	// ConfirmDestructive makes destructive tools return a confirmation
+	// token on their first call and act only when called again with it.
+	ConfirmDestructive bool
+
+	// ReferenceCacheDir, when set, is a directory reference topics are
+	// cached in across restarts, so get_reference answers without the
+	// docs site. RefreshReference refetches every topic into it at
+	// startup.
+	ReferenceCacheDir string
+	RefreshReference  bool
+
+	// RawAPI registers raw_api_request, which sends requests to any Data
+	// API endpoint.
+	RawAPI bool
+
+	// NotFoundLookup has get_fault and get_project, when the API can't
+	// find what they ask for, look through the token's accounts for where
+	// it actually is and name it in the error.
+	NotFoundLookup bool
+
+	// InsightsPreflightRows is the row count above which a query_insights
+	// preflight holds the query back; zero means
+	// DefaultInsightsPreflightRows.
+	InsightsPreflightRows int
+
+	// APILogSampleRate is the fraction (0-1] of successful API requests
+	// logged at debug level; failed ones are always logged. Zero means
+	// DefaultAPILogSampleRate.
+	APILogSampleRate float64
+
+	// SessionMaxAPICalls and SessionMaxRows cap the Honeybadger API
+	// requests each MCP session may make and the result rows its tool
+	// calls may return, after which its calls are refused. Zero is no
+	// limit.
+	SessionMaxAPICalls int
+	SessionMaxRows     int
+
+	// MaxConcurrentCalls caps the tool calls that run at once; others
+	// wait up to QueueTimeout for a slot and then fail. Zero is no limit.
+	// Zero QueueTimeout means DefaultQueueTimeout.
+	MaxConcurrentCalls int
+	QueueTimeout       time.Duration
+
+	// MaxResultTokens caps the estimated tokens of a tool result; a
+	// larger one is replaced by a summary of its shape with instructions
+	// for narrowing the call. Zero is no limit.
+	MaxResultTokens int
+
+	// Region is one of the Region constants, or empty. It only selects
+	// APIURL, so an APIURL set as well must agree with it.
+	Region string
+
+	// APIPathPrefix replaces DefaultAPIPathPrefix in API request paths, for
+	// self-hosted installs that serve the Data API under another path or
+	// version. Empty means DefaultAPIPathPrefix.
+	APIPathPrefix string
+
+	// DetectCapabilities probes the API at startup and hides the tools of
+	// features it doesn't serve, such as Insights on older installs.
+	DetectCapabilities bool
+
+	// UnsupportedTools are the tools whose features the probes found
+	// missing. It is set at startup rather than configured, survives
+	// reloads, and leaves the tools out like DisabledTools does.
+	UnsupportedTools []string
+}
+
+// Option sets an optional Config field in Load.
+type Option func(*Config)
+
+// WithChaosRate enables fault injection for the given fraction of API requests.
+func WithChaosRate(rate float64) Option {
+	return func(c *Config) { c.ChaosRate = rate }
+}
+
+// WithEnabledTools limits the registered tools to those matching patterns.
+func WithEnabledTools(patterns []string) Option {
+	return func(c *Config) { c.EnabledTools = patterns }
+}
+
+// WithDisabledTools removes the tools matching patterns.
+func WithDisabledTools(patterns []string) Option {
+	return func(c *Config) { c.DisabledTools = patterns }
+}
+
+// WithDeferTools turns on deferred tool loading.
+func WithDeferTools(deferTools bool) Option {
+	return func(c *Config) { c.DeferTools = deferTools }
+}
+
+// WithAuditLog records every tool call to the file at path.
+func WithAuditLog(path string) Option {
+	return func(c *Config) { c.AuditLogPath = path }
+}
+
+// WithProxy sends outbound requests through the proxy at proxyURL.
+func WithProxy(proxyURL string) Option {
+	return func(c *Config) { c.ProxyURL = proxyURL }
+}
+
+// WithCABundle trusts the CA certificates in the PEM file at path.
+func WithCABundle(path string) Option {
+	return func(c *Config) { c.CABundlePath = path }
+}
+
+// WithAPITimeout bounds each API request, retries included. Zero keeps
+// DefaultAPITimeout.
+func WithAPITimeout(timeout time.Duration) Option {
+	return func(c *Config) { c.APITimeout = timeout }
+}
+
+// WithAPIRetries retries failed API requests up to retries times.
+func WithAPIRetries(retries int) Option {
+	return func(c *Config) { c.APIRetries = retries }
+}
+
+// WithAPIRetryBackoff sets the wait before the first retry.
+func WithAPIRetryBackoff(backoff time.Duration) Option {
+	return func(c *Config) { c.APIRetryBackoff = backoff }
+}
+
+// WithAllowedProjectIDs limits tools to the projects with the given IDs.
+func WithAllowedProjectIDs(ids []int) Option {
+	return func(c *Config) { c.AllowedProjectIDs = ids }
+}
+
+// WithDeniedProjectIDs keeps tools away from the projects with the given IDs.
+func WithDeniedProjectIDs(ids []int) Option {
+	return func(c *Config) { c.DeniedProjectIDs = ids }
+}
+
+// WithLenientDecoding turns on lenient decoding of API responses.
+func WithLenientDecoding(lenient bool) Option {
+	return func(c *Config) { c.LenientDecoding = lenient }
+}
+
+// WithFixtures answers API requests from the recorded responses in dir.
+func WithFixtures(dir string) Option {
+	return func(c *Config) { c.FixturesDir = dir }
+}
+
+// WithReadOnlyBehavior sets what happens to write tools in read-only
+// mode.
+func WithReadOnlyBehavior(behavior string) Option {
+	return func(c *Config) { c.ReadOnlyBehavior = behavior }
+}
+
+// WithConfirmDestructive requires destructive tool calls to be confirmed.
+func WithConfirmDestructive(confirm bool) Option {
+	return func(c *Config) { c.ConfirmDestructive = confirm }
+}
+
+// WithReferenceCache caches reference topics in dir, refetching them all
+// at startup when refresh is set.
+func WithReferenceCache(dir string, refresh bool) Option {
+	return func(c *Config) { c.ReferenceCacheDir, c.RefreshReference = dir, refresh }
+}
+
+// WithRawAPI registers the raw_api_request tool.
+func WithRawAPI(enabled bool) Option {
+	return func(c *Config) { c.RawAPI = enabled }
+}
+
+// WithNotFoundLookup enables the lookup behind not-found errors of
+// get_fault and get_project.
+func WithNotFoundLookup(enabled bool) Option {
+	return func(c *Config) { c.NotFoundLookup = enabled }
+}
+
+// WithInsightsPreflightRows sets the row count above which a
+// query_insights preflight holds the query back.
+func WithInsightsPreflightRows(rows int) Option {
+	return func(c *Config) { c.InsightsPreflightRows = rows }
+}
+
+// WithAPILogSampleRate sets the fraction of successful API requests
+// logged at debug level.
+func WithAPILogSampleRate(rate float64) Option {
+	return func(c *Config) { c.APILogSampleRate = rate }
+}
+
+// WithSessionBudget caps the API requests and result rows each session
+// may use.
+func WithSessionBudget(maxCalls, maxRows int) Option {
+	return func(c *Config) {
+		c.SessionMaxAPICalls = maxCalls
+		c.SessionMaxRows = maxRows
+	}
+}
+
+// WithConcurrencyLimit caps the tool calls that run at once and how long
+// the rest wait for a slot.
+func WithConcurrencyLimit(maxCalls int, queueTimeout time.Duration) Option {
+	return func(c *Config) {
+		c.MaxConcurrentCalls = maxCalls
+		c.QueueTimeout = queueTimeout
+	}
+}
+
+// WithMaxResultTokens caps the estimated tokens of a tool result before it
+// is summarized instead.
+func WithMaxResultTokens(tokens int) Option {
+	return func(c *Config) { c.MaxResultTokens = tokens }
+}
+
+// WithRegion records the region APIURL was chosen for.
+func WithRegion(region string) Option {
+	return func(c *Config) { c.Region = region }
+}
+
+// WithAPIPathPrefix sets the path the Data API is served under.
+func WithAPIPathPrefix(prefix string) Option {
+	return func(c *Config) { c.APIPathPrefix = prefix }
+}
+
+// WithDetectCapabilities enables probing the API for the features it
+// serves at startup.
+func WithDetectCapabilities(enabled bool) Option {
+	return func(c *Config) { c.DetectCapabilities = enabled }
+}
+
+// WithServeStdio also serves stdio alongside http.
+func WithServeStdio(serve bool) Option {
+	return func(c *Config) { c.ServeStdio = serve }
+}
+
+// WithAuthStyle sets how the token is presented to the API.
+func WithAuthStyle(style string) Option {
+	return func(c *Config) { c.AuthStyle = style }
+}
+
+// ResolvedAuthStyle returns AuthStyle, or the default when it is unset:
+// bearer in http mode, which forwards each request's Bearer token, and
+// basic-username otherwise.
+func (c *Config) ResolvedAuthStyle() string {
+	return c.AuthStyleFor(c.TransportMode)
+}
+
+// AuthStyleFor returns the auth style for a call that arrived over
+// transport, which differs from TransportMode for stdio calls to a server
+// that also serves stdio (ServeStdio).
+func (c *Config) AuthStyleFor(transport string) string {
+	switch {
+	case c.AuthStyle != "":
+		return c.AuthStyle
+	case transport == TransportHTTP:
+		return AuthStyleBearer
+	default:
+		return AuthStyleBasicUsername
+	}
+}
+
+// RootCAs returns the system roots plus the certificates in CABundlePath,
+// or nil (meaning the system roots alone) when no bundle is configured.
+func (c *Config) RootCAs() (*x509.CertPool, error) {
+	if c.CABundlePath == "" {
+		return nil, nil
+	}
+	pem, err := os.ReadFile(c.CABundlePath)
+	if err != nil {
+		return nil, fmt.Errorf("ca-bundle: %w", err)
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("ca-bundle: no PEM certificates found in %s", c.CABundlePath)
+	}
+	return pool, nil
+}
+
+// ToolEnabled reports whether the tool named name passes the
+// EnabledTools/DisabledTools patterns and isn't one of UnsupportedTools.
+func (c *Config) ToolEnabled(name string) bool {
+	if len(c.EnabledTools) > 0 && !matchAny(c.EnabledTools, name) {
+		return false
+	}
+	if slices.Contains(c.UnsupportedTools, name) {
+		return false
+	}
+	return !matchAny(c.DisabledTools, name)
+}
+
+// ProjectScoped reports whether any project restriction is configured.
+func (c *Config) ProjectScoped() bool {
+	return len(c.AllowedProjectIDs) > 0 || len(c.DeniedProjectIDs) > 0
+}
+
+// ProjectAllowed reports whether the project with the given ID passes the
+// AllowedProjectIDs/DeniedProjectIDs lists.
+func (c *Config) ProjectAllowed(id int) bool {
+	if len(c.AllowedProjectIDs) > 0 && !slices.Contains(c.AllowedProjectIDs, id) {
+		return false
+	}
+	return !slices.Contains(c.DeniedProjectIDs, id)
+}
+
+// matchAny assumes patterns were checked by Validate, so match errors can't occur.
+func matchAny(patterns []string, name string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
 }
 
 func (c *Config) Validate() error {
-	// http mode takes the Bearer per-request; startup AuthToken is unused.
-	if c.TransportMode == TransportHTTP {
+	for _, p := range append(append([]string{}, c.EnabledTools...), c.DisabledTools...) {
+		if _, err := path.Match(p, ""); err != nil {
+			return fmt.Errorf("invalid tool pattern %q: %w", p, err)
+		}
+	}
+	for _, id := range append(append([]int{}, c.AllowedProjectIDs...), c.DeniedProjectIDs...) {
+		if id < 1 {
+			return fmt.Errorf("invalid project ID %d: must be a positive integer", id)
+		}
+	}
+	if c.DeferTools && !c.ToolEnabled("search_tools") {
+		return errors.New("defer-tools requires search_tools to be enabled")
+	}
+	if err := checkLogLevel(c.LogLevel); err != nil {
+		return err
+	}
+	if err := checkURL(c.APIURL); err != nil {
+		return fmt.Errorf("api-url: %w", err)
+	}
+	if err := checkRegion(c.Region); err != nil {
+		return err
+	}
+	if regionURL := RegionAPIURLs[c.Region]; c.Region != "" && c.APIURL != regionURL {
+		return fmt.Errorf("region %q uses %s, but api-url is %s: set one or the other", c.Region, regionURL, c.APIURL)
+	}
+	if p := c.APIPathPrefix; p != "" && (!strings.HasPrefix(p, "/") || strings.HasSuffix(p, "/") || strings.ContainsAny(p, "?#")) {
+		return fmt.Errorf("api-path-prefix: invalid path %q: must start with / and not end with one, e.g. /api/v2", p)
+	}
+	if err := checkURL(c.InstructionsURL); err != nil {
+		return fmt.Errorf("instructions-url: %w", err)
+	}
+	if c.ProxyURL != "" {
+		u, err := url.Parse(c.ProxyURL)
+		if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "socks5") {
+			return fmt.Errorf("proxy: invalid URL %q: must be an http, https, or socks5 URL", c.ProxyURL)
+		}
+	}
+	if _, err := c.RootCAs(); err != nil {
+		return err
+	}
+	if c.APITimeout < 0 {
+		return fmt.Errorf("api-timeout must not be negative, got %v", c.APITimeout)
+	}
+	if c.APIRetries < 0 || c.APIRetries > MaxAPIRetries {
+		return fmt.Errorf("api-retries must be between 0 and %d, got %d", MaxAPIRetries, c.APIRetries)
+	}
+	if c.APIRetryBackoff < 0 {
+		return fmt.Errorf("api-retry-backoff must not be negative, got %v", c.APIRetryBackoff)
+	}
+	if c.InsightsPreflightRows < 0 {
+		return fmt.Errorf("insights-preflight-rows must not be negative, got %d", c.InsightsPreflightRows)
+	}
+	if c.APILogSampleRate < 0 || c.APILogSampleRate > 1 {
+		return fmt.Errorf("api-log-sample-rate must be between 0 and 1, got %v", c.APILogSampleRate)
+	}
+	if c.SessionMaxAPICalls < 0 {
+		return fmt.Errorf("session-max-api-calls must not be negative, got %d", c.SessionMaxAPICalls)
+	}
+	if c.SessionMaxRows < 0 {
+		return fmt.Errorf("session-max-rows must not be negative, got %d", c.SessionMaxRows)
+	}
+	if c.MaxConcurrentCalls < 0 {
+		return fmt.Errorf("max-concurrent-calls must not be negative, got %d", c.MaxConcurrentCalls)
+	}
+	if c.MaxResultTokens < 0 {
+		return fmt.Errorf("max-result-tokens must not be negative, got %d", c.MaxResultTokens)
+	}
+	if c.QueueTimeout < 0 {
+		return fmt.Errorf("queue-timeout must not be negative, got %v", c.QueueTimeout)
+	}
+	if err := checkAuthStyle(c.AuthStyle); err != nil {
+		return err
+	}
+	if err := checkReadOnlyBehavior(c.ReadOnlyBehavior); err != nil {
+		return err
+	}
+	if c.ChaosRate < 0 || c.ChaosRate > 1 {
+		return fmt.Errorf("chaos rate must be between 0 and 1, got %v", c.ChaosRate)
+	}
+	if c.FixturesDir != "" {
+		info, err := os.Stat(c.FixturesDir)
+		if err != nil {
+			return fmt.Errorf("fixtures: %w", err)
+		}
+		if !info.IsDir() {
+			return fmt.Errorf("fixtures: %s is not a directory", c.FixturesDir)
+		}
+	}
+	if c.ServeStdio && c.TransportMode != TransportHTTP {
+		return errors.New("stdio can only be served alongside http")
+	}
+	// http mode takes the Bearer per-request; startup AuthToken is unused
+	// unless stdio is served too. Fixtures stand in for the API, so there
+	// is nothing to authenticate to.
+	if (c.TransportMode == TransportHTTP && !c.ServeStdio) || c.FixturesDir != "" {
 		return nil
 	}
 	if c.AuthToken == "" {
+		if c.ServeStdio {
+			return errors.New("auth-token is required to serve stdio alongside http")
+		}
 		return errors.New("auth-token is required")
 	}
 	return nil
 }
 
-func Load(authToken, apiURL, instructionsURL, logLevel string, readOnly bool, transportMode string) (*Config, error) {
+func Load(authToken, apiURL, instructionsURL, logLevel string, readOnly bool, transportMode string, opts ...Option) (*Config, error) {
 	if instructionsURL == "" {
 		instructionsURL = DefaultInstructionsURL
 	}
@@ -46,6 +594,12 @@ func Load(authToken, apiURL, instructionsURL, logLevel string, readOnly bool, tr
 		ReadOnly:        readOnly,
 		TransportMode:   transportMode,
 	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.APITimeout == 0 {
+		cfg.APITimeout = DefaultAPITimeout
+	}
 
 	if err := cfg.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid configuration: %w", err)
@@ -1,7 +1,11 @@
 package config
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestConfig_Validate(t *testing.T) {
@@ -117,3 +121,294 @@ func TestLoad(t *testing.T) {
 		})
 	}
 }
+
+func TestLoad_ChaosRate(t *testing.T) {
+	cfg, err := Load("test-token", "", "", "", true, TransportStdio, WithChaosRate(0.25))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.ChaosRate != 0.25 {
+		t.Errorf("Load() ChaosRate = %v, want 0.25", cfg.ChaosRate)
+	}
+
+	for _, rate := range []float64{-0.1, 1.5} {
+		if _, err := Load("test-token", "", "", "", true, TransportStdio, WithChaosRate(rate)); err == nil {
+			t.Errorf("Load() with chaos rate %v should fail", rate)
+		}
+	}
+}
+
+func TestLoad_APILogSampleRate(t *testing.T) {
+	for _, rate := range []float64{-0.1, 1.5} {
+		if _, err := Load("test-token", "", "", "", true, TransportStdio, WithAPILogSampleRate(rate)); err == nil {
+			t.Errorf("Load() with api log sample rate %v should fail", rate)
+		}
+	}
+}
+
+func TestLoad_Fixtures(t *testing.T) {
+	dir := t.TempDir()
+	cfg, err := Load("", "", "", "", true, TransportStdio, WithFixtures(dir))
+	if err != nil {
+		t.Fatalf("Load() with fixtures and no auth token error = %v", err)
+	}
+	if cfg.FixturesDir != dir {
+		t.Errorf("Load() FixturesDir = %q, want %q", cfg.FixturesDir, dir)
+	}
+
+	file := filepath.Join(dir, "file.json")
+	if err := os.WriteFile(file, []byte("{}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{filepath.Join(dir, "missing"), file} {
+		if _, err := Load("", "", "", "", true, TransportStdio, WithFixtures(path)); err == nil || !strings.Contains(err.Error(), "fixtures") {
+			t.Errorf("Load() with fixtures %s error = %v, want a fixtures error", path, err)
+		}
+	}
+}
+
+func TestConfig_ToolEnabled(t *testing.T) {
+	tests := []struct {
+		name     string
+		enabled  []string
+		disabled []string
+		tool     string
+		want     bool
+	}{
+		{"no patterns", nil, nil, "delete_project", true},
+		{"enabled match", []string{"list_*", "get_*"}, nil, "get_fault", true},
+		{"enabled miss", []string{"list_*", "get_*"}, nil, "delete_project", false},
+		{"disabled match", nil, []string{"delete_*"}, "delete_project", false},
+		{"disabled wins over enabled", []string{"*_project"}, []string{"delete_*"}, "delete_project", false},
+		{"exact name", []string{"query_insights"}, nil, "query_insights", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{EnabledTools: tt.enabled, DisabledTools: tt.disabled}
+			if got := cfg.ToolEnabled(tt.tool); got != tt.want {
+				t.Errorf("ToolEnabled(%q) = %v, want %v", tt.tool, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_ToolEnabledUnsupported(t *testing.T) {
+	cfg := &Config{EnabledTools: []string{"*_insights"}, UnsupportedTools: []string{"query_insights"}}
+	if cfg.ToolEnabled("query_insights") {
+		t.Error("expected an unsupported tool to be left out even when enabled")
+	}
+}
+
+func TestLoad_Region(t *testing.T) {
+	cfg, err := Load("token", RegionAPIURLs[RegionEU], "", "", true, TransportStdio, WithRegion(RegionEU))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.APIURL != "https://eu-app.honeybadger.io" {
+		t.Errorf("APIURL = %q, want the EU URL", cfg.APIURL)
+	}
+	if _, err := Load("token", "https://app.honeybadger.io", "", "", true, TransportStdio, WithRegion(RegionEU)); err == nil || !strings.Contains(err.Error(), `region "eu" uses https://eu-app.honeybadger.io, but api-url is https://app.honeybadger.io`) {
+		t.Errorf("expected a region and api-url mismatch error, got %v", err)
+	}
+	if _, err := Load("token", "https://app.honeybadger.io", "", "", true, TransportStdio, WithRegion("ap")); err == nil || !strings.Contains(err.Error(), "invalid region") {
+		t.Errorf("expected an invalid region error, got %v", err)
+	}
+}
+
+func TestLoad_APIPathPrefix(t *testing.T) {
+	for _, prefix := range []string{"", "/v2", "/api/v2", "/honeybadger/v1"} {
+		if _, err := Load("token", "", "", "", true, TransportStdio, WithAPIPathPrefix(prefix)); err != nil {
+			t.Errorf("Load() with prefix %q error = %v", prefix, err)
+		}
+	}
+	for _, prefix := range []string{"v2", "/api/", "/v2?x=1"} {
+		if _, err := Load("token", "", "", "", true, TransportStdio, WithAPIPathPrefix(prefix)); err == nil || !strings.Contains(err.Error(), "api-path-prefix") {
+			t.Errorf("expected prefix %q to be rejected, got %v", prefix, err)
+		}
+	}
+}
+
+func TestLoad_InvalidToolPattern(t *testing.T) {
+	if _, err := Load("test-token", "", "", "", true, TransportStdio, WithDisabledTools([]string{"list_["})); err == nil {
+		t.Error("Load() with a malformed tool pattern should fail")
+	}
+}
+
+func TestLoad_DeferToolsRequiresSearchTools(t *testing.T) {
+	_, err := Load("test-token", "", "", "", true, TransportStdio, WithDeferTools(true), WithDisabledTools([]string{"search_*"}))
+	if err == nil {
+		t.Error("Load() should reject defer-tools with search_tools disabled")
+	}
+}
+
+func TestLoad_ProxyAndCABundle(t *testing.T) {
+	bundle := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(bundle, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		opts    []Option
+		wantErr string
+	}{
+		{"http proxy", []Option{WithProxy("http://proxy.internal:3128")}, ""},
+		{"socks5 proxy", []Option{WithProxy("socks5://127.0.0.1:1080")}, ""},
+		{"proxy without scheme", []Option{WithProxy("proxy.internal:3128")}, "proxy: invalid URL"},
+		{"missing CA bundle", []Option{WithCABundle(filepath.Join(t.TempDir(), "missing.pem"))}, "ca-bundle"},
+		{"CA bundle without certificates", []Option{WithCABundle(bundle)}, "no PEM certificates"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load("token", "", "", "", true, TransportStdio, tt.opts...)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Load() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestLoad_APITimeoutAndRetries(t *testing.T) {
+	cfg, err := Load("token", "", "", "", true, TransportStdio)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.APITimeout != DefaultAPITimeout || cfg.APIRetries != 0 {
+		t.Errorf("expected default timeout and no retries, got %v and %d", cfg.APITimeout, cfg.APIRetries)
+	}
+
+	tests := []struct {
+		name    string
+		opts    []Option
+		wantErr string
+	}{
+		{"custom values", []Option{WithAPITimeout(2 * time.Minute), WithAPIRetries(3), WithAPIRetryBackoff(time.Second)}, ""},
+		{"negative timeout", []Option{WithAPITimeout(-time.Second)}, "api-timeout"},
+		{"negative retries", []Option{WithAPIRetries(-1)}, "api-retries"},
+		{"too many retries", []Option{WithAPIRetries(MaxAPIRetries + 1)}, "api-retries"},
+		{"negative backoff", []Option{WithAPIRetryBackoff(-time.Second)}, "api-retry-backoff"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Load("token", "", "", "", true, TransportStdio, tt.opts...)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Load() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestConfig_AuthStyle(t *testing.T) {
+	tests := []struct {
+		name      string
+		transport string
+		style     string
+		want      string
+	}{
+		{"stdio default", TransportStdio, "", AuthStyleBasicUsername},
+		{"http default", TransportHTTP, "", AuthStyleBearer},
+		{"explicit", TransportStdio, AuthStyleBasicPassword, AuthStyleBasicPassword},
+		{"explicit over http", TransportHTTP, AuthStyleBasicUsername, AuthStyleBasicUsername},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := Load("token", "", "", "", true, tt.transport, WithAuthStyle(tt.style))
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if got := cfg.ResolvedAuthStyle(); got != tt.want {
+				t.Errorf("ResolvedAuthStyle() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := Load("token", "", "", "", true, TransportStdio, WithAuthStyle("digest")); err == nil || !strings.Contains(err.Error(), "invalid auth style") {
+		t.Errorf("expected an invalid auth style error, got %v", err)
+	}
+}
+
+func TestLoad_ServeStdio(t *testing.T) {
+	cfg, err := Load("token", "", "", "", true, TransportHTTP, WithServeStdio(true))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if !cfg.ServeStdio || cfg.AuthStyleFor(TransportStdio) != AuthStyleBasicUsername || cfg.ResolvedAuthStyle() != AuthStyleBearer {
+		t.Errorf("expected stdio served with its own auth style, got %+v", cfg)
+	}
+
+	if _, err := Load("", "", "", "", true, TransportHTTP, WithServeStdio(true)); err == nil || !strings.Contains(err.Error(), "auth-token is required to serve stdio") {
+		t.Errorf("expected the stdio side to need a token, got %v", err)
+	}
+	if _, err := Load("token", "", "", "", true, TransportStdio, WithServeStdio(true)); err == nil || !strings.Contains(err.Error(), "alongside http") {
+		t.Errorf("expected stdio mode to refuse serving stdio again, got %v", err)
+	}
+}
+
+func TestLoad_ReadOnlyBehavior(t *testing.T) {
+	cfg, err := Load("token", "", "", "", true, TransportStdio, WithReadOnlyBehavior(ReadOnlyError))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.ReadOnlyBehavior != ReadOnlyError {
+		t.Errorf("ReadOnlyBehavior = %q, want %q", cfg.ReadOnlyBehavior, ReadOnlyError)
+	}
+	if _, err := Load("token", "", "", "", true, TransportStdio, WithReadOnlyBehavior("warn")); err == nil || !strings.Contains(err.Error(), "invalid read-only behavior") {
+		t.Errorf("expected invalid read-only behavior error, got %v", err)
+	}
+}
+
+func TestConfig_ProjectAllowed(t *testing.T) {
+	tests := []struct {
+		name    string
+		allowed []int
+		denied  []int
+		want    map[int]bool
+	}{
+		{"unscoped", nil, nil, map[int]bool{1: true, 2: true}},
+		{"allowlist", []int{1}, nil, map[int]bool{1: true, 2: false}},
+		{"denylist", nil, []int{2}, map[int]bool{1: true, 2: false}},
+		{"denylist wins", []int{1, 2}, []int{2}, map[int]bool{1: true, 2: false, 3: false}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{AllowedProjectIDs: tt.allowed, DeniedProjectIDs: tt.denied}
+			if got, want := cfg.ProjectScoped(), tt.allowed != nil || tt.denied != nil; got != want {
+				t.Errorf("ProjectScoped() = %v, want %v", got, want)
+			}
+			for id, want := range tt.want {
+				if got := cfg.ProjectAllowed(id); got != want {
+					t.Errorf("ProjectAllowed(%d) = %v, want %v", id, got, want)
+				}
+			}
+		})
+	}
+
+	if _, err := Load("token", "", "", "", true, TransportStdio, WithDeniedProjectIDs([]int{-1})); err == nil || !strings.Contains(err.Error(), "invalid project ID -1") {
+		t.Errorf("expected an invalid project ID error, got %v", err)
+	}
+}
+
+func TestParseIDs(t *testing.T) {
+	ids, err := ParseIDs([]string{"1, 2", "", " 3 "})
+	if err != nil {
+		t.Fatalf("ParseIDs() error = %v", err)
+	}
+	if len(ids) != 3 || ids[0] != 1 || ids[1] != 2 || ids[2] != 3 {
+		t.Errorf("ParseIDs() = %v, want [1 2 3]", ids)
+	}
+	if _, err := ParseIDs([]string{"1,abc"}); err == nil {
+		t.Error("expected an error for a non-numeric ID")
+	}
+}
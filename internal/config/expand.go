@@ -0,0 +1,85 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// envRef matches the references ExpandEnv replaces: ${VAR}, ${VAR:-default},
+// and the escape $${, which stands for a literal ${.
+var envRef = regexp.MustCompile(`\$\$\{|\$\{([A-Za-z_][A-Za-z0-9_]*)(?::-([^}]*))?\}`)
+
+// ExpandEnv replaces ${VAR} references in the string values of settings,
+// as read from a config file, with environment variables looked up with
+// lookup, so a committed config can leave secrets and per-environment
+// values to the environment. ${VAR:-default} falls back to default when
+// VAR is unset or empty, and $${ is a literal ${. Lists and nested maps
+// are expanded too; keys aren't. A reference to an unset variable without
+// a default is an error, naming every such variable, rather than an empty
+// value the setting might accept.
+func ExpandEnv(settings map[string]any, lookup func(string) (string, bool)) error {
+	missing := map[string]bool{}
+	expandMap(settings, lookup, missing)
+	if len(missing) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(missing))
+	for name := range missing {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var errs []error
+	for _, name := range names {
+		errs = append(errs, fmt.Errorf("environment variable %s is not set (use ${%s:-default} to give a default)", name, name))
+	}
+	return errors.Join(errs...)
+}
+
+func expandMap(m map[string]any, lookup func(string) (string, bool), missing map[string]bool) {
+	for k, v := range m {
+		m[k] = expandValue(v, lookup, missing)
+	}
+}
+
+func expandValue(v any, lookup func(string) (string, bool), missing map[string]bool) any {
+	switch v := v.(type) {
+	case string:
+		return expandString(v, lookup, missing)
+	case []any:
+		for i, item := range v {
+			v[i] = expandValue(item, lookup, missing)
+		}
+		return v
+	case map[string]any:
+		expandMap(v, lookup, missing)
+		return v
+	default:
+		return v
+	}
+}
+
+func expandString(s string, lookup func(string) (string, bool), missing map[string]bool) string {
+	if !strings.Contains(s, "${") {
+		return s
+	}
+	return envRef.ReplaceAllStringFunc(s, func(ref string) string {
+		if ref == "$${" {
+			return "${"
+		}
+		m := envRef.FindStringSubmatch(ref)
+		name, hasDefault := m[1], strings.Contains(ref, ":-")
+		value, ok := lookup(name)
+		switch {
+		case ok && (value != "" || !hasDefault):
+			return value
+		case hasDefault:
+			return m[2]
+		default:
+			missing[name] = true
+			return ""
+		}
+	})
+}
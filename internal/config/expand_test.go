@@ -0,0 +1,50 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExpandEnv(t *testing.T) {
+	env := map[string]string{"TOKEN": "secret", "EMPTY": "", "PROJECT": "42"}
+	lookup := func(name string) (string, bool) {
+		v, ok := env[name]
+		return v, ok
+	}
+	settings := map[string]any{
+		"auth-token":          "${TOKEN}",
+		"api-url":             "https://${HOST:-app.honeybadger.io}/",
+		"log-level":           "${EMPTY:-debug}",
+		"proxy":               "${EMPTY}",
+		"audit-log":           "/var/log/$${HOME}/audit.log",
+		"allowed-project-ids": []any{"${PROJECT}", 7},
+		"read-only":           false,
+	}
+	if err := ExpandEnv(settings, lookup); err != nil {
+		t.Fatalf("ExpandEnv() error = %v", err)
+	}
+	want := map[string]any{
+		"auth-token":          "secret",
+		"api-url":             "https://app.honeybadger.io/",
+		"log-level":           "debug",
+		"proxy":               "",
+		"audit-log":           "/var/log/${HOME}/audit.log",
+		"allowed-project-ids": []any{"42", 7},
+		"read-only":           false,
+	}
+	if !reflect.DeepEqual(settings, want) {
+		t.Errorf("ExpandEnv() = %v, want %v", settings, want)
+	}
+}
+
+func TestExpandEnv_Unset(t *testing.T) {
+	settings := map[string]any{"auth-token": "${TOKEN_PROD}", "api-url": "${API_URL}", "proxy": "${TOKEN_PROD}"}
+	err := ExpandEnv(settings, func(string) (string, bool) { return "", false })
+	if err == nil {
+		t.Fatal("expected an error for unset variables")
+	}
+	if msg := err.Error(); strings.Count(msg, "is not set") != 2 || strings.Index(msg, "API_URL") > strings.Index(msg, "TOKEN_PROD") {
+		t.Errorf("expected each unset variable reported once, in order, got %q", msg)
+	}
+}
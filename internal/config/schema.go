@@ -0,0 +1,357 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Kind is the type a config file value must have.
+type Kind int
+
+const (
+	KindString Kind = iota
+	KindBool
+	KindFloat
+	KindInt
+	// KindDuration is a Go duration string such as "30s" or "1m30s".
+	KindDuration
+	// KindList accepts a YAML list of strings or a comma-separated string.
+	KindList
+	// KindIDList accepts a YAML list of positive integers or a
+	// comma-separated string of them.
+	KindIDList
+	// KindURL is a string that must be an absolute http(s) URL.
+	KindURL
+	// KindLogLevel is one of LogLevels.
+	KindLogLevel
+	// KindAuthStyle is one of AuthStyles.
+	KindAuthStyle
+	// KindReadOnlyBehavior is one of ReadOnlyBehaviors.
+	KindReadOnlyBehavior
+	// KindRegion is one of Regions.
+	KindRegion
+)
+
+// FileKey is a setting the config file accepts. Names match the CLI flags.
+type FileKey struct {
+	Name string
+	Kind Kind
+}
+
+// FileKeys is the config file schema: every key viper would otherwise
+// accept silently, so typos can be reported instead of ignored.
+var FileKeys = []FileKey{
+	{"auth-token", KindString},
+	{"api-url", KindURL},
+	{"instructions-url", KindURL},
+	{"log-level", KindLogLevel},
+	{"read-only", KindBool},
+	{"read-only-behavior", KindReadOnlyBehavior},
+	{"enabled-tools", KindList},
+	{"disabled-tools", KindList},
+	{"defer-tools", KindBool},
+	{"audit-log", KindString},
+	{"chaos", KindFloat},
+	{"proxy", KindString},
+	{"ca-bundle", KindString},
+	{"api-timeout", KindDuration},
+	{"api-retries", KindInt},
+	{"api-retry-backoff", KindDuration},
+	{"auth-style", KindAuthStyle},
+	{"lenient-decoding", KindBool},
+	{"fixtures", KindString},
+	{"confirm-destructive", KindBool},
+	{"reference-cache-dir", KindString},
+	{"refresh-reference", KindBool},
+	{"raw-api", KindBool},
+	{"not-found-lookup", KindBool},
+	{"insights-preflight-rows", KindInt},
+	{"api-log-sample-rate", KindFloat},
+	{"session-max-api-calls", KindInt},
+	{"session-max-rows", KindInt},
+	{"max-concurrent-calls", KindInt},
+	{"queue-timeout", KindDuration},
+	{"max-result-tokens", KindInt},
+	{"region", KindRegion},
+	{"api-path-prefix", KindString},
+	{"detect-capabilities", KindBool},
+	{"allowed-project-ids", KindIDList},
+	{"denied-project-ids", KindIDList},
+	{"address", KindString},
+	{"endpoint-path", KindString},
+	{"stateless", KindBool},
+	{"stdio", KindBool},
+	{"public-url", KindURL},
+	{"authorization-server", KindURL},
+	{"resource-url", KindURL},
+	{"webhook-address", KindString},
+	{"webhook-path", KindString},
+	{"webhook-secret", KindString},
+}
+
+// LogLevels are the accepted log-level values, case-insensitively.
+var LogLevels = []string{"debug", "info", "warn", "warning", "error"}
+
+// AuthStyles are the accepted auth-style values.
+var AuthStyles = []string{AuthStyleBasicUsername, AuthStyleBasicPassword, AuthStyleBearer}
+
+// ReadOnlyBehaviors are the accepted read-only-behavior values.
+var ReadOnlyBehaviors = []string{ReadOnlyHide, ReadOnlyError}
+
+// Regions are the accepted region values.
+var Regions = []string{RegionUS, RegionEU}
+
+// CheckFileSettings validates the settings read from a config file (as
+// returned by viper's AllSettings) against FileKeys. Every problem is
+// reported, not just the first.
+func CheckFileSettings(settings map[string]any) error {
+	var errs []error
+	for _, key := range sortedKeys(settings) {
+		i := slices.IndexFunc(FileKeys, func(k FileKey) bool { return k.Name == key })
+		if i < 0 {
+			msg := fmt.Sprintf("unknown key %q", key)
+			if s := suggestKey(key); s != "" {
+				msg += fmt.Sprintf(" (did you mean %q?)", s)
+			}
+			errs = append(errs, errors.New(msg))
+			continue
+		}
+		if err := checkKind(FileKeys[i].Kind, settings[key]); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", key, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func checkKind(kind Kind, value any) error {
+	switch kind {
+	case KindBool:
+		switch v := value.(type) {
+		case bool:
+			return nil
+		case string:
+			if _, err := strconv.ParseBool(v); err == nil {
+				return nil
+			}
+		}
+		return fmt.Errorf("expected true or false, got %v", describe(value))
+	case KindFloat:
+		switch v := value.(type) {
+		case int, int64, float64:
+			return nil
+		case string:
+			if _, err := strconv.ParseFloat(v, 64); err == nil {
+				return nil
+			}
+		}
+		return fmt.Errorf("expected a number, got %v", describe(value))
+	case KindInt:
+		switch v := value.(type) {
+		case int, int64:
+			return nil
+		case string:
+			if _, err := strconv.Atoi(v); err == nil {
+				return nil
+			}
+		}
+		return fmt.Errorf("expected a whole number, got %v", describe(value))
+	case KindDuration:
+		if s, ok := value.(string); ok {
+			if _, err := time.ParseDuration(s); err == nil {
+				return nil
+			}
+		}
+		return fmt.Errorf("expected a duration such as \"30s\", got %v", describe(value))
+	case KindList:
+		switch v := value.(type) {
+		case string:
+			return nil
+		case []any:
+			for _, item := range v {
+				if _, ok := item.(string); !ok {
+					return fmt.Errorf("expected a list of strings, got item %v", describe(item))
+				}
+			}
+			return nil
+		}
+		return fmt.Errorf("expected a list of strings or a comma-separated string, got %v", describe(value))
+	case KindIDList:
+		switch v := value.(type) {
+		case string:
+			_, err := ParseIDs([]string{v})
+			return err
+		case []any:
+			items := make([]string, len(v))
+			for i, item := range v {
+				switch item.(type) {
+				case int, int64, string:
+					items[i] = fmt.Sprint(item)
+				default:
+					return fmt.Errorf("expected a list of IDs, got item %v", describe(item))
+				}
+			}
+			_, err := ParseIDs(items)
+			return err
+		}
+		return fmt.Errorf("expected a list of IDs or a comma-separated string, got %v", describe(value))
+	case KindURL:
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("expected a URL, got %v", describe(value))
+		}
+		return checkURL(s)
+	case KindLogLevel:
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("expected one of %s, got %v", strings.Join(LogLevels, ", "), describe(value))
+		}
+		return checkLogLevel(s)
+	case KindAuthStyle:
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("expected one of %s, got %v", strings.Join(AuthStyles, ", "), describe(value))
+		}
+		return checkAuthStyle(s)
+	case KindReadOnlyBehavior:
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("expected one of %s, got %v", strings.Join(ReadOnlyBehaviors, ", "), describe(value))
+		}
+		return checkReadOnlyBehavior(s)
+	case KindRegion:
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("expected one of %s, got %v", strings.Join(Regions, ", "), describe(value))
+		}
+		return checkRegion(s)
+	default:
+		if _, ok := value.(string); !ok {
+			return fmt.Errorf("expected a string, got %v", describe(value))
+		}
+		return nil
+	}
+}
+
+// ParseIDs parses IDs given as list items, comma-separated strings, or
+// both. Blank entries are skipped.
+func ParseIDs(items []string) ([]int, error) {
+	var ids []int
+	for _, item := range items {
+		for field := range strings.SplitSeq(item, ",") {
+			if field = strings.TrimSpace(field); field == "" {
+				continue
+			}
+			id, err := strconv.Atoi(field)
+			if err != nil || id < 1 {
+				return nil, fmt.Errorf("invalid ID %q: must be a positive integer", field)
+			}
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// checkURL accepts an empty string, which means "use the default".
+func checkURL(s string) error {
+	if s == "" {
+		return nil
+	}
+	u, err := url.Parse(s)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid URL %q: must be an absolute http or https URL", s)
+	}
+	return nil
+}
+
+// checkLogLevel accepts an empty string, which means "use the default".
+func checkLogLevel(s string) error {
+	if s == "" || slices.Contains(LogLevels, strings.ToLower(s)) {
+		return nil
+	}
+	return fmt.Errorf("invalid log level %q: must be one of %s", s, strings.Join(LogLevels, ", "))
+}
+
+// checkAuthStyle accepts an empty string, which means "use the default".
+func checkAuthStyle(s string) error {
+	if s == "" || slices.Contains(AuthStyles, s) {
+		return nil
+	}
+	return fmt.Errorf("invalid auth style %q: must be one of %s", s, strings.Join(AuthStyles, ", "))
+}
+
+func checkReadOnlyBehavior(s string) error {
+	if s == "" || slices.Contains(ReadOnlyBehaviors, s) {
+		return nil
+	}
+	return fmt.Errorf("invalid read-only behavior %q: must be one of %s", s, strings.Join(ReadOnlyBehaviors, ", "))
+}
+
+// checkRegion accepts an empty string, which means "use api-url".
+func checkRegion(s string) error {
+	if s == "" || slices.Contains(Regions, s) {
+		return nil
+	}
+	return fmt.Errorf("invalid region %q: must be one of %s", s, strings.Join(Regions, ", "))
+}
+
+func describe(value any) string {
+	switch value.(type) {
+	case map[string]any:
+		return "a nested mapping"
+	case []any:
+		return "a list"
+	default:
+		return fmt.Sprintf("%#v", value)
+	}
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// suggestKey returns the known key closest to key, if it is a plausible
+// typo (an edit distance of at most 3, or the same words with underscores).
+func suggestKey(key string) string {
+	normalized := strings.ReplaceAll(key, "_", "-")
+	best, bestDist := "", 4
+	for _, k := range FileKeys {
+		if k.Name == normalized {
+			return k.Name
+		}
+		if d := editDistance(normalized, k.Name); d < bestDist {
+			best, bestDist = k.Name, d
+		}
+	}
+	return best
+}
+
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
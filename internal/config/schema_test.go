@@ -0,0 +1,115 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckFileSettings(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings map[string]any
+		wantErrs []string
+	}{
+		{
+			name: "valid",
+			settings: map[string]any{
+				"auth-token":          "token",
+				"api-url":             "https://eu-app.honeybadger.io",
+				"log-level":           "DEBUG",
+				"read-only":           false,
+				"chaos":               0.5,
+				"enabled-tools":       []any{"list_*", "get_*"},
+				"stateless":           "true",
+				"api-timeout":         "2m",
+				"api-retries":         3,
+				"auth-style":          "bearer",
+				"allowed-project-ids": []any{1, "2"},
+				"denied-project-ids":  "3, 4",
+			},
+		},
+		{
+			name:     "unknown key with suggestion",
+			settings: map[string]any{"read_only": true, "auth-tokn": "x"},
+			wantErrs: []string{`unknown key "auth-tokn" (did you mean "auth-token"?)`, `unknown key "read_only" (did you mean "read-only"?)`},
+		},
+		{
+			name:     "unknown key without suggestion",
+			settings: map[string]any{"favorite-color": "blue"},
+			wantErrs: []string{`unknown key "favorite-color"`},
+		},
+		{
+			name: "mistyped values",
+			settings: map[string]any{
+				"read-only":      "sometimes",
+				"chaos":          "lots",
+				"disabled-tools": map[string]any{"a": "b"},
+				"auth-token":     123,
+			},
+			wantErrs: []string{"read-only: expected true or false", "chaos: expected a number", "disabled-tools: expected a list", "auth-token: expected a string"},
+		},
+		{
+			name:     "bad log level and URL",
+			settings: map[string]any{"log-level": "verbose", "public-url": "mcp.example.com"},
+			wantErrs: []string{`invalid log level "verbose"`, `public-url: invalid URL "mcp.example.com"`},
+		},
+		{
+			name:     "bad duration and count",
+			settings: map[string]any{"api-timeout": 30, "api-retries": "a few"},
+			wantErrs: []string{"api-timeout: expected a duration", "api-retries: expected a whole number"},
+		},
+		{
+			name:     "bad project IDs",
+			settings: map[string]any{"allowed-project-ids": []any{1, "two"}, "denied-project-ids": "0"},
+			wantErrs: []string{`allowed-project-ids: invalid ID "two"`, `denied-project-ids: invalid ID "0"`},
+		},
+		{
+			name:     "bad auth style",
+			settings: map[string]any{"auth-style": "digest"},
+			wantErrs: []string{`auth-style: invalid auth style "digest"`},
+		},
+		{
+			name:     "bad read-only behavior",
+			settings: map[string]any{"read-only-behavior": "warn"},
+			wantErrs: []string{`read-only-behavior: invalid read-only behavior "warn"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckFileSettings(tt.settings)
+			if len(tt.wantErrs) == 0 {
+				if err != nil {
+					t.Fatalf("CheckFileSettings() error = %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			for _, want := range tt.wantErrs {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("expected %q in error:\n%v", want, err)
+				}
+			}
+		})
+	}
+}
+
+func TestConfig_ValidateLogLevelAndURLs(t *testing.T) {
+	tests := []struct {
+		name   string
+		config Config
+	}{
+		{"bad log level", Config{AuthToken: "t", LogLevel: "loud"}},
+		{"relative api url", Config{AuthToken: "t", APIURL: "app.honeybadger.io"}},
+		{"non-http instructions url", Config{AuthToken: "t", InstructionsURL: "ftp://docs.example.com"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.config.Validate(); err == nil {
+				t.Error("expected validation error")
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// tokenProviderTimeout bounds resolving a token reference. It is generous
+// since a password manager may wait on the user to unlock it.
+const tokenProviderTimeout = time.Minute
+
+// TokenProvider resolves auth-token values starting with Prefix, so the
+// token can live in a password manager rather than in plain text in the
+// environment, a flag, or the config file.
+type TokenProvider struct {
+	Prefix string
+	// Resolve returns the token the whole value (prefix included) refers
+	// to.
+	Resolve func(ctx context.Context, ref string) (string, error)
+}
+
+// TokenProviders are the token references auth-token accepts:
+//
+//	keychain:SERVICE[/ACCOUNT]   a macOS Keychain generic password
+//	op://VAULT/ITEM/FIELD        a 1Password secret reference, read with the op CLI
+//	exec:COMMAND [ARGS...]       the output of a command, split on spaces and run without a shell
+var TokenProviders = []TokenProvider{
+	{Prefix: "keychain:", Resolve: keychainToken},
+	{Prefix: "op://", Resolve: onePasswordToken},
+	{Prefix: "exec:", Resolve: execToken},
+}
+
+// runCommand runs a provider's command and returns its output with
+// surrounding whitespace trimmed. It is a variable for tests.
+var runCommand = func(ctx context.Context, name string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%s: %w: %s", name, err, msg)
+		}
+		return "", fmt.Errorf("%s: %w", name, err)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// IsTokenReference reports whether value is resolved by a TokenProvider
+// rather than being the token itself.
+func IsTokenReference(value string) bool {
+	_, ok := tokenProvider(value)
+	return ok
+}
+
+// ResolveToken returns the token value refers to, or value itself when it
+// isn't a reference. An empty result is an error, so a misconfigured
+// provider isn't mistaken for a missing token.
+func ResolveToken(ctx context.Context, value string) (string, error) {
+	p, ok := tokenProvider(value)
+	if !ok {
+		return value, nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, tokenProviderTimeout)
+	defer cancel()
+	token, err := p.Resolve(ctx, value)
+	if err != nil {
+		return "", fmt.Errorf("resolve %s: %w", value, err)
+	}
+	if token == "" {
+		return "", fmt.Errorf("resolve %s: empty token", value)
+	}
+	return token, nil
+}
+
+func tokenProvider(value string) (TokenProvider, bool) {
+	for _, p := range TokenProviders {
+		if strings.HasPrefix(value, p.Prefix) {
+			return p, true
+		}
+	}
+	return TokenProvider{}, false
+}
+
+func keychainToken(ctx context.Context, ref string) (string, error) {
+	if runtime.GOOS != "darwin" {
+		return "", errors.New("the Keychain is only available on macOS")
+	}
+	service, account, _ := strings.Cut(strings.TrimPrefix(ref, "keychain:"), "/")
+	if service == "" {
+		return "", errors.New("expected keychain:SERVICE or keychain:SERVICE/ACCOUNT")
+	}
+	args := []string{"find-generic-password", "-s", service, "-w"}
+	if account != "" {
+		args = append(args, "-a", account)
+	}
+	return runCommand(ctx, "security", args...)
+}
+
+func onePasswordToken(ctx context.Context, ref string) (string, error) {
+	return runCommand(ctx, "op", "read", "--no-newline", ref)
+}
+
+func execToken(ctx context.Context, ref string) (string, error) {
+	args := strings.Fields(strings.TrimPrefix(ref, "exec:"))
+	if len(args) == 0 {
+		return "", errors.New("expected exec: followed by a command")
+	}
+	return runCommand(ctx, args[0], args[1:]...)
+}
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestResolveToken(t *testing.T) {
+	orig := runCommand
+	t.Cleanup(func() { runCommand = orig })
+	var ran []string
+	runCommand = func(ctx context.Context, name string, args ...string) (string, error) {
+		ran = append(ran, strings.Join(append([]string{name}, args...), " "))
+		if name == "false" {
+			return "", errors.New("false: exit status 1")
+		}
+		if name == "true" {
+			return "", nil
+		}
+		return "hbp_token", nil
+	}
+
+	type tokenCase struct {
+		value   string
+		want    string
+		command string
+		wantErr string
+	}
+	keychain := tokenCase{"keychain:honeybadger", "", "", "only available on macOS"}
+	if runtime.GOOS == "darwin" {
+		keychain = tokenCase{"keychain:honeybadger/me", "hbp_token", "security find-generic-password -s honeybadger -w -a me", ""}
+	}
+	tests := []tokenCase{
+		{"hbp_plain", "hbp_plain", "", ""},
+		{"op://Work/Honeybadger/token", "hbp_token", "op read --no-newline op://Work/Honeybadger/token", ""},
+		{"exec:pass show  honeybadger", "hbp_token", "pass show honeybadger", ""},
+		{"exec:", "", "", "expected exec: followed by a command"},
+		{"exec:false", "", "false", "exit status 1"},
+		{"exec:true", "", "true", "empty token"},
+		keychain,
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			ran = nil
+			got, err := ResolveToken(context.Background(), tt.value)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("expected an error containing %q, got %v", tt.wantErr, err)
+				}
+			} else if err != nil || got != tt.want {
+				t.Errorf("ResolveToken() = %q, %v, want %q", got, err, tt.want)
+			}
+			if tt.command != "" && (len(ran) != 1 || ran[0] != tt.command) {
+				t.Errorf("expected %q run, got %q", tt.command, ran)
+			}
+			if tt.command == "" && len(ran) != 0 {
+				t.Errorf("expected no command run, got %q", ran)
+			}
+		})
+	}
+}
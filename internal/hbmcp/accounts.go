@@ -0,0 +1,284 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/mail"
+	"slices"
+	"strings"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// accountRoles are the roles a user can hold in an account, from least to
+// most access.
+var accountRoles = []string{"Member", "Billing", "Admin", "Owner"}
+
+type accountUsers struct {
+	Users              []hbapi.AccountUser       `json:"users"`
+	PendingInvitations []hbapi.AccountInvitation `json:"pending_invitations"`
+}
+
+// RegisterAccountTools registers the tools that manage an account's users.
+func RegisterAccountTools(r *toolRegistrar, clientFor ClientFactory) {
+	// list_account_users tool
+	r.AddTool(
+		mcp.NewTool("list_account_users",
+			mcp.WithTitleAnnotation("List Account Users"),
+			mcp.WithDescription("List the users of an account with their roles, plus invitations that haven't been accepted yet, which hold a seat once accepted. Find account IDs with check_connection."),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithOutputSchema[accountUsers](),
+			mcp.WithString("account_id",
+				mcp.Required(),
+				mcp.Description("The ID of the account whose users to list"),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleListAccountUsers(ctx, clientFor(ctx), req)
+		},
+	)
+
+	// resolve_user tool
+	r.AddTool(
+		mcp.NewTool("resolve_user",
+			mcp.WithTitleAnnotation("Resolve User"),
+			mcp.WithDescription("Find the Honeybadger user ID for an email address among a project's users, a team's members, or an account's users, for tools that take a user ID such as update_fault's assignee_id. Pass exactly one of project_id, team_id, or account_id; a project is the right scope for assigning its faults."),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithOutputSchema[hbapi.User](),
+			mcp.WithString("email",
+				mcp.Required(),
+				mcp.Description("Email address of the user, matched ignoring case"),
+			),
+			mcp.WithNumber("project_id",
+				mcp.Description("Look among the users with access to this project"),
+				mcp.Min(1),
+			),
+			mcp.WithNumber("team_id",
+				mcp.Description("Look among this team's members"),
+				mcp.Min(1),
+			),
+			mcp.WithString("account_id",
+				mcp.Description("Look among this account's users (see check_connection for account IDs)"),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleResolveUser(ctx, clientFor(ctx), req)
+		},
+	)
+
+	// invite_account_user tool
+	r.AddTool(
+		mcp.NewTool("invite_account_user",
+			mcp.WithTitleAnnotation("Invite Account User"),
+			mcp.WithDescription("Invite someone to an account by email. Honeybadger emails them a link to join, and they take a seat once they accept."),
+			mcp.WithReadOnlyHintAnnotation(false),
+			mcp.WithDestructiveHintAnnotation(true),
+			mcp.WithIdempotentHintAnnotation(false),
+			mcp.WithOpenWorldHintAnnotation(true),
+			mcp.WithString("account_id",
+				mcp.Required(),
+				mcp.Description("The ID of the account to invite to"),
+			),
+			mcp.WithString("email",
+				mcp.Required(),
+				mcp.Description("Email address to send the invitation to"),
+			),
+			mcp.WithString("role",
+				mcp.Description("The invitee's role in the account (default Member)"),
+				mcp.Enum(accountRoles...),
+			),
+			mcp.WithArray("team_ids",
+				mcp.Description("IDs of teams to add the invitee to when they accept"),
+				mcp.WithNumberItems(mcp.Min(1)),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleInviteAccountUser(ctx, clientFor(ctx), req)
+		},
+	)
+
+	// remove_account_user tool
+	r.AddTool(
+		mcp.NewTool("remove_account_user",
+			mcp.WithTitleAnnotation("Remove Account User"),
+			mcp.WithDescription("Remove a user from an account, freeing their seat. They lose access to the account's projects but keep their Honeybadger login. Find user IDs with list_account_users."),
+			mcp.WithReadOnlyHintAnnotation(false),
+			mcp.WithDestructiveHintAnnotation(true),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithString("account_id",
+				mcp.Required(),
+				mcp.Description("The ID of the account to remove the user from"),
+			),
+			mcp.WithNumber("user_id",
+				mcp.Required(),
+				mcp.Description("The ID of the user to remove"),
+				mcp.Min(1),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleRemoveAccountUser(ctx, clientFor(ctx), req)
+		},
+	)
+}
+
+func handleListAccountUsers(ctx context.Context, client *hbapi.Client, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	accountID := req.GetString("account_id", "")
+	if accountID == "" {
+		return mcp.NewToolResultError("account_id is required"), nil
+	}
+
+	users, err := client.Accounts.ListUsers(ctx, accountID)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to list account users: %v", err)), nil
+	}
+
+	var notes toolNotes
+	result := accountUsers{Users: users, PendingInvitations: []hbapi.AccountInvitation{}}
+	if invitations, err := client.Accounts.ListInvitations(ctx, accountID); err != nil {
+		notes.warnf("pending invitations left out: %v", err)
+	} else {
+		for _, inv := range invitations {
+			if inv.AcceptedAt == nil {
+				result.PendingInvitations = append(result.PendingInvitations, inv)
+			}
+		}
+	}
+
+	// Return JSON response
+	jsonBytes, err := json.Marshal(result)
+	if err != nil {
+		return mcp.NewToolResultError("Failed to marshal response"), nil
+	}
+
+	return withNotes(mcp.NewToolResultText(string(jsonBytes)), &notes), nil
+}
+
+func handleInviteAccountUser(ctx context.Context, client *hbapi.Client, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	accountID := req.GetString("account_id", "")
+	if accountID == "" {
+		return mcp.NewToolResultError("account_id is required"), nil
+	}
+
+	email := req.GetString("email", "")
+	if email == "" {
+		return mcp.NewToolResultError("email is required"), nil
+	}
+	if addr, err := mail.ParseAddress(email); err != nil || addr.Address != email {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid email %q", email)), nil
+	}
+
+	params := hbapi.AccountInvitationParams{
+		Email:   email,
+		Role:    req.GetString("role", "Member"),
+		TeamIDs: req.GetIntSlice("team_ids", nil),
+	}
+
+	invitation, err := client.Accounts.CreateInvitation(ctx, accountID, params)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to invite account user: %v", err)), nil
+	}
+
+	// Return JSON response
+	jsonBytes, err := json.Marshal(invitation)
+	if err != nil {
+		return mcp.NewToolResultError("Failed to marshal response"), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonBytes)), nil
+}
+
+func handleRemoveAccountUser(ctx context.Context, client *hbapi.Client, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	accountID := req.GetString("account_id", "")
+	if accountID == "" {
+		return mcp.NewToolResultError("account_id is required"), nil
+	}
+
+	userID, ok := requireID(req.GetArguments(), "user_id")
+	if !ok {
+		return mcp.NewToolResultError("user_id must be a positive integer"), nil
+	}
+
+	if err := client.Accounts.RemoveUser(ctx, accountID, userID); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to remove account user: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("User %d removed from account %s", userID, accountID)), nil
+}
+
+func handleResolveUser(ctx context.Context, client *hbapi.Client, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	email := strings.TrimSpace(req.GetString("email", ""))
+	if email == "" {
+		return mcp.NewToolResultError("email is required"), nil
+	}
+
+	args := req.GetArguments()
+	var scopes []string
+	for _, name := range []string{"project_id", "team_id", "account_id"} {
+		if v, ok := args[name]; ok && v != nil {
+			scopes = append(scopes, name)
+		}
+	}
+	if len(scopes) != 1 {
+		return mcp.NewToolResultError("Pass exactly one of project_id, team_id, or account_id"), nil
+	}
+
+	var users []hbapi.User
+	var where string
+	switch scopes[0] {
+	case "project_id":
+		projectID, ok := requireID(args, "project_id")
+		if !ok {
+			return mcp.NewToolResultError("project_id must be a positive integer"), nil
+		}
+		project, err := client.Projects.Get(ctx, projectID)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to get project: %v", err)), nil
+		}
+		users, where = project.Users, fmt.Sprintf("project %d", projectID)
+	case "team_id":
+		teamID, ok := requireID(args, "team_id")
+		if !ok {
+			return mcp.NewToolResultError("team_id must be a positive integer"), nil
+		}
+		members, err := client.Teams.ListMembers(ctx, teamID)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to list team members: %v", err)), nil
+		}
+		for _, m := range members {
+			users = append(users, hbapi.User{ID: m.ID, Name: m.Name, Email: m.Email})
+		}
+		where = fmt.Sprintf("team %d", teamID)
+	default:
+		accountID := req.GetString("account_id", "")
+		accountUsers, err := client.Accounts.ListUsers(ctx, accountID)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to list account users: %v", err)), nil
+		}
+		for _, u := range accountUsers {
+			users = append(users, hbapi.User{ID: u.ID, Name: u.Name, Email: u.Email})
+		}
+		where = "account " + accountID
+	}
+
+	i := slices.IndexFunc(users, func(u hbapi.User) bool { return strings.EqualFold(u.Email, email) })
+	if i < 0 {
+		return mcp.NewToolResultError(fmt.Sprintf("No user with email %s in %s (%d users checked)", email, where, len(users))), nil
+	}
+
+	// Return JSON response
+	jsonBytes, err := json.Marshal(users[i])
+	if err != nil {
+		return mcp.NewToolResultError("Failed to marshal response"), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonBytes)), nil
+}
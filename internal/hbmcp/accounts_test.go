@@ -0,0 +1,147 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestHandleListAccountUsers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v2/accounts/abc/users":
+			_, _ = w.Write([]byte(`{"results": [{"id": 1, "role": "Owner", "name": "Ada", "email": "ada@example.com"}]}`))
+		case "/v2/accounts/abc/invitations":
+			_, _ = w.Write([]byte(`{"results": [
+				{"id": 2, "email": "new@example.com", "role": "Member", "created_at": "2024-01-01T00:00:00Z"},
+				{"id": 3, "email": "ada@example.com", "role": "Owner", "created_at": "2023-01-01T00:00:00Z", "accepted_at": "2023-01-02T00:00:00Z"}
+			]}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+		}
+	}))
+	defer server.Close()
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"account_id": "abc"}
+	result, err := handleListAccountUsers(context.Background(), client, req)
+	if err != nil || result.IsError {
+		t.Fatalf("unexpected error: %v %s", err, getResultText(result))
+	}
+	var got accountUsers
+	if err := json.Unmarshal([]byte(getResultText(result)), &got); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if len(got.Users) != 1 || got.Users[0].Role != "Owner" {
+		t.Errorf("unexpected users %+v", got.Users)
+	}
+	if len(got.PendingInvitations) != 1 || got.PendingInvitations[0].ID != 2 {
+		t.Errorf("expected only the unaccepted invitation, got %+v", got.PendingInvitations)
+	}
+}
+
+func TestHandleInviteAccountUser(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/v2/accounts/abc/invitations" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		var body map[string]map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("failed to decode request body: %v", err)
+		}
+		inv := body["invitation"]
+		if inv["email"] != "new@example.com" || inv["role"] != "Member" || len(inv["team_ids"].([]any)) != 2 {
+			t.Errorf("unexpected invitation params: %v", inv)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id": 2, "email": "new@example.com", "role": "Member", "team_ids": [4, 5], "created_at": "2024-01-01T00:00:00Z"}`))
+	}))
+	defer server.Close()
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"account_id": "abc", "email": "new@example.com", "team_ids": []any{float64(4), float64(5)}}
+	result, err := handleInviteAccountUser(context.Background(), client, req)
+	if err != nil || result.IsError {
+		t.Fatalf("unexpected error: %v %s", err, getResultText(result))
+	}
+	if !strings.Contains(getResultText(result), `"id":2`) {
+		t.Errorf("expected the invitation, got %s", getResultText(result))
+	}
+
+	req.Params.Arguments = map[string]any{"account_id": "abc", "email": "Ada <ada@example.com>"}
+	result, err = handleInviteAccountUser(context.Background(), client, req)
+	if err != nil || !result.IsError {
+		t.Errorf("expected an error result for a display-name address, got %s", getResultText(result))
+	}
+}
+
+func TestHandleRemoveAccountUser(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "DELETE" || r.URL.Path != "/v2/accounts/abc/users/7" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"account_id": "abc", "user_id": float64(7)}
+	result, err := handleRemoveAccountUser(context.Background(), client, req)
+	if err != nil || result.IsError {
+		t.Fatalf("unexpected error: %v %s", err, getResultText(result))
+	}
+	if got := getResultText(result); got != "User 7 removed from account abc" {
+		t.Errorf("unexpected result %q", got)
+	}
+}
+
+func TestHandleResolveUser(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v2/projects/5":
+			_, _ = w.Write([]byte(`{"id": 5, "name": "Shop", "users": [{"id": 7, "email": "Ada@Example.com", "name": "Ada"}]}`))
+		case "/v2/teams/3/team_members":
+			_, _ = w.Write([]byte(`{"results": [{"id": 8, "name": "Grace", "email": "grace@example.com", "admin": true}]}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+
+	call := func(args map[string]any) *mcp.CallToolResult {
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = args
+		result, err := handleResolveUser(context.Background(), client, req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return result
+	}
+
+	if result := call(map[string]any{"email": "ada@example.com", "project_id": float64(5)}); result.IsError || getResultText(result) != `{"id":7,"email":"Ada@Example.com","name":"Ada"}` {
+		t.Errorf("expected Ada found in the project, got %s", getResultText(result))
+	}
+	if result := call(map[string]any{"email": "grace@example.com", "team_id": float64(3)}); result.IsError || !strings.Contains(getResultText(result), `"id":8`) {
+		t.Errorf("expected Grace found in the team, got %s", getResultText(result))
+	}
+	if result := call(map[string]any{"email": "grace@example.com", "project_id": float64(5)}); !result.IsError || !strings.Contains(getResultText(result), "No user with email grace@example.com in project 5") {
+		t.Errorf("expected no match in the project, got %s", getResultText(result))
+	}
+	if result := call(map[string]any{"email": "ada@example.com", "project_id": float64(5), "team_id": float64(3)}); !result.IsError || !strings.Contains(getResultText(result), "exactly one") {
+		t.Errorf("expected two scopes refused, got %s", getResultText(result))
+	}
+}
@@ -0,0 +1,237 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	defaultAlarmEvalPeriods = 12
+	maxAlarmEvalPeriods     = 100
+	// maxAlarmEvalRange bounds how far back an evaluation queries, since
+	// Insights keeps data for a limited time anyway.
+	maxAlarmEvalRange = 30 * 24 * time.Hour
+)
+
+// alarmOperators are the comparisons an alert_result_count trigger makes
+// between a period's count and its value.
+var alarmOperators = map[string]func(count, value float64) bool{
+	"gt":  func(c, v float64) bool { return c > v },
+	"gte": func(c, v float64) bool { return c >= v },
+	"lt":  func(c, v float64) bool { return c < v },
+	"lte": func(c, v float64) bool { return c <= v },
+	"eq":  func(c, v float64) bool { return c == v },
+	"neq": func(c, v float64) bool { return c != v },
+}
+
+var alarmOperatorSymbols = map[string]string{"gt": ">", "gte": ">=", "lt": "<", "lte": "<=", "eq": "==", "neq": "!="}
+
+// alarmEvaluation is the result of evaluate_alarm_query: how the trigger
+// would have evaluated in each of the last periods, oldest first.
+type alarmEvaluation struct {
+	Query            string         `json:"query"`
+	EvaluationPeriod string         `json:"evaluation_period"`
+	Trigger          string         `json:"trigger"`
+	Verdict          string         `json:"verdict"`
+	Triggered        int            `json:"triggered"`
+	Evaluated        int            `json:"evaluated"`
+	MaxCount         int64          `json:"max_count"`
+	Periods          []alarmPeriod  `json:"periods"`
+	TriggerConfig    map[string]any `json:"trigger_config"`
+}
+
+// alarmPeriod is one evaluation period. A partial period hasn't ended yet,
+// allowing for the lookback lag, and isn't counted in the verdict.
+type alarmPeriod struct {
+	Start     time.Time `json:"start"`
+	Count     int64     `json:"count"`
+	Triggered bool      `json:"triggered"`
+	Partial   bool      `json:"partial,omitempty"`
+}
+
+func handleEvaluateAlarmQuery(ctx context.Context, client *hbapi.Client, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return evaluateAlarmQuery(ctx, client, req, time.Now())
+}
+
+func evaluateAlarmQuery(ctx context.Context, client *hbapi.Client, req mcp.CallToolRequest, now time.Time) (*mcp.CallToolResult, error) {
+	projectID := req.GetInt("project_id", 0)
+	if projectID == 0 {
+		return mcp.NewToolResultError("project_id is required"), nil
+	}
+
+	// Start from the saved alarm, if any, and let arguments override it so
+	// a change can be tried before update_alarm saves it.
+	var alarm hbapi.Alarm
+	if alarmID := req.GetString("alarm_id", ""); alarmID != "" {
+		saved, err := client.Alarms.Get(ctx, projectID, alarmID)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to get alarm: %v", err)), nil
+		}
+		alarm = *saved
+	}
+	if query := req.GetString("query", ""); query != "" {
+		alarm.Query = query
+	}
+	if period := req.GetString("evaluation_period", ""); period != "" {
+		alarm.EvaluationPeriod = period
+	}
+	if lag := req.GetString("lookback_lag", ""); lag != "" {
+		alarm.LookbackLag = lag
+	}
+	if raw := req.GetString("trigger_config", ""); raw != "" {
+		var triggerConfig map[string]any
+		if err := json.Unmarshal([]byte(raw), &triggerConfig); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to parse trigger_config JSON: %v", err)), nil
+		}
+		alarm.TriggerConfig = triggerConfig
+	}
+	if raw := req.GetString("stream_ids", ""); raw != "" {
+		var streamIDs []string
+		if err := json.Unmarshal([]byte(raw), &streamIDs); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to parse stream_ids JSON: %v", err)), nil
+		}
+		alarm.StreamIDs = streamIDs
+	}
+	switch {
+	case alarm.Query == "":
+		return mcp.NewToolResultError("query is required without alarm_id"), nil
+	case alarm.EvaluationPeriod == "":
+		return mcp.NewToolResultError("evaluation_period is required without alarm_id"), nil
+	case alarm.TriggerConfig == nil:
+		return mcp.NewToolResultError("trigger_config is required without alarm_id"), nil
+	}
+
+	period, ok := parseSpan(alarm.EvaluationPeriod)
+	if !ok || period < time.Minute {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid evaluation_period %q: use a span like '5m', '1h', or '1d' (minimum 1m)", alarm.EvaluationPeriod)), nil
+	}
+	var lag time.Duration
+	if alarm.LookbackLag != "" {
+		if lag, ok = parseSpan(alarm.LookbackLag); !ok {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid lookback_lag %q: use a span like '1m' or '0s'", alarm.LookbackLag)), nil
+		}
+	}
+	trigger, describe, errResult := alarmTrigger(alarm.TriggerConfig)
+	if errResult != nil {
+		return errResult, nil
+	}
+	periods := req.GetInt("periods", defaultAlarmEvalPeriods)
+	if periods < 1 || periods > maxAlarmEvalPeriods {
+		return mcp.NewToolResultError(fmt.Sprintf("periods must be between 1 and %d", maxAlarmEvalPeriods)), nil
+	}
+	if time.Duration(periods+1)*period > maxAlarmEvalRange {
+		return mcp.NewToolResultError(fmt.Sprintf("%d periods of %s reach back more than %s; ask for fewer periods", periods, formatSpan(period), formatSpan(maxAlarmEvalRange))), nil
+	}
+
+	// Count per period the way the alarm counts its results, binned so one
+	// query covers every period.
+	query := fmt.Sprintf("%s\n| stats count() as count by bin(%s) as period_start", strings.TrimSpace(alarm.Query), formatSpan(period))
+	now = now.UTC()
+	evalEnd := now.Add(-lag)
+	size := int64(period / time.Second)
+	last := evalEnd.Unix() - ((evalEnd.Unix()%size)+size)%size // start of the period evalEnd falls in
+	first := last - int64(periods)*size
+	response, err := client.Insights.Query(ctx, projectID, hbapi.InsightsQueryRequest{
+		Query:     query,
+		Ts:        fmt.Sprintf("PT%dM", int64(now.Sub(time.Unix(first, 0)).Minutes())+1),
+		StreamIDs: alarm.StreamIDs,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to query insights: %v", err)), nil
+	}
+	if response.Error != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Insights query error: %s", response.Error.Message)), nil
+	}
+
+	var notes toolNotes
+	counts := map[int64]int64{}
+	for _, row := range response.Results {
+		start, ok := insightsTime(row["period_start"])
+		n, isNum := row["count"].(float64)
+		if !ok || !isNum {
+			notes.warnf("skipped a result row without a period_start time and count: %v", row)
+			continue
+		}
+		counts[start.Unix()] += int64(n)
+	}
+
+	result := alarmEvaluation{
+		Query:            query,
+		EvaluationPeriod: formatSpan(period),
+		Trigger:          describe,
+		TriggerConfig:    alarm.TriggerConfig,
+		Periods:          []alarmPeriod{},
+	}
+	for ts := first; ts <= now.Unix(); ts += size {
+		p := alarmPeriod{
+			Start:     time.Unix(ts, 0).UTC(),
+			Count:     counts[ts],
+			Triggered: trigger(float64(counts[ts])),
+			Partial:   ts >= last,
+		}
+		result.Periods = append(result.Periods, p)
+		if p.Partial {
+			continue
+		}
+		result.Evaluated++
+		result.MaxCount = max(result.MaxCount, p.Count)
+		if p.Triggered {
+			result.Triggered++
+		}
+	}
+	result.Verdict = fmt.Sprintf("would have triggered in %d of the last %d periods of %s (%s); the highest count was %d", result.Triggered, result.Evaluated, formatSpan(period), describe, result.MaxCount)
+	if lag > 0 {
+		notes.warnf("periods end %s ago, allowing for the lookback lag", formatSpan(lag))
+	}
+
+	// Return JSON response
+	jsonBytes, err := json.Marshal(result)
+	if err != nil {
+		return mcp.NewToolResultError("Failed to marshal response"), nil
+	}
+
+	return withNotes(mcp.NewToolResultText(string(jsonBytes)), &notes), nil
+}
+
+// alarmTrigger returns the check an alert_result_count trigger config
+// makes on a period's count, with a description such as "count > 10".
+func alarmTrigger(triggerConfig map[string]any) (func(float64) bool, string, *mcp.CallToolResult) {
+	if t, _ := triggerConfig["type"].(string); t != "alert_result_count" {
+		return nil, "", mcp.NewToolResultError(fmt.Sprintf("trigger type %q can't be evaluated; only alert_result_count is supported", t))
+	}
+	config, _ := triggerConfig["config"].(map[string]any)
+	operator, _ := config["operator"].(string)
+	compare, ok := alarmOperators[operator]
+	if !ok {
+		return nil, "", mcp.NewToolResultError(fmt.Sprintf("unsupported trigger operator %q: use gt, gte, lt, lte, eq, or neq", operator))
+	}
+	value, ok := config["value"].(float64)
+	if !ok || math.IsNaN(value) {
+		return nil, "", mcp.NewToolResultError("trigger_config.config.value must be a number")
+	}
+	describe := fmt.Sprintf("count %s %s", alarmOperatorSymbols[operator], formatRate(value))
+	return func(count float64) bool { return compare(count, value) }, describe, nil
+}
+
+// insightsTime reads a timestamp from an Insights result cell, which is an
+// RFC 3339 or "YYYY-MM-DD HH:MM:SS" string in UTC, or Unix seconds.
+func insightsTime(v any) (time.Time, bool) {
+	switch v := v.(type) {
+	case float64:
+		return time.Unix(int64(v), 0).UTC(), true
+	case string:
+		for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999", "2006-01-02 15:04:05 UTC"} {
+			if t, err := time.Parse(layout, v); err == nil {
+				return t.UTC(), true
+			}
+		}
+	}
+	return time.Time{}, false
+}
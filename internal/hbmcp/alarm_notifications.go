@@ -0,0 +1,208 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// alarmNotifications is the result of get_alarm_notifications and
+// update_alarm_notifications: the project's integrations, each marked with
+// whether the alarm notifies it.
+type alarmNotifications struct {
+	ProjectID      int                 `json:"project_id"`
+	AlarmID        string              `json:"alarm_id"`
+	AlarmName      string              `json:"alarm_name"`
+	IntegrationIDs []int               `json:"integration_ids"`
+	Integrations   []alarmNotification `json:"integrations"`
+}
+
+type alarmNotification struct {
+	ID       int    `json:"id"`
+	Type     string `json:"type"`
+	Active   bool   `json:"active"`
+	Notified bool   `json:"notified"`
+}
+
+// alarmChannels is the part of an alarm hbapi.Alarm leaves out: the
+// integrations it notifies when it triggers.
+type alarmChannels struct {
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	IntegrationIDs []int  `json:"integration_ids"`
+}
+
+// RegisterAlarmNotificationTools registers the tools reading and setting
+// which integrations an alarm notifies. They take the raw client factory as
+// well, since hbapi's alarms don't carry their integrations.
+func RegisterAlarmNotificationTools(r *toolRegistrar, clientFor ClientFactory, rawFor RawClientFactory) {
+	// get_alarm_notifications tool
+	r.AddTool(
+		mcp.NewTool("get_alarm_notifications",
+			mcp.WithTitleAnnotation("Get Alarm Notifications"),
+			mcp.WithDescription("List a project's integrations (channels), such as Slack or PagerDuty, each marked with whether an Insights alarm notifies it when it triggers. Use update_alarm_notifications to change them."),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithOutputSchema[alarmNotifications](),
+			mcp.WithNumber("project_id",
+				mcp.Required(),
+				mcp.Description("The ID of the project the alarm belongs to"),
+				mcp.Min(1),
+			),
+			mcp.WithString("alarm_id",
+				mcp.Required(),
+				mcp.Description("The ID of the alarm"),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleGetAlarmNotifications(ctx, clientFor(ctx), rawFor(ctx), req)
+		},
+	)
+
+	// update_alarm_notifications tool
+	r.AddTool(
+		mcp.NewTool("update_alarm_notifications",
+			mcp.WithTitleAnnotation("Update Alarm Notifications"),
+			mcp.WithDescription("Set which of a project's integrations (channels) an Insights alarm notifies when it triggers, replacing the current set; pass an empty array to stop notifying. get_project_integrations lists the IDs, and test_project_integration checks that a channel delivers."),
+			mcp.WithReadOnlyHintAnnotation(false),
+			mcp.WithDestructiveHintAnnotation(true),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithOutputSchema[alarmNotifications](),
+			mcp.WithNumber("project_id",
+				mcp.Required(),
+				mcp.Description("The ID of the project the alarm belongs to"),
+				mcp.Min(1),
+			),
+			mcp.WithString("alarm_id",
+				mcp.Required(),
+				mcp.Description("The ID of the alarm to update"),
+			),
+			mcp.WithArray("integration_ids",
+				mcp.Required(),
+				mcp.Description("IDs of the integrations the alarm should notify; every other integration stops being notified"),
+				mcp.WithNumberItems(mcp.Min(1)),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleUpdateAlarmNotifications(ctx, clientFor(ctx), rawFor(ctx), req)
+		},
+	)
+}
+
+func handleGetAlarmNotifications(ctx context.Context, client *hbapi.Client, raw *RawClient, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	projectID := req.GetInt("project_id", 0)
+	if projectID == 0 {
+		return mcp.NewToolResultError("project_id is required"), nil
+	}
+
+	alarmID := req.GetString("alarm_id", "")
+	if alarmID == "" {
+		return mcp.NewToolResultError("alarm_id is required"), nil
+	}
+
+	var alarm alarmChannels
+	if err := raw.Raw(ctx, http.MethodGet, fmt.Sprintf("/projects/%d/alarms/%s", projectID, alarmID), nil, &alarm); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to get alarm: %v", err)), nil
+	}
+	integrations, err := client.Projects.GetIntegrations(ctx, projectID)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to get project integrations: %v", err)), nil
+	}
+
+	var notes toolNotes
+	return alarmNotificationsResult(projectID, alarm, integrations, &notes), nil
+}
+
+func handleUpdateAlarmNotifications(ctx context.Context, client *hbapi.Client, raw *RawClient, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	projectID := req.GetInt("project_id", 0)
+	if projectID == 0 {
+		return mcp.NewToolResultError("project_id is required"), nil
+	}
+
+	alarmID := req.GetString("alarm_id", "")
+	if alarmID == "" {
+		return mcp.NewToolResultError("alarm_id is required"), nil
+	}
+
+	if _, ok := req.GetArguments()["integration_ids"].([]any); !ok {
+		return mcp.NewToolResultError("integration_ids must be an array of integration IDs"), nil
+	}
+	integrationIDs := req.GetIntSlice("integration_ids", nil)
+	slices.Sort(integrationIDs)
+	integrationIDs = slices.Compact(integrationIDs)
+	if integrationIDs == nil {
+		integrationIDs = []int{}
+	}
+
+	// Check the IDs first so a typo is refused with the choices rather than
+	// as whatever the API makes of an unknown ID.
+	integrations, err := client.Projects.GetIntegrations(ctx, projectID)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to get project integrations: %v", err)), nil
+	}
+	for _, id := range integrationIDs {
+		if !slices.ContainsFunc(integrations, func(in hbapi.ProjectIntegration) bool { return in.ID == id }) {
+			return mcp.NewToolResultError(fmt.Sprintf("project %d has no integration %d; list them with get_project_integrations", projectID, id)), nil
+		}
+	}
+
+	body := map[string]any{"alarm": map[string]any{"integration_ids": integrationIDs}}
+	var alarm alarmChannels
+	if err := raw.Raw(ctx, http.MethodPut, fmt.Sprintf("/projects/%d/alarms/%s", projectID, alarmID), body, &alarm); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to update alarm notifications: %v", err)), nil
+	}
+
+	var notes toolNotes
+	if alarm.ID == "" {
+		// The update answered without the alarm; report what was asked for.
+		alarm = alarmChannels{ID: alarmID, IntegrationIDs: integrationIDs}
+	}
+	for _, in := range integrations {
+		if !in.Active && slices.Contains(integrationIDs, in.ID) {
+			notes.warnf("integration %d is inactive, so the alarm won't notify it until it's turned back on", in.ID)
+		}
+	}
+	return alarmNotificationsResult(projectID, alarm, integrations, &notes), nil
+}
+
+// alarmNotificationsResult marks each of the project's integrations with
+// whether alarm notifies it.
+func alarmNotificationsResult(projectID int, alarm alarmChannels, integrations []hbapi.ProjectIntegration, notes *toolNotes) *mcp.CallToolResult {
+	result := alarmNotifications{
+		ProjectID:      projectID,
+		AlarmID:        alarm.ID,
+		AlarmName:      alarm.Name,
+		IntegrationIDs: alarm.IntegrationIDs,
+		Integrations:   make([]alarmNotification, len(integrations)),
+	}
+	if result.IntegrationIDs == nil {
+		result.IntegrationIDs = []int{}
+	}
+	for i, in := range integrations {
+		result.Integrations[i] = alarmNotification{
+			ID:       in.ID,
+			Type:     in.Type,
+			Active:   in.Active,
+			Notified: slices.Contains(alarm.IntegrationIDs, in.ID),
+		}
+	}
+	if len(result.IntegrationIDs) == 0 {
+		notes.warnf("the alarm notifies no integrations, so it only shows in Honeybadger when it triggers")
+	}
+
+	// Return JSON response
+	jsonBytes, err := json.Marshal(result)
+	if err != nil {
+		return mcp.NewToolResultError("Failed to marshal response")
+	}
+
+	return withNotes(mcp.NewToolResultText(string(jsonBytes)), notes)
+}
@@ -0,0 +1,86 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestAlarmNotifications(t *testing.T) {
+	var updated []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v2/projects/5/integrations":
+			_, _ = w.Write([]byte(`[{"id": 9, "type": "slack", "active": false}, {"id": 10, "type": "pagerduty", "active": true}]`))
+		case r.Method == http.MethodGet && r.URL.Path == "/v2/projects/5/alarms/abc":
+			_, _ = w.Write([]byte(`{"id": "abc", "name": "Errors", "integration_ids": [10]}`))
+		case r.Method == http.MethodPut && r.URL.Path == "/v2/projects/5/alarms/abc":
+			var body map[string]map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			ids, _ := json.Marshal(body["alarm"]["integration_ids"])
+			updated = append(updated, string(ids))
+			_, _ = w.Write([]byte(`{"id": "abc", "name": "Errors", "integration_ids": ` + string(ids) + `}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+	raw := &RawClient{baseURL: server.URL, authToken: "test-token", httpClient: http.DefaultClient}
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"project_id": float64(5), "alarm_id": "abc"}
+	result, err := handleGetAlarmNotifications(context.Background(), client, raw, req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got alarmNotifications
+	if err := json.Unmarshal([]byte(getResultText(result)), &got); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if got.AlarmName != "Errors" || len(got.Integrations) != 2 || got.Integrations[0].Notified || !got.Integrations[1].Notified {
+		t.Errorf("expected only the pagerduty integration notified, got %+v", got)
+	}
+
+	update := func(ids []any) *mcp.CallToolResult {
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = map[string]any{"project_id": float64(5), "alarm_id": "abc", "integration_ids": ids}
+		result, err := handleUpdateAlarmNotifications(context.Background(), client, raw, req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return result
+	}
+
+	result = update([]any{float64(10), float64(9), float64(10)})
+	if result.IsError {
+		t.Fatalf("unexpected error: %s", getResultText(result))
+	}
+	if err := json.Unmarshal([]byte(getResultText(result)), &got); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if strings.Join(updated, " ") != "[9,10]" || !got.Integrations[0].Notified || !got.Integrations[1].Notified {
+		t.Errorf("expected both integrations notified, got %+v after %v", got, updated)
+	}
+	if notes := result.Content[len(result.Content)-1].(mcp.TextContent).Text; !strings.Contains(notes, "integration 9 is inactive") {
+		t.Errorf("expected a warning about the inactive integration, got %s", notes)
+	}
+
+	result = update([]any{})
+	if result.IsError || strings.Join(updated, " ") != "[9,10] []" {
+		t.Errorf("expected an empty array to clear the integrations, got %s after %v", getResultText(result), updated)
+	}
+
+	result = update([]any{float64(11)})
+	if !result.IsError || !strings.Contains(getResultText(result), "project 5 has no integration 11") || len(updated) != 2 {
+		t.Errorf("expected an unknown integration refused before updating, got %s after %v", getResultText(result), updated)
+	}
+}
@@ -18,6 +18,9 @@ func RegisterAlarmTools(r *toolRegistrar, clientFor ClientFactory) {
 			mcp.WithDescription("List all Insights alarms for a Honeybadger project. To interpret alarm configuration, fetch reference topic: alarms (via get_reference)."),
 			mcp.WithReadOnlyHintAnnotation(true),
 			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithOutputSchema[hbapi.AlarmListResponse](),
 			mcp.WithNumber("project_id",
 				mcp.Required(),
 				mcp.Description("The ID of the project to list alarms for"),
@@ -36,6 +39,9 @@ func RegisterAlarmTools(r *toolRegistrar, clientFor ClientFactory) {
 			mcp.WithDescription("Get a single Insights alarm by ID. To interpret alarm configuration, fetch reference topic: alarms (via get_reference)."),
 			mcp.WithReadOnlyHintAnnotation(true),
 			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithOutputSchema[hbapi.Alarm](),
 			mcp.WithNumber("project_id",
 				mcp.Required(),
 				mcp.Description("The ID of the project the alarm belongs to"),
@@ -58,6 +64,8 @@ func RegisterAlarmTools(r *toolRegistrar, clientFor ClientFactory) {
 			mcp.WithDescription("Create a new Insights alarm for a Honeybadger project. IMPORTANT: Requires reference topics: alarms, queries, badgerql — fetch via get_reference first (skip topics still visible in your context) for the trigger_config schema and query guidelines. Verify the query returns the expected results via query_insights before creating the alarm."),
 			mcp.WithReadOnlyHintAnnotation(false),
 			mcp.WithDestructiveHintAnnotation(true),
+			mcp.WithIdempotentHintAnnotation(false),
+			mcp.WithOpenWorldHintAnnotation(false),
 			mcp.WithNumber("project_id",
 				mcp.Required(),
 				mcp.Description("The ID of the project to create the alarm in"),
@@ -102,6 +110,8 @@ func RegisterAlarmTools(r *toolRegistrar, clientFor ClientFactory) {
 			mcp.WithDescription("Update an existing Insights alarm. IMPORTANT: Requires reference topics: alarms, queries, badgerql — fetch via get_reference first (skip topics still visible in your context) for the trigger_config schema and query guidelines."),
 			mcp.WithReadOnlyHintAnnotation(false),
 			mcp.WithDestructiveHintAnnotation(true),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
 			mcp.WithNumber("project_id",
 				mcp.Required(),
 				mcp.Description("The ID of the project the alarm belongs to"),
@@ -150,6 +160,8 @@ func RegisterAlarmTools(r *toolRegistrar, clientFor ClientFactory) {
 			mcp.WithDescription("Delete an Insights alarm."),
 			mcp.WithReadOnlyHintAnnotation(false),
 			mcp.WithDestructiveHintAnnotation(true),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
 			mcp.WithNumber("project_id",
 				mcp.Required(),
 				mcp.Description("The ID of the project the alarm belongs to"),
@@ -165,6 +177,50 @@ func RegisterAlarmTools(r *toolRegistrar, clientFor ClientFactory) {
 		},
 	)
 
+	// evaluate_alarm_query tool
+	r.AddTool(
+		mcp.NewTool("evaluate_alarm_query",
+			mcp.WithTitleAnnotation("Evaluate Alarm Query"),
+			mcp.WithDescription("Dry-run an Insights alarm: count its query's results in each of the last evaluation periods and report whether the trigger would have fired in each, with a one-line verdict. Pass alarm_id to evaluate a saved alarm, and query, evaluation_period, trigger_config, lookback_lag, or stream_ids to try changes to it (or to evaluate a new alarm without alarm_id) before create_alarm or update_alarm saves them. Only alert_result_count triggers can be evaluated. For the trigger_config schema, fetch reference topic: alarms (via get_reference)."),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithOutputSchema[alarmEvaluation](),
+			mcp.WithNumber("project_id",
+				mcp.Required(),
+				mcp.Description("The ID of the project the alarm belongs to"),
+				mcp.Min(1),
+			),
+			mcp.WithString("alarm_id",
+				mcp.Description("The ID of a saved alarm to evaluate; the other arguments override its settings"),
+			),
+			mcp.WithString("query",
+				mcp.Description("BadgerQL query for the alarm, counted per period like the alarm counts it. Required without alarm_id"),
+			),
+			mcp.WithString("evaluation_period",
+				mcp.Description("How often the alarm is evaluated (e.g., 5m, 1h, 1d). Minimum 1m. Required without alarm_id"),
+			),
+			mcp.WithString("trigger_config",
+				mcp.Description("JSON object defining when to trigger the alarm, e.g. {\"type\": \"alert_result_count\", \"config\": {\"operator\": \"gt\", \"value\": 10}}. Required without alarm_id"),
+			),
+			mcp.WithString("lookback_lag",
+				mcp.Description("Delay before evaluating to allow data to arrive (e.g., 1m, or 0s for no lag)"),
+			),
+			mcp.WithString("stream_ids",
+				mcp.Description("Optional JSON array of stream IDs to query"),
+			),
+			mcp.WithNumber("periods",
+				mcp.Description(fmt.Sprintf("How many of the most recent complete periods to evaluate (default %d, max %d, reaching back at most 30 days)", defaultAlarmEvalPeriods, maxAlarmEvalPeriods)),
+				mcp.Min(1),
+				mcp.Max(maxAlarmEvalPeriods),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleEvaluateAlarmQuery(ctx, clientFor(ctx), req)
+		},
+	)
+
 	// get_alarm_history tool
 	r.AddTool(
 		mcp.NewTool("get_alarm_history",
@@ -172,6 +228,8 @@ func RegisterAlarmTools(r *toolRegistrar, clientFor ClientFactory) {
 			mcp.WithDescription("Get the trigger history for an Insights alarm. To interpret trigger records and alarm states, fetch reference topic: alarms (via get_reference)."),
 			mcp.WithReadOnlyHintAnnotation(true),
 			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
 			mcp.WithNumber("project_id",
 				mcp.Required(),
 				mcp.Description("The ID of the project the alarm belongs to"),
@@ -182,7 +240,10 @@ func RegisterAlarmTools(r *toolRegistrar, clientFor ClientFactory) {
 				mcp.Description("The ID of the alarm to get history for"),
 			),
 			mcp.WithNumber("page",
-				mcp.Description("Page number for pagination (default: 0)"),
+				mcp.Description("Page number for pagination (default: 0). Prefer page_token"),
+			),
+			mcp.WithString("page_token",
+				mcp.Description(pageTokenDescription),
 			),
 		),
 		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -410,17 +471,22 @@ func handleGetAlarmHistory(ctx context.Context, client *hbapi.Client, req mcp.Ca
 		return mcp.NewToolResultError("alarm_id is required"), nil
 	}
 
-	page := req.GetInt("page", 0)
+	page, err := pageArg(req)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 
 	response, err := client.Alarms.History(ctx, projectID, alarmID, page)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to get alarm history: %v", err)), nil
 	}
 
+	notes := toolNotes{NextPageToken: nextPageToken(response.Links, "page")}
+
 	jsonBytes, err := json.Marshal(response)
 	if err != nil {
 		return mcp.NewToolResultError("Failed to marshal response"), nil
 	}
 
-	return mcp.NewToolResultText(string(jsonBytes)), nil
+	return withNotes(mcp.NewToolResultText(string(jsonBytes)), &notes), nil
 }
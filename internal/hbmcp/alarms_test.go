@@ -7,6 +7,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	hbapi "github.com/honeybadger-io/api-go"
 	"github.com/mark3labs/mcp-go/mcp"
@@ -474,3 +475,77 @@ func TestHandleCreateAlarm_InvalidTriggerConfigJSON(t *testing.T) {
 		t.Error("Error message should mention failed to parse trigger_config JSON")
 	}
 }
+
+func TestEvaluateAlarmQuery(t *testing.T) {
+	var body map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v2/projects/123/alarms/abc123":
+			_, _ = w.Write([]byte(`{"id": "abc123", "query": "filter level::str == \"error\"", "evaluation_period": "1h", "lookback_lag": "0s", "trigger_config": {"type": "alert_result_count", "config": {"operator": "gt", "value": 10}}}`))
+		case "/v2/projects/123/insights/queries":
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			_, _ = w.Write([]byte(`{"results": [{"period_start": "2024-01-02T09:00:00Z", "count": 12}, {"period_start": "2024-01-02T11:00:00Z", "count": 4}, {"period_start": "2024-01-02 12:00:00", "count": 30}], "meta": {}}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+		}
+	}))
+	defer server.Close()
+
+	client := hbapi.NewClient().
+		WithBaseURL(server.URL).
+		WithAuthToken("test-token")
+	now := time.Date(2024, 1, 2, 12, 30, 0, 0, time.UTC)
+	evaluate := func(args map[string]any) *mcp.CallToolResult {
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = args
+		result, err := evaluateAlarmQuery(context.Background(), client, req, now)
+		if err != nil {
+			t.Fatalf("evaluateAlarmQuery() error = %v", err)
+		}
+		return result
+	}
+
+	result := evaluate(map[string]any{"project_id": 123, "alarm_id": "abc123", "periods": 3})
+	if result.IsError {
+		t.Fatalf("expected successful result, got %s", getResultText(result))
+	}
+	var got alarmEvaluation
+	if err := json.Unmarshal([]byte(getResultText(result)), &got); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(body["query"].(string), "| stats count() as count by bin(1h) as period_start") || body["ts"] != "PT211M" {
+		t.Errorf("unexpected insights request %v", body)
+	}
+	if got.Triggered != 1 || got.Evaluated != 3 || got.MaxCount != 12 || len(got.Periods) != 4 || !got.Periods[3].Partial || got.Periods[3].Count != 30 {
+		t.Errorf("unexpected evaluation %+v", got)
+	}
+	if !strings.Contains(got.Verdict, "1 of the last 3 periods of 1h (count > 10)") {
+		t.Errorf("unexpected verdict %q", got.Verdict)
+	}
+
+	// Overrides try a different threshold without the saved alarm.
+	result = evaluate(map[string]any{"project_id": 123, "query": "fields @ts", "evaluation_period": "1h", "trigger_config": `{"type": "alert_result_count", "config": {"operator": "lte", "value": 5}}`, "periods": 3})
+	if err := json.Unmarshal([]byte(getResultText(result)), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Triggered != 2 || got.Trigger != "count <= 5" {
+		t.Errorf("unexpected evaluation %+v", got)
+	}
+
+	tests := []struct {
+		args    map[string]any
+		wantErr string
+	}{
+		{map[string]any{"project_id": 123, "evaluation_period": "1h"}, "query is required"},
+		{map[string]any{"project_id": 123, "alarm_id": "abc123", "evaluation_period": "30s"}, "invalid evaluation_period"},
+		{map[string]any{"project_id": 123, "alarm_id": "abc123", "trigger_config": `{"type": "alert_result_ratio"}`}, "only alert_result_count"},
+		{map[string]any{"project_id": 123, "alarm_id": "abc123", "trigger_config": `{"type": "alert_result_count", "config": {"operator": "above", "value": 1}}`}, "unsupported trigger operator"},
+		{map[string]any{"project_id": 123, "alarm_id": "abc123", "evaluation_period": "1d", "periods": 60}, "ask for fewer periods"},
+	}
+	for _, tt := range tests {
+		if result := evaluate(tt.args); !result.IsError || !strings.Contains(getResultText(result), tt.wantErr) {
+			t.Errorf("%v: expected error containing %q, got %s", tt.args, tt.wantErr, getResultText(result))
+		}
+	}
+}
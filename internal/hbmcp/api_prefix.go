@@ -0,0 +1,42 @@
+package hbmcp
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/honeybadger-io/honeybadger-mcp-server/internal/config"
+)
+
+// apiPrefixTransport sends API requests to a self-hosted install that
+// serves the Data API under another path or version than /v2. hbapi and
+// RawClient both build URLs as the API URL plus /v2, so the prefix is
+// swapped on the way out; requests to other hosts or paths pass as they
+// are.
+type apiPrefixTransport struct {
+	base http.RoundTripper
+	host string
+	from string
+	to   string
+}
+
+// newAPIPrefixTransport swaps /v2 for prefix under apiURL's path.
+func newAPIPrefixTransport(base http.RoundTripper, apiURL, prefix string) *apiPrefixTransport {
+	t := &apiPrefixTransport{base: base, from: config.DefaultAPIPathPrefix, to: prefix}
+	if u, err := url.Parse(apiURL); err == nil {
+		basePath := strings.TrimRight(u.Path, "/")
+		t.host, t.from, t.to = u.Host, basePath+t.from, basePath+t.to
+	}
+	return t
+}
+
+func (t *apiPrefixTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rest, ok := strings.CutPrefix(req.URL.Path, t.from)
+	if !ok || req.URL.Host != t.host || (rest != "" && !strings.HasPrefix(rest, "/")) {
+		return t.base.RoundTrip(req)
+	}
+	req = req.Clone(req.Context())
+	req.URL.Path = t.to + rest
+	req.URL.RawPath = ""
+	return t.base.RoundTrip(req)
+}
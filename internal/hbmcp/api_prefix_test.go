@@ -0,0 +1,70 @@
+package hbmcp
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/honeybadger-io/honeybadger-mcp-server/internal/config"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func TestAPIPrefixTransport(t *testing.T) {
+	var paths []string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"results": [], "links": {}}`))
+	}))
+	defer api.Close()
+
+	cfg := reloadTestConfig()
+	cfg.APIURL = api.URL + "/honeybadger"
+	cfg.APIPathPrefix = "/api/v1"
+	clientFor, rawFor := NewClientFactories(cfg, slog.New(slog.DiscardHandler))
+	ctx := context.Background()
+	if _, err := clientFor(ctx).Projects.ListAll(ctx); err != nil {
+		t.Fatalf("ListAll() error = %v", err)
+	}
+	if err := rawFor(ctx).Raw(ctx, http.MethodGet, "/projects/1/v2", nil, nil); err != nil {
+		t.Fatalf("Raw() error = %v", err)
+	}
+	want := []string{"/honeybadger/api/v1/projects", "/honeybadger/api/v1/projects/1/v2"}
+	if len(paths) != len(want) || paths[0] != want[0] || paths[1] != want[1] {
+		t.Errorf("paths = %v, want %v", paths, want)
+	}
+}
+
+func TestAPIPrefixTransport_OtherPaths(t *testing.T) {
+	var got string
+	base := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		got = r.URL.String()
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: r}, nil
+	})
+	transport := newAPIPrefixTransport(base, "https://honeybadger.internal", "/api/v2")
+	for url, want := range map[string]string{
+		"https://honeybadger.internal/v2/faults": "https://honeybadger.internal/api/v2/faults",
+		"https://honeybadger.internal/v2":        "https://honeybadger.internal/api/v2",
+		"https://honeybadger.internal/v20/x":     "https://honeybadger.internal/v20/x",
+		"https://elsewhere.example/v2/faults":    "https://elsewhere.example/v2/faults",
+	} {
+		req, _ := http.NewRequest(http.MethodGet, url, nil)
+		if _, err := transport.RoundTrip(req); err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("%s: sent %s, want %s", url, got, want)
+		}
+		if req.URL.String() != url {
+			t.Errorf("%s: the caller's request was changed to %s", url, req.URL)
+		}
+	}
+	if config.DefaultAPIPathPrefix != "/v2" {
+		t.Errorf("DefaultAPIPathPrefix = %q, want hbapi's /v2", config.DefaultAPIPathPrefix)
+	}
+}
@@ -0,0 +1,144 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// secretArgPattern matches argument names whose values never reach the
+// audit log, at any nesting depth. Webhook URLs, like Slack's, carry their
+// secret in the URL itself.
+var secretArgPattern = regexp.MustCompile(`(?i)token|secret|password|passwd|api_?key|auth|credential|private|webhook|hook_?url`)
+
+const redacted = "[REDACTED]"
+
+// auditRecord is one JSON line of the audit log.
+type auditRecord struct {
+	Time          time.Time      `json:"time"`
+	SessionID     string         `json:"session_id,omitempty"`
+	CorrelationID string         `json:"correlation_id,omitempty"`
+	Tool          string         `json:"tool"`
+	Arguments     map[string]any `json:"arguments"`
+	DurationMS    int64          `json:"duration_ms"`
+	ResultBytes   int            `json:"result_bytes"`
+	IsError       bool           `json:"is_error"`
+	Error         string         `json:"error,omitempty"`
+}
+
+// auditLog appends one JSON line per tool call. Writes are serialized so
+// concurrent calls in http mode can't interleave lines.
+type auditLog struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// openAuditLog opens path for appending. The file stays open for the life
+// of the process; each record is a single unbuffered write.
+func openAuditLog(path string) (*auditLog, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	return &auditLog{w: f}, nil
+}
+
+func (a *auditLog) write(rec auditRecord) error {
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	_, err = a.w.Write(append(line, '\n'))
+	return err
+}
+
+// middleware records every tool call after it completes. A call whose
+// record can't be written still returns its result, but as an error so the
+// failure is visible; the action itself has already happened.
+func (a *auditLog) middleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		start := time.Now()
+		result, err := next(ctx, req)
+
+		rec := auditRecord{
+			Time:          start.UTC(),
+			Tool:          req.Params.Name,
+			Arguments:     redactArgs(req.GetArguments()),
+			DurationMS:    time.Since(start).Milliseconds(),
+			CorrelationID: correlationID(ctx),
+		}
+		if session := server.ClientSessionFromContext(ctx); session != nil {
+			rec.SessionID = session.SessionID()
+		}
+		if err != nil {
+			rec.IsError = true
+			rec.Error = err.Error()
+		}
+		if result != nil {
+			rec.IsError = rec.IsError || result.IsError
+			rec.ResultBytes = resultSize(result)
+		}
+
+		if werr := a.write(rec); werr != nil {
+			return nil, errors.Join(err, errors.New("failed to write audit log: "+werr.Error()))
+		}
+		return result, err
+	}
+}
+
+// failClosed stands in for the audit middleware when the log can't be
+// opened: no tool runs unaudited.
+func failClosed(openErr error) server.ToolHandlerMiddleware {
+	return func(server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return mcp.NewToolResultError("Tool calls are disabled: the audit log could not be opened: " + openErr.Error()), nil
+		}
+	}
+}
+
+func redactArgs(args map[string]any) map[string]any {
+	out := make(map[string]any, len(args))
+	for k, v := range args {
+		if secretArgPattern.MatchString(k) {
+			out[k] = redacted
+			continue
+		}
+		out[k] = redactValue(v)
+	}
+	return out
+}
+
+func redactValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		return redactArgs(v)
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = redactValue(item)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+func resultSize(result *mcp.CallToolResult) int {
+	size := 0
+	for _, c := range result.Content {
+		if text, ok := c.(mcp.TextContent); ok {
+			size += len(text.Text)
+		}
+	}
+	return size
+}
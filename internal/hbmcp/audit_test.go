@@ -0,0 +1,105 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/honeybadger-io/honeybadger-mcp-server/internal/config"
+)
+
+func TestAuditLog(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"results": [{"id": 1, "name": "Web", "token": "secret-key"}], "links": {}}`))
+	}))
+	defer api.Close()
+
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	cfg := &config.Config{
+		AuthToken:     "test-token",
+		APIURL:        api.URL,
+		LogLevel:      "error",
+		TransportMode: config.TransportStdio,
+		AuditLogPath:  path,
+	}
+	s := NewServer(cfg, "test")
+
+	for _, msg := range []string{
+		`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"find_project_by_token","arguments":{"token":"secret-key"}}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"get_project","arguments":{}}}`,
+	} {
+		s.HandleMessage(context.Background(), []byte(msg))
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read audit log: %v", err)
+	}
+	if strings.Contains(string(data), "secret-key") {
+		t.Errorf("audit log leaked a secret argument:\n%s", data)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 audit records, got %d:\n%s", len(lines), data)
+	}
+	var first, second auditRecord
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &second); err != nil {
+		t.Fatal(err)
+	}
+
+	if first.Tool != "find_project_by_token" || first.Arguments["token"] != redacted {
+		t.Errorf("unexpected first record %+v", first)
+	}
+	if first.IsError || first.ResultBytes == 0 {
+		t.Errorf("expected successful call with a result size, got %+v", first)
+	}
+	if second.Tool != "get_project" || !second.IsError {
+		t.Errorf("expected failed get_project record, got %+v", second)
+	}
+}
+
+func TestAuditLog_FailsClosed(t *testing.T) {
+	cfg := &config.Config{
+		AuthToken:     "test-token",
+		APIURL:        "https://api.honeybadger.io",
+		LogLevel:      "error",
+		TransportMode: config.TransportStdio,
+		AuditLogPath:  filepath.Join(t.TempDir(), "missing", "audit.jsonl"),
+	}
+	s := NewServer(cfg, "test")
+
+	resp := s.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"search_tools","arguments":{"query":"fault"}}}`))
+	respBytes, _ := json.Marshal(resp)
+	if !strings.Contains(string(respBytes), "audit log could not be opened") {
+		t.Errorf("expected tool calls to be refused, got %s", respBytes)
+	}
+}
+
+func TestRedactArgs(t *testing.T) {
+	args := map[string]any{
+		"project_id": 1,
+		"api_key":    "k",
+		"config": map[string]any{
+			"Password": "p",
+			"items":    []any{map[string]any{"auth_token": "t", "name": "x"}},
+			"hook_url": "https://example.com/hook/abc",
+		},
+		"slack_webhook_url": "https://hooks.slack.com/services/T0/B0/secret",
+		"source_url":        "https://example.com/app.js",
+	}
+	got, _ := json.Marshal(redactArgs(args))
+	want := `{"api_key":"[REDACTED]","config":{"Password":"[REDACTED]","hook_url":"[REDACTED]","items":[{"auth_token":"[REDACTED]","name":"x"}]},"project_id":1,"slack_webhook_url":"[REDACTED]","source_url":"https://example.com/app.js"}`
+	if string(got) != want {
+		t.Errorf("redactArgs() = %s, want %s", got, want)
+	}
+}
@@ -1,6 +1,10 @@
 package hbmcp
 
-import "context"
+import (
+	"context"
+
+	"github.com/honeybadger-io/honeybadger-mcp-server/internal/config"
+)
 
 type authTokenKey struct{}
 type claimsKey struct{}
@@ -30,3 +34,20 @@ func ClaimsFromContext(ctx context.Context) *Claims {
 	v, _ := ctx.Value(claimsKey{}).(*Claims)
 	return v
 }
+
+type stdioCallKey struct{}
+
+// WithStdioTransport marks ctx as a call that arrived over stdio, so a
+// server serving http and stdio at once (--stdio) treats it as stdio.
+func WithStdioTransport(ctx context.Context) context.Context {
+	return context.WithValue(ctx, stdioCallKey{}, true)
+}
+
+// CallTransport returns the transport a call arrived over: stdio for calls
+// marked by WithStdioTransport, and otherwise the configured one.
+func CallTransport(ctx context.Context, cfg *config.Config) string {
+	if stdio, _ := ctx.Value(stdioCallKey{}).(bool); stdio {
+		return config.TransportStdio
+	}
+	return cfg.TransportMode
+}
@@ -0,0 +1,265 @@
+package hbmcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	defaultSourceContextLines = 5
+	maxSourceContextLines     = 50
+
+	// maxSourceFileBytes caps how much of a file fetch_content reads.
+	maxSourceFileBytes = 2 << 20
+)
+
+// backtraceSource is the result of resolve_backtrace_source.
+type backtraceSource struct {
+	ProjectID  int              `json:"project_id"`
+	FaultID    int              `json:"fault_id"`
+	NoticeID   string           `json:"notice_id"`
+	FrameIndex int              `json:"frame_index"`
+	Frame      backtraceFrame   `json:"frame"`
+	Revision   string           `json:"revision,omitempty"`
+	SourceURL  string           `json:"source_url"`
+	RawURL     string           `json:"raw_url,omitempty"`
+	Source     []sourceLineText `json:"source,omitempty"`
+}
+
+type backtraceFrame struct {
+	File   string `json:"file"`
+	Path   string `json:"path"`
+	Line   int    `json:"line"`
+	Method string `json:"method,omitempty"`
+}
+
+type sourceLineText struct {
+	Line int    `json:"line"`
+	Code string `json:"code"`
+}
+
+// RegisterBacktraceSourceTools registers the resolve_backtrace_source tool.
+// fetch is the client public source files are fetched with.
+func RegisterBacktraceSourceTools(r *toolRegistrar, clientFor ClientFactory, fetch *http.Client) {
+	// resolve_backtrace_source tool
+	r.AddTool(
+		mcp.NewTool("resolve_backtrace_source",
+			mcp.WithTitleAnnotation("Resolve Backtrace Source"),
+			mcp.WithDescription("Turn a frame of a fault's latest backtrace into a link to the code, by filling the project's source URL template (set in its settings, e.g. 'https://github.com/acme/shop/blob/[sha]/[file]#L[line]') with the notice's revision, the frame's file, and its line. With fetch_content, also returns the lines around the frame from public GitHub, GitLab, or Bitbucket repositories."),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(true),
+			mcp.WithNumber("project_id",
+				mcp.Required(),
+				mcp.Description("The ID of the project containing the fault"),
+				mcp.Min(1),
+			),
+			mcp.WithNumber("fault_id",
+				mcp.Required(),
+				mcp.Description("The ID of the fault"),
+				mcp.Min(1),
+			),
+			mcp.WithNumber("frame_index",
+				mcp.Description("Index of the frame in the latest notice's backtrace, 0 being where the error was raised (default 0)"),
+				mcp.Min(0),
+			),
+			mcp.WithBoolean("fetch_content",
+				mcp.Description("Also fetch the lines around the frame, if the repository is public on GitHub, GitLab, or Bitbucket (default false)"),
+			),
+			mcp.WithNumber("context_lines",
+				mcp.Description(fmt.Sprintf("With fetch_content, how many lines to return on each side of the frame's line (default %d, max %d)", defaultSourceContextLines, maxSourceContextLines)),
+				mcp.Min(0),
+				mcp.Max(maxSourceContextLines),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleResolveBacktraceSource(ctx, clientFor(ctx), fetch, req)
+		},
+	)
+}
+
+func handleResolveBacktraceSource(ctx context.Context, client *hbapi.Client, fetch *http.Client, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	projectID := req.GetInt("project_id", 0)
+	if projectID == 0 {
+		return mcp.NewToolResultError("project_id is required"), nil
+	}
+
+	faultID := req.GetInt("fault_id", 0)
+	if faultID == 0 {
+		return mcp.NewToolResultError("fault_id is required"), nil
+	}
+
+	frameIndex := req.GetInt("frame_index", 0)
+	contextLines := req.GetInt("context_lines", defaultSourceContextLines)
+	if frameIndex < 0 || contextLines < 0 {
+		return mcp.NewToolResultError("frame_index and context_lines must not be negative"), nil
+	}
+	contextLines = min(contextLines, maxSourceContextLines)
+
+	// hbapi's Project type leaves out source_url, so it's read from the
+	// response body the API client's transport keeps.
+	raw := &rawBody{}
+	if _, err := client.Projects.Get(context.WithValue(ctx, rawBodyKey{}, raw), projectID); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to get project: %v", err)), nil
+	}
+	var settings struct {
+		SourceURL string `json:"source_url"`
+	}
+	if body, ok := raw.load(); ok {
+		_ = json.Unmarshal(body, &settings)
+	}
+	if settings.SourceURL == "" {
+		return mcp.NewToolResultError("The project has no source URL template; set one with update_project's source_url, e.g. 'https://github.com/acme/shop/blob/[sha]/[file]#L[line]'"), nil
+	}
+
+	notices, err := client.Faults.ListNotices(ctx, projectID, faultID, hbapi.FaultListNoticesOptions{Limit: 1})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to list fault notices: %v", err)), nil
+	}
+	if len(notices.Results) == 0 {
+		return mcp.NewToolResultError("No notices found for this fault"), nil
+	}
+	notice := notices.Results[0]
+	if frameIndex >= len(notice.Backtrace) {
+		return mcp.NewToolResultError(fmt.Sprintf("frame_index %d is out of range; the latest notice's backtrace has %d frames", frameIndex, len(notice.Backtrace))), nil
+	}
+	entry := notice.Backtrace[frameIndex]
+
+	path, ok := repositoryPath(entry.File)
+	if !ok {
+		return mcp.NewToolResultError(fmt.Sprintf("Frame %d is in %s, outside the project, so it isn't in the repository", frameIndex, entry.File)), nil
+	}
+	result := backtraceSource{
+		ProjectID:  projectID,
+		FaultID:    faultID,
+		NoticeID:   notice.ID,
+		FrameIndex: frameIndex,
+		Frame:      backtraceFrame{File: entry.File, Path: path, Line: int(entry.Number), Method: entry.Method},
+	}
+	if notice.Environment.Revision != nil {
+		result.Revision = *notice.Environment.Revision
+	}
+	if result.Revision == "" && strings.Contains(settings.SourceURL, "[sha]") {
+		return mcp.NewToolResultError("The notice has no revision to fill the template's [sha] with; configure the notifier to report the deployed revision"), nil
+	}
+	result.SourceURL = strings.NewReplacer(
+		"[sha]", result.Revision,
+		"[file]", path,
+		"[line]", strconv.Itoa(result.Frame.Line),
+	).Replace(settings.SourceURL)
+
+	var notes toolNotes
+	if req.GetBool("fetch_content", false) {
+		rawURL, ok := rawSourceURL(result.SourceURL)
+		if !ok {
+			notes.warnf("content can only be fetched from GitHub, GitLab, or Bitbucket blob URLs")
+		} else if lines, err := fetchSourceLines(ctx, fetch, rawURL, result.Frame.Line, contextLines); err != nil {
+			notes.warnf("couldn't fetch the file, which is expected for private repositories: %v", err)
+		} else {
+			result.RawURL = rawURL
+			result.Source = lines
+		}
+	}
+
+	// Return JSON response
+	jsonBytes, err := json.Marshal(result)
+	if err != nil {
+		return mcp.NewToolResultError("Failed to marshal response"), nil
+	}
+
+	return withNotes(mcp.NewToolResultText(string(jsonBytes)), &notes), nil
+}
+
+// repositoryPath returns a backtrace file's path in the repository, or
+// false for files outside the project such as [GEM_ROOT] frames.
+func repositoryPath(file string) (string, bool) {
+	for _, prefix := range []string{"[PROJECT_ROOT]/", "webpack:///"} {
+		if rest, ok := strings.CutPrefix(file, prefix); ok {
+			return strings.TrimPrefix(rest, "./"), true
+		}
+	}
+	if strings.HasPrefix(file, "[") {
+		return "", false
+	}
+	return strings.TrimPrefix(file, "./"), true
+}
+
+// rawSourceURL maps a link to a file on a hosted repository to the URL
+// of its plain contents, or false for hosts it doesn't know.
+func rawSourceURL(sourceURL string) (string, bool) {
+	u, err := url.Parse(sourceURL)
+	if err != nil || u.Scheme != "https" {
+		return "", false
+	}
+	u.Fragment = ""
+	parts := strings.SplitN(strings.TrimPrefix(u.Path, "/"), "/", 4)
+	switch u.Host {
+	case "github.com":
+		// /owner/repo/blob/sha/path
+		if len(parts) < 4 || parts[2] != "blob" {
+			return "", false
+		}
+		u.Host = "raw.githubusercontent.com"
+		u.Path = "/" + parts[0] + "/" + parts[1] + "/" + parts[3]
+	case "gitlab.com":
+		// /group/project/-/blob/sha/path, with nested groups
+		if !strings.Contains(u.Path, "/-/blob/") {
+			return "", false
+		}
+		u.Path = strings.Replace(u.Path, "/-/blob/", "/-/raw/", 1)
+	case "bitbucket.org":
+		// /owner/repo/src/sha/path
+		if len(parts) < 4 || parts[2] != "src" {
+			return "", false
+		}
+		u.Path = "/" + parts[0] + "/" + parts[1] + "/raw/" + parts[3]
+	default:
+		return "", false
+	}
+	u.RawQuery = ""
+	return u.String(), true
+}
+
+// fetchSourceLines fetches rawURL and returns the lines within around of
+// line.
+func fetchSourceLines(ctx context.Context, client *http.Client, rawURL string, line, around int) ([]sourceLineText, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned %s", rawURL, resp.Status)
+	}
+
+	var lines []sourceLineText
+	scanner := bufio.NewScanner(io.LimitReader(resp.Body, maxSourceFileBytes))
+	scanner.Buffer(make([]byte, 0, 64<<10), maxSourceFileBytes)
+	for n := 1; scanner.Scan() && n <= line+around; n++ {
+		if n >= line-around {
+			lines = append(lines, sourceLineText{Line: n, Code: scanner.Text()})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("the file has fewer than %d lines", line)
+	}
+	return lines, nil
+}
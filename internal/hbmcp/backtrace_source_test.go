@@ -0,0 +1,172 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// redirectTransport sends every request to one test server, keeping the
+// original host in a header so handlers can tell them apart.
+type redirectTransport struct {
+	target *url.URL
+}
+
+func (t *redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("X-Original-Host", req.URL.Host)
+	req.URL.Scheme, req.URL.Host = t.target.Scheme, t.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func backtraceSourceServer(t *testing.T, sourceURL, revision string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Header.Get("X-Original-Host") == "raw.githubusercontent.com":
+			if r.URL.Path != "/acme/shop/abc123/app/models/order.rb" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			for i := 1; i <= 20; i++ {
+				_, _ = fmt.Fprintf(w, "line %d\n", i)
+			}
+		case r.URL.Path == "/v2/projects/1":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = fmt.Fprintf(w, `{"id": 1, "name": "Shop", "source_url": %q}`, sourceURL)
+		case r.URL.Path == "/v2/projects/1/faults/2/notices":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = fmt.Fprintf(w, `{"results": [{"id": "n1", "environment": {"revision": %s}, "backtrace": [
+				{"number": "10", "file": "[PROJECT_ROOT]/app/models/order.rb", "method": "total"},
+				{"number": "87", "file": "[GEM_ROOT]/gems/rack-3.0/lib/rack.rb", "method": "call"}
+			]}], "links": {}}`, revision)
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func backtraceSourceClients(server *httptest.Server) (*hbapi.Client, *http.Client) {
+	target, _ := url.Parse(server.URL)
+	client := hbapi.NewClient().
+		WithBaseURL(server.URL).
+		WithAuthToken("test-token").
+		WithHTTPClient(&http.Client{Transport: &rawBodyTransport{base: http.DefaultTransport}})
+	return client, &http.Client{Transport: &redirectTransport{target: target}}
+}
+
+func TestHandleResolveBacktraceSource(t *testing.T) {
+	server := backtraceSourceServer(t, "https://github.com/acme/shop/blob/[sha]/[file]#L[line]", `"abc123"`)
+	defer server.Close()
+	client, fetch := backtraceSourceClients(server)
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"project_id": float64(1), "fault_id": float64(2), "fetch_content": true, "context_lines": float64(2)}
+	result, err := handleResolveBacktraceSource(context.Background(), client, fetch, req)
+	if err != nil || result.IsError {
+		t.Fatalf("unexpected error: %v %s", err, getResultText(result))
+	}
+
+	var source backtraceSource
+	if err := json.Unmarshal([]byte(getResultText(result)), &source); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if source.SourceURL != "https://github.com/acme/shop/blob/abc123/app/models/order.rb#L10" {
+		t.Errorf("unexpected source_url %q", source.SourceURL)
+	}
+	if source.RawURL != "https://raw.githubusercontent.com/acme/shop/abc123/app/models/order.rb" {
+		t.Errorf("unexpected raw_url %q", source.RawURL)
+	}
+	if source.Frame.Path != "app/models/order.rb" || source.Frame.Line != 10 || source.Revision != "abc123" {
+		t.Errorf("unexpected frame: %+v", source)
+	}
+	if len(source.Source) != 5 || source.Source[0] != (sourceLineText{Line: 8, Code: "line 8"}) || source.Source[4].Line != 12 {
+		t.Errorf("expected lines 8 to 12, got %+v", source.Source)
+	}
+}
+
+func TestHandleResolveBacktraceSource_Errors(t *testing.T) {
+	tests := []struct {
+		name      string
+		sourceURL string
+		revision  string
+		args      map[string]any
+		want      string
+	}{
+		{"no template", "", `"abc123"`, nil, "no source URL template"},
+		{"no revision", "https://github.com/acme/shop/blob/[sha]/[file]#L[line]", `null`, nil, "no revision"},
+		{"outside the project", "https://github.com/acme/shop/blob/[sha]/[file]", `"abc123"`, map[string]any{"frame_index": float64(1)}, "outside the project"},
+		{"out of range", "https://github.com/acme/shop/blob/[sha]/[file]", `"abc123"`, map[string]any{"frame_index": float64(5)}, "has 2 frames"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := backtraceSourceServer(t, tt.sourceURL, tt.revision)
+			defer server.Close()
+			client, fetch := backtraceSourceClients(server)
+
+			req := mcp.CallToolRequest{}
+			req.Params.Arguments = map[string]any{"project_id": float64(1), "fault_id": float64(2)}
+			for k, v := range tt.args {
+				req.Params.Arguments.(map[string]any)[k] = v
+			}
+			result, _ := handleResolveBacktraceSource(context.Background(), client, fetch, req)
+			if !result.IsError || !strings.Contains(getResultText(result), tt.want) {
+				t.Errorf("expected error containing %q, got %q", tt.want, getResultText(result))
+			}
+		})
+	}
+}
+
+func TestHandleResolveBacktraceSource_FetchWarnings(t *testing.T) {
+	tests := []struct {
+		name      string
+		sourceURL string
+		want      string
+	}{
+		{"private repository", "https://github.com/acme/private/blob/[sha]/[file]#L[line]", "expected for private repositories"},
+		{"unknown host", "https://git.example.com/shop/[sha]/[file]", "only be fetched from GitHub, GitLab, or Bitbucket"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := backtraceSourceServer(t, tt.sourceURL, `"abc123"`)
+			defer server.Close()
+			client, fetch := backtraceSourceClients(server)
+
+			req := mcp.CallToolRequest{}
+			req.Params.Arguments = map[string]any{"project_id": float64(1), "fault_id": float64(2), "fetch_content": true}
+			result, _ := handleResolveBacktraceSource(context.Background(), client, fetch, req)
+			if result.IsError {
+				t.Fatalf("expected the link without content, got %q", getResultText(result))
+			}
+			if notes := getResultNotes(t, result); len(notes.Warnings) != 1 || !strings.Contains(notes.Warnings[0], tt.want) {
+				t.Errorf("expected a warning containing %q, got %v", tt.want, notes.Warnings)
+			}
+		})
+	}
+}
+
+func TestRawSourceURL(t *testing.T) {
+	tests := map[string]string{
+		"https://github.com/acme/shop/blob/abc/app/x.rb#L3":            "https://raw.githubusercontent.com/acme/shop/abc/app/x.rb",
+		"https://gitlab.com/acme/team/shop/-/blob/abc/app/x.rb#L3":     "https://gitlab.com/acme/team/shop/-/raw/abc/app/x.rb",
+		"https://bitbucket.org/acme/shop/src/abc/app/x.rb#lines-3":     "https://bitbucket.org/acme/shop/raw/abc/app/x.rb",
+		"https://github.com/acme/shop/tree/abc/app":                    "",
+		"http://github.com/acme/shop/blob/abc/app/x.rb":                "",
+		"https://sourcegraph.example.com/acme/shop/-/blob/app/x.rb?L3": "",
+	}
+	for in, want := range tests {
+		got, ok := rawSourceURL(in)
+		if got != want || ok != (want != "") {
+			t.Errorf("rawSourceURL(%q) = %q, %v; want %q", in, got, ok, want)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// errBudgetExhausted is returned by budgetTransport for requests past the
+// session's API call budget.
+var errBudgetExhausted = errors.New("session API call budget exhausted")
+
+// sessionBudgets implements --session-max-api-calls and
+// --session-max-rows: each session may make so many Honeybadger API
+// requests and get so many result rows back through tool calls, after
+// which its calls are refused. It guards rate limits against an agent
+// stuck paging or retrying in a loop. A limit of zero is no limit.
+type sessionBudgets struct {
+	maxCalls int
+	maxRows  int
+
+	mu        sync.Mutex
+	bySession map[string]*sessionBudget
+}
+
+// sessionBudget is what one session has used.
+type sessionBudget struct {
+	mu    sync.Mutex
+	calls int
+	rows  int
+}
+
+// budgetCall is a tool call's view of its session's budget, carried in
+// the context for budgetTransport.
+type budgetCall struct {
+	budget   *sessionBudget
+	maxCalls int
+	refused  atomic.Bool
+}
+
+type budgetCallKey struct{}
+
+func newSessionBudgets(maxCalls, maxRows int) *sessionBudgets {
+	return &sessionBudgets{maxCalls: maxCalls, maxRows: maxRows, bySession: map[string]*sessionBudget{}}
+}
+
+func (b *sessionBudgets) session(ctx context.Context) *sessionBudget {
+	sessionID := sessionIDFromContext(ctx)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	budget, ok := b.bySession[sessionID]
+	if !ok {
+		budget = &sessionBudget{}
+		b.bySession[sessionID] = budget
+	}
+	return budget
+}
+
+// middleware refuses calls once the session's budget is used up, and
+// counts the rows each call returns. It puts the session's budget in the
+// context so budgetTransport can count, and stop, the call's API requests.
+func (b *sessionBudgets) middleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if b.maxCalls == 0 && b.maxRows == 0 {
+			return next(ctx, req)
+		}
+		budget := b.session(ctx)
+		if result := b.exhausted(budget); result != nil {
+			return result, nil
+		}
+
+		call := &budgetCall{budget: budget, maxCalls: b.maxCalls}
+		result, err := next(context.WithValue(ctx, budgetCallKey{}, call), req)
+		if call.refused.Load() {
+			// The handler's own error would only say a request failed.
+			return b.exhausted(budget), nil
+		}
+		if err == nil && result != nil && !result.IsError {
+			budget.mu.Lock()
+			budget.rows += resultRowCount(result)
+			budget.mu.Unlock()
+		}
+		return result, err
+	}
+}
+
+// exhausted returns the result refusing a call when budget is used up,
+// or nil while it isn't. Besides the message it carries
+// {"error": "budget_exhausted", ...} as structured content.
+func (b *sessionBudgets) exhausted(budget *sessionBudget) *mcp.CallToolResult {
+	budget.mu.Lock()
+	defer budget.mu.Unlock()
+	limit, used, max := "", 0, 0
+	switch {
+	case b.maxCalls > 0 && budget.calls >= b.maxCalls:
+		limit, used, max = "api_calls", budget.calls, b.maxCalls
+	case b.maxRows > 0 && budget.rows >= b.maxRows:
+		limit, used, max = "rows", budget.rows, b.maxRows
+	default:
+		return nil
+	}
+	result := mcp.NewToolResultError(fmt.Sprintf("Session budget exhausted: %d of %d %s used. Stop making tool calls and report what you have found so far; the budget resets in a new session.", used, max, budgetUnits[limit]))
+	result.StructuredContent = map[string]any{
+		"error": "budget_exhausted",
+		"limit": limit,
+		"used":  used,
+		"max":   max,
+	}
+	return result
+}
+
+var budgetUnits = map[string]string{"api_calls": "API calls", "rows": "rows"}
+
+// take counts one API request against the budget, or reports false when
+// the budget has none left.
+func (c *budgetCall) take() bool {
+	c.budget.mu.Lock()
+	defer c.budget.mu.Unlock()
+	if c.maxCalls > 0 && c.budget.calls >= c.maxCalls {
+		c.refused.Store(true)
+		return false
+	}
+	c.budget.calls++
+	return true
+}
+
+// forget drops the budget of a session that has gone away.
+func (b *sessionBudgets) forget(sessionID string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.bySession, sessionID)
+}
+
+// resultRowCount counts the rows in a tool result: the elements of a JSON
+// array, or of the results array of a JSON object. Other results count as
+// none.
+func resultRowCount(result *mcp.CallToolResult) int {
+	if len(result.Content) == 0 {
+		return 0
+	}
+	text, ok := result.Content[0].(mcp.TextContent)
+	if !ok {
+		return 0
+	}
+	var rows []json.RawMessage
+	if json.Unmarshal([]byte(text.Text), &rows) == nil {
+		return len(rows)
+	}
+	var page struct {
+		Results []json.RawMessage `json:"results"`
+	}
+	if json.Unmarshal([]byte(text.Text), &page) == nil {
+		return len(page.Results)
+	}
+	return 0
+}
+
+// budgetTransport counts each API request made during a tool call against
+// the session budget the call's context carries, retries included, and
+// fails requests past it without sending them.
+type budgetTransport struct {
+	base http.RoundTripper
+}
+
+func (t *budgetTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if call, _ := req.Context().Value(budgetCallKey{}).(*budgetCall); call != nil && !call.take() {
+		return nil, errBudgetExhausted
+	}
+	return t.base.RoundTrip(req)
+}
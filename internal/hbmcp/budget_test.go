@@ -0,0 +1,71 @@
+package hbmcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestSessionBudgets_APICalls(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	client := &http.Client{Transport: &budgetTransport{base: http.DefaultTransport}}
+
+	// Each call makes two API requests.
+	b := newSessionBudgets(3, 0)
+	handler := b.middleware(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		for range 2 {
+			httpReq, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+			resp, err := client.Do(httpReq)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			_ = resp.Body.Close()
+		}
+		return mcp.NewToolResultText("ok"), nil
+	})
+
+	if result, _ := handler(context.Background(), mcp.CallToolRequest{}); result.IsError {
+		t.Fatalf("expected the first call to succeed, got %s", getResultText(result))
+	}
+	result, _ := handler(context.Background(), mcp.CallToolRequest{})
+	if !result.IsError || !strings.Contains(getResultText(result), "3 of 3 API calls used") {
+		t.Errorf("expected the call crossing the budget refused, got %s", getResultText(result))
+	}
+	if got := result.StructuredContent.(map[string]any); got["error"] != "budget_exhausted" || got["limit"] != "api_calls" {
+		t.Errorf("unexpected structured content %v", got)
+	}
+	if requests != 3 {
+		t.Errorf("expected 3 requests sent, got %d", requests)
+	}
+	if result, _ := handler(context.Background(), mcp.CallToolRequest{}); !result.IsError || requests != 3 {
+		t.Errorf("expected later calls refused without a request, got %s after %d requests", getResultText(result), requests)
+	}
+
+	b.forget("")
+	if result, _ := handler(context.Background(), mcp.CallToolRequest{}); result.IsError {
+		t.Errorf("expected a new session to get a fresh budget, got %s", getResultText(result))
+	}
+}
+
+func TestSessionBudgets_Rows(t *testing.T) {
+	b := newSessionBudgets(0, 4)
+	handler := b.middleware(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText(`{"results": [1, 2, 3]}`), nil
+	})
+
+	for i, wantError := range []bool{false, false, true} {
+		result, _ := handler(context.Background(), mcp.CallToolRequest{})
+		if result.IsError != wantError {
+			t.Errorf("call %d: expected error %v, got %s", i, wantError, getResultText(result))
+		}
+	}
+}
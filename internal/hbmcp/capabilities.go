@@ -0,0 +1,163 @@
+package hbmcp
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/honeybadger-io/honeybadger-mcp-server/internal/config"
+)
+
+// capabilityProbeTimeout bounds all of detectUnsupportedTools' requests.
+const capabilityProbeTimeout = 30 * time.Second
+
+// maxCapabilityTargets is how many projects, or accounts, each capability
+// is probed against.
+const maxCapabilityTargets = 3
+
+// apiCapability is an API feature that older or self-hosted installs may
+// not serve, with a cheap request that gets a 404 where it's missing and
+// the tools that need it.
+type apiCapability struct {
+	name   string
+	method string
+
+	// path is relative to /v2 and formatted with a project ID, or an
+	// account ID when account is set.
+	path    string
+	account bool
+	body    any
+
+	tools []string
+}
+
+var apiCapabilities = []apiCapability{
+	{
+		// Insights has no GET endpoint, so the probe is a query with no
+		// BadgerQL, which the API refuses without running anything.
+		name:   "insights",
+		method: http.MethodPost,
+		path:   "/projects/%d/insights/queries",
+		body:   map[string]any{},
+		tools:  []string{"query_insights", "query_insights_batch", "list_insights_query_history", "validate_insights_query", "get_insights_schema", "evaluate_alarm_query"},
+	},
+	{
+		name:   "dashboards",
+		method: http.MethodGet,
+		path:   "/projects/%d/dashboards",
+		tools:  []string{"list_dashboards", "get_dashboard", "create_dashboard", "update_dashboard", "delete_dashboard", "build_insights_widget"},
+	},
+	{
+		name:   "alarms",
+		method: http.MethodGet,
+		path:   "/projects/%d/alarms",
+		tools:  []string{"list_alarms", "get_alarm", "create_alarm", "update_alarm", "delete_alarm", "get_alarm_history", "get_alarm_notifications", "update_alarm_notifications"},
+	},
+	{
+		name:   "check_ins",
+		method: http.MethodGet,
+		path:   "/projects/%d/check_ins",
+		tools:  []string{"list_check_ins", "get_check_in", "create_check_in", "update_check_in", "delete_check_in"},
+	},
+	{
+		name:   "streams",
+		method: http.MethodGet,
+		path:   "/projects/%d/streams",
+		tools:  []string{"list_streams"},
+	},
+	{
+		name:    "status_pages",
+		method:  http.MethodGet,
+		path:    "/accounts/%s/status_pages",
+		account: true,
+		tools:   []string{"list_status_pages", "get_status_page", "create_status_page", "update_status_page", "delete_status_page"},
+	},
+}
+
+// detectUnsupportedTools probes the API for each of apiCapabilities
+// against up to maxCapabilityTargets of the token's projects or accounts,
+// and returns the tools of those every probe got a 404 for. Listing the
+// projects and accounts first shows the API URL and prefix are right, so a
+// 404 below them is the feature missing rather than the server. A 404 for
+// one project may only be that project, though, so any probe that gets
+// another answer, or can't run for want of a project or account, leaves
+// the tools in place.
+func detectUnsupportedTools(ctx context.Context, clientFor ClientFactory, rawFor RawClientFactory, logger *slog.Logger) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, capabilityProbeTimeout)
+	defer cancel()
+
+	projects, err := clientFor(ctx).Projects.ListAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing projects: %w", err)
+	}
+	accounts, err := clientFor(ctx).Accounts.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing accounts: %w", err)
+	}
+
+	var projectIDs, accountIDs []any
+	for _, p := range projects.Results[:min(len(projects.Results), maxCapabilityTargets)] {
+		projectIDs = append(projectIDs, p.ID)
+	}
+	for _, a := range accounts[:min(len(accounts), maxCapabilityTargets)] {
+		accountIDs = append(accountIDs, a.ID)
+	}
+
+	var unsupported []string
+	for _, c := range apiCapabilities {
+		targets := projectIDs
+		if c.account {
+			targets = accountIDs
+		}
+		if len(targets) == 0 {
+			logger.Debug("Skipping capability probe with nothing to probe", "capability", c.name)
+			continue
+		}
+		if capabilityMissing(ctx, rawFor, c, targets, logger) {
+			logger.Warn("The API doesn't serve a feature; hiding its tools", "capability", c.name, "tools", c.tools)
+			unsupported = append(unsupported, c.tools...)
+		}
+	}
+	return unsupported, nil
+}
+
+// capabilityMissing reports whether c's probe got a 404 for every target.
+func capabilityMissing(ctx context.Context, rawFor RawClientFactory, c apiCapability, targets []any, logger *slog.Logger) bool {
+	for _, target := range targets {
+		err := rawFor(ctx).Raw(ctx, c.method, fmt.Sprintf(c.path, target), c.body, nil)
+		if !isNotFound(err) {
+			if err != nil {
+				logger.Debug("Capability probe didn't get a 404; keeping its tools", "capability", c.name, "error", err)
+			}
+			return false
+		}
+	}
+	return true
+}
+
+// withDetectedCapabilities returns cfg with UnsupportedTools filled in, or
+// cfg itself when detection is off or can't run. Probes need a token at
+// startup, so http mode only runs them when it also serves stdio.
+func withDetectedCapabilities(cfg *config.Config, clientFor ClientFactory, rawFor RawClientFactory, logger *slog.Logger) *config.Config {
+	if !cfg.DetectCapabilities {
+		return cfg
+	}
+	switch {
+	case cfg.FixturesDir != "":
+		logger.Info("Skipping capability detection in fixture mode")
+		return cfg
+	case cfg.TransportMode == config.TransportHTTP && !cfg.ServeStdio:
+		logger.Info("Skipping capability detection: http mode has no token until a client connects")
+		return cfg
+	}
+	unsupported, err := detectUnsupportedTools(WithStdioTransport(context.Background()), clientFor, rawFor, logger)
+	if err != nil {
+		logger.Warn("Capability detection failed; keeping every tool", "error", err)
+		return cfg
+	}
+	detected := *cfg
+	detected.UnsupportedTools = unsupported
+	return &detected
+}
@@ -0,0 +1,153 @@
+package hbmcp
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/honeybadger-io/honeybadger-mcp-server/internal/config"
+)
+
+// capabilityAPI serves an install without Insights or status pages, whose
+// alarms endpoint is failing.
+func capabilityAPI(t *testing.T) *httptest.Server {
+	t.Helper()
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch path := strings.TrimPrefix(r.URL.Path, "/v2"); path {
+		case "/projects":
+			_, _ = w.Write([]byte(`{"results": [{"id": 7, "name": "Shop"}], "links": {}}`))
+		case "/accounts":
+			_, _ = w.Write([]byte(`{"results": [{"id": "abc", "name": "Acme"}]}`))
+		case "/projects/7/dashboards", "/projects/7/check_ins", "/projects/7/streams":
+			_, _ = w.Write([]byte(`{"results": []}`))
+		case "/projects/7/alarms":
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`{"errors": "oops"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors": "Not found"}`))
+		}
+	}))
+	t.Cleanup(api.Close)
+	return api
+}
+
+func TestDetectCapabilities(t *testing.T) {
+	cfg := reloadTestConfig()
+	cfg.APIURL = capabilityAPI(t).URL
+	cfg.DetectCapabilities = true
+	s, catalog, reloader := NewReloadableServer(cfg, "test")
+
+	tools := s.ListTools()
+	for _, name := range []string{"query_insights", "evaluate_alarm_query", "list_status_pages", "get_status_page"} {
+		if _, ok := tools[name]; ok {
+			t.Errorf("expected %s hidden", name)
+		}
+		if slices.ContainsFunc(catalog, func(info ToolInfo) bool { return info.Name == name }) {
+			t.Errorf("expected %s left out of the catalog", name)
+		}
+	}
+	// A probe that fails other than with a 404 keeps its tools.
+	for _, name := range []string{"list_dashboards", "list_check_ins", "list_streams", "list_alarms", "list_faults"} {
+		if _, ok := tools[name]; !ok {
+			t.Errorf("expected %s kept", name)
+		}
+	}
+
+	// Unsupported tools stay hidden across a reload.
+	next := *cfg
+	next.DisabledTools = []string{"list_streams"}
+	reloader.Reload(&next)
+	tools = s.ListTools()
+	if _, ok := tools["query_insights"]; ok {
+		t.Error("expected query_insights still hidden after a reload")
+	}
+	if _, ok := tools["list_streams"]; ok {
+		t.Error("expected the reload's selection applied")
+	}
+}
+
+func TestDetectCapabilities_Skipped(t *testing.T) {
+	api := capabilityAPI(t)
+	logger := slog.New(slog.DiscardHandler)
+	for name, cfg := range map[string]*config.Config{
+		"off":       {TransportMode: config.TransportStdio},
+		"http mode": {TransportMode: config.TransportHTTP, DetectCapabilities: true},
+		"fixtures":  {TransportMode: config.TransportStdio, DetectCapabilities: true, FixturesDir: t.TempDir()},
+	} {
+		cfg.APIURL = api.URL
+		clientFor, rawFor := NewClientFactories(cfg, logger)
+		if got := withDetectedCapabilities(cfg, clientFor, rawFor, logger); got != cfg {
+			t.Errorf("%s: expected detection skipped, got %v", name, got.UnsupportedTools)
+		}
+	}
+
+	// Detection that can't list projects keeps every tool.
+	cfg := &config.Config{TransportMode: config.TransportStdio, DetectCapabilities: true, APIURL: api.URL + "/missing", AuthToken: "token"}
+	clientFor, rawFor := NewClientFactories(cfg, logger)
+	if got := withDetectedCapabilities(cfg, clientFor, rawFor, logger); got != cfg {
+		t.Errorf("expected a failed detection to keep every tool, got %v", got.UnsupportedTools)
+	}
+}
+
+func TestDetectUnsupportedTools_SeveralProjects(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		writes []string
+	)
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		path := strings.TrimPrefix(r.URL.Path, "/v2")
+		if r.Method != http.MethodGet {
+			body, _ := io.ReadAll(r.Body)
+			mu.Lock()
+			writes = append(writes, r.Method+" "+path+" "+string(body))
+			mu.Unlock()
+		}
+		switch path {
+		case "/projects":
+			_, _ = w.Write([]byte(`{"results": [{"id": 7}, {"id": 8}, {"id": 9}, {"id": 10}], "links": {}}`))
+		case "/accounts":
+			_, _ = w.Write([]byte(`{"results": [{"id": "abc"}]}`))
+		case "/projects/9/dashboards", "/projects/7/alarms":
+			// Only some projects answer; the others 404.
+			_, _ = w.Write([]byte(`{"results": []}`))
+		case "/projects/7/insights/queries":
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_, _ = w.Write([]byte(`{"errors": "query is required"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors": "Not found"}`))
+		}
+	}))
+	defer api.Close()
+
+	logger := slog.New(slog.DiscardHandler)
+	cfg := &config.Config{TransportMode: config.TransportStdio, APIURL: api.URL, AuthToken: "token"}
+	clientFor, rawFor := NewClientFactories(cfg, logger)
+	unsupported, err := detectUnsupportedTools(context.Background(), clientFor, rawFor, logger)
+	if err != nil {
+		t.Fatalf("detectUnsupportedTools() error = %v", err)
+	}
+
+	for _, name := range []string{"list_dashboards", "list_alarms", "query_insights"} {
+		if slices.Contains(unsupported, name) {
+			t.Errorf("expected %s kept: a project other than the first serves it, or the probe wasn't a 404", name)
+		}
+	}
+	for _, name := range []string{"list_check_ins", "list_streams", "list_status_pages"} {
+		if !slices.Contains(unsupported, name) {
+			t.Errorf("expected %s hidden when every probe gets a 404, got %v", name, unsupported)
+		}
+	}
+	if !slices.Equal(writes, []string{"POST /projects/7/insights/queries {}"}) {
+		t.Errorf("expected GETs besides an Insights probe without a query, got %q", writes)
+	}
+}
@@ -0,0 +1,91 @@
+package hbmcp
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"time"
+)
+
+// chaosTimeoutAfter bounds how long an injected timeout stalls a request. It
+// is well under the API client's default 30s timeout so chaos runs stay usable.
+const chaosTimeoutAfter = 5 * time.Second
+
+type chaosFault int
+
+const (
+	chaosRateLimited chaosFault = iota
+	chaosServerError
+	chaosTimeout
+)
+
+// chaosTransport fails a random fraction of API requests with a 429, a 500,
+// or a stalled-then-timed-out request, so agent and retry behavior can be
+// exercised end to end without a misbehaving upstream. The request never
+// reaches base when a fault is injected.
+type chaosTransport struct {
+	base   http.RoundTripper
+	rate   float64
+	logger *slog.Logger
+
+	// Overridable in tests; default to math/rand/v2.
+	roll func() float64
+	pick func(n int) int
+}
+
+func newChaosTransport(base http.RoundTripper, rate float64, logger *slog.Logger) *chaosTransport {
+	return &chaosTransport{
+		base:   base,
+		rate:   rate,
+		logger: logger,
+		roll:   rand.Float64,
+		pick:   rand.IntN,
+	}
+}
+
+func (t *chaosTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.roll() >= t.rate {
+		return t.base.RoundTrip(req)
+	}
+
+	fault := chaosFault(t.pick(3))
+	switch fault {
+	case chaosRateLimited:
+		t.logger.WarnContext(req.Context(), "Chaos: injecting 429", "method", req.Method, "path", req.URL.Path)
+		resp := localResponse(req, http.StatusTooManyRequests, `{"errors":"Chaos: injected rate limit"}`)
+		resp.Header.Set("Retry-After", "1")
+		return resp, nil
+	case chaosServerError:
+		t.logger.WarnContext(req.Context(), "Chaos: injecting 500", "method", req.Method, "path", req.URL.Path)
+		return localResponse(req, http.StatusInternalServerError, `{"errors":"Chaos: injected server error"}`), nil
+	default:
+		t.logger.WarnContext(req.Context(), "Chaos: injecting timeout", "method", req.Method, "path", req.URL.Path)
+		timer := time.NewTimer(chaosTimeoutAfter)
+		defer timer.Stop()
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-timer.C:
+			return nil, context.DeadlineExceeded
+		}
+	}
+}
+
+// localResponse is a JSON response answered without reaching the API.
+func localResponse(req *http.Request, status int, body string) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewBufferString(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
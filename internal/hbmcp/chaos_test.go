@@ -0,0 +1,113 @@
+package hbmcp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func newTestChaosClient(t *testing.T, rate float64, fault chaosFault) (*hbapi.Client, *atomic.Int32) {
+	t.Helper()
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"results":[]}`))
+	}))
+	t.Cleanup(server.Close)
+
+	transport := newChaosTransport(http.DefaultTransport, rate, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	transport.roll = func() float64 { return 0.5 }
+	transport.pick = func(int) int { return int(fault) }
+
+	client := hbapi.NewClient().
+		WithBaseURL(server.URL).
+		WithAuthToken("test-token").
+		WithHTTPClient(&http.Client{Transport: transport})
+	return client, &hits
+}
+
+func TestChaosTransport_PassesThroughBelowRate(t *testing.T) {
+	client, hits := newTestChaosClient(t, 0.25, chaosServerError)
+
+	if _, err := client.Streams.List(context.Background(), 1); err != nil {
+		t.Fatalf("expected request to pass through, got %v", err)
+	}
+	if hits.Load() != 1 {
+		t.Errorf("expected upstream to be hit once, got %d", hits.Load())
+	}
+}
+
+func TestChaosTransport_InjectsStatusErrors(t *testing.T) {
+	cases := []struct {
+		name   string
+		fault  chaosFault
+		status int
+	}{
+		{"rate limited", chaosRateLimited, http.StatusTooManyRequests},
+		{"server error", chaosServerError, http.StatusInternalServerError},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			client, hits := newTestChaosClient(t, 1, c.fault)
+
+			_, err := client.Streams.List(context.Background(), 1)
+			var apiErr *hbapi.APIError
+			if !errors.As(err, &apiErr) {
+				t.Fatalf("expected *hbapi.APIError, got %T: %v", err, err)
+			}
+			if apiErr.StatusCode != c.status {
+				t.Errorf("expected status %d, got %d", c.status, apiErr.StatusCode)
+			}
+			if hits.Load() != 0 {
+				t.Errorf("injected faults must not reach upstream, got %d hits", hits.Load())
+			}
+		})
+	}
+}
+
+func TestChaosTransport_InjectsTimeout(t *testing.T) {
+	client, _ := newTestChaosClient(t, 1, chaosTimeout)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.Streams.List(ctx, 1)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if time.Since(start) > chaosTimeoutAfter {
+		t.Error("injected timeout should honor the request context")
+	}
+}
+
+func TestChaosTransport_SurfacesAsToolError(t *testing.T) {
+	client, _ := newTestChaosClient(t, 1, chaosRateLimited)
+
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Arguments: map[string]interface{}{"project_id": 1},
+		},
+	}
+	result, err := handleListStreams(context.Background(), client, req)
+	if err != nil {
+		t.Fatalf("handleListStreams() error = %v", err)
+	}
+	if !result.IsError {
+		t.Fatal("expected error result")
+	}
+	if text := getResultText(result); !strings.Contains(text, "429") {
+		t.Errorf("expected 429 in error, got %q", text)
+	}
+}
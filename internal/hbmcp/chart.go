@@ -0,0 +1,140 @@
+package hbmcp
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	renderJSON       = "json"
+	renderASCIIChart = "ascii_chart"
+
+	// maxSparklineWidth caps a sparkline's length; longer series are
+	// summed into groups of consecutive points.
+	maxSparklineWidth = 60
+)
+
+// renderDescription documents the render argument of time series tools.
+const renderDescription = "Output format: 'json' (default) for the raw series, or 'ascii_chart' for a one-line sparkline with total, min, max, and last annotations, which conveys the trend in far fewer tokens"
+
+// renderArg reads the render argument, defaulting to json.
+func renderArg(req mcp.CallToolRequest) (string, *mcp.CallToolResult) {
+	switch render := req.GetString("render", renderJSON); render {
+	case renderJSON, renderASCIIChart:
+		return render, nil
+	default:
+		return "", mcp.NewToolResultError(fmt.Sprintf("invalid render %q: use '%s' or '%s'", render, renderJSON, renderASCIIChart))
+	}
+}
+
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// chartPoint is one labeled value of a series to chart.
+type chartPoint struct {
+	Label string
+	Value float64
+}
+
+// renderSparkline draws points as a titled sparkline: a header with the
+// covered range, the line itself, and a summary. Series wider than
+// maxSparklineWidth are summed into groups so the line stays short.
+func renderSparkline(title string, points []chartPoint) string {
+	if len(points) == 0 {
+		return title + ": no data"
+	}
+
+	group := (len(points) + maxSparklineWidth - 1) / maxSparklineWidth
+	var columns []float64
+	for i := 0; i < len(points); i += group {
+		var sum float64
+		for _, p := range points[i:min(i+group, len(points))] {
+			sum += p.Value
+		}
+		columns = append(columns, sum)
+	}
+
+	lo, hi := 0, 0
+	var total float64
+	for i, p := range points {
+		total += p.Value
+		if p.Value < points[lo].Value {
+			lo = i
+		}
+		if p.Value > points[hi].Value {
+			hi = i
+		}
+	}
+	var peak float64
+	for _, c := range columns {
+		peak = max(peak, c)
+	}
+
+	var line strings.Builder
+	for _, c := range columns {
+		i := 0
+		if peak > 0 {
+			i = int(c / peak * float64(len(sparkBlocks)-1))
+		}
+		line.WriteRune(sparkBlocks[i])
+	}
+
+	header := fmt.Sprintf("%s: %s to %s, %d points", title, points[0].Label, points[len(points)-1].Label, len(points))
+	if group > 1 {
+		header += fmt.Sprintf(" (%d per character)", group)
+	}
+	summary := fmt.Sprintf("total %s, min %s (%s), max %s (%s), last %s",
+		formatChartValue(total),
+		formatChartValue(points[lo].Value), points[lo].Label,
+		formatChartValue(points[hi].Value), points[hi].Label,
+		formatChartValue(points[len(points)-1].Value))
+	return header + "\n" + line.String() + "\n" + summary
+}
+
+func formatChartValue(v float64) string {
+	if v == float64(int64(v)) {
+		return fmt.Sprintf("%d", int64(v))
+	}
+	return fmt.Sprintf("%.1f", v)
+}
+
+// occurrenceChartPoints labels [timestamp, count] pairs by date when every
+// timestamp falls on a midnight in loc, and by date and hour otherwise.
+func occurrenceChartPoints(counts []hbapi.ProjectOccurrenceCount, loc *time.Location) []chartPoint {
+	layout := "2006-01-02"
+	for _, c := range counts {
+		if t := time.Unix(c[0], 0).In(loc); t.Hour() != 0 || t.Minute() != 0 || t.Second() != 0 {
+			layout = "2006-01-02 15:04 MST"
+			break
+		}
+	}
+	points := make([]chartPoint, len(counts))
+	for i, c := range counts {
+		points[i] = chartPoint{Label: time.Unix(c[0], 0).In(loc).Format(layout), Value: float64(c[1])}
+	}
+	return points
+}
+
+// reportChartPoints converts notices_per_day rows, [timestamp, count]
+// pairs, to chart points labeled by date.
+func reportChartPoints(rows [][]interface{}) ([]chartPoint, error) {
+	points := make([]chartPoint, 0, len(rows))
+	for _, row := range rows {
+		if len(row) < 2 {
+			return nil, fmt.Errorf("unexpected report row %v", row)
+		}
+		label := fmt.Sprint(row[0])
+		if t, err := time.Parse(time.RFC3339Nano, label); err == nil {
+			label = t.Format("2006-01-02")
+		}
+		value, ok := row[1].(float64)
+		if !ok {
+			return nil, fmt.Errorf("unexpected report count %v", row[1])
+		}
+		points = append(points, chartPoint{Label: label, Value: value})
+	}
+	return points, nil
+}
@@ -11,6 +11,8 @@ const tokenPrefix = "hbo_"
 
 type Claims struct {
 	Scopes []string
+	// Subject identifies the user the token was issued to.
+	Subject string
 }
 
 func (c *Claims) HasScope(scope string) bool {
@@ -47,5 +49,6 @@ func ParseAccessToken(raw string, keyfunc jwt.Keyfunc, expectedIssuer, expectedA
 	}
 	mc, _ := tok.Claims.(jwt.MapClaims)
 	scope, _ := mc["scope"].(string)
-	return &Claims{Scopes: strings.Fields(scope)}, nil
+	subject, _ := mc.GetSubject()
+	return &Claims{Scopes: strings.Fields(scope), Subject: subject}, nil
 }
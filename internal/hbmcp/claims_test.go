@@ -34,6 +34,7 @@ func baseClaims() jwt.MapClaims {
 		"iss":   "http://localhost:3001",
 		"exp":   now + 60,
 		"scope": "read write",
+		"sub":   "user-42",
 	}
 }
 
@@ -51,6 +52,9 @@ func TestParseAccessToken_Success(t *testing.T) {
 	if got.HasScope("admin") {
 		t.Errorf("unexpected admin scope")
 	}
+	if got.Subject != "user-42" {
+		t.Errorf("Subject = %q, want user-42", got.Subject)
+	}
 }
 
 func TestParseAccessToken_MissingPrefix(t *testing.T) {
@@ -0,0 +1,181 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// coerceArguments converts each argument to the type the tool's input
+// schema declares before the call is validated, so handlers see one
+// representation whatever the client sent: numbers sent as strings (as
+// some clients send every argument) or as integers become float64,
+// booleans sent as strings become bools, and IDs sent as numbers to a
+// string parameter become strings. An empty string for a number or
+// boolean counts as leaving it out. A value that can't be converted fails
+// the call with an error naming the argument and what was sent, rather
+// than reaching the handler as a missing argument.
+func coerceArguments(tool mcp.Tool, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+		var coerced map[string]any
+		for name, value := range args {
+			prop, _ := tool.InputSchema.Properties[name].(map[string]any)
+			v, changed, err := coerceValue(name, prop, value)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+			}
+			if !changed {
+				continue
+			}
+			// The caller's map may be shared, e.g. by a batch of calls.
+			if coerced == nil {
+				coerced = maps.Clone(args)
+			}
+			if _, omit := v.(omitArg); omit {
+				delete(coerced, name)
+			} else {
+				coerced[name] = v
+			}
+		}
+		if coerced != nil {
+			req.Params.Arguments = coerced
+		}
+		return next(ctx, req)
+	}
+}
+
+// omitArg is what coerceValue returns for an argument to treat as left out.
+type omitArg struct{}
+
+// coerceValue converts value to the type prop declares, reporting whether
+// it changed.
+func coerceValue(name string, prop map[string]any, value any) (any, bool, error) {
+	switch prop["type"] {
+	case "number", "integer":
+		if s, ok := value.(string); ok && strings.TrimSpace(s) == "" {
+			return omitArg{}, true, nil
+		}
+		n, ok := coerceNumber(value)
+		if !ok {
+			return nil, false, fmt.Errorf("%s must be a number, got %s", name, describeArg(value))
+		}
+		if (prop["type"] == "integer" || isIDArg(name)) && n != math.Trunc(n) {
+			return nil, false, fmt.Errorf("%s must be a whole number, got %s", name, describeArg(value))
+		}
+		if _, ok := value.(float64); ok {
+			return value, false, nil
+		}
+		return n, true, nil
+	case "boolean":
+		s, ok := value.(string)
+		if !ok {
+			return value, false, nil
+		}
+		if strings.TrimSpace(s) == "" {
+			return omitArg{}, true, nil
+		}
+		b, err := strconv.ParseBool(strings.TrimSpace(s))
+		if err != nil {
+			return nil, false, fmt.Errorf("%s must be true or false, got %s", name, describeArg(value))
+		}
+		return b, true, nil
+	case "string":
+		switch n := value.(type) {
+		case float64:
+			return strconv.FormatFloat(n, 'f', -1, 64), true, nil
+		case int:
+			return strconv.Itoa(n), true, nil
+		case int64:
+			return strconv.FormatInt(n, 10), true, nil
+		case json.Number:
+			return n.String(), true, nil
+		}
+	case "array":
+		items, _ := prop["items"].(map[string]any)
+		list, ok := value.([]any)
+		if !ok || items == nil {
+			return value, false, nil
+		}
+		var out []any
+		for i, item := range list {
+			c, changed, err := coerceValue(fmt.Sprintf("%s[%d]", name, i), items, item)
+			if err != nil {
+				return nil, false, err
+			}
+			if _, omit := c.(omitArg); omit {
+				return nil, false, fmt.Errorf("%s[%d] must not be empty", name, i)
+			}
+			if changed && out == nil {
+				out = slices.Clone(list)
+			}
+			if changed {
+				out[i] = c
+			}
+		}
+		if out == nil {
+			return value, false, nil
+		}
+		return out, true, nil
+	}
+	return value, false, nil
+}
+
+// coerceNumber returns value as a float64, the type JSON numbers decode to
+// and the typed getters read.
+func coerceNumber(value any) (float64, bool) {
+	switch n := value.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(n), 64)
+		return f, err == nil && !math.IsInf(f, 0) && !math.IsNaN(f)
+	}
+	return 0, false
+}
+
+// isIDArg reports whether name is an ID argument, such as project_id or
+// fault_ids[0], which must be a whole number even where the schema only
+// says number.
+func isIDArg(name string) bool {
+	if i := strings.IndexByte(name, '['); i >= 0 {
+		name = name[:i]
+	}
+	return name == "id" || strings.HasSuffix(name, "_id") || strings.HasSuffix(name, "_ids")
+}
+
+// describeArg renders a value for an error message, e.g. string "abc".
+func describeArg(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return fmt.Sprintf("string %q", truncateLabel(v, 50))
+	case bool:
+		return fmt.Sprintf("boolean %t", v)
+	case float64:
+		return "number " + strconv.FormatFloat(v, 'f', -1, 64)
+	case []any:
+		return "an array"
+	case map[string]any:
+		return "an object"
+	}
+	return fmt.Sprintf("%T %v", value, value)
+}
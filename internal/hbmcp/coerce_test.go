@@ -0,0 +1,96 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestCoerceArguments(t *testing.T) {
+	s := server.NewMCPServer("test", "1.0.0")
+	r := newToolRegistrar(s)
+	var got map[string]any
+	r.AddTool(mcp.NewTool("get_thing",
+		mcp.WithTitleAnnotation("Get Thing"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithNumber("project_id", mcp.Required(), mcp.Min(1)),
+		mcp.WithNumber("threshold"),
+		mcp.WithString("notice_id"),
+		mcp.WithBoolean("latest"),
+		mcp.WithArray("fault_ids", mcp.WithNumberItems()),
+	), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		got = req.GetArguments()
+		return mcp.NewToolResultText(`{"ok": true}`), nil
+	})
+	handler := s.GetTool("get_thing").Handler
+
+	tests := []struct {
+		name    string
+		args    map[string]any
+		want    map[string]any
+		wantErr string
+	}{
+		{"numbers pass", map[string]any{"project_id": float64(12)}, map[string]any{"project_id": float64(12)}, ""},
+		{"int", map[string]any{"project_id": 12}, map[string]any{"project_id": float64(12)}, ""},
+		{"json number", map[string]any{"project_id": json.Number("12")}, map[string]any{"project_id": float64(12)}, ""},
+		{"numeric strings", map[string]any{"project_id": " 12 ", "threshold": "2.5", "fault_ids": []any{"3", float64(4)}}, map[string]any{"project_id": float64(12), "threshold": 2.5, "fault_ids": []any{float64(3), float64(4)}}, ""},
+		{"whole float string", map[string]any{"project_id": "12.0"}, map[string]any{"project_id": float64(12)}, ""},
+		{"number for a string", map[string]any{"project_id": 1, "notice_id": float64(98765)}, map[string]any{"project_id": float64(1), "notice_id": "98765"}, ""},
+		{"boolean string", map[string]any{"project_id": 1, "latest": "TRUE"}, map[string]any{"project_id": float64(1), "latest": true}, ""},
+		{"empty strings are left out", map[string]any{"project_id": 1, "threshold": "", "latest": " "}, map[string]any{"project_id": float64(1)}, ""},
+		{"unlisted arguments pass", map[string]any{"project_id": 1, "extra": "7"}, map[string]any{"project_id": float64(1), "extra": "7"}, ""},
+		{"not a number", map[string]any{"project_id": "abc"}, nil, `project_id must be a number, got string "abc"`},
+		{"fractional ID", map[string]any{"project_id": 12.5}, nil, "project_id must be a whole number, got number 12.5"},
+		{"fractional ID in a list", map[string]any{"project_id": 1, "fault_ids": []any{"3.5"}}, nil, `fault_ids[0] must be a whole number, got string "3.5"`},
+		{"not a boolean", map[string]any{"project_id": 1, "latest": "maybe"}, nil, `latest must be true or false, got string "maybe"`},
+		{"object for a number", map[string]any{"project_id": map[string]any{"id": 1}}, nil, "project_id must be a number, got an object"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = nil
+			req := mcp.CallToolRequest{}
+			req.Params.Arguments = tt.args
+			result, err := handler(context.Background(), req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr != "" {
+				if !result.IsError || !strings.Contains(getResultText(result), tt.wantErr) || got != nil {
+					t.Errorf("expected an error containing %q without running the handler, got %s", tt.wantErr, getResultText(result))
+				}
+				return
+			}
+			if result.IsError {
+				t.Fatalf("unexpected error result: %s", getResultText(result))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected the handler to get %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCoerceArguments_LeavesCallerArgumentsAlone(t *testing.T) {
+	tool := mcp.NewTool("get_thing", mcp.WithNumber("project_id"))
+	handler := coerceArguments(tool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if req.GetInt("project_id", 0) != 12 {
+			t.Errorf("expected project_id 12, got %v", req.GetArguments()["project_id"])
+		}
+		return mcp.NewToolResultText("ok"), nil
+	})
+	args := map[string]any{"project_id": "12"}
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = args
+	if _, err := handler(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if args["project_id"] != "12" {
+		t.Errorf("expected the caller's arguments to be unchanged, got %v", args["project_id"])
+	}
+}
@@ -0,0 +1,72 @@
+package hbmcp
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/honeybadger-io/honeybadger-mcp-server/internal/config"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// callPool implements --max-concurrent-calls and --queue-timeout: at most
+// so many tool calls run at once, across sessions, and the rest wait for
+// a slot for up to the queue timeout. It keeps a client that fires calls
+// in parallel from opening an unbounded number of API connections. A
+// limit of zero is no limit.
+type callPool struct {
+	slots        chan struct{}
+	queueTimeout time.Duration
+}
+
+type callSlotKey struct{}
+
+func newCallPool(maxInFlight int, queueTimeout time.Duration) *callPool {
+	if queueTimeout <= 0 {
+		queueTimeout = config.DefaultQueueTimeout
+	}
+	p := &callPool{queueTimeout: queueTimeout}
+	if maxInFlight > 0 {
+		p.slots = make(chan struct{}, maxInFlight)
+	}
+	return p
+}
+
+// middleware runs each call once a slot is free. A call made from inside
+// another, through invoke_tool, runs in its caller's slot, so a full pool
+// can't leave the outer call waiting on itself.
+func (p *callPool) middleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if p.slots == nil || ctx.Value(callSlotKey{}) != nil {
+			return next(ctx, req)
+		}
+
+		start := time.Now()
+		timer := time.NewTimer(p.queueTimeout)
+		defer timer.Stop()
+		select {
+		case p.slots <- struct{}{}:
+		case <-timer.C:
+			return p.busy(time.Since(start)), nil
+		case <-ctx.Done():
+			return mcp.NewToolResultError(fmt.Sprintf("Tool call canceled while waiting for one of %d call slots: %v", cap(p.slots), ctx.Err())), nil
+		}
+		defer func() { <-p.slots }()
+
+		return next(context.WithValue(ctx, callSlotKey{}, true), req)
+	}
+}
+
+// busy returns the result refusing a call that waited out the queue
+// timeout. Besides the message it carries {"error": "busy", ...} as
+// structured content.
+func (p *callPool) busy(waited time.Duration) *mcp.CallToolResult {
+	result := mcp.NewToolResultError(fmt.Sprintf("Server busy: all %d tool call slots stayed in use for %s. Retry this call shortly, and make fewer calls at once; the server's operator can raise --max-concurrent-calls.", cap(p.slots), waited.Round(time.Millisecond)))
+	result.StructuredContent = map[string]any{
+		"error":         "busy",
+		"max_in_flight": cap(p.slots),
+		"waited_ms":     waited.Milliseconds(),
+	}
+	return result
+}
@@ -0,0 +1,73 @@
+package hbmcp
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestCallPool(t *testing.T) {
+	p := newCallPool(2, 50*time.Millisecond)
+	release := make(chan struct{})
+	var running, peak atomic.Int32
+	handler := p.middleware(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			old := peak.Load()
+			if n <= old || peak.CompareAndSwap(old, n) {
+				break
+			}
+		}
+		<-release
+		return mcp.NewToolResultText("ok"), nil
+	})
+
+	var wg sync.WaitGroup
+	results := make([]*mcp.CallToolResult, 3)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], _ = handler(context.Background(), mcp.CallToolRequest{})
+		}()
+	}
+	time.Sleep(150 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	var busy int
+	for _, result := range results {
+		if !result.IsError {
+			continue
+		}
+		busy++
+		if !strings.Contains(getResultText(result), "all 2 tool call slots stayed in use") {
+			t.Errorf("unexpected error %s", getResultText(result))
+		}
+		if got := result.StructuredContent.(map[string]any); got["error"] != "busy" || got["max_in_flight"] != 2 {
+			t.Errorf("unexpected structured content %v", got)
+		}
+	}
+	if busy != 1 || peak.Load() != 2 {
+		t.Errorf("expected 2 calls run at once and 1 refused, got peak %d and %d refused", peak.Load(), busy)
+	}
+}
+
+func TestCallPool_Nested(t *testing.T) {
+	p := newCallPool(1, 50*time.Millisecond)
+	inner := p.middleware(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("inner"), nil
+	})
+	outer := p.middleware(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return inner(ctx, req)
+	})
+	if result, _ := outer(context.Background(), mcp.CallToolRequest{}); result.IsError || getResultText(result) != "inner" {
+		t.Errorf("expected the nested call to run in its caller's slot, got %s", getResultText(result))
+	}
+}
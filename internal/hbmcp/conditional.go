@@ -0,0 +1,175 @@
+package hbmcp
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+const (
+	// maxCachedBody is the largest response body kept for revalidation;
+	// bigger ones pass through uncached.
+	maxCachedBody = 1 << 20
+
+	// maxCacheBytes bounds the bodies kept across all requests. The least
+	// recently used are evicted first.
+	maxCacheBytes = 16 << 20
+)
+
+// conditionalTransport remembers GET responses that carry an ETag and
+// revalidates them with If-None-Match, so a repeated list call the API
+// answers with 304 Not Modified costs no body transfer. The cached body is
+// then returned as a 200, so everything above sees a normal response.
+// Every request still reaches the API, so results are never stale.
+//
+// Responses are cached per credential as well as per URL: in http mode
+// each caller's token sees only the responses fetched with it.
+//
+// Compression needs nothing here: the standard transport asks for gzip and
+// decompresses the response when the request doesn't set Accept-Encoding
+// itself, which hbapi doesn't.
+type conditionalTransport struct {
+	base   http.RoundTripper
+	logger *slog.Logger
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List // of *cachedResponse, most recently used first
+	size    int
+}
+
+type cachedResponse struct {
+	key    string
+	etag   string
+	header http.Header
+	body   []byte
+}
+
+func newConditionalTransport(base http.RoundTripper, logger *slog.Logger) *conditionalTransport {
+	return &conditionalTransport{
+		base:    base,
+		logger:  logger,
+		entries: map[string]*list.Element{},
+		lru:     list.New(),
+	}
+}
+
+func (t *conditionalTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet || req.Header.Get("If-None-Match") != "" || req.Header.Get("Range") != "" {
+		return t.base.RoundTrip(req)
+	}
+
+	key := conditionalCacheKey(req)
+	cached := t.get(key)
+	if cached != nil {
+		req = req.Clone(req.Context())
+		req.Header.Set("If-None-Match", cached.etag)
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached != nil:
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+		t.logger.DebugContext(req.Context(), "API response not modified; serving cached body", "path", req.URL.Path)
+		return cached.response(req), nil
+	case resp.StatusCode != http.StatusOK:
+		return resp, nil
+	}
+
+	etag := resp.Header.Get("ETag")
+	if etag == "" || resp.ContentLength > maxCachedBody || strings.Contains(resp.Header.Get("Cache-Control"), "no-store") {
+		t.remove(key)
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxCachedBody+1))
+	if err != nil {
+		_ = resp.Body.Close()
+		return nil, err
+	}
+	if len(body) > maxCachedBody {
+		// Too big after all: hand back what was read and the rest unread.
+		t.remove(key)
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+		return resp, nil
+	}
+	_ = resp.Body.Close()
+
+	t.put(&cachedResponse{key: key, etag: etag, header: resp.Header.Clone(), body: body})
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+// conditionalCacheKey identifies a response by URL and the credential it
+// was fetched with, hashed so tokens aren't held in the cache.
+func conditionalCacheKey(req *http.Request) string {
+	sum := sha256.Sum256([]byte(req.Header.Get("Authorization") + "\x00" + req.URL.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+// response rebuilds the cached 200 for req.
+func (c *cachedResponse) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        c.header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(c.body)),
+		ContentLength: int64(len(c.body)),
+		Request:       req,
+	}
+}
+
+func (t *conditionalTransport) get(key string) *cachedResponse {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	el, ok := t.entries[key]
+	if !ok {
+		return nil
+	}
+	t.lru.MoveToFront(el)
+	return el.Value.(*cachedResponse)
+}
+
+func (t *conditionalTransport) put(c *cachedResponse) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.removeLocked(c.key)
+	t.entries[c.key] = t.lru.PushFront(c)
+	t.size += len(c.body)
+	for t.size > maxCacheBytes {
+		t.removeLocked(t.lru.Back().Value.(*cachedResponse).key)
+	}
+}
+
+func (t *conditionalTransport) remove(key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.removeLocked(key)
+}
+
+func (t *conditionalTransport) removeLocked(key string) {
+	el, ok := t.entries[key]
+	if !ok {
+		return
+	}
+	t.lru.Remove(el)
+	delete(t.entries, key)
+	t.size -= len(el.Value.(*cachedResponse).body)
+}
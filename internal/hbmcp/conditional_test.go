@@ -0,0 +1,178 @@
+package hbmcp
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/honeybadger-io/honeybadger-mcp-server/internal/config"
+)
+
+func TestConditionalTransport(t *testing.T) {
+	var requests, notModified atomic.Int32
+	var body atomic.Value
+	body.Store(`{"results": [{"id": 1}]}`)
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		current := body.Load().(string)
+		etag := fmt.Sprintf(`W/"%d-%s"`, len(current), r.Header.Get("Authorization"))
+		if r.URL.Path == "/nocache" {
+			etag = ""
+		}
+		if etag != "" && r.Header.Get("If-None-Match") == etag {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		if etag != "" {
+			w.Header().Set("ETag", etag)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, current)
+	}))
+	defer api.Close()
+
+	transport := newConditionalTransport(http.DefaultTransport, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	client := &http.Client{Transport: transport}
+	get := func(path, token string) string {
+		t.Helper()
+		req, _ := http.NewRequest(http.MethodGet, api.URL+path, nil)
+		req.Header.Set("Authorization", token)
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("GET %s: %v", path, err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("GET %s: status %d, want 200", path, resp.StatusCode)
+		}
+		b, _ := io.ReadAll(resp.Body)
+		return string(b)
+	}
+
+	first := get("/projects", "a")
+	if second := get("/projects", "a"); second != first {
+		t.Errorf("revalidated body = %q, want %q", second, first)
+	}
+	if notModified.Load() != 1 {
+		t.Errorf("expected the repeat request to be revalidated with a 304, got %d", notModified.Load())
+	}
+
+	// Another credential doesn't share the cached response.
+	get("/projects", "b")
+	if notModified.Load() != 1 {
+		t.Errorf("expected a different token not to revalidate the first token's response")
+	}
+
+	// A changed resource is fetched in full and replaces the cached body.
+	body.Store(`{"results": [{"id": 1}, {"id": 2}]}`)
+	if got := get("/projects", "a"); !strings.Contains(got, `"id": 2`) {
+		t.Errorf("expected the changed body, got %q", got)
+	}
+
+	// Responses without an ETag aren't cached.
+	get("/nocache", "a")
+	get("/nocache", "a")
+	if len(transport.entries) != 2 {
+		t.Errorf("expected 2 cached responses, got %d", len(transport.entries))
+	}
+}
+
+func TestConditionalTransport_Eviction(t *testing.T) {
+	large := strings.Repeat("x", maxCachedBody/2)
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"`+r.URL.Path+`"`)
+		if r.URL.Path == "/huge" {
+			_, _ = io.WriteString(w, strings.Repeat("x", maxCachedBody+1))
+			return
+		}
+		_, _ = io.WriteString(w, large)
+	}))
+	defer api.Close()
+
+	transport := newConditionalTransport(http.DefaultTransport, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	client := &http.Client{Transport: transport}
+	for i := range 2*maxCacheBytes/len(large) + 1 {
+		resp, err := client.Get(fmt.Sprintf("%s/%d", api.URL, i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		_ = resp.Body.Close()
+	}
+	if transport.size > maxCacheBytes {
+		t.Errorf("cache holds %d bytes, want at most %d", transport.size, maxCacheBytes)
+	}
+
+	resp, err := client.Get(api.URL + "/huge")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if len(b) != maxCachedBody+1 {
+		t.Errorf("expected the whole oversized body, got %d bytes", len(b))
+	}
+	if transport.get(conditionalCacheKey(resp.Request)) != nil {
+		t.Error("expected an oversized body not to be cached")
+	}
+}
+
+func TestNewClientFactory_Gzip(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			t.Errorf("expected the request to accept gzip, got %q", r.Header.Get("Accept-Encoding"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		_, _ = io.WriteString(gz, `{"results": [{"id": "s1", "name": "Logs"}]}`)
+		_ = gz.Close()
+	}))
+	defer api.Close()
+
+	cfg := &config.Config{APIURL: api.URL, AuthToken: "test-token", TransportMode: config.TransportStdio}
+	ctx := context.Background()
+	streams, err := NewClientFactory(cfg, slog.New(slog.NewTextHandler(io.Discard, nil)))(ctx).Streams.List(ctx, 1)
+	if err != nil {
+		t.Fatalf("Streams.List() error = %v", err)
+	}
+	if len(streams) != 1 || streams[0].Name != "Logs" {
+		t.Errorf("Streams.List() = %+v, want the decompressed stream", streams)
+	}
+}
+
+func TestNewClientFactory_StdioAlongsideHTTP(t *testing.T) {
+	var auth atomic.Value
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth.Store(r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"results": []}`)
+	}))
+	defer api.Close()
+
+	cfg := &config.Config{APIURL: api.URL, AuthToken: "startup-token", TransportMode: config.TransportHTTP, ServeStdio: true}
+	clientFor := NewClientFactory(cfg, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	ctx := WithAuthToken(context.Background(), "caller-token")
+	if _, err := clientFor(ctx).Streams.List(ctx, 1); err != nil {
+		t.Fatalf("Streams.List() error = %v", err)
+	}
+	if got := auth.Load(); got != "Bearer caller-token" {
+		t.Errorf("expected an http call to forward its bearer token, got %q", got)
+	}
+
+	ctx = WithStdioTransport(context.Background())
+	if _, err := clientFor(ctx).Streams.List(ctx, 1); err != nil {
+		t.Fatalf("Streams.List() error = %v", err)
+	}
+	if got, _ := auth.Load().(string); !strings.HasPrefix(got, "Basic ") {
+		t.Errorf("expected a stdio call to use the startup token as Basic auth, got %q", got)
+	}
+}
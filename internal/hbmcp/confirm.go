@@ -0,0 +1,197 @@
+package hbmcp
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	confirmationArg = "confirmation_token"
+
+	// confirmationTTL is how long a confirmation token stays valid.
+	confirmationTTL = 5 * time.Minute
+)
+
+// confirmations implements --confirm-destructive: a call to a destructive
+// tool without a confirmation token is not run but answered with a token
+// and a summary of what the call would do, and only a second call with
+// the same arguments and that token runs it. Tokens are single use and
+// held in memory, so a restart invalidates them.
+type confirmations struct {
+	now func() time.Time
+
+	mu      sync.Mutex
+	pending map[string]pendingConfirmation
+}
+
+// pendingConfirmation is what a token confirms: one tool call, by one
+// caller, with particular arguments.
+type pendingConfirmation struct {
+	tool    string
+	args    string
+	caller  string
+	expires time.Time
+}
+
+// confirmationRequest is the result of a destructive call made without a
+// token.
+type confirmationRequest struct {
+	ConfirmationRequired bool           `json:"confirmation_required"`
+	Tool                 string         `json:"tool"`
+	Arguments            map[string]any `json:"arguments"`
+	Summary              string         `json:"summary"`
+	ConfirmationToken    string         `json:"confirmation_token"`
+	ExpiresAt            time.Time      `json:"expires_at"`
+}
+
+func newConfirmations() *confirmations {
+	return &confirmations{now: time.Now, pending: map[string]pendingConfirmation{}}
+}
+
+// withConfirmationArg adds the confirmation_token parameter to a
+// destructive tool.
+func withConfirmationArg(tool *mcp.Tool) {
+	mcp.WithString(confirmationArg,
+		mcp.Description("The token returned by a first call with the same arguments. This server confirms destructive calls: call without it to get a token and a summary of what the call will do, check the summary with the user, then call again with the token to run it."),
+	)(tool)
+}
+
+// require wraps the handler of a destructive tool so it only runs with a
+// valid confirmation token.
+func (c *confirmations) require(tool mcp.Tool, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := confirmedArgs(req.GetArguments())
+		key, err := json.Marshal(args)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to read arguments: %v", err)), nil
+		}
+		caller := confirmationCaller(ctx)
+
+		token, _ := req.GetArguments()[confirmationArg].(string)
+		if token == "" {
+			response := c.issue(tool, args, string(key), caller)
+			jsonBytes, err := json.Marshal(response)
+			if err != nil {
+				return mcp.NewToolResultError("Failed to marshal response"), nil
+			}
+			return mcp.NewToolResultText(string(jsonBytes)), nil
+		}
+
+		if problem := c.redeem(token, tool.Name, string(key), caller); problem != "" {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid %s: %s. Call %s without it to get a new one.", confirmationArg, problem, tool.Name)), nil
+		}
+		return next(ctx, req)
+	}
+}
+
+func (c *confirmations) issue(tool mcp.Tool, args map[string]any, key, caller string) confirmationRequest {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	token := hex.EncodeToString(b)
+	now := c.now()
+	expires := now.Add(confirmationTTL)
+
+	c.mu.Lock()
+	for t, p := range c.pending {
+		if now.After(p.expires) {
+			delete(c.pending, t)
+		}
+	}
+	c.pending[token] = pendingConfirmation{tool: tool.Name, args: key, caller: caller, expires: expires}
+	c.mu.Unlock()
+
+	return confirmationRequest{
+		ConfirmationRequired: true,
+		Tool:                 tool.Name,
+		Arguments:            args,
+		Summary:              confirmationSummary(tool, args),
+		ConfirmationToken:    token,
+		ExpiresAt:            expires.UTC(),
+	}
+}
+
+// redeem uses up token, returning why it can't confirm this call, or ""
+// when it does. A token is used up even when it doesn't match, so one
+// can't be tried against other arguments.
+func (c *confirmations) redeem(token, tool, key, caller string) string {
+	c.mu.Lock()
+	p, ok := c.pending[token]
+	delete(c.pending, token)
+	c.mu.Unlock()
+
+	switch {
+	case !ok || p.caller != caller:
+		return "unknown or already used"
+	case c.now().After(p.expires):
+		return "expired"
+	case p.tool != tool:
+		return fmt.Sprintf("it was issued for %s", p.tool)
+	case p.args != key:
+		return "the arguments differ from the call it was issued for"
+	}
+	return ""
+}
+
+// confirmedArgs returns the arguments a token is bound to: all of them
+// but the token itself, timeout_seconds, and fields, which don't change
+// what the call does.
+func confirmedArgs(args map[string]any) map[string]any {
+	confirmed := map[string]any{}
+	for k, v := range args {
+		if k != confirmationArg && k != timeoutArg && k != fieldsArg {
+			confirmed[k] = v
+		}
+	}
+	return confirmed
+}
+
+// confirmationCaller identifies who may redeem a token: in http mode the
+// bearer token the call was made with, hashed so it isn't held.
+func confirmationCaller(ctx context.Context) string {
+	token := AuthTokenFromContext(ctx)
+	if token == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// confirmationSummary describes a call for the user to check, such as
+// "Delete Project: delete_project with id=123".
+func confirmationSummary(tool mcp.Tool, args map[string]any) string {
+	title := tool.Annotations.Title
+	if title == "" {
+		title = tool.Name
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s: %s", title, tool.Name)
+	if len(args) == 0 {
+		sb.WriteString(" with no arguments")
+	}
+	for i, k := range slices.Sorted(maps.Keys(args)) {
+		if i == 0 {
+			sb.WriteString(" with ")
+		} else {
+			sb.WriteString(", ")
+		}
+		fmt.Fprintf(&sb, "%s=%s", k, jsonValue(args[k]))
+	}
+	if strings.HasPrefix(tool.Name, "delete_") {
+		sb.WriteString(". This can't be undone.")
+	} else {
+		sb.WriteString(".")
+	}
+	return sb.String()
+}
@@ -0,0 +1,131 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func confirmTestTool() (mcp.Tool, server.ToolHandlerFunc, *int) {
+	tool := mcp.NewTool("delete_project",
+		mcp.WithTitleAnnotation("Delete Project"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithNumber("id", mcp.Required()),
+	)
+	calls := 0
+	handler := func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		calls++
+		return mcp.NewToolResultText("deleted"), nil
+	}
+	return tool, handler, &calls
+}
+
+func confirmCall(ctx context.Context, handler server.ToolHandlerFunc, args map[string]any) *mcp.CallToolResult {
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "delete_project"
+	req.Params.Arguments = args
+	result, _ := handler(ctx, req)
+	return result
+}
+
+func TestConfirmations(t *testing.T) {
+	c := newConfirmations()
+	tool, next, calls := confirmTestTool()
+	handler := c.require(tool, next)
+	ctx := context.Background()
+
+	result := confirmCall(ctx, handler, map[string]any{"id": float64(123), "timeout_seconds": float64(30)})
+	if result.IsError || *calls != 0 {
+		t.Fatalf("expected a confirmation request and no call, got %q after %d calls", getResultText(result), *calls)
+	}
+	var request confirmationRequest
+	if err := json.Unmarshal([]byte(getResultText(result)), &request); err != nil {
+		t.Fatalf("failed to parse confirmation request: %v", err)
+	}
+	if !request.ConfirmationRequired || request.Tool != "delete_project" || request.ConfirmationToken == "" {
+		t.Errorf("unexpected confirmation request: %+v", request)
+	}
+	if request.Summary != "Delete Project: delete_project with id=123. This can't be undone." {
+		t.Errorf("unexpected summary %q", request.Summary)
+	}
+	if _, ok := request.Arguments["timeout_seconds"]; ok {
+		t.Error("expected timeout_seconds to be left out of the confirmed arguments")
+	}
+
+	// Leaving out timeout_seconds doesn't change what the call does.
+	result = confirmCall(ctx, handler, map[string]any{"id": float64(123), "confirmation_token": request.ConfirmationToken})
+	if result.IsError || *calls != 1 || getResultText(result) != "deleted" {
+		t.Fatalf("expected the confirmed call to run, got %q after %d calls", getResultText(result), *calls)
+	}
+
+	result = confirmCall(ctx, handler, map[string]any{"id": float64(123), "confirmation_token": request.ConfirmationToken})
+	if !result.IsError || !strings.Contains(getResultText(result), "unknown or already used") || *calls != 1 {
+		t.Errorf("expected a used token to be refused, got %q", getResultText(result))
+	}
+}
+
+func TestConfirmations_Refused(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    map[string]any
+		ctx     context.Context
+		advance time.Duration
+		want    string
+	}{
+		{"other arguments", map[string]any{"id": float64(456)}, context.Background(), 0, "arguments differ"},
+		{"expired", map[string]any{"id": float64(123)}, context.Background(), confirmationTTL + time.Second, "expired"},
+		{"other caller", map[string]any{"id": float64(123)}, WithAuthToken(context.Background(), "someone-else"), 0, "unknown or already used"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Date(2024, 3, 15, 14, 0, 0, 0, time.UTC)
+			c := newConfirmations()
+			c.now = func() time.Time { return now }
+			tool, next, calls := confirmTestTool()
+			handler := c.require(tool, next)
+
+			var request confirmationRequest
+			result := confirmCall(context.Background(), handler, map[string]any{"id": float64(123)})
+			if err := json.Unmarshal([]byte(getResultText(result)), &request); err != nil {
+				t.Fatalf("failed to parse confirmation request: %v", err)
+			}
+
+			now = now.Add(tt.advance)
+			tt.args["confirmation_token"] = request.ConfirmationToken
+			result = confirmCall(tt.ctx, handler, tt.args)
+			if !result.IsError || !strings.Contains(getResultText(result), tt.want) || *calls != 0 {
+				t.Errorf("expected refusal containing %q, got %q after %d calls", tt.want, getResultText(result), *calls)
+			}
+		})
+	}
+}
+
+func TestToolRegistrar_ConfirmDestructive(t *testing.T) {
+	s := server.NewMCPServer("test", "1.0.0")
+	r := newToolRegistrar(s)
+	r.confirmations = newConfirmations()
+
+	tool, handler, _ := confirmTestTool()
+	r.AddTool(tool, handler)
+	r.AddTool(mcp.NewTool("list_projects", mcp.WithReadOnlyHintAnnotation(true), mcp.WithDestructiveHintAnnotation(false)), handler)
+
+	if _, ok := s.GetTool("delete_project").Tool.InputSchema.Properties[confirmationArg]; !ok {
+		t.Error("expected delete_project to take confirmation_token")
+	}
+	if _, ok := s.GetTool("list_projects").Tool.InputSchema.Properties[confirmationArg]; ok {
+		t.Error("expected list_projects not to take confirmation_token")
+	}
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{}
+	result, _ := s.GetTool("list_projects").Handler(context.Background(), req)
+	if getResultText(result) != "deleted" {
+		t.Errorf("expected a non-destructive tool to run at once, got %q", getResultText(result))
+	}
+}
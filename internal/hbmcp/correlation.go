@@ -0,0 +1,86 @@
+package hbmcp
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/honeybadger-io/honeybadger-mcp-server/internal/logging"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// correlationHeader carries a tool call's correlation ID on the API
+// requests it makes.
+const correlationHeader = "X-Request-Id"
+
+type correlationIDKey struct{}
+
+// correlationID returns the ID of the tool call ctx belongs to, or "".
+func correlationID(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIDKey{}).(string)
+	return id
+}
+
+// correlateToolCalls gives every tool call a correlation ID. The ID is
+// sent with the call's API requests, added to what is logged with its
+// context, and appended to an error result, so a failed call an agent
+// reports can be matched to the server's logs and to the requests
+// Honeybadger received.
+func correlateToolCalls(logger *slog.Logger) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			id := rand.Text()
+			ctx = context.WithValue(ctx, correlationIDKey{}, id)
+			ctx = logging.ContextWithAttrs(ctx, slog.String("correlation_id", id))
+
+			start := time.Now()
+			result, err := next(ctx, req)
+			switch {
+			case err != nil:
+				logger.InfoContext(ctx, "Tool call failed", "tool", req.Params.Name, "duration_ms", time.Since(start).Milliseconds(), "error", err)
+				return result, fmt.Errorf("%w (correlation ID: %s)", err, id)
+			case result != nil && result.IsError:
+				logger.InfoContext(ctx, "Tool call returned an error", "tool", req.Params.Name, "duration_ms", time.Since(start).Milliseconds())
+				addCorrelationID(result, id)
+			default:
+				logger.DebugContext(ctx, "Tool call finished", "tool", req.Params.Name, "duration_ms", time.Since(start).Milliseconds())
+			}
+			return result, err
+		}
+	}
+}
+
+// addCorrelationID appends the correlation ID to an error result's message,
+// and to its structured content when that is an object.
+func addCorrelationID(result *mcp.CallToolResult, id string) {
+	for i, c := range result.Content {
+		if text, ok := c.(mcp.TextContent); ok {
+			text.Text += fmt.Sprintf("\n\nCorrelation ID: %s", id)
+			result.Content[i] = text
+			break
+		}
+	}
+	if structured, ok := result.StructuredContent.(map[string]any); ok {
+		structured["correlation_id"] = id
+	}
+}
+
+// correlationTransport sets correlationHeader on API requests made for a
+// tool call.
+type correlationTransport struct {
+	base http.RoundTripper
+}
+
+func (t *correlationTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	id := correlationID(req.Context())
+	if id == "" {
+		return t.base.RoundTrip(req)
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set(correlationHeader, id)
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,76 @@
+package hbmcp
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestCorrelateToolCalls(t *testing.T) {
+	var sent []string
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent = append(sent, r.Header.Get(correlationHeader))
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer api.Close()
+	client := &http.Client{Transport: &correlationTransport{base: http.DefaultTransport}}
+
+	handler := correlateToolCalls(slog.New(slog.DiscardHandler))(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		httpReq, _ := http.NewRequestWithContext(ctx, http.MethodGet, api.URL, nil)
+		resp, err := client.Do(httpReq)
+		if err != nil {
+			return nil, err
+		}
+		_ = resp.Body.Close()
+		result := mcp.NewToolResultError("Failed to list faults: HTTP 500")
+		result.StructuredContent = map[string]any{"error": "api"}
+		return result, nil
+	})
+
+	result, err := handler(context.Background(), mcp.CallToolRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(sent) != 1 || sent[0] == "" {
+		t.Fatalf("expected a correlation ID sent with the API request, got %q", sent)
+	}
+	if text := getResultText(result); !strings.HasSuffix(text, "\n\nCorrelation ID: "+sent[0]) {
+		t.Errorf("expected the error result to end with the correlation ID %s, got %q", sent[0], text)
+	}
+	if structured := result.StructuredContent.(map[string]any); structured["correlation_id"] != sent[0] {
+		t.Errorf("expected the correlation ID in the structured content, got %v", structured)
+	}
+
+	// Each call gets its own ID.
+	if _, err := handler(context.Background(), mcp.CallToolRequest{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(sent) != 2 || sent[1] == sent[0] {
+		t.Errorf("expected a new correlation ID per call, got %q", sent)
+	}
+}
+
+func TestCorrelateToolCalls_Success(t *testing.T) {
+	var id string
+	handler := correlateToolCalls(slog.New(slog.DiscardHandler))(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		id = correlationID(ctx)
+		return mcp.NewToolResultText(`{"id": 1}`), nil
+	})
+	result, _ := handler(context.Background(), mcp.CallToolRequest{})
+	if id == "" || getResultText(result) != `{"id": 1}` {
+		t.Errorf("expected an ID in the context and the result untouched, got %q, %q", id, getResultText(result))
+	}
+
+	failing := correlateToolCalls(slog.New(slog.DiscardHandler))(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return nil, errors.New("boom")
+	})
+	if _, err := failing(context.Background(), mcp.CallToolRequest{}); err == nil || !strings.Contains(err.Error(), "boom (correlation ID: ") {
+		t.Errorf("expected the correlation ID added to the error, got %v", err)
+	}
+}
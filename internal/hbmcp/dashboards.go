@@ -18,6 +18,9 @@ func RegisterDashboardTools(r *toolRegistrar, clientFor ClientFactory) {
 			mcp.WithDescription("List all Insights dashboards for a Honeybadger project"),
 			mcp.WithReadOnlyHintAnnotation(true),
 			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithOutputSchema[hbapi.DashboardListResponse](),
 			mcp.WithNumber("project_id",
 				mcp.Required(),
 				mcp.Description("The ID of the project to list dashboards for"),
@@ -36,6 +39,9 @@ func RegisterDashboardTools(r *toolRegistrar, clientFor ClientFactory) {
 			mcp.WithDescription("Get a single Insights dashboard by ID"),
 			mcp.WithReadOnlyHintAnnotation(true),
 			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithOutputSchema[hbapi.Dashboard](),
 			mcp.WithNumber("project_id",
 				mcp.Required(),
 				mcp.Description("The ID of the project the dashboard belongs to"),
@@ -58,6 +64,8 @@ func RegisterDashboardTools(r *toolRegistrar, clientFor ClientFactory) {
 			mcp.WithDescription("Create a new Insights dashboard for a Honeybadger project. IMPORTANT: Requires reference topics: dashboards, charts, queries, badgerql — fetch via get_reference first (skip topics still visible in your context). Verify each widget's query returns the expected results via query_insights before creating the dashboard."),
 			mcp.WithReadOnlyHintAnnotation(false),
 			mcp.WithDestructiveHintAnnotation(true),
+			mcp.WithIdempotentHintAnnotation(false),
+			mcp.WithOpenWorldHintAnnotation(false),
 			mcp.WithNumber("project_id",
 				mcp.Required(),
 				mcp.Description("The ID of the project to create the dashboard in"),
@@ -69,7 +77,7 @@ func RegisterDashboardTools(r *toolRegistrar, clientFor ClientFactory) {
 			),
 			mcp.WithString("widgets",
 				mcp.Required(),
-				mcp.Description("JSON array of widget objects. The dashboards reference topic has the full widget schema and examples. Each widget needs: type (insights_vis, alarms, errors, deployments, checkins, uptime), and optionally: grid ({x,y,w,h}), presentation ({title, subtitle}), config (type-specific settings). For insights_vis widgets, config should include query (BadgerQL string) and vis ({view, chart_config})."),
+				mcp.Description(widgetsDescription),
 			),
 			mcp.WithString("default_ts",
 				mcp.Description("Default time range for the dashboard. ISO 8601 duration (e.g., P1D, PT3H) or keyword (today, yesterday, week, month)."),
@@ -87,6 +95,8 @@ func RegisterDashboardTools(r *toolRegistrar, clientFor ClientFactory) {
 			mcp.WithDescription("Update an existing Insights dashboard. IMPORTANT: Requires reference topics: dashboards, charts, queries, badgerql — fetch via get_reference first (skip topics still visible in your context)."),
 			mcp.WithReadOnlyHintAnnotation(false),
 			mcp.WithDestructiveHintAnnotation(true),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
 			mcp.WithNumber("project_id",
 				mcp.Required(),
 				mcp.Description("The ID of the project the dashboard belongs to"),
@@ -102,7 +112,7 @@ func RegisterDashboardTools(r *toolRegistrar, clientFor ClientFactory) {
 			),
 			mcp.WithString("widgets",
 				mcp.Required(),
-				mcp.Description("JSON array of widget objects. The dashboards reference topic has the full widget schema and examples. Each widget needs: type (insights_vis, alarms, errors, deployments, checkins, uptime), and optionally: grid ({x,y,w,h}), presentation ({title, subtitle}), config (type-specific settings). For insights_vis widgets, config should include query (BadgerQL string) and vis ({view, chart_config})."),
+				mcp.Description(widgetsDescription),
 			),
 			mcp.WithString("default_ts",
 				mcp.Description("Default time range for the dashboard. ISO 8601 duration (e.g., P1D, PT3H) or keyword (today, yesterday, week, month)."),
@@ -120,6 +130,8 @@ func RegisterDashboardTools(r *toolRegistrar, clientFor ClientFactory) {
 			mcp.WithDescription("Delete an Insights dashboard"),
 			mcp.WithReadOnlyHintAnnotation(false),
 			mcp.WithDestructiveHintAnnotation(true),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
 			mcp.WithNumber("project_id",
 				mcp.Required(),
 				mcp.Description("The ID of the project the dashboard belongs to"),
@@ -134,6 +146,52 @@ func RegisterDashboardTools(r *toolRegistrar, clientFor ClientFactory) {
 			return handleDeleteDashboard(ctx, clientFor(ctx), req)
 		},
 	)
+
+	// build_insights_widget tool
+	r.AddTool(
+		mcp.NewTool("build_insights_widget",
+			mcp.WithTitleAnnotation("Build Insights Widget"),
+			mcp.WithDescription("Build an insights_vis dashboard widget from a query, chart view, title, and grid position, and return it as a checked widget object to put in the widgets array of create_dashboard or update_dashboard. Makes no API call: check the query itself with validate_insights_query. Requires reference topics: charts, dashboards (fetch via get_reference; skip topics still visible in your context)."),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithString("query",
+				mcp.Required(),
+				mcp.Description("BadgerQL query the widget charts"),
+			),
+			mcp.WithString("view",
+				mcp.Required(),
+				mcp.Description("Chart view, e.g. 'line', 'bar', or 'table'. The charts reference topic lists the views and their chart_config fields."),
+			),
+			mcp.WithString("chart_config",
+				mcp.Description("JSON object of view-specific chart settings, as described in the charts reference topic"),
+			),
+			mcp.WithString("title",
+				mcp.Description("Title shown above the widget"),
+			),
+			mcp.WithString("subtitle",
+				mcp.Description("Subtitle shown under the title"),
+			),
+			mcp.WithNumber("x",
+				mcp.Description("Grid column of the widget's left edge. Give x, y, w, and h together, or none to let the dashboard place the widget."),
+				mcp.Min(0),
+			),
+			mcp.WithNumber("y",
+				mcp.Description("Grid row of the widget's top edge"),
+				mcp.Min(0),
+			),
+			mcp.WithNumber("w",
+				mcp.Description("Widget width in grid columns"),
+				mcp.Min(1),
+			),
+			mcp.WithNumber("h",
+				mcp.Description("Widget height in grid rows"),
+				mcp.Min(1),
+			),
+		),
+		handleBuildInsightsWidget,
+	)
 }
 
 func handleListDashboards(ctx context.Context, client *hbapi.Client, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -195,9 +253,9 @@ func handleCreateDashboard(ctx context.Context, client *hbapi.Client, req mcp.Ca
 		return mcp.NewToolResultError("widgets is required"), nil
 	}
 
-	var widgets []map[string]interface{}
-	if err := json.Unmarshal([]byte(widgetsJSON), &widgets); err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to parse widgets JSON: %v", err)), nil
+	widgets, errResult := parseWidgets(widgetsJSON)
+	if errResult != nil {
+		return errResult, nil
 	}
 
 	dashboardReq := hbapi.DashboardRequest{
@@ -240,9 +298,9 @@ func handleUpdateDashboard(ctx context.Context, client *hbapi.Client, req mcp.Ca
 		return mcp.NewToolResultError("widgets is required"), nil
 	}
 
-	var widgets []map[string]interface{}
-	if err := json.Unmarshal([]byte(widgetsJSON), &widgets); err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to parse widgets JSON: %v", err)), nil
+	widgets, errResult := parseWidgets(widgetsJSON)
+	if errResult != nil {
+		return errResult, nil
 	}
 
 	dashboardReq := hbapi.DashboardRequest{
@@ -0,0 +1,137 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// defaultProjectResponse is what set_default_project and
+// get_default_project return.
+type defaultProjectResponse struct {
+	// DefaultProject is null when the session has no default project.
+	DefaultProject *projectNameEntry `json:"default_project"`
+
+	// PreviousDefault is the default set_default_project replaced or
+	// cleared, if there was one.
+	PreviousDefault *projectNameEntry `json:"previous_default_project,omitempty"`
+}
+
+// RegisterDefaultProjectTools registers the set_default_project and
+// get_default_project tools
+func RegisterDefaultProjectTools(r *toolRegistrar, clientFor ClientFactory, names *projectNames) {
+	// set_default_project tool
+	r.AddTool(
+		mcp.NewTool("set_default_project",
+			mcp.WithTitleAnnotation("Set Default Project"),
+			mcp.WithDescription("Set the project this session's tool calls use when they're given neither project_id nor project_name, so it needn't be passed on every call. Destructive tools like delete_project still need it passed. Pass clear to remove the default. The default lasts until the session ends and isn't shared with other sessions; it can't be set over stateless http, which has no sessions."),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithOutputSchema[defaultProjectResponse](),
+			mcp.WithNumber("project_id",
+				mcp.Description("The ID of the project to use by default"),
+				mcp.Min(1),
+			),
+			mcp.WithBoolean("clear",
+				mcp.Description("Remove the session's default project instead of setting one"),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			// Stateless http calls all share the session "", so a default
+			// set there would apply to every caller.
+			if sessionIDFromContext(ctx) == "" {
+				return mcp.NewToolResultError("set_default_project needs a session, and this server's http mode is stateless: pass project_id or project_name on each call instead"), nil
+			}
+			projectID := req.GetInt("project_id", 0)
+			if req.GetBool("clear", false) {
+				if projectID != 0 {
+					return mcp.NewToolResultError("Pass project_id or clear, not both"), nil
+				}
+				return defaultProjectResult(defaultProjectResponse{PreviousDefault: names.clearDefault(ctx)})
+			}
+			if projectID == 0 {
+				return mcp.NewToolResultError("project_id or project_name is required"), nil
+			}
+
+			// Fetching the project checks the token can see it, so a wrong
+			// ID fails here instead of on every later call.
+			project, err := clientFor(ctx).Projects.Get(ctx, projectID)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("Failed to get project: %v", err)), nil
+			}
+			entry := projectNameEntry{ID: project.ID, Name: project.Name}
+			return defaultProjectResult(defaultProjectResponse{
+				DefaultProject:  &entry,
+				PreviousDefault: names.setDefault(ctx, entry),
+			})
+		},
+	)
+
+	// get_default_project tool
+	r.AddTool(
+		mcp.NewTool("get_default_project",
+			mcp.WithTitleAnnotation("Get Default Project"),
+			mcp.WithDescription("Get the project set_default_project set for this session, or null if none is set."),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithOutputSchema[defaultProjectResponse](),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			var response defaultProjectResponse
+			if project, ok := names.defaultProject(ctx); ok {
+				response.DefaultProject = &project
+			}
+			return defaultProjectResult(response)
+		},
+	)
+}
+
+func defaultProjectResult(response defaultProjectResponse) (*mcp.CallToolResult, error) {
+	jsonBytes, err := json.Marshal(response)
+	if err != nil {
+		return mcp.NewToolResultError("Failed to marshal response"), nil
+	}
+	return mcp.NewToolResultText(string(jsonBytes)), nil
+}
+
+// defaultProject returns the session's default project, if one is set.
+func (p *projectNames) defaultProject(ctx context.Context) (projectNameEntry, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	project, ok := p.defaults[sessionIDFromContext(ctx)]
+	return project, ok
+}
+
+// setDefault makes project the session's default, returning the one it
+// replaces, if any.
+func (p *projectNames) setDefault(ctx context.Context, project projectNameEntry) *projectNameEntry {
+	sessionID := sessionIDFromContext(ctx)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	previous, ok := p.defaults[sessionID]
+	p.defaults[sessionID] = project
+	if !ok {
+		return nil
+	}
+	return &previous
+}
+
+// clearDefault removes the session's default, returning it, if one was
+// set.
+func (p *projectNames) clearDefault(ctx context.Context) *projectNameEntry {
+	sessionID := sessionIDFromContext(ctx)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	previous, ok := p.defaults[sessionID]
+	delete(p.defaults, sessionID)
+	if !ok {
+		return nil
+	}
+	return &previous
+}
@@ -0,0 +1,127 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestDefaultProject(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		requests []string
+	)
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r.Method+" "+strings.TrimPrefix(r.URL.Path, "/v2"))
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		switch path := strings.TrimPrefix(r.URL.Path, "/v2"); {
+		case path == "/projects":
+			_, _ = w.Write([]byte(`{"results": [{"id": 1, "name": "Shop"}, {"id": 2, "name": "Blog"}], "links": {}}`))
+		case path == "/projects/1":
+			_, _ = w.Write([]byte(`{"id": 1, "name": "Shop"}`))
+		case strings.HasSuffix(path, "/integrations"):
+			_, _ = w.Write([]byte(`[]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors": "Not found"}`))
+		}
+	}))
+	defer api.Close()
+
+	cfg := reloadTestConfig()
+	cfg.APIURL = api.URL
+	cfg.ReadOnly = false
+	s, _, _ := NewReloadableServer(cfg, "test")
+	session := newTestSession(t, s)
+	other := &testSession{id: "other", notifications: make(chan mcp.JSONRPCNotification, 10)}
+	if err := s.RegisterSession(context.Background(), other); err != nil {
+		t.Fatalf("RegisterSession() error = %v", err)
+	}
+
+	call := func(session *testSession, name string, args map[string]any) (*mcp.CallToolResult, []string) {
+		t.Helper()
+		mu.Lock()
+		requests = nil
+		mu.Unlock()
+		params, _ := json.Marshal(map[string]any{"name": name, "arguments": args})
+		msg := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":` + string(params) + `}`
+		ctx := context.Background()
+		if session != nil {
+			ctx = s.WithContext(ctx, session)
+		}
+		resp, ok := s.HandleMessage(ctx, []byte(msg)).(mcp.JSONRPCResponse)
+		if !ok {
+			t.Fatalf("expected JSON-RPC response calling %s", name)
+		}
+		result, ok := resp.Result.(*mcp.CallToolResult)
+		if !ok {
+			t.Fatalf("expected *mcp.CallToolResult, got %T", resp.Result)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		return result, slices.Clone(requests)
+	}
+
+	if result, _ := call(session, "get_default_project", nil); getResultText(result) != `{"default_project":null}` {
+		t.Errorf("expected no default, got %s", getResultText(result))
+	}
+	if result, _ := call(session, "get_project_integrations", nil); !result.IsError || !strings.Contains(getResultText(result), "set a default with set_default_project") {
+		t.Errorf("expected a missing project_id error, got %s", getResultText(result))
+	}
+
+	result, _ := call(session, "set_default_project", map[string]any{"project_name": "shop"})
+	if getResultText(result) != `{"default_project":{"project_id":1,"name":"Shop"}}` {
+		t.Fatalf("expected Shop as the default, got %s", getResultText(result))
+	}
+	for _, tt := range []struct {
+		name string
+		args map[string]any
+		want string
+	}{
+		{"get_project_integrations", nil, "GET /projects/1/integrations"},
+		{"get_project", nil, "GET /projects/1"},
+		{"get_project_integrations", map[string]any{"project_id": 2}, "GET /projects/2/integrations"},
+		{"get_project_integrations", map[string]any{"project_name": "Blog"}, "GET /projects/2/integrations"},
+	} {
+		result, requests := call(session, tt.name, tt.args)
+		if result.IsError || !slices.Contains(requests, tt.want) {
+			t.Errorf("%s %v: expected %s, got %v %s", tt.name, tt.args, tt.want, requests, getResultText(result))
+		}
+	}
+
+	// A destructive call never falls back to the default.
+	if result, requests := call(session, "delete_project", nil); !result.IsError || len(requests) > 0 || strings.Contains(getResultText(result), "set_default_project") {
+		t.Errorf("expected delete_project to need its id, got %v %s", requests, getResultText(result))
+	}
+
+	// Without a session, as over stateless http, there's nothing to keep
+	// a default in.
+	if result, _ := call(nil, "set_default_project", map[string]any{"project_id": 1}); !result.IsError || !strings.Contains(getResultText(result), "needs a session") {
+		t.Errorf("expected set_default_project to need a session, got %s", getResultText(result))
+	}
+
+	// Defaults aren't shared between sessions.
+	if result, _ := call(other, "get_default_project", nil); getResultText(result) != `{"default_project":null}` {
+		t.Errorf("expected no default in another session, got %s", getResultText(result))
+	}
+
+	result, _ = call(session, "set_default_project", map[string]any{"clear": true})
+	if getResultText(result) != `{"default_project":null,"previous_default_project":{"project_id":1,"name":"Shop"}}` {
+		t.Errorf("expected the default cleared, got %s", getResultText(result))
+	}
+	if result, _ := call(session, "set_default_project", map[string]any{"project_id": 9}); !result.IsError || !strings.Contains(getResultText(result), "Failed to get project") {
+		t.Errorf("expected an unknown project to be refused, got %s", getResultText(result))
+	}
+	if result, _ := call(session, "get_default_project", nil); getResultText(result) != `{"default_project":null}` {
+		t.Errorf("expected no default after a refused set, got %s", getResultText(result))
+	}
+}
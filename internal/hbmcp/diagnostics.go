@@ -0,0 +1,249 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/honeybadger-io/honeybadger-mcp-server/internal/buildinfo"
+	"github.com/honeybadger-io/honeybadger-mcp-server/internal/config"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ConnectionReport is the result of CheckConnection, shared by the
+// check_connection tool and the doctor subcommand.
+type ConnectionReport struct {
+	APIURL       string              `json:"api_url"`
+	OK           bool                `json:"ok"`
+	LatencyMS    int64               `json:"latency_ms"`
+	ProjectCount int                 `json:"project_count"`
+	Accounts     []connectionAccount `json:"accounts"`
+	ReadOnly     bool                `json:"read_only"`
+	Error        string              `json:"error,omitempty"`
+	Hints        []string            `json:"hints,omitempty"`
+}
+
+type connectionAccount struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// CheckConnection validates the client's credentials by listing projects
+// (the same call most sessions start with), times that round trip, and lists
+// the accounts the token can see. Only the projects call decides OK; an
+// accounts failure is reported as a hint since some tokens can't list them.
+func CheckConnection(ctx context.Context, client *hbapi.Client, apiURL string, readOnly bool) *ConnectionReport {
+	report := &ConnectionReport{
+		APIURL:   apiURL,
+		ReadOnly: readOnly,
+		Accounts: []connectionAccount{},
+	}
+
+	start := time.Now()
+	projects, err := client.Projects.ListAll(ctx)
+	report.LatencyMS = time.Since(start).Milliseconds()
+	if err != nil {
+		report.Error = err.Error()
+		var apiErr *hbapi.APIError
+		if errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden) {
+			report.Hints = append(report.Hints, "The API rejected the token. Use a personal auth token from your Honeybadger user settings; US and EU tokens only work against their own region's API URL.")
+		} else {
+			report.Hints = append(report.Hints, "The API could not be reached. Check the API URL and your network or proxy settings.")
+		}
+		return report
+	}
+	report.OK = true
+	report.ProjectCount = len(projects.Results)
+
+	accounts, err := client.Accounts.List(ctx)
+	if err != nil {
+		report.Hints = append(report.Hints, fmt.Sprintf("Could not list accounts: %v", err))
+	}
+	for _, a := range accounts {
+		report.Accounts = append(report.Accounts, connectionAccount{ID: a.ID, Name: a.Name})
+	}
+
+	if report.ProjectCount == 0 {
+		report.Hints = append(report.Hints, "The token is valid but has access to no projects.")
+	}
+	if readOnly {
+		report.Hints = append(report.Hints, "Read-only mode is on: tools that create, update, or delete are unavailable.")
+	}
+	return report
+}
+
+// RegisterDiagnosticTools registers the check_connection, whoami, and
+// get_server_info tools
+func RegisterDiagnosticTools(r *toolRegistrar, clientFor ClientFactory, current func() *config.Config) {
+	r.AddTool(
+		mcp.NewTool("check_connection",
+			mcp.WithTitleAnnotation("Check Connection"),
+			mcp.WithDescription("Check that the server can reach the Honeybadger API with its configured credentials. Reports API latency, the accounts and number of projects the token can access, and whether read-only mode is on. Use when other tools fail with authentication or connection errors."),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithOutputSchema[ConnectionReport](),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleCheckConnection(ctx, clientFor(ctx), current())
+		},
+	)
+
+	// whoami tool
+	r.AddTool(
+		mcp.NewTool("whoami",
+			mcp.WithTitleAnnotation("Who Am I"),
+			mcp.WithDescription("Show the identity tool calls act as: the accounts the auth token can access, how the server authenticates, whether write tools are allowed, and, for OAuth tokens over http, the token's subject. Call it before destructive actions to confirm which accounts they affect. The Honeybadger API doesn't expose the token owner's name or email, so they aren't included."),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithOutputSchema[whoamiResponse](),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleWhoami(ctx, clientFor(ctx), current())
+		},
+	)
+
+	// get_server_info tool
+	r.AddTool(
+		mcp.NewTool("get_server_info",
+			mcp.WithTitleAnnotation("Get Server Info"),
+			mcp.WithDescription("Show which build of this MCP server is running and how it is configured: version, git commit, Go version, transport, API URL, and which optional features are on. Use it when a tool is missing or behaves differently than documented, to tell a client/server mismatch from a bug. Makes no API calls."),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithOutputSchema[serverInfo](),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleGetServerInfo(ctx, current())
+		},
+	)
+}
+
+// serverInfo is the get_server_info result.
+type serverInfo struct {
+	buildinfo.Info
+	Transport string         `json:"transport"`
+	APIURL    string         `json:"api_url"`
+	Features  serverFeatures `json:"features"`
+}
+
+// serverFeatures reports the optional behaviour the configuration turns
+// on. Paths and patterns are left out; only whether each is set matters
+// for telling why the server behaves as it does.
+type serverFeatures struct {
+	ReadOnly           bool     `json:"read_only"`
+	ReadOnlyBehavior   string   `json:"read_only_behavior"`
+	ConfirmDestructive bool     `json:"confirm_destructive"`
+	DeferTools         bool     `json:"defer_tools"`
+	ToolSelection      bool     `json:"tool_selection"`
+	ProjectScope       bool     `json:"project_scope"`
+	RawAPI             bool     `json:"raw_api"`
+	NotFoundLookup     bool     `json:"not_found_lookup"`
+	MaxResultTokens    int      `json:"max_result_tokens,omitempty"`
+	Region             string   `json:"region,omitempty"`
+	APIPathPrefix      string   `json:"api_path_prefix,omitempty"`
+	UnsupportedTools   []string `json:"unsupported_tools,omitempty"`
+	AuditLog           bool     `json:"audit_log"`
+	LenientDecoding    bool     `json:"lenient_decoding"`
+	ReferenceCache     bool     `json:"reference_cache"`
+	Fixtures           bool     `json:"fixtures"`
+	ChaosRate          float64  `json:"chaos_rate,omitempty"`
+}
+
+func handleGetServerInfo(ctx context.Context, cfg *config.Config) (*mcp.CallToolResult, error) {
+	behavior := cfg.ReadOnlyBehavior
+	if behavior == "" {
+		behavior = config.ReadOnlyHide
+	}
+	response := serverInfo{
+		Info:      buildinfo.Get(),
+		Transport: CallTransport(ctx, cfg),
+		APIURL:    cfg.APIURL,
+		Features: serverFeatures{
+			ReadOnly:           EffectiveReadOnly(ctx, cfg),
+			ReadOnlyBehavior:   behavior,
+			ConfirmDestructive: cfg.ConfirmDestructive,
+			DeferTools:         cfg.DeferTools,
+			ToolSelection:      len(cfg.EnabledTools) > 0 || len(cfg.DisabledTools) > 0,
+			ProjectScope:       len(cfg.AllowedProjectIDs) > 0 || len(cfg.DeniedProjectIDs) > 0,
+			RawAPI:             cfg.RawAPI,
+			NotFoundLookup:     cfg.NotFoundLookup,
+			MaxResultTokens:    cfg.MaxResultTokens,
+			Region:             cfg.Region,
+			APIPathPrefix:      cfg.APIPathPrefix,
+			UnsupportedTools:   cfg.UnsupportedTools,
+			AuditLog:           cfg.AuditLogPath != "",
+			LenientDecoding:    cfg.LenientDecoding,
+			ReferenceCache:     cfg.ReferenceCacheDir != "",
+			Fixtures:           cfg.FixturesDir != "",
+			ChaosRate:          cfg.ChaosRate,
+		},
+	}
+
+	// Return JSON response
+	jsonBytes, err := json.Marshal(response)
+	if err != nil {
+		return mcp.NewToolResultError("Failed to marshal response"), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonBytes)), nil
+}
+
+// whoamiResponse is the whoami result.
+type whoamiResponse struct {
+	Accounts  []connectionAccount `json:"accounts"`
+	Transport string              `json:"transport"`
+	AuthStyle string              `json:"auth_style"`
+	Subject   string              `json:"subject,omitempty"`
+	Scopes    []string            `json:"scopes,omitempty"`
+	ReadOnly  bool                `json:"read_only"`
+}
+
+func handleWhoami(ctx context.Context, client *hbapi.Client, cfg *config.Config) (*mcp.CallToolResult, error) {
+	accounts, err := client.Accounts.List(ctx)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to list accounts: %v", err)), nil
+	}
+
+	response := whoamiResponse{
+		Accounts:  make([]connectionAccount, 0, len(accounts)),
+		Transport: CallTransport(ctx, cfg),
+		AuthStyle: cfg.AuthStyleFor(CallTransport(ctx, cfg)),
+		ReadOnly:  EffectiveReadOnly(ctx, cfg),
+	}
+	for _, a := range accounts {
+		response.Accounts = append(response.Accounts, connectionAccount{ID: a.ID, Name: a.Name})
+	}
+	if claims := ClaimsFromContext(ctx); claims != nil {
+		response.Subject = claims.Subject
+		response.Scopes = claims.Scopes
+	}
+
+	// Return JSON response
+	jsonBytes, err := json.Marshal(response)
+	if err != nil {
+		return mcp.NewToolResultError("Failed to marshal response"), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonBytes)), nil
+}
+
+func handleCheckConnection(ctx context.Context, client *hbapi.Client, cfg *config.Config) (*mcp.CallToolResult, error) {
+	report := CheckConnection(ctx, client, cfg.APIURL, EffectiveReadOnly(ctx, cfg))
+
+	// Return JSON response
+	jsonBytes, err := json.Marshal(report)
+	if err != nil {
+		return mcp.NewToolResultError("Failed to marshal response"), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonBytes)), nil
+}
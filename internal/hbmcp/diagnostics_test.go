@@ -0,0 +1,187 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/honeybadger-io/honeybadger-mcp-server/internal/buildinfo"
+	"github.com/honeybadger-io/honeybadger-mcp-server/internal/config"
+)
+
+func TestHandleCheckConnection(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v2/projects":
+			_, _ = w.Write([]byte(`{"results": [{"id": 1, "name": "Web"}, {"id": 2, "name": "API"}], "links": {}}`))
+		case "/v2/accounts":
+			_, _ = w.Write([]byte(`{"results": [{"id": "abc123", "name": "Acme"}]}`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+	cfg := &config.Config{APIURL: server.URL, ReadOnly: true, TransportMode: config.TransportStdio}
+
+	result, err := handleCheckConnection(context.Background(), client, cfg)
+	if err != nil {
+		t.Fatalf("handleCheckConnection() error = %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected successful result, got error: %s", getResultText(result))
+	}
+
+	var report ConnectionReport
+	if err := json.Unmarshal([]byte(getResultText(result)), &report); err != nil {
+		t.Fatalf("failed to unmarshal report: %v", err)
+	}
+	if !report.OK {
+		t.Errorf("expected ok report, got error %q", report.Error)
+	}
+	if report.ProjectCount != 2 {
+		t.Errorf("expected 2 projects, got %d", report.ProjectCount)
+	}
+	if len(report.Accounts) != 1 || report.Accounts[0].Name != "Acme" {
+		t.Errorf("unexpected accounts: %+v", report.Accounts)
+	}
+	if !report.ReadOnly {
+		t.Error("expected read-only to be reported")
+	}
+	if report.APIURL != server.URL {
+		t.Errorf("expected api_url %s, got %s", server.URL, report.APIURL)
+	}
+}
+
+func TestCheckConnection_Unauthorized(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"errors": "Invalid token"}`))
+	}))
+	defer server.Close()
+
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("bad-token")
+	report := CheckConnection(context.Background(), client, server.URL, false)
+
+	if report.OK {
+		t.Fatal("expected failed report")
+	}
+	if !strings.Contains(report.Error, "401") {
+		t.Errorf("expected 401 in error, got %q", report.Error)
+	}
+	if len(report.Hints) == 0 || !strings.Contains(report.Hints[0], "rejected the token") {
+		t.Errorf("expected token hint, got %v", report.Hints)
+	}
+}
+
+func TestCheckConnection_AccountsFailureIsNotFatal(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v2/accounts" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"results": [], "links": {}}`))
+	}))
+	defer server.Close()
+
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+	report := CheckConnection(context.Background(), client, server.URL, false)
+
+	if !report.OK {
+		t.Fatalf("accounts failure should not fail the check, got %q", report.Error)
+	}
+	hints := strings.Join(report.Hints, "\n")
+	if !strings.Contains(hints, "Could not list accounts") {
+		t.Errorf("expected accounts hint, got %v", report.Hints)
+	}
+	if !strings.Contains(hints, "no projects") {
+		t.Errorf("expected no-projects hint, got %v", report.Hints)
+	}
+}
+
+func TestHandleWhoami(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"results": [{"id": "abc123", "name": "Acme"}, {"id": "def456", "name": "Side Project"}]}`))
+	}))
+	defer server.Close()
+
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+
+	t.Run("stdio", func(t *testing.T) {
+		cfg := &config.Config{ReadOnly: true, TransportMode: config.TransportStdio}
+		result, err := handleWhoami(context.Background(), client, cfg)
+		if err != nil {
+			t.Fatalf("handleWhoami() error = %v", err)
+		}
+		var response whoamiResponse
+		if err := json.Unmarshal([]byte(getResultText(result)), &response); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		if len(response.Accounts) != 2 || response.Accounts[1].Name != "Side Project" {
+			t.Errorf("unexpected accounts: %+v", response.Accounts)
+		}
+		if !response.ReadOnly || response.AuthStyle != config.AuthStyleBasicUsername || response.Subject != "" {
+			t.Errorf("unexpected response: %+v", response)
+		}
+	})
+
+	t.Run("http", func(t *testing.T) {
+		cfg := &config.Config{TransportMode: config.TransportHTTP}
+		ctx := WithClaims(context.Background(), &Claims{Scopes: []string{"read", "write"}, Subject: "user-42"})
+		result, err := handleWhoami(ctx, client, cfg)
+		if err != nil {
+			t.Fatalf("handleWhoami() error = %v", err)
+		}
+		var response whoamiResponse
+		if err := json.Unmarshal([]byte(getResultText(result)), &response); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		if response.Subject != "user-42" || response.ReadOnly || response.AuthStyle != config.AuthStyleBearer {
+			t.Errorf("unexpected response: %+v", response)
+		}
+	})
+}
+
+func TestHandleGetServerInfo(t *testing.T) {
+	orig := buildinfo.Version
+	t.Cleanup(func() { buildinfo.Version = orig })
+	buildinfo.Version = "1.4.0"
+
+	cfg := &config.Config{
+		APIURL:             "https://eu-api.honeybadger.io/v2",
+		TransportMode:      config.TransportStdio,
+		ReadOnly:           true,
+		ConfirmDestructive: true,
+		AllowedProjectIDs:  []int{42},
+		AuditLogPath:       "/var/log/hb/audit.jsonl",
+	}
+	result, err := handleGetServerInfo(context.Background(), cfg)
+	if err != nil || result.IsError {
+		t.Fatalf("unexpected error: %v %s", err, getResultText(result))
+	}
+	text := getResultText(result)
+	if strings.Contains(text, "audit.jsonl") {
+		t.Errorf("expected paths left out, got %s", text)
+	}
+	var response serverInfo
+	if err := json.Unmarshal([]byte(text), &response); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if response.Version != "1.4.0" || response.GoVersion == "" || response.APIURL != cfg.APIURL || response.Transport != config.TransportStdio {
+		t.Errorf("unexpected build or connection info: %+v", response)
+	}
+	want := serverFeatures{ReadOnly: true, ReadOnlyBehavior: config.ReadOnlyHide, ConfirmDestructive: true, ProjectScope: true, AuditLog: true}
+	if !reflect.DeepEqual(response.Features, want) {
+		t.Errorf("features = %+v, want %+v", response.Features, want)
+	}
+}
@@ -0,0 +1,248 @@
+package hbmcp
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	defaultDigestWindow    = 7 * 24 * time.Hour
+	defaultDigestTopFaults = 5
+	// maxDigestClasses is how many error classes each project's digest
+	// lists; the rest are summed into other_notices.
+	maxDigestClasses = 10
+	// maxDigestMessage is how long a top fault's message may be.
+	maxDigestMessage = 200
+	// digestConcurrency bounds how many projects are fetched at once.
+	digestConcurrency = 4
+)
+
+// errorDigest is the result of generate_error_digest.
+type errorDigest struct {
+	Start        time.Time       `json:"start"`
+	Stop         time.Time       `json:"stop"`
+	Environment  string          `json:"environment,omitempty"`
+	TotalNotices int             `json:"total_notices"`
+	Projects     []projectDigest `json:"projects"`
+}
+
+// projectDigest is one project's section of a digest.
+type projectDigest struct {
+	ProjectID      int           `json:"project_id"`
+	ProjectName    string        `json:"project_name"`
+	TotalNotices   int           `json:"total_notices"`
+	NoticesPerDay  []digestCount `json:"notices_per_day"`
+	NoticesByClass []digestCount `json:"notices_by_class"`
+	OtherNotices   int           `json:"other_notices,omitempty"`
+	TopFaults      []digestFault `json:"top_unresolved_faults"`
+	Errors         []string      `json:"errors,omitempty"`
+}
+
+// digestCount is a labeled count: a day or an error class.
+type digestCount struct {
+	Label string `json:"label"`
+	Count int    `json:"count"`
+}
+
+type digestFault struct {
+	ID      int    `json:"id"`
+	Klass   string `json:"klass"`
+	Message string `json:"message"`
+	Notices int    `json:"notices"`
+	URL     string `json:"url"`
+}
+
+func handleGenerateErrorDigest(ctx context.Context, client *hbapi.Client, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	now := time.Now()
+	window, err := resolveTimeWindow(req, "start", "stop", now, time.UTC)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	var notes toolNotes
+	notes.addResolved(window.Resolved)
+	if window.Before.IsZero() {
+		window.Before = now
+	}
+	if window.After.IsZero() {
+		window.After = window.Before.Add(-defaultDigestWindow)
+	}
+	if !window.After.Before(window.Before) {
+		return mcp.NewToolResultError("start must be before stop"), nil
+	}
+
+	top := req.GetInt("top_faults", defaultDigestTopFaults)
+	if top > maxPageLimit {
+		notes.warnf("top_faults capped at %d (requested %d)", maxPageLimit, top)
+		top = maxPageLimit
+	}
+	if top < 1 {
+		return mcp.NewToolResultError("top_faults must be at least 1"), nil
+	}
+
+	var projects []hbapi.Project
+	if projectID := req.GetInt("project_id", 0); projectID != 0 {
+		project, err := client.Projects.Get(ctx, projectID)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to get project: %v", err)), nil
+		}
+		projects = append(projects, *project)
+	} else {
+		var response *hbapi.ProjectsResponse
+		if accountID := req.GetString("account_id", ""); accountID != "" {
+			response, err = client.Projects.ListByAccountID(ctx, accountID)
+		} else {
+			response, err = client.Projects.ListAll(ctx)
+		}
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to list projects: %v", err)), nil
+		}
+		if response.Links.Next != "" {
+			notes.warnf("only the first %d projects are in the digest; pass account_id to narrow the list", len(response.Results))
+		}
+		for _, p := range response.Results {
+			if projectAllowed(ctx, p.ID) {
+				projects = append(projects, p)
+			}
+		}
+	}
+
+	environment := req.GetString("environment", "")
+	digest := errorDigest{
+		Start:       window.After,
+		Stop:        window.Before,
+		Environment: environment,
+		Projects:    make([]projectDigest, len(projects)),
+	}
+	options := hbapi.ProjectGetReportOptions{Start: &window.After, Stop: &window.Before, Environment: environment}
+	q := "-is:resolved -is:ignored"
+	if environment != "" {
+		q += fmt.Sprintf(" environment:%q", environment)
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, digestConcurrency)
+	for i, p := range projects {
+		wg.Go(func() {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			digest.Projects[i] = buildProjectDigest(ctx, client, p, options, q, window, top)
+		})
+	}
+	wg.Wait()
+
+	failed := 0
+	for i := range digest.Projects {
+		d := &digest.Projects[i]
+		digest.TotalNotices += d.TotalNotices
+		if len(d.Errors) > 0 {
+			failed++
+		}
+	}
+	if failed > 0 && failed == len(projects) {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to build the digest: %s", digest.Projects[0].Errors[0])), nil
+	}
+	if failed > 0 {
+		notes.warnf("%d of %d projects are incomplete; see their errors", failed, len(projects))
+	}
+	slices.SortStableFunc(digest.Projects, func(a, b projectDigest) int {
+		return cmp.Or(cmp.Compare(b.TotalNotices, a.TotalNotices), cmp.Compare(a.ProjectID, b.ProjectID))
+	})
+
+	// Return JSON response
+	jsonBytes, err := json.Marshal(digest)
+	if err != nil {
+		return mcp.NewToolResultError("Failed to marshal response"), nil
+	}
+
+	return withNotes(mcp.NewToolResultText(string(jsonBytes)), &notes), nil
+}
+
+// buildProjectDigest fetches one project's reports and top faults. A
+// failed part is recorded in the digest's errors rather than dropping
+// the project.
+func buildProjectDigest(ctx context.Context, client *hbapi.Client, project hbapi.Project, options hbapi.ProjectGetReportOptions, q string, window timeWindow, top int) projectDigest {
+	d := projectDigest{
+		ProjectID:      project.ID,
+		ProjectName:    project.Name,
+		NoticesPerDay:  []digestCount{},
+		NoticesByClass: []digestCount{},
+		TopFaults:      []digestFault{},
+	}
+
+	if rows, err := client.Projects.GetReport(ctx, project.ID, hbapi.ProjectNoticesPerDay, options); err != nil {
+		d.Errors = append(d.Errors, fmt.Sprintf("notices_per_day: %v", err))
+	} else {
+		for _, c := range digestCounts(rows) {
+			if t, err := time.Parse(time.RFC3339Nano, c.Label); err == nil {
+				c.Label = t.Format("2006-01-02")
+			}
+			d.NoticesPerDay = append(d.NoticesPerDay, c)
+			d.TotalNotices += c.Count
+		}
+	}
+
+	if rows, err := client.Projects.GetReport(ctx, project.ID, hbapi.ProjectNoticesByClass, options); err != nil {
+		d.Errors = append(d.Errors, fmt.Sprintf("notices_by_class: %v", err))
+	} else {
+		classes := digestCounts(rows)
+		slices.SortStableFunc(classes, func(a, b digestCount) int { return cmp.Compare(b.Count, a.Count) })
+		for i, c := range classes {
+			if i < maxDigestClasses {
+				d.NoticesByClass = append(d.NoticesByClass, c)
+			} else {
+				d.OtherNotices += c.Count
+			}
+		}
+	}
+
+	faults, err := client.Faults.List(ctx, project.ID, hbapi.FaultListOptions{
+		Q:              q,
+		OccurredAfter:  window.After,
+		OccurredBefore: window.Before,
+		Order:          "frequent",
+		Limit:          top,
+	})
+	if err != nil {
+		d.Errors = append(d.Errors, fmt.Sprintf("top_unresolved_faults: %v", err))
+		return d
+	}
+	for _, f := range faults.Results {
+		notices := f.NoticesCount
+		if f.NoticesCountInRange != nil {
+			notices = *f.NoticesCountInRange
+		}
+		d.TopFaults = append(d.TopFaults, digestFault{
+			ID:      f.ID,
+			Klass:   f.Klass,
+			Message: truncateLabel(f.Message, maxDigestMessage),
+			Notices: notices,
+			URL:     f.URL,
+		})
+	}
+	return d
+}
+
+// digestCounts converts report rows, [label, count] pairs, to counts,
+// skipping rows of any other shape.
+func digestCounts(rows [][]interface{}) []digestCount {
+	counts := make([]digestCount, 0, len(rows))
+	for _, row := range rows {
+		if len(row) < 2 {
+			continue
+		}
+		count, ok := row[1].(float64)
+		if !ok {
+			continue
+		}
+		counts = append(counts, digestCount{Label: fmt.Sprint(row[0]), Count: int(count)})
+	}
+	return counts
+}
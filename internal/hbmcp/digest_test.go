@@ -0,0 +1,105 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func digestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v2/projects":
+			_, _ = w.Write([]byte(`{"results": [{"id": 1, "name": "Shop"}, {"id": 2, "name": "Admin"}], "links": {}}`))
+		case "/v2/projects/1":
+			_, _ = w.Write([]byte(`{"id": 1, "name": "Shop"}`))
+		case "/v2/projects/1/reports/notices_per_day":
+			if r.URL.Query().Get("start") == "" || r.URL.Query().Get("stop") == "" {
+				t.Errorf("expected a bounded period, got %q", r.URL.RawQuery)
+			}
+			_, _ = w.Write([]byte(`[["2024-03-14T00:00:00.000000Z", 4], ["2024-03-15T00:00:00.000000Z", 6]]`))
+		case "/v2/projects/1/reports/notices_by_class":
+			_, _ = w.Write([]byte(`[["NoMethodError", 3], ["RuntimeError", 7]]`))
+		case "/v2/projects/1/faults":
+			if q := r.URL.Query().Get("q"); q != `-is:resolved -is:ignored environment:"production"` {
+				t.Errorf("unexpected q %q", q)
+			}
+			_, _ = w.Write([]byte(`{"results": [{"id": 10, "klass": "RuntimeError", "message": "boom", "notices_count": 50, "notices_count_in_range": 7}], "links": {}}`))
+		case "/v2/projects/2/reports/notices_per_day", "/v2/projects/2/reports/notices_by_class", "/v2/projects/2/faults":
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors": "forbidden"}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestHandleGenerateErrorDigest(t *testing.T) {
+	server := digestServer(t)
+	defer server.Close()
+
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"project_id": float64(1), "environment": "production"}
+
+	result, err := handleGenerateErrorDigest(context.Background(), client, req)
+	if err != nil || result.IsError {
+		t.Fatalf("unexpected error: %v %s", err, getResultText(result))
+	}
+	var digest errorDigest
+	if err := json.Unmarshal([]byte(getResultText(result)), &digest); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if got := digest.Stop.Sub(digest.Start); got != defaultDigestWindow {
+		t.Errorf("expected the default 7 day period, got %v", got)
+	}
+	if len(digest.Projects) != 1 || digest.TotalNotices != 10 {
+		t.Fatalf("unexpected digest: %+v", digest)
+	}
+	p := digest.Projects[0]
+	if len(p.NoticesPerDay) != 2 || p.NoticesPerDay[0] != (digestCount{Label: "2024-03-14", Count: 4}) {
+		t.Errorf("unexpected notices_per_day: %+v", p.NoticesPerDay)
+	}
+	if len(p.NoticesByClass) != 2 || p.NoticesByClass[0].Label != "RuntimeError" {
+		t.Errorf("expected classes by notices, got %+v", p.NoticesByClass)
+	}
+	if len(p.TopFaults) != 1 || p.TopFaults[0].Notices != 7 {
+		t.Errorf("expected notices in the period, got %+v", p.TopFaults)
+	}
+}
+
+func TestHandleGenerateErrorDigest_AllProjects(t *testing.T) {
+	server := digestServer(t)
+	defer server.Close()
+
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"environment": "production"}
+
+	result, err := handleGenerateErrorDigest(context.Background(), client, req)
+	if err != nil || result.IsError {
+		t.Fatalf("unexpected error: %v %s", err, getResultText(result))
+	}
+	var digest errorDigest
+	if err := json.Unmarshal([]byte(getResultText(result)), &digest); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if len(digest.Projects) != 2 || digest.Projects[0].ProjectID != 1 {
+		t.Fatalf("expected both projects, busiest first, got %+v", digest.Projects)
+	}
+	if errs := digest.Projects[1].Errors; len(errs) != 3 {
+		t.Errorf("expected the failed project's errors, got %v", errs)
+	}
+	if notes := getResultNotes(t, result); len(notes.Warnings) != 1 || !strings.Contains(notes.Warnings[0], "1 of 2 projects") {
+		t.Errorf("unexpected warnings: %v", notes.Warnings)
+	}
+}
@@ -0,0 +1,271 @@
+package hbmcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/honeybadger-io/honeybadger-mcp-server/internal/config"
+)
+
+const (
+	exportFormatNDJSON = "ndjson"
+	exportFormatCSV    = "csv"
+
+	maxExportNotices = 10000
+)
+
+// defaultExportColumns are the CSV columns used when none are given: the
+// fields most analyses group or filter by.
+var defaultExportColumns = []string{
+	"id",
+	"created_at",
+	"environment_name",
+	"message",
+	"url",
+	"request.component",
+	"request.action",
+	"environment.hostname",
+	"environment.revision",
+}
+
+// noticeExport is the result of export_fault_notices. The notices
+// themselves are only in the file.
+type noticeExport struct {
+	Path    string   `json:"path"`
+	Format  string   `json:"format"`
+	Rows    int      `json:"rows"`
+	Bytes   int64    `json:"bytes"`
+	Columns []string `json:"columns,omitempty"`
+}
+
+// RegisterExportTools registers the export_fault_notices tool
+func RegisterExportTools(r *toolRegistrar, clientFor ClientFactory, current func() *config.Config) {
+	// export_fault_notices tool
+	r.AddTool(
+		mcp.NewTool("export_fault_notices",
+			mcp.WithTitleAnnotation("Export Fault Notices"),
+			mcp.WithDescription("Write a fault's notices, newest first and following pagination, to a new local file as NDJSON (one notice per line) or CSV, and return the path and row count. Use this to hand large sets of notices to other analysis tools instead of reading them through list_fault_notices. Only available when the server runs over stdio, since the file is written on the server's machine."),
+			mcp.WithReadOnlyHintAnnotation(false),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(false),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithNumber("project_id",
+				mcp.Required(),
+				mcp.Description("The ID of the project containing the fault"),
+				mcp.Min(1),
+			),
+			mcp.WithNumber("fault_id",
+				mcp.Required(),
+				mcp.Description("The ID of the fault to export notices for"),
+				mcp.Min(1),
+			),
+			mcp.WithString("path",
+				mcp.Required(),
+				mcp.Description("Absolute path of the file to create. Its directory must exist, and an existing file is never overwritten."),
+			),
+			mcp.WithString("format",
+				mcp.Description("File format: 'ndjson' writes each notice as a JSON object per line; 'csv' writes the chosen columns with a header row. Defaults to csv for paths ending in .csv, otherwise ndjson."),
+				mcp.Enum(exportFormatNDJSON, exportFormatCSV),
+			),
+			mcp.WithArray("columns",
+				mcp.WithStringItems(),
+				mcp.Description("CSV only: dot-separated key paths to write as columns, e.g. 'request.params.id' or 'backtrace.0.file'. Defaults to "+strings.Join(defaultExportColumns, ", ")+". Objects are written as JSON; missing keys as empty cells."),
+			),
+			mcp.WithString("created_after",
+				mcp.Description("Only include notices created after this timestamp"+timestampHint),
+			),
+			mcp.WithString("created_before",
+				mcp.Description("Only include notices created before this timestamp"+timestampHint),
+			),
+			mcp.WithNumber("max_notices",
+				mcp.Description("Maximum number of notices to export (default and max 10000)"),
+				mcp.Min(1),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if CallTransport(ctx, current()) == config.TransportHTTP {
+				return mcp.NewToolResultError("export_fault_notices writes to the server's filesystem, so it is only available over stdio"), nil
+			}
+			return handleExportFaultNotices(ctx, clientFor(ctx), req)
+		},
+	)
+}
+
+func handleExportFaultNotices(ctx context.Context, client *hbapi.Client, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	projectID := req.GetInt("project_id", 0)
+	if projectID == 0 {
+		return mcp.NewToolResultError("project_id is required"), nil
+	}
+
+	faultID := req.GetInt("fault_id", 0)
+	if faultID == 0 {
+		return mcp.NewToolResultError("fault_id is required"), nil
+	}
+
+	path := req.GetString("path", "")
+	if path == "" {
+		return mcp.NewToolResultError("path is required"), nil
+	}
+	if !filepath.IsAbs(path) {
+		return mcp.NewToolResultError(fmt.Sprintf("path must be absolute, got %q", path)), nil
+	}
+
+	format := req.GetString("format", "")
+	if format == "" {
+		format = exportFormatNDJSON
+		if strings.EqualFold(filepath.Ext(path), ".csv") {
+			format = exportFormatCSV
+		}
+	}
+	if format != exportFormatNDJSON && format != exportFormatCSV {
+		return mcp.NewToolResultError(fmt.Sprintf("format must be %s or %s, got %q", exportFormatNDJSON, exportFormatCSV, format)), nil
+	}
+
+	var columns []string
+	if format == exportFormatCSV {
+		columns = defaultExportColumns
+		if requested := req.GetStringSlice("columns", nil); len(requested) > 0 {
+			columns = make([]string, 0, len(requested))
+			for _, c := range requested {
+				if c = strings.Trim(strings.TrimSpace(c), "."); c != "" {
+					columns = append(columns, c)
+				}
+			}
+			if len(columns) == 0 {
+				return mcp.NewToolResultError("columns must contain at least one key path"), nil
+			}
+		}
+	}
+
+	created, err := resolveTimeWindow(req, "created_after", "created_before", time.Now(), time.UTC)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	var notes toolNotes
+	notes.addResolved(created.Resolved)
+
+	maxNotices := req.GetInt("max_notices", maxExportNotices)
+	if maxNotices > maxExportNotices {
+		notes.warnf("max_notices capped at %d (requested %d)", maxExportNotices, maxNotices)
+		maxNotices = maxExportNotices
+	}
+	if maxNotices < 1 {
+		return mcp.NewToolResultError("max_notices must be at least 1"), nil
+	}
+
+	// O_EXCL so an agent can't clobber an existing file by picking its name.
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to create export file: %v", err)), nil
+	}
+	w, writeErr := newNoticeWriter(bufio.NewWriter(file), format, columns)
+	if writeErr != nil {
+		_ = file.Close()
+		_ = os.Remove(path)
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to write export file: %v", writeErr)), nil
+	}
+
+	export := noticeExport{Path: path, Format: format, Columns: columns}
+	exhausted, err := walkFaultNotices(ctx, client, projectID, faultID, created, maxNotices, func(n hbapi.Notice) error {
+		if writeErr = w.write(n); writeErr == nil {
+			export.Rows++
+		}
+		return writeErr
+	})
+	if err != nil && writeErr == nil && export.Rows == 0 {
+		_ = file.Close()
+		_ = os.Remove(path)
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to list fault notices: %v", err)), nil
+	}
+	if writeErr == nil {
+		writeErr = w.flush()
+	}
+	if closeErr := file.Close(); writeErr == nil {
+		writeErr = closeErr
+	}
+	if writeErr != nil {
+		_ = os.Remove(path)
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to write export file: %v", writeErr)), nil
+	}
+	if err != nil {
+		notes.warnf("stopped after %d notices: %v; the file holds the notices exported so far", export.Rows, err)
+	} else if !exhausted {
+		notes.warnf("exported the newest %d notices; more exist. Raise max_notices (up to %d) or narrow the time range", export.Rows, maxExportNotices)
+	}
+	if info, err := os.Stat(path); err == nil {
+		export.Bytes = info.Size()
+	}
+
+	// Return JSON response
+	jsonBytes, err := json.Marshal(export)
+	if err != nil {
+		return mcp.NewToolResultError("Failed to marshal response"), nil
+	}
+
+	return withNotes(mcp.NewToolResultText(string(jsonBytes)), &notes), nil
+}
+
+// noticeWriter writes notices in one export format.
+type noticeWriter struct {
+	out     *bufio.Writer
+	csv     *csv.Writer
+	columns []string
+}
+
+// newNoticeWriter starts an export, writing the header row for CSV so even
+// an export of no notices is a valid file.
+func newNoticeWriter(out *bufio.Writer, format string, columns []string) (*noticeWriter, error) {
+	w := &noticeWriter{out: out, columns: columns}
+	if format == exportFormatCSV {
+		w.csv = csv.NewWriter(out)
+		if err := w.csv.Write(columns); err != nil {
+			return nil, err
+		}
+	}
+	return w, nil
+}
+
+func (w *noticeWriter) write(n hbapi.Notice) error {
+	raw, err := json.Marshal(n)
+	if err != nil {
+		return fmt.Errorf("notice %s: %w", n.ID, err)
+	}
+	if w.csv == nil {
+		if _, err := w.out.Write(raw); err != nil {
+			return err
+		}
+		return w.out.WriteByte('\n')
+	}
+
+	var doc any
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return fmt.Errorf("notice %s: %w", n.ID, err)
+	}
+	row := make([]string, len(w.columns))
+	for i, c := range w.columns {
+		if v, ok := lookupKeyPath(doc, c); ok {
+			row[i] = aggregateValue(v)
+		}
+	}
+	return w.csv.Write(row)
+}
+
+func (w *noticeWriter) flush() error {
+	if w.csv != nil {
+		w.csv.Flush()
+		if err := w.csv.Error(); err != nil {
+			return err
+		}
+	}
+	return w.out.Flush()
+}
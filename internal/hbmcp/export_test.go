@@ -0,0 +1,175 @@
+package hbmcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func exportRequest(args map[string]interface{}) mcp.CallToolRequest {
+	base := map[string]interface{}{"project_id": 1, "fault_id": 2}
+	for k, v := range args {
+		base[k] = v
+	}
+	return mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: base}}
+}
+
+func runExport(t *testing.T, client *hbapi.Client, args map[string]interface{}) (*mcp.CallToolResult, noticeExport) {
+	t.Helper()
+	result, err := handleExportFaultNotices(context.Background(), client, exportRequest(args))
+	if err != nil {
+		t.Fatalf("handleExportFaultNotices() error = %v", err)
+	}
+	var export noticeExport
+	if !result.IsError {
+		if err := json.Unmarshal([]byte(getResultText(result)), &export); err != nil {
+			t.Fatalf("failed to unmarshal result: %v", err)
+		}
+	}
+	return result, export
+}
+
+func TestHandleExportFaultNotices_NDJSON(t *testing.T) {
+	server := noticesServer(t, 60, func(i int) string { return fmt.Sprintf("web-%d", i%2) })
+	defer server.Close()
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+
+	path := filepath.Join(t.TempDir(), "notices.ndjson")
+	result, export := runExport(t, client, map[string]interface{}{"path": path})
+	if result.IsError {
+		t.Fatalf("expected success, got %s", getResultText(result))
+	}
+	if export.Format != exportFormatNDJSON || export.Rows != 60 || export.Bytes == 0 {
+		t.Errorf("unexpected export summary: %+v", export)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	lines := 0
+	ids := map[string]bool{}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		var n hbapi.Notice
+		if err := json.Unmarshal(scanner.Bytes(), &n); err != nil {
+			t.Fatalf("line %d is not a notice: %v", lines+1, err)
+		}
+		ids[n.ID] = true
+		lines++
+	}
+	if lines != 60 || len(ids) != 60 {
+		t.Errorf("expected 60 distinct notices across pages, got %d lines and %d ids", lines, len(ids))
+	}
+}
+
+func TestHandleExportFaultNotices_CSV(t *testing.T) {
+	server := noticesServer(t, 3, func(i int) string { return fmt.Sprintf("web-%d", i) })
+	defer server.Close()
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+
+	path := filepath.Join(t.TempDir(), "notices.csv")
+	result, export := runExport(t, client, map[string]interface{}{
+		"path":    path,
+		"columns": []interface{}{"id", "environment.hostname", "request.params.missing"},
+	})
+	if result.IsError {
+		t.Fatalf("expected success, got %s", getResultText(result))
+	}
+	if export.Format != exportFormatCSV || export.Rows != 3 {
+		t.Errorf("expected 3 csv rows inferred from the extension, got %+v", export)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		{"id", "environment.hostname", "request.params.missing"},
+		{"n0", "web-0", ""},
+		{"n1", "web-1", ""},
+		{"n2", "web-2", ""},
+	}
+	if fmt.Sprint(records) != fmt.Sprint(want) {
+		t.Errorf("expected %v, got %v", want, records)
+	}
+}
+
+func TestHandleExportFaultNotices_MaxNotices(t *testing.T) {
+	server := noticesServer(t, 60, func(int) string { return "web" })
+	defer server.Close()
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+
+	path := filepath.Join(t.TempDir(), "notices.ndjson")
+	result, export := runExport(t, client, map[string]interface{}{"path": path, "max_notices": 30})
+	if result.IsError {
+		t.Fatalf("expected success, got %s", getResultText(result))
+	}
+	if export.Rows != 30 {
+		t.Errorf("expected 30 rows, got %d", export.Rows)
+	}
+	notes := getResultNotes(t, result)
+	if len(notes.Warnings) != 1 || !strings.Contains(notes.Warnings[0], "more exist") {
+		t.Errorf("expected a warning that more notices exist, got %v", notes.Warnings)
+	}
+}
+
+func TestHandleExportFaultNotices_Errors(t *testing.T) {
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"errors":"Not found"}`))
+	}))
+	defer failing.Close()
+	client := hbapi.NewClient().WithBaseURL(failing.URL).WithAuthToken("test-token")
+
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "existing.ndjson")
+	if err := os.WriteFile(existing, []byte("keep me"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name    string
+		args    map[string]interface{}
+		wantErr string
+	}{
+		{"relative path", map[string]interface{}{"path": "notices.ndjson"}, "path must be absolute"},
+		{"existing file", map[string]interface{}{"path": existing}, "Failed to create export file"},
+		{"missing directory", map[string]interface{}{"path": filepath.Join(dir, "missing", "n.ndjson")}, "Failed to create export file"},
+		{"bad format", map[string]interface{}{"path": filepath.Join(dir, "n.txt"), "format": "xml"}, "format must be"},
+		{"api error", map[string]interface{}{"path": filepath.Join(dir, "failed.ndjson")}, "Failed to list fault notices"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			result, _ := runExport(t, client, c.args)
+			if !result.IsError || !strings.Contains(getResultText(result), c.wantErr) {
+				t.Errorf("expected error containing %q, got %s", c.wantErr, getResultText(result))
+			}
+		})
+	}
+
+	if b, _ := os.ReadFile(existing); string(b) != "keep me" {
+		t.Errorf("existing file was modified: %q", b)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "failed.ndjson")); !os.IsNotExist(err) {
+		t.Errorf("expected the failed export's file to be removed, got %v", err)
+	}
+}
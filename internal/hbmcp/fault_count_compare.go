@@ -0,0 +1,165 @@
+package hbmcp
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// faultCountComparison is the compare_fault_counts response: how many
+// faults occurred in a baseline range and a current range, in total and
+// per environment.
+type faultCountComparison struct {
+	Verdict      string            `json:"verdict"`
+	Baseline     faultCountWindow  `json:"baseline"`
+	Current      faultCountWindow  `json:"current"`
+	Total        faultCountDelta   `json:"total"`
+	Environments []faultCountDelta `json:"environments"`
+}
+
+type faultCountWindow struct {
+	After  time.Time `json:"after"`
+	Before time.Time `json:"before"`
+}
+
+// faultCountDelta compares one count across the two ranges. ChangePercent
+// is omitted when the baseline is zero.
+type faultCountDelta struct {
+	Environment   string   `json:"environment,omitempty"`
+	Baseline      int      `json:"baseline"`
+	Current       int      `json:"current"`
+	Delta         int      `json:"delta"`
+	ChangePercent *float64 `json:"change_percent,omitempty"`
+}
+
+func newFaultCountDelta(env string, baseline, current int) faultCountDelta {
+	d := faultCountDelta{Environment: env, Baseline: baseline, Current: current, Delta: current - baseline}
+	if baseline > 0 {
+		change := math.Round(float64(d.Delta)*1000/float64(baseline)) / 10
+		d.ChangePercent = &change
+	}
+	return d
+}
+
+// verdict sums up the total change in one line.
+func (d faultCountDelta) verdict() string {
+	switch {
+	case d.Delta == 0:
+		return fmt.Sprintf("unchanged: %d faults in both ranges", d.Current)
+	case d.Baseline == 0:
+		return fmt.Sprintf("up from none: %d faults vs 0", d.Current)
+	case d.Delta > 0:
+		return fmt.Sprintf("up %g%%: %d faults vs %d", *d.ChangePercent, d.Current, d.Baseline)
+	default:
+		return fmt.Sprintf("down %g%%: %d faults vs %d", -*d.ChangePercent, d.Current, d.Baseline)
+	}
+}
+
+// faultCountsByEnvironment sums counts across the resolved and ignored
+// groups the API splits each environment into.
+func faultCountsByEnvironment(counts *hbapi.FaultCounts) map[string]int {
+	byEnv := map[string]int{}
+	for _, env := range counts.Environments {
+		byEnv[env.Environment] += env.Count
+	}
+	return byEnv
+}
+
+func handleCompareFaultCounts(ctx context.Context, client *hbapi.Client, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return compareFaultCounts(ctx, client, req, time.Now())
+}
+
+func compareFaultCounts(ctx context.Context, client *hbapi.Client, req mcp.CallToolRequest, now time.Time) (*mcp.CallToolResult, error) {
+	projectID := req.GetInt("project_id", 0)
+	if projectID == 0 {
+		return mcp.NewToolResultError("project_id is required"), nil
+	}
+
+	loc, err := timezoneArg(req)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	current, err := resolveTimeWindow(req, "current_after", "current_before", now, loc)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if current.After.IsZero() {
+		return mcp.NewToolResultError("current_after is required"), nil
+	}
+	if current.Before.IsZero() {
+		current.Before = now
+	}
+	baseline, err := resolveTimeWindow(req, "baseline_after", "baseline_before", now, loc)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	// By default the baseline is the span of the same length just before
+	// the current range.
+	if baseline.Before.IsZero() {
+		baseline.Before = current.After
+	}
+	if baseline.After.IsZero() {
+		baseline.After = baseline.Before.Add(-current.Before.Sub(current.After))
+	}
+	if !baseline.Before.After(baseline.After) {
+		return mcp.NewToolResultError("baseline_before must be after baseline_after"), nil
+	}
+
+	q, err := faultQueryArg(req)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	var notes toolNotes
+	notes.addResolved(baseline.Resolved, current.Resolved)
+	baselineSpan, currentSpan := baseline.Before.Sub(baseline.After), current.Before.Sub(current.After)
+	if baselineSpan.Round(time.Minute) != currentSpan.Round(time.Minute) {
+		notes.warnf("the ranges differ in length (%s baseline vs %s current), so their counts aren't directly comparable", formatSpan(baselineSpan.Round(time.Minute)), formatSpan(currentSpan.Round(time.Minute)))
+	}
+
+	baselineCounts, err := client.Faults.GetCounts(ctx, projectID, hbapi.FaultListOptions{Q: q, OccurredAfter: baseline.After, OccurredBefore: baseline.Before})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to get baseline fault counts: %v", err)), nil
+	}
+	currentCounts, err := client.Faults.GetCounts(ctx, projectID, hbapi.FaultListOptions{Q: q, OccurredAfter: current.After, OccurredBefore: current.Before})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to get current fault counts: %v", err)), nil
+	}
+
+	response := faultCountComparison{
+		Baseline:     faultCountWindow{After: baseline.After.In(loc), Before: baseline.Before.In(loc)},
+		Current:      faultCountWindow{After: current.After.In(loc), Before: current.Before.In(loc)},
+		Total:        newFaultCountDelta("", baselineCounts.Total, currentCounts.Total),
+		Environments: []faultCountDelta{},
+	}
+	response.Verdict = response.Total.verdict()
+
+	baselineByEnv, currentByEnv := faultCountsByEnvironment(baselineCounts), faultCountsByEnvironment(currentCounts)
+	for env, n := range currentByEnv {
+		response.Environments = append(response.Environments, newFaultCountDelta(env, baselineByEnv[env], n))
+	}
+	for env, n := range baselineByEnv {
+		if _, ok := currentByEnv[env]; !ok {
+			response.Environments = append(response.Environments, newFaultCountDelta(env, n, 0))
+		}
+	}
+	// Biggest movers first.
+	slices.SortFunc(response.Environments, func(a, b faultCountDelta) int {
+		abs := func(n int) int { return max(n, -n) }
+		return cmp.Or(cmp.Compare(abs(b.Delta), abs(a.Delta)), cmp.Compare(a.Environment, b.Environment))
+	})
+
+	// Return JSON response
+	jsonBytes, err := json.Marshal(response)
+	if err != nil {
+		return mcp.NewToolResultError("Failed to marshal response"), nil
+	}
+
+	return withNotes(mcp.NewToolResultText(string(jsonBytes)), &notes), nil
+}
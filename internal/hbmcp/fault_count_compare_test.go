@@ -0,0 +1,132 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestCompareFaultCounts(t *testing.T) {
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	currentAfter := now.Add(-7 * 24 * time.Hour)
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/projects/123/faults/summary" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		query := r.URL.Query()
+		queries = append(queries, query.Encode())
+		if query.Get("q") != "-is:resolved" {
+			t.Errorf("expected q=-is:resolved, got %s", query.Get("q"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if query.Get("occurred_before") == strconv.FormatInt(currentAfter.Unix(), 10) {
+			_, _ = w.Write([]byte(`{"total": 10, "environments": [
+				{"environment": "production", "resolved": false, "ignored": false, "count": 6},
+				{"environment": "production", "resolved": false, "ignored": true, "count": 2},
+				{"environment": "staging", "resolved": false, "ignored": false, "count": 2}
+			]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"total": 14, "environments": [
+			{"environment": "production", "resolved": false, "ignored": false, "count": 13},
+			{"environment": "development", "resolved": false, "ignored": false, "count": 1}
+		]}`))
+	}))
+	defer server.Close()
+
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{
+		"project_id":    123,
+		"current_after": "7 days ago",
+		"resolved":      false,
+		"timezone":      "UTC",
+	}
+
+	result, err := compareFaultCounts(context.Background(), client, req, now)
+	if err != nil || result.IsError {
+		t.Fatalf("compareFaultCounts() = %s, %v", getResultText(result), err)
+	}
+	if len(queries) != 2 {
+		t.Fatalf("expected a counts request per range, got %d", len(queries))
+	}
+
+	var response faultCountComparison
+	if err := json.Unmarshal([]byte(getResultText(result)), &response); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if response.Verdict != "up 40%: 14 faults vs 10" {
+		t.Errorf("unexpected verdict %q", response.Verdict)
+	}
+	if !response.Baseline.Before.Equal(currentAfter) || !response.Baseline.After.Equal(currentAfter.Add(-7*24*time.Hour)) {
+		t.Errorf("expected the baseline to be the week before, got %+v", response.Baseline)
+	}
+	if !response.Current.Before.Equal(now) {
+		t.Errorf("expected the current range to end now, got %v", response.Current.Before)
+	}
+
+	want := []faultCountDelta{
+		{Environment: "production", Baseline: 8, Current: 13, Delta: 5},
+		{Environment: "staging", Baseline: 2, Current: 0, Delta: -2},
+		{Environment: "development", Baseline: 0, Current: 1, Delta: 1},
+	}
+	if len(response.Environments) != len(want) {
+		t.Fatalf("expected %d environments, got %+v", len(want), response.Environments)
+	}
+	for i, w := range want {
+		got := response.Environments[i]
+		if got.Environment != w.Environment || got.Baseline != w.Baseline || got.Current != w.Current || got.Delta != w.Delta {
+			t.Errorf("environment %d: expected %+v, got %+v", i, w, got)
+		}
+	}
+	if p := response.Environments[0].ChangePercent; p == nil || *p != 62.5 {
+		t.Errorf("expected production change_percent 62.5, got %v", p)
+	}
+	if response.Environments[2].ChangePercent != nil {
+		t.Errorf("expected no change_percent without a baseline, got %v", *response.Environments[2].ChangePercent)
+	}
+}
+
+func TestCompareFaultCounts_Arguments(t *testing.T) {
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"total": 0, "environments": []}`))
+	}))
+	defer server.Close()
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+
+	call := func(args map[string]any) *mcp.CallToolResult {
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = args
+		result, _ := compareFaultCounts(context.Background(), client, req, now)
+		return result
+	}
+
+	if result := call(map[string]any{"project_id": 123}); !result.IsError || !strings.Contains(getResultText(result), "current_after is required") {
+		t.Errorf("expected current_after to be required, got %s", getResultText(result))
+	}
+	if result := call(map[string]any{"project_id": 123, "current_after": "-1d", "baseline_after": "-1d", "baseline_before": "-2d"}); !result.IsError {
+		t.Errorf("expected a reversed baseline to fail, got %s", getResultText(result))
+	}
+
+	result := call(map[string]any{"project_id": 123, "current_after": "-1d", "baseline_after": "-30d"})
+	if result.IsError {
+		t.Fatalf("unexpected error: %s", getResultText(result))
+	}
+	if notes := result.Content[len(result.Content)-1].(mcp.TextContent).Text; !strings.Contains(notes, "differ in length (29d baseline vs 1d current)") {
+		t.Errorf("expected a warning about unequal ranges, got %s", notes)
+	}
+	if !strings.Contains(getResultText(result), `"verdict":"unchanged: 0 faults in both ranges"`) {
+		t.Errorf("unexpected response %s", getResultText(result))
+	}
+}
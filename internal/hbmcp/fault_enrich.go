@@ -0,0 +1,129 @@
+package hbmcp
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	hbapi "github.com/honeybadger-io/api-go"
+)
+
+const (
+	// enrichConcurrency bounds the affected-user lookups list_faults runs
+	// at once for enrich, so a page of faults doesn't burst the API.
+	enrichConcurrency = 5
+
+	// maxEnrichPages is how many pages of the last day's faults enrich
+	// reads looking for a page's faults.
+	maxEnrichPages = 4
+)
+
+// enrichedFault is a fault as list_faults returns it with enrich.
+type enrichedFault struct {
+	hbapi.Fault
+	Enrichment faultEnrichment `json:"enrichment"`
+}
+
+// faultEnrichment holds the fields enrich computes for triage. A count
+// is omitted when it couldn't be computed.
+type faultEnrichment struct {
+	Occurrences24h *int   `json:"occurrences_24h,omitempty"`
+	AffectedUsers  *int   `json:"affected_users,omitempty"`
+	AgeHours       int    `json:"age_hours"`
+	Environment    string `json:"environment,omitempty"`
+}
+
+// enrichFaults computes the enrich fields for a page of faults listed
+// with q. Occurrences come from listing the faults that occurred in the
+// last day, which reports each one's count in range; affected users take
+// one call per fault. Whatever can't be computed is left out with a
+// warning rather than failing the list.
+func enrichFaults(ctx context.Context, client *hbapi.Client, projectID int, q string, faults []hbapi.Fault, now time.Time, notes *toolNotes) []enrichedFault {
+	enriched := make([]enrichedFault, len(faults))
+	for i, f := range faults {
+		enriched[i] = enrichedFault{Fault: f, Enrichment: faultEnrichment{
+			AgeHours:    int(now.Sub(f.CreatedAt).Hours()),
+			Environment: f.Environment,
+		}}
+	}
+	if len(faults) == 0 {
+		return enriched
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var userErrs int
+	sem := make(chan struct{}, enrichConcurrency)
+	for i := range enriched {
+		wg.Go(func() {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			users, err := client.Faults.ListAffectedUsers(ctx, projectID, enriched[i].ID, hbapi.FaultListAffectedUsersOptions{})
+			if err != nil {
+				mu.Lock()
+				userErrs++
+				mu.Unlock()
+				return
+			}
+			count := len(users)
+			enriched[i].Enrichment.AffectedUsers = &count
+		})
+	}
+
+	counts, complete, err := occurrencesSince(ctx, client, projectID, q, faults, now.Add(-24*time.Hour))
+	wg.Wait()
+
+	if userErrs > 0 {
+		notes.warnf("affected_users left out for %d of %d faults: the lookup failed", userErrs, len(faults))
+	}
+	if err != nil {
+		notes.warnf("occurrences_24h left out: %v", err)
+		return enriched
+	}
+	missing := 0
+	for i := range enriched {
+		count, ok := counts[enriched[i].ID]
+		if !ok && !complete {
+			missing++
+			continue
+		}
+		enriched[i].Enrichment.Occurrences24h = &count
+	}
+	if missing > 0 {
+		notes.warnf("occurrences_24h left out for %d faults: more than %d faults occurred in the last day", missing, maxEnrichPages*maxPageLimit)
+	}
+	return enriched
+}
+
+// occurrencesSince counts the notices each of faults had since since, by
+// listing the faults matching q that occurred since then. A fault missing
+// from a complete listing had none; complete is false when the listing
+// stopped at maxEnrichPages first.
+func occurrencesSince(ctx context.Context, client *hbapi.Client, projectID int, q string, faults []hbapi.Fault, since time.Time) (map[int]int, bool, error) {
+	wanted := map[int]bool{}
+	for _, f := range faults {
+		wanted[f.ID] = true
+	}
+	counts := map[int]int{}
+	for page := 1; page <= maxEnrichPages; page++ {
+		response, err := client.Faults.List(ctx, projectID, hbapi.FaultListOptions{
+			Q:             q,
+			OccurredAfter: since,
+			Limit:         maxPageLimit,
+			Order:         "frequent",
+			Page:          page,
+		})
+		if err != nil {
+			return nil, false, err
+		}
+		for _, f := range response.Results {
+			if wanted[f.ID] && f.NoticesCountInRange != nil {
+				counts[f.ID] = *f.NoticesCountInRange
+			}
+		}
+		if len(counts) == len(wanted) || response.Links.Next == "" {
+			return counts, true, nil
+		}
+	}
+	return counts, false, nil
+}
@@ -0,0 +1,74 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestHandleListFaults_Enrich(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v2/projects/1/faults":
+			if r.URL.Query().Get("occurred_after") == "" {
+				_, _ = w.Write([]byte(`{"results": [
+					{"id": 10, "environment": "production", "created_at": "2024-01-01T00:00:00Z"},
+					{"id": 11, "environment": "staging", "created_at": "2024-01-01T00:00:00Z"}
+				], "links": {}}`))
+				return
+			}
+			if r.URL.Query().Get("q") != "-is:resolved" {
+				t.Errorf("expected the supplemental list to keep q, got %q", r.URL.RawQuery)
+			}
+			_, _ = w.Write([]byte(`{"results": [{"id": 10, "notices_count_in_range": 7}], "links": {}}`))
+		case "/v2/projects/1/faults/10/affected_users":
+			_, _ = w.Write([]byte(`[{"user": "a@example.com", "count": 3}, {"user": "b@example.com", "count": 1}]`))
+		case "/v2/projects/1/faults/11/affected_users":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"project_id": float64(1), "q": "-is:resolved", "enrich": true}
+
+	result, err := handleListFaults(context.Background(), client, req)
+	if err != nil || result.IsError {
+		t.Fatalf("unexpected error: %v %s", err, getResultText(result))
+	}
+	var response hbapi.ListResponse[enrichedFault]
+	if err := json.Unmarshal([]byte(getResultText(result)), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if len(response.Results) != 2 {
+		t.Fatalf("expected 2 faults, got %d", len(response.Results))
+	}
+
+	first, second := response.Results[0].Enrichment, response.Results[1].Enrichment
+	if first.Occurrences24h == nil || *first.Occurrences24h != 7 || first.AffectedUsers == nil || *first.AffectedUsers != 2 {
+		t.Errorf("unexpected enrichment for fault 10: %+v", first)
+	}
+	if first.Environment != "production" || first.AgeHours <= 0 {
+		t.Errorf("expected environment and age, got %+v", first)
+	}
+	if second.Occurrences24h == nil || *second.Occurrences24h != 0 {
+		t.Errorf("expected a fault missing from the last day to have 0 occurrences, got %+v", second)
+	}
+	if second.AffectedUsers != nil {
+		t.Errorf("expected affected_users left out when the lookup fails, got %d", *second.AffectedUsers)
+	}
+	if notes := getResultNotes(t, result); len(notes.Warnings) != 1 || !strings.Contains(notes.Warnings[0], "affected_users left out for 1 of 2") {
+		t.Errorf("unexpected warnings: %v", notes.Warnings)
+	}
+}
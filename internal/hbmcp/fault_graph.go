@@ -0,0 +1,269 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// maxGraphDeployPages is how many pages of deploys export_fault_graph reads
+// looking for the ones its faults were created after.
+const maxGraphDeployPages = 4
+
+// faultGraph links faults to the things they share. Non-fault nodes sum the
+// notices of their linked faults so hotspots are weighted.
+type faultGraph struct {
+	Nodes []faultGraphNode `json:"nodes"`
+	Edges []faultGraphEdge `json:"edges"`
+
+	index map[string]int
+}
+
+type faultGraphNode struct {
+	ID      string         `json:"id"`
+	Type    string         `json:"type"`
+	Label   string         `json:"label"`
+	Notices int            `json:"notices"`
+	Attrs   map[string]any `json:"attrs,omitempty"`
+}
+
+type faultGraphEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Type string `json:"type"`
+}
+
+// node returns the node with id, adding it on first use.
+func (g *faultGraph) node(id, typ, label string) *faultGraphNode {
+	if i, ok := g.index[id]; ok {
+		return &g.Nodes[i]
+	}
+	g.index[id] = len(g.Nodes)
+	g.Nodes = append(g.Nodes, faultGraphNode{ID: id, Type: typ, Label: label})
+	return &g.Nodes[len(g.Nodes)-1]
+}
+
+func (g *faultGraph) link(fault *faultGraphNode, id, typ, label, edgeType string) {
+	n := g.node(id, typ, label)
+	n.Notices += fault.Notices
+	g.Edges = append(g.Edges, faultGraphEdge{From: fault.ID, To: id, Type: edgeType})
+}
+
+// buildFaultGraph links each fault to its component, assignee, and tags, and
+// to the latest deploy in its environment created before the fault was.
+// deploys may be in any order.
+func buildFaultGraph(faults []hbapi.Fault, deploys []hbapi.Deployment) *faultGraph {
+	g := &faultGraph{Nodes: []faultGraphNode{}, Edges: []faultGraphEdge{}, index: map[string]int{}}
+
+	for _, f := range faults {
+		notices := f.NoticesCount
+		if f.NoticesCountInRange != nil {
+			notices = *f.NoticesCountInRange
+		}
+		fault := g.node(fmt.Sprintf("fault:%d", f.ID), "fault", fmt.Sprintf("%s: %s", f.Klass, truncateLabel(f.Message, 60)))
+		fault.Notices = notices
+		fault.Attrs = map[string]any{
+			"environment": f.Environment,
+			"resolved":    f.Resolved,
+			"created_at":  f.CreatedAt,
+			"url":         f.URL,
+		}
+		// Copy before linking: appending nodes may move the backing array.
+		fn := *fault
+
+		if f.Component != "" {
+			g.link(&fn, "component:"+f.Component, "component", f.Component, "in_component")
+		}
+		if f.Assignee != nil {
+			label := f.Assignee.Name
+			if label == "" {
+				label = f.Assignee.Email
+			}
+			g.link(&fn, fmt.Sprintf("assignee:%d", f.Assignee.ID), "assignee", label, "assigned_to")
+		}
+		for _, tag := range f.Tags {
+			g.link(&fn, "tag:"+tag, "tag", tag, "tagged")
+		}
+		if d := precedingDeploy(f, deploys); d != nil {
+			id := fmt.Sprintf("deploy:%d", d.ID)
+			_, seen := g.index[id]
+			g.link(&fn, id, "deploy", deployLabel(*d), "first_seen_after")
+			if !seen {
+				g.Nodes[g.index[id]].Attrs = map[string]any{
+					"environment": d.Environment,
+					"revision":    d.Revision,
+					"created_at":  d.CreatedAt,
+				}
+			}
+		}
+	}
+	return g
+}
+
+// listGraphDeploys pages back through the deploys before the window's
+// end until it reaches one older than every fault, so each fault can be
+// linked to the deploy that preceded it, however long ago it was created.
+// It stops after maxGraphDeployPages with a warning.
+func listGraphDeploys(ctx context.Context, client *hbapi.Client, projectID int, before time.Time, faults []hbapi.Fault, notes *toolNotes) ([]hbapi.Deployment, error) {
+	if len(faults) == 0 {
+		return nil, nil
+	}
+	earliest := faults[0].CreatedAt
+	for _, f := range faults[1:] {
+		if f.CreatedAt.Before(earliest) {
+			earliest = f.CreatedAt
+		}
+	}
+
+	var deploys []hbapi.Deployment
+	seen := map[int]bool{}
+	for page := 0; ; page++ {
+		if page == maxGraphDeployPages {
+			notes.warnf("deploys before %s omitted; faults created earlier may be linked to no deploy or an older one", before.UTC().Format(time.RFC3339))
+			return deploys, nil
+		}
+		results, err := client.Deployments.List(ctx, projectID, hbapi.DeploymentListOptions{
+			CreatedBefore: before,
+			Limit:         maxPageLimit,
+		})
+		if err != nil {
+			if page == 0 {
+				return nil, err
+			}
+			notes.warnf("deploys before %s omitted: %v", before.UTC().Format(time.RFC3339), err)
+			return deploys, nil
+		}
+		var oldest time.Time
+		for _, d := range results {
+			if !seen[d.ID] {
+				seen[d.ID] = true
+				deploys = append(deploys, d)
+			}
+			if oldest.IsZero() || d.CreatedAt.Before(oldest) {
+				oldest = d.CreatedAt
+			}
+		}
+		if len(results) < maxPageLimit || oldest.Before(earliest) || (!before.IsZero() && !oldest.Before(before)) {
+			return deploys, nil
+		}
+		before = oldest
+	}
+}
+
+func precedingDeploy(f hbapi.Fault, deploys []hbapi.Deployment) *hbapi.Deployment {
+	var latest *hbapi.Deployment
+	for i, d := range deploys {
+		if d.Environment != "" && f.Environment != "" && d.Environment != f.Environment {
+			continue
+		}
+		if d.CreatedAt.After(f.CreatedAt) {
+			continue
+		}
+		if latest == nil || d.CreatedAt.After(latest.CreatedAt) {
+			latest = &deploys[i]
+		}
+	}
+	return latest
+}
+
+func deployLabel(d hbapi.Deployment) string {
+	revision := d.Revision
+	if len(revision) > 7 {
+		revision = revision[:7]
+	}
+	if revision == "" {
+		revision = strconv.Itoa(d.ID)
+	}
+	return fmt.Sprintf("deploy %s (%s, %s)", revision, d.Environment, d.CreatedAt.UTC().Format(time.DateOnly))
+}
+
+func truncateLabel(s string, n int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if r := []rune(s); len(r) > n {
+		return string(r[:n-1]) + "…"
+	}
+	return s
+}
+
+// dot renders the graph in Graphviz DOT. Node widths scale with notices.
+func (g *faultGraph) dot() string {
+	shapes := map[string]string{"fault": "box", "component": "ellipse", "assignee": "house", "tag": "note", "deploy": "cds"}
+	var sb strings.Builder
+	sb.WriteString("digraph fault_graph {\n  rankdir=LR;\n")
+	for _, n := range g.Nodes {
+		fmt.Fprintf(&sb, "  %s [label=%s, shape=%s];\n",
+			dotQuote(n.ID), dotQuote(fmt.Sprintf("%s\n%d notices", n.Label, n.Notices)), shapes[n.Type])
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&sb, "  %s -> %s [label=%s];\n", dotQuote(e.From), dotQuote(e.To), dotQuote(e.Type))
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+func dotQuote(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	return `"` + r.Replace(s) + `"`
+}
+
+func handleExportFaultGraph(ctx context.Context, client *hbapi.Client, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	projectID := req.GetInt("project_id", 0)
+	if projectID == 0 {
+		return mcp.NewToolResultError("project_id is required"), nil
+	}
+
+	format := req.GetString("format", "json")
+	if format != "json" && format != "dot" {
+		return mcp.NewToolResultError("format must be 'json' or 'dot'"), nil
+	}
+
+	window, err := resolveTimeWindow(req, "occurred_after", "occurred_before", time.Now(), time.UTC)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	var notes toolNotes
+	notes.addResolved(window.Resolved)
+
+	limit := notes.limitArg(req)
+	if limit == 0 {
+		limit = maxPageLimit
+	}
+
+	faults, err := client.Faults.List(ctx, projectID, hbapi.FaultListOptions{
+		Q:              req.GetString("q", ""),
+		OccurredAfter:  window.After,
+		OccurredBefore: window.Before,
+		Order:          "frequent",
+		Limit:          limit,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to list faults: %v", err)), nil
+	}
+	if faults.Links.Next != "" {
+		notes.warnf("graph covers the %d most frequent faults; narrow the window or query to see others", len(faults.Results))
+	}
+
+	deploys, err := listGraphDeploys(ctx, client, projectID, window.Before, faults.Results, &notes)
+	if err != nil {
+		notes.warnf("deploys omitted: %v", err)
+	}
+
+	graph := buildFaultGraph(faults.Results, deploys)
+	if format == "dot" {
+		return withNotes(mcp.NewToolResultText(graph.dot()), &notes), nil
+	}
+
+	// Return JSON response
+	jsonBytes, err := json.Marshal(graph)
+	if err != nil {
+		return mcp.NewToolResultError("Failed to marshal response"), nil
+	}
+
+	return withNotes(mcp.NewToolResultText(string(jsonBytes)), &notes), nil
+}
@@ -0,0 +1,208 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestBuildFaultGraph(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 3, d, 12, 0, 0, 0, time.UTC) }
+	inRange := 7
+	faults := []hbapi.Fault{
+		{ID: 1, Klass: "NoMethodError", Message: "undefined method", Component: "users", Environment: "production", CreatedAt: day(10), NoticesCount: 100, NoticesCountInRange: &inRange, Tags: []string{"billing"}, Assignee: &hbapi.User{ID: 9, Name: "Pat"}},
+		{ID: 2, Klass: "Timeout", Message: "execution expired", Component: "users", Environment: "production", CreatedAt: day(12), NoticesCount: 3, Tags: []string{"billing", "slow"}},
+		{ID: 3, Klass: "KeyError", Message: "key not found", Environment: "staging", CreatedAt: day(12), NoticesCount: 1},
+	}
+	deploys := []hbapi.Deployment{
+		{ID: 20, Environment: "production", Revision: "abcdef123456", CreatedAt: day(11)},
+		{ID: 10, Environment: "production", Revision: "0123456789", CreatedAt: day(9)},
+		{ID: 30, Environment: "staging", Revision: "ffff", CreatedAt: day(13)},
+	}
+
+	g := buildFaultGraph(faults, deploys)
+
+	nodes := map[string]faultGraphNode{}
+	for _, n := range g.Nodes {
+		nodes[n.ID] = n
+	}
+	if n := nodes["component:users"]; n.Notices != 10 {
+		t.Errorf("component:users notices = %d, want 10 (7 in range + 3)", n.Notices)
+	}
+	if n := nodes["tag:billing"]; n.Notices != 10 {
+		t.Errorf("tag:billing notices = %d, want 10", n.Notices)
+	}
+	if n := nodes["assignee:9"]; n.Label != "Pat" {
+		t.Errorf("assignee label = %q, want Pat", n.Label)
+	}
+	if _, ok := nodes["deploy:30"]; ok {
+		t.Error("staging deploy after fault 3 should not be linked")
+	}
+
+	edges := map[string]string{}
+	for _, e := range g.Edges {
+		if e.Type == "first_seen_after" {
+			edges[e.From] = e.To
+		}
+	}
+	if edges["fault:1"] != "deploy:10" || edges["fault:2"] != "deploy:20" {
+		t.Errorf("unexpected deploy edges %v", edges)
+	}
+	if len(g.Edges) != 8 {
+		t.Errorf("expected 8 edges, got %d", len(g.Edges))
+	}
+}
+
+func TestHandleExportFaultGraph_Dot(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v2/projects/1/faults":
+			if r.URL.Query().Get("order") != "frequent" {
+				t.Errorf("expected order=frequent, got %q", r.URL.Query().Get("order"))
+			}
+			_, _ = w.Write([]byte(`{"results": [{"id": 5, "klass": "RuntimeError", "message": "say \"hi\"", "component": "jobs", "notices_count": 4, "created_at": "2024-03-01T00:00:00Z"}], "links": {}}`))
+		case "/v2/projects/1/deploys":
+			_, _ = w.Write([]byte(`{"results": []}`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Arguments: map[string]interface{}{"project_id": 1, "format": "dot"},
+		},
+	}
+
+	result, err := handleExportFaultGraph(context.Background(), client, req)
+	if err != nil {
+		t.Fatalf("handleExportFaultGraph() error = %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got %s", getResultText(result))
+	}
+	dot := getResultText(result)
+	for _, want := range []string{"digraph fault_graph {", `"fault:5" -> "component:jobs" [label="in_component"];`, `say \"hi\"`} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("expected %q in DOT output:\n%s", want, dot)
+		}
+	}
+}
+
+func TestHandleExportFaultGraph_DeploysFailureIsWarning(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v2/projects/1/deploys" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"results": [{"id": 5, "klass": "RuntimeError", "notices_count": 4}], "links": {}}`))
+	}))
+	defer server.Close()
+
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Arguments: map[string]interface{}{"project_id": 1},
+		},
+	}
+
+	result, err := handleExportFaultGraph(context.Background(), client, req)
+	if err != nil {
+		t.Fatalf("handleExportFaultGraph() error = %v", err)
+	}
+	var graph faultGraph
+	if err := json.Unmarshal([]byte(getResultText(result)), &graph); err != nil {
+		t.Fatalf("failed to unmarshal graph: %v", err)
+	}
+	if len(graph.Nodes) != 1 {
+		t.Errorf("expected 1 node, got %d", len(graph.Nodes))
+	}
+	notes := getResultNotes(t, result)
+	if len(notes.Warnings) != 1 || !strings.Contains(notes.Warnings[0], "deploys omitted") {
+		t.Errorf("expected deploys warning, got %v", notes.Warnings)
+	}
+}
+
+func TestHandleExportFaultGraph_PagesDeploys(t *testing.T) {
+	tests := []struct {
+		name     string
+		recent   int
+		wantEdge string
+		wantWarn bool
+	}{
+		{"reaches the preceding deploy", 60, "deploy:999", false},
+		{"stops at the page limit", 200, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Hourly deploys back from March 10, all after the fault was
+			// created, then one before it.
+			var deploys []hbapi.Deployment
+			for i := range tt.recent {
+				deploys = append(deploys, hbapi.Deployment{ID: i + 1, Environment: "production", CreatedAt: time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC).Add(-time.Duration(i) * time.Hour)})
+			}
+			deploys = append(deploys, hbapi.Deployment{ID: 999, Environment: "production", CreatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)})
+
+			var pages int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				if r.URL.Path == "/v2/projects/1/deploys" {
+					pages++
+					page := []hbapi.Deployment{}
+					before, _ := strconv.ParseInt(r.URL.Query().Get("created_before"), 10, 64)
+					for _, d := range deploys {
+						if len(page) < maxPageLimit && (before == 0 || d.CreatedAt.Unix() < before) {
+							page = append(page, d)
+						}
+					}
+					_ = json.NewEncoder(w).Encode(map[string]any{"results": page})
+					return
+				}
+				_, _ = w.Write([]byte(`{"results": [{"id": 5, "klass": "RuntimeError", "environment": "production", "notices_count": 4, "created_at": "2024-02-01T00:00:00Z"}], "links": {}}`))
+			}))
+			defer server.Close()
+
+			client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+			req := mcp.CallToolRequest{
+				Params: mcp.CallToolParams{
+					Arguments: map[string]interface{}{"project_id": 1},
+				},
+			}
+			result, err := handleExportFaultGraph(context.Background(), client, req)
+			if err != nil || result.IsError {
+				t.Fatalf("unexpected error: %v %s", err, getResultText(result))
+			}
+			var graph faultGraph
+			if err := json.Unmarshal([]byte(getResultText(result)), &graph); err != nil {
+				t.Fatalf("failed to unmarshal graph: %v", err)
+			}
+			var edge string
+			for _, e := range graph.Edges {
+				if e.Type == "first_seen_after" {
+					edge = e.To
+				}
+			}
+			if edge != tt.wantEdge {
+				t.Errorf("expected fault 5 linked to %q, got %q after %d pages", tt.wantEdge, edge, pages)
+			}
+			if pages > maxGraphDeployPages {
+				t.Errorf("expected at most %d pages, got %d", maxGraphDeployPages, pages)
+			}
+			if warnings := getResultNotes(t, result).Warnings; (len(warnings) > 0) != tt.wantWarn {
+				t.Errorf("expected warning %v, got %v", tt.wantWarn, warnings)
+			}
+		})
+	}
+}
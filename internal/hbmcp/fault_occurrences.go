@@ -0,0 +1,224 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// maxDerivedOccurrenceNotices bounds how many notices are counted when the
+// fault occurrences endpoint isn't available (40 pages).
+const maxDerivedOccurrenceNotices = 1000
+
+// derivedOccurrencePeriods is how many periods a series counted from
+// notices covers, about what the occurrences endpoint returns.
+var derivedOccurrencePeriods = map[string]int{
+	"hour":  24,
+	"day":   30,
+	"week":  12,
+	"month": 12,
+}
+
+// faultOccurrenceSeries is the result of get_fault_occurrence_counts.
+// Source says whether the counts came from the API's occurrence counts
+// ("api") or were counted from the fault's notices ("notices").
+type faultOccurrenceSeries struct {
+	Source string `json:"source"`
+	occurrenceSeries
+}
+
+// RegisterFaultOccurrenceTools registers get_fault_occurrence_counts. It
+// reads the fault occurrences endpoint, which hbapi has no method for,
+// through a raw client.
+func RegisterFaultOccurrenceTools(r *toolRegistrar, clientFor ClientFactory, rawFor RawClientFactory) {
+	// get_fault_occurrence_counts tool
+	r.AddTool(
+		mcp.NewTool("get_fault_occurrence_counts",
+			mcp.WithTitleAnnotation("Get Fault Occurrence Counts"),
+			mcp.WithDescription("Get occurrence counts for a single fault as [unix_timestamp, count] pairs with a total, to chart when a specific regression started without the rest of the project's noise. Where the API doesn't provide a fault's counts, they're counted from its most recent notices instead, and source says which. Use get_project_occurrence_counts for a whole project, and analyze_fault_trend for a verdict rather than the raw series."),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithOutputSchema[faultOccurrenceSeries](),
+			mcp.WithNumber("project_id",
+				mcp.Required(),
+				mcp.Description("The ID of the project containing the fault"),
+				mcp.Min(1),
+			),
+			mcp.WithNumber("fault_id",
+				mcp.Required(),
+				mcp.Description("The ID of the fault to count occurrences of"),
+				mcp.Min(1),
+			),
+			mcp.WithString("period",
+				mcp.Description("Time period for grouping data: 'hour', 'day', 'week', or 'month'. Defaults to 'hour'"),
+				mcp.Enum("hour", "day", "week", "month"),
+			),
+			mcp.WithString("environment",
+				mcp.Description("Optional environment name to filter results"),
+			),
+			mcp.WithString("timezone",
+				mcp.Description(timezoneDescription),
+			),
+			mcp.WithString("render",
+				mcp.Description(renderDescription),
+				mcp.Enum(renderJSON, renderASCIIChart),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleGetFaultOccurrenceCounts(ctx, clientFor(ctx), rawFor(ctx), req, time.Now())
+		},
+	)
+}
+
+func handleGetFaultOccurrenceCounts(ctx context.Context, client *hbapi.Client, raw *RawClient, req mcp.CallToolRequest, now time.Time) (*mcp.CallToolResult, error) {
+	projectID := req.GetInt("project_id", 0)
+	if projectID == 0 {
+		return mcp.NewToolResultError("project_id is required"), nil
+	}
+	faultID := req.GetInt("fault_id", 0)
+	if faultID == 0 {
+		return mcp.NewToolResultError("fault_id is required"), nil
+	}
+
+	period := req.GetString("period", "hour")
+	if _, ok := derivedOccurrencePeriods[period]; !ok {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid period %q: use 'hour', 'day', 'week', or 'month'", period)), nil
+	}
+	environment := req.GetString("environment", "")
+	render, errResult := renderArg(req)
+	if errResult != nil {
+		return errResult, nil
+	}
+	loc, err := timezoneArg(req)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	var notes toolNotes
+	result := faultOccurrenceSeries{Source: "api"}
+	query := url.Values{"period": {period}}
+	if environment != "" {
+		query.Set("environment", environment)
+	}
+	var counts []hbapi.ProjectOccurrenceCount
+	err = raw.Raw(ctx, http.MethodGet, fmt.Sprintf("/projects/%d/faults/%d/occurrences?%s", projectID, faultID, query.Encode()), nil, &counts)
+	var apiErr *hbapi.APIError
+	switch {
+	case err == nil:
+		if loc != time.UTC && period != "hour" {
+			notes.warnf("the API groups %s periods by UTC", period)
+		}
+	case errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound:
+		// Either the fault doesn't exist or the endpoint doesn't; getting
+		// the fault tells which, and fails with the API's error if it's
+		// the fault.
+		if _, err := client.Faults.Get(ctx, projectID, faultID); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to get fault: %v", err)), nil
+		}
+		result.Source = "notices"
+		counts, err = countFaultOccurrences(ctx, client, projectID, faultID, period, environment, loc, now, &notes)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to list fault notices: %v", err)), nil
+		}
+	default:
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to get fault occurrence counts: %v", err)), nil
+	}
+
+	result.Counts = counts
+	if result.Counts == nil {
+		result.Counts = []hbapi.ProjectOccurrenceCount{}
+	}
+	for _, c := range counts {
+		result.Total += c[1]
+	}
+
+	if render == renderASCIIChart {
+		// The chart is for people; the series stays structured content so
+		// the result still matches the output schema.
+		chart := mcp.NewToolResultText(renderSparkline(fmt.Sprintf("Fault %d occurrences", faultID), occurrenceChartPoints(result.Counts, loc)))
+		chart.StructuredContent = result
+		return withNotes(chart, &notes), nil
+	}
+
+	// Return JSON response
+	jsonBytes, err := json.Marshal(result)
+	if err != nil {
+		return mcp.NewToolResultError("Failed to marshal response"), nil
+	}
+
+	return withNotes(mcp.NewToolResultText(string(jsonBytes)), &notes), nil
+}
+
+// countFaultOccurrences counts a fault's notices into zero-filled periods
+// in loc, covering derivedOccurrencePeriods of them up to now. Weeks start
+// on Monday.
+func countFaultOccurrences(ctx context.Context, client *hbapi.Client, projectID, faultID int, period, environment string, loc *time.Location, now time.Time, notes *toolNotes) ([]hbapi.ProjectOccurrenceCount, error) {
+	start := func(t time.Time) time.Time {
+		t = t.In(loc)
+		switch period {
+		case "hour":
+			return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, loc)
+		case "day":
+			return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+		case "week":
+			return time.Date(t.Year(), t.Month(), t.Day()-(int(t.Weekday())+6)%7, 0, 0, 0, 0, loc)
+		default:
+			return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, loc)
+		}
+	}
+	next := func(t time.Time) time.Time {
+		switch period {
+		case "hour":
+			return t.Add(time.Hour)
+		case "day":
+			return t.AddDate(0, 0, 1)
+		case "week":
+			return t.AddDate(0, 0, 7)
+		default:
+			return t.AddDate(0, 1, 0)
+		}
+	}
+
+	first := start(now)
+	for range derivedOccurrencePeriods[period] - 1 {
+		first = start(first.Add(-time.Nanosecond))
+	}
+	counts := []hbapi.ProjectOccurrenceCount{}
+	index := map[int64]int{}
+	for t := first; !t.After(now); t = next(t) {
+		index[t.Unix()] = len(counts)
+		counts = append(counts, hbapi.ProjectOccurrenceCount{t.Unix(), 0})
+	}
+
+	var counted int
+	var oldest time.Time
+	exhausted, err := walkFaultNotices(ctx, client, projectID, faultID, timeWindow{After: first}, maxDerivedOccurrenceNotices, func(n hbapi.Notice) error {
+		counted++
+		oldest = n.CreatedAt
+		if environment != "" && n.EnvironmentName != environment {
+			return nil
+		}
+		if i, ok := index[start(n.CreatedAt).Unix()]; ok {
+			counts[i][1]++
+		}
+		return nil
+	})
+	switch {
+	case err != nil && counted == 0:
+		return nil, err
+	case err != nil:
+		notes.warnf("stopped after %d notices: %v; counts before %s are incomplete", counted, err, oldest.UTC().Format(time.RFC3339))
+	case !exhausted:
+		notes.warnf("counted the newest %d notices; counts before %s are incomplete. Use a shorter period for an exact series", counted, oldest.UTC().Format(time.RFC3339))
+	}
+	return counts, nil
+}
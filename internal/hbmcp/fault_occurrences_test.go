@@ -0,0 +1,89 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestHandleGetFaultOccurrenceCounts(t *testing.T) {
+	now := time.Date(2024, 3, 15, 14, 30, 0, 0, time.UTC)
+	endpoint := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v2/projects/5/faults/7/occurrences":
+			if !endpoint {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"errors": "Not found"}`))
+				return
+			}
+			if r.URL.Query().Get("period") != "day" || r.URL.Query().Get("environment") != "production" {
+				t.Errorf("unexpected query %s", r.URL.RawQuery)
+			}
+			_, _ = w.Write([]byte(`[[1710374400, 3], [1710460800, 9]]`))
+		case "/v2/projects/5/faults/7":
+			_, _ = w.Write([]byte(`{"id": 7, "project_id": 5, "klass": "RuntimeError"}`))
+		case "/v2/projects/5/faults/7/notices":
+			_, _ = w.Write([]byte(`{"results": [
+				{"id": "c", "created_at": "2024-03-15T14:10:00Z", "environment_name": "production"},
+				{"id": "b", "created_at": "2024-03-15T12:40:00Z", "environment_name": "staging"},
+				{"id": "a", "created_at": "2024-03-15T12:05:00Z", "environment_name": "production"}
+			], "links": {}}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+	raw := &RawClient{baseURL: server.URL, authToken: "test-token", httpClient: http.DefaultClient}
+
+	call := func(args map[string]any) faultOccurrenceSeries {
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = args
+		result, err := handleGetFaultOccurrenceCounts(context.Background(), client, raw, req, now)
+		if err != nil || result.IsError {
+			t.Fatalf("unexpected error: %v %s", err, getResultText(result))
+		}
+		var got faultOccurrenceSeries
+		if err := json.Unmarshal([]byte(getResultText(result)), &got); err != nil {
+			t.Fatalf("failed to parse response: %v", err)
+		}
+		return got
+	}
+
+	got := call(map[string]any{"project_id": float64(5), "fault_id": float64(7), "period": "day", "environment": "production"})
+	if got.Source != "api" || got.Total != 12 || len(got.Counts) != 2 {
+		t.Errorf("expected the API's counts, got %+v", got)
+	}
+
+	endpoint = false
+	got = call(map[string]any{"project_id": float64(5), "fault_id": float64(7), "environment": "production"})
+	if got.Source != "notices" || got.Total != 2 || len(got.Counts) != 24 {
+		t.Fatalf("expected 24 hourly counts from the production notices, got %+v", got)
+	}
+	if last := got.Counts[len(got.Counts)-1]; last != (hbapi.ProjectOccurrenceCount{now.Truncate(time.Hour).Unix(), 1}) {
+		t.Errorf("expected one occurrence in the current hour, got %v", last)
+	}
+	if noon := got.Counts[len(got.Counts)-3]; noon[1] != 1 {
+		t.Errorf("expected one occurrence at noon, got %v", noon)
+	}
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"project_id": float64(5), "fault_id": float64(7), "period": "week", "render": "ascii_chart"}
+	result, _ := handleGetFaultOccurrenceCounts(context.Background(), client, raw, req, now)
+	if result.IsError || !strings.HasPrefix(getResultText(result), "Fault 7 occurrences") {
+		t.Errorf("expected a chart, got %s", getResultText(result))
+	}
+	if series, ok := result.StructuredContent.(faultOccurrenceSeries); !ok || series.Source != "notices" || len(series.Counts) != 12 {
+		t.Errorf("expected the charted series as structured content, got %+v", result.StructuredContent)
+	}
+}
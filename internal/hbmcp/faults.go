@@ -5,6 +5,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
+	"slices"
+	"sort"
+	"strings"
+	"time"
 
 	hbapi "github.com/honeybadger-io/api-go"
 	"github.com/mark3labs/mcp-go/mcp"
@@ -19,36 +23,33 @@ func RegisterFaultTools(r *toolRegistrar, clientFor ClientFactory) {
 			mcp.WithDescription("Get a list of faults for a project with optional filtering and ordering. Requires reference topic: errors (fetch via get_reference; skip if still visible in your context) for the fault/notice model and the q search syntax."),
 			mcp.WithReadOnlyHintAnnotation(true),
 			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithOutputSchema[hbapi.FaultListResponse](),
 			mcp.WithNumber("project_id",
 				mcp.Required(),
 				mcp.Description("The ID of the project to get faults for"),
 				mcp.Min(1),
 			),
-			mcp.WithString("q",
-				mcp.Description("Search string to filter faults (see the errors reference topic for the search query syntax)"),
-			),
-			mcp.WithString("created_after",
-				mcp.Description("Filter faults created after this timestamp"),
-			),
-			mcp.WithString("occurred_after",
-				mcp.Description("Filter faults that occurred after this timestamp"),
-			),
-			mcp.WithString("occurred_before",
-				mcp.Description("Filter faults that occurred before this timestamp"),
-			),
+			faultFilterArgs,
 			mcp.WithNumber("limit",
 				mcp.Description("Maximum number of faults to return (max 25)"),
 				mcp.Min(1),
-				mcp.Max(25),
 			),
 			mcp.WithString("order",
 				mcp.Description("Order results by 'recent' or 'frequent'"),
 				mcp.Enum("recent", "frequent"),
 			),
 			mcp.WithNumber("page",
-				mcp.Description("Page number for pagination"),
+				mcp.Description("Page number for pagination. Prefer page_token"),
 				mcp.Min(1),
 			),
+			mcp.WithString("page_token",
+				mcp.Description(pageTokenDescription),
+			),
+			mcp.WithBoolean("enrich",
+				mcp.Description("Add an enrichment object to each fault for triage: occurrences in the last 24 hours, affected user count, age in hours, and environment. Costs a few extra API calls (default false)"),
+			),
 		),
 		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			return handleListFaults(ctx, clientFor(ctx), req)
@@ -62,6 +63,9 @@ func RegisterFaultTools(r *toolRegistrar, clientFor ClientFactory) {
 			mcp.WithDescription("Get detailed information for a specific fault in a project"),
 			mcp.WithReadOnlyHintAnnotation(true),
 			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithOutputSchema[hbapi.Fault](),
 			mcp.WithNumber("project_id",
 				mcp.Required(),
 				mcp.Description("The ID of the project containing the fault"),
@@ -74,7 +78,7 @@ func RegisterFaultTools(r *toolRegistrar, clientFor ClientFactory) {
 			),
 		),
 		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return handleGetFault(ctx, clientFor(ctx), req)
+			return handleGetFault(ctx, clientFor(ctx), req, r.lookupNotFound)
 		},
 	)
 
@@ -85,6 +89,8 @@ func RegisterFaultTools(r *toolRegistrar, clientFor ClientFactory) {
 			mcp.WithDescription("Update a fault's resolved, ignored, assignee, or resolve-on-deploy state. Only the provided fields are changed. Setting resolved or ignored to true in the same request takes precedence over resolve_on_deploy."),
 			mcp.WithReadOnlyHintAnnotation(false),
 			mcp.WithDestructiveHintAnnotation(true),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
 			mcp.WithNumber("project_id",
 				mcp.Required(),
 				mcp.Description("The ID of the project containing the fault"),
@@ -102,7 +108,7 @@ func RegisterFaultTools(r *toolRegistrar, clientFor ClientFactory) {
 				mcp.Description("Whether the fault is ignored"),
 			),
 			mcp.WithInteger("assignee_id",
-				mcp.Description("Positive integer to assign that user; null to remove the current assignee; omit to leave unchanged"),
+				mcp.Description("Positive integer to assign that user (find it by email with resolve_user); null to remove the current assignee; omit to leave unchanged"),
 				mcp.Min(1),
 				nullable,
 			),
@@ -119,9 +125,11 @@ func RegisterFaultTools(r *toolRegistrar, clientFor ClientFactory) {
 	r.AddTool(
 		mcp.NewTool("list_fault_notices",
 			mcp.WithTitleAnnotation("List Fault Notices"),
-			mcp.WithDescription("Get a list of notices (individual error events) for a specific fault"),
+			mcp.WithDescription("Get a list of notices (individual error events) for a specific fault, newest first. Set latest to get just the most recent notice, which is usually what you want when diagnosing a fault, or first to get the earliest one, where root cause analysis often starts."),
 			mcp.WithReadOnlyHintAnnotation(true),
 			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
 			mcp.WithNumber("project_id",
 				mcp.Required(),
 				mcp.Description("The ID of the project containing the fault"),
@@ -133,15 +141,43 @@ func RegisterFaultTools(r *toolRegistrar, clientFor ClientFactory) {
 				mcp.Min(1),
 			),
 			mcp.WithString("created_after",
-				mcp.Description("Filter notices created after this timestamp"),
+				mcp.Description("Filter notices created after this timestamp"+timestampHint),
 			),
 			mcp.WithString("created_before",
-				mcp.Description("Filter notices created before this timestamp"),
+				mcp.Description("Filter notices created before this timestamp"+timestampHint),
 			),
 			mcp.WithNumber("limit",
 				mcp.Description("Maximum number of notices to return (max 25)"),
 				mcp.Min(1),
-				mcp.Max(25),
+			),
+			mcp.WithBoolean("latest",
+				mcp.Description("Return only the most recent notice as a single object instead of a list (ignores limit)"),
+			),
+			mcp.WithBoolean("first",
+				mcp.Description("Return only the earliest notice still kept (or the earliest after created_after) as a single object instead of a list (ignores limit)"),
+			),
+			mcp.WithNumber("sample",
+				mcp.Description("Return this many notices spread evenly across the fault's history (or the created_after/created_before window), from the earliest to the latest, instead of only the most recent page. Takes one API call per notice (max 25, ignores limit)"),
+				mcp.Min(2),
+				mcp.Max(maxNoticeSample),
+			),
+			mcp.WithBoolean("app_trace_only",
+				mcp.Description("Keep only backtrace frames in the application's own code, dropping library and framework frames (default false)"),
+			),
+			mcp.WithNumber("max_frames",
+				mcp.Description("Keep at most this many backtrace frames per notice, innermost first"),
+				mcp.Min(1),
+			),
+			mcp.WithArray("exclude_fields",
+				mcp.WithStringItems(),
+				mcp.Description("Dot-separated key paths to leave out of each notice, e.g. 'cookies', 'web_environment', or 'request.session'"),
+			),
+			mcp.WithNumber("max_notice_bytes",
+				mcp.Description(fmt.Sprintf("Trim notices whose JSON is larger than this: their biggest fields are replaced by a note of their size and their backtraces cut to the first %d frames, and the full notice is linked at resource_uri for reading on demand (default %d, 0 to never trim)", trimmedFrames, defaultMaxNoticeBytes)),
+				mcp.Min(0),
+			),
+			mcp.WithString("page_token",
+				mcp.Description(pageTokenDescription),
 			),
 		),
 		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -156,6 +192,8 @@ func RegisterFaultTools(r *toolRegistrar, clientFor ClientFactory) {
 			mcp.WithDescription("Get a list of users who were affected by a specific fault with occurrence counts"),
 			mcp.WithReadOnlyHintAnnotation(true),
 			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
 			mcp.WithNumber("project_id",
 				mcp.Required(),
 				mcp.Description("The ID of the project containing the fault"),
@@ -175,6 +213,42 @@ func RegisterFaultTools(r *toolRegistrar, clientFor ClientFactory) {
 		},
 	)
 
+	// search_user_impact tool
+	r.AddTool(
+		mcp.NewTool("search_user_impact",
+			mcp.WithTitleAnnotation("Search User Impact"),
+			mcp.WithDescription("Find the faults a user hit, in one project or every project, e.g. to answer \"what errors did customer X hit this week?\". Searches each project's faults by its user search field setting (context.user_email unless set) within a time window (default the last 7 days), then counts the user's notices of each fault from its affected users. Faults are listed most of the user's notices first."),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithOutputSchema[userImpact](),
+			mcp.WithString("user",
+				mcp.Required(),
+				mcp.Description("The user to search for, usually an email address"),
+			),
+			mcp.WithNumber("project_id",
+				mcp.Description("The ID of the project to search; omit to search every project"),
+				mcp.Min(1),
+			),
+			mcp.WithString("account_id",
+				mcp.Description("Without project_id, only search this account's projects (see check_connection for account IDs)"),
+			),
+			mcp.WithString("search_field",
+				mcp.Description("Field to search for the user, e.g. 'context.user_id', instead of each project's user search field setting"),
+			),
+			mcp.WithString("occurred_after",
+				mcp.Description("Only faults that occurred after this timestamp (default 7 days before occurred_before)"+timestampHint),
+			),
+			mcp.WithString("occurred_before",
+				mcp.Description("Only faults that occurred before this timestamp (default now)"+timestampHint),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleSearchUserImpact(ctx, clientFor(ctx), req)
+		},
+	)
+
 	// get_fault_counts tool
 	r.AddTool(
 		mcp.NewTool("get_fault_counts",
@@ -182,26 +256,378 @@ func RegisterFaultTools(r *toolRegistrar, clientFor ClientFactory) {
 			mcp.WithDescription("Get fault count statistics for a project with optional filtering. Requires reference topic: errors (fetch via get_reference; skip if still visible in your context) for the q search syntax."),
 			mcp.WithReadOnlyHintAnnotation(true),
 			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithOutputSchema[hbapi.FaultCounts](),
 			mcp.WithNumber("project_id",
 				mcp.Required(),
 				mcp.Description("The ID of the project to get fault counts for"),
 				mcp.Min(1),
 			),
+			faultFilterArgs,
+			mcp.WithString("timezone",
+				mcp.Description(timezoneDescription),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleGetFaultCounts(ctx, clientFor(ctx), req)
+		},
+	)
+
+	// get_account_fault_counts tool
+	r.AddTool(
+		mcp.NewTool("get_account_fault_counts",
+			mcp.WithTitleAnnotation("Get Account Fault Counts"),
+			mcp.WithDescription("Get fault counts for every project, or every project in one account, in one call: total, unresolved, and ignored faults per project plus account-wide totals, most unresolved first. Takes the same filters as get_fault_counts. Use this for an overview of account health before drilling into a project."),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithOutputSchema[accountFaultCounts](),
+			mcp.WithString("account_id",
+				mcp.Description("Only count faults in this account's projects (see check_connection for account IDs)"),
+			),
+			faultFilterArgs,
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleGetAccountFaultCounts(ctx, clientFor(ctx), req)
+		},
+	)
+
+	// compare_fault_counts tool
+	r.AddTool(
+		mcp.NewTool("compare_fault_counts",
+			mcp.WithTitleAnnotation("Compare Fault Counts"),
+			mcp.WithDescription("Compare how many faults occurred in a project across two time ranges, e.g. this week vs last week or before vs after a release. Returns a one-line verdict (e.g. \"up 40%: 14 faults vs 10\"), the total and per-environment counts for each range with their delta and change_percent, biggest movers first. By default the baseline is the span of the same length just before the current range, so current_after alone answers \"did errors go up since Tuesday's deploy?\". Takes the same q filters as get_fault_counts; requires reference topic errors for the q syntax (fetch via get_reference; skip if still visible in your context)."),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithOutputSchema[faultCountComparison](),
+			mcp.WithNumber("project_id",
+				mcp.Required(),
+				mcp.Description("The ID of the project to compare fault counts for"),
+				mcp.Min(1),
+			),
+			mcp.WithString("current_after",
+				mcp.Required(),
+				mcp.Description("Start of the current range. A bounded range such as \"yesterday 2pm-4pm\" also sets current_before"+timestampHint),
+			),
+			mcp.WithString("current_before",
+				mcp.Description("End of the current range (default now)"+timestampHint),
+			),
+			mcp.WithString("baseline_after",
+				mcp.Description("Start of the baseline range (default the current range's length before baseline_before). A bounded range also sets baseline_before"+timestampHint),
+			),
+			mcp.WithString("baseline_before",
+				mcp.Description("End of the baseline range (default current_after)"+timestampHint),
+			),
+			faultQueryArgs,
+			mcp.WithString("timezone",
+				mcp.Description(timezoneDescription),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleCompareFaultCounts(ctx, clientFor(ctx), req)
+		},
+	)
+
+	// export_fault_graph tool
+	r.AddTool(
+		mcp.NewTool("export_fault_graph",
+			mcp.WithTitleAnnotation("Export Fault Graph"),
+			mcp.WithDescription("Export a graph linking a project's most frequent faults in a time window to their components, assignees, tags, and the deploy that preceded each fault, as JSON or Graphviz DOT. Component, assignee, tag, and deploy nodes carry the total notices of their linked faults, so hotspots stand out when visualized in external tools."),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithNumber("project_id",
+				mcp.Required(),
+				mcp.Description("The ID of the project to graph"),
+				mcp.Min(1),
+			),
 			mcp.WithString("q",
 				mcp.Description("Search string to filter faults (see the errors reference topic for the search query syntax)"),
 			),
+			mcp.WithString("occurred_after",
+				mcp.Description("Include faults that occurred after this timestamp"+timestampHint),
+			),
+			mcp.WithString("occurred_before",
+				mcp.Description("Include faults that occurred before this timestamp"+timestampHint),
+			),
+			mcp.WithNumber("limit",
+				mcp.Description("Maximum number of faults to include, most frequent first (max 25, default 25)"),
+				mcp.Min(1),
+			),
+			mcp.WithString("format",
+				mcp.Description("Output format: 'json' (nodes and edges) or 'dot' (Graphviz). Defaults to json."),
+				mcp.Enum("json", "dot"),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleExportFaultGraph(ctx, clientFor(ctx), req)
+		},
+	)
+
+	// aggregate_fault_notices tool
+	r.AddTool(
+		mcp.NewTool("aggregate_fault_notices",
+			mcp.WithTitleAnnotation("Aggregate Fault Notices"),
+			mcp.WithDescription("Count a fault's notices by the value at a key path, such as request.params.id, environment.hostname, or request.user.email, newest notices first. Use this instead of paging through list_fault_notices to see whether a fault is concentrated on one user, host, or input."),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithNumber("project_id",
+				mcp.Required(),
+				mcp.Description("The ID of the project containing the fault"),
+				mcp.Min(1),
+			),
+			mcp.WithNumber("fault_id",
+				mcp.Required(),
+				mcp.Description("The ID of the fault to aggregate notices for"),
+				mcp.Min(1),
+			),
+			mcp.WithString("key",
+				mcp.Required(),
+				mcp.Description("Dot-separated path into each notice, e.g. 'request.params.id', 'environment.hostname', 'request.context.user_id', or 'backtrace.0.file'. Notices without the key are counted as missing."),
+			),
+			mcp.WithString("created_after",
+				mcp.Description("Only include notices created after this timestamp"+timestampHint),
+			),
+			mcp.WithString("created_before",
+				mcp.Description("Only include notices created before this timestamp"+timestampHint),
+			),
+			mcp.WithNumber("max_notices",
+				mcp.Description(fmt.Sprintf("Maximum number of notices to scan (default %d, max %d)", defaultAggregateNotices, maxAggregateNotices)),
+				mcp.Min(1),
+			),
+			mcp.WithNumber("top",
+				mcp.Description("Number of most common values to return; the rest are summed into other_count (default 20)"),
+				mcp.Min(1),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleAggregateFaultNotices(ctx, clientFor(ctx), req)
+		},
+	)
+
+	// summarize_notice_patterns tool
+	r.AddTool(
+		mcp.NewTool("summarize_notice_patterns",
+			mcp.WithTitleAnnotation("Summarize Notice Patterns"),
+			mcp.WithDescription("Summarize what a fault's notices have in common in one call, newest notices first: the most common URL patterns (paths with IDs replaced by :id), hostnames, and user agents; how many distinct values each request param takes, where a param with few values across many notices is a likely trigger; and the share of notices failing along the same application trace. Use this to start investigating a fault before aggregating by a single key with aggregate_fault_notices."),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithNumber("project_id",
+				mcp.Required(),
+				mcp.Description("The ID of the project containing the fault"),
+				mcp.Min(1),
+			),
+			mcp.WithNumber("fault_id",
+				mcp.Required(),
+				mcp.Description("The ID of the fault to summarize notices of"),
+				mcp.Min(1),
+			),
 			mcp.WithString("created_after",
-				mcp.Description("Filter faults created after this timestamp"),
+				mcp.Description("Only include notices created after this timestamp"+timestampHint),
+			),
+			mcp.WithString("created_before",
+				mcp.Description("Only include notices created before this timestamp"+timestampHint),
+			),
+			mcp.WithNumber("max_notices",
+				mcp.Description(fmt.Sprintf("Maximum number of notices to scan (default %d, max %d)", defaultAggregateNotices, maxAggregateNotices)),
+				mcp.Min(1),
+			),
+			mcp.WithNumber("top",
+				mcp.Description(fmt.Sprintf("Number of most common URL patterns, hostnames, and user agents to return; the rest are summed into each one's other_count (default %d)", defaultPatternTop)),
+				mcp.Min(1),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleSummarizeNoticePatterns(ctx, clientFor(ctx), req)
+		},
+	)
+
+	// get_fault_context_keys tool
+	r.AddTool(
+		mcp.NewTool("get_fault_context_keys",
+			mcp.WithTitleAnnotation("Get Fault Context Keys"),
+			mcp.WithDescription("Sample a fault's most recent notices and list every key in their request context, params, and session, with how often each appears, its value types, and a few example values. Use this to learn what metadata a fault's notices carry before aggregating by a key with aggregate_fault_notices or building a BadgerQL or affected-users query; each key's path can be passed to aggregate_fault_notices as is."),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithOutputSchema[contextKeys](),
+			mcp.WithNumber("project_id",
+				mcp.Required(),
+				mcp.Description("The ID of the project containing the fault"),
+				mcp.Min(1),
+			),
+			mcp.WithNumber("fault_id",
+				mcp.Required(),
+				mcp.Description("The ID of the fault to sample notices of"),
+				mcp.Min(1),
+			),
+			mcp.WithNumber("sample_size",
+				mcp.Description(fmt.Sprintf("Number of most recent notices to sample (default %d, max %d)", defaultContextKeySample, maxContextKeySample)),
+				mcp.Min(1),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleGetFaultContextKeys(ctx, clientFor(ctx), req)
+		},
+	)
+
+	// compare_fault_notices tool
+	r.AddTool(
+		mcp.NewTool("compare_fault_notices",
+			mcp.WithTitleAnnotation("Compare Fault Notices"),
+			mcp.WithDescription("Compare two notices of a fault and return what changed from the base notice to the other: added, removed, and changed request params and context keys, environment changes (hostname, revision, URL, component, action, web environment), and where their backtraces first differ. By default compares the latest notice with the one before it; pass base_before to compare with the last notice before a regression, e.g. a deploy time."),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithNumber("project_id",
+				mcp.Required(),
+				mcp.Description("The ID of the project containing the fault"),
+				mcp.Min(1),
+			),
+			mcp.WithNumber("fault_id",
+				mcp.Required(),
+				mcp.Description("The ID of the fault"),
+				mcp.Min(1),
+			),
+			mcp.WithString("notice_id",
+				mcp.Description(fmt.Sprintf("ID of the notice to compare, among the fault's %d most recent (default the latest)", maxCompareNotices)),
+			),
+			mcp.WithString("base_notice_id",
+				mcp.Description(fmt.Sprintf("ID of the notice to compare against, among the fault's %d most recent (default the notice before notice_id)", maxCompareNotices)),
+			),
+			mcp.WithString("base_before",
+				mcp.Description("Compare against the newest notice created before this timestamp instead of base_notice_id"+timestampHint),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleCompareFaultNotices(ctx, clientFor(ctx), req)
+		},
+	)
+
+	// analyze_fault_trend tool
+	r.AddTool(
+		mcp.NewTool("analyze_fault_trend",
+			mcp.WithTitleAnnotation("Analyze Fault Trend"),
+			mcp.WithDescription("Analyze how often a fault, or a whole project, has occurred over a recent window. Returns a one-line verdict (e.g. \"spiking since 14:00 UTC: peak 120/1h vs baseline 8/1h\") with a status of quiet, new, spiking, stopped, recovered, increasing, decreasing, or steady, plus first/last seen, total, baseline, peak, rate_of_change (second half of the window vs the first), and detected spikes. Use this instead of reading raw occurrence counts to decide whether something needs attention now."),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithOutputSchema[occurrenceTrend](),
+			mcp.WithNumber("project_id",
+				mcp.Required(),
+				mcp.Description("The ID of the project to analyze"),
+				mcp.Min(1),
+			),
+			mcp.WithNumber("fault_id",
+				mcp.Description("The ID of a fault to analyze. Omit to analyze all of the project's occurrences"),
+				mcp.Min(1),
+			),
+			mcp.WithString("window",
+				mcp.Description("How far back to look, e.g. '6h', '24h', or '7d' (1h to 30d, default 24h)"),
+			),
+			mcp.WithString("bucket",
+				mcp.Description("Bucket size for counting, e.g. '15m', '1h', or '1d'. Defaults to 1h for windows up to 2d, 6h up to 14d, and 1d beyond"),
+			),
+			mcp.WithNumber("spike_factor",
+				mcp.Description("A bucket counts as a spike when it exceeds this multiple of the baseline (median bucket) and at least 5 occurrences (default 3)"),
+			),
+			mcp.WithString("environment",
+				mcp.Description("Optional environment name to filter project occurrences by; ignored for faults"),
+			),
+			mcp.WithBoolean("include_series",
+				mcp.Description("Also return the bucketed counts as [unix_timestamp, count] pairs"),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleAnalyzeFaultTrend(ctx, clientFor(ctx), req)
+		},
+	)
+
+	// find_similar_faults tool
+	r.AddTool(
+		mcp.NewTool("find_similar_faults",
+			mcp.WithTitleAnnotation("Find Similar Faults"),
+			mcp.WithDescription("Find faults with the same error class as a given fault in the same project's resolved faults and in other projects, ranked by how closely their component and message match. Use this to discover how a recurring error was fixed before: resolved matches rank first among equals, and their comments and deploys often show the fix."),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithOutputSchema[similarFaults](),
+			mcp.WithNumber("project_id",
+				mcp.Required(),
+				mcp.Description("The ID of the project containing the fault"),
+				mcp.Min(1),
+			),
+			mcp.WithNumber("fault_id",
+				mcp.Required(),
+				mcp.Description("The ID of the fault to find similar faults for"),
+				mcp.Min(1),
+			),
+			mcp.WithString("search_in",
+				mcp.Description("Where to look: 'resolved' (the same project's resolved faults), 'other_projects' (faults in every other project), or 'all' (both; the default)"),
+				mcp.Enum(similarInResolved, similarInProjects, similarInAll),
+			),
+			mcp.WithString("account_id",
+				mcp.Description("Only search other projects in this account (see check_connection for account IDs)"),
+			),
+			mcp.WithNumber("limit",
+				mcp.Description("Maximum number of similar faults to return (default 10)"),
+				mcp.Min(1),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleFindSimilarFaults(ctx, clientFor(ctx), req)
+		},
+	)
+
+	// search_project_notices tool
+	r.AddTool(
+		mcp.NewTool("search_project_notices",
+			mcp.WithTitleAnnotation("Search Project Notices"),
+			mcp.WithDescription("Find the notices (individual error events) across all of a project's faults whose value at a key path contains a given value, e.g. every error on request.url '/checkout' or for context.user_email 'ada@example.com', newest first. Searches the faults matching field:\"value\" that occurred in a time window (default the last 7 days), and the most recent notices of each. Each match links its full notice at resource_uri."),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithNumber("project_id",
+				mcp.Required(),
+				mcp.Description("The ID of the project to search"),
+				mcp.Min(1),
+			),
+			mcp.WithString("field",
+				mcp.Required(),
+				mcp.Description("Dot-separated key path into the notice to match, e.g. 'request.url', 'request.params.order_id', 'context.user_email', or 'environment.hostname'"),
+			),
+			mcp.WithString("value",
+				mcp.Required(),
+				mcp.Description("Value to find at field; matches case-insensitively anywhere in the field's value"),
 			),
 			mcp.WithString("occurred_after",
-				mcp.Description("Filter faults that occurred after this timestamp"),
+				mcp.Description("Only notices created after this timestamp (default 7 days before occurred_before)"+timestampHint),
 			),
 			mcp.WithString("occurred_before",
-				mcp.Description("Filter faults that occurred before this timestamp"),
+				mcp.Description("Only notices created before this timestamp (default now)"+timestampHint),
+			),
+			mcp.WithNumber("limit",
+				mcp.Description(fmt.Sprintf("Maximum number of notices to return (default %d)", defaultNoticeSearchLimit)),
+				mcp.Min(1),
+				mcp.Max(maxNoticeSearchLimit),
 			),
 		),
 		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return handleGetFaultCounts(ctx, clientFor(ctx), req)
+			return handleSearchProjectNotices(ctx, clientFor(ctx), req)
 		},
 	)
 }
@@ -213,32 +639,180 @@ func handleListFaults(ctx context.Context, client *hbapi.Client, req mcp.CallToo
 		return mcp.NewToolResultError("project_id is required"), nil
 	}
 
-	// Build options struct
-	options := hbapi.FaultListOptions{
-		Q:              req.GetString("q", ""),
-		CreatedAfter:   parseTimestampValue(req.GetString("created_after", "")),
-		OccurredAfter:  parseTimestampValue(req.GetString("occurred_after", "")),
-		OccurredBefore: parseTimestampValue(req.GetString("occurred_before", "")),
-		Limit:          req.GetInt("limit", 0),
-		Order:          req.GetString("order", ""),
-		Page:           req.GetInt("page", 0),
+	page, err := pageArg(req)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	var notes toolNotes
+	options, errResult := faultFilterOptions(req, time.UTC, &notes)
+	if errResult != nil {
+		return errResult, nil
 	}
+	options.Limit = notes.limitArg(req)
+	options.Order = req.GetString("order", "")
+	options.Page = page
 
 	response, err := client.Faults.List(ctx, projectID, options)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to list faults: %v", err)), nil
 	}
+	notes.NextPageToken = nextPageToken(response.Links, "page")
+
+	var body any = response
+	if req.GetBool("enrich", false) {
+		body = hbapi.ListResponse[enrichedFault]{
+			Results: enrichFaults(ctx, client, projectID, options.Q, response.Results, time.Now(), &notes),
+			Links:   response.Links,
+		}
+	}
 
 	// Return JSON response
-	jsonBytes, err := json.Marshal(response)
+	jsonBytes, err := json.Marshal(body)
 	if err != nil {
 		return mcp.NewToolResultError("Failed to marshal response"), nil
 	}
 
-	return mcp.NewToolResultText(string(jsonBytes)), nil
+	return withNotes(mcp.NewToolResultText(string(jsonBytes)), &notes), nil
 }
 
-func handleGetFault(ctx context.Context, client *hbapi.Client, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+// resolveFaultTimeFilters parses the time filters shared by list_faults,
+// get_fault_counts, and get_account_fault_counts: created_after on its own, and the occurred_after/
+// occurred_before pair. The error result is non-nil when either is invalid.
+func resolveFaultTimeFilters(req mcp.CallToolRequest, loc *time.Location) (created, occurred timeWindow, errResult *mcp.CallToolResult) {
+	now := time.Now()
+	created, err := resolveTimeWindow(req, "created_after", "", now, loc)
+	if err != nil {
+		return created, occurred, mcp.NewToolResultError(err.Error())
+	}
+	occurred, err = resolveTimeWindow(req, "occurred_after", "occurred_before", now, loc)
+	if err != nil {
+		return created, occurred, mcp.NewToolResultError(err.Error())
+	}
+	return created, occurred, nil
+}
+
+// faultFilterArgs declares the fault filters list_faults,
+// get_fault_counts, and get_account_fault_counts share, which
+// faultFilterOptions reads. A new filter added here reaches all three.
+func faultFilterArgs(tool *mcp.Tool) {
+	faultQueryArgs(tool)
+	for _, opt := range []mcp.ToolOption{
+		mcp.WithString("created_after",
+			mcp.Description("Filter faults created after this timestamp"+timestampHint),
+		),
+		mcp.WithString("occurred_after",
+			mcp.Description("Filter faults that occurred after this timestamp"+timestampHint),
+		),
+		mcp.WithString("occurred_before",
+			mcp.Description("Filter faults that occurred before this timestamp"+timestampHint),
+		),
+	} {
+		opt(tool)
+	}
+}
+
+// faultQueryArgs declares the fault filters that faultQueryArg turns into
+// search terms, for tools that take their time range some other way.
+func faultQueryArgs(tool *mcp.Tool) {
+	for _, opt := range []mcp.ToolOption{
+		mcp.WithString("q",
+			mcp.Description("Search string to filter faults (see the errors reference topic for the search query syntax)"),
+		),
+		mcp.WithBoolean("resolved",
+			mcp.Description("Only faults that are resolved (true) or unresolved (false). Added to q as is:resolved or -is:resolved"),
+		),
+		mcp.WithBoolean("ignored",
+			mcp.Description("Only faults that are ignored (true) or not ignored (false). Added to q as is:ignored or -is:ignored"),
+		),
+		mcp.WithString("assignee",
+			mcp.Description("Only faults assigned to the user with this email address. Added to q as assignee:EMAIL"),
+		),
+		mcp.WithArray("tags",
+			mcp.Description("Only faults with every one of these tags, e.g. [\"billing\", \"p1\"]. Prefix a tag with - to leave out faults that have it. Added to q as tag:NAME or -tag:NAME"),
+			mcp.WithStringItems(mcp.MinLength(1)),
+		),
+	} {
+		opt(tool)
+	}
+}
+
+// faultFilterOptions returns the list options for the faultFilterArgs
+// arguments, with relative times resolved in loc and noted in notes. The
+// error result is non-nil when an argument is invalid.
+func faultFilterOptions(req mcp.CallToolRequest, loc *time.Location, notes *toolNotes) (hbapi.FaultListOptions, *mcp.CallToolResult) {
+	created, occurred, errResult := resolveFaultTimeFilters(req, loc)
+	if errResult != nil {
+		return hbapi.FaultListOptions{}, errResult
+	}
+	q, err := faultQueryArg(req)
+	if err != nil {
+		return hbapi.FaultListOptions{}, mcp.NewToolResultError(err.Error())
+	}
+	notes.addResolved(created.Resolved, occurred.Resolved)
+	return hbapi.FaultListOptions{
+		Q:              q,
+		CreatedAfter:   created.After,
+		OccurredAfter:  occurred.After,
+		OccurredBefore: occurred.Before,
+	}, nil
+}
+
+// faultQueryArg returns the q argument with the resolved, ignored,
+// assignee, and tags arguments translated into search terms and appended,
+// so agents don't have to write the syntax themselves.
+func faultQueryArg(req mcp.CallToolRequest) (string, error) {
+	args := req.GetArguments()
+	terms := []string{}
+	if q := strings.TrimSpace(req.GetString("q", "")); q != "" {
+		terms = append(terms, q)
+	}
+	for _, state := range []string{"resolved", "ignored"} {
+		raw, ok := args[state]
+		if !ok {
+			continue
+		}
+		val, ok := raw.(bool)
+		if !ok {
+			return "", fmt.Errorf("%s must be a boolean", state)
+		}
+		if val {
+			terms = append(terms, "is:"+state)
+		} else {
+			terms = append(terms, "-is:"+state)
+		}
+	}
+	if assignee := strings.TrimSpace(req.GetString("assignee", "")); assignee != "" {
+		if strings.ContainsAny(assignee, "\" \t") {
+			return "", fmt.Errorf("invalid assignee %q: use the user's email address", assignee)
+		}
+		terms = append(terms, "assignee:"+assignee)
+	}
+	if raw, ok := args["tags"]; ok && raw != nil {
+		tags, ok := raw.([]any)
+		if !ok {
+			return "", fmt.Errorf("tags must be an array of strings")
+		}
+		for _, t := range tags {
+			tag, _ := t.(string)
+			tag = strings.TrimSpace(tag)
+			negate := strings.HasPrefix(tag, "-")
+			name := strings.TrimPrefix(tag, "-")
+			if name == "" || strings.ContainsAny(name, "\" \t") {
+				return "", fmt.Errorf("invalid tag %q: tags can't be empty or contain spaces or quotes", t)
+			}
+			if negate {
+				terms = append(terms, "-tag:"+name)
+			} else {
+				terms = append(terms, "tag:"+name)
+			}
+		}
+	}
+	return strings.Join(terms, " "), nil
+}
+
+// handleGetFault gets a fault. With lookup, a 404 is followed by a search
+// of the token's other projects for the fault (see faultNotFoundHint).
+func handleGetFault(ctx context.Context, client *hbapi.Client, req mcp.CallToolRequest, lookup bool) (*mcp.CallToolResult, error) {
 	projectID := req.GetInt("project_id", 0)
 	if projectID == 0 {
 		return mcp.NewToolResultError("project_id is required"), nil
@@ -251,7 +825,11 @@ func handleGetFault(ctx context.Context, client *hbapi.Client, req mcp.CallToolR
 
 	fault, err := client.Faults.Get(ctx, projectID, faultID)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to get fault: %v", err)), nil
+		hint := ""
+		if lookup {
+			hint = faultNotFoundHint(ctx, client, err, projectID, faultID)
+		}
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to get fault: %v%s", err, hint)), nil
 	}
 
 	// Return JSON response
@@ -349,11 +927,92 @@ func handleListFaultNotices(ctx context.Context, client *hbapi.Client, req mcp.C
 		return mcp.NewToolResultError("fault_id is required"), nil
 	}
 
+	created, err := resolveTimeWindow(req, "created_after", "created_before", time.Now(), time.UTC)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	var notes toolNotes
+	notes.addResolved(created.Resolved)
+
 	// Build options struct
 	options := hbapi.FaultListNoticesOptions{
-		CreatedAfter:  parseTimestampValue(req.GetString("created_after", "")),
-		CreatedBefore: parseTimestampValue(req.GetString("created_before", "")),
-		Limit:         req.GetInt("limit", 0),
+		CreatedAfter:  created.After,
+		CreatedBefore: created.Before,
+	}
+	cursor, err := createdBeforeArg(req)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if !cursor.IsZero() {
+		options.CreatedBefore = cursor
+	}
+	filter, err := noticeFilterArgs(req)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	maxBytes := req.GetInt("max_notice_bytes", defaultMaxNoticeBytes)
+	latest := req.GetBool("latest", false)
+	if req.GetBool("first", false) {
+		switch {
+		case latest:
+			return mcp.NewToolResultError("first and latest can't be combined"), nil
+		case req.GetInt("sample", 0) != 0:
+			return mcp.NewToolResultError("first and sample can't be combined"), nil
+		case !cursor.IsZero():
+			return mcp.NewToolResultError("first can't be combined with page_token"), nil
+		}
+		if limit := req.GetInt("limit", 0); limit > 1 {
+			notes.warnf("limit %d ignored because first is set", limit)
+		}
+		notice, err := firstFaultNotice(ctx, client, projectID, faultID, created, time.Now(), &notes)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to find first fault notice: %v", err)), nil
+		}
+		if notice == nil {
+			return mcp.NewToolResultError("No notices found for this fault"), nil
+		}
+		var payload any = notice
+		if filter.active() {
+			notices, err := filter.apply([]hbapi.Notice{*notice}, &notes)
+			if err != nil {
+				return mcp.NewToolResultError("Failed to marshal response"), nil
+			}
+			payload = notices[0]
+		}
+		return noticesResult(projectID, faultID, payload, maxBytes, &notes), nil
+	}
+	if sample := req.GetInt("sample", 0); sample != 0 {
+		switch {
+		case sample < 2 || sample > maxNoticeSample:
+			return mcp.NewToolResultError(fmt.Sprintf("sample must be between 2 and %d", maxNoticeSample)), nil
+		case latest:
+			return mcp.NewToolResultError("sample and latest can't be combined"), nil
+		case !cursor.IsZero():
+			return mcp.NewToolResultError("sample can't be combined with page_token"), nil
+		}
+		if limit := req.GetInt("limit", 0); limit > 0 {
+			notes.warnf("limit %d ignored because sample is set", limit)
+		}
+		notices, err := sampleFaultNotices(ctx, client, projectID, faultID, created, sample, time.Now(), &notes)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to sample fault notices: %v", err)), nil
+		}
+		var results any = notices
+		if filter.active() {
+			if results, err = filter.apply(notices, &notes); err != nil {
+				return mcp.NewToolResultError("Failed to marshal response"), nil
+			}
+		}
+		return noticesResult(projectID, faultID, map[string]any{"results": results}, maxBytes, &notes), nil
+	}
+	if latest {
+		// Notices come back newest first, so one is all the API needs to send.
+		if limit := req.GetInt("limit", 0); limit > 1 {
+			notes.warnf("limit %d ignored because latest is set", limit)
+		}
+		options.Limit = 1
+	} else {
+		options.Limit = notes.limitArg(req)
 	}
 
 	response, err := client.Faults.ListNotices(ctx, projectID, faultID, options)
@@ -361,13 +1020,48 @@ func handleListFaultNotices(ctx context.Context, client *hbapi.Client, req mcp.C
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to list fault notices: %v", err)), nil
 	}
 
+	if latest && len(response.Results) == 0 {
+		return mcp.NewToolResultError("No notices found for this fault"), nil
+	}
+	if !latest {
+		notes.NextPageToken = nextPageToken(response.Links, "created_before")
+	}
+
+	var payload any = response
+	if latest {
+		payload = response.Results[0]
+	}
+	if filter.active() {
+		notices, err := filter.apply(response.Results, &notes)
+		if err != nil {
+			return mcp.NewToolResultError("Failed to marshal response"), nil
+		}
+		if latest {
+			payload = notices[0]
+		} else {
+			payload = map[string]any{"results": notices, "links": response.Links}
+		}
+	}
+	return noticesResult(projectID, faultID, payload, maxBytes, &notes), nil
+}
+
+// noticesResult renders list_fault_notices' payload, trimming notices
+// over maxBytes and linking each trimmed one's full JSON after it.
+func noticesResult(projectID, faultID int, payload any, maxBytes int, notes *toolNotes) *mcp.CallToolResult {
+	payload, links, err := trimLargeNotices(projectID, faultID, payload, maxBytes, notes)
+	if err != nil {
+		return mcp.NewToolResultError("Failed to marshal response")
+	}
+
 	// Return JSON response
-	jsonBytes, err := json.Marshal(response)
+	jsonBytes, err := json.Marshal(payload)
 	if err != nil {
-		return mcp.NewToolResultError("Failed to marshal response"), nil
+		return mcp.NewToolResultError("Failed to marshal response")
 	}
 
-	return mcp.NewToolResultText(string(jsonBytes)), nil
+	result := mcp.NewToolResultText(string(jsonBytes))
+	result.Content = append(result.Content, links...)
+	return withNotes(result, notes)
 }
 func handleListFaultAffectedUsers(ctx context.Context, client *hbapi.Client, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	projectID := req.GetInt("project_id", 0)
@@ -406,12 +1100,14 @@ func handleGetFaultCounts(ctx context.Context, client *hbapi.Client, req mcp.Cal
 		return mcp.NewToolResultError("project_id is required"), nil
 	}
 
-	// Build options struct (reuse same filtering options as List)
-	options := hbapi.FaultListOptions{
-		Q:              req.GetString("q", ""),
-		CreatedAfter:   parseTimestampValue(req.GetString("created_after", "")),
-		OccurredAfter:  parseTimestampValue(req.GetString("occurred_after", "")),
-		OccurredBefore: parseTimestampValue(req.GetString("occurred_before", "")),
+	loc, err := timezoneArg(req)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	var notes toolNotes
+	options, errResult := faultFilterOptions(req, loc, &notes)
+	if errResult != nil {
+		return errResult, nil
 	}
 
 	counts, err := client.Faults.GetCounts(ctx, projectID, options)
@@ -425,5 +1121,85 @@ func handleGetFaultCounts(ctx context.Context, client *hbapi.Client, req mcp.Cal
 		return mcp.NewToolResultError("Failed to marshal response"), nil
 	}
 
-	return mcp.NewToolResultText(string(jsonBytes)), nil
+	return withNotes(mcp.NewToolResultText(string(jsonBytes)), &notes), nil
+}
+
+// projectFaultCounts is one project's row of get_account_fault_counts.
+type projectFaultCounts struct {
+	ProjectID   int    `json:"project_id"`
+	ProjectName string `json:"project_name"`
+	Total       int    `json:"total"`
+	Unresolved  int    `json:"unresolved"`
+	Ignored     int    `json:"ignored"`
+}
+
+// accountFaultCounts is the get_account_fault_counts response.
+type accountFaultCounts struct {
+	Projects   []projectFaultCounts `json:"projects"`
+	Total      int                  `json:"total"`
+	Unresolved int                  `json:"unresolved"`
+	Ignored    int                  `json:"ignored"`
+}
+
+func handleGetAccountFaultCounts(ctx context.Context, client *hbapi.Client, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var notes toolNotes
+	options, errResult := faultFilterOptions(req, time.UTC, &notes)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	var projects *hbapi.ProjectsResponse
+	var err error
+	if accountID := req.GetString("account_id", ""); accountID != "" {
+		projects, err = client.Projects.ListByAccountID(ctx, accountID)
+	} else {
+		projects, err = client.Projects.ListAll(ctx)
+	}
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to list projects: %v", err)), nil
+	}
+	if projects.Links.Next != "" {
+		notes.warnf("only the first %d projects were counted; pass account_id to narrow the list", len(projects.Results))
+	}
+
+	allowed := slices.DeleteFunc(slices.Clone(projects.Results), func(p hbapi.Project) bool { return !projectAllowed(ctx, p.ID) })
+	response := accountFaultCounts{Projects: []projectFaultCounts{}}
+	err = eachProject(allowed, func(p hbapi.Project) (int, string) { return p.ID, p.Name }, &notes, func(p hbapi.Project) error {
+		counts, err := client.Faults.GetCounts(ctx, p.ID, options)
+		if err != nil {
+			return err
+		}
+		row := projectFaultCounts{ProjectID: p.ID, ProjectName: p.Name, Total: counts.Total}
+		for _, env := range counts.Environments {
+			switch {
+			case env.Ignored:
+				row.Ignored += env.Count
+			case !env.Resolved:
+				row.Unresolved += env.Count
+			}
+		}
+		response.Projects = append(response.Projects, row)
+		response.Total += row.Total
+		response.Unresolved += row.Unresolved
+		response.Ignored += row.Ignored
+		return nil
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to get fault counts: %v", err)), nil
+	}
+	sort.SliceStable(response.Projects, func(i, j int) bool {
+		a, b := response.Projects[i], response.Projects[j]
+		if a.Unresolved != b.Unresolved {
+			return a.Unresolved > b.Unresolved
+		}
+		return a.ProjectID < b.ProjectID
+	})
+
+	// Return JSON response
+	jsonBytes, err := json.Marshal(response)
+	if err != nil {
+		return mcp.NewToolResultError("Failed to marshal response"), nil
+	}
+
+	return withNotes(mcp.NewToolResultText(string(jsonBytes)), &notes), nil
 }
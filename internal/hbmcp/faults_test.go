@@ -6,6 +6,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -135,6 +136,65 @@ func TestHandleListFaults_WithOptions(t *testing.T) {
 	}
 }
 
+func TestHandleListFaults_StateFilters(t *testing.T) {
+	var gotQ string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotQ = r.URL.Query().Get("q")
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"results": []}`))
+	}))
+	defer server.Close()
+
+	client := hbapi.NewClient().
+		WithBaseURL(server.URL).
+		WithAuthToken("test-token")
+
+	tests := []struct {
+		name    string
+		args    map[string]interface{}
+		wantQ   string
+		wantErr string
+	}{
+		{"unresolved", map[string]interface{}{"resolved": false}, "-is:resolved", ""},
+		{"combined with q", map[string]interface{}{"q": "environment:production", "resolved": false, "ignored": false, "assignee": "jane@example.com"}, "environment:production -is:resolved -is:ignored assignee:jane@example.com", ""},
+		{"ignored", map[string]interface{}{"ignored": true}, "is:ignored", ""},
+		{"non-boolean state", map[string]interface{}{"resolved": "yes"}, "", "resolved must be a boolean"},
+		{"assignee with a space", map[string]interface{}{"assignee": "Jane Doe"}, "", "invalid assignee"},
+		{"tags", map[string]interface{}{"q": "class:RuntimeError", "tags": []any{"billing", " -flaky "}}, "class:RuntimeError tag:billing -tag:flaky", ""},
+		{"tag with a space", map[string]interface{}{"tags": []any{"needs triage"}}, "", "invalid tag"},
+		{"non-array tags", map[string]interface{}{"tags": "billing"}, "", "tags must be an array"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotQ = ""
+			tt.args["project_id"] = 123
+			req := mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: tt.args}}
+			for name, handler := range map[string]func(context.Context, *hbapi.Client, mcp.CallToolRequest) (*mcp.CallToolResult, error){
+				"list_faults":      handleListFaults,
+				"get_fault_counts": handleGetFaultCounts,
+			} {
+				result, err := handler(context.Background(), client, req)
+				if err != nil {
+					t.Fatalf("%s error = %v", name, err)
+				}
+				if tt.wantErr != "" {
+					if !result.IsError || !strings.Contains(getResultText(result), tt.wantErr) {
+						t.Errorf("%s: expected error %q, got %s", name, tt.wantErr, getResultText(result))
+					}
+					continue
+				}
+				if result.IsError {
+					t.Fatalf("%s: unexpected error: %s", name, getResultText(result))
+				}
+				if gotQ != tt.wantQ {
+					t.Errorf("%s: expected q=%q, got %q", name, tt.wantQ, gotQ)
+				}
+			}
+		})
+	}
+}
+
 func TestHandleListFaults_WithPage(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		query := r.URL.Query()
@@ -285,7 +345,7 @@ func TestHandleGetFault(t *testing.T) {
 		},
 	}
 
-	result, err := handleGetFault(context.Background(), client, req)
+	result, err := handleGetFault(context.Background(), client, req, false)
 	if err != nil {
 		t.Fatalf("handleGetFault() error = %v", err)
 	}
@@ -326,7 +386,7 @@ func TestHandleGetFault_MissingProjectID(t *testing.T) {
 		},
 	}
 
-	result, err := handleGetFault(context.Background(), client, req)
+	result, err := handleGetFault(context.Background(), client, req, false)
 	if err != nil {
 		t.Fatalf("handleGetFault() error = %v", err)
 	}
@@ -352,7 +412,7 @@ func TestHandleGetFault_MissingFaultID(t *testing.T) {
 		},
 	}
 
-	result, err := handleGetFault(context.Background(), client, req)
+	result, err := handleGetFault(context.Background(), client, req, false)
 	if err != nil {
 		t.Fatalf("handleGetFault() error = %v", err)
 	}
@@ -379,7 +439,7 @@ func TestHandleGetFault_InvalidProjectID(t *testing.T) {
 		},
 	}
 
-	result, err := handleGetFault(context.Background(), client, req)
+	result, err := handleGetFault(context.Background(), client, req, false)
 	if err != nil {
 		t.Fatalf("handleGetFault() error = %v", err)
 	}
@@ -406,7 +466,7 @@ func TestHandleGetFault_InvalidFaultID(t *testing.T) {
 		},
 	}
 
-	result, err := handleGetFault(context.Background(), client, req)
+	result, err := handleGetFault(context.Background(), client, req, false)
 	if err != nil {
 		t.Fatalf("handleGetFault() error = %v", err)
 	}
@@ -441,7 +501,7 @@ func TestHandleGetFault_Error(t *testing.T) {
 		},
 	}
 
-	result, err := handleGetFault(context.Background(), client, req)
+	result, err := handleGetFault(context.Background(), client, req, false)
 	if err != nil {
 		t.Fatalf("handleGetFault() error = %v", err)
 	}
@@ -902,6 +962,82 @@ func TestHandleListFaultNotices_WithOptions(t *testing.T) {
 	}
 }
 
+func TestHandleListFaultNotices_Latest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("limit"); got != "1" {
+			t.Errorf("expected limit=1, got %s", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"results": [{"id": "notice-newest", "message": "boom"}]}`))
+	}))
+	defer server.Close()
+
+	client := hbapi.NewClient().
+		WithBaseURL(server.URL).
+		WithAuthToken("test-token")
+
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Arguments: map[string]interface{}{
+				"project_id": 123,
+				"fault_id":   456,
+				"latest":     true,
+				"limit":      10,
+			},
+		},
+	}
+
+	result, err := handleListFaultNotices(context.Background(), client, req)
+	if err != nil {
+		t.Fatalf("handleListFaultNotices() error = %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected successful result, got error: %s", getResultText(result))
+	}
+
+	var notice hbapi.Notice
+	if err := json.Unmarshal([]byte(getResultText(result)), &notice); err != nil {
+		t.Fatalf("expected a single notice object: %v", err)
+	}
+	if notice.ID != "notice-newest" {
+		t.Errorf("expected notice-newest, got %q", notice.ID)
+	}
+	notes := getResultNotes(t, result)
+	if len(notes.Warnings) != 1 || !strings.Contains(notes.Warnings[0], "limit 10 ignored") {
+		t.Errorf("expected ignored-limit warning, got %v", notes.Warnings)
+	}
+}
+
+func TestHandleListFaultNotices_LatestNoNotices(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"results": []}`))
+	}))
+	defer server.Close()
+
+	client := hbapi.NewClient().
+		WithBaseURL(server.URL).
+		WithAuthToken("test-token")
+
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Arguments: map[string]interface{}{
+				"project_id": 123,
+				"fault_id":   456,
+				"latest":     true,
+			},
+		},
+	}
+
+	result, err := handleListFaultNotices(context.Background(), client, req)
+	if err != nil {
+		t.Fatalf("handleListFaultNotices() error = %v", err)
+	}
+	if !result.IsError {
+		t.Fatal("expected error result when the fault has no notices")
+	}
+}
+
 func TestHandleListFaultNotices_MissingProjectID(t *testing.T) {
 	client := hbapi.NewClient()
 
@@ -1356,3 +1492,106 @@ func TestHandleGetFaultCounts_Error(t *testing.T) {
 		t.Error("Error message should contain 'Failed to get fault counts'")
 	}
 }
+
+func TestHandleGetAccountFaultCounts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v2/projects":
+			if got := r.URL.Query().Get("account_id"); got != "abc" {
+				t.Errorf("expected account_id abc, got %q", got)
+			}
+			_, _ = w.Write([]byte(`{"results": [{"id": 1, "name": "API"}, {"id": 2, "name": "Web"}, {"id": 3, "name": "Legacy"}]}`))
+		case "/v2/projects/1/faults/summary":
+			if got := r.URL.Query().Get("q"); got != "-is:resolved" {
+				t.Errorf("expected q to be passed through, got %q", got)
+			}
+			_, _ = w.Write([]byte(`{"total": 10, "environments": [
+				{"environment": "production", "resolved": false, "ignored": false, "count": 2},
+				{"environment": "production", "resolved": true, "ignored": false, "count": 7},
+				{"environment": "staging", "resolved": false, "ignored": true, "count": 1}
+			]}`))
+		case "/v2/projects/2/faults/summary":
+			_, _ = w.Write([]byte(`{"total": 5, "environments": [{"environment": "production", "resolved": false, "ignored": false, "count": 5}]}`))
+		default:
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors": "Forbidden"}`))
+		}
+	}))
+	defer server.Close()
+
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"account_id": "abc", "q": "-is:resolved"}
+
+	result, err := handleGetAccountFaultCounts(context.Background(), client, req)
+	if err != nil {
+		t.Fatalf("handleGetAccountFaultCounts() error = %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got %s", getResultText(result))
+	}
+
+	var response accountFaultCounts
+	if err := json.Unmarshal([]byte(getResultText(result)), &response); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	want := []projectFaultCounts{
+		{ProjectID: 2, ProjectName: "Web", Total: 5, Unresolved: 5},
+		{ProjectID: 1, ProjectName: "API", Total: 10, Unresolved: 2, Ignored: 1},
+	}
+	if !reflect.DeepEqual(response.Projects, want) {
+		t.Errorf("projects = %+v, want %+v", response.Projects, want)
+	}
+	if response.Total != 15 || response.Unresolved != 7 || response.Ignored != 1 {
+		t.Errorf("unexpected totals: %+v", response)
+	}
+	notes := getResultNotes(t, result)
+	if len(notes.Warnings) != 1 || !strings.Contains(notes.Warnings[0], "skipped project 3 (Legacy)") {
+		t.Errorf("expected a warning for the skipped project, got %v", notes.Warnings)
+	}
+}
+
+func TestHandleGetAccountFaultCounts_Filters(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/v2/projects" {
+			_, _ = w.Write([]byte(`{"results": [{"id": 1, "name": "API"}]}`))
+			return
+		}
+		if got := r.URL.Query().Get("q"); got != "class:RuntimeError -is:ignored assignee:ada@example.com" {
+			t.Errorf("expected the filters added to q, got %q", got)
+		}
+		_, _ = w.Write([]byte(`{"total": 1, "environments": []}`))
+	}))
+	defer server.Close()
+
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"q": "class:RuntimeError", "ignored": false, "assignee": "ada@example.com"}
+	if result, err := handleGetAccountFaultCounts(context.Background(), client, req); err != nil || result.IsError {
+		t.Fatalf("unexpected error: %v %s", err, getResultText(result))
+	}
+}
+
+func TestHandleGetAccountFaultCounts_AllFail(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/v2/projects" {
+			_, _ = w.Write([]byte(`{"results": [{"id": 1, "name": "API"}]}`))
+			return
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"errors": "Invalid API token"}`))
+	}))
+	defer server.Close()
+
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+	result, err := handleGetAccountFaultCounts(context.Background(), client, mcp.CallToolRequest{})
+	if err != nil {
+		t.Fatalf("handleGetAccountFaultCounts() error = %v", err)
+	}
+	if !result.IsError || !strings.Contains(getResultText(result), "Failed to get fault counts") {
+		t.Errorf("expected a fault counts error, got %s", getResultText(result))
+	}
+}
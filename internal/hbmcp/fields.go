@@ -0,0 +1,248 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const fieldsArg = "fields"
+
+// fieldTree is a parsed fields selection: the keys to keep at one level
+// of the result, each with the selection under it. A nil tree keeps the
+// whole value.
+type fieldTree map[string]fieldTree
+
+// withFieldsArg adds the fields parameter every tool accepts.
+func withFieldsArg(tool *mcp.Tool) {
+	mcp.WithString(fieldsArg,
+		mcp.Description("Optional selection of the fields to return from a JSON result, to save tokens: comma-separated dot paths, where [*] steps into every element of an array and {a,b} selects several keys, e.g. 'results[*].{id,klass,message,notices_count}'. Arrays are stepped into even without [*]. Other keys are left out."),
+	)(tool)
+}
+
+// shapeFields trims a successful JSON result to the fields argument. The
+// selection is checked before the handler runs, so a bad one fails
+// without side effects; a result that isn't JSON is returned whole with a
+// warning.
+func shapeFields(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		raw, ok := req.GetArguments()[fieldsArg]
+		if !ok || raw == nil {
+			return next(ctx, req)
+		}
+		s, ok := raw.(string)
+		if !ok {
+			return mcp.NewToolResultError(fieldsArg + " must be a string"), nil
+		}
+		tree, err := parseFields(s)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid %s: %v", fieldsArg, err)), nil
+		}
+
+		result, err := next(ctx, req)
+		if err != nil || result == nil || result.IsError || tree == nil || len(result.Content) == 0 {
+			return result, err
+		}
+		text, ok := result.Content[0].(mcp.TextContent)
+		if !ok {
+			return result, nil
+		}
+
+		dec := json.NewDecoder(strings.NewReader(text.Text))
+		dec.UseNumber()
+		var v any
+		if err := dec.Decode(&v); err != nil || dec.More() {
+			addWarning(result, "fields ignored: this result isn't JSON")
+			return result, nil
+		}
+		matched := false
+		shaped, err := json.Marshal(tree.apply(v, &matched))
+		if err != nil {
+			return result, nil
+		}
+		text.Text = string(shaped)
+		result.Content[0] = text
+		if !matched {
+			addWarning(result, "fields matched nothing in the result; compare the paths with an unfiltered call")
+		}
+		return result, nil
+	}
+}
+
+// parseFields parses a fields selection. An empty one selects nothing to
+// trim and returns a nil tree.
+func parseFields(s string) (fieldTree, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	paths, err := expandFields(s)
+	if err != nil {
+		return nil, err
+	}
+	tree := fieldTree{}
+	for _, p := range paths {
+		p = strings.TrimPrefix(strings.TrimPrefix(p, "$"), ".")
+		p = strings.ReplaceAll(p, "[*]", "")
+		if strings.ContainsAny(p, "[]{}") {
+			return nil, fmt.Errorf("%q: only [*] and {a,b} are supported", p)
+		}
+		segments := strings.Split(p, ".")
+		for _, seg := range segments {
+			if seg == "" {
+				return nil, fmt.Errorf("%q has an empty key", p)
+			}
+		}
+		tree.add(segments)
+	}
+	return tree, nil
+}
+
+// expandFields splits a selection into plain paths, expanding each {a,b}
+// group into one path per alternative.
+func expandFields(s string) ([]string, error) {
+	parts, err := splitTopLevel(s)
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		open := strings.IndexByte(part, '{')
+		if open < 0 {
+			paths = append(paths, part)
+			continue
+		}
+		end := matchingBrace(part, open)
+		if end < 0 {
+			return nil, fmt.Errorf("%q has an unclosed {", part)
+		}
+		inner, err := expandFields(part[open+1 : end])
+		if err != nil {
+			return nil, err
+		}
+		for _, alt := range inner {
+			expanded, err := expandFields(part[:open] + alt + part[end+1:])
+			if err != nil {
+				return nil, err
+			}
+			paths = append(paths, expanded...)
+		}
+	}
+	return paths, nil
+}
+
+// splitTopLevel splits s at the commas outside braces.
+func splitTopLevel(s string) ([]string, error) {
+	var parts []string
+	depth, start := 0, 0
+	for i, c := range s {
+		switch c {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth < 0 {
+				return nil, fmt.Errorf("%q has an unmatched }", s)
+			}
+		case ',':
+			if depth == 0 {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("%q has an unclosed {", s)
+	}
+	return append(parts, s[start:]), nil
+}
+
+// matchingBrace returns the index of the } closing the { at open, or -1.
+func matchingBrace(s string, open int) int {
+	depth := 0
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// add selects the path of keys. Selecting a key whole wins over
+// selecting some of its fields.
+func (t fieldTree) add(path []string) {
+	key := path[0]
+	sub, seen := t[key]
+	if len(path) == 1 {
+		t[key] = nil
+		return
+	}
+	if seen && sub == nil {
+		return
+	}
+	if sub == nil {
+		sub = fieldTree{}
+		t[key] = sub
+	}
+	sub.add(path[1:])
+}
+
+// apply returns the selected parts of v, stepping into arrays. matched is
+// set when any selected key is found.
+func (t fieldTree) apply(v any, matched *bool) any {
+	if t == nil {
+		return v
+	}
+	switch v := v.(type) {
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = t.apply(item, matched)
+		}
+		return out
+	case map[string]any:
+		out := map[string]any{}
+		for key, sub := range t {
+			if value, ok := v[key]; ok {
+				*matched = true
+				out[key] = sub.apply(value, matched)
+			}
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// addWarning adds a warning to a result's notes, appending a notes block
+// if it has none.
+func addWarning(result *mcp.CallToolResult, warning string) {
+	if n := len(result.Content); n > 1 {
+		if text, ok := result.Content[n-1].(mcp.TextContent); ok {
+			var notes toolNotes
+			dec := json.NewDecoder(strings.NewReader(text.Text))
+			dec.DisallowUnknownFields()
+			if dec.Decode(&notes) == nil {
+				notes.warnf("%s", warning)
+				if jsonBytes, err := json.Marshal(notes); err == nil {
+					text.Text = string(jsonBytes)
+					result.Content[n-1] = text
+					return
+				}
+			}
+		}
+	}
+	var notes toolNotes
+	notes.warnf("%s", warning)
+	withNotes(result, &notes)
+}
@@ -0,0 +1,103 @@
+package hbmcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestShapeFields(t *testing.T) {
+	body := `{"results": [
+		{"id": 1, "klass": "RuntimeError", "message": "boom", "assignee": {"id": 7, "name": "Ann"}, "tags": ["a"]},
+		{"id": 12345678901234567890, "klass": "KeyError", "message": "key", "assignee": null, "tags": []}
+	], "links": {"next": "x"}}`
+	tests := []struct {
+		fields string
+		want   string
+	}{
+		{"results[*].{id,klass}", `{"results":[{"id":1,"klass":"RuntimeError"},{"id":12345678901234567890,"klass":"KeyError"}]}`},
+		{"results.id, links", `{"links":{"next":"x"},"results":[{"id":1},{"id":12345678901234567890}]}`},
+		{"$.results[*].{id,assignee.name}", `{"results":[{"assignee":{"name":"Ann"},"id":1},{"assignee":null,"id":12345678901234567890}]}`},
+		{"results[*].{assignee.name,assignee}", `{"results":[{"assignee":{"id":7,"name":"Ann"}},{"assignee":null}]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fields, func(t *testing.T) {
+			handler := shapeFields(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				return mcp.NewToolResultText(body), nil
+			})
+			req := mcp.CallToolRequest{}
+			req.Params.Arguments = map[string]any{fieldsArg: tt.fields}
+			result, err := handler(context.Background(), req)
+			if err != nil || result.IsError {
+				t.Fatalf("unexpected error: %v %s", err, getResultText(result))
+			}
+			if got := getResultText(result); got != tt.want {
+				t.Errorf("got %s\nwant %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShapeFields_Invalid(t *testing.T) {
+	for _, fields := range []string{"results[0].id", "results.{id,klass", "results..id", "results.{}", "id}"} {
+		ran := false
+		handler := shapeFields(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			ran = true
+			return mcp.NewToolResultText(`{}`), nil
+		})
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = map[string]any{fieldsArg: fields}
+		result, _ := handler(context.Background(), req)
+		if !result.IsError || !strings.Contains(getResultText(result), "invalid fields") || ran {
+			t.Errorf("%q: expected a validation error before the handler ran, got %q", fields, getResultText(result))
+		}
+	}
+}
+
+func TestShapeFields_Warnings(t *testing.T) {
+	tests := []struct {
+		name   string
+		result *mcp.CallToolResult
+		want   string
+	}{
+		{"not JSON", mcp.NewToolResultText("id,klass\n1,RuntimeError\n"), "isn't JSON"},
+		{"no match", withNotes(mcp.NewToolResultText(`{"results": []}`), &toolNotes{NextPageToken: "p2"}), "matched nothing"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := shapeFields(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				return tt.result, nil
+			})
+			req := mcp.CallToolRequest{}
+			req.Params.Arguments = map[string]any{fieldsArg: "id"}
+			result, _ := handler(context.Background(), req)
+			notes := getResultNotes(t, result)
+			if len(notes.Warnings) != 1 || !strings.Contains(notes.Warnings[0], tt.want) {
+				t.Errorf("expected a warning containing %q, got %+v", tt.want, notes)
+			}
+			if tt.name == "no match" && (len(result.Content) != 2 || notes.NextPageToken != "p2") {
+				t.Errorf("expected the warning added to the existing notes, got %+v", result.Content)
+			}
+		})
+	}
+}
+
+func TestToolRegistrar_FieldsArg(t *testing.T) {
+	s := server.NewMCPServer("test", "1.0.0", server.WithToolCapabilities(true))
+	r := newToolRegistrar(s)
+	r.AddTool(mcp.NewTool("example"), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText(`{"id": 1, "name": "x"}`), nil
+	})
+
+	tool := s.GetTool("example")
+	if _, ok := tool.Tool.InputSchema.Properties[fieldsArg]; !ok {
+		t.Errorf("expected %s in the tool's input schema", fieldsArg)
+	}
+	result, err := tool.Handler(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]any{fieldsArg: "id"}}})
+	if err != nil || getResultText(result) != `{"id":1}` {
+		t.Errorf("expected the result trimmed to id, got %q, %v", getResultText(result), err)
+	}
+}
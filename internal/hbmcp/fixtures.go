@@ -0,0 +1,66 @@
+package hbmcp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+)
+
+// fixtureTransport answers API requests from recorded JSON responses under
+// dir instead of the network. A request is served from the file named for
+// its method and path, without the /v2 prefix: GET /v2/projects/1/faults
+// reads GET/projects/1/faults.json. When the request has a query string,
+// a file whose name adds "@" and the sorted, encoded query
+// (GET/projects/1/faults@page=2&q=foo.json) is preferred, so one path can
+// have per-query responses and a catch-all. Requests with no fixture get a
+// 404 naming the file that was looked for.
+type fixtureTransport struct {
+	dir    string
+	logger *slog.Logger
+}
+
+func (t *fixtureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	names := fixtureNames(req)
+	for _, name := range names {
+		// fixtureNames has already resolved ".." segments taken from tool
+		// arguments; OpenInRoot also keeps symlinks inside the directory.
+		f, err := os.OpenInRoot(t.dir, name)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading fixture %s: %w", name, err)
+		}
+		body, err := io.ReadAll(f)
+		_ = f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("reading fixture %s: %w", name, err)
+		}
+		t.logger.DebugContext(req.Context(), "Serving API fixture", "method", req.Method, "path", req.URL.Path, "fixture", name)
+		return localResponse(req, http.StatusOK, string(body)), nil
+	}
+
+	t.logger.WarnContext(req.Context(), "No API fixture for request", "method", req.Method, "path", req.URL.Path, "query", req.URL.RawQuery)
+	message, _ := json.Marshal(map[string]string{
+		"errors": fmt.Sprintf("no fixture for %s %s: add %s to the fixtures directory", req.Method, req.URL.Path, names[0]),
+	})
+	return localResponse(req, http.StatusNotFound, string(message)), nil
+}
+
+// fixtureNames lists the files that can answer req, most specific first.
+func fixtureNames(req *http.Request) []string {
+	base := path.Clean("/" + strings.TrimPrefix(req.URL.Path, "/v2"))
+	base = req.Method + base
+	names := []string{base + ".json"}
+	if query := req.URL.Query(); len(query) > 0 {
+		names = append([]string{base + "@" + query.Encode() + ".json"}, names...)
+	}
+	return names
+}
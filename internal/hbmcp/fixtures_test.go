@@ -0,0 +1,84 @@
+package hbmcp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	hbapi "github.com/honeybadger-io/api-go"
+
+	"github.com/honeybadger-io/honeybadger-mcp-server/internal/config"
+)
+
+func writeFixture(t *testing.T, dir, name, body string) {
+	t.Helper()
+	path := filepath.Join(dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFixtureNames(t *testing.T) {
+	tests := []struct {
+		method, url string
+		want        []string
+	}{
+		{"GET", "https://app.honeybadger.io/v2/projects", []string{"GET/projects.json"}},
+		{"POST", "https://app.honeybadger.io/v2/projects/1/insights/queries", []string{"POST/projects/1/insights/queries.json"}},
+		{"GET", "https://app.honeybadger.io/v2/projects/1/faults?q=foo&page=2", []string{"GET/projects/1/faults@page=2&q=foo.json", "GET/projects/1/faults.json"}},
+		{"GET", "https://app.honeybadger.io/v2/projects/1/dashboards/../../../../secret", []string{"GET/secret.json"}},
+	}
+	for _, tt := range tests {
+		req, _ := http.NewRequest(tt.method, tt.url, nil)
+		got := fixtureNames(req)
+		if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+			t.Errorf("fixtureNames(%s %s) = %q, want %q", tt.method, tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestNewClientFactory_Fixtures(t *testing.T) {
+	dir := t.TempDir()
+	writeFixture(t, dir, "GET/projects.json", `{"results": [{"id": 1, "name": "Demo"}], "links": {}}`)
+	writeFixture(t, dir, "GET/projects/1/faults.json", `{"results": [{"id": 10, "klass": "RuntimeError"}], "links": {}}`)
+	writeFixture(t, dir, "GET/projects/1/faults@q=is%3Aresolved.json", `{"results": [], "links": {}}`)
+	writeFixture(t, filepath.Dir(dir), "secret.json", `{"id": 99}`)
+
+	// Unroutable, so any request that escapes the fixtures fails loudly.
+	cfg := &config.Config{APIURL: "http://127.0.0.1:1", FixturesDir: dir, TransportMode: config.TransportStdio}
+	client := NewClientFactory(cfg, slog.New(slog.NewTextHandler(io.Discard, nil)))(context.Background())
+	ctx := context.Background()
+
+	projects, err := client.Projects.ListAll(ctx)
+	if err != nil || len(projects.Results) != 1 || projects.Results[0].Name != "Demo" {
+		t.Fatalf("Projects.ListAll() = %+v, %v; want the Demo fixture", projects, err)
+	}
+
+	faults, err := client.Faults.List(ctx, 1, hbapi.FaultListOptions{})
+	if err != nil || len(faults.Results) != 1 {
+		t.Errorf("Faults.List() = %+v, %v; want the catch-all fixture", faults, err)
+	}
+	faults, err = client.Faults.List(ctx, 1, hbapi.FaultListOptions{Q: "is:resolved"})
+	if err != nil || len(faults.Results) != 0 {
+		t.Errorf("Faults.List(q) = %+v, %v; want the per-query fixture", faults, err)
+	}
+
+	_, err = client.Projects.Get(ctx, 2)
+	var apiErr *hbapi.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound || !strings.Contains(apiErr.Message, "add GET/projects/2.json") {
+		t.Errorf("expected a 404 naming the missing fixture, got %v", err)
+	}
+
+	if _, err := client.Dashboards.Get(ctx, 1, "../../../../secret"); err == nil {
+		t.Error("expected a fixture outside the directory not to be served")
+	}
+}
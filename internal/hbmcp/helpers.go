@@ -2,7 +2,6 @@ package hbmcp
 
 import (
 	"math"
-	"time"
 )
 
 // maxSafeInteger is the largest integer a float64 can represent exactly
@@ -37,25 +36,3 @@ func requireID(args map[string]any, name string) (int, bool) {
 	}
 	return 0, false
 }
-
-// parseTimestamp converts a timestamp string to *time.Time, returns nil if empty or invalid
-func parseTimestamp(ts string) *time.Time {
-	if ts == "" {
-		return nil
-	}
-	if parsed, err := time.Parse(time.RFC3339, ts); err == nil {
-		return &parsed
-	}
-	return nil
-}
-
-// parseTimestampValue converts a timestamp string to time.Time, returns zero value if empty or invalid
-func parseTimestampValue(ts string) time.Time {
-	if ts == "" {
-		return time.Time{}
-	}
-	if parsed, err := time.Parse(time.RFC3339, ts); err == nil {
-		return parsed
-	}
-	return time.Time{}
-}
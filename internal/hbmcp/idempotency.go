@@ -0,0 +1,145 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// createReplayWindow is how long a create call's result is handed back to
+// an identical call in the same session instead of creating again.
+const createReplayWindow = 10 * time.Minute
+
+// replayedCreates are the tools whose calls each create a resource, so a
+// client retrying one after a timeout would create it twice.
+// raw_api_request is left out: its POSTs aren't all creates.
+var replayedCreates = map[string]bool{
+	"create_project":         true,
+	"setup_project":          true,
+	"create_alarm":           true,
+	"create_dashboard":       true,
+	"create_check_in":        true,
+	"create_status_page":     true,
+	"create_team_invitation": true,
+	"invite_account_user":    true,
+}
+
+// createReplays makes create tools safe to retry. The Honeybadger API takes
+// no idempotency keys, so the server keys each create call by session,
+// caller, tool, and arguments itself: an identical call made while the first is
+// still running waits for it, and one made within createReplayWindow of a
+// successful call gets that call's result back rather than a second POST.
+// Failed calls aren't remembered, so they can be retried.
+type createReplays struct {
+	now func() time.Time
+
+	mu        sync.Mutex
+	bySession map[string]map[string]*createCall
+}
+
+// createCall is one create call, in flight until done is closed.
+type createCall struct {
+	done   chan struct{}
+	result *mcp.CallToolResult
+	at     time.Time
+}
+
+func newCreateReplays() *createReplays {
+	return &createReplays{now: time.Now, bySession: map[string]map[string]*createCall{}}
+}
+
+// replay wraps the handler of a create tool.
+func (c *createReplays) replay(tool mcp.Tool, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, err := json.Marshal(confirmedArgs(req.GetArguments()))
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to read arguments: %v", err)), nil
+		}
+		sessionID := sessionIDFromContext(ctx)
+		// Stateless http calls all share the session "", so the caller's
+		// token keeps one client from getting another's result.
+		key := confirmationCaller(ctx) + " " + tool.Name + " " + string(args)
+
+		call, first := c.start(sessionID, key)
+		if !first {
+			select {
+			case <-call.done:
+			case <-ctx.Done():
+				return mcp.NewToolResultError(fmt.Sprintf("Failed to wait for an identical %s call still running: %v", tool.Name, ctx.Err())), nil
+			}
+			if call.result != nil {
+				return replayedResult(tool.Name, call), nil
+			}
+			// The earlier call failed and was forgotten; this one runs.
+			return c.replay(tool, next)(ctx, req)
+		}
+
+		result, err := next(ctx, req)
+		c.finish(sessionID, key, call, result, err)
+		return result, err
+	}
+}
+
+// start returns the call for key, and whether it's new and so for the
+// caller to run.
+func (c *createReplays) start(sessionID, key string) (*createCall, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	calls := c.bySession[sessionID]
+	if calls == nil {
+		calls = map[string]*createCall{}
+		c.bySession[sessionID] = calls
+	}
+	for k, call := range calls {
+		if !call.at.IsZero() && now.Sub(call.at) > createReplayWindow {
+			delete(calls, k)
+		}
+	}
+	if call, ok := calls[key]; ok {
+		return call, false
+	}
+	call := &createCall{done: make(chan struct{})}
+	calls[key] = call
+	return call, true
+}
+
+// finish records how call went, keeping it for replays only if it
+// succeeded.
+func (c *createReplays) finish(sessionID, key string, call *createCall, result *mcp.CallToolResult, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err == nil && result != nil && !result.IsError {
+		// Outer layers may add to the result, so the replay keeps its own
+		// copy of what the handler returned.
+		kept := *result
+		kept.Content = slices.Clone(result.Content)
+		call.result = &kept
+		call.at = c.now()
+	} else if calls := c.bySession[sessionID]; calls[key] == call {
+		delete(calls, key)
+	}
+	close(call.done)
+}
+
+// replayedResult returns a copy of call's result with a warning that it
+// came from an earlier call.
+func replayedResult(tool string, call *createCall) *mcp.CallToolResult {
+	result := *call.result
+	result.Content = slices.Clone(call.result.Content)
+	addWarning(&result, fmt.Sprintf("nothing was created: an identical %s call in this session already succeeded at %s, and this is its result. Change an argument to create another.", tool, call.at.UTC().Format(time.RFC3339)))
+	return &result
+}
+
+// forget drops the calls of a session that has gone away.
+func (c *createReplays) forget(sessionID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.bySession, sessionID)
+}
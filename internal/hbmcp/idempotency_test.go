@@ -0,0 +1,122 @@
+package hbmcp
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestCreateReplays(t *testing.T) {
+	c := newCreateReplays()
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+	tool := mcp.NewTool("create_project", mcp.WithString("name"))
+	calls := 0
+	fail := false
+	handler := c.replay(tool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		calls++
+		if fail {
+			return mcp.NewToolResultError("Failed to create project: timeout"), nil
+		}
+		return mcp.NewToolResultText(`{"id": 5}`), nil
+	})
+	call := func(args map[string]any) *mcp.CallToolResult {
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = args
+		result, _ := handler(context.Background(), req)
+		return result
+	}
+
+	call(map[string]any{"name": "Checkout"})
+	result := call(map[string]any{"name": "Checkout", "timeout_seconds": float64(30)})
+	if calls != 1 || getResultText(result) != `{"id": 5}` {
+		t.Fatalf("expected the retry answered from the first call, got %q after %d calls", getResultText(result), calls)
+	}
+	if notes := result.Content[len(result.Content)-1].(mcp.TextContent).Text; !strings.Contains(notes, "nothing was created") {
+		t.Errorf("expected a warning on the replayed result, got %s", notes)
+	}
+
+	call(map[string]any{"name": "Billing"})
+	if calls != 2 {
+		t.Errorf("expected different arguments to create again, got %d calls", calls)
+	}
+
+	now = now.Add(createReplayWindow + time.Second)
+	call(map[string]any{"name": "Checkout"})
+	if calls != 3 {
+		t.Errorf("expected a call after the window to create again, got %d calls", calls)
+	}
+
+	// A failed call isn't remembered, so its retry runs.
+	fail = true
+	call(map[string]any{"name": "Orders"})
+	fail = false
+	if result := call(map[string]any{"name": "Orders"}); calls != 5 || result.IsError {
+		t.Errorf("expected a failed call's retry to run, got %q after %d calls", getResultText(result), calls)
+	}
+}
+
+func TestCreateReplays_WaitsForCallInFlight(t *testing.T) {
+	c := newCreateReplays()
+	tool := mcp.NewTool("create_alarm")
+	release := make(chan struct{})
+	var mu sync.Mutex
+	calls := 0
+	handler := c.replay(tool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		<-release
+		return mcp.NewToolResultText(`{"id": 9}`), nil
+	})
+
+	var wg sync.WaitGroup
+	results := make([]*mcp.CallToolResult, 2)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], _ = handler(context.Background(), mcp.CallToolRequest{})
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if calls != 1 {
+		t.Errorf("expected one create for two identical concurrent calls, got %d", calls)
+	}
+	for _, result := range results {
+		if result.IsError || getResultText(result) != `{"id": 9}` {
+			t.Errorf("unexpected result %q", getResultText(result))
+		}
+	}
+}
+
+func TestCreateReplays_KeyedByCaller(t *testing.T) {
+	c := newCreateReplays()
+	tool := mcp.NewTool("create_project", mcp.WithString("name"))
+	calls := 0
+	handler := c.replay(tool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		calls++
+		return mcp.NewToolResultText(`{"id": 5}`), nil
+	})
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"name": "Checkout"}
+
+	// Stateless http calls have no session ID, so only the token tells
+	// callers apart.
+	_, _ = handler(WithAuthToken(context.Background(), "token-a"), req)
+	result, _ := handler(WithAuthToken(context.Background(), "token-b"), req)
+	if calls != 2 || len(result.Content) != 1 {
+		t.Errorf("expected another caller's identical call to create again, got %d calls", calls)
+	}
+	_, _ = handler(WithAuthToken(context.Background(), "token-a"), req)
+	if calls != 2 {
+		t.Errorf("expected the same caller's retry to be replayed, got %d calls", calls)
+	}
+}
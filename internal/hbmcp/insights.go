@@ -2,15 +2,29 @@ package hbmcp
 
 import (
 	"context"
+	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
 
 	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/honeybadger-io/honeybadger-mcp-server/internal/config"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
+// Formats query_insights can return its results in.
+const (
+	insightsOutputJSON = "json"
+	insightsOutputCSV  = "csv"
+	insightsOutputTSV  = "tsv"
+)
+
 // RegisterInsightsTools registers all insights-related MCP tools
-func RegisterInsightsTools(r *toolRegistrar, clientFor ClientFactory) {
+func RegisterInsightsTools(r *toolRegistrar, clientFor ClientFactory, history *insightsHistory, current func() *config.Config) {
 	// query_insights tool
 	r.AddTool(
 		mcp.NewTool("query_insights",
@@ -18,6 +32,8 @@ func RegisterInsightsTools(r *toolRegistrar, clientFor ClientFactory) {
 			mcp.WithDescription("Execute a BadgerQL query against Insights data. Requires reference topics: queries, badgerql (fetch via get_reference; skip topics still visible in your context). To visualize or share results, also fetch the charts topic."),
 			mcp.WithReadOnlyHintAnnotation(true),
 			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
 			mcp.WithNumber("project_id",
 				mcp.Required(),
 				mcp.Description("The ID of the project to query insights for"),
@@ -37,12 +53,172 @@ func RegisterInsightsTools(r *toolRegistrar, clientFor ClientFactory) {
 				mcp.WithStringItems(),
 				mcp.Description("Optional list of stream IDs to restrict the query to specific Insights streams. Use list_streams to discover a project's stream IDs; pass the 'id' field (not the slug). Omit to query all streams. Passing only unrecognized IDs yields an error, not an empty result."),
 			),
+			mcp.WithString("output",
+				mcp.Description("Result format: 'json' (default) returns the API response with results and meta; 'csv' and 'tsv' return just the rows as a table with a header row of the query's fields, which is more compact for large results."),
+				mcp.Enum(insightsOutputJSON, insightsOutputCSV, insightsOutputTSV),
+			),
+			mcp.WithBoolean("preflight",
+				mcp.Description("Count the rows the query would return first, and hold it back with the count instead of running it when that's more than the server's preflight limit (10000 unless configured otherwise). Costs one extra query; use it for broad queries or long ts ranges (default false)"),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			client := clientFor(ctx)
+			if req.GetBool("preflight", false) {
+				maxRows := current().InsightsPreflightRows
+				if maxRows == 0 {
+					maxRows = config.DefaultInsightsPreflightRows
+				}
+				if result := preflightInsightsQuery(ctx, client, req, maxRows); result != nil {
+					return result, nil
+				}
+			}
+			result, err := handleQueryInsights(ctx, client, req)
+			history.record(ctx, req, result, time.Now())
+			return result, err
+		},
+	)
+
+	// query_insights_batch tool
+	r.AddTool(
+		mcp.NewTool("query_insights_batch",
+			mcp.WithTitleAnnotation("Query Insights Batch"),
+			mcp.WithDescription(fmt.Sprintf("Run up to %d named BadgerQL queries at once against one project and time range, returning each query's results keyed by its name. Use this instead of several query_insights calls when an investigation needs more than one view of the same data. A failed query reports its error under its name without failing the rest. Requires reference topics: queries, badgerql (fetch via get_reference; skip topics still visible in your context).", maxBatchQueries)),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithOutputSchema[insightsBatch](),
+			mcp.WithNumber("project_id",
+				mcp.Required(),
+				mcp.Description("The ID of the project to query insights for"),
+				mcp.Min(1),
+			),
+			mcp.WithArray("queries",
+				mcp.Required(),
+				mcp.Description("The queries to run, each an object with a unique 'name' to key its result by and a BadgerQL 'query'"),
+				mcp.MinItems(1),
+				mcp.MaxItems(maxBatchQueries),
+				mcp.Items(map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"name":  map[string]any{"type": "string", "description": "Key for this query's result"},
+						"query": map[string]any{"type": "string", "description": "BadgerQL query string"},
+					},
+					"required": []string{"name", "query"},
+				}),
+			),
+			mcp.WithString("ts",
+				mcp.Description("Time range for every query, as for query_insights. Defaults to PT3H."),
+			),
+			mcp.WithString("timezone",
+				mcp.Description("IANA timezone identifier (e.g., 'America/New_York') for timestamp interpretation"),
+			),
+			mcp.WithArray("stream_ids",
+				mcp.WithStringItems(),
+				mcp.Description("Optional list of stream IDs every query is restricted to, as for query_insights"),
+			),
+			mcp.WithString("output",
+				mcp.Description("Result format for every query, as for query_insights: 'json' (default) gives each its results and meta; 'csv' and 'tsv' give each a table."),
+				mcp.Enum(insightsOutputJSON, insightsOutputCSV, insightsOutputTSV),
+			),
 		),
 		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return handleQueryInsights(ctx, clientFor(ctx), req)
+			return handleQueryInsightsBatch(ctx, clientFor(ctx), history, req)
 		},
 	)
 
+	// list_insights_query_history tool
+	r.AddTool(
+		mcp.NewTool("list_insights_query_history",
+			mcp.WithTitleAnnotation("List Insights Query History"),
+			mcp.WithDescription("List the BadgerQL queries this session has run against a project with query_insights or query_insights_batch, newest first, with their arguments, row counts, and errors, so you can rerun or refine one instead of rewriting it. Insights keeps no query history of its own, so this covers only queries run through this server in the current session."),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithNumber("project_id",
+				mcp.Required(),
+				mcp.Description("The ID of the project whose queries to list"),
+				mcp.Min(1),
+			),
+			mcp.WithString("contains",
+				mcp.Description("Only list queries whose text contains this string (case-insensitive)"),
+			),
+			mcp.WithNumber("limit",
+				mcp.Description(fmt.Sprintf("Maximum number of queries to return (default %d, max %d)", defaultQueryHistoryLimit, maxQueryHistory)),
+				mcp.Min(1),
+				mcp.Max(maxQueryHistory),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleListInsightsQueryHistory(ctx, history, req)
+		},
+	)
+
+	// validate_insights_query tool
+	r.AddTool(
+		mcp.NewTool("validate_insights_query",
+			mcp.WithTitleAnnotation("Validate Insights Query"),
+			mcp.WithDescription("Check a BadgerQL query without running it in full: returns whether it parses, the error if not, and the fields and column types it would produce. Much cheaper than query_insights, so use it to iterate on a query before running it. Requires reference topic: badgerql (fetch via get_reference; skip topics still visible in your context)."),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithOutputSchema[queryValidation](),
+			mcp.WithNumber("project_id",
+				mcp.Required(),
+				mcp.Description("The ID of the project the query is for"),
+				mcp.Min(1),
+			),
+			mcp.WithString("query",
+				mcp.Required(),
+				mcp.Description("BadgerQL query string to validate"),
+			),
+			mcp.WithArray("stream_ids",
+				mcp.WithStringItems(),
+				mcp.Description("Optional list of stream IDs the query will run against, as for query_insights"),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleValidateInsightsQuery(ctx, clientFor(ctx), req)
+		},
+	)
+
+	// get_insights_schema tool
+	r.AddTool(
+		mcp.NewTool("get_insights_schema",
+			mcp.WithTitleAnnotation("Get Insights Schema"),
+			mcp.WithDescription("Discover the fields of a project's Insights events before writing a query: samples recent events and returns each field's name as BadgerQL refers to it (with the type cast its values suggest, e.g. duration::int), its JSON types, how many sampled events had it, and an example value, plus the event types seen. Nested fields are named with dot paths. Fields absent from the sample may still exist; pass event_type to focus on one kind of event."),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithOutputSchema[insightsSchema](),
+			mcp.WithNumber("project_id",
+				mcp.Required(),
+				mcp.Description("The ID of the project to describe"),
+				mcp.Min(1),
+			),
+			mcp.WithString("event_type",
+				mcp.Description("Only sample events with this event_type"),
+			),
+			mcp.WithArray("stream_ids",
+				mcp.WithStringItems(),
+				mcp.Description("Optional list of stream IDs to sample, as for query_insights. Omit to sample all streams"),
+			),
+			mcp.WithString("ts",
+				mcp.Description(fmt.Sprintf("Time range to sample, as for query_insights. Defaults to %s", defaultSchemaWindow)),
+			),
+			mcp.WithNumber("sample",
+				mcp.Description(fmt.Sprintf("How many of the most recent events to sample (default %d, max %d)", defaultSchemaSample, maxSchemaSample)),
+				mcp.Min(1),
+				mcp.Max(maxSchemaSample),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleGetInsightsSchema(ctx, clientFor(ctx), req)
+		},
+	)
 }
 
 func handleQueryInsights(ctx context.Context, client *hbapi.Client, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -56,6 +232,11 @@ func handleQueryInsights(ctx context.Context, client *hbapi.Client, req mcp.Call
 		return mcp.NewToolResultError("query is required"), nil
 	}
 
+	output := req.GetString("output", insightsOutputJSON)
+	if !validInsightsOutput(output) {
+		return mcp.NewToolResultError(fmt.Sprintf("output must be %s, %s, or %s, got %q", insightsOutputJSON, insightsOutputCSV, insightsOutputTSV, output)), nil
+	}
+
 	// Build request struct
 	request := hbapi.InsightsQueryRequest{
 		Query:     query,
@@ -73,11 +254,137 @@ func handleQueryInsights(ctx context.Context, client *hbapi.Client, req mcp.Call
 		return mcp.NewToolResultError(fmt.Sprintf("Insights query error: %s", response.Error.Message)), nil
 	}
 
+	var notes toolNotes
+	if response.Meta.TotalRows > response.Meta.Rows {
+		notes.warnf("results truncated: %d of %d rows returned; add a limit or narrow the query", response.Meta.Rows, response.Meta.TotalRows)
+	}
+
+	if output != insightsOutputJSON {
+		table, err := insightsTable(response, insightsComma(output))
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to write %s: %v", output, err)), nil
+		}
+		return withNotes(mcp.NewToolResultText(table), &notes), nil
+	}
+
 	// Return JSON response
 	jsonBytes, err := json.Marshal(response)
 	if err != nil {
 		return mcp.NewToolResultError("Failed to marshal response"), nil
 	}
 
+	return withNotes(mcp.NewToolResultText(string(jsonBytes)), &notes), nil
+}
+
+func validInsightsOutput(output string) bool {
+	return output == insightsOutputJSON || output == insightsOutputCSV || output == insightsOutputTSV
+}
+
+// insightsComma is the field separator of a table output.
+func insightsComma(output string) rune {
+	if output == insightsOutputTSV {
+		return '\t'
+	}
+	return ','
+}
+
+// insightsTable renders query results as CSV, or TSV when comma is a tab,
+// with a header row of the query's fields in order. Strings are written
+// as-is, nulls as empty cells, and everything else as compact JSON.
+func insightsTable(response *hbapi.InsightsQueryResponse, comma rune) (string, error) {
+	fields := response.Meta.Fields
+	if len(fields) == 0 {
+		// Without meta.fields, fall back to every key the rows use.
+		seen := map[string]bool{}
+		for _, row := range response.Results {
+			for k := range row {
+				if !seen[k] {
+					seen[k] = true
+					fields = append(fields, k)
+				}
+			}
+		}
+		sort.Strings(fields)
+	}
+
+	var sb strings.Builder
+	w := csv.NewWriter(&sb)
+	w.Comma = comma
+	if err := w.Write(fields); err != nil {
+		return "", err
+	}
+	record := make([]string, len(fields))
+	for _, row := range response.Results {
+		for i, f := range fields {
+			record[i] = ""
+			if v, ok := row[f]; ok && v != nil {
+				record[i] = aggregateValue(v)
+			}
+		}
+		if err := w.Write(record); err != nil {
+			return "", err
+		}
+	}
+	w.Flush()
+	return sb.String(), w.Error()
+}
+
+// validationWindow and validationLimit keep a validation run to a sliver of
+// data: the API has no parse-only mode, but it reports the result schema
+// however few rows come back.
+const (
+	validationWindow = "PT1M"
+	validationLimit  = "\n| limit 1"
+)
+
+type queryValidation struct {
+	Valid  bool                     `json:"valid"`
+	Error  string                   `json:"error,omitempty"`
+	Fields []string                 `json:"fields,omitempty"`
+	Schema []map[string]interface{} `json:"schema,omitempty"`
+}
+
+func handleValidateInsightsQuery(ctx context.Context, client *hbapi.Client, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	projectID := req.GetInt("project_id", 0)
+	if projectID == 0 {
+		return mcp.NewToolResultError("project_id is required"), nil
+	}
+
+	query := req.GetString("query", "")
+	if query == "" {
+		return mcp.NewToolResultError("query is required"), nil
+	}
+
+	request := hbapi.InsightsQueryRequest{
+		Query:     query + validationLimit,
+		Ts:        validationWindow,
+		StreamIDs: req.GetStringSlice("stream_ids", nil),
+	}
+
+	var validation queryValidation
+	response, err := client.Insights.Query(ctx, projectID, request)
+	switch {
+	case err != nil:
+		// A query the API can't parse comes back as a 4xx; anything else
+		// (auth, network, server errors) says nothing about the query.
+		var apiErr *hbapi.APIError
+		if !errors.As(err, &apiErr) || (apiErr.StatusCode != http.StatusBadRequest && apiErr.StatusCode != http.StatusUnprocessableEntity) {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to validate insights query: %v", err)), nil
+		}
+		validation.Error = apiErr.Message
+	case response.Error != nil:
+		validation.Error = response.Error.Message
+	default:
+		validation.Valid = true
+		validation.Fields = response.Meta.Fields
+		validation.Schema = response.Meta.Schema
+	}
+
+	// Return JSON response
+	jsonBytes, err := json.Marshal(validation)
+	if err != nil {
+		return mcp.NewToolResultError("Failed to marshal response"), nil
+	}
+
 	return mcp.NewToolResultText(string(jsonBytes)), nil
 }
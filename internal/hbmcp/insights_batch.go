@@ -0,0 +1,156 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// maxBatchQueries is how many queries one query_insights_batch call runs.
+const maxBatchQueries = 10
+
+type namedInsightsQuery struct {
+	Name  string
+	Query string
+}
+
+// insightsBatchResult is one query's outcome: the API's results and meta,
+// a table for csv and tsv output, or the error the query failed with.
+type insightsBatchResult struct {
+	Results []map[string]interface{} `json:"results,omitempty"`
+	Meta    *hbapi.InsightsQueryMeta `json:"meta,omitempty"`
+	Table   string                   `json:"table,omitempty"`
+	Error   string                   `json:"error,omitempty"`
+}
+
+type insightsBatch struct {
+	Queries map[string]insightsBatchResult `json:"queries"`
+}
+
+func handleQueryInsightsBatch(ctx context.Context, client *hbapi.Client, history *insightsHistory, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	projectID := req.GetInt("project_id", 0)
+	if projectID == 0 {
+		return mcp.NewToolResultError("project_id is required"), nil
+	}
+
+	queries, err := parseNamedQueries(req.GetArguments()["queries"])
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	output := req.GetString("output", insightsOutputJSON)
+	if !validInsightsOutput(output) {
+		return mcp.NewToolResultError(fmt.Sprintf("output must be %s, %s, or %s, got %q", insightsOutputJSON, insightsOutputCSV, insightsOutputTSV, output)), nil
+	}
+
+	ts := req.GetString("ts", "")
+	timezone := req.GetString("timezone", "")
+	streamIDs := req.GetStringSlice("stream_ids", nil)
+
+	// Every query runs at once; each writes only its own slot.
+	responses := make([]*hbapi.InsightsQueryResponse, len(queries))
+	errs := make([]error, len(queries))
+	var wg sync.WaitGroup
+	for i, q := range queries {
+		wg.Go(func() {
+			responses[i], errs[i] = client.Insights.Query(ctx, projectID, hbapi.InsightsQueryRequest{
+				Query:     q.Query,
+				Ts:        ts,
+				Timezone:  timezone,
+				StreamIDs: streamIDs,
+			})
+		})
+	}
+	wg.Wait()
+
+	var notes toolNotes
+	batch := insightsBatch{Queries: map[string]insightsBatchResult{}}
+	var failures []string
+	now := time.Now()
+	for i, q := range queries {
+		var result insightsBatchResult
+		response := responses[i]
+		switch {
+		case errs[i] != nil:
+			result.Error = fmt.Sprintf("Failed to query insights: %v", errs[i])
+		case response.Error != nil:
+			result.Error = fmt.Sprintf("Insights query error: %s", response.Error.Message)
+		case output != insightsOutputJSON:
+			result.Table, err = insightsTable(response, insightsComma(output))
+			if err != nil {
+				result.Error = fmt.Sprintf("Failed to write %s: %v", output, err)
+			}
+		default:
+			result.Results = response.Results
+			result.Meta = &response.Meta
+		}
+
+		run := insightsQueryRun{ProjectID: projectID, Query: q.Query, Ts: ts, Timezone: timezone, StreamIDs: streamIDs, RanAt: now.UTC(), Error: result.Error}
+		if result.Error != "" {
+			failures = append(failures, fmt.Sprintf("%s: %s", q.Name, result.Error))
+		} else {
+			run.Rows = response.Meta.Rows
+			if response.Meta.TotalRows > response.Meta.Rows {
+				notes.warnf("%s: results truncated: %d of %d rows returned; add a limit or narrow the query", q.Name, response.Meta.Rows, response.Meta.TotalRows)
+			}
+		}
+		history.add(ctx, run)
+		batch.Queries[q.Name] = result
+	}
+
+	if len(failures) == len(queries) {
+		return mcp.NewToolResultError(fmt.Sprintf("All %d queries failed: %s", len(queries), strings.Join(failures, "; "))), nil
+	}
+	if len(failures) > 0 {
+		notes.warnf("%d of %d queries failed; see their error fields", len(failures), len(queries))
+	}
+
+	// Return JSON response
+	jsonBytes, err := json.Marshal(batch)
+	if err != nil {
+		return mcp.NewToolResultError("Failed to marshal response"), nil
+	}
+
+	return withNotes(mcp.NewToolResultText(string(jsonBytes)), &notes), nil
+}
+
+// parseNamedQueries reads the queries argument: 1 to maxBatchQueries
+// objects, each with a unique name and a query.
+func parseNamedQueries(arg any) ([]namedInsightsQuery, error) {
+	items, ok := arg.([]any)
+	if !ok || len(items) == 0 {
+		return nil, errors.New("queries is required")
+	}
+	if len(items) > maxBatchQueries {
+		return nil, fmt.Errorf("queries takes at most %d queries, got %d", maxBatchQueries, len(items))
+	}
+
+	queries := make([]namedInsightsQuery, 0, len(items))
+	seen := map[string]bool{}
+	for i, item := range items {
+		obj, ok := item.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("queries[%d] must be an object with name and query", i)
+		}
+		name, _ := obj["name"].(string)
+		query, _ := obj["query"].(string)
+		switch {
+		case strings.TrimSpace(name) == "":
+			return nil, fmt.Errorf("queries[%d] needs a name", i)
+		case strings.TrimSpace(query) == "":
+			return nil, fmt.Errorf("queries[%d] (%s) needs a query", i, name)
+		case seen[name]:
+			return nil, fmt.Errorf("queries[%d]: the name %q is used twice", i, name)
+		}
+		seen[name] = true
+		queries = append(queries, namedInsightsQuery{Name: name, Query: query})
+	}
+	return queries, nil
+}
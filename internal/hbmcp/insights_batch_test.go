@@ -0,0 +1,180 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestHandleQueryInsightsBatch(t *testing.T) {
+	// Each request waits until all three have arrived, so the test only
+	// passes if the queries run at once.
+	var mu sync.Mutex
+	arrived := 0
+	all := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body hbapi.InsightsQueryRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		if body.Ts != "P1D" || len(body.StreamIDs) != 1 {
+			t.Errorf("expected the shared ts and stream_ids, got %+v", body)
+		}
+
+		mu.Lock()
+		arrived++
+		if arrived == 3 {
+			close(all)
+		}
+		mu.Unlock()
+		select {
+		case <-all:
+		case <-time.After(5 * time.Second):
+			t.Error("expected the queries to run concurrently")
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		switch body.Query {
+		case "stats count()":
+			_, _ = w.Write([]byte(`{"results": [{"count": 42}], "meta": {"fields": ["count"], "rows": 1, "total_rows": 1}}`))
+		case "stats count() by route":
+			_, _ = w.Write([]byte(`{"results": [{"route": "/a", "count": 2}], "meta": {"fields": ["route", "count"], "rows": 1, "total_rows": 9}}`))
+		default:
+			_, _ = w.Write([]byte(`{"results": [], "meta": {}, "error": {"message": "unknown field bogus"}}`))
+		}
+	}))
+	defer server.Close()
+
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+	history := newInsightsHistory()
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{
+		"project_id": float64(1),
+		"ts":         "P1D",
+		"stream_ids": []any{"s1"},
+		"queries": []any{
+			map[string]any{"name": "total", "query": "stats count()"},
+			map[string]any{"name": "by_route", "query": "stats count() by route"},
+			map[string]any{"name": "broken", "query": "fields bogus"},
+		},
+	}
+
+	result, err := handleQueryInsightsBatch(historyContext(), client, history, req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("unexpected error result: %s", getResultText(result))
+	}
+
+	var batch insightsBatch
+	if err := json.Unmarshal([]byte(getResultText(result)), &batch); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if got := batch.Queries["total"]; got.Meta == nil || len(got.Results) != 1 || got.Results[0]["count"] != float64(42) {
+		t.Errorf("unexpected total result: %+v", got)
+	}
+	if got := batch.Queries["by_route"]; got.Meta == nil || got.Meta.TotalRows != 9 {
+		t.Errorf("unexpected by_route result: %+v", got)
+	}
+	if got := batch.Queries["broken"]; got.Meta != nil || !strings.Contains(got.Error, "unknown field bogus") {
+		t.Errorf("expected broken to report its error, got %+v", got)
+	}
+
+	notes := getResultNotes(t, result)
+	if len(notes.Warnings) != 2 || !strings.HasPrefix(notes.Warnings[0], "by_route: results truncated") || !strings.Contains(notes.Warnings[1], "1 of 3 queries failed") {
+		t.Errorf("unexpected warnings: %v", notes.Warnings)
+	}
+
+	runs := history.list(historyContext(), 1, "", maxQueryHistory)
+	if len(runs) != 3 || runs[0].Error == "" || runs[2].Rows != 1 || runs[2].Ts != "P1D" {
+		t.Errorf("expected every query in the history, got %+v", runs)
+	}
+}
+
+func TestHandleQueryInsightsBatch_Table(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"results": [{"count": 42}], "meta": {"fields": ["count"], "rows": 1, "total_rows": 1}}`))
+	}))
+	defer server.Close()
+
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{
+		"project_id": float64(1),
+		"output":     "csv",
+		"queries":    []any{map[string]any{"name": "total", "query": "stats count()"}},
+	}
+
+	result, _ := handleQueryInsightsBatch(context.Background(), client, newInsightsHistory(), req)
+	var batch insightsBatch
+	if err := json.Unmarshal([]byte(getResultText(result)), &batch); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if got := batch.Queries["total"]; got.Table != "count\n42\n" || got.Results != nil || got.Meta != nil {
+		t.Errorf("expected only a table, got %+v", got)
+	}
+}
+
+func TestHandleQueryInsightsBatch_AllFailed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{
+		"project_id": float64(1),
+		"queries": []any{
+			map[string]any{"name": "a", "query": "stats count()"},
+			map[string]any{"name": "b", "query": "fields x"},
+		},
+	}
+
+	result, _ := handleQueryInsightsBatch(context.Background(), client, newInsightsHistory(), req)
+	if !result.IsError || !strings.Contains(getResultText(result), "All 2 queries failed: a: Failed to query insights") {
+		t.Errorf("expected an error naming each query, got %q", getResultText(result))
+	}
+}
+
+func TestHandleQueryInsightsBatch_Validation(t *testing.T) {
+	client := hbapi.NewClient()
+	tooMany := make([]any, maxBatchQueries+1)
+	for i := range tooMany {
+		tooMany[i] = map[string]any{"name": string(rune('a' + i)), "query": "stats count()"}
+	}
+	tests := []struct {
+		name string
+		args map[string]any
+		want string
+	}{
+		{"missing project_id", map[string]any{"queries": []any{map[string]any{"name": "a", "query": "q"}}}, "project_id is required"},
+		{"missing queries", map[string]any{"project_id": float64(1)}, "queries is required"},
+		{"too many", map[string]any{"project_id": float64(1), "queries": tooMany}, "at most 10 queries"},
+		{"not an object", map[string]any{"project_id": float64(1), "queries": []any{"stats count()"}}, "must be an object"},
+		{"no name", map[string]any{"project_id": float64(1), "queries": []any{map[string]any{"query": "q"}}}, "needs a name"},
+		{"no query", map[string]any{"project_id": float64(1), "queries": []any{map[string]any{"name": "a"}}}, "needs a query"},
+		{"duplicate name", map[string]any{"project_id": float64(1), "queries": []any{map[string]any{"name": "a", "query": "q"}, map[string]any{"name": "a", "query": "r"}}}, "used twice"},
+		{"bad output", map[string]any{"project_id": float64(1), "output": "xml", "queries": []any{map[string]any{"name": "a", "query": "q"}}}, "output must be"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := mcp.CallToolRequest{}
+			req.Params.Arguments = tt.args
+			result, _ := handleQueryInsightsBatch(context.Background(), client, newInsightsHistory(), req)
+			if !result.IsError || !strings.Contains(getResultText(result), tt.want) {
+				t.Errorf("expected error containing %q, got %q", tt.want, getResultText(result))
+			}
+		})
+	}
+}
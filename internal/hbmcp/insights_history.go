@@ -0,0 +1,173 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// maxQueryHistory is how many queries each session remembers.
+	maxQueryHistory = 50
+
+	defaultQueryHistoryLimit = 20
+)
+
+// insightsQueryRun is one query run through query_insights or
+// query_insights_batch as list_insights_query_history reports it.
+type insightsQueryRun struct {
+	ProjectID int       `json:"project_id"`
+	Query     string    `json:"query"`
+	Ts        string    `json:"ts,omitempty"`
+	Timezone  string    `json:"timezone,omitempty"`
+	StreamIDs []string  `json:"stream_ids,omitempty"`
+	RanAt     time.Time `json:"ran_at"`
+	Rows      int       `json:"rows"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// sameQuery reports whether r and o would return the same results.
+func (r insightsQueryRun) sameQuery(o insightsQueryRun) bool {
+	return r.ProjectID == o.ProjectID && r.Query == o.Query && r.Ts == o.Ts &&
+		r.Timezone == o.Timezone && slices.Equal(r.StreamIDs, o.StreamIDs)
+}
+
+// insightsHistory remembers the queries each session ran through
+// query_insights and query_insights_batch. The Insights API keeps no
+// query history, so this is the server's own record, kept per session so
+// http clients can't see each other's queries. Calls without a session,
+// as over stateless http, would all share one history, so none is kept
+// for them.
+type insightsHistory struct {
+	mu        sync.Mutex
+	bySession map[string][]insightsQueryRun
+}
+
+func newInsightsHistory() *insightsHistory {
+	return &insightsHistory{bySession: map[string][]insightsQueryRun{}}
+}
+
+// record adds the query_insights call req with its result.
+func (h *insightsHistory) record(ctx context.Context, req mcp.CallToolRequest, result *mcp.CallToolResult, now time.Time) {
+	run := insightsQueryRun{
+		ProjectID: req.GetInt("project_id", 0),
+		Query:     req.GetString("query", ""),
+		Ts:        req.GetString("ts", ""),
+		Timezone:  req.GetString("timezone", ""),
+		StreamIDs: req.GetStringSlice("stream_ids", nil),
+		RanAt:     now.UTC(),
+	}
+	if run.ProjectID == 0 || run.Query == "" || result == nil {
+		return
+	}
+	if result.IsError {
+		run.Error = resultText(result)
+	} else if len(result.Content) > 0 {
+		if text, ok := result.Content[0].(mcp.TextContent); ok {
+			run.Rows = resultRows(req.GetString("output", insightsOutputJSON), text.Text)
+		}
+	}
+
+	h.add(ctx, run)
+}
+
+// add records run in the session's history. A query run again replaces
+// its earlier entry, so each appears once.
+func (h *insightsHistory) add(ctx context.Context, run insightsQueryRun) {
+	sessionID := sessionIDFromContext(ctx)
+	if sessionID == "" {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	runs := slices.DeleteFunc(h.bySession[sessionID], run.sameQuery)
+	runs = append(runs, run)
+	if len(runs) > maxQueryHistory {
+		runs = runs[len(runs)-maxQueryHistory:]
+	}
+	h.bySession[sessionID] = runs
+}
+
+// resultRows counts the rows in a query_insights result: from meta in a
+// JSON response, or the records after the header in a table.
+func resultRows(output, text string) int {
+	if output == insightsOutputCSV || output == insightsOutputTSV {
+		r := csv.NewReader(strings.NewReader(text))
+		r.Comma = insightsComma(output)
+		records, err := r.ReadAll()
+		if err != nil || len(records) == 0 {
+			return 0
+		}
+		return len(records) - 1
+	}
+	var response hbapi.InsightsQueryResponse
+	if json.Unmarshal([]byte(text), &response) != nil {
+		return 0
+	}
+	return response.Meta.Rows
+}
+
+// list returns the session's queries for projectID whose text contains
+// contains (case-insensitively), newest first.
+func (h *insightsHistory) list(ctx context.Context, projectID int, contains string, limit int) []insightsQueryRun {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	runs := h.bySession[sessionIDFromContext(ctx)]
+	result := []insightsQueryRun{}
+	for i := len(runs) - 1; i >= 0 && len(result) < limit; i-- {
+		if runs[i].ProjectID == projectID && strings.Contains(strings.ToLower(runs[i].Query), strings.ToLower(contains)) {
+			result = append(result, runs[i])
+		}
+	}
+	return result
+}
+
+// forget drops the history of a session that has gone away.
+func (h *insightsHistory) forget(sessionID string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.bySession, sessionID)
+}
+
+// sessionIDFromContext returns the MCP session ID, or "" outside a session.
+func sessionIDFromContext(ctx context.Context) string {
+	if session := server.ClientSessionFromContext(ctx); session != nil {
+		return session.SessionID()
+	}
+	return ""
+}
+
+func handleListInsightsQueryHistory(ctx context.Context, history *insightsHistory, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	projectID := req.GetInt("project_id", 0)
+	if projectID == 0 {
+		return mcp.NewToolResultError("project_id is required"), nil
+	}
+
+	limit := req.GetInt("limit", defaultQueryHistoryLimit)
+	if limit < 1 || limit > maxQueryHistory {
+		return mcp.NewToolResultError(fmt.Sprintf("limit must be between 1 and %d", maxQueryHistory)), nil
+	}
+
+	runs := history.list(ctx, projectID, req.GetString("contains", ""), limit)
+
+	// Return JSON response
+	jsonBytes, err := json.Marshal(map[string][]insightsQueryRun{"queries": runs})
+	if err != nil {
+		return mcp.NewToolResultError("Failed to marshal response"), nil
+	}
+
+	result := mcp.NewToolResultText(string(jsonBytes))
+	if sessionIDFromContext(ctx) == "" {
+		addWarning(result, "no query history is kept without a session, as in stateless http mode")
+	}
+	return result, nil
+}
@@ -0,0 +1,188 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func queryInsightsRequest(args map[string]any) mcp.CallToolRequest {
+	return mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: args}}
+}
+
+// historyContext returns a context inside a session, which the history
+// needs to keep anything.
+func historyContext() context.Context {
+	return server.NewMCPServer("test", "1.0.0").WithContext(context.Background(), &testSession{id: "history-test"})
+}
+
+func TestInsightsHistory_Record(t *testing.T) {
+	h := newInsightsHistory()
+	ctx := historyContext()
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	h.record(ctx, queryInsightsRequest(map[string]any{"project_id": 1, "query": "fields a"}),
+		mcp.NewToolResultText(`{"results": [], "meta": {"rows": 7}}`), now)
+	h.record(ctx, queryInsightsRequest(map[string]any{"project_id": 1, "query": "fields b", "ts": "week"}),
+		mcp.NewToolResultError("Failed to execute insights query: bad field b"), now.Add(time.Minute))
+	h.record(ctx, queryInsightsRequest(map[string]any{"project_id": 2, "query": "fields c"}),
+		mcp.NewToolResultText(`{"results": [], "meta": {"rows": 1}}`), now)
+	// Missing arguments are refused before any query runs, so aren't history.
+	h.record(ctx, queryInsightsRequest(map[string]any{"project_id": 1}),
+		mcp.NewToolResultError("query is required"), now)
+
+	runs := h.list(ctx, 1, "", maxQueryHistory)
+	if len(runs) != 2 {
+		t.Fatalf("expected 2 queries for project 1, got %+v", runs)
+	}
+	if runs[0].Query != "fields b" || runs[0].Ts != "week" || !strings.Contains(runs[0].Error, "bad field b") {
+		t.Errorf("expected the failed query first, got %+v", runs[0])
+	}
+	if runs[1].Query != "fields a" || runs[1].Rows != 7 || runs[1].Error != "" || !runs[1].RanAt.Equal(now) {
+		t.Errorf("expected the successful query second, got %+v", runs[1])
+	}
+
+	// Rerunning a query moves it to the front rather than repeating it.
+	h.record(ctx, queryInsightsRequest(map[string]any{"project_id": 1, "query": "fields a"}),
+		mcp.NewToolResultText(`{"results": [], "meta": {"rows": 9}}`), now.Add(2*time.Minute))
+	runs = h.list(ctx, 1, "", maxQueryHistory)
+	if len(runs) != 2 || runs[0].Query != "fields a" || runs[0].Rows != 9 {
+		t.Errorf("expected the rerun query once, first, got %+v", runs)
+	}
+
+	// Rows are counted from a table too.
+	h.record(ctx, queryInsightsRequest(map[string]any{"project_id": 2, "query": "fields c", "output": "csv"}),
+		mcp.NewToolResultText("c\n\"x\ny\"\nz\n"), now)
+	if runs := h.list(ctx, 2, "", maxQueryHistory); len(runs) != 1 || runs[0].Rows != 2 {
+		t.Errorf("expected 2 rows counted from the CSV, got %+v", runs)
+	}
+
+	if runs := h.list(ctx, 1, "FIELDS B", maxQueryHistory); len(runs) != 1 || runs[0].Query != "fields b" {
+		t.Errorf("expected contains to match case-insensitively, got %+v", runs)
+	}
+	if runs := h.list(ctx, 1, "", 1); len(runs) != 1 {
+		t.Errorf("expected limit to cap the result, got %+v", runs)
+	}
+}
+
+func TestInsightsHistory_Cap(t *testing.T) {
+	h := newInsightsHistory()
+	ctx := historyContext()
+	for i := range maxQueryHistory + 5 {
+		h.record(ctx, queryInsightsRequest(map[string]any{"project_id": 1, "query": fmt.Sprintf("fields f%d", i)}),
+			mcp.NewToolResultText(`{}`), time.Now())
+	}
+
+	runs := h.list(ctx, 1, "", maxQueryHistory+5)
+	if len(runs) != maxQueryHistory {
+		t.Fatalf("expected %d queries, got %d", maxQueryHistory, len(runs))
+	}
+	if want := fmt.Sprintf("fields f%d", maxQueryHistory+4); runs[0].Query != want {
+		t.Errorf("expected newest %q first, got %q", want, runs[0].Query)
+	}
+}
+
+func TestInsightsHistory_NoSession(t *testing.T) {
+	h := newInsightsHistory()
+	h.record(context.Background(), queryInsightsRequest(map[string]any{"project_id": 1, "query": "fields a"}),
+		mcp.NewToolResultText(`{}`), time.Now())
+	if runs := h.list(context.Background(), 1, "", maxQueryHistory); len(runs) != 0 {
+		t.Errorf("expected no history kept without a session, got %+v", runs)
+	}
+
+	result, _ := handleListInsightsQueryHistory(context.Background(), h, queryInsightsRequest(map[string]any{"project_id": 1}))
+	if result.IsError || len(result.Content) != 2 || !strings.Contains(result.Content[1].(mcp.TextContent).Text, "without a session") {
+		t.Errorf("expected a warning that no history is kept, got %v", result.Content)
+	}
+}
+
+func TestHandleListInsightsQueryHistory_InvalidArgs(t *testing.T) {
+	h := newInsightsHistory()
+	tests := []struct {
+		name string
+		args map[string]any
+		want string
+	}{
+		{"missing project_id", map[string]any{}, "project_id is required"},
+		{"limit too high", map[string]any{"project_id": 1, "limit": maxQueryHistory + 1}, "limit must be between"},
+		{"limit too low", map[string]any{"project_id": 1, "limit": 0}, "limit must be between"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := handleListInsightsQueryHistory(context.Background(), h, queryInsightsRequest(tt.args))
+			if err != nil {
+				t.Fatalf("handleListInsightsQueryHistory() error = %v", err)
+			}
+			if !result.IsError || !strings.Contains(getResultText(result), tt.want) {
+				t.Errorf("expected error containing %q, got %q", tt.want, getResultText(result))
+			}
+		})
+	}
+}
+
+func TestListInsightsQueryHistory_Sessions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"results": [{"count": 3}], "meta": {"rows": 1, "total_rows": 1}}`))
+	}))
+	defer server.Close()
+
+	cfg := reloadTestConfig()
+	cfg.APIURL = server.URL
+	s, _, _ := NewReloadableServer(cfg, "test")
+	session := newTestSession(t, s)
+	other := &testSession{id: "other", notifications: make(chan mcp.JSONRPCNotification, 10)}
+	if err := s.RegisterSession(context.Background(), other); err != nil {
+		t.Fatalf("RegisterSession() error = %v", err)
+	}
+
+	call := func(session *testSession, name string, args map[string]any) *mcp.CallToolResult {
+		t.Helper()
+		params, _ := json.Marshal(map[string]any{"name": name, "arguments": args})
+		msg := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":` + string(params) + `}`
+		resp, ok := s.HandleMessage(s.WithContext(context.Background(), session), []byte(msg)).(mcp.JSONRPCResponse)
+		if !ok {
+			t.Fatalf("expected JSON-RPC response calling %s", name)
+		}
+		result, ok := resp.Result.(*mcp.CallToolResult)
+		if !ok {
+			t.Fatalf("expected *mcp.CallToolResult, got %T", resp.Result)
+		}
+		return result
+	}
+	history := func(session *testSession) []insightsQueryRun {
+		t.Helper()
+		result := call(session, "list_insights_query_history", map[string]any{"project_id": 42})
+		var response struct {
+			Queries []insightsQueryRun `json:"queries"`
+		}
+		if err := json.Unmarshal([]byte(getResultText(result)), &response); err != nil {
+			t.Fatalf("failed to parse history %q: %v", getResultText(result), err)
+		}
+		return response.Queries
+	}
+
+	if result := call(session, "query_insights", map[string]any{"project_id": 42, "query": "stats count()"}); result.IsError {
+		t.Fatalf("query_insights failed: %s", getResultText(result))
+	}
+
+	if runs := history(session); len(runs) != 1 || runs[0].Query != "stats count()" || runs[0].Rows != 1 {
+		t.Errorf("expected the session's query, got %+v", runs)
+	}
+	if runs := history(other); len(runs) != 0 {
+		t.Errorf("expected another session to see no queries, got %+v", runs)
+	}
+
+	s.UnregisterSession(context.Background(), session.SessionID())
+	if runs := history(session); len(runs) != 0 {
+		t.Errorf("expected the history to end with the session, got %+v", runs)
+	}
+}
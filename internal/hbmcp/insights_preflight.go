@@ -0,0 +1,71 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// preflightCount turns a query into one that counts the rows it would
+// return. The API has no cost estimate, but a count comes back as a single
+// row however many it covers.
+const preflightCount = "\n| stats count() as preflight_rows"
+
+type insightsPreflight struct {
+	Rows    int    `json:"rows"`
+	MaxRows int    `json:"max_rows"`
+	Ran     bool   `json:"ran"`
+	Hint    string `json:"hint"`
+}
+
+// preflightInsightsQuery counts the rows query_insights' query would
+// return over the same range and streams, and returns a result holding the
+// query back when there are more than maxRows. It returns nil when the
+// query should go ahead.
+func preflightInsightsQuery(ctx context.Context, client *hbapi.Client, req mcp.CallToolRequest, maxRows int) *mcp.CallToolResult {
+	projectID := req.GetInt("project_id", 0)
+	query := req.GetString("query", "")
+	if projectID == 0 || query == "" {
+		// handleQueryInsights reports the missing argument.
+		return nil
+	}
+
+	response, err := client.Insights.Query(ctx, projectID, hbapi.InsightsQueryRequest{
+		Query:     query + preflightCount,
+		Ts:        req.GetString("ts", ""),
+		Timezone:  req.GetString("timezone", ""),
+		StreamIDs: req.GetStringSlice("stream_ids", nil),
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to preflight insights query: %v", err))
+	}
+	if response.Error != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Insights query error: %s", response.Error.Message))
+	}
+	if len(response.Results) == 0 {
+		return nil
+	}
+	rows, err := strconv.ParseFloat(fmt.Sprint(response.Results[0]["preflight_rows"]), 64)
+	if err != nil || int(rows) <= maxRows {
+		return nil
+	}
+
+	var notes toolNotes
+	notes.warnf("query held back: it would return %d rows, more than the preflight limit of %d", int(rows), maxRows)
+
+	// Return JSON response
+	jsonBytes, err := json.Marshal(insightsPreflight{
+		Rows:    int(rows),
+		MaxRows: maxRows,
+		Hint:    "Narrow ts, add a filter, aggregate with stats, or add a limit, then run it again; pass preflight false to run it as is",
+	})
+	if err != nil {
+		return mcp.NewToolResultError("Failed to marshal response")
+	}
+
+	return withNotes(mcp.NewToolResultText(string(jsonBytes)), &notes)
+}
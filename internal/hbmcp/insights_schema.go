@@ -0,0 +1,200 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	defaultSchemaSample = 200
+	maxSchemaSample     = 1000
+	defaultSchemaWindow = "P1D"
+
+	// maxSchemaDepth bounds how deep into nested objects fields are named,
+	// so a deeply nested payload doesn't flood the result.
+	maxSchemaDepth = 4
+)
+
+// insightsSchema is the result of get_insights_schema: the fields seen in a
+// sample of a project's recent events, most common first.
+type insightsSchema struct {
+	ProjectID  int                 `json:"project_id"`
+	Window     string              `json:"window"`
+	Sampled    int                 `json:"sampled"`
+	EventTypes []insightsEventSeen `json:"event_types"`
+	Fields     []insightsField     `json:"fields"`
+}
+
+type insightsEventSeen struct {
+	EventType string `json:"event_type"`
+	Count     int    `json:"count"`
+}
+
+// insightsField is one field of the sampled events. Query is how to refer
+// to it in BadgerQL, with the type cast its values suggest.
+type insightsField struct {
+	Name    string   `json:"name"`
+	Query   string   `json:"query"`
+	Types   []string `json:"types"`
+	Seen    int      `json:"seen"`
+	Example any      `json:"example,omitempty"`
+
+	// fractional is set once a number value isn't whole, so the field is
+	// read as a float rather than an int.
+	fractional bool
+}
+
+// badgerQLCasts maps the JSON types inferred for a field to the BadgerQL
+// cast that reads it.
+var badgerQLCasts = map[string]string{
+	"string":  "str",
+	"number":  "int",
+	"boolean": "bool",
+}
+
+func handleGetInsightsSchema(ctx context.Context, client *hbapi.Client, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	projectID := req.GetInt("project_id", 0)
+	if projectID == 0 {
+		return mcp.NewToolResultError("project_id is required"), nil
+	}
+
+	sample := req.GetInt("sample", defaultSchemaSample)
+	if sample < 1 || sample > maxSchemaSample {
+		return mcp.NewToolResultError(fmt.Sprintf("sample must be between 1 and %d", maxSchemaSample)), nil
+	}
+
+	// The preview holds each event's whole payload, so one query samples
+	// every field whatever the event type.
+	query := "fields @preview"
+	if eventType := strings.TrimSpace(req.GetString("event_type", "")); eventType != "" {
+		query += "\n| filter event_type::str == " + strconv.Quote(eventType)
+	}
+	query += fmt.Sprintf("\n| sort @ts desc\n| limit %d", sample)
+	window := req.GetString("ts", defaultSchemaWindow)
+	response, err := client.Insights.Query(ctx, projectID, hbapi.InsightsQueryRequest{
+		Query:     query,
+		Ts:        window,
+		StreamIDs: req.GetStringSlice("stream_ids", nil),
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to query insights: %v", err)), nil
+	}
+	if response.Error != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Insights query error: %s", response.Error.Message)), nil
+	}
+
+	var notes toolNotes
+	fields := map[string]*insightsField{}
+	eventTypes := map[string]int{}
+	result := insightsSchema{ProjectID: projectID, Window: window}
+	for _, row := range response.Results {
+		event, ok := insightsPreview(row["@preview"])
+		if !ok {
+			continue
+		}
+		result.Sampled++
+		if t, ok := event["event_type"].(string); ok {
+			eventTypes[t]++
+		}
+		seen := map[string]bool{}
+		collectInsightsFields(event, "", 0, fields, seen)
+		for name := range seen {
+			fields[name].Seen++
+		}
+	}
+	if result.Sampled < len(response.Results) {
+		notes.warnf("skipped %d events without a readable @preview", len(response.Results)-result.Sampled)
+	}
+	if result.Sampled == 0 {
+		notes.warnf("no events in %s; widen ts or check stream_ids", window)
+	}
+
+	result.EventTypes = []insightsEventSeen{}
+	for t, n := range eventTypes {
+		result.EventTypes = append(result.EventTypes, insightsEventSeen{EventType: t, Count: n})
+	}
+	slices.SortFunc(result.EventTypes, func(a, b insightsEventSeen) int {
+		if a.Count != b.Count {
+			return b.Count - a.Count
+		}
+		return strings.Compare(a.EventType, b.EventType)
+	})
+	result.Fields = []insightsField{}
+	for _, name := range slices.Sorted(maps.Keys(fields)) {
+		f := fields[name]
+		slices.Sort(f.Types)
+		f.Query = name
+		// Nulls don't change how a field is read; a field of mixed types
+		// is left for the query to cast.
+		if types := slices.DeleteFunc(slices.Clone(f.Types), func(t string) bool { return t == "null" }); len(types) == 1 {
+			if cast, ok := badgerQLCasts[types[0]]; ok {
+				if f.fractional {
+					cast = "float"
+				}
+				f.Query = name + "::" + cast
+			}
+		}
+		result.Fields = append(result.Fields, *f)
+	}
+	slices.SortStableFunc(result.Fields, func(a, b insightsField) int { return b.Seen - a.Seen })
+
+	// Return JSON response
+	jsonBytes, err := json.Marshal(result)
+	if err != nil {
+		return mcp.NewToolResultError("Failed to marshal response"), nil
+	}
+
+	return withNotes(mcp.NewToolResultText(string(jsonBytes)), &notes), nil
+}
+
+// insightsPreview reads an event's @preview cell, which the API returns as
+// an object or as its JSON encoding.
+func insightsPreview(v any) (map[string]any, bool) {
+	switch v := v.(type) {
+	case map[string]any:
+		return v, true
+	case string:
+		var event map[string]any
+		if err := json.Unmarshal([]byte(v), &event); err == nil {
+			return event, true
+		}
+	}
+	return nil, false
+}
+
+// collectInsightsFields records the type and an example of each field in
+// event under prefix, naming nested fields with dot paths, and marks the
+// fields it saw in seen.
+func collectInsightsFields(event map[string]any, prefix string, depth int, fields map[string]*insightsField, seen map[string]bool) {
+	for key, value := range event {
+		name := prefix + key
+		if nested, ok := value.(map[string]any); ok && depth < maxSchemaDepth && len(nested) > 0 {
+			collectInsightsFields(nested, name+".", depth+1, fields, seen)
+			continue
+		}
+		f, ok := fields[name]
+		if !ok {
+			f = &insightsField{Name: name}
+			fields[name] = f
+		}
+		seen[name] = true
+		t := jsonType(value)
+		if !slices.Contains(f.Types, t) {
+			f.Types = append(f.Types, t)
+		}
+		if n, ok := value.(float64); ok && n != float64(int64(n)) {
+			f.fractional = true
+		}
+		if f.Example == nil && t != "null" && t != "object" && t != "array" {
+			f.Example = value
+		}
+	}
+}
@@ -3,6 +3,7 @@ package hbmcp
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -213,6 +214,70 @@ func TestHandleQueryInsights_MissingQuery(t *testing.T) {
 	}
 }
 
+func TestHandleQueryInsights_Table(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"results": [
+				{"name": "web, main", "count": 10, "tags": ["a"], "extra": "ignored"},
+				{"name": "api", "count": 15.5, "tags": null}
+			],
+			"meta": {"fields": ["name", "count", "tags"], "rows": 2, "total_rows": 5}
+		}`))
+	}))
+	defer server.Close()
+
+	client := hbapi.NewClient().
+		WithBaseURL(server.URL).
+		WithAuthToken("test-token")
+
+	tests := []struct {
+		output string
+		want   string
+	}{
+		{"csv", "name,count,tags\n\"web, main\",10,\"[\"\"a\"\"]\"\napi,15.5,\n"},
+		{"tsv", "name\tcount\ttags\nweb, main\t10\t\"[\"\"a\"\"]\"\napi\t15.5\t\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.output, func(t *testing.T) {
+			req := mcp.CallToolRequest{}
+			req.Params.Arguments = map[string]any{"project_id": 123, "query": "fields name, count, tags", "output": tt.output}
+
+			result, err := handleQueryInsights(context.Background(), client, req)
+			if err != nil {
+				t.Fatalf("handleQueryInsights() error = %v", err)
+			}
+			if result.IsError {
+				t.Fatalf("unexpected error result: %s", getResultText(result))
+			}
+			if got := getResultText(result); got != tt.want {
+				t.Errorf("expected table %q, got %q", tt.want, got)
+			}
+			if notes := getResultNotes(t, result); len(notes.Warnings) != 1 || !strings.Contains(notes.Warnings[0], "2 of 5 rows") {
+				t.Errorf("expected the truncation warning, got %v", notes.Warnings)
+			}
+		})
+	}
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"project_id": 123, "query": "fields name", "output": "xml"}
+	result, _ := handleQueryInsights(context.Background(), client, req)
+	if !result.IsError || !strings.Contains(getResultText(result), "output must be") {
+		t.Errorf("expected an unknown output to be refused, got %q", getResultText(result))
+	}
+}
+
+func TestInsightsTable_NoFields(t *testing.T) {
+	response := &hbapi.InsightsQueryResponse{Results: []map[string]interface{}{{"b": "x", "a": 1}, {"c": true}}}
+	got, err := insightsTable(response, ',')
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "a,b,c\n1,x,\n,,true\n"; got != want {
+		t.Errorf("expected columns from the rows' keys, got %q", got)
+	}
+}
+
 func TestHandleQueryInsights_InlineError(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
@@ -367,3 +432,195 @@ func TestHandleQueryInsights_ProjectNotFound(t *testing.T) {
 		t.Error("Error message should contain 'Failed to query insights'")
 	}
 }
+
+func TestHandleValidateInsightsQuery(t *testing.T) {
+	cases := []struct {
+		name       string
+		status     int
+		body       string
+		wantError  bool
+		wantValid  bool
+		wantReason string
+	}{
+		{
+			name:      "valid query",
+			status:    http.StatusOK,
+			body:      `{"results":[],"meta":{"fields":["count"],"schema":[{"name":"count","type":"UInt64"}],"rows":0,"total_rows":0}}`,
+			wantValid: true,
+		},
+		{
+			name:       "parse error",
+			status:     http.StatusUnprocessableEntity,
+			body:       `{"errors":"Unexpected token 'bogus' at line 1"}`,
+			wantReason: "Unexpected token 'bogus' at line 1",
+		},
+		{
+			name:       "query error in response",
+			status:     http.StatusOK,
+			body:       `{"results":[],"meta":{},"error":{"message":"Unknown function foo()"}}`,
+			wantReason: "Unknown function foo()",
+		},
+		{
+			name:      "auth failure is a tool error",
+			status:    http.StatusUnauthorized,
+			body:      `{"errors":"Unauthorized"}`,
+			wantError: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var reqBody hbapi.InsightsQueryRequest
+				if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+					t.Errorf("Failed to decode request body: %v", err)
+				}
+				if reqBody.Query != "stats count()\n| limit 1" {
+					t.Errorf("expected the query to be limited, got %q", reqBody.Query)
+				}
+				if reqBody.Ts != validationWindow {
+					t.Errorf("expected ts %s, got %s", validationWindow, reqBody.Ts)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(c.status)
+				_, _ = w.Write([]byte(c.body))
+			}))
+			defer server.Close()
+
+			client := hbapi.NewClient().
+				WithBaseURL(server.URL).
+				WithAuthToken("test-token")
+
+			req := mcp.CallToolRequest{
+				Params: mcp.CallToolParams{
+					Arguments: map[string]interface{}{
+						"project_id": 123,
+						"query":      "stats count()",
+					},
+				},
+			}
+
+			result, err := handleValidateInsightsQuery(context.Background(), client, req)
+			if err != nil {
+				t.Fatalf("handleValidateInsightsQuery() error = %v", err)
+			}
+			if result.IsError != c.wantError {
+				t.Fatalf("expected IsError %v, got %v: %s", c.wantError, result.IsError, getResultText(result))
+			}
+			if c.wantError {
+				return
+			}
+
+			var validation queryValidation
+			if err := json.Unmarshal([]byte(getResultText(result)), &validation); err != nil {
+				t.Fatalf("failed to unmarshal result: %v", err)
+			}
+			if validation.Valid != c.wantValid {
+				t.Errorf("expected valid %v, got %v", c.wantValid, validation.Valid)
+			}
+			if validation.Error != c.wantReason {
+				t.Errorf("expected error %q, got %q", c.wantReason, validation.Error)
+			}
+			if c.wantValid && (len(validation.Fields) != 1 || len(validation.Schema) != 1) {
+				t.Errorf("expected fields and schema, got %+v", validation)
+			}
+		})
+	}
+}
+
+func TestPreflightInsightsQuery(t *testing.T) {
+	rows := 50000
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body hbapi.InsightsQueryRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		if body.Query != "filter event_type::str == \"request\"\n| stats count() as preflight_rows" || body.Ts != "P7D" {
+			t.Errorf("unexpected preflight request %+v", body)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"results": [{"preflight_rows": %d}], "meta": {"rows": 1, "total_rows": 1}}`, rows)
+	}))
+	defer server.Close()
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"project_id": 123, "query": `filter event_type::str == "request"`, "ts": "P7D", "preflight": true}
+	result := preflightInsightsQuery(context.Background(), client, req, 10000)
+	if result == nil || result.IsError {
+		t.Fatalf("expected the query held back, got %v", result)
+	}
+	var got insightsPreflight
+	if err := json.Unmarshal([]byte(getResultText(result)), &got); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if got.Rows != 50000 || got.MaxRows != 10000 || got.Ran {
+		t.Errorf("unexpected preflight %+v", got)
+	}
+	if notes := result.Content[len(result.Content)-1].(mcp.TextContent).Text; !strings.Contains(notes, "query held back") {
+		t.Errorf("expected a warning, got %s", notes)
+	}
+
+	rows = 200
+	if result := preflightInsightsQuery(context.Background(), client, req, 10000); result != nil {
+		t.Errorf("expected the query to go ahead, got %s", getResultText(result))
+	}
+}
+
+func TestHandleGetInsightsSchema(t *testing.T) {
+	var reqBody hbapi.InsightsQueryRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&reqBody); err != nil {
+			t.Errorf("Failed to decode request body: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"results": [
+			{"@preview": {"event_type": "request", "duration": 12, "user": {"id": 7, "admin": false}}},
+			{"@preview": "{\"event_type\": \"request\", \"duration\": 3.5, \"user\": {\"id\": 8, \"admin\": null}}"},
+			{"@preview": {"event_type": "job", "queue": "default"}},
+			{"@preview": 42}
+		], "meta": {}}`))
+	}))
+	defer server.Close()
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"project_id": float64(5), "event_type": "request", "sample": float64(50)}
+	result, err := handleGetInsightsSchema(context.Background(), client, req)
+	if err != nil || result.IsError {
+		t.Fatalf("unexpected error: %v %s", err, getResultText(result))
+	}
+	if reqBody.Query != "fields @preview\n| filter event_type::str == \"request\"\n| sort @ts desc\n| limit 50" || reqBody.Ts != defaultSchemaWindow {
+		t.Errorf("unexpected query %+v", reqBody)
+	}
+
+	var got insightsSchema
+	if err := json.Unmarshal([]byte(getResultText(result)), &got); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if got.Sampled != 3 || len(got.EventTypes) != 2 || got.EventTypes[0] != (insightsEventSeen{EventType: "request", Count: 2}) {
+		t.Errorf("unexpected sample %+v", got)
+	}
+	queries := map[string]string{}
+	for _, f := range got.Fields {
+		queries[f.Name] = fmt.Sprintf("%s seen %d", f.Query, f.Seen)
+	}
+	want := map[string]string{
+		"event_type": "event_type::str seen 3",
+		"duration":   "duration::float seen 2",
+		"user.id":    "user.id::int seen 2",
+		"user.admin": "user.admin::bool seen 2",
+		"queue":      "queue::str seen 1",
+	}
+	for name, w := range want {
+		if queries[name] != w {
+			t.Errorf("%s: expected %q, got %q", name, w, queries[name])
+		}
+	}
+	if got.Fields[0].Name != "event_type" || got.Fields[len(got.Fields)-1].Name != "queue" {
+		t.Errorf("expected the fields ordered most seen first, got %+v", got.Fields)
+	}
+	if notes := result.Content[len(result.Content)-1].(mcp.TextContent).Text; !strings.Contains(notes, "skipped 1 events") {
+		t.Errorf("expected a warning about the unreadable event, got %s", notes)
+	}
+}
@@ -0,0 +1,147 @@
+package hbmcp
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"text/template"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+//go:embed install/*.md
+var installFS embed.FS
+
+// installTemplates holds one template per framework, named <framework>.md.
+var installTemplates = template.Must(template.ParseFS(installFS, "install/*.md"))
+
+// installDocs is where each language's client library is documented.
+const installDocs = "https://docs.honeybadger.io/lib/"
+
+type installFramework struct {
+	Name     string
+	Language string
+	DocsURL  string
+}
+
+// installFrameworks are the frameworks install instructions exist for,
+// each with a template in install/.
+var installFrameworks = []installFramework{
+	{"rails", "ruby", installDocs + "ruby/"},
+	{"ruby", "ruby", installDocs + "ruby/"},
+	{"node", "javascript", installDocs + "javascript/"},
+	{"express", "javascript", installDocs + "javascript/"},
+	{"browser", "javascript", installDocs + "javascript/"},
+	{"react", "javascript", installDocs + "javascript/"},
+	{"python", "python", installDocs + "python/"},
+	{"django", "python", installDocs + "python/"},
+	{"flask", "python", installDocs + "python/"},
+	{"go", "go", installDocs + "go/"},
+	{"php", "php", installDocs + "php/"},
+	{"laravel", "php", installDocs + "php/"},
+}
+
+func installFrameworkNames() []string {
+	names := make([]string, len(installFrameworks))
+	for i, f := range installFrameworks {
+		names[i] = f.Name
+	}
+	return names
+}
+
+// installKeyNote goes with every rendered snippet, since each embeds the
+// project's API key.
+const installKeyNote = "The snippets include the project's API key. Prefer reading it from the environment (HONEYBADGER_API_KEY) or a secrets manager over committing it."
+
+// renderInstall renders framework's instructions for the project's API key.
+func renderInstall(framework string, project *hbapi.Project) (string, error) {
+	var sb strings.Builder
+	err := installTemplates.ExecuteTemplate(&sb, framework+".md", struct {
+		APIKey      string
+		ProjectID   int
+		ProjectName string
+	}{project.Token, project.ID, project.Name})
+	return sb.String(), err
+}
+
+type installInstructions struct {
+	ProjectID    int    `json:"project_id"`
+	ProjectName  string `json:"project_name"`
+	Framework    string `json:"framework"`
+	Language     string `json:"language"`
+	DocsURL      string `json:"docs_url"`
+	Instructions string `json:"instructions"`
+	Note         string `json:"note"`
+}
+
+// RegisterInstallTools registers get_install_instructions.
+func RegisterInstallTools(r *toolRegistrar, clientFor ClientFactory) {
+	// get_install_instructions tool
+	r.AddTool(
+		mcp.NewTool("get_install_instructions",
+			mcp.WithTitleAnnotation("Get Install Instructions"),
+			mcp.WithDescription("Get the steps and code to add Honeybadger error reporting to an app in a given framework, filled in with the project's real API key, as Markdown. Use this when wiring error reporting into a codebase rather than writing the client configuration from memory."),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithOutputSchema[installInstructions](),
+			mcp.WithNumber("project_id",
+				mcp.Required(),
+				mcp.Description("The ID of the project the app reports errors to"),
+				mcp.Min(1),
+			),
+			mcp.WithString("framework",
+				mcp.Required(),
+				mcp.Description("The app's framework, or its language for apps without one (e.g. 'ruby', 'node', 'python')"),
+				mcp.Enum(installFrameworkNames()...),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleGetInstallInstructions(ctx, clientFor(ctx), req)
+		},
+	)
+}
+
+func handleGetInstallInstructions(ctx context.Context, client *hbapi.Client, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	projectID := req.GetInt("project_id", 0)
+	if projectID == 0 {
+		return mcp.NewToolResultError("project_id is required"), nil
+	}
+
+	name := strings.ToLower(req.GetString("framework", ""))
+	i := slices.IndexFunc(installFrameworks, func(f installFramework) bool { return f.Name == name })
+	if i < 0 {
+		return mcp.NewToolResultError(fmt.Sprintf("framework must be one of %s, got %q", strings.Join(installFrameworkNames(), ", "), name)), nil
+	}
+	framework := installFrameworks[i]
+
+	project, err := client.Projects.Get(ctx, projectID)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to get project: %v", err)), nil
+	}
+	instructions, err := renderInstall(framework.Name, project)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to render instructions: %v", err)), nil
+	}
+
+	// Return JSON response
+	jsonBytes, err := json.Marshal(installInstructions{
+		ProjectID:    project.ID,
+		ProjectName:  project.Name,
+		Framework:    framework.Name,
+		Language:     framework.Language,
+		DocsURL:      framework.DocsURL,
+		Instructions: instructions,
+		Note:         installKeyNote,
+	})
+	if err != nil {
+		return mcp.NewToolResultError("Failed to marshal response"), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonBytes)), nil
+}
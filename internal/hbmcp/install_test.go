@@ -0,0 +1,62 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestInstallTemplates(t *testing.T) {
+	project := &hbapi.Project{ID: 5, Name: "Checkout", Token: "hbp_abc123"}
+	for _, f := range installFrameworks {
+		got, err := renderInstall(f.Name, project)
+		if err != nil {
+			t.Errorf("%s: %v", f.Name, err)
+			continue
+		}
+		if !strings.Contains(got, "hbp_abc123") {
+			t.Errorf("%s: expected the API key in\n%s", f.Name, got)
+		}
+	}
+	if len(installTemplates.Templates()) != len(installFrameworks) {
+		t.Errorf("expected one template per framework, got %s", installTemplates.DefinedTemplates())
+	}
+}
+
+func TestHandleGetInstallInstructions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/projects/5" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": 5, "name": "Checkout", "token": "hbp_abc123"}`))
+	}))
+	defer server.Close()
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"project_id": float64(5), "framework": "django"}
+	result, err := handleGetInstallInstructions(context.Background(), client, req)
+	if err != nil || result.IsError {
+		t.Fatalf("unexpected error: %v %s", err, getResultText(result))
+	}
+	var got installInstructions
+	if err := json.Unmarshal([]byte(getResultText(result)), &got); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if got.Language != "python" || got.ProjectName != "Checkout" || !strings.Contains(got.Instructions, "hbp_abc123") {
+		t.Errorf("unexpected instructions %+v", got)
+	}
+
+	req.Params.Arguments = map[string]any{"project_id": float64(5), "framework": "elixir"}
+	result, err = handleGetInstallInstructions(context.Background(), client, req)
+	if err != nil || !result.IsError {
+		t.Errorf("expected an error result for an unknown framework, got %s", getResultText(result))
+	}
+}
@@ -0,0 +1,97 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+type integrationTest struct {
+	ProjectID     int    `json:"project_id"`
+	IntegrationID int    `json:"integration_id"`
+	Type          string `json:"type"`
+	Active        bool   `json:"active"`
+	Sent          bool   `json:"sent"`
+	Result        any    `json:"result,omitempty"`
+}
+
+// RegisterIntegrationTools registers test_project_integration. It takes
+// the raw client factory as well, since hbapi can't test integrations.
+func RegisterIntegrationTools(r *toolRegistrar, clientFor ClientFactory, rawFor RawClientFactory) {
+	// test_project_integration tool
+	r.AddTool(
+		mcp.NewTool("test_project_integration",
+			mcp.WithTitleAnnotation("Test Project Integration"),
+			mcp.WithDescription("Send a test notification through one of a project's integrations (channels), such as Slack or PagerDuty, to check that it's delivered. Use after connecting or changing an integration; get_project_integrations lists the IDs. The notification reaches the real channel, so tell the user to expect it."),
+			mcp.WithReadOnlyHintAnnotation(false),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(false),
+			mcp.WithOpenWorldHintAnnotation(true),
+			mcp.WithNumber("project_id",
+				mcp.Required(),
+				mcp.Description("The ID of the project the integration belongs to"),
+				mcp.Min(1),
+			),
+			mcp.WithNumber("integration_id",
+				mcp.Required(),
+				mcp.Description("The ID of the integration to test"),
+				mcp.Min(1),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleTestProjectIntegration(ctx, clientFor(ctx), rawFor(ctx), req)
+		},
+	)
+}
+
+func handleTestProjectIntegration(ctx context.Context, client *hbapi.Client, raw *RawClient, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	projectID := req.GetInt("project_id", 0)
+	if projectID == 0 {
+		return mcp.NewToolResultError("project_id is required"), nil
+	}
+
+	integrationID := req.GetInt("integration_id", 0)
+	if integrationID == 0 {
+		return mcp.NewToolResultError("integration_id is required"), nil
+	}
+
+	integrations, err := client.Projects.GetIntegrations(ctx, projectID)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to get project integrations: %v", err)), nil
+	}
+	i := slices.IndexFunc(integrations, func(in hbapi.ProjectIntegration) bool { return in.ID == integrationID })
+	if i < 0 {
+		return mcp.NewToolResultError(fmt.Sprintf("project %d has no integration %d; list them with get_project_integrations", projectID, integrationID)), nil
+	}
+	integration := integrations[i]
+
+	var response any
+	if err := raw.Raw(ctx, http.MethodPost, fmt.Sprintf("/projects/%d/integrations/%d/test", projectID, integrationID), nil, &response); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to test %s integration %d: %v", integration.Type, integrationID, err)), nil
+	}
+
+	var notes toolNotes
+	if !integration.Active {
+		notes.warnf("integration %d is inactive, so real notifications won't go through it until it's turned back on", integrationID)
+	}
+
+	// Return JSON response
+	jsonBytes, err := json.Marshal(integrationTest{
+		ProjectID:     projectID,
+		IntegrationID: integrationID,
+		Type:          integration.Type,
+		Active:        integration.Active,
+		Sent:          true,
+		Result:        response,
+	})
+	if err != nil {
+		return mcp.NewToolResultError("Failed to marshal response"), nil
+	}
+
+	return withNotes(mcp.NewToolResultText(string(jsonBytes)), &notes), nil
+}
@@ -0,0 +1,63 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestHandleTestProjectIntegration(t *testing.T) {
+	var tested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v2/projects/5/integrations":
+			_, _ = w.Write([]byte(`[{"id": 9, "type": "slack", "active": false}, {"id": 10, "type": "pagerduty", "active": true}]`))
+		case r.Method == http.MethodPost && r.URL.Path == "/v2/projects/5/integrations/9/test":
+			tested = append(tested, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+	raw := &RawClient{baseURL: server.URL, authToken: "test-token", httpClient: http.DefaultClient}
+
+	call := func(integrationID int) *mcp.CallToolResult {
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = map[string]any{"project_id": float64(5), "integration_id": float64(integrationID)}
+		result, err := handleTestProjectIntegration(context.Background(), client, raw, req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return result
+	}
+
+	result := call(9)
+	if result.IsError {
+		t.Fatalf("unexpected error: %s", getResultText(result))
+	}
+	var got integrationTest
+	if err := json.Unmarshal([]byte(getResultText(result)), &got); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if !got.Sent || got.Type != "slack" || len(tested) != 1 {
+		t.Errorf("expected the slack integration tested, got %+v after %v", got, tested)
+	}
+	if notes := result.Content[len(result.Content)-1].(mcp.TextContent).Text; !strings.Contains(notes, "integration 9 is inactive") {
+		t.Errorf("expected a warning about the inactive integration, got %s", notes)
+	}
+
+	result = call(11)
+	if !result.IsError || !strings.Contains(getResultText(result), "project 5 has no integration 11") || len(tested) != 1 {
+		t.Errorf("expected an unknown integration refused before testing, got %s", getResultText(result))
+	}
+}
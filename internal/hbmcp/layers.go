@@ -0,0 +1,149 @@
+package hbmcp
+
+import (
+	"log/slog"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// toolLayer is one cross-cutting behaviour the registrar gives every tool,
+// so it lives in one place instead of in each handler and new tools get it
+// without doing anything.
+type toolLayer struct {
+	name string
+
+	// define, when set, adjusts the tool's definition, usually to add an
+	// argument the layer handles. Every layer's define runs before any
+	// wrap, so wrap sees the final definition.
+	define func(tool *mcp.Tool)
+
+	// wrap returns next with the layer applied, or next itself for tools
+	// the layer doesn't apply to.
+	wrap func(tool mcp.Tool, next server.ToolHandlerFunc) server.ToolHandlerFunc
+}
+
+// middlewareLayer applies m to every tool alike. Unlike server middleware
+// it also applies to calls made through invoke_tool.
+func middlewareLayer(name string, m server.ToolHandlerMiddleware) toolLayer {
+	return toolLayer{name: name, wrap: func(_ mcp.Tool, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return m(next)
+	}}
+}
+
+// layers returns the layers AddTool applies, outermost first:
+//
+//	recover      turns a panic below into an error result
+//	structured   returns the JSON result as structured content as well
+//	summarize    replaces a result over the token limit with a summary, if
+//	             one is set
+//	fields       shapes whatever result the layers below settle on
+//	coerce       converts arguments to the types the input schema declares
+//	validate     rejects arguments that don't match the input schema
+//	r.middleware metrics, project_name resolution, project scope, session
+//	             budget, concurrency limit, and the raw body fallback
+//	read_only    refuses write tools while the server is read-only
+//	cache        reuses an earlier read-only result on request
+//	confirm      holds destructive calls until they're confirmed
+//	replay       answers a retried create call with the first one's result
+//	timeout      bounds how long the handler runs
+//
+// coerce and validate run before r.middleware so project name resolution
+// and the project scope check see the arguments the handler will.
+func (r *toolRegistrar) layers() []toolLayer {
+	logger := r.logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	layers := []toolLayer{
+		{name: "recover", wrap: recoverPanics(logger)},
+		{
+			name:   "structured",
+			define: loosenOutputSchema,
+			wrap: func(tool mcp.Tool, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+				if !hasOutputSchema(tool) {
+					return next
+				}
+				return structuredResults(next)
+			},
+		},
+	}
+	if r.maxResultTokens > 0 {
+		layers = append(layers, toolLayer{name: "summarize", wrap: summarizeResults(r.maxResultTokens)})
+	}
+	layers = append(layers,
+		toolLayer{
+			name:   "fields",
+			define: withFieldsArg,
+			wrap: func(_ mcp.Tool, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+				return shapeFields(next)
+			},
+		},
+		toolLayer{name: "coerce", wrap: coerceArguments},
+		toolLayer{name: "validate", wrap: validateArguments},
+	)
+	layers = append(layers, r.middleware...)
+	if r.current != nil {
+		layers = append(layers, toolLayer{name: "read_only", wrap: func(tool mcp.Tool, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+			if isReadOnly(tool) {
+				return next
+			}
+			return guardWrites(r.current, tool.Name, next)
+		}})
+	}
+	if r.results != nil {
+		layers = append(layers, toolLayer{
+			name: "cache",
+			define: func(tool *mcp.Tool) {
+				if cachesResults(*tool) {
+					withCacheArg(tool)
+				}
+			},
+			wrap: func(tool mcp.Tool, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+				if !cachesResults(tool) {
+					return next
+				}
+				return r.results.cached(tool, next)
+			},
+		})
+	}
+	if r.confirmations != nil {
+		layers = append(layers, toolLayer{
+			name: "confirm",
+			define: func(tool *mcp.Tool) {
+				if isDestructive(*tool) {
+					withConfirmationArg(tool)
+				}
+			},
+			wrap: func(tool mcp.Tool, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+				if !isDestructive(tool) {
+					return next
+				}
+				return r.confirmations.require(tool, next)
+			},
+		})
+	}
+	if r.creates != nil {
+		layers = append(layers, toolLayer{name: "replay", wrap: func(tool mcp.Tool, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+			if !replayedCreates[tool.Name] {
+				return next
+			}
+			return r.creates.replay(tool, next)
+		}})
+	}
+	return append(layers, toolLayer{
+		name:   "timeout",
+		define: withTimeoutArg,
+		wrap: func(_ mcp.Tool, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+			return toolTimeout(next)
+		},
+	})
+}
+
+func isReadOnly(tool mcp.Tool) bool {
+	return tool.Annotations.ReadOnlyHint != nil && *tool.Annotations.ReadOnlyHint
+}
+
+func isDestructive(tool mcp.Tool) bool {
+	return tool.Annotations.DestructiveHint != nil && *tool.Annotations.DestructiveHint
+}
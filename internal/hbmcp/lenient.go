@@ -0,0 +1,279 @@
+package hbmcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	hbapi "github.com/honeybadger-io/api-go"
+)
+
+// lenientRoutes maps API paths to the type the client decodes their
+// responses into. Only these responses are coerced in lenient mode; the
+// notice and fault payloads carry the most free-form, client-reported
+// data.
+var lenientRoutes = []struct {
+	path *regexp.Regexp
+	typ  reflect.Type
+}{
+	{regexp.MustCompile(`/v2/projects/\d+/faults/\d+/notices$`), reflect.TypeFor[hbapi.FaultNoticesResponse]()},
+	{regexp.MustCompile(`/v2/projects/\d+/faults/\d+$`), reflect.TypeFor[hbapi.Fault]()},
+	{regexp.MustCompile(`/v2/projects/\d+/faults$`), reflect.TypeFor[hbapi.FaultListResponse]()},
+	{regexp.MustCompile(`/v2/projects/\d+$`), reflect.TypeFor[hbapi.Project]()},
+	{regexp.MustCompile(`/v2/projects$`), reflect.TypeFor[hbapi.ProjectsResponse]()},
+}
+
+// maxLenientFixes bounds how many coerced fields are named in the log.
+const maxLenientFixes = 10
+
+// lenientTransport rewrites responses that wouldn't decode into the
+// client's types so that they do: values of the wrong JSON type are
+// converted where that's lossless (numeric strings to numbers, scalars to
+// strings) and dropped otherwise. Responses that already decode pass
+// through untouched.
+type lenientTransport struct {
+	base   http.RoundTripper
+	logger *slog.Logger
+}
+
+func (t *lenientTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || req.Method != http.MethodGet || resp.StatusCode >= 400 {
+		return resp, err
+	}
+	typ := lenientType(req.URL.Path)
+	if typ == nil {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	if fixed, fixes := lenientDecode(body, typ); len(fixes) > 0 {
+		t.logger.WarnContext(req.Context(), "Coerced API response fields that didn't match the client's types",
+			"path", req.URL.Path, "count", len(fixes), "fields", fixes[:min(len(fixes), maxLenientFixes)])
+		body = fixed
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	resp.ContentLength = int64(len(body))
+	resp.Header.Del("Content-Length")
+	return resp, nil
+}
+
+func lenientType(path string) reflect.Type {
+	for _, r := range lenientRoutes {
+		if r.path.MatchString(path) {
+			return r.typ
+		}
+	}
+	return nil
+}
+
+// lenientDecode returns body rewritten to decode into typ, and the paths of
+// the fields it changed. A body that already decodes, or that isn't JSON
+// at all, is returned unchanged with no fixes.
+func lenientDecode(body []byte, typ reflect.Type) ([]byte, []string) {
+	if json.Unmarshal(body, reflect.New(typ).Interface()) == nil {
+		return body, nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var doc any
+	if dec.Decode(&doc) != nil {
+		return body, nil
+	}
+	var fixes []string
+	fixed, err := json.Marshal(coerce(doc, typ, "", &fixes))
+	if err != nil {
+		return body, nil
+	}
+	return fixed, fixes
+}
+
+var (
+	timeType        = reflect.TypeFor[time.Time]()
+	unmarshalerType = reflect.TypeFor[json.Unmarshaler]()
+)
+
+// coerce returns v, a value decoded with UseNumber, converted to fit typ.
+// path locates v in the document for the fixes it appends to. A value
+// that can't be converted becomes nil, which decodes as the zero value.
+func coerce(v any, typ reflect.Type, path string, fixes *[]string) any {
+	if v == nil {
+		return nil
+	}
+	drop := func() any {
+		*fixes = append(*fixes, path)
+		return nil
+	}
+
+	if typ.Kind() == reflect.Pointer {
+		return coerce(v, typ.Elem(), path, fixes)
+	}
+	if typ != timeType && reflect.PointerTo(typ).Implements(unmarshalerType) {
+		// Types with their own decoding, like hbapi.Number, are taken as
+		// they are or not at all.
+		raw, err := json.Marshal(v)
+		if err != nil || json.Unmarshal(raw, reflect.New(typ).Interface()) != nil {
+			return drop()
+		}
+		return v
+	}
+
+	switch typ.Kind() {
+	case reflect.Interface:
+		return v
+	case reflect.String:
+		switch v := v.(type) {
+		case string:
+			return v
+		case json.Number:
+			*fixes = append(*fixes, path)
+			return v.String()
+		case bool:
+			*fixes = append(*fixes, path)
+			return strconv.FormatBool(v)
+		default:
+			raw, err := json.Marshal(v)
+			if err != nil {
+				return drop()
+			}
+			*fixes = append(*fixes, path)
+			return string(raw)
+		}
+	case reflect.Bool:
+		switch v := v.(type) {
+		case bool:
+			return v
+		case string:
+			if b, err := strconv.ParseBool(v); err == nil {
+				*fixes = append(*fixes, path)
+				return b
+			}
+		}
+		return drop()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, ok := numberOf(v)
+		if !ok {
+			return drop()
+		}
+		if i, err := n.Int64(); err == nil {
+			if _, isNumber := v.(json.Number); !isNumber {
+				*fixes = append(*fixes, path)
+			}
+			return json.Number(strconv.FormatInt(i, 10))
+		}
+		if f, err := n.Float64(); err == nil && f == float64(int64(f)) {
+			*fixes = append(*fixes, path)
+			return json.Number(strconv.FormatInt(int64(f), 10))
+		}
+		return drop()
+	case reflect.Float32, reflect.Float64:
+		n, ok := numberOf(v)
+		if !ok {
+			return drop()
+		}
+		if _, isNumber := v.(json.Number); !isNumber {
+			*fixes = append(*fixes, path)
+		}
+		return n
+	case reflect.Slice, reflect.Array:
+		items, ok := v.([]any)
+		if !ok {
+			return drop()
+		}
+		out := make([]any, len(items))
+		for i, item := range items {
+			out[i] = coerce(item, typ.Elem(), fmt.Sprintf("%s[%d]", path, i), fixes)
+		}
+		return out
+	case reflect.Map:
+		obj, ok := v.(map[string]any)
+		if !ok || typ.Key().Kind() != reflect.String {
+			return drop()
+		}
+		out := make(map[string]any, len(obj))
+		for k, item := range obj {
+			out[k] = coerce(item, typ.Elem(), joinPath(path, k), fixes)
+		}
+		return out
+	case reflect.Struct:
+		if typ == timeType {
+			if s, ok := v.(string); ok {
+				if _, err := time.Parse(time.RFC3339, s); err == nil {
+					return s
+				}
+			}
+			return drop()
+		}
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return drop()
+		}
+		out := make(map[string]any, len(obj))
+		for k, item := range obj {
+			if field, ok := jsonField(typ, k); ok {
+				out[k] = coerce(item, field.Type, joinPath(path, k), fixes)
+			} else {
+				out[k] = item
+			}
+		}
+		return out
+	default:
+		return drop()
+	}
+}
+
+// numberOf reads v as a number: a JSON number, or a string holding one.
+func numberOf(v any) (json.Number, bool) {
+	switch v := v.(type) {
+	case json.Number:
+		return v, true
+	case string:
+		s := strings.TrimSpace(v)
+		if _, err := strconv.ParseFloat(s, 64); err == nil {
+			return json.Number(s), true
+		}
+	}
+	return "", false
+}
+
+// jsonField finds the field of struct type typ that encoding/json would
+// decode key into, matching names case-insensitively as it does.
+func jsonField(typ reflect.Type, key string) (reflect.StructField, bool) {
+	for i := range typ.NumField() {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if strings.EqualFold(name, key) {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,191 @@
+package hbmcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+	"testing/quick"
+
+	hbapi "github.com/honeybadger-io/api-go"
+
+	"github.com/honeybadger-io/honeybadger-mcp-server/internal/config"
+)
+
+// lenientFixtures are API payloads with the polymorphic values client
+// libraries send in practice: PHP's [] for empty hashes, numbers as
+// strings, nulls, and objects where strings are expected.
+var lenientFixtures = []struct {
+	file string
+	typ  reflect.Type
+}{
+	{"notices_php.json", reflect.TypeFor[hbapi.FaultNoticesResponse]()},
+	{"notices_js.json", reflect.TypeFor[hbapi.FaultNoticesResponse]()},
+	{"fault.json", reflect.TypeFor[hbapi.Fault]()},
+}
+
+func readLenientFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	body, err := os.ReadFile(filepath.Join("testdata", "lenient", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return body
+}
+
+func TestLenientDecode_Fixtures(t *testing.T) {
+	for _, fx := range lenientFixtures {
+		t.Run(fx.file, func(t *testing.T) {
+			body := readLenientFixture(t, fx.file)
+			if json.Unmarshal(body, reflect.New(fx.typ).Interface()) == nil {
+				t.Fatal("fixture should fail strict decoding")
+			}
+			fixed, fixes := lenientDecode(body, fx.typ)
+			if len(fixes) == 0 {
+				t.Fatal("expected fixes")
+			}
+			if err := json.Unmarshal(fixed, reflect.New(fx.typ).Interface()); err != nil {
+				t.Fatalf("lenient output doesn't decode: %v", err)
+			}
+		})
+	}
+}
+
+func TestLenientDecode_KeepsConvertibleValues(t *testing.T) {
+	fixed, fixes := lenientDecode(readLenientFixture(t, "notices_php.json"), reflect.TypeFor[hbapi.FaultNoticesResponse]())
+	var response hbapi.FaultNoticesResponse
+	if err := json.Unmarshal(fixed, &response); err != nil {
+		t.Fatalf("lenient output doesn't decode: %v", err)
+	}
+	n := response.Results[0]
+	if n.Environment.PID != 2211 {
+		t.Errorf("expected pid 2211 from the string, got %d", n.Environment.PID)
+	}
+	if n.Environment.Revision == nil || *n.Environment.Revision != "4512" {
+		t.Errorf("expected revision \"4512\" from the number, got %v", n.Environment.Revision)
+	}
+	if n.Backtrace[0].Number != 87 || n.Backtrace[1].Number != 0 || *n.Backtrace[2].Column != 4 {
+		t.Errorf("unexpected backtrace lines: %+v", n.Backtrace)
+	}
+	if n.Message != `ErrorException: Undefined array key "total"` {
+		t.Errorf("message changed: %q", n.Message)
+	}
+	for _, want := range []string{"results[0].environment.stats", "results[0].request.params", "results[0].backtrace[1].number"} {
+		if !slices.Contains(fixes, want) {
+			t.Errorf("expected %q among fixes %v", want, fixes)
+		}
+	}
+}
+
+func TestLenientDecode_Unchanged(t *testing.T) {
+	body := []byte(`{"id": 1, "created_at": "2024-01-02T03:04:05Z", "tags": ["a"]}`)
+	fixed, fixes := lenientDecode(body, reflect.TypeFor[hbapi.Fault]())
+	if !bytes.Equal(fixed, body) || len(fixes) != 0 {
+		t.Errorf("expected a decodable body unchanged, got %s with fixes %v", fixed, fixes)
+	}
+	if fixed, _ := lenientDecode([]byte("<html>"), reflect.TypeFor[hbapi.Fault]()); string(fixed) != "<html>" {
+		t.Errorf("expected non-JSON unchanged, got %s", fixed)
+	}
+}
+
+// TestLenientDecode_Property replaces random values in the fixtures with
+// random JSON of any type and checks the lenient output always decodes.
+func TestLenientDecode_Property(t *testing.T) {
+	for _, fx := range lenientFixtures {
+		var doc any
+		if err := json.Unmarshal(readLenientFixture(t, fx.file), &doc); err != nil {
+			t.Fatal(err)
+		}
+		property := func(seed int64) bool {
+			rng := rand.New(rand.NewSource(seed))
+			mutated := mutateJSON(doc, rng)
+			body, err := json.Marshal(mutated)
+			if err != nil {
+				t.Fatal(err)
+			}
+			fixed, _ := lenientDecode(body, fx.typ)
+			if err := json.Unmarshal(fixed, reflect.New(fx.typ).Interface()); err != nil {
+				t.Logf("%s: seed %d: %v\n%s", fx.file, seed, err, body)
+				return false
+			}
+			return true
+		}
+		if err := quick.Check(property, &quick.Config{MaxCount: 500}); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+// mutateJSON copies v, replacing about one value in five with random JSON.
+func mutateJSON(v any, rng *rand.Rand) any {
+	if rng.Intn(5) == 0 {
+		return randomJSON(rng, 2)
+	}
+	switch v := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, item := range v {
+			out[k] = mutateJSON(item, rng)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = mutateJSON(item, rng)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+func randomJSON(rng *rand.Rand, depth int) any {
+	kinds := 6
+	if depth == 0 {
+		kinds = 4
+	}
+	switch rng.Intn(kinds) {
+	case 0:
+		return nil
+	case 1:
+		return rng.Intn(2) == 0
+	case 2:
+		return []any{1.5, -3.0, 42.0, 1e20}[rng.Intn(4)]
+	case 3:
+		return []string{"", "12", "?", "2024-01-02T03:04:05Z", "true", " 7 "}[rng.Intn(6)]
+	case 4:
+		return []any{randomJSON(rng, depth-1), randomJSON(rng, depth-1)}
+	default:
+		return map[string]any{"a": randomJSON(rng, depth-1)}
+	}
+}
+
+func TestLenientTransport(t *testing.T) {
+	body := readLenientFixture(t, "notices_php.json")
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(body)
+	}))
+	defer api.Close()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	for _, lenient := range []bool{false, true} {
+		cfg := &config.Config{APIURL: api.URL, AuthToken: "test-token", LenientDecoding: lenient}
+		_, err := NewClientFactory(cfg, logger)(context.Background()).Faults.ListNotices(context.Background(), 1, 9001, hbapi.FaultListNoticesOptions{})
+		if lenient && err != nil {
+			t.Errorf("expected lenient decoding to succeed, got %v", err)
+		}
+		if !lenient && err == nil {
+			t.Error("expected strict decoding to fail")
+		}
+	}
+}
@@ -0,0 +1,264 @@
+package hbmcp
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/honeybadger-io/honeybadger-mcp-server/internal/config"
+)
+
+// latencySamples is how many recent durations percentiles are computed
+// from, per tool and for the API as a whole.
+const latencySamples = 1000
+
+// usageMetrics counts tool calls and API requests since the server
+// started. It is kept in memory only; a restart starts it over.
+type usageMetrics struct {
+	started time.Time
+
+	mu    sync.Mutex
+	tools map[string]*toolUsage
+	api   apiUsage
+}
+
+type toolUsage struct {
+	calls   int
+	errors  int
+	latency latencyWindow
+}
+
+type apiUsage struct {
+	requests int
+	errors   int
+	latency  latencyWindow
+}
+
+// latencyWindow keeps the last latencySamples durations.
+type latencyWindow struct {
+	samples []time.Duration
+	next    int
+}
+
+func (w *latencyWindow) add(d time.Duration) {
+	if len(w.samples) < latencySamples {
+		w.samples = append(w.samples, d)
+		return
+	}
+	w.samples[w.next] = d
+	w.next = (w.next + 1) % latencySamples
+}
+
+// percentiles returns the 50th, 90th, and 99th percentile in milliseconds,
+// or nil when nothing was recorded.
+func (w *latencyWindow) percentiles() *latencyPercentiles {
+	if len(w.samples) == 0 {
+		return nil
+	}
+	sorted := slices.Clone(w.samples)
+	slices.Sort(sorted)
+	at := func(p float64) float64 {
+		i := int(math.Ceil(p*float64(len(sorted)))) - 1
+		return float64(sorted[max(i, 0)].Microseconds()) / 1000
+	}
+	return &latencyPercentiles{P50: at(0.50), P90: at(0.90), P99: at(0.99)}
+}
+
+type latencyPercentiles struct {
+	P50 float64 `json:"p50"`
+	P90 float64 `json:"p90"`
+	P99 float64 `json:"p99"`
+}
+
+// usageStats is the result of the stats tool.
+type usageStats struct {
+	Since time.Time   `json:"since"`
+	Tools []toolStats `json:"tools"`
+	API   apiStats    `json:"api"`
+}
+
+type toolStats struct {
+	Name      string              `json:"name"`
+	Calls     int                 `json:"calls"`
+	Errors    int                 `json:"errors"`
+	ErrorRate float64             `json:"error_rate"`
+	LatencyMS *latencyPercentiles `json:"latency_ms,omitempty"`
+}
+
+type apiStats struct {
+	Requests  int                 `json:"requests"`
+	Errors    int                 `json:"errors"`
+	ErrorRate float64             `json:"error_rate"`
+	LatencyMS *latencyPercentiles `json:"latency_ms,omitempty"`
+}
+
+type usageMetricsKey struct{}
+
+// statsEndpointKey marks a stats call made by StatsHandler, which is
+// served to operators rather than to a caller of the MCP endpoint.
+type statsEndpointKey struct{}
+
+func newUsageMetrics(now time.Time) *usageMetrics {
+	return &usageMetrics{started: now, tools: map[string]*toolUsage{}}
+}
+
+// middleware counts each call and its duration under the tool's name. A
+// call fails if it returns an error or an error result, including when
+// the arguments are rejected. It also puts m in the context so
+// metricsTransport can time the API requests the call makes.
+func (m *usageMetrics) middleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		start := time.Now()
+		result, err := next(context.WithValue(ctx, usageMetricsKey{}, m), req)
+		m.recordTool(req.Params.Name, time.Since(start), err != nil || (result != nil && result.IsError))
+		return result, err
+	}
+}
+
+func (m *usageMetrics) recordTool(name string, d time.Duration, failed bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	usage, ok := m.tools[name]
+	if !ok {
+		usage = &toolUsage{}
+		m.tools[name] = usage
+	}
+	usage.calls++
+	if failed {
+		usage.errors++
+	}
+	usage.latency.add(d)
+}
+
+func (m *usageMetrics) recordAPI(d time.Duration, failed bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.api.requests++
+	if failed {
+		m.api.errors++
+	}
+	m.api.latency.add(d)
+}
+
+// snapshot returns the stats so far, most called tools first.
+func (m *usageMetrics) snapshot() usageStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stats := usageStats{
+		Since: m.started,
+		Tools: []toolStats{},
+		API: apiStats{
+			Requests:  m.api.requests,
+			Errors:    m.api.errors,
+			ErrorRate: errorRate(m.api.errors, m.api.requests),
+			LatencyMS: m.api.latency.percentiles(),
+		},
+	}
+	for name, usage := range m.tools {
+		stats.Tools = append(stats.Tools, toolStats{
+			Name:      name,
+			Calls:     usage.calls,
+			Errors:    usage.errors,
+			ErrorRate: errorRate(usage.errors, usage.calls),
+			LatencyMS: usage.latency.percentiles(),
+		})
+	}
+	slices.SortFunc(stats.Tools, func(a, b toolStats) int {
+		return cmp.Or(cmp.Compare(b.Calls, a.Calls), cmp.Compare(a.Name, b.Name))
+	})
+	return stats
+}
+
+func errorRate(failed, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(failed)/float64(total)*1000) / 1000
+}
+
+// metricsTransport times each API request made during a tool call in the
+// usageMetrics the call's context carries, counting every retry as a
+// request of its own. A request fails if it gets no response or a 4xx or
+// 5xx one.
+type metricsTransport struct {
+	base http.RoundTripper
+}
+
+func (t *metricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	m, _ := req.Context().Value(usageMetricsKey{}).(*usageMetrics)
+	if m == nil {
+		return t.base.RoundTrip(req)
+	}
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	m.recordAPI(time.Since(start), err != nil || resp.StatusCode >= 400)
+	return resp, err
+}
+
+var statsToolInfo = ToolInfo{
+	Name:        "stats",
+	Description: "Show how tools have been used since the server started: calls, error rates, and latency percentiles per tool, and request counts, error rate, and latency percentiles for the Honeybadger API. Latencies are in milliseconds, over each tool's last 1000 calls and the API's last 1000 requests. The stats cover every caller of the server, so they're only available over stdio. Takes no parameters.",
+	ReadOnly:    true,
+}
+
+// registerStatsTool registers the stats tool directly on s, like
+// search_tools, so checking usage doesn't count as usage.
+func registerStatsTool(s *server.MCPServer, metrics *usageMetrics, current func() *config.Config) {
+	s.AddTool(
+		mcp.NewTool(statsToolInfo.Name,
+			mcp.WithTitleAnnotation("Usage Stats"),
+			mcp.WithDescription(statsToolInfo.Description),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if CallTransport(ctx, current()) == config.TransportHTTP && ctx.Value(statsEndpointKey{}) == nil {
+				return mcp.NewToolResultError("stats reports the usage of every caller of this server, so it is only available over stdio"), nil
+			}
+			// Return JSON response
+			jsonBytes, err := json.Marshal(metrics.snapshot())
+			if err != nil {
+				return mcp.NewToolResultError("Failed to marshal response"), nil
+			}
+
+			return mcp.NewToolResultText(string(jsonBytes)), nil
+		},
+	)
+}
+
+// StatsHandler serves what the stats tool returns, for operators of an
+// http mode server, who should put it behind the same authentication as
+// the MCP endpoint. It responds 404 when the stats tool is disabled.
+func StatsHandler(s *server.MCPServer) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tool := s.GetTool(statsToolInfo.Name)
+		if tool == nil {
+			http.NotFound(w, r)
+			return
+		}
+		req := mcp.CallToolRequest{}
+		req.Params.Name = statsToolInfo.Name
+		result, err := tool.Handler(context.WithValue(r.Context(), statsEndpointKey{}, true), req)
+		if err != nil || result.IsError || len(result.Content) == 0 {
+			http.Error(w, "stats unavailable", http.StatusInternalServerError)
+			return
+		}
+		text, _ := mcp.AsTextContent(result.Content[0])
+		if text == nil {
+			http.Error(w, "stats unavailable", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(text.Text))
+	})
+}
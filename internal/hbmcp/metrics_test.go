@@ -0,0 +1,207 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/honeybadger-io/honeybadger-mcp-server/internal/config"
+)
+
+func TestLatencyWindow_Percentiles(t *testing.T) {
+	var w latencyWindow
+	if w.percentiles() != nil {
+		t.Error("expected no percentiles before any samples")
+	}
+	for i := 1; i <= 100; i++ {
+		w.add(time.Duration(i) * time.Millisecond)
+	}
+	got := w.percentiles()
+	if got.P50 != 50 || got.P90 != 90 || got.P99 != 99 {
+		t.Errorf("expected p50=50 p90=90 p99=99, got %+v", got)
+	}
+
+	// Older samples give way to newer ones.
+	for range latencySamples {
+		w.add(time.Second)
+	}
+	if got := w.percentiles(); got.P50 != 1000 || len(w.samples) != latencySamples {
+		t.Errorf("expected only the last %d samples to count, got %+v over %d", latencySamples, got, len(w.samples))
+	}
+}
+
+func TestUsageMetrics_Middleware(t *testing.T) {
+	m := newUsageMetrics(time.Now())
+	handler := m.middleware(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if ctx.Value(usageMetricsKey{}) != m {
+			t.Error("expected the metrics in the handler's context")
+		}
+		switch req.GetString("outcome", "") {
+		case "error result":
+			return mcp.NewToolResultError("nope"), nil
+		case "error":
+			return nil, errors.New("boom")
+		}
+		return mcp.NewToolResultText("ok"), nil
+	})
+
+	call := func(name, outcome string) {
+		req := mcp.CallToolRequest{}
+		req.Params.Name = name
+		req.Params.Arguments = map[string]any{"outcome": outcome}
+		_, _ = handler(context.Background(), req)
+	}
+	call("list_faults", "")
+	call("list_faults", "error result")
+	call("list_faults", "")
+	call("list_faults", "error")
+	call("get_fault", "")
+
+	stats := m.snapshot()
+	if len(stats.Tools) != 2 {
+		t.Fatalf("expected 2 tools, got %+v", stats.Tools)
+	}
+	faults := stats.Tools[0]
+	if faults.Name != "list_faults" || faults.Calls != 4 || faults.Errors != 2 || faults.ErrorRate != 0.5 || faults.LatencyMS == nil {
+		t.Errorf("unexpected list_faults stats: %+v", faults)
+	}
+	if got := stats.Tools[1]; got.Name != "get_fault" || got.Calls != 1 || got.Errors != 0 || got.ErrorRate != 0 {
+		t.Errorf("unexpected get_fault stats: %+v", got)
+	}
+	if stats.API.Requests != 0 || stats.API.LatencyMS != nil {
+		t.Errorf("expected no API requests, got %+v", stats.API)
+	}
+}
+
+func TestMetricsTransport(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer api.Close()
+
+	m := newUsageMetrics(time.Now())
+	client := &http.Client{Transport: &metricsTransport{base: http.DefaultTransport}}
+	get := func(ctx context.Context, path string) {
+		t.Helper()
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, api.URL+path, nil)
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("request failed: %v", err)
+		}
+		_ = resp.Body.Close()
+	}
+
+	ctx := context.WithValue(context.Background(), usageMetricsKey{}, m)
+	get(ctx, "/ok")
+	get(ctx, "/missing")
+	get(context.Background(), "/ok")
+
+	stats := m.snapshot()
+	if stats.API.Requests != 2 || stats.API.Errors != 1 || stats.API.ErrorRate != 0.5 || stats.API.LatencyMS == nil {
+		t.Errorf("expected 2 requests recorded with 1 error, got %+v", stats.API)
+	}
+}
+
+func TestStats_Server(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"results": [], "links": {}}`))
+	}))
+	defer api.Close()
+
+	cfg := reloadTestConfig()
+	cfg.APIURL = api.URL
+	s, catalog, _ := NewReloadableServer(cfg, "test")
+	session := newTestSession(t, s)
+
+	found := false
+	for _, tool := range catalog {
+		found = found || tool.Name == statsToolInfo.Name
+	}
+	if !found {
+		t.Error("expected stats in the catalog")
+	}
+
+	call := func(name string, args map[string]any) *mcp.CallToolResult {
+		t.Helper()
+		params, _ := json.Marshal(map[string]any{"name": name, "arguments": args})
+		msg := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":` + string(params) + `}`
+		resp, ok := s.HandleMessage(s.WithContext(context.Background(), session), []byte(msg)).(mcp.JSONRPCResponse)
+		if !ok {
+			t.Fatalf("expected JSON-RPC response calling %s", name)
+		}
+		return resp.Result.(*mcp.CallToolResult)
+	}
+	call("list_projects", nil)
+	call("get_project", map[string]any{})
+
+	var stats usageStats
+	if err := json.Unmarshal([]byte(getResultText(call("stats", nil))), &stats); err != nil {
+		t.Fatalf("failed to parse stats: %v", err)
+	}
+	if len(stats.Tools) != 2 {
+		t.Fatalf("expected 2 tools used, stats itself not counted, got %+v", stats.Tools)
+	}
+	if got := stats.Tools[0]; got.Name != "get_project" || got.Errors != 1 {
+		t.Errorf("expected get_project to have failed once, got %+v", got)
+	}
+	if got := stats.Tools[1]; got.Name != "list_projects" || got.Errors != 0 {
+		t.Errorf("expected list_projects to have succeeded, got %+v", got)
+	}
+	if stats.API.Requests != 1 || stats.API.Errors != 0 {
+		t.Errorf("expected 1 API request, got %+v", stats.API)
+	}
+
+	rec := httptest.NewRecorder()
+	StatsHandler(s).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/stats", nil))
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/json" {
+		t.Fatalf("expected JSON 200 from /stats, got %d %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	var served usageStats
+	if err := json.Unmarshal(rec.Body.Bytes(), &served); err != nil || len(served.Tools) != 2 {
+		t.Errorf("expected /stats to serve the same stats, got %s", rec.Body.String())
+	}
+}
+
+func TestStatsHandler_Disabled(t *testing.T) {
+	cfg := reloadTestConfig()
+	cfg.DisabledTools = []string{"stats"}
+	s, _, _ := NewReloadableServer(cfg, "test")
+
+	rec := httptest.NewRecorder()
+	StatsHandler(s).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/stats", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected 404 with stats disabled, got %d", rec.Code)
+	}
+}
+
+func TestStats_HTTP(t *testing.T) {
+	cfg := reloadTestConfig()
+	cfg.TransportMode = config.TransportHTTP
+	s, _, _ := NewReloadableServer(cfg, "test")
+	tool := s.GetTool(statsToolInfo.Name)
+
+	req := mcp.CallToolRequest{}
+	req.Params.Name = statsToolInfo.Name
+	if result, _ := tool.Handler(context.Background(), req); !result.IsError || !strings.Contains(getResultText(result), "only available over stdio") {
+		t.Errorf("expected stats refused to http callers, got %s", getResultText(result))
+	}
+	if result, _ := tool.Handler(WithStdioTransport(context.Background()), req); result.IsError {
+		t.Errorf("expected stats over stdio alongside http, got %s", getResultText(result))
+	}
+
+	rec := httptest.NewRecorder()
+	StatsHandler(s).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/stats", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected /stats served to operators, got %d %s", rec.Code, rec.Body.String())
+	}
+}
@@ -0,0 +1,122 @@
+package hbmcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	hbapi "github.com/honeybadger-io/api-go"
+)
+
+// maxNotFoundFaultProjects bounds how many other projects are asked for a
+// fault that isn't in the project it was looked up in.
+const maxNotFoundFaultProjects = 25
+
+// accountProject is a project with the account it belongs to.
+type accountProject struct {
+	hbapi.Project
+	Account hbapi.Account
+}
+
+func isNotFound(err error) bool {
+	var apiErr *hbapi.APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// accountProjects lists the projects in each of the token's accounts,
+// leaving out those outside the project scope.
+func accountProjects(ctx context.Context, client *hbapi.Client) ([]hbapi.Account, []accountProject, error) {
+	accounts, err := client.Accounts.List(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	var projects []accountProject
+	for _, a := range accounts {
+		response, err := client.Projects.ListByAccountID(ctx, a.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, p := range response.Results {
+			if projectAllowed(ctx, p.ID) {
+				projects = append(projects, accountProject{Project: p, Account: a})
+			}
+		}
+	}
+	return accounts, projects, nil
+}
+
+// describeAccounts names accounts for a hint, e.g. `"Acme" (abc123)`.
+func describeAccounts(accounts []hbapi.Account) string {
+	names := make([]string, 0, len(accounts))
+	for _, a := range accounts {
+		names = append(names, fmt.Sprintf("%q (%s)", a.Name, a.ID))
+	}
+	return strings.Join(names, ", ")
+}
+
+// projectNotFoundHint looks for a project the API couldn't find across the
+// token's accounts and says where it is, or that it's in none of them, so
+// the agent can correct the ID instead of retrying it. It returns "" when
+// err isn't a 404 or the lookup itself fails.
+func projectNotFoundHint(ctx context.Context, client *hbapi.Client, err error, projectID int) string {
+	if !isNotFound(err) {
+		return ""
+	}
+	accounts, projects, lookupErr := accountProjects(ctx, client)
+	if lookupErr != nil {
+		return ""
+	}
+	for _, p := range projects {
+		if p.ID == projectID {
+			return fmt.Sprintf(" Project %d (%q) is in account %q (%s); the token may not have access to it through that account.", p.ID, p.Name, p.Account.Name, p.Account.ID)
+		}
+	}
+	return fmt.Sprintf(" Project %d isn't among the %d projects in the token's accounts (%s); use list_projects to find the right ID.", projectID, len(projects), describeAccounts(accounts))
+}
+
+// faultNotFoundHint looks for a fault the API couldn't find in the token's
+// other projects, since a fault ID paired with the wrong project_id is the
+// usual cause. Projects in the same account are asked first, up to
+// maxNotFoundFaultProjects in all. It returns "" when err isn't a 404 or
+// the lookup itself fails.
+func faultNotFoundHint(ctx context.Context, client *hbapi.Client, err error, projectID, faultID int) string {
+	if !isNotFound(err) {
+		return ""
+	}
+	accounts, projects, lookupErr := accountProjects(ctx, client)
+	if lookupErr != nil {
+		return ""
+	}
+
+	var account string
+	found := false
+	for _, p := range projects {
+		if p.ID == projectID {
+			account, found = p.Account.ID, true
+		}
+	}
+	if !found {
+		return fmt.Sprintf(" Project %d isn't among the %d projects in the token's accounts (%s); use list_projects to find the right project_id.", projectID, len(projects), describeAccounts(accounts))
+	}
+
+	var candidates []accountProject
+	for _, sameAccount := range []bool{true, false} {
+		for _, p := range projects {
+			if p.ID != projectID && (p.Account.ID == account) == sameAccount {
+				candidates = append(candidates, p)
+			}
+		}
+	}
+	checked := candidates[:min(len(candidates), maxNotFoundFaultProjects)]
+	for _, p := range checked {
+		if _, err := client.Faults.Get(ctx, p.ID, faultID); err == nil {
+			return fmt.Sprintf(" Fault %d is in project %d (%q) in account %q (%s); call again with project_id %d.", faultID, p.ID, p.Name, p.Account.Name, p.Account.ID, p.ID)
+		}
+	}
+	if len(checked) < len(candidates) {
+		return fmt.Sprintf(" Fault %d isn't in project %d or the %d other projects checked (of %d); check the fault ID, or use list_faults to find it.", faultID, projectID, len(checked), len(candidates))
+	}
+	return fmt.Sprintf(" Fault %d isn't in project %d or any other project in the token's accounts; check the fault ID.", faultID, projectID)
+}
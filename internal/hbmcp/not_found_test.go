@@ -0,0 +1,114 @@
+package hbmcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// notFoundAPI serves two accounts: project 1 in "Acme" and projects 2 and 3
+// in "Side Gig". Fault 10 is in project 3; every other project or fault is
+// a 404.
+func notFoundAPI(t *testing.T) (*hbapi.Client, *[]string) {
+	t.Helper()
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/v2/accounts":
+			_, _ = w.Write([]byte(`{"results": [{"id": "acme", "name": "Acme"}, {"id": "side", "name": "Side Gig"}]}`))
+		case r.URL.Path == "/v2/projects" && r.URL.Query().Get("account_id") == "acme":
+			_, _ = w.Write([]byte(`{"results": [{"id": 1, "name": "Storefront"}]}`))
+		case r.URL.Path == "/v2/projects" && r.URL.Query().Get("account_id") == "side":
+			_, _ = w.Write([]byte(`{"results": [{"id": 2, "name": "Blog"}, {"id": 3, "name": "Shop"}]}`))
+		case r.URL.Path == "/v2/projects/3/faults/10":
+			_, _ = w.Write([]byte(`{"id": 10, "project_id": 3, "klass": "RuntimeError"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors": "Not found"}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token"), &paths
+}
+
+func TestHandleGetFault_NotFoundLookup(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   map[string]any
+		lookup bool
+		want   string
+	}{
+		{"found in another project", map[string]any{"project_id": 1, "fault_id": 10}, true, `Fault 10 is in project 3 ("Shop") in account "Side Gig" (side); call again with project_id 3.`},
+		{"in no project", map[string]any{"project_id": 2, "fault_id": 11}, true, "Fault 11 isn't in project 2 or any other project in the token's accounts"},
+		{"unknown project", map[string]any{"project_id": 99, "fault_id": 10}, true, `Project 99 isn't among the 3 projects in the token's accounts ("Acme" (acme), "Side Gig" (side))`},
+		{"lookup off", map[string]any{"project_id": 1, "fault_id": 10}, false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, paths := notFoundAPI(t)
+			req := mcp.CallToolRequest{}
+			req.Params.Arguments = tt.args
+			result, err := handleGetFault(context.Background(), client, req, tt.lookup)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			text := getResultText(result)
+			if !result.IsError || !strings.HasPrefix(text, "Failed to get fault:") {
+				t.Fatalf("expected a not-found error, got %s", text)
+			}
+			if tt.want == "" {
+				if len(*paths) != 1 {
+					t.Errorf("expected no lookup, got requests %v", *paths)
+				}
+				return
+			}
+			if !strings.Contains(text, tt.want) {
+				t.Errorf("expected %q in %s", tt.want, text)
+			}
+		})
+	}
+}
+
+func TestHandleGetFault_NotFoundLookupChecksSameAccountFirst(t *testing.T) {
+	client, paths := notFoundAPI(t)
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"project_id": 2, "fault_id": 10}
+	result, _ := handleGetFault(context.Background(), client, req, true)
+	if !strings.Contains(getResultText(result), "call again with project_id 3") {
+		t.Fatalf("expected the fault to be found in project 3, got %s", getResultText(result))
+	}
+	// Project 3 shares project 2's account, so project 1 is never asked.
+	for _, p := range *paths {
+		if p == "/v2/projects/1/faults/10" {
+			t.Errorf("expected the same account's projects to be checked first, got requests %v", *paths)
+		}
+	}
+}
+
+func TestHandleGetProject_NotFoundLookup(t *testing.T) {
+	client, _ := notFoundAPI(t)
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"id": 42}
+	result, err := handleGetProject(context.Background(), client, req, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if text := getResultText(result); !result.IsError || !strings.Contains(text, "Project 42 isn't among the 3 projects in the token's accounts") || !strings.Contains(text, "use list_projects") {
+		t.Errorf("expected a hint naming the accounts, got %s", text)
+	}
+
+	// A project the listing finds but the API won't return is named with
+	// its account.
+	req.Params.Arguments = map[string]any{"id": 2}
+	result, _ = handleGetProject(context.Background(), client, req, true)
+	if text := getResultText(result); !strings.Contains(text, `Project 2 ("Blog") is in account "Side Gig" (side)`) {
+		t.Errorf("expected a hint naming the account, got %s", text)
+	}
+}
@@ -0,0 +1,92 @@
+package hbmcp
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"maps"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// maxPageLimit is the largest page size the Honeybadger API accepts for
+// paginated fault and notice listings.
+const maxPageLimit = 25
+
+// toolNotes is non-fatal context about a successful result: how time
+// arguments were interpreted, the token for the next page, and warnings
+// such as capped limits or truncated results that would otherwise only be
+// visible in server logs. It travels as a second content block so the
+// first keeps the API's shape.
+type toolNotes struct {
+	ResolvedTimeRange map[string]string `json:"resolved_time_range,omitempty"`
+	NextPageToken     string            `json:"next_page_token,omitempty"`
+	Warnings          []string          `json:"warnings,omitempty"`
+}
+
+func (n *toolNotes) warnf(format string, args ...any) {
+	n.Warnings = append(n.Warnings, fmt.Sprintf(format, args...))
+}
+
+func (n *toolNotes) addResolved(resolved ...map[string]string) {
+	for _, r := range resolved {
+		if len(r) == 0 {
+			continue
+		}
+		if n.ResolvedTimeRange == nil {
+			n.ResolvedTimeRange = map[string]string{}
+		}
+		maps.Copy(n.ResolvedTimeRange, r)
+	}
+}
+
+// limitArg reads the limit argument, capping it at maxPageLimit with a
+// warning instead of letting the API reject the request.
+func (n *toolNotes) limitArg(req mcp.CallToolRequest) int {
+	limit := req.GetInt("limit", 0)
+	if limit > maxPageLimit {
+		n.warnf("limit capped at %d (requested %d)", maxPageLimit, limit)
+		return maxPageLimit
+	}
+	return limit
+}
+
+// eachProject calls fn for each of the projects a tool fans out across,
+// skipping any it fails for with a warning, since one inaccessible project
+// shouldn't hide the rest. It returns the first error only when fn failed
+// for every project.
+func eachProject[P any](projects []P, project func(P) (int, string), notes *toolNotes, fn func(P) error) error {
+	var firstErr error
+	var succeeded int
+	for _, p := range projects {
+		if err := fn(p); err != nil {
+			id, name := project(p)
+			if name != "" {
+				notes.warnf("skipped project %d (%s): %v", id, name, err)
+			} else {
+				notes.warnf("skipped project %d: %v", id, err)
+			}
+			firstErr = cmp.Or(firstErr, err)
+			continue
+		}
+		succeeded++
+	}
+	if succeeded == 0 {
+		return firstErr
+	}
+	return nil
+}
+
+// withNotes appends n to a successful result. Error results and empty
+// notes are returned unchanged.
+func withNotes(result *mcp.CallToolResult, n *toolNotes) *mcp.CallToolResult {
+	if result.IsError || (len(n.ResolvedTimeRange) == 0 && n.NextPageToken == "" && len(n.Warnings) == 0) {
+		return result
+	}
+	jsonBytes, err := json.Marshal(n)
+	if err != nil {
+		return result
+	}
+	result.Content = append(result.Content, mcp.NewTextContent(string(jsonBytes)))
+	return result
+}
@@ -0,0 +1,195 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func getResultNotes(t *testing.T, result *mcp.CallToolResult) toolNotes {
+	t.Helper()
+	var notes toolNotes
+	if len(result.Content) < 2 {
+		return notes
+	}
+	text, ok := result.Content[1].(mcp.TextContent)
+	if !ok {
+		t.Fatalf("expected text notes block, got %T", result.Content[1])
+	}
+	if err := json.Unmarshal([]byte(text.Text), &notes); err != nil {
+		t.Fatalf("failed to unmarshal notes: %v", err)
+	}
+	return notes
+}
+
+func TestWithNotes(t *testing.T) {
+	result := withNotes(mcp.NewToolResultText("{}"), &toolNotes{})
+	if len(result.Content) != 1 {
+		t.Errorf("empty notes should not add a block, got %d blocks", len(result.Content))
+	}
+
+	var notes toolNotes
+	notes.warnf("something %s", "odd")
+	result = withNotes(mcp.NewToolResultError("boom"), &notes)
+	if len(result.Content) != 1 {
+		t.Errorf("notes should not be attached to errors, got %d blocks", len(result.Content))
+	}
+
+	result = withNotes(mcp.NewToolResultText("{}"), &notes)
+	if got := getResultNotes(t, result); len(got.Warnings) != 1 || got.Warnings[0] != "something odd" {
+		t.Errorf("unexpected warnings %v", got.Warnings)
+	}
+}
+
+func TestHandleListFaults_LimitCapped(t *testing.T) {
+	var limit string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limit = r.URL.Query().Get("limit")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"results": [], "links": {}}`))
+	}))
+	defer server.Close()
+
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Arguments: map[string]interface{}{"project_id": 1, "limit": 100},
+		},
+	}
+
+	result, err := handleListFaults(context.Background(), client, req)
+	if err != nil {
+		t.Fatalf("handleListFaults() error = %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got %s", getResultText(result))
+	}
+	if limit != "25" {
+		t.Errorf("expected limit=25 sent to the API, got %q", limit)
+	}
+	notes := getResultNotes(t, result)
+	if len(notes.Warnings) != 1 || !strings.Contains(notes.Warnings[0], "limit capped at 25") {
+		t.Errorf("expected limit warning, got %v", notes.Warnings)
+	}
+}
+
+func TestArgsCapped_Registered(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"results": [], "links": {}}`))
+	}))
+	defer server.Close()
+
+	cfg := reloadTestConfig()
+	cfg.APIURL = server.URL
+	s, _, _ := NewReloadableServer(cfg, "test")
+	ctx := s.WithContext(context.Background(), newTestSession(t, s))
+
+	// Argument validation runs before the handlers, so the tools mustn't
+	// declare a maximum that rejects what the handler would cap.
+	tests := []struct {
+		name string
+		args string
+		want string
+	}{
+		{"list_faults", `{"project_id": 1, "limit": 100}`, "limit capped at 25"},
+		{"list_fault_notices", `{"project_id": 1, "fault_id": 2, "limit": 100}`, "limit capped at 25"},
+		{"aggregate_fault_notices", `{"project_id": 1, "fault_id": 2, "key": "environment.hostname", "max_notices": 1000}`, "max_notices capped at 500"},
+		{"summarize_notice_patterns", `{"project_id": 1, "fault_id": 2, "max_notices": 1000}`, "max_notices capped at 500"},
+		{"get_fault_context_keys", `{"project_id": 1, "fault_id": 2, "sample_size": 1000}`, "sample_size capped at 100"},
+	}
+	for _, tt := range tests {
+		msg := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"` + tt.name + `","arguments":` + tt.args + `}}`
+		resp, _ := s.HandleMessage(ctx, []byte(msg)).(mcp.JSONRPCResponse)
+		result, ok := resp.Result.(*mcp.CallToolResult)
+		if !ok || result.IsError {
+			t.Fatalf("%s: expected success, got %v", tt.name, resp.Result)
+		}
+		if notes := getResultNotes(t, result); !slices.ContainsFunc(notes.Warnings, func(w string) bool { return strings.Contains(w, tt.want) }) {
+			t.Errorf("%s: expected %q warning, got %v", tt.name, tt.want, notes.Warnings)
+		}
+	}
+}
+
+func TestHandleQueryInsights_TruncationWarning(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"results": [{"count": 1}], "meta": {"rows": 1, "total_rows": 40}}`))
+	}))
+	defer server.Close()
+
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Arguments: map[string]interface{}{"project_id": 1, "query": "fields @ts"},
+		},
+	}
+
+	result, err := handleQueryInsights(context.Background(), client, req)
+	if err != nil {
+		t.Fatalf("handleQueryInsights() error = %v", err)
+	}
+	notes := getResultNotes(t, result)
+	if len(notes.Warnings) != 1 || !strings.Contains(notes.Warnings[0], "1 of 40 rows") {
+		t.Errorf("expected truncation warning, got %v", notes.Warnings)
+	}
+}
+
+func TestHandleListProjects_TruncationWarning(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"results": [{"id": 1, "name": "Web"}], "links": {"next": "/v2/projects?page=2"}}`))
+	}))
+	defer server.Close()
+
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+	result, err := handleListProjects(context.Background(), client, mcp.CallToolRequest{})
+	if err != nil {
+		t.Fatalf("handleListProjects() error = %v", err)
+	}
+	notes := getResultNotes(t, result)
+	if len(notes.Warnings) != 1 || !strings.Contains(notes.Warnings[0], "results truncated") {
+		t.Errorf("expected truncation warning, got %v", notes.Warnings)
+	}
+}
+
+func TestEachProject(t *testing.T) {
+	project := func(id int) (int, string) {
+		if id == 1 {
+			return id, "Shop"
+		}
+		return id, ""
+	}
+	failFor := func(failing ...int) func(int) error {
+		return func(id int) error {
+			if slices.Contains(failing, id) {
+				return fmt.Errorf("project %d is forbidden", id)
+			}
+			return nil
+		}
+	}
+
+	var notes toolNotes
+	if err := eachProject([]int{1, 2, 3}, project, &notes, failFor(1, 3)); err != nil {
+		t.Errorf("expected no error while one project succeeds, got %v", err)
+	}
+	if want := []string{"skipped project 1 (Shop): project 1 is forbidden", "skipped project 3: project 3 is forbidden"}; !slices.Equal(notes.Warnings, want) {
+		t.Errorf("expected warnings %v, got %v", want, notes.Warnings)
+	}
+
+	notes = toolNotes{}
+	if err := eachProject([]int{1, 2}, project, &notes, failFor(1, 2)); err == nil || err.Error() != "project 1 is forbidden" {
+		t.Errorf("expected the first error when every project fails, got %v", err)
+	}
+	if err := eachProject(nil, project, &notes, failFor()); err != nil {
+		t.Errorf("expected no error without projects, got %v", err)
+	}
+}
@@ -0,0 +1,240 @@
+package hbmcp
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	defaultAggregateNotices = 100
+	maxAggregateNotices     = 500
+	defaultAggregateTop     = 20
+)
+
+// noticeAggregate is the distribution of one key path's values across a
+// fault's notices. Values beyond the top N are folded into OtherCount so
+// the counts always add up to NoticesScanned.
+type noticeAggregate struct {
+	Key            string             `json:"key"`
+	NoticesScanned int                `json:"notices_scanned"`
+	Missing        int                `json:"missing"`
+	DistinctValues int                `json:"distinct_values"`
+	Values         []noticeValueCount `json:"values"`
+	OtherCount     int                `json:"other_count,omitempty"`
+	Oldest         *time.Time         `json:"oldest_notice_at,omitempty"`
+	Newest         *time.Time         `json:"newest_notice_at,omitempty"`
+	counts         map[string]int
+}
+
+type noticeValueCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// lookupKeyPath walks a dot-separated path such as "request.params.id"
+// through a notice decoded as generic JSON. Numeric segments index into
+// arrays, so "backtrace.0.file" works too.
+func lookupKeyPath(doc any, path string) (any, bool) {
+	cur := doc
+	for _, seg := range strings.Split(path, ".") {
+		switch v := cur.(type) {
+		case map[string]any:
+			next, ok := v[seg]
+			if !ok {
+				return nil, false
+			}
+			cur = next
+		case []any:
+			i, err := strconv.Atoi(seg)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, false
+			}
+			cur = v[i]
+		default:
+			return nil, false
+		}
+	}
+	return cur, cur != nil
+}
+
+// aggregateValue renders a value as a grouping key: strings as-is, and
+// everything else (numbers, booleans, objects) as compact JSON.
+func aggregateValue(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+func (a *noticeAggregate) add(n hbapi.Notice) error {
+	raw, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	var doc any
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return err
+	}
+
+	a.NoticesScanned++
+	if a.Oldest == nil || n.CreatedAt.Before(*a.Oldest) {
+		t := n.CreatedAt
+		a.Oldest = &t
+	}
+	if a.Newest == nil || n.CreatedAt.After(*a.Newest) {
+		t := n.CreatedAt
+		a.Newest = &t
+	}
+
+	v, ok := lookupKeyPath(doc, a.Key)
+	if !ok {
+		a.Missing++
+		return nil
+	}
+	a.counts[aggregateValue(v)]++
+	return nil
+}
+
+// finish sorts values by count (then value, for stable output) and keeps
+// the top n.
+func (a *noticeAggregate) finish(top int) {
+	a.DistinctValues = len(a.counts)
+	a.Values = make([]noticeValueCount, 0, len(a.counts))
+	for v, c := range a.counts {
+		a.Values = append(a.Values, noticeValueCount{Value: v, Count: c})
+	}
+	slices.SortFunc(a.Values, func(x, y noticeValueCount) int {
+		return cmp.Or(cmp.Compare(y.Count, x.Count), cmp.Compare(x.Value, y.Value))
+	})
+	if len(a.Values) > top {
+		for _, v := range a.Values[top:] {
+			a.OtherCount += v.Count
+		}
+		a.Values = a.Values[:top]
+	}
+}
+
+func handleAggregateFaultNotices(ctx context.Context, client *hbapi.Client, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	projectID := req.GetInt("project_id", 0)
+	if projectID == 0 {
+		return mcp.NewToolResultError("project_id is required"), nil
+	}
+
+	faultID := req.GetInt("fault_id", 0)
+	if faultID == 0 {
+		return mcp.NewToolResultError("fault_id is required"), nil
+	}
+
+	key := strings.Trim(strings.TrimSpace(req.GetString("key", "")), ".")
+	if key == "" {
+		return mcp.NewToolResultError("key is required"), nil
+	}
+
+	created, err := resolveTimeWindow(req, "created_after", "created_before", time.Now(), time.UTC)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	var notes toolNotes
+	notes.addResolved(created.Resolved)
+
+	maxNotices := req.GetInt("max_notices", defaultAggregateNotices)
+	if maxNotices > maxAggregateNotices {
+		notes.warnf("max_notices capped at %d (requested %d)", maxAggregateNotices, maxNotices)
+		maxNotices = maxAggregateNotices
+	}
+	top := req.GetInt("top", defaultAggregateTop)
+	if maxNotices < 1 || top < 1 {
+		return mcp.NewToolResultError("max_notices and top must be at least 1"), nil
+	}
+
+	agg := &noticeAggregate{Key: key, counts: map[string]int{}}
+	var readErr error
+	exhausted, err := walkFaultNotices(ctx, client, projectID, faultID, created, maxNotices, func(n hbapi.Notice) error {
+		if readErr = agg.add(n); readErr != nil {
+			readErr = fmt.Errorf("notice %s: %w", n.ID, readErr)
+		}
+		return readErr
+	})
+	if readErr != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to read %v", readErr)), nil
+	}
+	if err != nil {
+		if agg.NoticesScanned == 0 {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to list fault notices: %v", err)), nil
+		}
+		notes.warnf("stopped after %d notices: %v", agg.NoticesScanned, err)
+	}
+	if !exhausted {
+		notes.warnf("scanned the newest %d notices; more exist. Raise max_notices (up to %d) or narrow the time range", agg.NoticesScanned, maxAggregateNotices)
+	}
+	agg.finish(top)
+
+	// Return JSON response
+	jsonBytes, err := json.Marshal(agg)
+	if err != nil {
+		return mcp.NewToolResultError("Failed to marshal response"), nil
+	}
+
+	return withNotes(mcp.NewToolResultText(string(jsonBytes)), &notes), nil
+}
+
+// walkFaultNotices calls visit for up to maxNotices of a fault's notices
+// in created, newest first, paging as needed. It reports whether every
+// matching notice was visited. A visit error stops the walk and is
+// returned as is, as is an API error, possibly after some notices.
+func walkFaultNotices(ctx context.Context, client *hbapi.Client, projectID, faultID int, created timeWindow, maxNotices int, visit func(hbapi.Notice) error) (bool, error) {
+	seen := map[string]bool{}
+	before := created.Before
+	var oldest time.Time
+	for len(seen) < maxNotices {
+		page, err := client.Faults.ListNotices(ctx, projectID, faultID, hbapi.FaultListNoticesOptions{
+			CreatedAfter:  created.After,
+			CreatedBefore: before,
+			Limit:         maxPageLimit,
+		})
+		if err != nil {
+			return false, err
+		}
+
+		added := 0
+		for _, n := range page.Results {
+			if seen[n.ID] || len(seen) >= maxNotices {
+				continue
+			}
+			seen[n.ID] = true
+			if err := visit(n); err != nil {
+				return false, err
+			}
+			if oldest.IsZero() || n.CreatedAt.Before(oldest) {
+				oldest = n.CreatedAt
+			}
+			added++
+		}
+		if len(page.Results) < maxPageLimit || page.Links.Next == "" {
+			return true, nil
+		}
+		if added == 0 {
+			// A full page of notices already seen: more than a page share
+			// one second and the cursor can't get past them.
+			return false, nil
+		}
+		// created_before has one-second resolution, so step past the oldest
+		// notice's second and rely on seen to drop the overlap rather than
+		// skip notices that share it.
+		before = oldest.Truncate(time.Second).Add(time.Second)
+	}
+	return false, nil
+}
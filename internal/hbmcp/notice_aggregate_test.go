@@ -0,0 +1,173 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestLookupKeyPath(t *testing.T) {
+	var doc any
+	_ = json.Unmarshal([]byte(`{"request": {"params": {"id": 42}, "user": null}, "backtrace": [{"file": "app.rb"}]}`), &doc)
+
+	cases := []struct {
+		path   string
+		want   string
+		wantOK bool
+	}{
+		{"request.params.id", "42", true},
+		{"backtrace.0.file", "app.rb", true},
+		{"backtrace.1.file", "", false},
+		{"request.user", "", false},
+		{"request.params.missing", "", false},
+		{"request.params.id.deeper", "", false},
+	}
+	for _, c := range cases {
+		v, ok := lookupKeyPath(doc, c.path)
+		if ok != c.wantOK {
+			t.Errorf("lookupKeyPath(%q) ok = %v, want %v", c.path, ok, c.wantOK)
+			continue
+		}
+		if ok && aggregateValue(v) != c.want {
+			t.Errorf("lookupKeyPath(%q) = %q, want %q", c.path, aggregateValue(v), c.want)
+		}
+	}
+}
+
+// noticesServer serves total notices, newest first, one second apart, and
+// honors created_before and limit like the API.
+func noticesServer(t *testing.T, total int, hostname func(i int) string) *httptest.Server {
+	t.Helper()
+	newest := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var before time.Time
+		if raw := r.URL.Query().Get("created_before"); raw != "" {
+			var secs int64
+			_, _ = fmt.Sscan(raw, &secs)
+			before = time.Unix(secs, 0)
+		}
+		var results []hbapi.Notice
+		for i := 0; i < total && len(results) < maxPageLimit; i++ {
+			created := newest.Add(-time.Duration(i) * time.Second)
+			if !before.IsZero() && !created.Before(before) {
+				continue
+			}
+			results = append(results, hbapi.Notice{
+				ID:          fmt.Sprintf("n%d", i),
+				CreatedAt:   created,
+				Environment: hbapi.NoticeEnvironment{Hostname: hostname(i)},
+			})
+		}
+		resp := hbapi.FaultNoticesResponse{Results: results}
+		if len(results) == maxPageLimit {
+			resp.Links.Next = "/v2/projects/1/faults/2/notices?page=2"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+}
+
+func TestHandleAggregateFaultNotices(t *testing.T) {
+	server := noticesServer(t, 60, func(i int) string {
+		if i%3 == 0 {
+			return "web-1"
+		}
+		return "web-2"
+	})
+	defer server.Close()
+
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Arguments: map[string]interface{}{
+				"project_id": 1,
+				"fault_id":   2,
+				"key":        "environment.hostname",
+			},
+		},
+	}
+
+	result, err := handleAggregateFaultNotices(context.Background(), client, req)
+	if err != nil {
+		t.Fatalf("handleAggregateFaultNotices() error = %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected successful result, got error: %s", getResultText(result))
+	}
+
+	var agg noticeAggregate
+	if err := json.Unmarshal([]byte(getResultText(result)), &agg); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if agg.NoticesScanned != 60 {
+		t.Errorf("expected all 60 notices scanned across pages, got %d", agg.NoticesScanned)
+	}
+	want := []noticeValueCount{{Value: "web-2", Count: 40}, {Value: "web-1", Count: 20}}
+	if len(agg.Values) != 2 || agg.Values[0] != want[0] || agg.Values[1] != want[1] {
+		t.Errorf("expected %v, got %v", want, agg.Values)
+	}
+	if len(result.Content) != 1 {
+		t.Errorf("expected no notes when every notice was scanned, got %s", getResultNotes(t, result).Warnings)
+	}
+}
+
+func TestHandleAggregateFaultNotices_TopAndMaxNotices(t *testing.T) {
+	server := noticesServer(t, 60, func(i int) string { return fmt.Sprintf("web-%d", i%5) })
+	defer server.Close()
+
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Arguments: map[string]interface{}{
+				"project_id":  1,
+				"fault_id":    2,
+				"key":         "environment.hostname",
+				"max_notices": 30,
+				"top":         2,
+			},
+		},
+	}
+
+	result, err := handleAggregateFaultNotices(context.Background(), client, req)
+	if err != nil {
+		t.Fatalf("handleAggregateFaultNotices() error = %v", err)
+	}
+
+	var agg noticeAggregate
+	if err := json.Unmarshal([]byte(getResultText(result)), &agg); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if agg.NoticesScanned != 30 {
+		t.Errorf("expected 30 notices scanned, got %d", agg.NoticesScanned)
+	}
+	if agg.DistinctValues != 5 || len(agg.Values) != 2 || agg.OtherCount != 18 {
+		t.Errorf("expected top 2 of 5 values with 18 others, got %+v", agg)
+	}
+	notes := getResultNotes(t, result)
+	if len(notes.Warnings) != 1 || !strings.Contains(notes.Warnings[0], "more exist") {
+		t.Errorf("expected truncation warning, got %v", notes.Warnings)
+	}
+}
+
+func TestHandleAggregateFaultNotices_MissingKey(t *testing.T) {
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Arguments: map[string]interface{}{"project_id": 1, "fault_id": 2},
+		},
+	}
+	result, err := handleAggregateFaultNotices(context.Background(), hbapi.NewClient(), req)
+	if err != nil {
+		t.Fatalf("handleAggregateFaultNotices() error = %v", err)
+	}
+	if !result.IsError || !strings.Contains(getResultText(result), "key is required") {
+		t.Errorf("expected key is required error, got %q", getResultText(result))
+	}
+}
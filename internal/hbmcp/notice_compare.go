@@ -0,0 +1,283 @@
+package hbmcp
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// maxCompareNotices is how many of a fault's most recent notices are
+	// searched for the notice IDs compare_fault_notices is given.
+	maxCompareNotices = 500
+
+	// maxCompareValue is how long a compared value may be in the result.
+	maxCompareValue = 200
+
+	// compareFrames is how many frames of each backtrace are returned
+	// from the first difference on.
+	compareFrames = 5
+)
+
+// errNoticesFound stops the walk for compare_fault_notices once both
+// notices are found.
+var errNoticesFound = errors.New("notices found")
+
+// noticeComparison is the result of compare_fault_notices: what changed
+// from the base notice to the other one.
+type noticeComparison struct {
+	Base        noticeSummary       `json:"base"`
+	Notice      noticeSummary       `json:"notice"`
+	Params      []fieldChange       `json:"params"`
+	Context     []fieldChange       `json:"context"`
+	Environment []fieldChange       `json:"environment"`
+	Backtrace   backtraceComparison `json:"backtrace"`
+}
+
+type noticeSummary struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	Message   string    `json:"message"`
+}
+
+// fieldChange is a key path whose value differs between the notices.
+// Base is absent for an added key and Notice for a removed one.
+type fieldChange struct {
+	Path   string  `json:"path"`
+	Change string  `json:"change"`
+	Base   *string `json:"base,omitempty"`
+	Notice *string `json:"notice,omitempty"`
+}
+
+// backtraceComparison compares the notices' backtraces frame by frame
+// from the top. Frames are rendered as "file:line in method".
+type backtraceComparison struct {
+	Same            bool     `json:"same"`
+	BaseFrames      int      `json:"base_frames"`
+	NoticeFrames    int      `json:"notice_frames"`
+	FirstDifference *int     `json:"first_difference,omitempty"`
+	Base            []string `json:"base,omitempty"`
+	Notice          []string `json:"notice,omitempty"`
+}
+
+// noticeEnvironmentPaths are the parts of a notice compared as its
+// environment. Per-process details like the PID and load stats are left
+// out, since they differ between almost any two notices.
+var noticeEnvironmentPaths = []string{
+	"environment_name",
+	"environment.hostname",
+	"environment.revision",
+	"environment.project_root",
+	"url",
+	"request.component",
+	"request.action",
+	"web_environment",
+}
+
+func handleCompareFaultNotices(ctx context.Context, client *hbapi.Client, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	projectID := req.GetInt("project_id", 0)
+	if projectID == 0 {
+		return mcp.NewToolResultError("project_id is required"), nil
+	}
+
+	faultID := req.GetInt("fault_id", 0)
+	if faultID == 0 {
+		return mcp.NewToolResultError("fault_id is required"), nil
+	}
+
+	noticeID := req.GetString("notice_id", "")
+	baseID := req.GetString("base_notice_id", "")
+	baseWindow, err := resolveTimeWindow(req, "", "base_before", time.Now(), time.UTC)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if baseID != "" && !baseWindow.Before.IsZero() {
+		return mcp.NewToolResultError("Pass base_notice_id or base_before, not both"), nil
+	}
+	if baseID != "" && baseID == noticeID {
+		return mcp.NewToolResultError("base_notice_id and notice_id are the same notice"), nil
+	}
+
+	var notes toolNotes
+	notes.addResolved(baseWindow.Resolved)
+
+	// By default the base is the notice just before the compared one.
+	var notice, base *hbapi.Notice
+	previous := baseID == "" && baseWindow.Before.IsZero()
+	_, err = walkFaultNotices(ctx, client, projectID, faultID, timeWindow{}, maxCompareNotices, func(n hbapi.Notice) error {
+		switch {
+		case notice == nil && (noticeID == "" || n.ID == noticeID):
+			notice = &n
+		case previous && notice != nil && base == nil:
+			base = &n
+		case baseID != "" && n.ID == baseID:
+			base = &n
+		}
+		if notice != nil && (base != nil || !baseWindow.Before.IsZero()) {
+			return errNoticesFound
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errNoticesFound) {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to list fault notices: %v", err)), nil
+	}
+	if notice == nil {
+		if noticeID == "" {
+			return mcp.NewToolResultError("No notices found for this fault"), nil
+		}
+		return mcp.NewToolResultError(fmt.Sprintf("Notice %s not found among the fault's %d most recent notices", noticeID, maxCompareNotices)), nil
+	}
+
+	if !baseWindow.Before.IsZero() {
+		page, err := client.Faults.ListNotices(ctx, projectID, faultID, hbapi.FaultListNoticesOptions{CreatedBefore: baseWindow.Before, Limit: 1})
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to list fault notices: %v", err)), nil
+		}
+		if len(page.Results) == 0 {
+			return mcp.NewToolResultError("No notices of this fault were created before base_before"), nil
+		}
+		base = &page.Results[0]
+	}
+	if base == nil {
+		if previous {
+			return mcp.NewToolResultError(fmt.Sprintf("Notice %s is the fault's oldest notice, so there's nothing before it to compare with; pass base_notice_id", notice.ID)), nil
+		}
+		return mcp.NewToolResultError(fmt.Sprintf("Notice %s not found among the fault's %d most recent notices", baseID, maxCompareNotices)), nil
+	}
+	if base.ID == notice.ID {
+		return mcp.NewToolResultError("The base and compared notices are the same notice; pick an earlier base_before"), nil
+	}
+
+	result, err := compareNotices(*base, *notice)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to compare notices: %v", err)), nil
+	}
+
+	// Return JSON response
+	jsonBytes, err := json.Marshal(result)
+	if err != nil {
+		return mcp.NewToolResultError("Failed to marshal response"), nil
+	}
+
+	return withNotes(mcp.NewToolResultText(string(jsonBytes)), &notes), nil
+}
+
+// compareNotices diffs base against notice.
+func compareNotices(base, notice hbapi.Notice) (noticeComparison, error) {
+	result := noticeComparison{
+		Base:      noticeSummary{ID: base.ID, CreatedAt: base.CreatedAt, Message: base.Message},
+		Notice:    noticeSummary{ID: notice.ID, CreatedAt: notice.CreatedAt, Message: notice.Message},
+		Params:    diffFields(flattenNoticeMap("request.params", base.Request.Params), flattenNoticeMap("request.params", notice.Request.Params)),
+		Context:   diffFields(flattenNoticeMap("request.context", base.Request.Context), flattenNoticeMap("request.context", notice.Request.Context)),
+		Backtrace: compareBacktraces(base.Backtrace, notice.Backtrace),
+	}
+
+	baseEnv, err := noticeEnvironment(base)
+	if err != nil {
+		return result, err
+	}
+	noticeEnv, err := noticeEnvironment(notice)
+	if err != nil {
+		return result, err
+	}
+	result.Environment = diffFields(baseEnv, noticeEnv)
+	return result, nil
+}
+
+// flattenNoticeMap flattens obj into rendered values keyed by path.
+func flattenNoticeMap(prefix string, obj map[string]any) map[string]string {
+	values := map[string]string{}
+	flattenContextKeys(prefix, obj, 1, func(path string, v any) {
+		values[path] = aggregateValue(v)
+	})
+	return values
+}
+
+// noticeEnvironment flattens the noticeEnvironmentPaths of n.
+func noticeEnvironment(n hbapi.Notice) (map[string]string, error) {
+	data, err := json.Marshal(n)
+	if err != nil {
+		return nil, err
+	}
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	values := map[string]string{}
+	for _, path := range noticeEnvironmentPaths {
+		v, ok := lookupKeyPath(doc, path)
+		if !ok {
+			continue
+		}
+		if obj, isObj := v.(map[string]any); isObj {
+			for k, s := range flattenNoticeMap(path, obj) {
+				values[k] = s
+			}
+			continue
+		}
+		values[path] = aggregateValue(v)
+	}
+	return values, nil
+}
+
+// diffFields returns the paths added, removed, or changed from base to
+// notice, sorted by path.
+func diffFields(base, notice map[string]string) []fieldChange {
+	changes := []fieldChange{}
+	for path, b := range base {
+		n, ok := notice[path]
+		switch {
+		case !ok:
+			changes = append(changes, fieldChange{Path: path, Change: "removed", Base: compareValue(b)})
+		case n != b:
+			changes = append(changes, fieldChange{Path: path, Change: "changed", Base: compareValue(b), Notice: compareValue(n)})
+		}
+	}
+	for path, n := range notice {
+		if _, ok := base[path]; !ok {
+			changes = append(changes, fieldChange{Path: path, Change: "added", Notice: compareValue(n)})
+		}
+	}
+	slices.SortFunc(changes, func(a, b fieldChange) int {
+		return cmp.Compare(a.Path, b.Path)
+	})
+	return changes
+}
+
+func compareValue(s string) *string {
+	s = truncateLabel(s, maxCompareValue)
+	return &s
+}
+
+// compareBacktraces compares two backtraces from the top frame down.
+func compareBacktraces(base, notice []hbapi.BacktraceEntry) backtraceComparison {
+	result := backtraceComparison{BaseFrames: len(base), NoticeFrames: len(notice)}
+	b, n := renderFrames(base), renderFrames(notice)
+	first := 0
+	for first < len(b) && first < len(n) && b[first] == n[first] {
+		first++
+	}
+	if first == len(b) && first == len(n) {
+		result.Same = true
+		return result
+	}
+	result.FirstDifference = &first
+	result.Base = b[first:min(first+compareFrames, len(b))]
+	result.Notice = n[first:min(first+compareFrames, len(n))]
+	return result
+}
+
+func renderFrames(frames []hbapi.BacktraceEntry) []string {
+	rendered := make([]string, len(frames))
+	for i, f := range frames {
+		rendered[i] = fmt.Sprintf("%s:%d in %s", f.File, f.Number, f.Method)
+	}
+	return rendered
+}
@@ -0,0 +1,148 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const compareNoticesBody = `{"results": [
+	{"id": "n3", "created_at": "2024-03-02T10:00:00Z", "message": "undefined method 'name' for nil", "environment_name": "production",
+	 "environment": {"hostname": "web-2", "revision": "def456", "pid": 12},
+	 "request": {"action": "show", "component": "users", "params": {"id": "7", "format": "json"}, "context": {"user_id": 7, "plan": {"tier": "pro"}}},
+	 "backtrace": [{"file": "[PROJECT_ROOT]/app/models/user.rb", "number": "12", "method": "display_name"}, {"file": "[PROJECT_ROOT]/app/controllers/users_controller.rb", "number": "8", "method": "show"}]},
+	{"id": "n2", "created_at": "2024-03-01T10:00:00Z", "message": "undefined method 'name' for nil", "environment_name": "production",
+	 "environment": {"hostname": "web-1", "revision": "abc123", "pid": 11},
+	 "request": {"action": "show", "component": "users", "params": {"id": "5", "debug": "1"}, "context": {"user_id": 5, "plan": {"tier": "pro"}}},
+	 "backtrace": [{"file": "[PROJECT_ROOT]/app/views/users/show.html.erb", "number": "3", "method": "render"}, {"file": "[PROJECT_ROOT]/app/controllers/users_controller.rb", "number": "8", "method": "show"}]},
+	{"id": "n1", "created_at": "2024-02-20T10:00:00Z", "message": "undefined method 'name' for nil", "environment_name": "production",
+	 "environment": {"hostname": "web-2", "revision": "def456", "pid": 10},
+	 "request": {"action": "show", "component": "users", "params": {"id": "7", "format": "json"}, "context": {"user_id": 7, "plan": {"tier": "pro"}}},
+	 "backtrace": [{"file": "[PROJECT_ROOT]/app/models/user.rb", "number": "12", "method": "display_name"}, {"file": "[PROJECT_ROOT]/app/controllers/users_controller.rb", "number": "8", "method": "show"}]}
+], "links": {}}`
+
+func newCompareNoticesServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/projects/1/faults/2/notices" {
+			t.Errorf("unexpected request %s", r.URL)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if before := r.URL.Query().Get("created_before"); before != "" {
+			if before != "1708819200" {
+				t.Errorf("unexpected created_before %q", before)
+			}
+			_, _ = w.Write([]byte(`{"results": [{"id": "n1", "created_at": "2024-02-20T10:00:00Z", "environment": {"hostname": "web-2"}}], "links": {}}`))
+			return
+		}
+		_, _ = w.Write([]byte(compareNoticesBody))
+	}))
+}
+
+func TestHandleCompareFaultNotices(t *testing.T) {
+	server := newCompareNoticesServer(t)
+	defer server.Close()
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"project_id": float64(1), "fault_id": float64(2)}
+	result, err := handleCompareFaultNotices(context.Background(), client, req)
+	if err != nil || result.IsError {
+		t.Fatalf("unexpected error: %v %s", err, getResultText(result))
+	}
+	var got noticeComparison
+	if err := json.Unmarshal([]byte(getResultText(result)), &got); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if got.Base.ID != "n2" || got.Notice.ID != "n3" {
+		t.Errorf("expected the latest notice compared with the one before, got %s -> %s", got.Base.ID, got.Notice.ID)
+	}
+
+	render := func(changes []fieldChange) string {
+		var parts []string
+		for _, c := range changes {
+			s := c.Change + " " + c.Path
+			if c.Base != nil {
+				s += " " + *c.Base
+			}
+			if c.Notice != nil {
+				s += " " + *c.Notice
+			}
+			parts = append(parts, s)
+		}
+		return strings.Join(parts, "; ")
+	}
+	if want := "removed request.params.debug 1; added request.params.format json; changed request.params.id 5 7"; render(got.Params) != want {
+		t.Errorf("params: got %q, want %q", render(got.Params), want)
+	}
+	if want := "changed request.context.user_id 5 7"; render(got.Context) != want {
+		t.Errorf("context: got %q, want %q", render(got.Context), want)
+	}
+	if want := "changed environment.hostname web-1 web-2; changed environment.revision abc123 def456"; render(got.Environment) != want {
+		t.Errorf("environment: got %q, want %q", render(got.Environment), want)
+	}
+
+	bt := got.Backtrace
+	if bt.Same || bt.FirstDifference == nil || *bt.FirstDifference != 0 {
+		t.Fatalf("expected the backtraces to differ at the top frame, got %+v", bt)
+	}
+	if len(bt.Base) != 2 || bt.Base[0] != "[PROJECT_ROOT]/app/views/users/show.html.erb:3 in render" {
+		t.Errorf("unexpected base frames %v", bt.Base)
+	}
+}
+
+func TestHandleCompareFaultNotices_Base(t *testing.T) {
+	server := newCompareNoticesServer(t)
+	defer server.Close()
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+
+	tests := []struct {
+		name      string
+		args      map[string]any
+		wantBase  string
+		wantError string
+	}{
+		{"base_notice_id", map[string]any{"base_notice_id": "n1"}, "n1", ""},
+		{"base_before", map[string]any{"base_before": "2024-02-25T00:00:00Z"}, "n1", ""},
+		{"oldest", map[string]any{"notice_id": "n1"}, "", "nothing before it"},
+		{"unknown notice", map[string]any{"notice_id": "nope"}, "", "Notice nope not found"},
+		{"both bases", map[string]any{"base_notice_id": "n1", "base_before": "2024-02-25T00:00:00Z"}, "", "not both"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := mcp.CallToolRequest{}
+			req.Params.Arguments = map[string]any{"project_id": float64(1), "fault_id": float64(2)}
+			for k, v := range tt.args {
+				req.Params.Arguments.(map[string]any)[k] = v
+			}
+			result, _ := handleCompareFaultNotices(context.Background(), client, req)
+			if tt.wantError != "" {
+				if !result.IsError || !strings.Contains(getResultText(result), tt.wantError) {
+					t.Errorf("expected an error containing %q, got %q", tt.wantError, getResultText(result))
+				}
+				return
+			}
+			var got noticeComparison
+			if err := json.Unmarshal([]byte(getResultText(result)), &got); err != nil {
+				t.Fatalf("failed to parse response %q: %v", getResultText(result), err)
+			}
+			if got.Base.ID != tt.wantBase || got.Notice.ID != "n3" {
+				t.Errorf("expected %s -> n3, got %s -> %s", tt.wantBase, got.Base.ID, got.Notice.ID)
+			}
+		})
+	}
+}
+
+func TestCompareBacktraces_Same(t *testing.T) {
+	frames := []hbapi.BacktraceEntry{{File: "a.rb", Number: 1, Method: "x"}}
+	if got := compareBacktraces(frames, frames); !got.Same || got.FirstDifference != nil {
+		t.Errorf("expected identical backtraces to be the same, got %+v", got)
+	}
+}
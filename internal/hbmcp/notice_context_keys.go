@@ -0,0 +1,151 @@
+package hbmcp
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	defaultContextKeySample = 25
+	maxContextKeySample     = 100
+
+	// maxContextKeyExamples is how many distinct example values each key
+	// reports, and maxContextKeyExample how long each may be.
+	maxContextKeyExamples = 3
+	maxContextKeyExample  = 100
+
+	// maxContextKeyDepth stops nested objects from being flattened
+	// further; deeper values are reported as objects.
+	maxContextKeyDepth = 4
+)
+
+// contextKeySections are the parts of a notice's request whose keys
+// get_fault_context_keys reports, in the order it reports them.
+var contextKeySections = []string{"context", "params", "session"}
+
+// contextKey is one key path found in a fault's notices.
+type contextKey struct {
+	Path      string   `json:"path"`
+	Section   string   `json:"section"`
+	Count     int      `json:"count"`
+	Frequency float64  `json:"frequency"`
+	Types     []string `json:"types"`
+	Examples  []string `json:"examples"`
+
+	section int
+	types   map[string]bool
+}
+
+type contextKeys struct {
+	NoticesSampled int          `json:"notices_sampled"`
+	Keys           []contextKey `json:"keys"`
+}
+
+func handleGetFaultContextKeys(ctx context.Context, client *hbapi.Client, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	projectID := req.GetInt("project_id", 0)
+	if projectID == 0 {
+		return mcp.NewToolResultError("project_id is required"), nil
+	}
+
+	faultID := req.GetInt("fault_id", 0)
+	if faultID == 0 {
+		return mcp.NewToolResultError("fault_id is required"), nil
+	}
+
+	var notes toolNotes
+	sample := req.GetInt("sample_size", defaultContextKeySample)
+	if sample > maxContextKeySample {
+		notes.warnf("sample_size capped at %d (requested %d)", maxContextKeySample, sample)
+		sample = maxContextKeySample
+	}
+	if sample < 1 {
+		return mcp.NewToolResultError("sample_size must be at least 1"), nil
+	}
+
+	result := contextKeys{Keys: []contextKey{}}
+	keys := map[string]*contextKey{}
+	_, err := walkFaultNotices(ctx, client, projectID, faultID, timeWindow{}, sample, func(n hbapi.Notice) error {
+		result.NoticesSampled++
+		for i, section := range []map[string]any{n.Request.Context, n.Request.Params, n.Request.Session} {
+			name := contextKeySections[i]
+			flattenContextKeys("request."+name, section, 1, func(path string, v any) {
+				k, ok := keys[path]
+				if !ok {
+					k = &contextKey{Path: path, Section: name, Examples: []string{}, section: i, types: map[string]bool{}}
+					keys[path] = k
+				}
+				k.Count++
+				k.types[jsonType(v)] = true
+				if example := truncateLabel(aggregateValue(v), maxContextKeyExample); v != nil && len(k.Examples) < maxContextKeyExamples && !slices.Contains(k.Examples, example) {
+					k.Examples = append(k.Examples, example)
+				}
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		if result.NoticesSampled == 0 {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to list fault notices: %v", err)), nil
+		}
+		notes.warnf("stopped after %d notices: %v", result.NoticesSampled, err)
+	}
+
+	for _, k := range keys {
+		k.Frequency = math.Round(float64(k.Count)/float64(result.NoticesSampled)*100) / 100
+		for t := range k.types {
+			k.Types = append(k.Types, t)
+		}
+		slices.Sort(k.Types)
+		result.Keys = append(result.Keys, *k)
+	}
+	slices.SortFunc(result.Keys, func(a, b contextKey) int {
+		return cmp.Or(cmp.Compare(a.section, b.section), cmp.Compare(b.Count, a.Count), cmp.Compare(a.Path, b.Path))
+	})
+
+	// Return JSON response
+	jsonBytes, err := json.Marshal(result)
+	if err != nil {
+		return mcp.NewToolResultError("Failed to marshal response"), nil
+	}
+
+	return withNotes(mcp.NewToolResultText(string(jsonBytes)), &notes), nil
+}
+
+// flattenContextKeys calls visit with the dot-separated path of every
+// value in obj, descending into nested objects up to maxContextKeyDepth.
+// Arrays are values; their elements aren't keys.
+func flattenContextKeys(prefix string, obj map[string]any, depth int, visit func(path string, v any)) {
+	for k, v := range obj {
+		path := prefix + "." + k
+		if nested, ok := v.(map[string]any); ok && len(nested) > 0 && depth < maxContextKeyDepth {
+			flattenContextKeys(path, nested, depth+1, visit)
+			continue
+		}
+		visit(path, v)
+	}
+}
+
+// jsonType names the JSON type of a decoded value.
+func jsonType(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case float64, json.Number:
+		return "number"
+	case bool:
+		return "boolean"
+	case []any:
+		return "array"
+	default:
+		return "object"
+	}
+}
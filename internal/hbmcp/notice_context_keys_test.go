@@ -0,0 +1,108 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestHandleGetFaultContextKeys(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/projects/1/faults/2/notices" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"results": [
+			{"id": "a", "created_at": "2024-03-15T14:00:03Z", "request": {
+				"context": {"user_id": 42, "account": {"plan": "pro", "trial": false}},
+				"params": {"id": "7", "tags": ["x", "y"]},
+				"session": {"token": "[FILTERED]"}
+			}},
+			{"id": "b", "created_at": "2024-03-15T14:00:02Z", "request": {
+				"context": {"user_id": "guest", "account": {}},
+				"params": {"id": "7"}
+			}},
+			{"id": "c", "created_at": "2024-03-15T14:00:01Z", "request": {
+				"context": {"user_id": 9},
+				"params": {"id": "8", "note": null}
+			}}
+		], "links": {}}`))
+	}))
+	defer server.Close()
+
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"project_id": float64(1), "fault_id": float64(2)}
+
+	result, err := handleGetFaultContextKeys(context.Background(), client, req)
+	if err != nil || result.IsError {
+		t.Fatalf("unexpected error: %v %s", err, getResultText(result))
+	}
+	var response contextKeys
+	if err := json.Unmarshal([]byte(getResultText(result)), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if response.NoticesSampled != 3 {
+		t.Errorf("expected 3 notices sampled, got %d", response.NoticesSampled)
+	}
+
+	var paths []string
+	byPath := map[string]contextKey{}
+	for _, k := range response.Keys {
+		paths = append(paths, k.Path)
+		byPath[k.Path] = k
+	}
+	want := "request.context.user_id,request.context.account,request.context.account.plan,request.context.account.trial," +
+		"request.params.id,request.params.note,request.params.tags,request.session.token"
+	if got := strings.Join(paths, ","); got != want {
+		t.Errorf("expected keys by section, then count, then path:\n got %s\nwant %s", got, want)
+	}
+
+	if got := byPath["request.context.user_id"]; got.Count != 3 || got.Frequency != 1 || strings.Join(got.Types, ",") != "number,string" || strings.Join(got.Examples, ",") != "42,guest,9" {
+		t.Errorf("unexpected user_id key: %+v", got)
+	}
+	if got := byPath["request.params.id"]; strings.Join(got.Examples, ",") != "7,8" || got.Section != "params" {
+		t.Errorf("expected distinct examples, got %+v", got)
+	}
+	if got := byPath["request.context.account.plan"]; got.Count != 1 || got.Frequency != 0.33 {
+		t.Errorf("expected nested keys flattened, got %+v", got)
+	}
+	if got := byPath["request.context.account"]; got.Count != 1 || got.Types[0] != "object" {
+		t.Errorf("expected an empty object to be a value, got %+v", got)
+	}
+	if got := byPath["request.params.note"]; got.Types[0] != "null" || len(got.Examples) != 0 {
+		t.Errorf("expected null without examples, got %+v", got)
+	}
+	if got := byPath["request.params.tags"]; got.Types[0] != "array" || got.Examples[0] != `["x","y"]` {
+		t.Errorf("expected arrays as values, got %+v", got)
+	}
+}
+
+func TestHandleGetFaultContextKeys_Validation(t *testing.T) {
+	client := hbapi.NewClient()
+	tests := []struct {
+		name string
+		args map[string]any
+		want string
+	}{
+		{"missing project_id", map[string]any{"fault_id": float64(2)}, "project_id is required"},
+		{"missing fault_id", map[string]any{"project_id": float64(1)}, "fault_id is required"},
+		{"bad sample_size", map[string]any{"project_id": float64(1), "fault_id": float64(2), "sample_size": float64(0)}, "sample_size must be at least 1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := mcp.CallToolRequest{}
+			req.Params.Arguments = tt.args
+			result, _ := handleGetFaultContextKeys(context.Background(), client, req)
+			if !result.IsError || !strings.Contains(getResultText(result), tt.want) {
+				t.Errorf("expected error containing %q, got %q", tt.want, getResultText(result))
+			}
+		})
+	}
+}
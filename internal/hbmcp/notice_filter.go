@@ -0,0 +1,139 @@
+package hbmcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// noticeFilter trims notices before they're returned. Full notices, with
+// every backtrace frame, cookie, and web environment variable, are what
+// most often blow out an agent's context.
+type noticeFilter struct {
+	appTraceOnly bool
+	maxFrames    int
+	exclude      []string
+}
+
+// noticeFilterArgs reads the app_trace_only, max_frames, and
+// exclude_fields arguments.
+func noticeFilterArgs(req mcp.CallToolRequest) (noticeFilter, error) {
+	f := noticeFilter{
+		appTraceOnly: req.GetBool("app_trace_only", false),
+		maxFrames:    req.GetInt("max_frames", 0),
+	}
+	if _, ok := req.GetArguments()["max_frames"]; ok && f.maxFrames < 1 {
+		return f, fmt.Errorf("max_frames must be at least 1")
+	}
+	for _, path := range req.GetStringSlice("exclude_fields", nil) {
+		if path = strings.Trim(strings.TrimSpace(path), "."); path != "" {
+			f.exclude = append(f.exclude, path)
+		}
+	}
+	return f, nil
+}
+
+func (f noticeFilter) active() bool {
+	return f.appTraceOnly || f.maxFrames > 0 || len(f.exclude) > 0
+}
+
+// apply filters notices, returning them as hbapi.Notice values, or as
+// generic JSON objects when fields were excluded.
+func (f noticeFilter) apply(notices []hbapi.Notice, notes *toolNotes) ([]any, error) {
+	truncated, unmarked := 0, 0
+	for i := range notices {
+		n := &notices[i]
+		if f.appTraceOnly {
+			app := make([]hbapi.BacktraceEntry, 0, len(n.Backtrace))
+			for _, frame := range n.Backtrace {
+				if frame.Context == "app" {
+					app = append(app, frame)
+				}
+			}
+			if len(app) == 0 {
+				app = n.ApplicationTrace
+			}
+			if len(app) > 0 || len(n.Backtrace) == 0 {
+				n.Backtrace = app
+			} else {
+				unmarked++
+			}
+			// The backtrace now holds just these frames.
+			n.ApplicationTrace = nil
+		}
+		if f.maxFrames > 0 {
+			if len(n.Backtrace) > f.maxFrames {
+				n.Backtrace = n.Backtrace[:f.maxFrames]
+				truncated++
+			}
+			if len(n.ApplicationTrace) > f.maxFrames {
+				n.ApplicationTrace = n.ApplicationTrace[:f.maxFrames]
+			}
+		}
+	}
+	if unmarked > 0 {
+		notes.warnf("%d notices have no frames marked as application code; their full backtraces are kept", unmarked)
+	}
+	if truncated > 0 {
+		notes.warnf("backtraces of %d notices cut to their first %d frames", truncated, f.maxFrames)
+	}
+
+	results := make([]any, len(notices))
+	for i, n := range notices {
+		results[i] = n
+	}
+	if len(f.exclude) == 0 {
+		return results, nil
+	}
+
+	matched := map[string]bool{}
+	for i, n := range notices {
+		raw, err := json.Marshal(n)
+		if err != nil {
+			return nil, err
+		}
+		var doc any
+		if err := json.Unmarshal(raw, &doc); err != nil {
+			return nil, err
+		}
+		for _, path := range f.exclude {
+			if deleteKeyPath(doc, path) {
+				matched[path] = true
+			}
+		}
+		results[i] = doc
+	}
+	for _, path := range f.exclude {
+		if !matched[path] && len(notices) > 0 {
+			notes.warnf("exclude_fields: no notice has %s", path)
+		}
+	}
+	return results, nil
+}
+
+// deleteKeyPath removes the value at a dot-separated path, as read by
+// lookupKeyPath, from the object holding it. It reports whether there
+// was one.
+func deleteKeyPath(doc any, path string) bool {
+	parent := doc
+	key := path
+	if i := strings.LastIndex(path, "."); i >= 0 {
+		var ok bool
+		if parent, ok = lookupKeyPath(doc, path[:i]); !ok {
+			return false
+		}
+		key = path[i+1:]
+	}
+	obj, ok := parent.(map[string]any)
+	if !ok {
+		return false
+	}
+	if _, ok := obj[key]; !ok {
+		return false
+	}
+	delete(obj, key)
+	return true
+}
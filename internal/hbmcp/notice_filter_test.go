@@ -0,0 +1,161 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const filterTestNotices = `{"results": [
+	{
+		"id": "n1",
+		"cookies": {"session": "secret"},
+		"web_environment": {"HTTP_USER_AGENT": "curl"},
+		"request": {"session": {"user_id": 1}, "params": {"id": "7"}},
+		"backtrace": [
+			{"number": "10", "file": "[PROJECT_ROOT]/app/models/order.rb", "method": "total", "context": "app"},
+			{"number": "20", "file": "[GEM_ROOT]/activerecord/base.rb", "method": "save", "context": "all"},
+			{"number": "30", "file": "[PROJECT_ROOT]/app/controllers/orders_controller.rb", "method": "create", "context": "app"},
+			{"number": "40", "file": "[GEM_ROOT]/rack/handler.rb", "method": "call", "context": "all"}
+		],
+		"application_trace": [
+			{"number": "10", "file": "[PROJECT_ROOT]/app/models/order.rb", "method": "total", "context": "app"},
+			{"number": "30", "file": "[PROJECT_ROOT]/app/controllers/orders_controller.rb", "method": "create", "context": "app"}
+		]
+	},
+	{
+		"id": "n2",
+		"backtrace": [
+			{"number": "1", "file": "lib/a.js", "method": "a"},
+			{"number": "2", "file": "lib/b.js", "method": "b"}
+		]
+	}
+], "links": {}}`
+
+func listFilteredNotices(t *testing.T, args map[string]interface{}) *mcp.CallToolResult {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(filterTestNotices))
+	}))
+	t.Cleanup(server.Close)
+
+	client := hbapi.NewClient().
+		WithBaseURL(server.URL).
+		WithAuthToken("test-token")
+	args["project_id"] = 123
+	args["fault_id"] = 456
+
+	result, err := handleListFaultNotices(context.Background(), client, mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: args}})
+	if err != nil {
+		t.Fatalf("handleListFaultNotices() error = %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got %s", getResultText(result))
+	}
+	return result
+}
+
+func TestHandleListFaultNotices_AppTraceOnly(t *testing.T) {
+	result := listFilteredNotices(t, map[string]interface{}{"app_trace_only": true, "max_frames": 1})
+
+	var response hbapi.ListResponse[hbapi.Notice]
+	if err := json.Unmarshal([]byte(getResultText(result)), &response); err != nil {
+		t.Fatalf("failed to parse notices: %v", err)
+	}
+	first := response.Results[0]
+	if len(first.Backtrace) != 1 || first.Backtrace[0].Method != "total" {
+		t.Errorf("expected only the first app frame, got %+v", first.Backtrace)
+	}
+	if first.ApplicationTrace != nil {
+		t.Errorf("expected application_trace to be dropped, got %+v", first.ApplicationTrace)
+	}
+	if len(response.Results[1].Backtrace) != 1 {
+		t.Errorf("expected the unmarked backtrace cut to 1 frame, got %+v", response.Results[1].Backtrace)
+	}
+
+	warnings := strings.Join(getResultNotes(t, result).Warnings, "\n")
+	for _, want := range []string{"1 notices have no frames marked as application code", "backtraces of 2 notices cut to their first 1 frames"} {
+		if !strings.Contains(warnings, want) {
+			t.Errorf("expected warning %q, got %q", want, warnings)
+		}
+	}
+}
+
+func TestHandleListFaultNotices_ExcludeFields(t *testing.T) {
+	result := listFilteredNotices(t, map[string]interface{}{
+		"exclude_fields": []interface{}{"cookies", "web_environment", "request.session", "request.nope"},
+		"latest":         true,
+	})
+
+	text := getResultText(result)
+	var notice map[string]any
+	if err := json.Unmarshal([]byte(text), &notice); err != nil {
+		t.Fatalf("expected a single notice object: %v", err)
+	}
+	for _, gone := range []string{"cookies", "web_environment"} {
+		if _, ok := notice[gone]; ok {
+			t.Errorf("expected %s to be excluded", gone)
+		}
+	}
+	request := notice["request"].(map[string]any)
+	if _, ok := request["session"]; ok {
+		t.Error("expected request.session to be excluded")
+	}
+	if _, ok := request["params"]; !ok {
+		t.Error("expected request.params to be kept")
+	}
+	if notice["id"] != "n1" {
+		t.Errorf("expected the first notice, got %v", notice["id"])
+	}
+
+	warnings := strings.Join(getResultNotes(t, result).Warnings, "\n")
+	if !strings.Contains(warnings, "no notice has request.nope") {
+		t.Errorf("expected a warning for the unmatched path, got %q", warnings)
+	}
+}
+
+func TestHandleListFaultNotices_InvalidMaxFrames(t *testing.T) {
+	req := mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"project_id": 123,
+		"fault_id":   456,
+		"max_frames": 0,
+	}}}
+	result, err := handleListFaultNotices(context.Background(), nil, req)
+	if err != nil {
+		t.Fatalf("handleListFaultNotices() error = %v", err)
+	}
+	if !result.IsError || !strings.Contains(getResultText(result), "max_frames must be at least 1") {
+		t.Errorf("expected max_frames error, got %q", getResultText(result))
+	}
+}
+
+func TestDeleteKeyPath(t *testing.T) {
+	var doc any
+	_ = json.Unmarshal([]byte(`{"a": {"b": {"c": 1}}, "list": [{"x": 1}], "s": "v"}`), &doc)
+
+	for _, tt := range []struct {
+		path string
+		want bool
+	}{
+		{"a.b.c", true},
+		{"a.b.c", false},
+		{"list.0.x", true},
+		{"s.inner", false},
+		{"missing", false},
+		{"s", true},
+	} {
+		if got := deleteKeyPath(doc, tt.path); got != tt.want {
+			t.Errorf("deleteKeyPath(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+	if b, _ := json.Marshal(doc); string(b) != `{"a":{"b":{}},"list":[{}]}` {
+		t.Errorf("doc after deletes = %s", b)
+	}
+}
@@ -0,0 +1,88 @@
+package hbmcp
+
+import (
+	"context"
+	"time"
+
+	hbapi "github.com/honeybadger-io/api-go"
+)
+
+const (
+	// firstNoticeGrowth is how much each step widens the window searched
+	// for a fault's first notice: from one second past the fault's creation
+	// to years past it in eight steps.
+	firstNoticeGrowth = 16
+
+	// firstNoticePages caps the pages read back through a window that holds
+	// more notices than a page.
+	firstNoticePages = 10
+)
+
+// firstFaultNotice returns the earliest notice of a fault still kept, or
+// nil when there are none. The API lists notices newest first, so it
+// searches a window starting at the fault's creation (or created's lower
+// bound), widening it until the window holds a notice, then reads back to
+// the oldest one.
+func firstFaultNotice(ctx context.Context, client *hbapi.Client, projectID, faultID int, created timeWindow, now time.Time, notes *toolNotes) (*hbapi.Notice, error) {
+	from, to := created.After, created.Before
+	after := from
+	var firstSeen time.Time
+	if from.IsZero() {
+		fault, err := client.Faults.Get(ctx, projectID, faultID)
+		if err != nil {
+			return nil, err
+		}
+		from, firstSeen = fault.CreatedAt, fault.CreatedAt
+		// The API compares whole seconds, so the window starts a second
+		// early to take in a notice from the same second as the fault.
+		after = from.Add(-time.Second)
+	}
+	if to.IsZero() {
+		to = now
+	}
+
+	for span := time.Second; ; span *= firstNoticeGrowth {
+		before := from.Add(span)
+		last := !before.Before(to)
+		if last {
+			before = to.Add(time.Second)
+		}
+		page, err := client.Faults.ListNotices(ctx, projectID, faultID, hbapi.FaultListNoticesOptions{
+			CreatedAfter:  after,
+			CreatedBefore: before,
+			Limit:         maxPageLimit,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(page.Results) == 0 {
+			if last {
+				return nil, nil
+			}
+			continue
+		}
+
+		oldest := page.Results[len(page.Results)-1]
+		for pages := 1; len(page.Results) == maxPageLimit; pages++ {
+			if pages == firstNoticePages {
+				notes.warnf("stopped after %d pages of notices; the fault may have earlier notices than the one returned", pages)
+				break
+			}
+			page, err = client.Faults.ListNotices(ctx, projectID, faultID, hbapi.FaultListNoticesOptions{
+				CreatedAfter:  after,
+				CreatedBefore: oldest.CreatedAt,
+				Limit:         maxPageLimit,
+			})
+			if err != nil {
+				return nil, err
+			}
+			if len(page.Results) > 0 {
+				oldest = page.Results[len(page.Results)-1]
+			}
+		}
+		if !firstSeen.IsZero() && oldest.CreatedAt.Sub(firstSeen) > time.Minute {
+			notes.warnf("the fault was first seen at %s, but its earliest notice still kept is from %s; older notices are past the project's retention", firstSeen.UTC().Format(time.RFC3339), oldest.CreatedAt.UTC().Format(time.RFC3339))
+		}
+		return &oldest, nil
+	}
+}
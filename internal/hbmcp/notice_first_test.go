@@ -0,0 +1,64 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestHandleListFaultNotices_First(t *testing.T) {
+	created := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	// Notices an hour, two hours, and a day after the fault was created;
+	// the one at creation is past retention.
+	kept := []time.Time{created.Add(24 * time.Hour), created.Add(2 * time.Hour), created.Add(time.Hour)}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/v2/projects/1/faults/2" {
+			fmt.Fprintf(w, `{"id": 2, "created_at": %q}`, created.Format(time.RFC3339))
+			return
+		}
+		after, _ := strconv.ParseInt(r.URL.Query().Get("created_after"), 10, 64)
+		before, _ := strconv.ParseInt(r.URL.Query().Get("created_before"), 10, 64)
+		var results []string
+		for i, at := range kept {
+			if at.Unix() > after && at.Unix() < before {
+				results = append(results, fmt.Sprintf(`{"id": "n%d", "created_at": %q}`, i, at.Format(time.RFC3339)))
+			}
+		}
+		fmt.Fprintf(w, `{"results": [%s], "links": {}}`, strings.Join(results, ","))
+	}))
+	defer server.Close()
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"project_id": float64(1), "fault_id": float64(2), "first": true}
+	result, err := handleListFaultNotices(context.Background(), client, req)
+	if err != nil || result.IsError {
+		t.Fatalf("unexpected error: %v %s", err, getResultText(result))
+	}
+	var got hbapi.Notice
+	if err := json.Unmarshal([]byte(getResultText(result)), &got); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if got.ID != "n2" {
+		t.Errorf("expected the earliest kept notice n2, got %s", got.ID)
+	}
+	if notes := result.Content[len(result.Content)-1].(mcp.TextContent).Text; !strings.Contains(notes, "past the project's retention") {
+		t.Errorf("expected a retention warning, got %s", notes)
+	}
+
+	req.Params.Arguments = map[string]any{"project_id": float64(1), "fault_id": float64(2), "first": true, "latest": true}
+	result, err = handleListFaultNotices(context.Background(), client, req)
+	if err != nil || !result.IsError {
+		t.Errorf("expected first and latest refused together, got %s", getResultText(result))
+	}
+}
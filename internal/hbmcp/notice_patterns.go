@@ -0,0 +1,255 @@
+package hbmcp
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	defaultPatternTop = 10
+
+	// maxPatternParams bounds the params reported, most common first, so
+	// a fault whose notices each carry different params doesn't flood the
+	// result.
+	maxPatternParams = 50
+
+	// maxPatternValue is how long a counted value may be before it is
+	// truncated, so long values don't dominate the result.
+	maxPatternValue = 200
+
+	// patternTraceFrames is how many frames of the most common application
+	// trace are returned.
+	patternTraceFrames = 10
+)
+
+// idSegment matches URL path segments that are identifiers rather than
+// routes: numbers, UUIDs, and long hex strings.
+var idSegment = regexp.MustCompile(`^(\d+|[0-9a-fA-F]{8}(-[0-9a-fA-F]{4}){3}-[0-9a-fA-F]{12}|[0-9a-fA-F]{16,})$`)
+
+// noticePatterns summarizes what a fault's notices have in common: the
+// routes, hosts, and clients they come from, how varied their params are,
+// and whether they fail along the same application trace.
+type noticePatterns struct {
+	NoticesScanned   int                   `json:"notices_scanned"`
+	Oldest           *time.Time            `json:"oldest_notice_at,omitempty"`
+	Newest           *time.Time            `json:"newest_notice_at,omitempty"`
+	URLPatterns      *noticeAggregate      `json:"url_patterns"`
+	Hostnames        *noticeAggregate      `json:"hostnames"`
+	UserAgents       *noticeAggregate      `json:"user_agents"`
+	Params           []paramCardinality    `json:"params"`
+	ApplicationTrace applicationTraceShare `json:"application_trace"`
+	params           map[string]*paramCount
+	traces           map[string]int
+	traceFrames      map[string][]string
+}
+
+// paramCardinality is how varied one request param is across the notices
+// that carry it: a param with one distinct value is likely the trigger, one
+// with as many values as notices likely isn't.
+type paramCardinality struct {
+	Name           string `json:"name"`
+	Seen           int    `json:"seen"`
+	DistinctValues int    `json:"distinct_values"`
+	TopValue       string `json:"top_value"`
+	TopCount       int    `json:"top_count"`
+}
+
+type paramCount struct {
+	seen   int
+	values map[string]int
+}
+
+// applicationTraceShare is how many notices fail along the most common
+// application trace.
+type applicationTraceShare struct {
+	NoticesWithTrace int      `json:"notices_with_trace"`
+	DistinctTraces   int      `json:"distinct_traces"`
+	TopSharePercent  float64  `json:"top_share_percent"`
+	TopTrace         []string `json:"top_trace,omitempty"`
+}
+
+func newNoticePatterns() *noticePatterns {
+	return &noticePatterns{
+		URLPatterns: &noticeAggregate{Key: "url", counts: map[string]int{}},
+		Hostnames:   &noticeAggregate{Key: "environment.hostname", counts: map[string]int{}},
+		UserAgents:  &noticeAggregate{Key: "web_environment.HTTP_USER_AGENT", counts: map[string]int{}},
+		params:      map[string]*paramCount{},
+		traces:      map[string]int{},
+		traceFrames: map[string][]string{},
+	}
+}
+
+func (p *noticePatterns) add(n hbapi.Notice) {
+	p.NoticesScanned++
+	if p.Oldest == nil || n.CreatedAt.Before(*p.Oldest) {
+		t := n.CreatedAt
+		p.Oldest = &t
+	}
+	if p.Newest == nil || n.CreatedAt.After(*p.Newest) {
+		t := n.CreatedAt
+		p.Newest = &t
+	}
+
+	rawURL := n.URL
+	if rawURL == "" && n.Request.URL != nil {
+		rawURL = *n.Request.URL
+	}
+	countPattern(p.URLPatterns, urlPattern(rawURL))
+	countPattern(p.Hostnames, n.Environment.Hostname)
+	agent, _ := n.WebEnvironment["HTTP_USER_AGENT"].(string)
+	countPattern(p.UserAgents, agent)
+
+	flattenContextKeys("request.params", n.Request.Params, 1, func(path string, v any) {
+		param, ok := p.params[path]
+		if !ok {
+			param = &paramCount{values: map[string]int{}}
+			p.params[path] = param
+		}
+		param.seen++
+		param.values[truncateLabel(aggregateValue(v), maxPatternValue)]++
+	})
+
+	if len(n.ApplicationTrace) > 0 {
+		frames := renderFrames(n.ApplicationTrace)
+		key := strings.Join(frames, "\n")
+		p.traces[key]++
+		if _, ok := p.traceFrames[key]; !ok {
+			p.traceFrames[key] = frames[:min(patternTraceFrames, len(frames))]
+		}
+	}
+}
+
+// countPattern counts value in agg, or counts it missing when empty.
+func countPattern(agg *noticeAggregate, value string) {
+	agg.NoticesScanned++
+	if value == "" {
+		agg.Missing++
+		return
+	}
+	agg.counts[truncateLabel(value, maxPatternValue)]++
+}
+
+// urlPattern reduces a URL to its path with identifier segments replaced
+// by :id, so /orders/123 and /orders/456 count as one route.
+func urlPattern(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	path := raw
+	if u, err := url.Parse(raw); err == nil && (u.Path != "" || u.Host != "") {
+		path = u.Path
+	}
+	if path == "" {
+		path = "/"
+	}
+	segments := strings.Split(path, "/")
+	for i, s := range segments {
+		if idSegment.MatchString(s) {
+			segments[i] = ":id"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// finish ranks each breakdown, keeping the top n values, and works out the
+// params' cardinality and the application trace share.
+func (p *noticePatterns) finish(top int) {
+	p.URLPatterns.finish(top)
+	p.Hostnames.finish(top)
+	p.UserAgents.finish(top)
+
+	p.Params = make([]paramCardinality, 0, len(p.params))
+	for name, param := range p.params {
+		c := paramCardinality{Name: name, Seen: param.seen, DistinctValues: len(param.values)}
+		for v, n := range param.values {
+			if n > c.TopCount || (n == c.TopCount && v < c.TopValue) {
+				c.TopValue, c.TopCount = v, n
+			}
+		}
+		p.Params = append(p.Params, c)
+	}
+	slices.SortFunc(p.Params, func(a, b paramCardinality) int {
+		return cmp.Or(cmp.Compare(b.Seen, a.Seen), cmp.Compare(a.Name, b.Name))
+	})
+
+	topTrace := ""
+	for key, n := range p.traces {
+		p.ApplicationTrace.NoticesWithTrace += n
+		if n > p.traces[topTrace] || (n == p.traces[topTrace] && key < topTrace) {
+			topTrace = key
+		}
+	}
+	p.ApplicationTrace.DistinctTraces = len(p.traces)
+	if topTrace != "" && p.NoticesScanned > 0 {
+		p.ApplicationTrace.TopSharePercent = math.Round(float64(p.traces[topTrace])*1000/float64(p.NoticesScanned)) / 10
+		p.ApplicationTrace.TopTrace = p.traceFrames[topTrace]
+	}
+}
+
+func handleSummarizeNoticePatterns(ctx context.Context, client *hbapi.Client, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	projectID := req.GetInt("project_id", 0)
+	if projectID == 0 {
+		return mcp.NewToolResultError("project_id is required"), nil
+	}
+
+	faultID := req.GetInt("fault_id", 0)
+	if faultID == 0 {
+		return mcp.NewToolResultError("fault_id is required"), nil
+	}
+
+	created, err := resolveTimeWindow(req, "created_after", "created_before", time.Now(), time.UTC)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	var notes toolNotes
+	notes.addResolved(created.Resolved)
+
+	maxNotices := req.GetInt("max_notices", defaultAggregateNotices)
+	if maxNotices > maxAggregateNotices {
+		notes.warnf("max_notices capped at %d (requested %d)", maxAggregateNotices, maxNotices)
+		maxNotices = maxAggregateNotices
+	}
+	top := req.GetInt("top", defaultPatternTop)
+	if maxNotices < 1 || top < 1 {
+		return mcp.NewToolResultError("max_notices and top must be at least 1"), nil
+	}
+
+	patterns := newNoticePatterns()
+	exhausted, err := walkFaultNotices(ctx, client, projectID, faultID, created, maxNotices, func(n hbapi.Notice) error {
+		patterns.add(n)
+		return nil
+	})
+	if err != nil {
+		if patterns.NoticesScanned == 0 {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to list fault notices: %v", err)), nil
+		}
+		notes.warnf("stopped after %d notices: %v", patterns.NoticesScanned, err)
+	}
+	if !exhausted {
+		notes.warnf("scanned the newest %d notices; more exist. Raise max_notices (up to %d) or narrow the time range", patterns.NoticesScanned, maxAggregateNotices)
+	}
+	patterns.finish(top)
+	if len(patterns.Params) > maxPatternParams {
+		notes.warnf("listed the %d most common of %d params", maxPatternParams, len(patterns.Params))
+		patterns.Params = patterns.Params[:maxPatternParams]
+	}
+
+	// Return JSON response
+	jsonBytes, err := json.Marshal(patterns)
+	if err != nil {
+		return mcp.NewToolResultError("Failed to marshal response"), nil
+	}
+
+	return withNotes(mcp.NewToolResultText(string(jsonBytes)), &notes), nil
+}
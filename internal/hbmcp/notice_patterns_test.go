@@ -0,0 +1,96 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestURLPattern(t *testing.T) {
+	cases := map[string]string{
+		"https://shop.example.com/orders/123/items?page=2":                    "/orders/:id/items",
+		"/users/0b9f6a3e-8d4c-4f0e-9a57-2c1d7e6b5f40":                         "/users/:id",
+		"https://example.com/carts/5f4dcc3b5aa765d61d8327deb882cf99/checkout": "/carts/:id/checkout",
+		"https://example.com":      "/",
+		"/v2/reports/2024-summary": "/v2/reports/2024-summary",
+		"":                         "",
+	}
+	for raw, want := range cases {
+		if got := urlPattern(raw); got != want {
+			t.Errorf("urlPattern(%q) = %q, want %q", raw, got, want)
+		}
+	}
+}
+
+func TestHandleSummarizeNoticePatterns(t *testing.T) {
+	trace := []hbapi.BacktraceEntry{{File: "app/models/order.rb", Number: 12, Method: "total"}}
+	other := []hbapi.BacktraceEntry{{File: "app/jobs/sync.rb", Number: 3, Method: "perform"}}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var results []hbapi.Notice
+		for i := 0; i < 4; i++ {
+			n := hbapi.Notice{
+				ID:               fmt.Sprintf("n%d", i),
+				CreatedAt:        time.Date(2024, 1, 2, 0, 0, i, 0, time.UTC),
+				URL:              fmt.Sprintf("https://shop.example.com/orders/%d", 100+i),
+				Environment:      hbapi.NoticeEnvironment{Hostname: "web-1"},
+				WebEnvironment:   map[string]any{"HTTP_USER_AGENT": "curl/8.0"},
+				Request:          hbapi.NoticeRequest{Params: map[string]any{"id": 100 + i, "currency": "EUR"}},
+				ApplicationTrace: trace,
+			}
+			if i == 3 {
+				n.URL = ""
+				n.Environment.Hostname = "worker-1"
+				n.WebEnvironment = nil
+				n.Request.Params = nil
+				n.ApplicationTrace = other
+			}
+			results = append(results, n)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(hbapi.FaultNoticesResponse{Results: results})
+	}))
+	defer server.Close()
+
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"project_id": 1, "fault_id": 2}
+	result, err := handleSummarizeNoticePatterns(context.Background(), client, req)
+	if err != nil || result.IsError {
+		t.Fatalf("unexpected error: %v %s", err, getResultText(result))
+	}
+
+	var got noticePatterns
+	if err := json.Unmarshal([]byte(getResultText(result)), &got); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if got.NoticesScanned != 4 {
+		t.Errorf("expected 4 notices scanned, got %d", got.NoticesScanned)
+	}
+	if len(got.URLPatterns.Values) != 1 || got.URLPatterns.Values[0] != (noticeValueCount{Value: "/orders/:id", Count: 3}) || got.URLPatterns.Missing != 1 {
+		t.Errorf("unexpected URL patterns %+v", got.URLPatterns)
+	}
+	if got.Hostnames.DistinctValues != 2 || got.Hostnames.Values[0] != (noticeValueCount{Value: "web-1", Count: 3}) {
+		t.Errorf("unexpected hostnames %+v", got.Hostnames)
+	}
+	if got.UserAgents.Values[0] != (noticeValueCount{Value: "curl/8.0", Count: 3}) || got.UserAgents.Missing != 1 {
+		t.Errorf("unexpected user agents %+v", got.UserAgents)
+	}
+	want := []paramCardinality{
+		{Name: "request.params.currency", Seen: 3, DistinctValues: 1, TopValue: "EUR", TopCount: 3},
+		{Name: "request.params.id", Seen: 3, DistinctValues: 3, TopValue: "100", TopCount: 1},
+	}
+	if len(got.Params) != 2 || got.Params[0] != want[0] || got.Params[1] != want[1] {
+		t.Errorf("expected params %+v, got %+v", want, got.Params)
+	}
+	share := got.ApplicationTrace
+	if share.NoticesWithTrace != 4 || share.DistinctTraces != 2 || share.TopSharePercent != 75 || len(share.TopTrace) != 1 || share.TopTrace[0] != "app/models/order.rb:12 in total" {
+		t.Errorf("unexpected application trace share %+v", share)
+	}
+}
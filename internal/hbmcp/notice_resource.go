@@ -0,0 +1,236 @@
+package hbmcp
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/honeybadger-io/honeybadger-mcp-server/internal/config"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// noticeResourceTemplate addresses one notice's full JSON. The API has no
+// endpoint for a single notice, so created_at, in unix seconds, lets a
+// read find it with one list call around that second instead of paging
+// through the fault's history; the URI carries everything a read needs,
+// so it works on any instance of a stateless http deployment.
+const noticeResourceTemplate = "honeybadger://projects/{project_id}/faults/{fault_id}/notices/{notice_id}{?created_at}"
+
+// defaultMaxNoticeBytes is the size above which list_fault_notices trims a
+// notice unless max_notice_bytes says otherwise.
+const defaultMaxNoticeBytes = 16384
+
+// trimmedFrames is how many backtrace frames a trimmed notice keeps.
+const trimmedFrames = 10
+
+// keptNoticeFields are never trimmed: they identify the notice and say
+// what went wrong, which is what a trimmed notice is still read for.
+var keptNoticeFields = map[string]bool{
+	"id": true, "created_at": true, "fault_id": true, "message": true, "url": true, "environment_name": true,
+}
+
+func noticeResourceURI(projectID, faultID int, noticeID string, createdAt time.Time) string {
+	return fmt.Sprintf("honeybadger://projects/%d/faults/%d/notices/%s?created_at=%d",
+		projectID, faultID, url.PathEscape(noticeID), createdAt.Unix())
+}
+
+// RegisterNoticeResources serves the notices list_fault_notices trims in
+// full, at the URIs it links them by.
+func RegisterNoticeResources(s *server.MCPServer, clientFor ClientFactory, current func() *config.Config) {
+	s.AddResourceTemplate(
+		mcp.NewResourceTemplate(noticeResourceTemplate, "Fault notice",
+			mcp.WithTemplateDescription("The full JSON of one notice, as linked from list_fault_notices when it trims a large notice"),
+			mcp.WithTemplateMIMEType("application/json"),
+		),
+		func(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			return readNoticeResource(ctx, clientFor(ctx), current(), req)
+		},
+	)
+}
+
+func readNoticeResource(ctx context.Context, client *hbapi.Client, cfg *config.Config, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	arg := func(name string) string {
+		switch v := req.Params.Arguments[name].(type) {
+		case string:
+			return v
+		case []string:
+			if len(v) > 0 {
+				return v[0]
+			}
+		}
+		return ""
+	}
+	projectID, err := strconv.Atoi(arg("project_id"))
+	if err != nil || projectID < 1 {
+		return nil, fmt.Errorf("invalid project_id in %s", req.Params.URI)
+	}
+	faultID, err := strconv.Atoi(arg("fault_id"))
+	if err != nil || faultID < 1 {
+		return nil, fmt.Errorf("invalid fault_id in %s", req.Params.URI)
+	}
+	noticeID := arg("notice_id")
+	if noticeID == "" {
+		return nil, fmt.Errorf("missing notice_id in %s", req.Params.URI)
+	}
+	createdAt, err := strconv.ParseInt(arg("created_at"), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("missing or invalid created_at in %s", req.Params.URI)
+	}
+	if !cfg.ProjectAllowed(projectID) {
+		return nil, fmt.Errorf("%s", projectOutOfScope(projectID))
+	}
+
+	at := time.Unix(createdAt, 0)
+	response, err := client.Faults.ListNotices(ctx, projectID, faultID, hbapi.FaultListNoticesOptions{
+		CreatedAfter:  at.Add(-time.Second),
+		CreatedBefore: at.Add(time.Second),
+		Limit:         maxPageLimit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notice: %w", err)
+	}
+	for _, n := range response.Results {
+		if n.ID != noticeID {
+			continue
+		}
+		jsonBytes, err := json.Marshal(n)
+		if err != nil {
+			return nil, err
+		}
+		return []mcp.ResourceContents{mcp.TextResourceContents{
+			URI:      req.Params.URI,
+			MIMEType: "application/json",
+			Text:     string(jsonBytes),
+		}}, nil
+	}
+	return nil, fmt.Errorf("notice %s not found on fault %d at %s", noticeID, faultID, at.UTC().Format(time.RFC3339))
+}
+
+// trimLargeNotices trims each notice in payload whose JSON is over
+// maxBytes, replacing its largest fields with a note of their size and
+// cutting its backtraces to their first frames until it fits, and adds a
+// resource_uri where the full notice can be read. payload is a single
+// notice or an object whose results hold them. It returns the payload and
+// a link to each trimmed notice.
+func trimLargeNotices(projectID, faultID int, payload any, maxBytes int, notes *toolNotes) (any, []mcp.Content, error) {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return nil, nil, err
+	}
+	if maxBytes <= 0 || len(raw) <= maxBytes {
+		return payload, nil, nil
+	}
+	var doc any
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, nil, err
+	}
+	notices := []any{doc}
+	if obj, ok := doc.(map[string]any); ok {
+		if results, ok := obj["results"].([]any); ok {
+			notices = results
+		}
+	}
+
+	var links []mcp.Content
+	for _, v := range notices {
+		notice, ok := v.(map[string]any)
+		if !ok {
+			continue
+		}
+		trimmed, err := trimNotice(notice, maxBytes)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(trimmed) == 0 {
+			continue
+		}
+		id, _ := notice["id"].(string)
+		createdAt, _ := time.Parse(time.RFC3339, fmt.Sprint(notice["created_at"]))
+		uri := noticeResourceURI(projectID, faultID, id, createdAt)
+		notice["trimmed_fields"] = trimmed
+		notice["resource_uri"] = uri
+		links = append(links, mcp.NewResourceLink(uri, "Notice "+id, "The full JSON of notice "+id, "application/json"))
+	}
+	if len(links) > 0 {
+		notes.warnf("%d notices over %d bytes were trimmed; read each one's resource_uri for the full notice, or pass max_notice_bytes 0 to get them inline", len(links), maxBytes)
+	}
+	return doc, links, nil
+}
+
+// trimNotice trims notice in place until its JSON fits in maxBytes, or
+// nothing is left to trim, and returns the paths it trimmed.
+func trimNotice(notice map[string]any, maxBytes int) ([]string, error) {
+	size := func(v any) (int, error) {
+		b, err := json.Marshal(v)
+		return len(b), err
+	}
+	total, err := size(notice)
+	if err != nil || total <= maxBytes {
+		return nil, err
+	}
+
+	var trimmed []string
+	for _, key := range []string{"backtrace", "application_trace"} {
+		if frames, ok := notice[key].([]any); ok && len(frames) > trimmedFrames {
+			notice[key] = frames[:trimmedFrames]
+			trimmed = append(trimmed, fmt.Sprintf("%s (first %d of %d frames kept)", key, trimmedFrames, len(frames)))
+		}
+	}
+
+	// Candidates are the top-level fields and the request's, so a huge
+	// params or session goes before the rest of the request does.
+	type field struct {
+		parent map[string]any
+		key    string
+		path   string
+		size   int
+	}
+	var fields []field
+	add := func(parent map[string]any, key, path string) error {
+		n, err := size(parent[key])
+		if err != nil {
+			return err
+		}
+		fields = append(fields, field{parent, key, path, n})
+		return nil
+	}
+	for key, v := range notice {
+		if keptNoticeFields[key] || key == "backtrace" || key == "application_trace" {
+			continue
+		}
+		if request, ok := v.(map[string]any); ok && key == "request" {
+			for k := range request {
+				if err := add(request, k, "request."+k); err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+		if err := add(notice, key, key); err != nil {
+			return nil, err
+		}
+	}
+	slices.SortFunc(fields, func(a, b field) int {
+		return cmp.Or(cmp.Compare(b.size, a.size), cmp.Compare(a.path, b.path))
+	})
+
+	for _, f := range fields {
+		if total, err = size(notice); err != nil || total <= maxBytes {
+			break
+		}
+		placeholder := fmt.Sprintf("[trimmed: %d bytes]", f.size)
+		if f.size <= len(placeholder)+2 {
+			break
+		}
+		f.parent[f.key] = placeholder
+		trimmed = append(trimmed, f.path)
+	}
+	return trimmed, err
+}
@@ -0,0 +1,107 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/honeybadger-io/honeybadger-mcp-server/internal/config"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestHandleListFaultNotices_TrimsLargeNotices(t *testing.T) {
+	params := fmt.Sprintf(`{"blob": %q}`, strings.Repeat("x", 20000))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"results": [
+			{"id": "big", "created_at": "2024-01-01T12:00:00Z", "message": "boom", "request": {"params": %s, "url": "/orders"}},
+			{"id": "small", "created_at": "2024-01-01T11:00:00Z", "message": "boom", "request": {"params": {"id": 1}}}
+		], "links": {}}`, params)
+	}))
+	defer server.Close()
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"project_id": float64(1), "fault_id": float64(2), "max_notice_bytes": float64(4096)}
+	result, err := handleListFaultNotices(context.Background(), client, req)
+	if err != nil || result.IsError {
+		t.Fatalf("unexpected error: %v %s", err, getResultText(result))
+	}
+	var got struct {
+		Results []map[string]any `json:"results"`
+	}
+	if err := json.Unmarshal([]byte(getResultText(result)), &got); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	big, small := got.Results[0], got.Results[1]
+	wantURI := "honeybadger://projects/1/faults/2/notices/big?created_at=1704110400"
+	if big["resource_uri"] != wantURI || big["message"] != "boom" {
+		t.Errorf("expected the big notice trimmed and linked, got %v", big)
+	}
+	if request := big["request"].(map[string]any); !strings.HasPrefix(fmt.Sprint(request["params"]), "[trimmed: ") || request["url"] != "/orders" {
+		t.Errorf("expected only request.params trimmed, got %v", request)
+	}
+	if _, ok := small["resource_uri"]; ok {
+		t.Errorf("expected the small notice inline, got %v", small)
+	}
+	link, ok := result.Content[1].(mcp.ResourceLink)
+	if !ok || link.URI != wantURI {
+		t.Errorf("expected a link to the full notice, got %#v", result.Content[1])
+	}
+	if notes := result.Content[len(result.Content)-1].(mcp.TextContent).Text; !strings.Contains(notes, "1 notices over 4096 bytes were trimmed") {
+		t.Errorf("expected a warning about the trimmed notice, got %s", notes)
+	}
+
+	req.Params.Arguments = map[string]any{"project_id": float64(1), "fault_id": float64(2), "max_notice_bytes": float64(0)}
+	result, err = handleListFaultNotices(context.Background(), client, req)
+	if err != nil || result.IsError || strings.Contains(getResultText(result), "resource_uri") {
+		t.Errorf("expected nothing trimmed with max_notice_bytes 0, got %v %s", err, getResultText(result))
+	}
+}
+
+func TestReadNoticeResource(t *testing.T) {
+	at := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.URL.Path != "/v2/projects/1/faults/2/notices" || q.Get("created_after") != fmt.Sprint(at.Unix()-1) || q.Get("created_before") != fmt.Sprint(at.Unix()+1) {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"results": [{"id": "other"}, {"id": "big", "message": "boom"}], "links": {}}`))
+	}))
+	defer api.Close()
+
+	s := server.NewMCPServer("test", "1.0.0")
+	RegisterNoticeResources(s, func(context.Context) *hbapi.Client {
+		return hbapi.NewClient().WithBaseURL(api.URL).WithAuthToken("test-token")
+	}, staticConfig(&config.Config{DeniedProjectIDs: []int{3}}))
+
+	read := func(uri string) mcp.JSONRPCMessage {
+		return s.HandleMessage(context.Background(), []byte(fmt.Sprintf(`{"jsonrpc":"2.0","id":1,"method":"resources/read","params":{"uri":%q}}`, uri)))
+	}
+	resp, ok := read(noticeResourceURI(1, 2, "big", at)).(mcp.JSONRPCResponse)
+	if !ok {
+		t.Fatalf("expected a result, got %#v", read(noticeResourceURI(1, 2, "big", at)))
+	}
+	contents := resp.Result.(mcp.ReadResourceResult).Contents
+	if text := contents[0].(mcp.TextResourceContents).Text; !strings.Contains(text, `"id":"big"`) {
+		t.Errorf("expected the notice's JSON, got %s", text)
+	}
+
+	for _, uri := range []string{
+		noticeResourceURI(1, 2, "missing", at),
+		noticeResourceURI(3, 2, "big", at),
+		"honeybadger://projects/1/faults/2/notices/big",
+	} {
+		if _, ok := read(uri).(mcp.JSONRPCError); !ok {
+			t.Errorf("expected an error reading %s", uri)
+		}
+	}
+}
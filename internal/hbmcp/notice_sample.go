@@ -0,0 +1,93 @@
+package hbmcp
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	hbapi "github.com/honeybadger-io/api-go"
+)
+
+const (
+	// maxNoticeSample caps list_fault_notices' sample, which takes one API
+	// call per notice.
+	maxNoticeSample = maxPageLimit
+
+	// sampleConcurrency bounds the notice lookups a sample runs at once.
+	sampleConcurrency = 5
+)
+
+// sampleFaultNotices returns up to n notices spread across the fault's
+// history, newest first. The window defaults to the fault's creation
+// through its last notice; n points are spaced evenly across it, the first
+// and last on its ends, and each contributes the newest notice created at
+// or before it. A quiet stretch makes neighbouring points land on the same
+// notice, so fewer than n may come back, with a warning.
+func sampleFaultNotices(ctx context.Context, client *hbapi.Client, projectID, faultID int, created timeWindow, n int, now time.Time, notes *toolNotes) ([]hbapi.Notice, error) {
+	from, to := created.After, created.Before
+	if from.IsZero() || to.IsZero() {
+		fault, err := client.Faults.Get(ctx, projectID, faultID)
+		if err != nil {
+			return nil, err
+		}
+		if from.IsZero() {
+			from = fault.CreatedAt
+		}
+		if to.IsZero() {
+			to = now
+			if fault.LastNoticeAt != nil {
+				to = *fault.LastNoticeAt
+			}
+		}
+	}
+	if to.Before(from) {
+		to = from
+	}
+
+	found := make([]*hbapi.Notice, n)
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, sampleConcurrency)
+	for i := range n {
+		at := to
+		if n > 1 {
+			at = from.Add(to.Sub(from) * time.Duration(i) / time.Duration(n-1))
+		}
+		wg.Go(func() {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			// The API compares whole seconds, so a notice created in the
+			// same second as the point (the fault's first, say) still counts.
+			page, err := client.Faults.ListNotices(ctx, projectID, faultID, hbapi.FaultListNoticesOptions{
+				CreatedAfter:  created.After,
+				CreatedBefore: at.Add(time.Second),
+				Limit:         1,
+			})
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			if len(page.Results) > 0 {
+				found[i] = &page.Results[0]
+			}
+		})
+	}
+	wg.Wait()
+
+	notices := make([]hbapi.Notice, 0, n)
+	seen := map[string]bool{}
+	for i := n - 1; i >= 0; i-- {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		if found[i] == nil || seen[found[i].ID] {
+			continue
+		}
+		seen[found[i].ID] = true
+		notices = append(notices, *found[i])
+	}
+	if len(notices) < n {
+		notes.warnf("sampled %d distinct notices of %d requested; the fault has few notices in the window", len(notices), n)
+	}
+	return notices, nil
+}
@@ -0,0 +1,86 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestHandleListFaultNotices_Sample(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	// Notices at hours 0, 1, and 3 of a fault last seen at hour 4; nothing
+	// was created at hour 2.
+	hours := []int{3, 1, 0}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v2/projects/1/faults/2":
+			fmt.Fprintf(w, `{"id": 2, "created_at": %q, "last_notice_at": %q}`, start.Format(time.RFC3339), start.Add(4*time.Hour).Format(time.RFC3339))
+		case "/v2/projects/1/faults/2/notices":
+			if r.URL.Query().Get("limit") != "1" {
+				t.Errorf("expected one notice per sample point, got limit %q", r.URL.Query().Get("limit"))
+			}
+			before, _ := strconv.ParseInt(r.URL.Query().Get("created_before"), 10, 64)
+			for _, h := range hours {
+				if at := start.Add(time.Duration(h) * time.Hour); at.Unix() < before {
+					fmt.Fprintf(w, `{"results": [{"id": "n%d", "created_at": %q}], "links": {}}`, h, at.Format(time.RFC3339))
+					return
+				}
+			}
+			_, _ = w.Write([]byte(`{"results": [], "links": {}}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"project_id": float64(1), "fault_id": float64(2), "sample": float64(5)}
+	result, err := handleListFaultNotices(context.Background(), client, req)
+	if err != nil || result.IsError {
+		t.Fatalf("unexpected error: %v %s", err, getResultText(result))
+	}
+	var got struct {
+		Results []hbapi.Notice `json:"results"`
+	}
+	if err := json.Unmarshal([]byte(getResultText(result)), &got); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	var ids []string
+	for _, n := range got.Results {
+		ids = append(ids, n.ID)
+	}
+	if strings.Join(ids, ",") != "n3,n1,n0" {
+		t.Errorf("expected the distinct sampled notices newest first, got %v", ids)
+	}
+	if notes := result.Content[len(result.Content)-1].(mcp.TextContent).Text; !strings.Contains(notes, "sampled 3 distinct notices of 5 requested") {
+		t.Errorf("expected a warning about the short sample, got %s", notes)
+	}
+}
+
+func TestHandleListFaultNotices_SampleInvalid(t *testing.T) {
+	for _, args := range []map[string]any{
+		{"sample": float64(1)},
+		{"sample": float64(3), "latest": true},
+		{"sample": float64(3), "page_token": "Y3JlYXRlZF9iZWZvcmU9MTcwNDA2NzIwMA"},
+	} {
+		args["project_id"], args["fault_id"] = float64(1), float64(2)
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = args
+		result, err := handleListFaultNotices(context.Background(), hbapi.NewClient().WithBaseURL("http://127.0.0.1:1"), req)
+		if err != nil || !result.IsError {
+			t.Errorf("expected an error result for %v, got %v %s", args, err, getResultText(result))
+		}
+	}
+}
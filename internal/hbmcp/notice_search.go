@@ -0,0 +1,169 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	defaultNoticeSearchLimit = 25
+	maxNoticeSearchLimit     = 100
+)
+
+// noticeMatch is a notice search_project_notices found. The notice itself
+// is left out to keep the list small; resource_uri reads it in full.
+type noticeMatch struct {
+	FaultID     int       `json:"fault_id"`
+	Klass       string    `json:"klass"`
+	NoticeID    string    `json:"notice_id"`
+	CreatedAt   time.Time `json:"created_at"`
+	Message     string    `json:"message"`
+	Environment string    `json:"environment"`
+	Value       any       `json:"value"`
+	ResourceURI string    `json:"resource_uri"`
+}
+
+// handleSearchProjectNotices searches notices across a project's faults.
+// The API has no project-wide notice search, so it narrows the faults with
+// the fault search, which matches a fault when any of its notices match,
+// then reads a page of each fault's notices and matches them itself.
+func handleSearchProjectNotices(ctx context.Context, client *hbapi.Client, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	projectID := req.GetInt("project_id", 0)
+	if projectID == 0 {
+		return mcp.NewToolResultError("project_id is required"), nil
+	}
+	field := strings.Trim(strings.TrimSpace(req.GetString("field", "")), ".")
+	if field == "" {
+		return mcp.NewToolResultError("field is required"), nil
+	}
+	value := strings.TrimSpace(req.GetString("value", ""))
+	if value == "" {
+		return mcp.NewToolResultError("value is required"), nil
+	}
+	limit := req.GetInt("limit", defaultNoticeSearchLimit)
+	if limit < 1 || limit > maxNoticeSearchLimit {
+		return mcp.NewToolResultError(fmt.Sprintf("limit must be between 1 and %d", maxNoticeSearchLimit)), nil
+	}
+
+	now := time.Now()
+	window, err := resolveTimeWindow(req, "occurred_after", "occurred_before", now, time.UTC)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	var notes toolNotes
+	notes.addResolved(window.Resolved)
+	if window.Before.IsZero() {
+		window.Before = now
+	}
+	if window.After.IsZero() {
+		window.After = window.Before.Add(-defaultUserImpactWindow)
+	}
+
+	faults, err := client.Faults.List(ctx, projectID, hbapi.FaultListOptions{
+		Q:              fmt.Sprintf("%s:%q", field, value),
+		OccurredAfter:  window.After,
+		OccurredBefore: window.Before,
+		Order:          "recent",
+		Limit:          maxPageLimit,
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to search faults: %v", err)), nil
+	}
+	if faults.Links.Next != "" {
+		notes.warnf("more than %d faults match; only the notices of the %d most recent were searched", len(faults.Results), len(faults.Results))
+	}
+
+	matches := searchFaultNotices(ctx, client, projectID, faults.Results, field, value, window, &notes)
+	slices.SortStableFunc(matches, func(a, b noticeMatch) int { return b.CreatedAt.Compare(a.CreatedAt) })
+	if len(matches) > limit {
+		notes.warnf("%d matching notices found; only the %d most recent are listed", len(matches), limit)
+		matches = matches[:limit]
+	}
+
+	// Return JSON response
+	jsonBytes, err := json.Marshal(map[string][]noticeMatch{"results": matches})
+	if err != nil {
+		return mcp.NewToolResultError("Failed to marshal response"), nil
+	}
+
+	return withNotes(mcp.NewToolResultText(string(jsonBytes)), &notes), nil
+}
+
+// searchFaultNotices reads the most recent page of each fault's notices in
+// window, at most enrichConcurrency faults at a time, and returns those
+// whose value at field contains value.
+func searchFaultNotices(ctx context.Context, client *hbapi.Client, projectID int, faults []hbapi.Fault, field, value string, window timeWindow, notes *toolNotes) []noticeMatch {
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var failed, full int
+	matches := []noticeMatch{}
+	sem := make(chan struct{}, enrichConcurrency)
+	for _, f := range faults {
+		wg.Go(func() {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			page, err := client.Faults.ListNotices(ctx, projectID, f.ID, hbapi.FaultListNoticesOptions{
+				CreatedAfter:  window.After,
+				CreatedBefore: window.Before,
+				Limit:         maxPageLimit,
+			})
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				failed++
+				return
+			}
+			if page.Links.Next != "" {
+				full++
+			}
+			for _, n := range page.Results {
+				if v, ok := noticeValue(n, field); ok && strings.Contains(strings.ToLower(fmt.Sprint(v)), strings.ToLower(value)) {
+					matches = append(matches, noticeMatch{
+						FaultID:     f.ID,
+						Klass:       f.Klass,
+						NoticeID:    n.ID,
+						CreatedAt:   n.CreatedAt,
+						Message:     n.Message,
+						Environment: n.EnvironmentName,
+						Value:       v,
+						ResourceURI: noticeResourceURI(projectID, f.ID, n.ID, n.CreatedAt),
+					})
+				}
+			}
+		})
+	}
+	wg.Wait()
+	if failed > 0 {
+		notes.warnf("skipped %d faults whose notices couldn't be listed", failed)
+	}
+	if full > 0 {
+		notes.warnf("%d faults have more than %d notices in the window; only their most recent were searched, so narrow occurred_after and occurred_before to reach older ones", full, maxPageLimit)
+	}
+	return matches
+}
+
+// noticeValue returns the value at a dot-separated key path in a notice's
+// JSON. The fault search takes paths such as context.user_email that the
+// notice holds under request, so those are looked up there too.
+func noticeValue(n hbapi.Notice, path string) (any, bool) {
+	raw, err := json.Marshal(n)
+	if err != nil {
+		return nil, false
+	}
+	var doc any
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, false
+	}
+	if v, ok := lookupKeyPath(doc, path); ok {
+		return v, true
+	}
+	return lookupKeyPath(doc, "request."+path)
+}
@@ -0,0 +1,56 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestHandleSearchProjectNotices(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v2/projects/1/faults":
+			if q := r.URL.Query().Get("q"); q != `context.user_email:"ada@example.com"` {
+				t.Errorf("unexpected fault search %q", q)
+			}
+			_, _ = w.Write([]byte(`{"results": [{"id": 10, "klass": "NoMethodError"}, {"id": 11, "klass": "Timeout"}], "links": {}}`))
+		case "/v2/projects/1/faults/10/notices":
+			_, _ = w.Write([]byte(`{"results": [
+				{"id": "a", "created_at": "2024-01-01T10:00:00Z", "request": {"context": {"user_email": "ADA@example.com"}}},
+				{"id": "b", "created_at": "2024-01-01T09:00:00Z", "request": {"context": {"user_email": "bob@example.com"}}}
+			], "links": {}}`))
+		case "/v2/projects/1/faults/11/notices":
+			_, _ = w.Write([]byte(`{"results": [{"id": "c", "created_at": "2024-01-01T11:00:00Z", "request": {"context": {"user_email": "ada@example.com"}}}], "links": {}}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+		}
+	}))
+	defer server.Close()
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"project_id": float64(1), "field": "context.user_email", "value": "ada@example.com"}
+	result, err := handleSearchProjectNotices(context.Background(), client, req)
+	if err != nil || result.IsError {
+		t.Fatalf("unexpected error: %v %s", err, getResultText(result))
+	}
+	var got struct {
+		Results []noticeMatch `json:"results"`
+	}
+	if err := json.Unmarshal([]byte(getResultText(result)), &got); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if len(got.Results) != 2 || got.Results[0].NoticeID != "c" || got.Results[1].NoticeID != "a" {
+		t.Fatalf("expected notices c and a, newest first, got %+v", got.Results)
+	}
+	if got.Results[1].FaultID != 10 || !strings.HasPrefix(got.Results[1].ResourceURI, "honeybadger://projects/1/faults/10/notices/a") {
+		t.Errorf("unexpected match %+v", got.Results[1])
+	}
+}
@@ -0,0 +1,92 @@
+package hbmcp
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// pageTokenDescription documents the page_token argument of every
+// paginated tool.
+const pageTokenDescription = "Token from a previous response's next_page_token to fetch the next page. Pass the same filters as the original call."
+
+var errInvalidPageToken = errors.New("invalid page_token: pass next_page_token from a previous response unchanged")
+
+// nextPageToken turns the API's links.next URL into an opaque token, so
+// agents never parse URLs out of links. The token is the link's query
+// string, and is only issued when it carries cursor, the parameter the
+// calling tool knows how to send back; otherwise the page couldn't be
+// fetched and the token would be a dead end.
+func nextPageToken(links hbapi.PaginationLinks, cursor string) string {
+	if links.Next == "" {
+		return ""
+	}
+	u, err := url.Parse(links.Next)
+	if err != nil || u.Query().Get(cursor) == "" {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(u.Query().Encode()))
+}
+
+// pageTokenArg decodes the page_token argument. It returns nil values when
+// no token was passed.
+func pageTokenArg(req mcp.CallToolRequest) (url.Values, error) {
+	token := strings.TrimSpace(req.GetString("page_token", ""))
+	if token == "" {
+		return nil, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, errInvalidPageToken
+	}
+	values, err := url.ParseQuery(string(raw))
+	if err != nil {
+		return nil, errInvalidPageToken
+	}
+	return values, nil
+}
+
+// pageArg resolves the page number for tools the API paginates by page,
+// from either page_token or the older numeric page argument.
+func pageArg(req mcp.CallToolRequest) (int, error) {
+	values, err := pageTokenArg(req)
+	if err != nil {
+		return 0, err
+	}
+	if values == nil {
+		return req.GetInt("page", 0), nil
+	}
+	if req.GetInt("page", 0) != 0 {
+		return 0, fmt.Errorf("pass either page or page_token, not both")
+	}
+	page, err := strconv.Atoi(values.Get("page"))
+	if err != nil || page < 1 {
+		return 0, errInvalidPageToken
+	}
+	return page, nil
+}
+
+// createdBeforeArg resolves the created_before cursor for notice listings,
+// which the API paginates by time rather than by page. The zero time means
+// no token was passed.
+func createdBeforeArg(req mcp.CallToolRequest) (time.Time, error) {
+	values, err := pageTokenArg(req)
+	if err != nil || values == nil {
+		return time.Time{}, err
+	}
+	raw := values.Get("created_before")
+	if secs, err := strconv.ParseInt(raw, 10, 64); err == nil {
+		return time.Unix(secs, 0).UTC(), nil
+	}
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+	return time.Time{}, errInvalidPageToken
+}
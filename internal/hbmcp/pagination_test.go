@@ -0,0 +1,142 @@
+package hbmcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func pageTokenRequest(args map[string]interface{}) mcp.CallToolRequest {
+	return mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: args}}
+}
+
+func TestNextPageToken(t *testing.T) {
+	links := hbapi.PaginationLinks{Next: "https://app.honeybadger.io/v2/projects/1/faults?page=3&q=boom"}
+
+	token := nextPageToken(links, "page")
+	if token == "" {
+		t.Fatal("expected a token for a next link with a page")
+	}
+	page, err := pageArg(pageTokenRequest(map[string]interface{}{"page_token": token}))
+	if err != nil {
+		t.Fatalf("pageArg() error = %v", err)
+	}
+	if page != 3 {
+		t.Errorf("expected page 3, got %d", page)
+	}
+
+	if got := nextPageToken(hbapi.PaginationLinks{}, "page"); got != "" {
+		t.Errorf("expected no token without a next link, got %q", got)
+	}
+	if got := nextPageToken(links, "created_before"); got != "" {
+		t.Errorf("expected no token when the cursor parameter is missing, got %q", got)
+	}
+}
+
+func TestPageArg(t *testing.T) {
+	token := nextPageToken(hbapi.PaginationLinks{Next: "/v2/projects/1/faults?page=2"}, "page")
+
+	cases := []struct {
+		name    string
+		args    map[string]interface{}
+		want    int
+		wantErr bool
+	}{
+		{"neither", map[string]interface{}{}, 0, false},
+		{"page only", map[string]interface{}{"page": 4}, 4, false},
+		{"token only", map[string]interface{}{"page_token": token}, 2, false},
+		{"both", map[string]interface{}{"page": 4, "page_token": token}, 0, true},
+		{"garbage token", map[string]interface{}{"page_token": "not a token!"}, 0, true},
+		{"token without page", map[string]interface{}{"page_token": "cT1ib29t"}, 0, true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := pageArg(pageTokenRequest(c.args))
+			if (err != nil) != c.wantErr {
+				t.Fatalf("pageArg() error = %v, wantErr %v", err, c.wantErr)
+			}
+			if got != c.want {
+				t.Errorf("pageArg() = %d, want %d", got, c.want)
+			}
+		})
+	}
+}
+
+func TestHandleListFaults_PageToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("page") == "2" {
+			_, _ = w.Write([]byte(`{"results": [], "links": {}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"results": [], "links": {"next": "https://app.honeybadger.io/v2/projects/123/faults?page=2"}}`))
+	}))
+	defer server.Close()
+
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+
+	result, err := handleListFaults(context.Background(), client, pageTokenRequest(map[string]interface{}{"project_id": 123}))
+	if err != nil {
+		t.Fatalf("handleListFaults() error = %v", err)
+	}
+	token := getResultNotes(t, result).NextPageToken
+	if token == "" {
+		t.Fatal("expected next_page_token in notes")
+	}
+
+	result, err = handleListFaults(context.Background(), client, pageTokenRequest(map[string]interface{}{"project_id": 123, "page_token": token}))
+	if err != nil {
+		t.Fatalf("handleListFaults() error = %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected successful result, got error: %s", getResultText(result))
+	}
+	if len(result.Content) != 1 {
+		t.Error("expected no next_page_token on the last page")
+	}
+}
+
+func TestHandleListFaultNotices_PageToken(t *testing.T) {
+	cursor := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if got := r.URL.Query().Get("created_before"); got != "" && got != "1704153600" {
+			t.Errorf("expected created_before=1704153600, got %s", got)
+		}
+		_, _ = w.Write([]byte(`{"results": [], "links": {"next": "/v2/projects/123/faults/456/notices?created_before=1704153600"}}`))
+	}))
+	defer server.Close()
+
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+	args := map[string]interface{}{"project_id": 123, "fault_id": 456}
+
+	result, err := handleListFaultNotices(context.Background(), client, pageTokenRequest(args))
+	if err != nil {
+		t.Fatalf("handleListFaultNotices() error = %v", err)
+	}
+	token := getResultNotes(t, result).NextPageToken
+	if token == "" {
+		t.Fatal("expected next_page_token in notes")
+	}
+
+	args["page_token"] = token
+	before, err := createdBeforeArg(pageTokenRequest(args))
+	if err != nil {
+		t.Fatalf("createdBeforeArg() error = %v", err)
+	}
+	if !before.Equal(cursor) {
+		t.Errorf("expected cursor %s, got %s", cursor, before)
+	}
+	result, err = handleListFaultNotices(context.Background(), client, pageTokenRequest(args))
+	if err != nil {
+		t.Fatalf("handleListFaultNotices() error = %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected successful result, got error: %s", getResultText(result))
+	}
+}
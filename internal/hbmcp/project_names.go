@@ -0,0 +1,247 @@
+package hbmcp
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/honeybadger-io/honeybadger-mcp-server/internal/config"
+)
+
+const (
+	// projectNameArg is the argument a tool taking a project ID also
+	// accepts a project name in.
+	projectNameArg = "project_name"
+
+	// projectNamesTTL is how long a session's project list is used to
+	// resolve names before it is fetched again. A name that doesn't match
+	// refetches sooner, so a new project is found right away.
+	projectNamesTTL = 5 * time.Minute
+)
+
+// projectNames lets any tool taking a project ID be called with
+// project_name instead: the name is matched, ignoring case, against the
+// session's projects, fetched once and cached, and the call goes on with
+// the matching project's ID. A name matching several projects is refused
+// with their IDs so the agent can pick one. A call with neither, to a tool
+// whose project ID was required, uses the session's default project when
+// set_default_project has set one.
+type projectNames struct {
+	clientFor ClientFactory
+	current   func() *config.Config
+
+	// required names the tools whose project ID argument was required
+	// before project_name made it optional. Written only while tools are
+	// registered.
+	required map[string]bool
+
+	mu        sync.Mutex
+	bySession map[string]*projectNameList
+	defaults  map[string]projectNameEntry
+}
+
+// projectNameList is one session's cached projects.
+type projectNameList struct {
+	fetchedAt time.Time
+	projects  []projectNameEntry
+
+	// caller is the confirmationCaller of the token they were listed with,
+	// so a session that changes tokens doesn't match the old token's
+	// projects.
+	caller string
+}
+
+type projectNameEntry struct {
+	ID   int    `json:"project_id"`
+	Name string `json:"name"`
+}
+
+func newProjectNames(clientFor ClientFactory, current func() *config.Config) *projectNames {
+	return &projectNames{
+		clientFor: clientFor,
+		current:   current,
+		required:  map[string]bool{},
+		bySession: map[string]*projectNameList{},
+		defaults:  map[string]projectNameEntry{},
+	}
+}
+
+// projectArg returns the name of the tool's project ID argument, or "" if
+// it has none.
+func projectArg(tool mcp.Tool) string {
+	arg := "project_id"
+	if name, ok := projectIDArgs[tool.Name]; ok {
+		arg = name
+	}
+	if _, ok := tool.InputSchema.Properties[arg]; !ok {
+		return ""
+	}
+	return arg
+}
+
+// layer returns the registrar layer adding project_name to tools with a
+// project ID argument. It sits before the project scope so the resolved
+// ID is checked like one passed directly.
+func (p *projectNames) layer() toolLayer {
+	return toolLayer{
+		name: "project_name",
+		define: func(tool *mcp.Tool) {
+			arg := projectArg(*tool)
+			if arg == "" {
+				return
+			}
+			mcp.WithString(projectNameArg,
+				mcp.Description(fmt.Sprintf("Name of the project, instead of %s; matched ignoring case against the projects list_projects returns", arg)),
+			)(tool)
+			for i, name := range tool.InputSchema.Required {
+				if name == arg {
+					tool.InputSchema.Required = append(tool.InputSchema.Required[:i:i], tool.InputSchema.Required[i+1:]...)
+					p.required[tool.Name] = true
+					break
+				}
+			}
+		},
+		wrap: func(tool mcp.Tool, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+			arg := projectArg(tool)
+			if arg == "" {
+				return next
+			}
+			// A destructive call always names its project, so a default set
+			// earlier in the session can't pick what gets deleted.
+			return p.resolve(arg, p.required[tool.Name], !isDestructive(tool), next)
+		},
+	}
+}
+
+// resolve replaces a call's project_name with the ID argument arg. When
+// the call has neither and the argument is required, it is filled with the
+// session's default project if useDefault is set.
+func (p *projectNames) resolve(arg string, required, useDefault bool, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args := req.GetArguments()
+		name, _ := args[projectNameArg].(string)
+		name = strings.TrimSpace(name)
+		_, hasID := args[arg]
+		switch {
+		case name == "" && !hasID && required:
+			if !useDefault {
+				return mcp.NewToolResultError(fmt.Sprintf("%s or %s is required", arg, projectNameArg)), nil
+			}
+			project, ok := p.defaultProject(ctx)
+			if !ok {
+				return mcp.NewToolResultError(fmt.Sprintf("%s or %s is required, or set a default with set_default_project", arg, projectNameArg)), nil
+			}
+			resolved := maps.Clone(args)
+			if resolved == nil {
+				resolved = map[string]any{}
+			}
+			resolved[arg] = float64(project.ID)
+			req.Params.Arguments = resolved
+			return next(ctx, req)
+		case name == "":
+			return next(ctx, req)
+		case hasID:
+			return mcp.NewToolResultError(fmt.Sprintf("Pass %s or %s, not both", arg, projectNameArg)), nil
+		}
+
+		id, refusal, err := p.lookup(ctx, name)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to resolve %s: %v", projectNameArg, err)), nil
+		}
+		if refusal != "" {
+			return mcp.NewToolResultError(refusal), nil
+		}
+		resolved := maps.Clone(args)
+		delete(resolved, projectNameArg)
+		resolved[arg] = float64(id)
+		req.Params.Arguments = resolved
+		return next(ctx, req)
+	}
+}
+
+// lookup returns the ID of the one project in scope named name, or why
+// there isn't one. A name matching none refetches a list older than a few
+// seconds before it is refused.
+func (p *projectNames) lookup(ctx context.Context, name string) (int, string, error) {
+	list, err := p.projects(ctx, false)
+	if err != nil {
+		return 0, "", err
+	}
+	matches := list.match(name)
+	if len(matches) == 0 && time.Since(list.fetchedAt) > 5*time.Second {
+		if list, err = p.projects(ctx, true); err != nil {
+			return 0, "", err
+		}
+		matches = list.match(name)
+	}
+
+	switch len(matches) {
+	case 0:
+		return 0, fmt.Sprintf("No project named %q; call list_projects for the projects you can access", name), nil
+	case 1:
+		return matches[0].ID, "", nil
+	}
+	candidates := make([]string, len(matches))
+	for i, m := range matches {
+		candidates[i] = fmt.Sprintf("%s (project_id %d)", m.Name, m.ID)
+	}
+	return 0, fmt.Sprintf("%d projects are named %q: %s. Call again with the project's ID instead of %s", len(matches), name, strings.Join(candidates, ", "), projectNameArg), nil
+}
+
+func (l *projectNameList) match(name string) []projectNameEntry {
+	var matches []projectNameEntry
+	for _, p := range l.projects {
+		if strings.EqualFold(p.Name, name) {
+			matches = append(matches, p)
+		}
+	}
+	return matches
+}
+
+// projects returns the session's projects in scope, fetching them when
+// the cached list is missing, stale, listed with another token, or refresh
+// is set. Calls without a session, as in stateless http mode, share the
+// session "" across callers, so their lists aren't cached.
+func (p *projectNames) projects(ctx context.Context, refresh bool) (*projectNameList, error) {
+	sessionID := sessionIDFromContext(ctx)
+	caller := confirmationCaller(ctx)
+	p.mu.Lock()
+	list, ok := p.bySession[sessionID]
+	p.mu.Unlock()
+	if ok && !refresh && list.caller == caller && time.Since(list.fetchedAt) < projectNamesTTL {
+		return list, nil
+	}
+
+	response, err := p.clientFor(ctx).Projects.ListAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	list = &projectNameList{fetchedAt: time.Now(), caller: caller}
+	cfg := p.current()
+	for _, project := range response.Results {
+		if cfg.ProjectAllowed(project.ID) {
+			list.projects = append(list.projects, projectNameEntry{ID: project.ID, Name: project.Name})
+		}
+	}
+	if sessionID != "" {
+		p.mu.Lock()
+		p.bySession[sessionID] = list
+		p.mu.Unlock()
+	}
+	return list, nil
+}
+
+// forget drops the projects cached and the default project set for a
+// session that has gone away.
+func (p *projectNames) forget(sessionID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.bySession, sessionID)
+	delete(p.defaults, sessionID)
+}
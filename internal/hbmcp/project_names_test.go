@@ -0,0 +1,127 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/honeybadger-io/honeybadger-mcp-server/internal/config"
+)
+
+func TestProjectNames(t *testing.T) {
+	var listed int
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/projects" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		listed++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"results": [{"id": 1, "name": "Shop"}, {"id": 2, "name": "Blog"}, {"id": 3, "name": "blog"}, {"id": 4, "name": "Admin"}], "links": {}}`))
+	}))
+	defer api.Close()
+	clientFor := func(context.Context) *hbapi.Client {
+		return hbapi.NewClient().WithBaseURL(api.URL).WithAuthToken("test-token")
+	}
+	names := newProjectNames(clientFor, staticConfig(&config.Config{DeniedProjectIDs: []int{4}}))
+
+	r := newToolRegistrar(server.NewMCPServer("test", "1.0.0"))
+	r.middleware = append(r.middleware, names.layer())
+	var got map[string]any
+	r.AddTool(mcp.NewTool("list_faults",
+		mcp.WithNumber("project_id", mcp.Required()),
+	), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		got = req.GetArguments()
+		return mcp.NewToolResultText("ok"), nil
+	})
+	tool := r.server.GetTool("list_faults")
+	if _, ok := tool.Tool.InputSchema.Properties[projectNameArg]; !ok || slices.Contains(tool.Tool.InputSchema.Required, "project_id") {
+		t.Fatalf("expected project_name added and project_id made optional, got %+v", tool.Tool.InputSchema)
+	}
+
+	ctx := r.server.WithContext(context.Background(), &testSession{id: "session"})
+	call := func(args map[string]any) *mcp.CallToolResult {
+		got = nil
+		req := mcp.CallToolRequest{}
+		req.Params.Name = "list_faults"
+		req.Params.Arguments = args
+		result, err := tool.Handler(ctx, req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return result
+	}
+
+	if result := call(map[string]any{"project_name": "shop"}); result.IsError || got["project_id"] != float64(1) || got[projectNameArg] != nil {
+		t.Errorf("expected shop resolved to project 1, got %v (%s)", got, getResultText(result))
+	}
+	if result := call(map[string]any{"project_id": float64(2)}); result.IsError || got["project_id"] != float64(2) {
+		t.Errorf("expected project_id passed through, got %v (%s)", got, getResultText(result))
+	}
+	tests := []struct {
+		args    map[string]any
+		wantErr string
+	}{
+		{map[string]any{"project_name": "Blog"}, `2 projects are named "Blog": Blog (project_id 2), blog (project_id 3)`},
+		{map[string]any{"project_name": "Admin"}, `No project named "Admin"`},
+		{map[string]any{"project_name": "Shop", "project_id": float64(1)}, "not both"},
+		{map[string]any{}, "project_id or project_name is required"},
+	}
+	for _, tt := range tests {
+		if result := call(tt.args); !result.IsError || !strings.Contains(getResultText(result), tt.wantErr) || got != nil {
+			t.Errorf("%v: expected error containing %q, got %s", tt.args, tt.wantErr, getResultText(result))
+		}
+	}
+	if listed != 1 {
+		t.Errorf("expected the project list fetched once and cached, got %d requests", listed)
+	}
+}
+
+func TestProjectNames_Callers(t *testing.T) {
+	var listed int
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		listed++
+		w.Header().Set("Content-Type", "application/json")
+		if r.Header.Get("Authorization") == "Basic "+base64.StdEncoding.EncodeToString([]byte("token-a:")) {
+			_, _ = w.Write([]byte(`{"results": [{"id": 1, "name": "Shop"}], "links": {}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"results": [{"id": 7, "name": "Shop"}], "links": {}}`))
+	}))
+	defer api.Close()
+	clientFor := func(ctx context.Context) *hbapi.Client {
+		return hbapi.NewClient().WithBaseURL(api.URL).WithAuthToken(AuthTokenFromContext(ctx))
+	}
+	names := newProjectNames(clientFor, staticConfig(&config.Config{}))
+	s := server.NewMCPServer("test", "1.0.0")
+
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		wantID     int
+		wantListed int
+	}{
+		{"stateless, first caller", WithAuthToken(context.Background(), "token-a"), 1, 1},
+		{"stateless, second caller", WithAuthToken(context.Background(), "token-b"), 7, 2},
+		{"stateless, not cached", WithAuthToken(context.Background(), "token-a"), 1, 3},
+		{"session", WithAuthToken(s.WithContext(context.Background(), &testSession{id: "s"}), "token-a"), 1, 4},
+		{"session, cached", WithAuthToken(s.WithContext(context.Background(), &testSession{id: "s"}), "token-a"), 1, 4},
+		{"session, new token", WithAuthToken(s.WithContext(context.Background(), &testSession{id: "s"}), "token-b"), 7, 5},
+	}
+	for _, tt := range tests {
+		list, err := names.projects(tt.ctx, false)
+		if err != nil {
+			t.Fatalf("%s: projects() error = %v", tt.name, err)
+		}
+		if matches := list.match("shop"); len(matches) != 1 || matches[0].ID != tt.wantID || listed != tt.wantListed {
+			t.Errorf("%s: expected project %d after %d lists, got %v after %d", tt.name, tt.wantID, tt.wantListed, matches, listed)
+		}
+	}
+}
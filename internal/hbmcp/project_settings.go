@@ -0,0 +1,165 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+type settingKind int
+
+const (
+	settingString settingKind = iota
+	settingBool
+	settingDays
+)
+
+// projectSettings are the project settings update_project can change, in
+// the order diffs list them.
+var projectSettings = []struct {
+	name string
+	kind settingKind
+}{
+	{"name", settingString},
+	{"resolve_errors_on_deploy", settingBool},
+	{"disable_public_links", settingBool},
+	{"user_url", settingString},
+	{"source_url", settingString},
+	{"purge_days", settingDays},
+	{"user_search_field", settingString},
+}
+
+type settingChange struct {
+	Setting string `json:"setting"`
+	Current any    `json:"current"`
+	Desired any    `json:"desired"`
+}
+
+// projectSettingsDiff is the result of get_project_settings_diff.
+// UpdateProject holds the update_project arguments that apply the
+// changes, and is omitted when there is nothing to apply.
+type projectSettingsDiff struct {
+	ProjectID     int             `json:"project_id"`
+	Changes       []settingChange `json:"changes"`
+	Unchanged     []string        `json:"unchanged"`
+	UpdateProject map[string]any  `json:"update_project,omitempty"`
+}
+
+func handleGetProjectSettingsDiff(ctx context.Context, client *hbapi.Client, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	projectID := req.GetInt("project_id", 0)
+	if projectID == 0 {
+		return mcp.NewToolResultError("project_id is required"), nil
+	}
+
+	desiredJSON := req.GetString("desired", "")
+	if desiredJSON == "" {
+		return mcp.NewToolResultError("desired is required"), nil
+	}
+	var desired map[string]any
+	if err := json.Unmarshal([]byte(desiredJSON), &desired); err != nil || desired == nil {
+		return mcp.NewToolResultError("desired must be a JSON object of project settings"), nil
+	}
+	if err := checkDesiredSettings(desired); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	// hbapi's Project type leaves out most settings, so they're read from
+	// the response body the API client's transport keeps.
+	raw := &rawBody{}
+	project, err := client.Projects.Get(context.WithValue(ctx, rawBodyKey{}, raw), projectID)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to get project: %v", err)), nil
+	}
+	current := map[string]any{"name": project.Name}
+	if body, ok := raw.load(); ok {
+		_ = json.Unmarshal(body, &current)
+	}
+
+	var notes toolNotes
+	diff := projectSettingsDiff{ProjectID: projectID, Changes: []settingChange{}, Unchanged: []string{}}
+	update := map[string]any{}
+	for _, setting := range projectSettings {
+		want, ok := desired[setting.name]
+		if !ok {
+			continue
+		}
+		have, known := current[setting.name]
+		if known && sameSetting(have, want) {
+			diff.Unchanged = append(diff.Unchanged, setting.name)
+			continue
+		}
+		diff.Changes = append(diff.Changes, settingChange{Setting: setting.name, Current: have, Desired: want})
+		switch {
+		case setting.kind == settingString && want == "":
+			notes.warnf("update_project can't clear %s; change it in the Honeybadger UI", setting.name)
+			continue
+		case !known:
+			notes.warnf("the API didn't report the current %s, so update_project sets it in case it differs", setting.name)
+		}
+		update[setting.name] = want
+	}
+	if len(update) > 0 {
+		update["id"] = projectID
+		diff.UpdateProject = update
+	}
+
+	// Return JSON response
+	jsonBytes, err := json.Marshal(diff)
+	if err != nil {
+		return mcp.NewToolResultError("Failed to marshal response"), nil
+	}
+
+	return withNotes(mcp.NewToolResultText(string(jsonBytes)), &notes), nil
+}
+
+// checkDesiredSettings reports the first setting in desired that
+// update_project doesn't take or whose value is the wrong type.
+func checkDesiredSettings(desired map[string]any) error {
+	kinds := map[string]settingKind{}
+	names := make([]string, len(projectSettings))
+	for i, s := range projectSettings {
+		kinds[s.name] = s.kind
+		names[i] = s.name
+	}
+	for _, name := range slices.Sorted(maps.Keys(desired)) {
+		v := desired[name]
+		kind, ok := kinds[name]
+		if !ok {
+			return fmt.Errorf("unknown setting %q; settings are %s", name, strings.Join(names, ", "))
+		}
+		switch kind {
+		case settingString:
+			if _, ok := v.(string); !ok {
+				return fmt.Errorf("%s must be a string", name)
+			}
+		case settingBool:
+			if _, ok := v.(bool); !ok {
+				return fmt.Errorf("%s must be a boolean", name)
+			}
+		case settingDays:
+			if n, ok := v.(float64); !ok || n < 1 || n != math.Trunc(n) {
+				return fmt.Errorf("%s must be a whole number of days of at least 1", name)
+			}
+		}
+	}
+	if desired["name"] == "" {
+		return fmt.Errorf("name must not be empty")
+	}
+	return nil
+}
+
+// sameSetting compares a current value from the API with a desired one;
+// a null current value matches an empty string.
+func sameSetting(current, desired any) bool {
+	if current == nil {
+		return desired == ""
+	}
+	return current == desired
+}
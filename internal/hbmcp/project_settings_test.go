@@ -0,0 +1,157 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestHandleGetProjectSettingsDiff(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/projects/123" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"id": 123,
+			"name": "Shop",
+			"resolve_errors_on_deploy": false,
+			"disable_public_links": true,
+			"user_url": null,
+			"source_url": "https://github.com/acme/shop/blob/[sha]/[file]#L[line]",
+			"purge_days": 30
+		}`))
+	}))
+	defer server.Close()
+
+	// The settings are read from the body rawBodyTransport keeps, as in
+	// the server's API client.
+	client := hbapi.NewClient().
+		WithBaseURL(server.URL).
+		WithAuthToken("test-token").
+		WithHTTPClient(&http.Client{Transport: &rawBodyTransport{base: http.DefaultTransport}})
+
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Arguments: map[string]interface{}{
+				"project_id": 123,
+				"desired": `{
+					"name": "Shop",
+					"resolve_errors_on_deploy": true,
+					"disable_public_links": true,
+					"user_url": "",
+					"source_url": "",
+					"purge_days": 90,
+					"user_search_field": "context.user_email"
+				}`,
+			},
+		},
+	}
+
+	result, err := handleGetProjectSettingsDiff(context.Background(), client, req)
+	if err != nil {
+		t.Fatalf("handleGetProjectSettingsDiff() error = %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got %s", getResultText(result))
+	}
+
+	var diff projectSettingsDiff
+	if err := json.Unmarshal([]byte(getResultText(result)), &diff); err != nil {
+		t.Fatalf("failed to parse diff: %v", err)
+	}
+
+	wantUnchanged := []string{"name", "disable_public_links", "user_url"}
+	if !reflect.DeepEqual(diff.Unchanged, wantUnchanged) {
+		t.Errorf("unchanged = %v, want %v", diff.Unchanged, wantUnchanged)
+	}
+	var changed []string
+	for _, c := range diff.Changes {
+		changed = append(changed, c.Setting)
+	}
+	wantChanged := []string{"resolve_errors_on_deploy", "source_url", "purge_days", "user_search_field"}
+	if !reflect.DeepEqual(changed, wantChanged) {
+		t.Errorf("changes = %v, want %v", changed, wantChanged)
+	}
+	if diff.Changes[2].Current != float64(30) || diff.Changes[2].Desired != float64(90) {
+		t.Errorf("purge_days change = %+v, want 30 -> 90", diff.Changes[2])
+	}
+
+	// source_url can't be cleared through update_project, so it's left out.
+	wantUpdate := map[string]any{
+		"id":                       float64(123),
+		"resolve_errors_on_deploy": true,
+		"purge_days":               float64(90),
+		"user_search_field":        "context.user_email",
+	}
+	if !reflect.DeepEqual(diff.UpdateProject, wantUpdate) {
+		t.Errorf("update_project = %v, want %v", diff.UpdateProject, wantUpdate)
+	}
+
+	warnings := strings.Join(getResultNotes(t, result).Warnings, "\n")
+	if !strings.Contains(warnings, "can't clear source_url") || !strings.Contains(warnings, "didn't report the current user_search_field") {
+		t.Errorf("expected warnings about source_url and user_search_field, got %q", warnings)
+	}
+}
+
+func TestHandleGetProjectSettingsDiff_NoChanges(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": 123, "name": "Shop", "purge_days": 30}`))
+	}))
+	defer server.Close()
+
+	client := hbapi.NewClient().
+		WithBaseURL(server.URL).
+		WithAuthToken("test-token").
+		WithHTTPClient(&http.Client{Transport: &rawBodyTransport{base: http.DefaultTransport}})
+	req := mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"project_id": 123,
+		"desired":    `{"name": "Shop", "purge_days": 30}`,
+	}}}
+
+	result, err := handleGetProjectSettingsDiff(context.Background(), client, req)
+	if err != nil {
+		t.Fatalf("handleGetProjectSettingsDiff() error = %v", err)
+	}
+	if text := getResultText(result); strings.Contains(text, "update_project") || !strings.Contains(text, `"changes":[]`) {
+		t.Errorf("expected no changes and no update_project, got %s", text)
+	}
+}
+
+func TestHandleGetProjectSettingsDiff_InvalidDesired(t *testing.T) {
+	tests := []struct {
+		desired string
+		want    string
+	}{
+		{"", "desired is required"},
+		{`[1]`, "desired must be a JSON object"},
+		{`{"owner": "me"}`, `unknown setting "owner"`},
+		{`{"purge_days": 0}`, "purge_days must be a whole number"},
+		{`{"purge_days": "30"}`, "purge_days must be a whole number"},
+		{`{"resolve_errors_on_deploy": "yes"}`, "resolve_errors_on_deploy must be a boolean"},
+		{`{"user_url": 5}`, "user_url must be a string"},
+		{`{"name": ""}`, "name must not be empty"},
+	}
+	for _, tt := range tests {
+		req := mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+			"project_id": 123,
+			"desired":    tt.desired,
+		}}}
+		// The client is never reached: the desired state is checked first.
+		result, err := handleGetProjectSettingsDiff(context.Background(), nil, req)
+		if err != nil {
+			t.Fatalf("handleGetProjectSettingsDiff() error = %v", err)
+		}
+		if !result.IsError || !strings.Contains(getResultText(result), tt.want) {
+			t.Errorf("desired %s: expected error containing %q, got %q", tt.desired, tt.want, getResultText(result))
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// setupFrameworks are the frameworks setup_project returns snippets for
+// unless asked for others.
+var setupFrameworks = []string{"rails", "node", "python"}
+
+type projectSetup struct {
+	Project          *hbapi.Project    `json:"project"`
+	APIKey           string            `json:"api_key"`
+	SlackIntegration *setupStep        `json:"slack_integration,omitempty"`
+	Snippets         map[string]string `json:"snippets"`
+	Note             string            `json:"note"`
+}
+
+// setupStep reports an optional step that can fail without undoing the
+// project it follows.
+type setupStep struct {
+	Created bool   `json:"created"`
+	Error   string `json:"error,omitempty"`
+	Result  any    `json:"result,omitempty"`
+}
+
+// RegisterProjectSetupTools registers setup_project. It takes the raw
+// client factory as well, since hbapi can't create integrations.
+func RegisterProjectSetupTools(r *toolRegistrar, clientFor ClientFactory, rawFor RawClientFactory) {
+	// setup_project tool
+	r.AddTool(
+		mcp.NewTool("setup_project",
+			mcp.WithTitleAnnotation("Set Up Project"),
+			mcp.WithDescription("Onboard a new service in one call: create a project with recommended settings (resolve errors on deploy), optionally connect a Slack webhook, and return the project's API key with copy-pasteable install and config snippets (Rails, Node, and Python by default). Use create_project instead for full control over the settings."),
+			mcp.WithReadOnlyHintAnnotation(false),
+			mcp.WithDestructiveHintAnnotation(true),
+			mcp.WithIdempotentHintAnnotation(false),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithString("account_id",
+				mcp.Description("The account ID to create the project in. If omitted, the project is created in the first account your auth token has access to."),
+				mcp.MinLength(1),
+			),
+			mcp.WithString("name",
+				mcp.Required(),
+				mcp.Description("The name of the new project"),
+				mcp.MinLength(1),
+				mcp.MaxLength(255),
+			),
+			mcp.WithBoolean("resolve_errors_on_deploy",
+				mcp.Description("Whether unresolved faults are marked resolved when a deploy is recorded (default true)"),
+			),
+			mcp.WithNumber("purge_days",
+				mcp.Description("The number of days to retain data (default the longest your subscription plan allows)"),
+				mcp.Min(1),
+			),
+			mcp.WithString("slack_webhook_url",
+				mcp.Description("A Slack incoming webhook URL to send the project's error notifications to"),
+			),
+			mcp.WithArray("frameworks",
+				mcp.WithStringEnumItems(installFrameworkNames()),
+				mcp.Description("Frameworks to return install instructions for, as for get_install_instructions (default rails, node, and python)"),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleSetupProject(ctx, clientFor(ctx), rawFor(ctx), req)
+		},
+	)
+}
+
+func handleSetupProject(ctx context.Context, client *hbapi.Client, raw *RawClient, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	name := req.GetString("name", "")
+	if name == "" {
+		return mcp.NewToolResultError("name is required"), nil
+	}
+
+	webhook := req.GetString("slack_webhook_url", "")
+	if webhook != "" {
+		if u, err := url.Parse(webhook); err != nil || u.Scheme != "https" || u.Host == "" {
+			return mcp.NewToolResultError(fmt.Sprintf("slack_webhook_url must be an https URL, got %q", webhook)), nil
+		}
+	}
+	frameworks := req.GetStringSlice("frameworks", setupFrameworks)
+	for _, f := range frameworks {
+		if !slices.Contains(installFrameworkNames(), f) {
+			return mcp.NewToolResultError(fmt.Sprintf("unknown framework %q; expected one of %s", f, strings.Join(installFrameworkNames(), ", "))), nil
+		}
+	}
+
+	resolveOnDeploy := req.GetBool("resolve_errors_on_deploy", true)
+	projectReq := hbapi.ProjectRequest{
+		Name:                  name,
+		ResolveErrorsOnDeploy: &resolveOnDeploy,
+	}
+	if purgeDays := req.GetInt("purge_days", 0); purgeDays > 0 {
+		projectReq.PurgeDays = &purgeDays
+	}
+
+	project, err := client.Projects.Create(ctx, req.GetString("account_id", ""), projectReq)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to create project: %v", err)), nil
+	}
+
+	var notes toolNotes
+	setup := projectSetup{
+		Project:  project,
+		APIKey:   project.Token,
+		Snippets: map[string]string{},
+		Note:     installKeyNote,
+	}
+	for _, f := range frameworks {
+		if setup.Snippets[f], err = renderInstall(f, project); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to render instructions: %v", err)), nil
+		}
+	}
+	if webhook != "" {
+		// The project exists by now, so a failure here is reported rather
+		// than failing the call and hiding the new project's API key.
+		var integration map[string]any
+		err := raw.Raw(ctx, http.MethodPost, fmt.Sprintf("/projects/%d/integrations", project.ID), map[string]any{
+			"integration": map[string]any{
+				"type":    "slack",
+				"options": map[string]any{"url": webhook},
+			},
+		}, &integration)
+		setup.SlackIntegration = &setupStep{Created: err == nil}
+		if err == nil {
+			setup.SlackIntegration.Result = integration
+		} else {
+			setup.SlackIntegration.Error = err.Error()
+			notes.warnf("project %d was created, but its Slack integration wasn't: %v; add it from the project's Integrations settings", project.ID, err)
+		}
+	}
+
+	// Return JSON response
+	jsonBytes, err := json.Marshal(setup)
+	if err != nil {
+		return mcp.NewToolResultError("Failed to marshal response"), nil
+	}
+
+	return withNotes(mcp.NewToolResultText(string(jsonBytes)), &notes), nil
+}
@@ -0,0 +1,99 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestHandleSetupProject(t *testing.T) {
+	integrationStatus := http.StatusCreated
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v2/projects":
+			if body["project"]["name"] != "Checkout" || body["project"]["resolve_errors_on_deploy"] != true {
+				t.Errorf("unexpected project request %v", body)
+			}
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id": 5, "name": "Checkout", "token": "hbp_abc123"}`))
+		case "/v2/projects/5/integrations":
+			if options, _ := body["integration"]["options"].(map[string]any); options["url"] != "https://hooks.slack.com/services/T0/B0/x" {
+				t.Errorf("unexpected integration request %v", body)
+			}
+			w.WriteHeader(integrationStatus)
+			if integrationStatus == http.StatusCreated {
+				_, _ = w.Write([]byte(`{"id": 9, "type": "slack"}`))
+			} else {
+				_, _ = w.Write([]byte(`{"errors": "Type is not supported"}`))
+			}
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+		}
+	}))
+	defer server.Close()
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+	raw := &RawClient{baseURL: server.URL, authToken: "test-token", httpClient: http.DefaultClient}
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{
+		"name":              "Checkout",
+		"slack_webhook_url": "https://hooks.slack.com/services/T0/B0/x",
+		"frameworks":        []any{"rails", "python"},
+	}
+	result, err := handleSetupProject(context.Background(), client, raw, req)
+	if err != nil || result.IsError {
+		t.Fatalf("unexpected error: %v %s", err, getResultText(result))
+	}
+	var got projectSetup
+	if err := json.Unmarshal([]byte(getResultText(result)), &got); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if got.APIKey != "hbp_abc123" || got.SlackIntegration == nil || !got.SlackIntegration.Created {
+		t.Errorf("unexpected setup %+v", got)
+	}
+	if len(got.Snippets) != 2 || !strings.Contains(got.Snippets["rails"], "honeybadger install hbp_abc123") || !strings.Contains(got.Snippets["python"], "api_key='hbp_abc123'") {
+		t.Errorf("unexpected snippets %v", got.Snippets)
+	}
+
+	// A failed integration still returns the new project and its key.
+	integrationStatus = http.StatusUnprocessableEntity
+	result, err = handleSetupProject(context.Background(), client, raw, req)
+	if err != nil || result.IsError {
+		t.Fatalf("unexpected error: %v %s", err, getResultText(result))
+	}
+	got = projectSetup{}
+	if err := json.Unmarshal([]byte(getResultText(result)), &got); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if got.APIKey != "hbp_abc123" || got.SlackIntegration.Created || got.SlackIntegration.Error == "" {
+		t.Errorf("expected the integration failure reported, got %+v", got.SlackIntegration)
+	}
+	if notes := result.Content[len(result.Content)-1].(mcp.TextContent).Text; !strings.Contains(notes, "project 5 was created, but its Slack integration wasn't") {
+		t.Errorf("expected a warning, got %s", notes)
+	}
+}
+
+func TestHandleSetupProject_Invalid(t *testing.T) {
+	for _, args := range []map[string]any{
+		{"name": "Checkout", "slack_webhook_url": "http://hooks.slack.com/x"},
+		{"name": "Checkout", "frameworks": []any{"elixir"}},
+	} {
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = args
+		result, err := handleSetupProject(context.Background(), nil, nil, req)
+		if err != nil || !result.IsError {
+			t.Errorf("expected an error result for %v, got %v", args, getResultText(result))
+		}
+	}
+}
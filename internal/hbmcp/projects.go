@@ -1,9 +1,15 @@
 package hbmcp
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	hbapi "github.com/honeybadger-io/api-go"
@@ -19,6 +25,9 @@ func RegisterProjectTools(r *toolRegistrar, clientFor ClientFactory) {
 			mcp.WithDescription("List all Honeybadger projects (returns summary info; use get_project for full details)"),
 			mcp.WithReadOnlyHintAnnotation(true),
 			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithOutputSchema[projectSummaryResponse](),
 			mcp.WithString("account_id",
 				mcp.Description("Optional account ID to filter projects by specific account"),
 			),
@@ -35,6 +44,9 @@ func RegisterProjectTools(r *toolRegistrar, clientFor ClientFactory) {
 			mcp.WithDescription("Get a single Honeybadger project by ID"),
 			mcp.WithReadOnlyHintAnnotation(true),
 			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithOutputSchema[hbapi.Project](),
 			mcp.WithNumber("id",
 				mcp.Required(),
 				mcp.Description("The ID of the project to retrieve"),
@@ -42,7 +54,28 @@ func RegisterProjectTools(r *toolRegistrar, clientFor ClientFactory) {
 			),
 		),
 		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-			return handleGetProject(ctx, clientFor(ctx), req)
+			return handleGetProject(ctx, clientFor(ctx), req, r.lookupNotFound)
+		},
+	)
+
+	// find_project_by_token tool
+	r.AddTool(
+		mcp.NewTool("find_project_by_token",
+			mcp.WithTitleAnnotation("Find Project by API Key"),
+			mcp.WithDescription("Find which Honeybadger project a project API key (the key apps report errors with, not a personal auth token) belongs to. Use when all you have is a key from an app's config file."),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithOutputSchema[projectSummary](),
+			mcp.WithString("token",
+				mcp.Required(),
+				mcp.Description("The project API key to look up"),
+				mcp.MinLength(1),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleFindProjectByToken(ctx, clientFor(ctx), req)
 		},
 	)
 
@@ -53,6 +86,8 @@ func RegisterProjectTools(r *toolRegistrar, clientFor ClientFactory) {
 			mcp.WithDescription("Create a new Honeybadger project"),
 			mcp.WithReadOnlyHintAnnotation(false),
 			mcp.WithDestructiveHintAnnotation(true),
+			mcp.WithIdempotentHintAnnotation(false),
+			mcp.WithOpenWorldHintAnnotation(false),
 			mcp.WithString("account_id",
 				mcp.Description("The account ID to associate the project with. If omitted, the project is created in the first account your auth token has access to."),
 				mcp.MinLength(1),
@@ -95,6 +130,8 @@ func RegisterProjectTools(r *toolRegistrar, clientFor ClientFactory) {
 			mcp.WithDescription("Update an existing Honeybadger project"),
 			mcp.WithReadOnlyHintAnnotation(false),
 			mcp.WithDestructiveHintAnnotation(true),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
 			mcp.WithNumber("id",
 				mcp.Required(),
 				mcp.Description("The ID of the project to update"),
@@ -130,6 +167,31 @@ func RegisterProjectTools(r *toolRegistrar, clientFor ClientFactory) {
 		},
 	)
 
+	// get_project_settings_diff tool
+	r.AddTool(
+		mcp.NewTool("get_project_settings_diff",
+			mcp.WithTitleAnnotation("Get Project Settings Diff"),
+			mcp.WithDescription("Compare a project's current settings with a desired state and return each setting that differs (current and desired values), the ones that already match, and the update_project arguments that would apply the difference. Changes nothing, so it's safe for reviewing settings kept as code before applying them."),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithOutputSchema[projectSettingsDiff](),
+			mcp.WithNumber("project_id",
+				mcp.Required(),
+				mcp.Description("The ID of the project to compare"),
+				mcp.Min(1),
+			),
+			mcp.WithString("desired",
+				mcp.Required(),
+				mcp.Description("JSON object of the settings to compare, using update_project's argument names: name, resolve_errors_on_deploy, disable_public_links, user_url, source_url, purge_days, user_search_field. Settings left out aren't compared."),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleGetProjectSettingsDiff(ctx, clientFor(ctx), req)
+		},
+	)
+
 	// delete_project tool
 	r.AddTool(
 		mcp.NewTool("delete_project",
@@ -137,6 +199,8 @@ func RegisterProjectTools(r *toolRegistrar, clientFor ClientFactory) {
 			mcp.WithDescription("Delete a Honeybadger project"),
 			mcp.WithReadOnlyHintAnnotation(false),
 			mcp.WithDestructiveHintAnnotation(true),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
 			mcp.WithNumber("id",
 				mcp.Required(),
 				mcp.Description("The ID of the project to delete"),
@@ -152,9 +216,11 @@ func RegisterProjectTools(r *toolRegistrar, clientFor ClientFactory) {
 	r.AddTool(
 		mcp.NewTool("get_project_occurrence_counts",
 			mcp.WithTitleAnnotation("Get Project Occurrence Counts"),
-			mcp.WithDescription("Get occurrence counts for all projects or a specific project"),
+			mcp.WithDescription("Get occurrence counts for all projects or a specific project as [unix_timestamp, count] pairs, with a total per series. Use bucket/aggregate to compress long series"),
 			mcp.WithReadOnlyHintAnnotation(true),
 			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
 			mcp.WithNumber("project_id",
 				mcp.Description("Optional project ID to get occurrence counts for a specific project"),
 				mcp.Min(1),
@@ -166,6 +232,23 @@ func RegisterProjectTools(r *toolRegistrar, clientFor ClientFactory) {
 			mcp.WithString("environment",
 				mcp.Description("Optional environment name to filter results"),
 			),
+			mcp.WithString("bucket",
+				mcp.Description("Optional bucket size to downsample the series into, e.g. '6h', '1d', '1w' (units: m, h, d, w). Buckets are aligned to midnight in timezone"),
+			),
+			mcp.WithString("aggregate",
+				mcp.Description("How counts within a bucket are combined: 'sum' (default), 'avg', or 'max'. Requires bucket"),
+				mcp.Enum("sum", "avg", "max"),
+			),
+			mcp.WithString("timezone",
+				mcp.Description(timezoneDescription),
+			),
+			mcp.WithBoolean("include_project_names",
+				mcp.Description("In all-projects mode, return a list of {project_id, project_name, counts, total} entries instead of series keyed by project ID. Defaults to false"),
+			),
+			mcp.WithString("render",
+				mcp.Description(renderDescription),
+				mcp.Enum(renderJSON, renderASCIIChart),
+			),
 		),
 		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			return handleGetProjectOccurrenceCounts(ctx, clientFor(ctx), req)
@@ -179,6 +262,8 @@ func RegisterProjectTools(r *toolRegistrar, clientFor ClientFactory) {
 			mcp.WithDescription("Get a list of integrations (channels) for a Honeybadger project"),
 			mcp.WithReadOnlyHintAnnotation(true),
 			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
 			mcp.WithNumber("project_id",
 				mcp.Required(),
 				mcp.Description("The ID of the project to get integrations for"),
@@ -197,6 +282,8 @@ func RegisterProjectTools(r *toolRegistrar, clientFor ClientFactory) {
 			mcp.WithDescription("Get report data for a Honeybadger project"),
 			mcp.WithReadOnlyHintAnnotation(true),
 			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
 			mcp.WithNumber("project_id",
 				mcp.Required(),
 				mcp.Description("The ID of the project to get report data for"),
@@ -208,19 +295,63 @@ func RegisterProjectTools(r *toolRegistrar, clientFor ClientFactory) {
 				mcp.Enum("notices_by_class", "notices_by_location", "notices_by_user", "notices_per_day"),
 			),
 			mcp.WithString("start",
-				mcp.Description("Start date/time in ISO 8601 format for the beginning of the reporting period"),
+				mcp.Description("Start of the reporting period"+timestampHint),
 			),
 			mcp.WithString("stop",
-				mcp.Description("Stop date/time in ISO 8601 format for the end of the reporting period"),
+				mcp.Description("End of the reporting period"+timestampHint),
 			),
 			mcp.WithString("environment",
 				mcp.Description("Optional environment name to filter results"),
 			),
+			mcp.WithString("timezone",
+				mcp.Description(timezoneDescription),
+			),
+			mcp.WithString("render",
+				mcp.Description(renderDescription+". ascii_chart applies to notices_per_day only"),
+				mcp.Enum(renderJSON, renderASCIIChart),
+			),
 		),
 		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 			return handleGetProjectReport(ctx, clientFor(ctx), req)
 		},
 	)
+
+	// generate_error_digest tool
+	r.AddTool(
+		mcp.NewTool("generate_error_digest",
+			mcp.WithTitleAnnotation("Generate Error Digest"),
+			mcp.WithDescription("Build a digest of error activity over a date range (default the last 7 days) for one project, or every project in an account: per project, notices per day, notices by error class, and the most frequent unresolved faults, plus the total. The structured result is meant to be summarized into a weekly report for Slack or email."),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithOutputSchema[errorDigest](),
+			mcp.WithNumber("project_id",
+				mcp.Description("The ID of the project to digest; omit to digest every project"),
+				mcp.Min(1),
+			),
+			mcp.WithString("account_id",
+				mcp.Description("Without project_id, only digest this account's projects (see check_connection for account IDs)"),
+			),
+			mcp.WithString("start",
+				mcp.Description("Start of the digest period (default 7 days before stop)"+timestampHint),
+			),
+			mcp.WithString("stop",
+				mcp.Description("End of the digest period (default now)"+timestampHint),
+			),
+			mcp.WithString("environment",
+				mcp.Description("Optional environment name to filter results"),
+			),
+			mcp.WithNumber("top_faults",
+				mcp.Description(fmt.Sprintf("How many unresolved faults to list per project, most frequent first (default %d, max %d)", defaultDigestTopFaults, maxPageLimit)),
+				mcp.Min(1),
+				mcp.Max(maxPageLimit),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleGenerateErrorDigest(ctx, clientFor(ctx), req)
+		},
+	)
 }
 
 // projectSummary is a lightweight representation of a project for list results.
@@ -244,6 +375,19 @@ type projectSummaryResponse struct {
 	Links   hbapi.PaginationLinks `json:"links"`
 }
 
+func summarizeProject(p hbapi.Project) projectSummary {
+	return projectSummary{
+		ID:                   p.ID,
+		Name:                 p.Name,
+		Token:                p.Token,
+		Active:               p.Active,
+		CreatedAt:            p.CreatedAt,
+		LastNoticeAt:         p.LastNoticeAt,
+		FaultCount:           p.FaultCount,
+		UnresolvedFaultCount: p.UnresolvedFaultCount,
+	}
+}
+
 func handleListProjects(ctx context.Context, client *hbapi.Client, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	// Extract account_id parameter (optional)
 	var response *hbapi.ProjectsResponse
@@ -262,19 +406,19 @@ func handleListProjects(ctx context.Context, client *hbapi.Client, req mcp.CallT
 
 	// Map to lightweight summaries to reduce token usage.
 	// Full project details are available via get_project.
-	summaries := make([]projectSummary, len(response.Results))
-	for i, p := range response.Results {
-		summaries[i] = projectSummary{
-			ID:                   p.ID,
-			Name:                 p.Name,
-			Token:                p.Token,
-			Active:               p.Active,
-			CreatedAt:            p.CreatedAt,
-			LastNoticeAt:         p.LastNoticeAt,
-			FaultCount:           p.FaultCount,
-			UnresolvedFaultCount: p.UnresolvedFaultCount,
+	var notes toolNotes
+	summaries := make([]projectSummary, 0, len(response.Results))
+	for _, p := range response.Results {
+		if projectAllowed(ctx, p.ID) {
+			summaries = append(summaries, summarizeProject(p))
 		}
 	}
+	if hidden := len(response.Results) - len(summaries); hidden > 0 {
+		notes.warnf("omitted %d projects outside the projects this server is allowed to access", hidden)
+	}
+	if response.Links.Next != "" {
+		notes.warnf("results truncated: only the first %d projects were returned; pass account_id to narrow the list", len(response.Results))
+	}
 
 	jsonBytes, err := json.Marshal(projectSummaryResponse{
 		Results: summaries,
@@ -284,10 +428,12 @@ func handleListProjects(ctx context.Context, client *hbapi.Client, req mcp.CallT
 		return mcp.NewToolResultError("Failed to marshal response"), nil
 	}
 
-	return mcp.NewToolResultText(string(jsonBytes)), nil
+	return withNotes(mcp.NewToolResultText(string(jsonBytes)), &notes), nil
 }
 
-func handleGetProject(ctx context.Context, client *hbapi.Client, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+// handleGetProject gets a project. With lookup, a 404 is followed by a
+// search of the token's accounts for the project (see projectNotFoundHint).
+func handleGetProject(ctx context.Context, client *hbapi.Client, req mcp.CallToolRequest, lookup bool) (*mcp.CallToolResult, error) {
 	id := req.GetInt("id", 0)
 	if id == 0 {
 		return mcp.NewToolResultError("id is required"), nil
@@ -295,7 +441,11 @@ func handleGetProject(ctx context.Context, client *hbapi.Client, req mcp.CallToo
 
 	project, err := client.Projects.Get(ctx, id)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to get project: %v", err)), nil
+		hint := ""
+		if lookup {
+			hint = projectNotFoundHint(ctx, client, err, id)
+		}
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to get project: %v%s", err, hint)), nil
 	}
 
 	// Return JSON response
@@ -307,6 +457,39 @@ func handleGetProject(ctx context.Context, client *hbapi.Client, req mcp.CallToo
 	return mcp.NewToolResultText(string(jsonBytes)), nil
 }
 
+func handleFindProjectByToken(ctx context.Context, client *hbapi.Client, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	token := strings.TrimSpace(req.GetString("token", ""))
+	if token == "" {
+		return mcp.NewToolResultError("token is required"), nil
+	}
+
+	// There's no lookup endpoint, so match against every visible project.
+	response, err := client.Projects.ListAll(ctx)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to list projects: %v", err)), nil
+	}
+
+	for _, p := range response.Results {
+		if p.Token == token {
+			if !projectAllowed(ctx, p.ID) {
+				return mcp.NewToolResultError(projectOutOfScope(p.ID)), nil
+			}
+			// Return JSON response
+			jsonBytes, err := json.Marshal(summarizeProject(p))
+			if err != nil {
+				return mcp.NewToolResultError("Failed to marshal response"), nil
+			}
+			return mcp.NewToolResultText(string(jsonBytes)), nil
+		}
+	}
+
+	msg := fmt.Sprintf("No project with that API key among the %d projects this auth token can access. The key may belong to another account, or the project may have been deleted.", len(response.Results))
+	if response.Links.Next != "" {
+		msg += " Only the first page of projects was searched."
+	}
+	return mcp.NewToolResultError(msg), nil
+}
+
 func handleCreateProject(ctx context.Context, client *hbapi.Client, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	// Optional: the API associates the project with the token's first
 	// accessible account when account_id is absent.
@@ -420,6 +603,36 @@ func handleDeleteProject(ctx context.Context, client *hbapi.Client, req mcp.Call
 	return mcp.NewToolResultText(string(jsonBytes)), nil
 }
 
+// occurrenceSeries is one project's occurrence time series plus its total,
+// so callers don't have to sum hundreds of pairs themselves.
+type occurrenceSeries struct {
+	Counts []hbapi.ProjectOccurrenceCount `json:"counts"`
+	Total  int64                          `json:"total"`
+}
+
+// allOccurrenceSeries is the all-projects response: series keyed by project
+// ID, as returned by the API, plus an account-wide total.
+type allOccurrenceSeries struct {
+	Projects map[string]occurrenceSeries `json:"projects"`
+	Total    int64                       `json:"total"`
+}
+
+// projectOccurrenceSeries is one project's series in the
+// include_project_names response.
+type projectOccurrenceSeries struct {
+	ProjectID   int    `json:"project_id"`
+	ProjectName string `json:"project_name"`
+	occurrenceSeries
+}
+
+// namedOccurrenceSeries is the all-projects response with
+// include_project_names: series joined with the projects list and ordered
+// by project ID.
+type namedOccurrenceSeries struct {
+	Projects []projectOccurrenceSeries `json:"projects"`
+	Total    int64                     `json:"total"`
+}
+
 func handleGetProjectOccurrenceCounts(ctx context.Context, client *hbapi.Client, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	// Build options struct using typed getters
 	options := hbapi.ProjectGetOccurrenceCountsOptions{
@@ -427,30 +640,192 @@ func handleGetProjectOccurrenceCounts(ctx context.Context, client *hbapi.Client,
 		Environment: req.GetString("environment", ""),
 	}
 
+	var bucket time.Duration
+	if raw := req.GetString("bucket", ""); raw != "" {
+		var ok bool
+		bucket, ok = parseSpan(raw)
+		if !ok || bucket < time.Minute {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid bucket %q: use a size like '6h', '1d', or '1w' (minimum 1m)", raw)), nil
+		}
+	}
+	aggregate := req.GetString("aggregate", "")
+	if aggregate != "" && bucket == 0 {
+		return mcp.NewToolResultError("aggregate requires bucket"), nil
+	}
+	switch aggregate {
+	case "":
+		aggregate = "sum"
+	case "sum", "avg", "max":
+	default:
+		return mcp.NewToolResultError(fmt.Sprintf("invalid aggregate %q: use 'sum', 'avg', or 'max'", aggregate)), nil
+	}
+	render, errResult := renderArg(req)
+	if errResult != nil {
+		return errResult, nil
+	}
+	loc, err := timezoneArg(req)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	var notes toolNotes
+	if loc != time.UTC && options.Period != "" && options.Period != "hour" {
+		notes.warnf("the API groups %s periods by UTC; use period hour with bucket 1d to count days in %s", options.Period, loc)
+	}
+
+	summarize := func(counts []hbapi.ProjectOccurrenceCount) occurrenceSeries {
+		series := occurrenceSeries{Counts: counts}
+		for _, c := range counts {
+			series.Total += c[1]
+		}
+		if bucket > 0 {
+			series.Counts = bucketOccurrences(counts, int64(bucket/time.Second), aggregate, loc)
+		}
+		return series
+	}
+
 	// Check if project_id is provided
 	var result interface{}
-	var err error
 
 	projectID := req.GetInt("project_id", 0)
 	if projectID > 0 {
 		// Get occurrence counts for specific project
-		result, err = client.Projects.GetOccurrenceCounts(ctx, projectID, options)
+		counts, err := client.Projects.GetOccurrenceCounts(ctx, projectID, options)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to get occurrence counts: %v", err)), nil
+		}
+		series := summarize(counts)
+		if render == renderASCIIChart {
+			return withNotes(mcp.NewToolResultText(renderSparkline(fmt.Sprintf("Project %d occurrences", projectID), occurrenceChartPoints(series.Counts, loc))), &notes), nil
+		}
+		result = series
 	} else {
 		// Get occurrence counts for all projects
-		result, err = client.Projects.GetAllOccurrenceCounts(ctx, options)
+		all, err := client.Projects.GetAllOccurrenceCounts(ctx, options)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to get occurrence counts: %v", err)), nil
+		}
+		response := allOccurrenceSeries{Projects: make(map[string]occurrenceSeries, len(all))}
+		for id, counts := range all {
+			if n, err := strconv.Atoi(id); err == nil && !projectAllowed(ctx, n) {
+				continue
+			}
+			series := summarize(counts)
+			response.Projects[id] = series
+			response.Total += series.Total
+		}
+		if req.GetBool("include_project_names", false) {
+			return namedOccurrenceCounts(ctx, client, response, render, loc, &notes)
+		}
+		if render == renderASCIIChart {
+			ids := slices.Sorted(maps.Keys(response.Projects))
+			charts := make([]string, 0, len(ids)+1)
+			for _, id := range ids {
+				charts = append(charts, renderSparkline("Project "+id+" occurrences", occurrenceChartPoints(response.Projects[id].Counts, loc)))
+			}
+			charts = append(charts, fmt.Sprintf("Total across projects: %d", response.Total))
+			return withNotes(mcp.NewToolResultText(strings.Join(charts, "\n\n")), &notes), nil
+		}
+		result = response
 	}
 
+	// Return JSON response
+	jsonBytes, err := json.Marshal(result)
 	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to get occurrence counts: %v", err)), nil
+		return mcp.NewToolResultError("Failed to marshal response"), nil
+	}
+
+	return withNotes(mcp.NewToolResultText(string(jsonBytes)), &notes), nil
+}
+
+// namedOccurrenceCounts labels the all-projects series with project names
+// from the projects list. A series whose project isn't in the list keeps an
+// empty name, with a warning added to notes.
+func namedOccurrenceCounts(ctx context.Context, client *hbapi.Client, all allOccurrenceSeries, render string, zone *time.Location, notes *toolNotes) (*mcp.CallToolResult, error) {
+	projects, err := client.Projects.ListAll(ctx)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to list projects: %v", err)), nil
+	}
+	names := make(map[int]string, len(projects.Results))
+	for _, p := range projects.Results {
+		names[p.ID] = p.Name
+	}
+
+	response := namedOccurrenceSeries{Projects: make([]projectOccurrenceSeries, 0, len(all.Projects)), Total: all.Total}
+	for id, series := range all.Projects {
+		n, _ := strconv.Atoi(id)
+		response.Projects = append(response.Projects, projectOccurrenceSeries{ProjectID: n, ProjectName: names[n], occurrenceSeries: series})
+	}
+	slices.SortFunc(response.Projects, func(a, b projectOccurrenceSeries) int {
+		return cmp.Compare(a.ProjectID, b.ProjectID)
+	})
+	var unnamed []string
+	for _, p := range response.Projects {
+		if _, ok := names[p.ProjectID]; !ok {
+			unnamed = append(unnamed, strconv.Itoa(p.ProjectID))
+		}
+	}
+	if len(unnamed) > 0 {
+		notes.warnf("no name found for projects %s in the projects list", strings.Join(unnamed, ", "))
+	}
+
+	if render == renderASCIIChart {
+		charts := make([]string, 0, len(response.Projects)+1)
+		for _, p := range response.Projects {
+			label := fmt.Sprintf("Project %d occurrences", p.ProjectID)
+			if p.ProjectName != "" {
+				label = fmt.Sprintf("%s (%d) occurrences", p.ProjectName, p.ProjectID)
+			}
+			charts = append(charts, renderSparkline(label, occurrenceChartPoints(p.Counts, zone)))
+		}
+		charts = append(charts, fmt.Sprintf("Total across projects: %d", response.Total))
+		return withNotes(mcp.NewToolResultText(strings.Join(charts, "\n\n")), notes), nil
 	}
 
 	// Return JSON response
-	jsonBytes, err := json.Marshal(result)
+	jsonBytes, err := json.Marshal(response)
 	if err != nil {
 		return mcp.NewToolResultError("Failed to marshal response"), nil
 	}
 
-	return mcp.NewToolResultText(string(jsonBytes)), nil
+	return withNotes(mcp.NewToolResultText(string(jsonBytes)), notes), nil
+}
+
+// bucketOccurrences downsamples [timestamp, count] pairs into buckets of
+// size seconds aligned to the Unix epoch as seen in loc (so to loc's
+// midnight for day-sized buckets), combining counts with aggregate. Each
+// output pair is stamped with its bucket's start. avg is integer-rounded to
+// keep the pair shape. The API returns pairs in ascending time order, which
+// this relies on.
+func bucketOccurrences(counts []hbapi.ProjectOccurrenceCount, size int64, aggregate string, loc *time.Location) []hbapi.ProjectOccurrenceCount {
+	result := []hbapi.ProjectOccurrenceCount{}
+	var n int64
+	flush := func() {
+		if n > 0 && aggregate == "avg" {
+			last := &result[len(result)-1]
+			last[1] = int64(math.Round(float64(last[1]) / float64(n)))
+		}
+	}
+	for _, c := range counts {
+		_, offset := time.Unix(c[0], 0).In(loc).Zone()
+		local := c[0] + int64(offset)
+		start := local - ((local%size)+size)%size - int64(offset)
+		if len(result) == 0 || result[len(result)-1][0] != start {
+			flush()
+			result = append(result, hbapi.ProjectOccurrenceCount{start, c[1]})
+			n = 1
+			continue
+		}
+		last := &result[len(result)-1]
+		switch aggregate {
+		case "max":
+			last[1] = max(last[1], c[1])
+		default: // sum and avg accumulate; avg divides on flush
+			last[1] += c[1]
+		}
+		n++
+	}
+	flush()
+	return result
 }
 
 func handleGetProjectIntegrations(ctx context.Context, client *hbapi.Client, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -499,23 +874,70 @@ func handleGetProjectReport(ctx context.Context, client *hbapi.Client, req mcp.C
 		reportType = hbapi.ProjectReportType(reportStr) // Let the API handle unknown types
 	}
 
+	render, errResult := renderArg(req)
+	if errResult != nil {
+		return errResult, nil
+	}
+	if render == renderASCIIChart && reportType != hbapi.ProjectNoticesPerDay {
+		return mcp.NewToolResultError("render ascii_chart only applies to the notices_per_day report"), nil
+	}
+
+	loc, err := timezoneArg(req)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	window, err := resolveTimeWindow(req, "start", "stop", time.Now(), loc)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	var notes toolNotes
+	notes.addResolved(window.Resolved)
+	if loc != time.UTC && reportType == hbapi.ProjectNoticesPerDay {
+		notes.warnf("notices_per_day counts UTC days; use get_project_occurrence_counts with bucket 1d and timezone to count days in %s", loc)
+	}
+
 	// Build options struct using typed getters
 	options := hbapi.ProjectGetReportOptions{
-		Start:       parseTimestamp(req.GetString("start", "")),
-		Stop:        parseTimestamp(req.GetString("stop", "")),
 		Environment: req.GetString("environment", ""),
 	}
+	if !window.After.IsZero() {
+		options.Start = &window.After
+	}
+	if !window.Before.IsZero() {
+		options.Stop = &window.Before
+	}
 
 	report, err := client.Projects.GetReport(ctx, projectID, reportType, options)
 	if err != nil {
 		return mcp.NewToolResultError(fmt.Sprintf("Failed to get project report: %v", err)), nil
 	}
 
+	if render == renderASCIIChart {
+		points, err := reportChartPoints(report)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to chart project report: %v", err)), nil
+		}
+		return withNotes(mcp.NewToolResultText(renderSparkline(fmt.Sprintf("Project %d notices per day", projectID), points)), &notes), nil
+	}
+	if loc != time.UTC {
+		// notices_per_day rows lead with their day's UTC timestamp.
+		for _, row := range report {
+			if len(row) == 0 {
+				continue
+			}
+			if s, ok := row[0].(string); ok {
+				if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+					row[0] = t.In(loc).Format(time.RFC3339)
+				}
+			}
+		}
+	}
+
 	// Return JSON response
 	jsonBytes, err := json.Marshal(report)
 	if err != nil {
 		return mcp.NewToolResultError("Failed to marshal response"), nil
 	}
 
-	return mcp.NewToolResultText(string(jsonBytes)), nil
+	return withNotes(mcp.NewToolResultText(string(jsonBytes)), &notes), nil
 }
@@ -320,7 +320,7 @@ func TestHandleGetProject(t *testing.T) {
 		},
 	}
 
-	result, err := handleGetProject(context.Background(), client, req)
+	result, err := handleGetProject(context.Background(), client, req, false)
 	if err != nil {
 		t.Fatalf("handleGetProject() error = %v", err)
 	}
@@ -360,7 +360,7 @@ func TestHandleGetProject_MissingID(t *testing.T) {
 		},
 	}
 
-	result, err := handleGetProject(context.Background(), client, req)
+	result, err := handleGetProject(context.Background(), client, req, false)
 	if err != nil {
 		t.Fatalf("handleGetProject() error = %v", err)
 	}
@@ -387,7 +387,7 @@ func TestHandleGetProject_InvalidID(t *testing.T) {
 		},
 	}
 
-	result, err := handleGetProject(context.Background(), client, req)
+	result, err := handleGetProject(context.Background(), client, req, false)
 	if err != nil {
 		t.Fatalf("handleGetProject() error = %v", err)
 	}
@@ -401,6 +401,79 @@ func TestHandleGetProject_InvalidID(t *testing.T) {
 	}
 }
 
+func TestHandleFindProjectByToken(t *testing.T) {
+	mockResponse := `{"results": [{"id": 1, "name": "Web", "token": "web-key"}, {"id": 2, "name": "API", "token": "api-key"}], "links": {}}`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/projects" {
+			t.Errorf("expected path /v2/projects, got %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(mockResponse))
+	}))
+	defer server.Close()
+
+	client := hbapi.NewClient().
+		WithBaseURL(server.URL).
+		WithAuthToken("test-token")
+
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Arguments: map[string]interface{}{
+				"token": " api-key ",
+			},
+		},
+	}
+
+	result, err := handleFindProjectByToken(context.Background(), client, req)
+	if err != nil {
+		t.Fatalf("handleFindProjectByToken() error = %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected successful result, got error: %s", getResultText(result))
+	}
+
+	var project projectSummary
+	if err := json.Unmarshal([]byte(getResultText(result)), &project); err != nil {
+		t.Fatalf("Response should be a project summary: %v", err)
+	}
+	if project.ID != 2 || project.Name != "API" {
+		t.Errorf("expected project 2 (API), got %d (%s)", project.ID, project.Name)
+	}
+}
+
+func TestHandleFindProjectByToken_NotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"results": [{"id": 1, "name": "Web", "token": "web-key"}], "links": {"next": "/v2/projects?page=2"}}`))
+	}))
+	defer server.Close()
+
+	client := hbapi.NewClient().
+		WithBaseURL(server.URL).
+		WithAuthToken("test-token")
+
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Arguments: map[string]interface{}{
+				"token": "unknown-key",
+			},
+		},
+	}
+
+	result, err := handleFindProjectByToken(context.Background(), client, req)
+	if err != nil {
+		t.Fatalf("handleFindProjectByToken() error = %v", err)
+	}
+	if !result.IsError {
+		t.Fatal("expected error result")
+	}
+	text := getResultText(result)
+	if !strings.Contains(text, "among the 1 projects") || !strings.Contains(text, "first page") {
+		t.Errorf("unexpected error message: %s", text)
+	}
+}
+
 func TestHandleCreateProject(t *testing.T) {
 	mockResponse := `{"id": 456, "name": "New Project", "active": true, "created_at": "2024-01-01T00:00:00Z", "token": "secret789", "fault_count": 0, "unresolved_fault_count": 0, "environments": [], "owner": {"id": 1, "email": "user@example.com", "name": "User 1"}, "sites": [], "teams": [], "users": []}`
 
@@ -890,3 +963,384 @@ func TestHandleGetProjectReport_WithOptions(t *testing.T) {
 		t.Fatalf("expected successful result, got error: %s", getResultText(result))
 	}
 }
+
+func TestHandleGetProjectOccurrenceCounts(t *testing.T) {
+	mockResponse := `[[1704067200, 5], [1704070800, 3], [1704074400, 0]]`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/projects/123/occurrences" {
+			t.Errorf("expected path /v2/projects/123/occurrences, got %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(mockResponse))
+	}))
+	defer server.Close()
+
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Arguments: map[string]interface{}{
+				"project_id": float64(123),
+			},
+		},
+	}
+
+	result, err := handleGetProjectOccurrenceCounts(context.Background(), client, req)
+	if err != nil {
+		t.Fatalf("handleGetProjectOccurrenceCounts() error = %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected successful result, got error: %s", getResultText(result))
+	}
+
+	var response occurrenceSeries
+	if err := json.Unmarshal([]byte(getResultText(result)), &response); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if response.Total != 8 {
+		t.Errorf("expected total 8, got %d", response.Total)
+	}
+	if len(response.Counts) != 3 {
+		t.Errorf("expected raw series of 3 pairs, got %d", len(response.Counts))
+	}
+}
+
+func TestHandleGetProjectOccurrenceCounts_Bucketed(t *testing.T) {
+	// Four hourly points across two 2h buckets starting at 2024-01-01T00:00Z.
+	mockResponse := `[[1704067200, 5], [1704070800, 3], [1704074400, 1], [1704078000, 6]]`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(mockResponse))
+	}))
+	defer server.Close()
+
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+
+	cases := []struct {
+		aggregate string
+		want      []hbapi.ProjectOccurrenceCount
+	}{
+		{"", []hbapi.ProjectOccurrenceCount{{1704067200, 8}, {1704074400, 7}}},
+		{"sum", []hbapi.ProjectOccurrenceCount{{1704067200, 8}, {1704074400, 7}}},
+		{"avg", []hbapi.ProjectOccurrenceCount{{1704067200, 4}, {1704074400, 4}}},
+		{"max", []hbapi.ProjectOccurrenceCount{{1704067200, 5}, {1704074400, 6}}},
+	}
+	for _, c := range cases {
+		t.Run("aggregate="+c.aggregate, func(t *testing.T) {
+			args := map[string]interface{}{
+				"project_id": float64(123),
+				"bucket":     "2h",
+			}
+			if c.aggregate != "" {
+				args["aggregate"] = c.aggregate
+			}
+			req := mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: args}}
+
+			result, err := handleGetProjectOccurrenceCounts(context.Background(), client, req)
+			if err != nil {
+				t.Fatalf("handleGetProjectOccurrenceCounts() error = %v", err)
+			}
+			if result.IsError {
+				t.Fatalf("expected successful result, got error: %s", getResultText(result))
+			}
+
+			var response occurrenceSeries
+			if err := json.Unmarshal([]byte(getResultText(result)), &response); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			if response.Total != 15 {
+				t.Errorf("total should sum raw counts regardless of aggregate, got %d", response.Total)
+			}
+			if fmt.Sprint(response.Counts) != fmt.Sprint(c.want) {
+				t.Errorf("expected %v, got %v", c.want, response.Counts)
+			}
+		})
+	}
+}
+
+func TestHandleGetProjectOccurrenceCounts_AllProjects(t *testing.T) {
+	mockResponse := `{"1": [[1704067200, 2], [1704070800, 3]], "2": [[1704067200, 10]]}`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/projects/occurrences" {
+			t.Errorf("expected path /v2/projects/occurrences, got %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(mockResponse))
+	}))
+	defer server.Close()
+
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Arguments: map[string]interface{}{
+				"bucket": "1d",
+			},
+		},
+	}
+
+	result, err := handleGetProjectOccurrenceCounts(context.Background(), client, req)
+	if err != nil {
+		t.Fatalf("handleGetProjectOccurrenceCounts() error = %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected successful result, got error: %s", getResultText(result))
+	}
+
+	var response allOccurrenceSeries
+	if err := json.Unmarshal([]byte(getResultText(result)), &response); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if response.Total != 15 {
+		t.Errorf("expected account total 15, got %d", response.Total)
+	}
+	if got := response.Projects["1"]; got.Total != 5 || len(got.Counts) != 1 || got.Counts[0][1] != 5 {
+		t.Errorf("unexpected series for project 1: %+v", got)
+	}
+}
+
+func TestHandleGetProjectOccurrenceCounts_ProjectNames(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v2/projects/occurrences":
+			_, _ = w.Write([]byte(`{"12": [[1704067200, 2]], "3": [[1704067200, 10]]}`))
+		case "/v2/projects":
+			_, _ = w.Write([]byte(`{"results": [{"id": 3, "name": "API"}], "links": {}}`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Arguments: map[string]interface{}{
+				"include_project_names": true,
+			},
+		},
+	}
+
+	result, err := handleGetProjectOccurrenceCounts(context.Background(), client, req)
+	if err != nil {
+		t.Fatalf("handleGetProjectOccurrenceCounts() error = %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected successful result, got error: %s", getResultText(result))
+	}
+
+	var response namedOccurrenceSeries
+	if err := json.Unmarshal([]byte(getResultText(result)), &response); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if response.Total != 12 || len(response.Projects) != 2 {
+		t.Fatalf("unexpected response: %+v", response)
+	}
+	if got := response.Projects[0]; got.ProjectID != 3 || got.ProjectName != "API" || got.Total != 10 {
+		t.Errorf("expected project 3 named first, got %+v", got)
+	}
+	if got := response.Projects[1]; got.ProjectID != 12 || got.ProjectName != "" || got.Total != 2 {
+		t.Errorf("expected project 12 unnamed, got %+v", got)
+	}
+	if notes := result.Content[len(result.Content)-1].(mcp.TextContent).Text; !strings.Contains(notes, "no name found for projects 12") {
+		t.Errorf("expected a warning for the unnamed project, got %s", notes)
+	}
+}
+
+func TestHandleGetProjectOccurrenceCounts_InvalidArguments(t *testing.T) {
+	cases := []struct {
+		name string
+		args map[string]interface{}
+		want string
+	}{
+		{"bad bucket", map[string]interface{}{"bucket": "soon"}, "invalid bucket"},
+		{"bucket too small", map[string]interface{}{"bucket": "30s"}, "invalid bucket"},
+		{"aggregate without bucket", map[string]interface{}{"aggregate": "max"}, "aggregate requires bucket"},
+		{"unknown aggregate", map[string]interface{}{"bucket": "1h", "aggregate": "median"}, "invalid aggregate"},
+		{"unknown render", map[string]interface{}{"render": "svg"}, "invalid render"},
+		{"unknown timezone", map[string]interface{}{"timezone": "Mars/Olympus"}, "invalid timezone"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			client := hbapi.NewClient().WithBaseURL("http://127.0.0.1:0").WithAuthToken("test-token")
+			req := mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: c.args}}
+
+			result, err := handleGetProjectOccurrenceCounts(context.Background(), client, req)
+			if err != nil {
+				t.Fatalf("handleGetProjectOccurrenceCounts() error = %v", err)
+			}
+			if !result.IsError {
+				t.Fatal("expected error result")
+			}
+			if text := getResultText(result); !strings.Contains(text, c.want) {
+				t.Errorf("expected %q in error, got %q", c.want, text)
+			}
+		})
+	}
+}
+
+func TestHandleGetProjectOccurrenceCounts_ASCIIChart(t *testing.T) {
+	mockResponse := `[[1704067200, 2], [1704070800, 0], [1704074400, 8], [1704078000, 4]]`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(mockResponse))
+	}))
+	defer server.Close()
+
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Arguments: map[string]interface{}{
+				"project_id": float64(123),
+				"render":     "ascii_chart",
+			},
+		},
+	}
+
+	result, err := handleGetProjectOccurrenceCounts(context.Background(), client, req)
+	if err != nil {
+		t.Fatalf("handleGetProjectOccurrenceCounts() error = %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected successful result, got error: %s", getResultText(result))
+	}
+	want := "Project 123 occurrences: 2024-01-01 00:00 UTC to 2024-01-01 03:00 UTC, 4 points\n" +
+		"▂▁█▄\n" +
+		"total 14, min 0 (2024-01-01 01:00 UTC), max 8 (2024-01-01 02:00 UTC), last 4"
+	if got := getResultText(result); got != want {
+		t.Errorf("expected chart:\n%s\ngot:\n%s", want, got)
+	}
+}
+
+func TestHandleGetProjectOccurrenceCounts_Timezone(t *testing.T) {
+	// Hourly points from 2024-01-01T03:00Z to 06:00Z straddle midnight in
+	// New York (05:00Z).
+	mockResponse := `[[1704078000, 1], [1704081600, 2], [1704085200, 4], [1704088800, 8]]`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(mockResponse))
+	}))
+	defer server.Close()
+
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+	call := func(args map[string]interface{}) *mcp.CallToolResult {
+		args["project_id"] = float64(123)
+		result, err := handleGetProjectOccurrenceCounts(context.Background(), client, mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: args}})
+		if err != nil || result.IsError {
+			t.Fatalf("unexpected error: %v %s", err, getResultText(result))
+		}
+		return result
+	}
+
+	var response occurrenceSeries
+	if err := json.Unmarshal([]byte(getResultText(call(map[string]interface{}{"bucket": "1d", "timezone": "America/New_York"}))), &response); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	want := []hbapi.ProjectOccurrenceCount{{1703998800, 3}, {1704085200, 12}}
+	if fmt.Sprint(response.Counts) != fmt.Sprint(want) {
+		t.Errorf("expected days split at New York midnight %v, got %v", want, response.Counts)
+	}
+
+	text := getResultText(call(map[string]interface{}{"bucket": "1d", "timezone": "America/New_York", "render": "ascii_chart"}))
+	if !strings.Contains(text, "2023-12-31 to 2024-01-01, 2 points") {
+		t.Errorf("expected chart labels in New York days, got %s", text)
+	}
+
+	result := call(map[string]interface{}{"period": "day", "timezone": "America/New_York"})
+	if notes := result.Content[len(result.Content)-1].(mcp.TextContent).Text; !strings.Contains(notes, "groups day periods by UTC") {
+		t.Errorf("expected a warning about UTC periods, got %s", notes)
+	}
+}
+
+func TestHandleGetProjectReport_Timezone(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[["2023-01-24T00:00:00.000000+00:00", 3161]]`))
+	}))
+	defer server.Close()
+
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+	req := mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"project_id": float64(123),
+		"report":     "notices_per_day",
+		"timezone":   "Asia/Tokyo",
+	}}}
+	result, err := handleGetProjectReport(context.Background(), client, req)
+	if err != nil || result.IsError {
+		t.Fatalf("unexpected error: %v %s", err, getResultText(result))
+	}
+	if text := getResultText(result); text != `[["2023-01-24T09:00:00+09:00",3161]]` {
+		t.Errorf("expected timestamps in Tokyo time, got %s", text)
+	}
+	if notes := result.Content[len(result.Content)-1].(mcp.TextContent).Text; !strings.Contains(notes, "notices_per_day counts UTC days") {
+		t.Errorf("expected a warning about UTC days, got %s", notes)
+	}
+}
+
+func TestHandleGetProjectReport_ASCIIChart(t *testing.T) {
+	mockResponse := `[["2023-01-24T00:00:00.000000+00:00", 3161], ["2023-01-25T00:00:00.000000+00:00", 2620]]`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(mockResponse))
+	}))
+	defer server.Close()
+
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+	call := func(report string) *mcp.CallToolResult {
+		req := mcp.CallToolRequest{
+			Params: mcp.CallToolParams{
+				Arguments: map[string]interface{}{
+					"project_id": float64(123),
+					"report":     report,
+					"render":     "ascii_chart",
+				},
+			},
+		}
+		result, err := handleGetProjectReport(context.Background(), client, req)
+		if err != nil {
+			t.Fatalf("handleGetProjectReport() error = %v", err)
+		}
+		return result
+	}
+
+	result := call("notices_per_day")
+	if result.IsError {
+		t.Fatalf("expected successful result, got error: %s", getResultText(result))
+	}
+	text := getResultText(result)
+	if !strings.Contains(text, "2023-01-24 to 2023-01-25, 2 points") || !strings.Contains(text, "total 5781") {
+		t.Errorf("unexpected chart: %s", text)
+	}
+
+	if result := call("notices_by_class"); !result.IsError {
+		t.Error("expected ascii_chart to be rejected for notices_by_class")
+	}
+}
+
+func TestRenderSparkline_Compresses(t *testing.T) {
+	points := make([]chartPoint, 150)
+	for i := range points {
+		points[i] = chartPoint{Label: fmt.Sprint(i), Value: float64(i % 10)}
+	}
+	lines := strings.Split(renderSparkline("Series", points), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected header, line, and summary, got %q", lines)
+	}
+	if !strings.Contains(lines[0], "150 points (3 per character)") {
+		t.Errorf("unexpected header %q", lines[0])
+	}
+	if n := len([]rune(lines[1])); n != 50 {
+		t.Errorf("expected 50 characters, got %d", n)
+	}
+	if lines[2] != "total 675, min 0 (0), max 9 (9), last 9" {
+		t.Errorf("unexpected summary %q", lines[2])
+	}
+}
@@ -0,0 +1,198 @@
+package hbmcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// RawClient sends requests to any Data API endpoint, for API surfaces
+// hbapi has no method for yet. Requests are built the way hbapi builds
+// them, with the /v2 prefix, auth, and JSON headers, and go through the
+// same http.Client, so retries, tracing, fixtures, and the rest apply.
+// Error responses are returned as *hbapi.APIError.
+type RawClient struct {
+	baseURL     string
+	authToken   string
+	bearerToken string
+	httpClient  *http.Client
+}
+
+// RawClientFactory returns the raw client for a request, like
+// ClientFactory does the hbapi one.
+type RawClientFactory func(ctx context.Context) *RawClient
+
+// NewRequest builds a request for path, which is relative to /v2 and may
+// carry a query string. A non-nil body is sent as JSON.
+func (c *RawClient) NewRequest(ctx context.Context, method, path string, body any) (*http.Request, error) {
+	var buf io.Reader
+	if body != nil {
+		jsonBody, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request body: %w", err)
+		}
+		buf = bytes.NewReader(jsonBody)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+"/v2"+path, buf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	if c.bearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.bearerToken)
+	} else if c.authToken != "" {
+		req.SetBasicAuth(c.authToken, "")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	return req, nil
+}
+
+// Do sends req and decodes the response body into v, if v is non-nil. An
+// empty body, as from a 204, leaves v as it was.
+func (c *RawClient) Do(ctx context.Context, req *http.Request, v any) error {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode >= 400 {
+		return hbapi.WrapError(resp, nil)
+	}
+
+	if v != nil {
+		if err := json.NewDecoder(resp.Body).Decode(v); err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+	}
+	return nil
+}
+
+// Raw builds and sends a request in one call.
+func (c *RawClient) Raw(ctx context.Context, method, path string, body, v any) error {
+	req, err := c.NewRequest(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+	return c.Do(ctx, req, v)
+}
+
+// rawAPIMethods are the methods raw_api_request accepts.
+var rawAPIMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+
+// rawAPIProjectPath matches paths under a single project.
+var rawAPIProjectPath = regexp.MustCompile(`^/projects/(\d+)(/|$)`)
+
+// RegisterRawAPITools registers the raw_api_request tool. It is opt-in
+// (--raw-api), since it can reach anything the token can.
+func RegisterRawAPITools(r *toolRegistrar, rawFor RawClientFactory) {
+	// raw_api_request tool
+	r.AddTool(
+		mcp.NewTool("raw_api_request",
+			mcp.WithTitleAnnotation("Raw API Request"),
+			mcp.WithDescription("Send a request to any Honeybadger Data API endpoint (https://docs.honeybadger.io/api/) and return the response body as is. Only for API features no other tool covers; prefer a dedicated tool when one fits, since they validate arguments and shape results. Paths are relative to /v2, e.g. '/projects/123/deploys?environment=production'."),
+			mcp.WithReadOnlyHintAnnotation(false),
+			mcp.WithDestructiveHintAnnotation(true),
+			mcp.WithIdempotentHintAnnotation(false),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithString("method",
+				mcp.Description("HTTP method (default GET)"),
+				mcp.Enum(rawAPIMethods...),
+			),
+			mcp.WithString("path",
+				mcp.Required(),
+				mcp.Description("Endpoint path relative to /v2, with an optional query string, e.g. '/projects/123/deploys?environment=production'"),
+			),
+			mcp.WithObject("body",
+				mcp.Description("JSON request body, for POST, PUT, and PATCH"),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleRawAPIRequest(ctx, rawFor(ctx), req)
+		},
+	)
+}
+
+func handleRawAPIRequest(ctx context.Context, client *RawClient, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	method := strings.ToUpper(req.GetString("method", http.MethodGet))
+	if !slices.Contains(rawAPIMethods, method) {
+		return mcp.NewToolResultError(fmt.Sprintf("method must be one of %s", strings.Join(rawAPIMethods, ", "))), nil
+	}
+
+	p, err := rawAPIPath(req.GetString("path", ""))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid path: %v", err)), nil
+	}
+	if projectScoped(ctx) {
+		m := rawAPIProjectPath.FindStringSubmatch(p)
+		if m == nil {
+			return mcp.NewToolResultError("With a project scope configured, raw_api_request can only reach paths under /projects/{id}"), nil
+		}
+		if id, _ := strconv.Atoi(m[1]); !projectAllowed(ctx, id) {
+			return mcp.NewToolResultError(projectOutOfScope(id)), nil
+		}
+	}
+
+	var body any
+	if b, ok := req.GetArguments()["body"]; ok && b != nil {
+		if method == http.MethodGet || method == http.MethodDelete {
+			return mcp.NewToolResultError(fmt.Sprintf("body can't be sent with %s", method)), nil
+		}
+		body = b
+	}
+
+	var response json.RawMessage
+	if err := client.Raw(ctx, method, p, body, &response); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to request %s %s: %v", method, p, err)), nil
+	}
+	if len(response) == 0 {
+		return mcp.NewToolResultText(fmt.Sprintf("%s %s succeeded with no response body", method, p)), nil
+	}
+
+	// Return JSON response
+	return mcp.NewToolResultText(string(response)), nil
+}
+
+// rawAPIPath checks that p is a path on the Data API, relative to /v2, and
+// returns it without a /v2 prefix the caller may have included.
+func rawAPIPath(p string) (string, error) {
+	p = strings.TrimSpace(p)
+	if p == "" {
+		return "", errors.New("path is required")
+	}
+	u, err := url.Parse(p)
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme != "" || u.Host != "" || u.Fragment != "" || !strings.HasPrefix(u.Path, "/") {
+		return "", fmt.Errorf("%q must be a path starting with /, like /projects/123/faults", p)
+	}
+	if path.Clean(u.Path) != u.Path {
+		return "", fmt.Errorf("%q must not contain empty, . or .. segments or a trailing /", p)
+	}
+	if u.Path == "/v2" || strings.HasPrefix(u.Path, "/v2/") {
+		p = strings.TrimPrefix(p, "/v2")
+	}
+	if p == "" || strings.HasPrefix(p, "?") {
+		return "", errors.New("path must name an endpoint")
+	}
+	return p, nil
+}
@@ -0,0 +1,100 @@
+package hbmcp
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/honeybadger-io/honeybadger-mcp-server/internal/config"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestHandleRawAPIRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, _, _ := r.BasicAuth(); user != "test-token" {
+			t.Errorf("expected the token as the Basic username, got %q", user)
+		}
+		switch r.Method + " " + r.URL.Path {
+		case "GET /v2/projects/1/deploys":
+			if env := r.URL.Query().Get("environment"); env != "production" {
+				t.Errorf("expected the query string passed through, got %q", env)
+			}
+			_, _ = w.Write([]byte(`{"results": [{"id": 5, "environment": "production"}]}`))
+		case "POST /v2/projects/1/sites":
+			body, _ := io.ReadAll(r.Body)
+			if string(body) != `{"site":{"name":"Home"}}` {
+				t.Errorf("unexpected body %s", body)
+			}
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id": "abc"}`))
+		case "DELETE /v2/projects/1/sites/abc":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors": "Not found"}`))
+		}
+	}))
+	defer server.Close()
+
+	client := &RawClient{baseURL: server.URL, authToken: "test-token", httpClient: http.DefaultClient}
+	tests := []struct {
+		name      string
+		args      map[string]any
+		want      string
+		wantError bool
+	}{
+		{"get", map[string]any{"path": "/projects/1/deploys?environment=production"}, `{"results": [{"id": 5, "environment": "production"}]}`, false},
+		{"v2 prefix", map[string]any{"path": "/v2/projects/1/deploys?environment=production"}, `"id": 5`, false},
+		{"post", map[string]any{"method": "post", "path": "/projects/1/sites", "body": map[string]any{"site": map[string]any{"name": "Home"}}}, `{"id": "abc"}`, false},
+		{"no content", map[string]any{"method": "DELETE", "path": "/projects/1/sites/abc"}, "DELETE /projects/1/sites/abc succeeded with no response body", false},
+		{"api error", map[string]any{"path": "/projects/1/nothing"}, "Failed to request GET /projects/1/nothing: HTTP 404: Not found", true},
+		{"absolute URL", map[string]any{"path": "https://evil.example/v2/projects"}, "must be a path", true},
+		{"dot segments", map[string]any{"path": "/projects/1/../../../admin"}, "must not contain", true},
+		{"unknown method", map[string]any{"method": "HEAD", "path": "/projects"}, "method must be one of", true},
+		{"body with GET", map[string]any{"path": "/projects", "body": map[string]any{"a": 1}}, "body can't be sent with GET", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := mcp.CallToolRequest{}
+			req.Params.Arguments = tt.args
+			result, err := handleRawAPIRequest(context.Background(), client, req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.IsError != tt.wantError || !strings.Contains(getResultText(result), tt.want) {
+				t.Errorf("got error=%v %q, want error=%v containing %q", result.IsError, getResultText(result), tt.wantError, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleRawAPIRequest_ProjectScope(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	client := &RawClient{baseURL: server.URL, httpClient: http.DefaultClient}
+	ctx := context.WithValue(context.Background(), projectScopeKey{}, &config.Config{AllowedProjectIDs: []int{1}})
+	for path, want := range map[string]string{
+		"/projects/1/deploys": "",
+		"/projects/1":         "",
+		"/projects/2/deploys": "outside the projects",
+		"/projects/12":        "outside the projects",
+		"/projects":           "only reach paths under /projects/{id}",
+		"/teams":              "only reach paths under /projects/{id}",
+	} {
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = map[string]any{"path": path}
+		result, _ := handleRawAPIRequest(ctx, client, req)
+		if want == "" && result.IsError {
+			t.Errorf("%s: expected the request allowed, got %q", path, getResultText(result))
+		}
+		if want != "" && (!result.IsError || !strings.Contains(getResultText(result), want)) {
+			t.Errorf("%s: expected an error containing %q, got %q", path, want, getResultText(result))
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package hbmcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// decodeErrorMarker is how hbapi prefixes errors from decoding a response
+// body into its types.
+const decodeErrorMarker = "failed to decode response"
+
+// maxRawBody bounds the response body kept for the fallback; a larger body
+// is dropped and the decode error returned as is.
+const maxRawBody = 4 << 20
+
+type rawBodyKey struct{}
+
+// rawBody holds the last successful API response body of a tool call.
+type rawBody struct {
+	mu        sync.Mutex
+	body      []byte
+	truncated bool
+}
+
+func (b *rawBody) store(body []byte, truncated bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.body, b.truncated = body, truncated
+}
+
+func (b *rawBody) load() ([]byte, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.body, !b.truncated && len(b.body) > 0
+}
+
+// rawBodyTransport copies successful response bodies into the rawBody in
+// the request context, if there is one, as the client reads them.
+type rawBodyTransport struct {
+	base http.RoundTripper
+}
+
+func (t *rawBodyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	raw, _ := req.Context().Value(rawBodyKey{}).(*rawBody)
+	if err != nil || raw == nil || resp.StatusCode >= 400 {
+		return resp, err
+	}
+	resp.Body = &teeBody{ReadCloser: resp.Body, raw: raw}
+	return resp, nil
+}
+
+// teeBody records what is read from a response body, up to maxRawBody.
+// The decoder may stop before EOF, so it's stored on every read.
+type teeBody struct {
+	io.ReadCloser
+	raw       *rawBody
+	buf       bytes.Buffer
+	truncated bool
+}
+
+func (b *teeBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if room := maxRawBody - b.buf.Len(); n > room {
+		b.buf.Write(p[:room])
+		b.truncated = true
+	} else {
+		b.buf.Write(p[:n])
+	}
+	b.raw.store(b.buf.Bytes(), b.truncated)
+	return n, err
+}
+
+// rawBodyFallback returns the raw JSON of a response the API client
+// couldn't decode instead of the decode error, with a warning, so a
+// response shape the client doesn't know yet doesn't hide the data from
+// the agent. Only the last response of a call is kept, which is the one
+// that failed since handlers stop at the first error.
+func rawBodyFallback(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		raw := &rawBody{}
+		result, err := next(context.WithValue(ctx, rawBodyKey{}, raw), req)
+		if err != nil || result == nil || !result.IsError {
+			return result, err
+		}
+		msg := resultText(result)
+		if !strings.Contains(msg, decodeErrorMarker) {
+			return result, nil
+		}
+		body, ok := raw.load()
+		if !ok || !json.Valid(body) {
+			return result, nil
+		}
+
+		var notes toolNotes
+		notes.warnf("%s. This is the API's raw JSON response instead, which may not match this tool's usual output.", strings.TrimSuffix(msg, "."))
+		return withNotes(mcp.NewToolResultText(string(body)), &notes), nil
+	}
+}
+
+// resultText joins a result's text content.
+func resultText(result *mcp.CallToolResult) string {
+	var parts []string
+	for _, c := range result.Content {
+		if text, ok := c.(mcp.TextContent); ok {
+			parts = append(parts, text.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
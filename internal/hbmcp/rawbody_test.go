@@ -0,0 +1,65 @@
+package hbmcp
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/honeybadger-io/honeybadger-mcp-server/internal/config"
+)
+
+func TestRawBodyFallback(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		body        string
+		wantError   bool
+		wantText    string
+		wantWarning bool
+	}{
+		{"decodes", http.StatusOK, `{"id": 1, "name": "Project 1"}`, false, `"name":"Project 1"`, false},
+		{"unexpected shape", http.StatusOK, `{"id": "one", "name": "Project 1"}`, false, `{"id": "one", "name": "Project 1"}`, true},
+		{"not JSON", http.StatusOK, `<html>oops</html>`, true, decodeErrorMarker, false},
+		{"API error", http.StatusNotFound, `{"errors": "Not found"}`, true, "Failed to get project", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer api.Close()
+
+			httpClient := newAPIHTTPClient(&config.Config{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+			client := hbapi.NewClient().WithBaseURL(api.URL).WithHTTPClient(httpClient).WithAuthToken("test-token")
+			handler := rawBodyFallback(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				return handleGetProject(ctx, client, req, false)
+			})
+
+			req := mcp.CallToolRequest{}
+			req.Params.Arguments = map[string]any{"id": 1}
+			result, err := handler(context.Background(), req)
+			if err != nil {
+				t.Fatalf("handler error = %v", err)
+			}
+			if result.IsError != tt.wantError {
+				t.Fatalf("IsError = %v, want %v: %s", result.IsError, tt.wantError, getResultText(result))
+			}
+			if !strings.Contains(getResultText(result), tt.wantText) {
+				t.Errorf("expected %q in result, got %q", tt.wantText, getResultText(result))
+			}
+			notes := getResultNotes(t, result)
+			if hasWarning := len(notes.Warnings) == 1 && strings.Contains(notes.Warnings[0], decodeErrorMarker); hasWarning != tt.wantWarning {
+				t.Errorf("expected decode warning = %v, got %v", tt.wantWarning, notes.Warnings)
+			}
+		})
+	}
+}
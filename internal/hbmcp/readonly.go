@@ -0,0 +1,46 @@
+package hbmcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/honeybadger-io/honeybadger-mcp-server/internal/config"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// hidesWriteTools reports whether write tools are left out of what the
+// caller sees: in read-only mode, unless read-only-behavior is error.
+func hidesWriteTools(ctx context.Context, cfg *config.Config) bool {
+	return EffectiveReadOnly(ctx, cfg) && cfg.ReadOnlyBehavior != config.ReadOnlyError
+}
+
+// readOnlyError is the result of calling a write tool in read-only mode.
+// Besides the message it carries {"error": "read_only", ...} as structured
+// content, so a client can tell it apart from a failed call and explain
+// the limitation rather than look for another way to make the change.
+func readOnlyError(ctx context.Context, cfg *config.Config, tool string) *mcp.CallToolResult {
+	reason := "the server was started with --read-only"
+	if CallTransport(ctx, cfg) == config.TransportHTTP {
+		reason = "the access token doesn't have the write scope"
+	}
+	result := mcp.NewToolResultError(fmt.Sprintf("Tool %q is not available in read-only mode: %s. Tell the user the change can't be made through this server as configured.", tool, reason))
+	result.StructuredContent = map[string]any{
+		"error":  "read_only",
+		"tool":   tool,
+		"reason": reason,
+	}
+	return result
+}
+
+// guardWrites refuses calls to the write tool name while read-only mode is
+// on. With read-only-behavior hide the tool filter refuses them first;
+// with error they reach this.
+func guardWrites(current func() *config.Config, name string, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if cfg := current(); EffectiveReadOnly(ctx, cfg) {
+			return readOnlyError(ctx, cfg, name), nil
+		}
+		return next(ctx, req)
+	}
+}
@@ -0,0 +1,74 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/honeybadger-io/honeybadger-mcp-server/internal/config"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestReadOnlyBehavior_Error(t *testing.T) {
+	cfg := reloadTestConfig()
+	cfg.ReadOnlyBehavior = config.ReadOnlyError
+	s, _, _ := NewReloadableServer(cfg, "test")
+
+	names := listToolNames(t, s)
+	if !slices.Contains(names, "delete_project") || !slices.Contains(names, "list_projects") {
+		t.Fatalf("expected write tools to stay listed, got %v", names)
+	}
+
+	resp := s.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"delete_project","arguments":{"id":1}}}`))
+	raw, err := json.Marshal(resp)
+	if err != nil {
+		t.Fatalf("failed to marshal response: %v", err)
+	}
+	var decoded struct {
+		Result struct {
+			IsError           bool              `json:"isError"`
+			Content           []mcp.TextContent `json:"content"`
+			StructuredContent map[string]any    `json:"structuredContent"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		t.Fatalf("failed to unmarshal response %s: %v", raw, err)
+	}
+	if !decoded.Result.IsError || len(decoded.Result.Content) == 0 || !strings.Contains(decoded.Result.Content[0].Text, "not available in read-only mode: the server was started with --read-only") {
+		t.Errorf("expected a read-only error, got %s", raw)
+	}
+	if decoded.Result.StructuredContent["error"] != "read_only" || decoded.Result.StructuredContent["tool"] != "delete_project" {
+		t.Errorf("expected structured read_only error, got %v", decoded.Result.StructuredContent)
+	}
+
+	search := s.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"search_tools","arguments":{"query":"delete project"}}}`))
+	raw, _ = json.Marshal(search)
+	if !strings.Contains(string(raw), "Name: delete_project") || !strings.Contains(string(raw), "Available: no, the server is read-only") {
+		t.Errorf("expected search_tools to list delete_project as unavailable, got %s", raw)
+	}
+}
+
+func TestReadOnlyError_HTTP(t *testing.T) {
+	result := readOnlyError(context.Background(), &config.Config{TransportMode: config.TransportHTTP}, "update_fault")
+	if !result.IsError || !strings.Contains(getResultText(result), "doesn't have the write scope") {
+		t.Errorf("expected the write scope to be named, got %q", getResultText(result))
+	}
+}
+
+func TestReloader_ReadOnlyBehavior(t *testing.T) {
+	s, _, reloader := NewReloadableServer(reloadTestConfig(), "test")
+	session := newTestSession(t, s)
+
+	next := reloadTestConfig()
+	next.ReadOnlyBehavior = config.ReadOnlyError
+	reloader.Reload(next)
+
+	if !slices.Contains(listToolNames(t, s), "create_project") {
+		t.Error("create_project should be listed after switching to read-only-behavior error")
+	}
+	if n := drainListChanged(session); n != 1 {
+		t.Errorf("expected 1 tools/list_changed notification, got %d", n)
+	}
+}
@@ -0,0 +1,35 @@
+package hbmcp
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// recoverPanics turns a panic in a tool's handler, such as one on an API
+// payload of an unexpected shape, into an error result, so one bad call
+// fails on its own instead of as a protocol error or, for work the
+// handler left running, a crashed server. The panic is logged with its
+// stack; the result gets the call's correlation ID like any other error,
+// which matches a reported failure to that log entry.
+func recoverPanics(logger *slog.Logger) func(tool mcp.Tool, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(tool mcp.Tool, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, req mcp.CallToolRequest) (result *mcp.CallToolResult, err error) {
+			defer func() {
+				v := recover()
+				if v == nil {
+					return
+				}
+				logger.ErrorContext(ctx, "Tool handler panicked", "tool", tool.Name, "panic", v, "stack", string(debug.Stack()))
+				result = mcp.NewToolResultError(fmt.Sprintf("Internal error in %s: %v. This is a bug in the server rather than a problem with the call, so retrying won't help; please report it with the correlation ID.", tool.Name, v))
+				result.StructuredContent = map[string]any{"error": "internal", "tool": tool.Name}
+				err = nil
+			}()
+			return next(ctx, req)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package hbmcp
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestRecoverPanics(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+	s := server.NewMCPServer("test", "1.0.0", server.WithToolHandlerMiddleware(correlateToolCalls(logger)))
+	r := newToolRegistrar(s)
+	r.logger = logger
+	r.AddTool(mcp.NewTool("get_thing",
+		mcp.WithTitleAnnotation("Get Thing"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+	), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var results []string
+		return mcp.NewToolResultText(results[0]), nil
+	})
+
+	resp, ok := s.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"get_thing","arguments":{}}}`)).(mcp.JSONRPCResponse)
+	if !ok {
+		t.Fatal("expected a tool result rather than a protocol error")
+	}
+	result := resp.Result.(*mcp.CallToolResult)
+	text := getResultText(result)
+	if !result.IsError || !strings.Contains(text, "Internal error in get_thing: runtime error: index out of range") {
+		t.Errorf("expected an internal error result, got %q", text)
+	}
+	_, id, found := strings.Cut(text, "Correlation ID: ")
+	if !found {
+		t.Fatalf("expected a correlation ID in %q", text)
+	}
+	if structured, _ := result.StructuredContent.(map[string]any); structured["error"] != "internal" || structured["correlation_id"] != id {
+		t.Errorf("unexpected structured content %v", result.StructuredContent)
+	}
+	if !strings.Contains(logs.String(), "Tool handler panicked") || !strings.Contains(logs.String(), "stack=") {
+		t.Errorf("expected the panic logged with its stack, got %s", logs.String())
+	}
+}
@@ -37,12 +37,24 @@ type cacheEntry struct {
 	body      string
 	etag      string
 	fetchedAt time.Time
+	// fromDisk marks a copy loaded from the on-disk cache and not yet
+	// revalidated, which stays fresh for referenceDiskTTL.
+	fromDisk bool
+}
+
+func (e *cacheEntry) fresh(ttl time.Duration) bool {
+	if e.fromDisk {
+		ttl = referenceDiskTTL
+	}
+	return time.Since(e.fetchedAt) < ttl
 }
 
 // referenceFetcher pulls reference content from the docs site with an
-// in-memory cache. There is deliberately no embedded fallback: the docs site
-// is the single source of truth, and a cold-cache fetch failure surfaces as a
-// tool error rather than silently serving content that may have drifted.
+// in-memory cache, optionally kept on disk (see persist). There is
+// deliberately no embedded fallback: the docs site is the single source of
+// truth, and a cold-cache fetch failure surfaces as a tool error rather than
+// silently serving content that may have drifted. The disk cache is only ever
+// a copy of what the docs site served to this server version.
 type referenceFetcher struct {
 	baseURL string
 	ttl     time.Duration
@@ -52,6 +64,11 @@ type referenceFetcher struct {
 	mu      sync.Mutex
 	entries map[string]*cacheEntry
 	flights map[string]chan struct{}
+
+	// cachePath and version are set by persist to keep the cache on disk.
+	cachePath string
+	version   string
+	saveMu    sync.Mutex
 }
 
 func newReferenceFetcher(baseURL string, logger *slog.Logger) *referenceFetcher {
@@ -72,7 +89,7 @@ func newReferenceFetcher(baseURL string, logger *slog.Logger) *referenceFetcher
 func (f *referenceFetcher) get(ctx context.Context, path string) (string, error) {
 	for {
 		f.mu.Lock()
-		if e, ok := f.entries[path]; ok && time.Since(e.fetchedAt) < f.ttl {
+		if e, ok := f.entries[path]; ok && e.fresh(f.ttl) {
 			body := e.body
 			f.mu.Unlock()
 			return body, nil
@@ -102,10 +119,13 @@ func (f *referenceFetcher) get(ctx context.Context, path string) (string, error)
 		case err == nil && status == http.StatusOK:
 			f.entries[path] = &cacheEntry{body: body, etag: etag, fetchedAt: now}
 			f.mu.Unlock()
+			f.save()
 			return body, nil
 		case err == nil && status == http.StatusNotModified && stale != nil:
 			stale.fetchedAt = now
+			stale.fromDisk = false
 			f.mu.Unlock()
+			f.save()
 			return stale.body, nil
 		default:
 			if err == nil {
@@ -113,6 +133,7 @@ func (f *referenceFetcher) get(ctx context.Context, path string) (string, error)
 			}
 			if stale != nil {
 				stale.fetchedAt = now
+				stale.fromDisk = false
 				f.mu.Unlock()
 				f.logger.Warn("Reference refresh failed, serving stale copy", "path", path, "error", err)
 				return stale.body, nil
@@ -173,6 +194,8 @@ func RegisterReferenceTools(r *toolRegistrar, fetcher *referenceFetcher) {
 			mcp.WithDescription("Returns Honeybadger reference documentation by topic. Topics: badgerql (query language), queries (Insights query fundamentals: streams, time ranges, field grounding), charts (visualization views, chart_config), dashboards (widgets, layout), alarms (trigger_config, states, patterns), errors (fault/notice model, error search syntax), checkins (cron/scheduled-task monitoring: schedule types, plan gating, timezone format, report payloads, lifecycle states). Fetch all topics you need in one call, e.g. topics: [\"badgerql\", \"charts\"]; skip topics still visible in your context. Call with no arguments for a topic index, or [\"all\"] for everything."),
 			mcp.WithReadOnlyHintAnnotation(true),
 			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
 			mcp.WithArray("topics",
 				mcp.Description("Reference topics to fetch: badgerql, queries, charts, dashboards, alarms, errors, checkins, or all. Omit for an index of topics."),
 				mcp.WithStringItems(),
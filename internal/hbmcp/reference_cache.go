@@ -0,0 +1,171 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// referenceCacheFile is the file in the reference cache directory the
+	// cached topics are kept in.
+	referenceCacheFile = "reference.json"
+
+	// referenceDiskTTL is how long a copy read from disk is served without
+	// revalidating it, so the first get_reference of a session doesn't
+	// wait on the docs site.
+	referenceDiskTTL = 24 * time.Hour
+
+	// referenceRefreshTimeout bounds --refresh-reference, which holds up
+	// startup.
+	referenceRefreshTimeout = 30 * time.Second
+)
+
+// referenceCache is the on-disk form of the fetcher's cache. It is pinned
+// to the server version and instructions URL it was written by; a cache
+// from another version is ignored, since its topics may not match this
+// version's tools.
+type referenceCache struct {
+	ServerVersion   string                         `json:"server_version"`
+	InstructionsURL string                         `json:"instructions_url"`
+	Entries         map[string]referenceCacheEntry `json:"entries"`
+}
+
+type referenceCacheEntry struct {
+	Body      string    `json:"body"`
+	ETag      string    `json:"etag,omitempty"`
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+// persist keeps the fetcher's cache in dir across restarts, loading the
+// copy there if it was written by this version for this URL. Cached
+// topics are served without a request for referenceDiskTTL, and after
+// that still serve when the docs site can't be reached.
+func (f *referenceFetcher) persist(dir, version string) {
+	f.cachePath = filepath.Join(dir, referenceCacheFile)
+	f.version = version
+
+	data, err := os.ReadFile(f.cachePath)
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			f.logger.Warn("Failed to read reference cache", "path", f.cachePath, "error", err)
+		}
+		return
+	}
+	var cache referenceCache
+	if err := json.Unmarshal(data, &cache); err != nil {
+		f.logger.Warn("Ignoring unreadable reference cache", "path", f.cachePath, "error", err)
+		return
+	}
+	if cache.ServerVersion != version || cache.InstructionsURL != f.baseURL {
+		f.logger.Info("Ignoring reference cache from another version", "path", f.cachePath, "cached_version", cache.ServerVersion)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for path, e := range cache.Entries {
+		f.entries[path] = &cacheEntry{body: e.Body, etag: e.ETag, fetchedAt: e.FetchedAt, fromDisk: true}
+	}
+	f.logger.Debug("Loaded reference cache", "path", f.cachePath, "entries", len(cache.Entries))
+}
+
+// save writes the cache to disk, if persist was called. A failure is
+// logged; the in-memory cache still serves.
+func (f *referenceFetcher) save() {
+	if f.cachePath == "" {
+		return
+	}
+	f.mu.Lock()
+	cache := referenceCache{ServerVersion: f.version, InstructionsURL: f.baseURL, Entries: map[string]referenceCacheEntry{}}
+	for path, e := range f.entries {
+		cache.Entries[path] = referenceCacheEntry{Body: e.body, ETag: e.etag, FetchedAt: e.fetchedAt}
+	}
+	f.mu.Unlock()
+
+	f.saveMu.Lock()
+	defer f.saveMu.Unlock()
+	if err := writeFileAtomic(f.cachePath, cache); err != nil {
+		f.logger.Warn("Failed to write reference cache", "path", f.cachePath, "error", err)
+	}
+}
+
+// writeFileAtomic writes v as JSON to path through a temporary file, so a
+// crash mid-write can't leave a truncated cache.
+func writeFileAtomic(path string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// refresh refetches the index and every topic it lists, ignoring cached
+// copies, for --refresh-reference. What was cached is kept for whatever
+// can't be refetched.
+func (f *referenceFetcher) refresh(ctx context.Context) error {
+	defer f.save()
+	body, err := f.refetch(ctx, "index.json")
+	if err != nil {
+		return err
+	}
+	var idx instructionIndex
+	if err := json.Unmarshal([]byte(body), &idx); err != nil {
+		return fmt.Errorf("invalid reference index: %w", err)
+	}
+	for _, set := range idx.Instructions {
+		if _, err := f.refetch(ctx, set.Name+".txt"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// refetch fetches path unconditionally and caches it.
+func (f *referenceFetcher) refetch(ctx context.Context, path string) (string, error) {
+	body, etag, status, err := f.fetch(ctx, path, nil)
+	if err == nil && status != http.StatusOK {
+		err = fmt.Errorf("unexpected status %d", status)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch reference from %s/%s: %w", f.baseURL, path, err)
+	}
+	f.mu.Lock()
+	f.entries[path] = &cacheEntry{body: body, etag: etag, fetchedAt: time.Now()}
+	f.mu.Unlock()
+	return body, nil
+}
+
+// refreshReference runs refresh at startup. A failure is logged and the
+// server starts with whatever was cached.
+func refreshReference(f *referenceFetcher, logger *slog.Logger) {
+	ctx, cancel := context.WithTimeout(context.Background(), referenceRefreshTimeout)
+	defer cancel()
+	if err := f.refresh(ctx); err != nil {
+		logger.Warn("Failed to refresh reference cache; serving cached topics", "error", err)
+		return
+	}
+	logger.Info("Refreshed reference cache", "path", f.cachePath)
+}
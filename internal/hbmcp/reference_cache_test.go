@@ -0,0 +1,80 @@
+package hbmcp
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestReferenceFetcher_PersistsAcrossRestarts(t *testing.T) {
+	var hits map[string]*atomic.Int64
+	var fail atomic.Bool
+	server := newDocsServer(t, &hits, &fail)
+	defer server.Close()
+	dir := t.TempDir()
+
+	first := testFetcher(server.URL)
+	first.persist(dir, "1.0.0")
+	if result, err := handleGetReference(context.Background(), first, referenceRequest("badgerql")); err != nil || result.IsError {
+		t.Fatalf("priming call failed: err=%v result=%s", err, getResultText(result))
+	}
+
+	// A restart of the same version answers from disk, even offline.
+	fail.Store(true)
+	before := hits["/instructions/badgerql.txt"].Load()
+	restarted := testFetcher(server.URL)
+	restarted.persist(dir, "1.0.0")
+	result, err := handleGetReference(context.Background(), restarted, referenceRequest("badgerql"))
+	if err != nil || result.IsError || !strings.Contains(getResultText(result), "# BadgerQL Reference") {
+		t.Fatalf("expected the cached topic, got err=%v result=%s", err, getResultText(result))
+	}
+	if n := hits["/instructions/badgerql.txt"].Load(); n != before {
+		t.Errorf("expected no request for a fresh disk copy, got %d", n-before)
+	}
+
+	// Another version doesn't trust it.
+	upgraded := testFetcher(server.URL)
+	upgraded.persist(dir, "1.1.0")
+	result, _ = handleGetReference(context.Background(), upgraded, referenceRequest("badgerql"))
+	if !result.IsError {
+		t.Errorf("expected another version's cache to be ignored, got %s", getResultText(result))
+	}
+}
+
+func TestReferenceFetcher_Refresh(t *testing.T) {
+	var hits map[string]*atomic.Int64
+	var fail atomic.Bool
+	server := newDocsServer(t, &hits, &fail)
+	defer server.Close()
+	dir := t.TempDir()
+
+	f := testFetcher(server.URL)
+	f.persist(dir, "1.0.0")
+	if result, err := handleGetReference(context.Background(), f, referenceRequest("badgerql")); err != nil || result.IsError {
+		t.Fatalf("priming call failed: err=%v result=%s", err, getResultText(result))
+	}
+	if err := f.refresh(context.Background()); err != nil {
+		t.Fatalf("refresh() error = %v", err)
+	}
+	if n := hits["/instructions/badgerql.txt"].Load(); n != 2 {
+		t.Errorf("expected refresh to refetch a fresh topic, got %d requests", n)
+	}
+	for _, s := range testSets {
+		if hits["/instructions/"+s.name+".txt"].Load() == 0 {
+			t.Errorf("expected refresh to fetch %s", s.name)
+		}
+	}
+
+	// Every topic is then on disk for a restart to serve offline.
+	fail.Store(true)
+	restarted := testFetcher(server.URL)
+	restarted.persist(dir, "1.0.0")
+	result, err := handleGetReference(context.Background(), restarted, referenceRequest("all"))
+	if err != nil || result.IsError || !strings.Contains(getResultText(result), "# Errors") {
+		t.Errorf("expected every topic cached, got err=%v result=%s", err, getResultText(result))
+	}
+	if err := restarted.refresh(context.Background()); err == nil {
+		t.Error("expected refresh to fail while the docs site is down")
+	}
+}
@@ -0,0 +1,132 @@
+package hbmcp
+
+import (
+	"log/slog"
+	"maps"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"github.com/honeybadger-io/honeybadger-mcp-server/internal/config"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Reloader applies config changes to a running server. Only the settings
+// that shape the tool list are reloadable: EnabledTools, DisabledTools,
+// ReadOnly, and ReadOnlyBehavior, plus the project scope, which is checked
+// per call. Whenever the list a client would see changes, connected clients
+// get a notifications/tools/list_changed so they refresh without
+// reconnecting.
+type Reloader struct {
+	server *server.MCPServer
+	live   *atomic.Pointer[config.Config]
+	logger *slog.Logger
+
+	mu  sync.Mutex
+	all map[string]server.ServerTool
+}
+
+// newReloader snapshots every tool registered on s; call it before any
+// tool is removed.
+func newReloader(s *server.MCPServer, live *atomic.Pointer[config.Config], logger *slog.Logger) *Reloader {
+	all := map[string]server.ServerTool{}
+	for name, tool := range s.ListTools() {
+		all[name] = *tool
+	}
+	return &Reloader{server: s, live: live, logger: logger, all: all}
+}
+
+// Current returns the configuration in effect, reloads included.
+func (rl *Reloader) Current() *config.Config {
+	return rl.live.Load()
+}
+
+// Reload applies next's reloadable settings. Other settings that differ
+// are logged and keep their startup values until a restart.
+func (rl *Reloader) Reload(next *config.Config) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	prev := rl.live.Load()
+	for name, changed := range map[string]bool{
+		"auth-token":              next.AuthToken != prev.AuthToken,
+		"api-url":                 next.APIURL != prev.APIURL,
+		"instructions-url":        next.InstructionsURL != prev.InstructionsURL,
+		"log-level":               next.LogLevel != prev.LogLevel,
+		"defer-tools":             next.DeferTools != prev.DeferTools,
+		"audit-log":               next.AuditLogPath != prev.AuditLogPath,
+		"chaos":                   next.ChaosRate != prev.ChaosRate,
+		"proxy":                   next.ProxyURL != prev.ProxyURL,
+		"ca-bundle":               next.CABundlePath != prev.CABundlePath,
+		"api-timeout":             next.APITimeout != prev.APITimeout,
+		"api-retries":             next.APIRetries != prev.APIRetries,
+		"api-retry-backoff":       next.APIRetryBackoff != prev.APIRetryBackoff,
+		"auth-style":              next.AuthStyle != prev.AuthStyle,
+		"lenient-decoding":        next.LenientDecoding != prev.LenientDecoding,
+		"fixtures":                next.FixturesDir != prev.FixturesDir,
+		"confirm-destructive":     next.ConfirmDestructive != prev.ConfirmDestructive,
+		"reference-cache-dir":     next.ReferenceCacheDir != prev.ReferenceCacheDir,
+		"raw-api":                 next.RawAPI != prev.RawAPI,
+		"not-found-lookup":        next.NotFoundLookup != prev.NotFoundLookup,
+		"insights-preflight-rows": next.InsightsPreflightRows != prev.InsightsPreflightRows,
+		"api-log-sample-rate":     next.APILogSampleRate != prev.APILogSampleRate,
+		"session-max-api-calls":   next.SessionMaxAPICalls != prev.SessionMaxAPICalls,
+		"session-max-rows":        next.SessionMaxRows != prev.SessionMaxRows,
+		"max-concurrent-calls":    next.MaxConcurrentCalls != prev.MaxConcurrentCalls,
+		"queue-timeout":           next.QueueTimeout != prev.QueueTimeout,
+		"max-result-tokens":       next.MaxResultTokens != prev.MaxResultTokens,
+		"region":                  next.Region != prev.Region,
+		"api-path-prefix":         next.APIPathPrefix != prev.APIPathPrefix,
+		"detect-capabilities":     next.DetectCapabilities != prev.DetectCapabilities,
+	} {
+		if changed {
+			rl.logger.Warn("Config change requires a restart to take effect", "setting", name)
+		}
+	}
+
+	merged := *prev
+	merged.EnabledTools = next.EnabledTools
+	merged.DisabledTools = next.DisabledTools
+	merged.ReadOnly = next.ReadOnly
+	merged.ReadOnlyBehavior = next.ReadOnlyBehavior
+	merged.AllowedProjectIDs = next.AllowedProjectIDs
+	merged.DeniedProjectIDs = next.DeniedProjectIDs
+	rl.live.Store(&merged)
+
+	toolsChanged := rl.applyToolSelection(&merged)
+	readOnlyChanged := merged.ReadOnly != prev.ReadOnly || merged.ReadOnlyBehavior != prev.ReadOnlyBehavior
+	if !toolsChanged && readOnlyChanged {
+		// Read-only is applied by the tool filter at list time, so the
+		// registered set is unchanged but what clients see isn't.
+		rl.server.SendNotificationToAllClients(mcp.MethodNotificationToolsListChanged, nil)
+	}
+	scopeChanged := !slices.Equal(merged.AllowedProjectIDs, prev.AllowedProjectIDs) || !slices.Equal(merged.DeniedProjectIDs, prev.DeniedProjectIDs)
+	if toolsChanged || readOnlyChanged || scopeChanged {
+		rl.logger.Info("Config reloaded", "enabled_tools", merged.EnabledTools, "disabled_tools", merged.DisabledTools, "read_only", merged.ReadOnly, "read_only_behavior", merged.ReadOnlyBehavior,
+			"allowed_project_ids", merged.AllowedProjectIDs, "denied_project_ids", merged.DeniedProjectIDs)
+	}
+}
+
+// applyToolSelection registers exactly the tools cfg enables and reports
+// whether that changed the registered set. mcp-go notifies clients itself
+// when the set is replaced.
+func (rl *Reloader) applyToolSelection(cfg *config.Config) bool {
+	var want []server.ServerTool
+	for _, name := range slices.Sorted(maps.Keys(rl.all)) {
+		if cfg.ToolEnabled(name) {
+			want = append(want, rl.all[name])
+		}
+	}
+
+	have := slices.Sorted(maps.Keys(rl.server.ListTools()))
+	wantNames := make([]string, len(want))
+	for i, t := range want {
+		wantNames[i] = t.Tool.Name
+	}
+	if slices.Equal(have, wantNames) {
+		return false
+	}
+	rl.server.SetTools(want...)
+	return true
+}
@@ -0,0 +1,169 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/honeybadger-io/honeybadger-mcp-server/internal/config"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type testSession struct {
+	id            string
+	notifications chan mcp.JSONRPCNotification
+}
+
+func (s *testSession) Initialize()       {}
+func (s *testSession) Initialized() bool { return true }
+func (s *testSession) SessionID() string { return s.id }
+func (s *testSession) NotificationChannel() chan<- mcp.JSONRPCNotification {
+	return s.notifications
+}
+
+func newTestSession(t *testing.T, s *server.MCPServer) *testSession {
+	t.Helper()
+	session := &testSession{id: "reload-test", notifications: make(chan mcp.JSONRPCNotification, 10)}
+	if err := s.RegisterSession(context.Background(), session); err != nil {
+		t.Fatalf("RegisterSession() error = %v", err)
+	}
+	return session
+}
+
+// drainListChanged returns how many tools/list_changed notifications are
+// queued for session.
+func drainListChanged(session *testSession) int {
+	n := 0
+	for {
+		select {
+		case note := <-session.notifications:
+			if note.Method == mcp.MethodNotificationToolsListChanged {
+				n++
+			}
+		default:
+			return n
+		}
+	}
+}
+
+func listToolNames(t *testing.T, s *server.MCPServer) []string {
+	t.Helper()
+	resp := s.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`))
+	raw, err := json.Marshal(resp)
+	if err != nil {
+		t.Fatalf("failed to marshal response: %v", err)
+	}
+	var decoded struct {
+		Result mcp.ListToolsResult `json:"result"`
+	}
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		t.Fatalf("failed to unmarshal tools/list: %v", err)
+	}
+	var names []string
+	for _, tool := range decoded.Result.Tools {
+		names = append(names, tool.Name)
+	}
+	return names
+}
+
+func reloadTestConfig() *config.Config {
+	return &config.Config{
+		AuthToken:     "test-token",
+		APIURL:        "https://api.honeybadger.io/v2",
+		LogLevel:      "error",
+		ReadOnly:      true,
+		TransportMode: config.TransportStdio,
+	}
+}
+
+func TestReloader_ToolSelection(t *testing.T) {
+	cfg := reloadTestConfig()
+	cfg.DisabledTools = []string{"list_faults"}
+	s, _, reloader := NewReloadableServer(cfg, "test")
+	session := newTestSession(t, s)
+
+	if slices.Contains(listToolNames(t, s), "list_faults") {
+		t.Fatal("list_faults should start disabled")
+	}
+
+	next := reloadTestConfig()
+	next.DisabledTools = []string{"get_fault"}
+	reloader.Reload(next)
+
+	names := listToolNames(t, s)
+	if !slices.Contains(names, "list_faults") {
+		t.Error("list_faults should be re-enabled after reload")
+	}
+	if slices.Contains(names, "get_fault") {
+		t.Error("get_fault should be disabled after reload")
+	}
+	if n := drainListChanged(session); n != 1 {
+		t.Errorf("expected 1 tools/list_changed notification, got %d", n)
+	}
+
+	// A disabled tool must not be callable either.
+	callMsg := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"get_fault","arguments":{"project_id":1,"fault_id":1}}}`
+	if _, ok := s.HandleMessage(context.Background(), []byte(callMsg)).(mcp.JSONRPCError); !ok {
+		t.Error("expected JSON-RPC error calling a tool disabled by reload")
+	}
+}
+
+func TestReloader_ReadOnly(t *testing.T) {
+	s, _, reloader := NewReloadableServer(reloadTestConfig(), "test")
+	session := newTestSession(t, s)
+
+	if slices.Contains(listToolNames(t, s), "create_project") {
+		t.Fatal("create_project should be hidden in read-only mode")
+	}
+
+	next := reloadTestConfig()
+	next.ReadOnly = false
+	reloader.Reload(next)
+
+	if !slices.Contains(listToolNames(t, s), "create_project") {
+		t.Error("create_project should be listed after disabling read-only")
+	}
+	if n := drainListChanged(session); n != 1 {
+		t.Errorf("expected 1 tools/list_changed notification, got %d", n)
+	}
+}
+
+func TestReloader_NoChange(t *testing.T) {
+	s, _, reloader := NewReloadableServer(reloadTestConfig(), "test")
+	session := newTestSession(t, s)
+
+	// Settings that need a restart are not applied and don't notify.
+	next := reloadTestConfig()
+	next.APIURL = "https://eu-api.honeybadger.io/v2"
+	reloader.Reload(next)
+
+	if n := drainListChanged(session); n != 0 {
+		t.Errorf("expected no notifications, got %d", n)
+	}
+	if got := reloader.live.Load().APIURL; got != "https://api.honeybadger.io/v2" {
+		t.Errorf("api-url should not be reloaded, got %s", got)
+	}
+}
+
+func TestReloader_ProjectScope(t *testing.T) {
+	s, _, reloader := NewReloadableServer(reloadTestConfig(), "test")
+	call := func() string {
+		resp := s.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"get_project","arguments":{"id":2}}}`))
+		raw, err := json.Marshal(resp)
+		if err != nil {
+			t.Fatalf("failed to marshal response: %v", err)
+		}
+		return string(raw)
+	}
+
+	next := reloadTestConfig()
+	next.AllowedProjectIDs = []int{1}
+	reloader.Reload(next)
+
+	if got := call(); !strings.Contains(got, "Project 2 is outside") {
+		t.Errorf("expected the reloaded project scope to apply, got %s", got)
+	}
+}
@@ -0,0 +1,177 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	cacheArg = "cache"
+
+	cacheUse     = "use"
+	cacheBypass  = "bypass"
+	cacheRefresh = "refresh"
+
+	// resultCacheTTL is how long a result can be reused with cache "use".
+	resultCacheTTL = 5 * time.Minute
+
+	// maxCachedResults bounds the results kept per session. The oldest are
+	// dropped first.
+	maxCachedResults = 50
+)
+
+// uncachedTools are read-only tools whose result depends on server state
+// rather than the API, so reusing one would report that state wrongly.
+var uncachedTools = map[string]bool{
+	"watch_faults":                true,
+	"unwatch_faults":              true,
+	"list_insights_query_history": true,
+	"set_default_project":         true,
+	"get_default_project":         true,
+}
+
+// resultCaches lets an agent reuse the result of an expensive read-only
+// call, such as an Insights query, within its session. Each successful
+// call's result is kept per session, keyed by caller, tool, and
+// arguments, for resultCacheTTL; a later identical call passing cache
+// "use" gets it back instead of calling the API again. Results are reused
+// only on request, so a call without cache always sees fresh data, and
+// calls without a session aren't kept at all.
+type resultCaches struct {
+	now func() time.Time
+
+	mu        sync.Mutex
+	bySession map[string]map[string]*cachedResult
+}
+
+type cachedResult struct {
+	result *mcp.CallToolResult
+	at     time.Time
+}
+
+func newResultCaches() *resultCaches {
+	return &resultCaches{now: time.Now, bySession: map[string]map[string]*cachedResult{}}
+}
+
+// cachesResults reports whether tool's results may be reused.
+func cachesResults(tool mcp.Tool) bool {
+	return isReadOnly(tool) && !uncachedTools[tool.Name]
+}
+
+// withCacheArg adds the cache parameter read-only tools accept.
+func withCacheArg(tool *mcp.Tool) {
+	mcp.WithString(cacheArg,
+		mcp.Description(fmt.Sprintf("Optional reuse of an earlier result: %q returns the result of an identical call made in this session within the last %s, if there is one, instead of calling the API again; %q (the default) calls the API and keeps the result for reuse; %q calls the API without keeping it. Reused results carry a warning with their age.", cacheUse, resultCacheTTL, cacheRefresh, cacheBypass)),
+		mcp.Enum(cacheUse, cacheRefresh, cacheBypass),
+	)(tool)
+}
+
+// cached wraps the handler of a read-only tool.
+func (c *resultCaches) cached(tool mcp.Tool, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		mode := cacheRefresh
+		if raw, ok := req.GetArguments()[cacheArg]; ok && raw != nil {
+			s, _ := raw.(string)
+			if s != cacheUse && s != cacheRefresh && s != cacheBypass {
+				return mcp.NewToolResultError(fmt.Sprintf("%s must be %q, %q, or %q", cacheArg, cacheUse, cacheRefresh, cacheBypass)), nil
+			}
+			mode = s
+		}
+		if mode == cacheBypass {
+			return next(ctx, req)
+		}
+		// Stateless http calls have no session to keep results for.
+		sessionID := sessionIDFromContext(ctx)
+		if sessionID == "" {
+			result, err := next(ctx, req)
+			if mode == cacheUse && err == nil && result != nil {
+				addWarning(result, fmt.Sprintf("results aren't cached without a session, so cache %q called the API", cacheUse))
+			}
+			return result, err
+		}
+
+		args := confirmedArgs(req.GetArguments())
+		delete(args, cacheArg)
+		key, err := json.Marshal(args)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to read arguments: %v", err)), nil
+		}
+		// A session's calls all carry the same token, but keying by it too
+		// keeps a result from reaching a caller with other credentials.
+		k := confirmationCaller(ctx) + " " + tool.Name + " " + string(key)
+
+		if mode == cacheUse {
+			if hit := c.get(sessionID, k); hit != nil {
+				return reusedResult(tool.Name, hit, c.now()), nil
+			}
+		}
+		result, err := next(ctx, req)
+		if err == nil && result != nil && !result.IsError {
+			c.put(sessionID, k, result)
+		}
+		return result, err
+	}
+}
+
+func (c *resultCaches) get(sessionID, key string) *cachedResult {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	hit, ok := c.bySession[sessionID][key]
+	if !ok || c.now().Sub(hit.at) > resultCacheTTL {
+		return nil
+	}
+	return hit
+}
+
+func (c *resultCaches) put(sessionID, key string, result *mcp.CallToolResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	results := c.bySession[sessionID]
+	if results == nil {
+		results = map[string]*cachedResult{}
+		c.bySession[sessionID] = results
+	}
+	for k, r := range results {
+		if now.Sub(r.at) > resultCacheTTL {
+			delete(results, k)
+		}
+	}
+	// Outer layers may add to the result, so the cache keeps its own copy
+	// of what the handler returned.
+	kept := *result
+	kept.Content = slices.Clone(result.Content)
+	results[key] = &cachedResult{result: &kept, at: now}
+	for len(results) > maxCachedResults {
+		oldest := ""
+		for k, r := range results {
+			if oldest == "" || r.at.Before(results[oldest].at) {
+				oldest = k
+			}
+		}
+		delete(results, oldest)
+	}
+}
+
+// reusedResult returns a copy of hit's result with a warning that it came
+// from an earlier call.
+func reusedResult(tool string, hit *cachedResult, now time.Time) *mcp.CallToolResult {
+	result := *hit.result
+	result.Content = slices.Clone(hit.result.Content)
+	addWarning(&result, fmt.Sprintf("cached result of an identical %s call at %s (%s ago); pass cache %q for fresh data", tool, hit.at.UTC().Format(time.RFC3339), now.Sub(hit.at).Round(time.Second), cacheRefresh))
+	return &result
+}
+
+// forget drops the results of a session that has gone away.
+func (c *resultCaches) forget(sessionID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.bySession, sessionID)
+}
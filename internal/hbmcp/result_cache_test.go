@@ -0,0 +1,98 @@
+package hbmcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestResultCaches(t *testing.T) {
+	c := newResultCaches()
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+	tool := mcp.NewTool("query_insights", mcp.WithString("query"))
+	calls := 0
+	handler := c.cached(tool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		calls++
+		return mcp.NewToolResultText(`{"results": []}`), nil
+	})
+	ctx := server.NewMCPServer("test", "1.0.0").WithContext(context.Background(), &testSession{id: "cache-test"})
+	call := func(args map[string]any) *mcp.CallToolResult {
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = args
+		result, _ := handler(ctx, req)
+		return result
+	}
+
+	call(map[string]any{"query": "fields @ts"})
+	call(map[string]any{"query": "fields @ts"})
+	if calls != 2 {
+		t.Fatalf("expected calls without cache to run, got %d calls", calls)
+	}
+
+	now = now.Add(time.Minute)
+	result := call(map[string]any{"query": "fields @ts", "cache": "use", "timeout_seconds": float64(30)})
+	if calls != 2 || getResultText(result) != `{"results": []}` {
+		t.Fatalf("expected the cached result, got %q after %d calls", getResultText(result), calls)
+	}
+	if notes := result.Content[len(result.Content)-1].(mcp.TextContent).Text; !strings.Contains(notes, "cached result") || !strings.Contains(notes, "1m0s ago") {
+		t.Errorf("expected a warning on the cached result, got %s", notes)
+	}
+
+	call(map[string]any{"query": "fields @ts", "cache": "refresh"})
+	call(map[string]any{"query": "fields @message", "cache": "use"})
+	if calls != 4 {
+		t.Errorf("expected refresh and a miss to run, got %d calls", calls)
+	}
+
+	// A bypassed call isn't kept.
+	call(map[string]any{"query": "fields @id", "cache": "bypass"})
+	call(map[string]any{"query": "fields @id", "cache": "use"})
+	if calls != 6 {
+		t.Errorf("expected a bypassed result not to be reused, got %d calls", calls)
+	}
+
+	now = now.Add(resultCacheTTL + time.Second)
+	call(map[string]any{"query": "fields @ts", "cache": "use"})
+	if calls != 7 {
+		t.Errorf("expected an expired result to run again, got %d calls", calls)
+	}
+
+	if result := call(map[string]any{"query": "fields @ts", "cache": "sometimes"}); !result.IsError {
+		t.Errorf("expected an invalid cache mode to fail, got %s", getResultText(result))
+	}
+}
+
+func TestResultCaches_Callers(t *testing.T) {
+	c := newResultCaches()
+	tool := mcp.NewTool("query_insights", mcp.WithString("query"))
+	calls := 0
+	handler := c.cached(tool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		calls++
+		return mcp.NewToolResultText(`{"results": []}`), nil
+	})
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"query": "fields @ts", "cache": "use"}
+	session := server.NewMCPServer("test", "1.0.0").WithContext(context.Background(), &testSession{id: "cache-test"})
+
+	_, _ = handler(WithAuthToken(session, "token-a"), req)
+	_, _ = handler(WithAuthToken(session, "token-b"), req)
+	if calls != 2 {
+		t.Errorf("expected a caller with another token not to get the cached result, got %d calls", calls)
+	}
+
+	// Stateless http calls have no session ID, so nothing is kept for
+	// them.
+	_, _ = handler(WithAuthToken(context.Background(), "token-a"), req)
+	result, _ := handler(WithAuthToken(context.Background(), "token-a"), req)
+	if calls != 4 {
+		t.Errorf("expected calls without a session not to be cached, got %d calls", calls)
+	}
+	if notes := result.Content[len(result.Content)-1].(mcp.TextContent).Text; !strings.Contains(notes, "without a session") {
+		t.Errorf("expected a warning that nothing was cached, got %s", notes)
+	}
+}
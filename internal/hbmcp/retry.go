@@ -0,0 +1,122 @@
+package hbmcp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// maxRetryWait caps both the exponential backoff and a server's
+// Retry-After, so one retry can't eat the whole request timeout.
+const maxRetryWait = 10 * time.Second
+
+// retryTransport retries API requests that failed in a way worth retrying:
+// network errors and 502/503/504 for idempotent methods, and 429 for any
+// method since the API rejected the request without acting on it. Waits
+// grow exponentially from backoff with jitter, and honor Retry-After.
+// The http.Client timeout bounds all attempts together.
+type retryTransport struct {
+	base    http.RoundTripper
+	retries int
+	backoff time.Duration
+	logger  *slog.Logger
+
+	// Overridable in tests; defaults to math/rand/v2.
+	jitter func() float64
+}
+
+func newRetryTransport(base http.RoundTripper, retries int, backoff time.Duration, logger *slog.Logger) *retryTransport {
+	return &retryTransport{
+		base:    base,
+		retries: retries,
+		backoff: backoff,
+		logger:  logger,
+		jitter:  rand.Float64,
+	}
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.Body != nil {
+			if req.GetBody == nil {
+				return nil, errors.New("cannot retry request with a non-rewindable body")
+			}
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+
+		resp, err := t.base.RoundTrip(req)
+		if attempt >= t.retries || !shouldRetry(req, resp, err) {
+			return resp, err
+		}
+
+		wait := t.wait(attempt, resp)
+		status := 0
+		if resp != nil {
+			status = resp.StatusCode
+			// Drain so the connection can be reused.
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+			_ = resp.Body.Close()
+		}
+		t.logger.DebugContext(req.Context(), "Retrying API request", "method", req.Method, "path", req.URL.Path, "attempt", attempt+1, "status", status, "error", err, "wait", wait)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if err != nil {
+		// The caller gave up; retrying would only fail again.
+		if errors.Is(err, context.Canceled) || req.Context().Err() != nil {
+			return false
+		}
+		// The session's budget won't grow back by waiting.
+		if errors.Is(err, errBudgetExhausted) {
+			return false
+		}
+		return idempotent(req.Method)
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return idempotent(req.Method)
+	}
+	return false
+}
+
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// wait is the delay before retry attempt+1: the server's Retry-After when
+// it sent one, otherwise backoff doubled per attempt with up to 50% jitter.
+func (t *retryTransport) wait(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && secs >= 0 {
+			return min(time.Duration(secs)*time.Second, maxRetryWait)
+		}
+	}
+	d := t.backoff << attempt
+	d += time.Duration(t.jitter() * float64(d) / 2)
+	return min(d, maxRetryWait)
+}
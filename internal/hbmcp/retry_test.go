@@ -0,0 +1,141 @@
+package hbmcp
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestRetryServer responds with statuses in order, then 200 once exhausted.
+func newTestRetryServer(t *testing.T, statuses ...int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(hits.Add(1))
+		if n <= len(statuses) {
+			w.WriteHeader(statuses[n-1])
+			return
+		}
+		_, _ = io.Copy(w, r.Body)
+	}))
+	t.Cleanup(server.Close)
+	return server, &hits
+}
+
+func newTestRetryClient(retries int) *http.Client {
+	transport := newRetryTransport(http.DefaultTransport, retries, time.Millisecond, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	transport.jitter = func() float64 { return 0 }
+	return &http.Client{Transport: transport}
+}
+
+func TestRetryTransport_RetriesTransientStatuses(t *testing.T) {
+	server, hits := newTestRetryServer(t, http.StatusServiceUnavailable, http.StatusTooManyRequests)
+
+	resp, err := newTestRetryClient(2).Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected 200 after retries, got %d", resp.StatusCode)
+	}
+	if hits.Load() != 3 {
+		t.Errorf("expected 3 attempts, got %d", hits.Load())
+	}
+}
+
+func TestRetryTransport_GivesUpAfterRetries(t *testing.T) {
+	server, hits := newTestRetryServer(t, http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway)
+
+	resp, err := newTestRetryClient(1).Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusBadGateway {
+		t.Errorf("expected the last 502 to be returned, got %d", resp.StatusCode)
+	}
+	if hits.Load() != 2 {
+		t.Errorf("expected 2 attempts, got %d", hits.Load())
+	}
+}
+
+func TestRetryTransport_DoesNotRetryNonIdempotent(t *testing.T) {
+	cases := []struct {
+		name   string
+		status int
+		hits   int32
+	}{
+		{"server error is not retried", http.StatusServiceUnavailable, 1},
+		{"rate limit is retried with the body resent", http.StatusTooManyRequests, 2},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			server, hits := newTestRetryServer(t, c.status)
+
+			resp, err := newTestRetryClient(2).Post(server.URL, "application/json", strings.NewReader(`{"ok":true}`))
+			if err != nil {
+				t.Fatalf("Post() error = %v", err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			_ = resp.Body.Close()
+			if hits.Load() != c.hits {
+				t.Errorf("expected %d attempts, got %d", c.hits, hits.Load())
+			}
+			if resp.StatusCode == http.StatusOK && string(body) != `{"ok":true}` {
+				t.Errorf("expected the body to be resent, got %q", body)
+			}
+		})
+	}
+}
+
+func TestRetryTransport_DoesNotRetryClientErrors(t *testing.T) {
+	server, hits := newTestRetryServer(t, http.StatusNotFound)
+
+	resp, err := newTestRetryClient(3).Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	_ = resp.Body.Close()
+	if hits.Load() != 1 {
+		t.Errorf("expected 1 attempt, got %d", hits.Load())
+	}
+}
+
+func TestRetryTransport_StopsWhenContextCanceled(t *testing.T) {
+	server, hits := newTestRetryServer(t, http.StatusServiceUnavailable, http.StatusServiceUnavailable)
+	transport := newRetryTransport(http.DefaultTransport, 5, time.Hour, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	if _, err := (&http.Client{Transport: transport}).Do(req); err == nil {
+		t.Fatal("expected the canceled context to end the retries")
+	}
+	if hits.Load() != 1 {
+		t.Errorf("expected 1 attempt before the wait was cut short, got %d", hits.Load())
+	}
+}
+
+func TestRetryTransport_Wait(t *testing.T) {
+	transport := newRetryTransport(nil, 3, 100*time.Millisecond, nil)
+	transport.jitter = func() float64 { return 1 }
+
+	if got := transport.wait(2, nil); got != 600*time.Millisecond {
+		t.Errorf("expected 400ms backoff plus 50%% jitter, got %v", got)
+	}
+	resp := &http.Response{Header: http.Header{"Retry-After": []string{"3"}}}
+	if got := transport.wait(0, resp); got != 3*time.Second {
+		t.Errorf("expected Retry-After to be honored, got %v", got)
+	}
+	resp.Header.Set("Retry-After", "3600")
+	if got := transport.wait(0, resp); got != maxRetryWait {
+		t.Errorf("expected Retry-After to be capped at %v, got %v", maxRetryWait, got)
+	}
+}
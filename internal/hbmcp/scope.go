@@ -0,0 +1,92 @@
+package hbmcp
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	"github.com/honeybadger-io/honeybadger-mcp-server/internal/config"
+)
+
+// projectIDArgs names the argument holding the project ID for tools that
+// don't call it project_id.
+var projectIDArgs = map[string]string{
+	"get_project":    "id",
+	"update_project": "id",
+	"delete_project": "id",
+}
+
+type projectScopeKey struct{}
+
+// projectAllowed reports whether the project scope the call runs under
+// allows the project with id. Calls that didn't go through scopeProjects,
+// like direct handler calls in tests, are unrestricted.
+func projectAllowed(ctx context.Context, id int) bool {
+	cfg, _ := ctx.Value(projectScopeKey{}).(*config.Config)
+	return cfg == nil || cfg.ProjectAllowed(id)
+}
+
+// projectScoped reports whether the call runs under a project scope, for
+// handlers that can reach projects without naming one in an argument.
+func projectScoped(ctx context.Context) bool {
+	return ctx.Value(projectScopeKey{}) != nil
+}
+
+// scopedProjectID parses a project argument the way GetInt does for the
+// handlers, so the ID checked is the one the API is called with. A value
+// GetInt would read as 0, or round to another ID, isn't an ID at all: the
+// handler would skip or misread it, so it can't be let past the scope.
+func scopedProjectID(v any) (int, bool) {
+	switch v := v.(type) {
+	case int:
+		return v, true
+	case float64:
+		return int(v), v == math.Trunc(v)
+	case string:
+		id, err := strconv.Atoi(v)
+		return id, err == nil
+	}
+	return 0, false
+}
+
+func projectOutOfScope(id int) string {
+	return fmt.Sprintf("Project %d is outside the projects this server is allowed to access", id)
+}
+
+// scopeProjects enforces AllowedProjectIDs/DeniedProjectIDs: a call naming
+// a project outside the scope is refused before its handler runs, and
+// handlers that list projects get the scope through ctx to filter their
+// results. It is registrar middleware so calls made through invoke_tool are
+// checked too.
+func scopeProjects(current func() *config.Config) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			cfg := current()
+			if !cfg.ProjectScoped() {
+				return next(ctx, req)
+			}
+
+			arg := "project_id"
+			if name, ok := projectIDArgs[req.Params.Name]; ok {
+				arg = name
+			}
+			if v, ok := req.GetArguments()[arg]; ok && v != nil {
+				id, ok := scopedProjectID(v)
+				if !ok {
+					return mcp.NewToolResultError(fmt.Sprintf("%s must be a project ID, got %v", arg, v)), nil
+				}
+				if !cfg.ProjectAllowed(id) {
+					return mcp.NewToolResultError(projectOutOfScope(id)), nil
+				}
+			}
+			if (req.Params.Name == "create_project" || req.Params.Name == "setup_project") && len(cfg.AllowedProjectIDs) > 0 {
+				return mcp.NewToolResultError(req.Params.Name + " is unavailable while allowed-project-ids is set: the new project wouldn't be in the allowed list"), nil
+			}
+			return next(context.WithValue(ctx, projectScopeKey{}, cfg), req)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/honeybadger-io/honeybadger-mcp-server/internal/config"
+)
+
+func TestScopeProjects(t *testing.T) {
+	scoped := &config.Config{AllowedProjectIDs: []int{1, 2}, DeniedProjectIDs: []int{2}}
+	tests := []struct {
+		name    string
+		cfg     *config.Config
+		tool    string
+		args    map[string]any
+		wantErr string
+	}{
+		{"unscoped", &config.Config{}, "list_faults", map[string]any{"project_id": 9}, ""},
+		{"allowed project", scoped, "list_faults", map[string]any{"project_id": 1}, ""},
+		{"not allowed", scoped, "list_faults", map[string]any{"project_id": 3}, "Project 3 is outside"},
+		{"denied", scoped, "list_faults", map[string]any{"project_id": 2}, "Project 2 is outside"},
+		{"string ID", scoped, "list_faults", map[string]any{"project_id": "3"}, "Project 3 is outside"},
+		{"unparseable string ID", scoped, "list_faults", map[string]any{"project_id": " 3"}, "project_id must be a project ID"},
+		{"fractional ID", scoped, "list_faults", map[string]any{"project_id": 1.5}, "project_id must be a project ID"},
+		{"null ID", scoped, "list_faults", map[string]any{"project_id": nil}, ""},
+		{"id argument", scoped, "delete_project", map[string]any{"id": 3}, "Project 3 is outside"},
+		{"id of something else", scoped, "get_alarm", map[string]any{"project_id": 1, "id": 3}, ""},
+		{"no project", scoped, "list_projects", map[string]any{}, ""},
+		{"create with allowlist", scoped, "create_project", map[string]any{"name": "New"}, "create_project is unavailable"},
+		{"create with denylist", &config.Config{DeniedProjectIDs: []int{2}}, "create_project", map[string]any{"name": "New"}, ""},
+		{"setup with allowlist", scoped, "setup_project", map[string]any{"name": "New"}, "setup_project is unavailable"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			handler := scopeProjects(func() *config.Config { return tt.cfg })(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				called = true
+				return mcp.NewToolResultText("ok"), nil
+			})
+
+			req := mcp.CallToolRequest{}
+			req.Params.Name = tt.tool
+			req.Params.Arguments = tt.args
+			result, err := handler(context.Background(), req)
+			if err != nil {
+				t.Fatalf("handler error = %v", err)
+			}
+			if tt.wantErr == "" {
+				if !called || result.IsError {
+					t.Errorf("expected the call to go through, got %q", getResultText(result))
+				}
+				return
+			}
+			if called {
+				t.Error("expected the handler not to run")
+			}
+			if !result.IsError || !strings.Contains(getResultText(result), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %q", tt.wantErr, getResultText(result))
+			}
+		})
+	}
+}
+
+func TestScopeProjects_InvokeTool(t *testing.T) {
+	cfg := reloadTestConfig()
+	cfg.DeferTools = true
+	cfg.AllowedProjectIDs = []int{1}
+	s, _, _ := NewReloadableServer(cfg, "test")
+
+	resp := s.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"invoke_tool","arguments":{"name":"list_faults","arguments":{"project_id":2}}}}`))
+	raw, err := json.Marshal(resp)
+	if err != nil {
+		t.Fatalf("failed to marshal response: %v", err)
+	}
+	if !strings.Contains(string(raw), "Project 2 is outside") {
+		t.Errorf("expected invoke_tool to enforce the project scope, got %s", raw)
+	}
+}
+
+func TestScopeProjects_CoercedIDs(t *testing.T) {
+	cfg := reloadTestConfig()
+	cfg.AllowedProjectIDs = []int{1}
+	s, _, _ := NewReloadableServer(cfg, "test")
+
+	// The scope check sees IDs after coercion, so spellings GetInt can't
+	// parse are still checked against the project they name.
+	for _, id := range []any{" 2", "2.0", "2e0", 2.0} {
+		params, _ := json.Marshal(map[string]any{"name": "list_faults", "arguments": map[string]any{"project_id": id}})
+		resp := s.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":`+string(params)+`}`))
+		raw, err := json.Marshal(resp)
+		if err != nil {
+			t.Fatalf("failed to marshal response: %v", err)
+		}
+		if !strings.Contains(string(raw), "Project 2 is outside") {
+			t.Errorf("project_id %#v: expected the project scope to refuse it, got %s", id, raw)
+		}
+	}
+}
+
+func TestHandleListProjects_Scoped(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"results": [{"id": 1, "name": "Project 1"}, {"id": 2, "name": "Project 2"}, {"id": 3, "name": "Project 3"}]}`))
+	}))
+	defer server.Close()
+
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+	ctx := context.WithValue(context.Background(), projectScopeKey{}, &config.Config{DeniedProjectIDs: []int{2}})
+
+	result, err := handleListProjects(ctx, client, mcp.CallToolRequest{})
+	if err != nil {
+		t.Fatalf("handleListProjects() error = %v", err)
+	}
+	var response projectSummaryResponse
+	if err := json.Unmarshal([]byte(getResultText(result)), &response); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if len(response.Results) != 2 || response.Results[0].ID != 1 || response.Results[1].ID != 3 {
+		t.Errorf("expected projects 1 and 3, got %+v", response.Results)
+	}
+	notes := getResultNotes(t, result)
+	if len(notes.Warnings) != 1 || !strings.Contains(notes.Warnings[0], "omitted 1 projects") {
+		t.Errorf("expected a note about the omitted project, got %v", notes.Warnings)
+	}
+}
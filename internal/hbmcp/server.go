@@ -2,6 +2,12 @@ package hbmcp
 
 import (
 	"context"
+	"crypto/tls"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+	"time"
 
 	hbapi "github.com/honeybadger-io/api-go"
 	"github.com/honeybadger-io/honeybadger-mcp-server/internal/config"
@@ -15,7 +21,7 @@ type ClientFactory func(ctx context.Context) *hbapi.Client
 // In http mode the token's scope is authoritative; in stdio there's no token,
 // so the startup --read-only flag decides. Missing claims fails closed.
 func EffectiveReadOnly(ctx context.Context, cfg *config.Config) bool {
-	if cfg.TransportMode == config.TransportHTTP {
+	if CallTransport(ctx, cfg) == config.TransportHTTP {
 		claims := ClaimsFromContext(ctx)
 		return claims == nil || !claims.HasScope("write")
 	}
@@ -41,14 +47,43 @@ func NewServer(cfg *config.Config, version string) *server.MCPServer {
 // search_tools) so callers like the HTTP landing page can list the
 // server's tools without an MCP session.
 func NewServerWithCatalog(cfg *config.Config, version string) (*server.MCPServer, []ToolInfo) {
+	s, catalog, _ := NewReloadableServer(cfg, version)
+	return s, catalog
+}
+
+// NewReloadableServer also returns a Reloader for applying config changes
+// (tool selection, read-only) to the running server.
+func NewReloadableServer(cfg *config.Config, version string) (*server.MCPServer, []ToolInfo, *Reloader) {
 	logger := logging.SetupLogger(cfg.LogLevel)
 
+	// Handlers and filters read the config through current so a reload can
+	// swap it without racing in-flight requests.
+	live := &atomic.Pointer[config.Config]{}
+	live.Store(cfg)
+	current := live.Load
+
+	watches := newFaultWatches(logger)
+	queryHistory := newInsightsHistory()
+	creates := newCreateReplays()
+	results := newResultCaches()
+	budgets := newSessionBudgets(cfg.SessionMaxAPICalls, cfg.SessionMaxRows)
+	calls := newCallPool(cfg.MaxConcurrentCalls, cfg.QueueTimeout)
+	metrics := newUsageMetrics(time.Now())
+	clientFor, rawFor := NewClientFactories(cfg, logger)
+	names := newProjectNames(clientFor, current)
+
 	hooks := &server.Hooks{}
 	hooks.AddOnRegisterSession(func(ctx context.Context, session server.ClientSession) {
 		logger.Info("Client session registered", "session_id", session.SessionID())
 	})
 	hooks.AddOnUnregisterSession(func(ctx context.Context, session server.ClientSession) {
 		logger.Info("Client session unregistered", "session_id", session.SessionID())
+		watches.stopSession(session.SessionID())
+		queryHistory.forget(session.SessionID())
+		creates.forget(session.SessionID())
+		results.forget(session.SessionID())
+		budgets.forget(session.SessionID())
+		names.forget(session.SessionID())
 	})
 	hooks.AddOnError(func(ctx context.Context, id any, method mcp.MCPMethod, message any, err error) {
 		logger.Error("Error in request", "method", method, "request_id", id, "error", err)
@@ -63,9 +98,25 @@ func NewServerWithCatalog(cfg *config.Config, version string) (*server.MCPServer
 		server.WithLogging(),
 		server.WithRecovery(),
 		server.WithHooks(hooks),
+		server.WithToolHandlerMiddleware(correlateToolCalls(logger)),
+		server.WithToolHandlerMiddleware(traceToolCalls),
+	}
+	if cfg.AuditLogPath != "" {
+		audit, err := openAuditLog(cfg.AuditLogPath)
+		if err != nil {
+			logger.Error("Failed to open audit log; tool calls are disabled", "path", cfg.AuditLogPath, "error", err)
+			serverOptions = append(serverOptions, server.WithToolHandlerMiddleware(failClosed(err)))
+		} else {
+			logger.Info("Audit log enabled", "path", cfg.AuditLogPath)
+			serverOptions = append(serverOptions, server.WithToolHandlerMiddleware(audit.middleware))
+		}
 	}
 	serverOptions = append(serverOptions, server.WithToolFilter(func(ctx context.Context, tools []mcp.Tool) []mcp.Tool {
-		if EffectiveReadOnly(ctx, cfg) {
+		cfg := current()
+		if cfg.DeferTools {
+			return deferredToolView(tools, EffectiveReadOnly(ctx, cfg))
+		}
+		if hidesWriteTools(ctx, cfg) {
 			return filterReadOnlyTools(tools)
 		}
 		return tools
@@ -73,34 +124,253 @@ func NewServerWithCatalog(cfg *config.Config, version string) (*server.MCPServer
 
 	s := server.NewMCPServer("honeybadger-mcp-server", version, serverOptions...)
 
-	clientFor := newClientFactory(cfg)
 	r := newToolRegistrar(s)
-	RegisterReferenceTools(r, newReferenceFetcher(cfg.InstructionsURL, logger))
+	r.logger = logger
+	r.middleware = append(r.middleware,
+		middlewareLayer("metrics", metrics.middleware),
+		names.layer(),
+		middlewareLayer("scope", scopeProjects(current)),
+		middlewareLayer("budget", budgets.middleware),
+		middlewareLayer("concurrency", calls.middleware),
+		middlewareLayer("raw_body", rawBodyFallback),
+	)
+	r.current = current
+	r.creates = creates
+	r.results = results
+	r.lookupNotFound = cfg.NotFoundLookup
+	r.maxResultTokens = cfg.MaxResultTokens
+	if cfg.ConfirmDestructive {
+		r.confirmations = newConfirmations()
+	}
+	fetcher := newReferenceFetcher(cfg.InstructionsURL, logger)
+	fetcher.client.Transport = newBaseTransport(cfg, logger)
+	if cfg.ReferenceCacheDir != "" {
+		fetcher.persist(cfg.ReferenceCacheDir, version)
+	}
+	if cfg.RefreshReference {
+		refreshReference(fetcher, logger)
+	}
+	RegisterReferenceTools(r, fetcher)
 	RegisterProjectTools(r, clientFor)
+	RegisterDefaultProjectTools(r, clientFor, names)
+	RegisterProjectSetupTools(r, clientFor, rawFor)
+	RegisterIntegrationTools(r, clientFor, rawFor)
+	RegisterInstallTools(r, clientFor)
 	RegisterFaultTools(r, clientFor)
-	RegisterInsightsTools(r, clientFor)
+	RegisterFaultOccurrenceTools(r, clientFor, rawFor)
+	RegisterNoticeResources(s, clientFor, current)
+	RegisterInsightsTools(r, clientFor, queryHistory, current)
 	RegisterStreamTools(r, clientFor)
 	RegisterDashboardTools(r, clientFor)
 	RegisterAlarmTools(r, clientFor)
+	RegisterAlarmNotificationTools(r, clientFor, rawFor)
 	RegisterCheckInTools(r, clientFor)
-	registerSearchTool(s, r.catalog, cfg)
+	RegisterTeamTools(r, clientFor)
+	RegisterAccountTools(r, clientFor)
+	RegisterStatusPageTools(r, clientFor)
+	RegisterDiagnosticTools(r, clientFor, current)
+	RegisterWatchTools(r, clientFor, watches)
+	RegisterExportTools(r, clientFor, current)
+	RegisterSourceMapTools(r, clientFor, current, newSourceMapUploader(cfg, logger))
+	RegisterBacktraceSourceTools(r, clientFor, newOutboundHTTPClient(cfg, logger))
+	if cfg.RawAPI {
+		RegisterRawAPITools(r, rawFor)
+	}
+	registerStatsTool(s, metrics, current)
+	r.catalog = append(r.catalog, statsToolInfo)
+
+	registerSearchTool(s, r.catalog, current)
+	catalog := append(r.catalog, searchToolInfo)
+	if cfg.DeferTools {
+		registerInvokeTool(s, current)
+		catalog = append(catalog, invokeToolInfo)
+	}
+
+	// Tools of features the API doesn't serve are left out like disabled
+	// ones, for the server's lifetime.
+	cfg = withDetectedCapabilities(cfg, clientFor, rawFor, logger)
+	live.Store(cfg)
+
+	// Every tool is registered above so a reload can bring disabled ones
+	// back; the current selection is applied here, before any session
+	// exists to be notified.
+	reloader := newReloader(s, live, logger)
+	reloader.applyToolSelection(cfg)
+	if len(cfg.EnabledTools) > 0 || len(cfg.DisabledTools) > 0 {
+		logger.Info("Tool selection applied", "enabled_tools", cfg.EnabledTools, "disabled_tools", cfg.DisabledTools, "registered", len(s.ListTools()))
+	}
+
+	var enabled []ToolInfo
+	for _, t := range catalog {
+		if cfg.ToolEnabled(t.Name) {
+			enabled = append(enabled, t)
+		}
+	}
+	return s, enabled, reloader
+}
+
+// newBaseTransport returns the transport outbound requests start from:
+// http.DefaultTransport, which honors HTTPS_PROXY and friends, unless an
+// explicit proxy or CA bundle is configured. Validate has already checked
+// both, so a failure here only logs and keeps the default.
+func newBaseTransport(cfg *config.Config, logger *slog.Logger) http.RoundTripper {
+	if cfg.ProxyURL == "" && cfg.CABundlePath == "" {
+		return http.DefaultTransport
+	}
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	if cfg.ProxyURL != "" {
+		proxyURL, err := url.Parse(cfg.ProxyURL)
+		if err != nil {
+			logger.Error("Ignoring invalid proxy URL", "error", err)
+			return http.DefaultTransport
+		}
+		t.Proxy = http.ProxyURL(proxyURL)
+		logger.Debug("Using proxy for outbound requests", "proxy", proxyURL.Redacted())
+	}
+	if cfg.CABundlePath != "" {
+		pool, err := cfg.RootCAs()
+		if err != nil {
+			logger.Error("Ignoring CA bundle", "error", err)
+			return http.DefaultTransport
+		}
+		t.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+		logger.Debug("Trusting CA bundle for outbound requests", "path", cfg.CABundlePath)
+	}
+	return t
+}
 
-	return s, append(r.catalog, searchToolInfo)
+// NewClientFactory returns the factory tool handlers use to get an API
+// client for a request. It is exported for CLI commands like doctor that
+// talk to the API the same way the server does.
+func NewClientFactory(cfg *config.Config, logger *slog.Logger) ClientFactory {
+	clientFor, _ := NewClientFactories(cfg, logger)
+	return clientFor
 }
 
-func newClientFactory(cfg *config.Config) ClientFactory {
+// NewClientFactories returns NewClientFactory's factory along with one for
+// raw clients, for endpoints hbapi has no method for. Both share a single
+// http.Client, auth, and transport stack.
+func NewClientFactories(cfg *config.Config, logger *slog.Logger) (ClientFactory, RawClientFactory) {
+	httpClient := newAPIHTTPClient(cfg, logger)
+	token := func(context.Context) string { return cfg.AuthToken }
 	if cfg.TransportMode == config.TransportHTTP {
 		// No fallback to cfg.AuthToken — the 401 middleware must catch
 		// bearer-less requests; a fallback would mask that regression.
-		return func(ctx context.Context) *hbapi.Client {
-			return hbapi.NewClient().
-				WithBaseURL(cfg.APIURL).
-				WithBearerToken(AuthTokenFromContext(ctx))
+		// Only calls that arrived over stdio (--stdio) use the startup
+		// token.
+		token = func(ctx context.Context) string {
+			if CallTransport(ctx, cfg) == config.TransportStdio {
+				return cfg.AuthToken
+			}
+			return AuthTokenFromContext(ctx)
 		}
 	}
-	return func(ctx context.Context) *hbapi.Client {
-		return hbapi.NewClient().
+
+	// The style only depends on the call's transport when none is
+	// configured, and basic-password is never a default.
+	if cfg.ResolvedAuthStyle() == config.AuthStyleBasicPassword {
+		// hbapi only sends a token as the Basic username, so the client
+		// gets no token and the header is set on the way out instead.
+		authed := *httpClient
+		authed.Transport = &basicPasswordTransport{base: httpClient.Transport, token: token}
+		httpClient = &authed
+	}
+	clientFor := func(ctx context.Context) *hbapi.Client {
+		client := hbapi.NewClient().
 			WithBaseURL(cfg.APIURL).
-			WithAuthToken(cfg.AuthToken)
+			WithHTTPClient(httpClient)
+		switch cfg.AuthStyleFor(CallTransport(ctx, cfg)) {
+		case config.AuthStyleBearer:
+			return client.WithBearerToken(token(ctx))
+		case config.AuthStyleBasicPassword:
+			return client
+		default:
+			return client.WithAuthToken(token(ctx))
+		}
+	}
+	rawFor := func(ctx context.Context) *RawClient {
+		client := &RawClient{baseURL: cfg.APIURL, httpClient: httpClient}
+		switch cfg.AuthStyleFor(CallTransport(ctx, cfg)) {
+		case config.AuthStyleBearer:
+			client.bearerToken = token(ctx)
+		case config.AuthStyleBasicPassword:
+		default:
+			client.authToken = token(ctx)
+		}
+		return client
+	}
+	return clientFor, rawFor
+}
+
+// basicPasswordTransport sends the token as the Basic auth password with
+// an empty username, for proxies and on-prem installs that expect it there.
+// The token is looked up per request since http mode forwards each caller's.
+type basicPasswordTransport struct {
+	base  http.RoundTripper
+	token func(context.Context) string
+}
+
+func (t *basicPasswordTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.SetBasicAuth("", t.token(req.Context()))
+	return t.base.RoundTrip(req)
+}
+
+// newOutboundHTTPClient builds a client for requests outside the Data API,
+// such as source map uploads and fetching public source files. They go
+// through the same proxy and CA settings and are traced, but skip the
+// API's retries, revalidation, and fixtures.
+func newOutboundHTTPClient(cfg *config.Config, logger *slog.Logger) *http.Client {
+	timeout := cfg.APITimeout
+	if timeout == 0 {
+		timeout = config.DefaultAPITimeout
+	}
+	return &http.Client{Timeout: timeout, Transport: &tracingTransport{base: newBaseTransport(cfg, logger)}}
+}
+
+// newAPIHTTPClient builds the single http.Client shared by every API client
+// the factory hands out, so connections are pooled across tool calls.
+// Transport-level behavior (tracing, retries, fault injection, the API
+// path prefix, ETag revalidation, lenient decoding, keeping bodies for
+// rawBodyFallback, and the correlation header) is layered on here.
+func newAPIHTTPClient(cfg *config.Config, logger *slog.Logger) *http.Client {
+	transport := newBaseTransport(cfg, logger)
+	if cfg.FixturesDir != "" {
+		logger.Warn("Fixture mode enabled: API requests are answered from recorded responses", "dir", cfg.FixturesDir)
+		transport = &fixtureTransport{dir: cfg.FixturesDir, logger: logger}
+	}
+	if cfg.ChaosRate > 0 {
+		logger.Warn("Chaos mode enabled: API requests will fail at random", "rate", cfg.ChaosRate)
+		transport = newChaosTransport(transport, cfg.ChaosRate, logger)
+	}
+	if prefix := cfg.APIPathPrefix; prefix != "" && prefix != config.DefaultAPIPathPrefix {
+		transport = newAPIPrefixTransport(transport, cfg.APIURL, prefix)
+	}
+	// Injected faults are traced and logged like real ones, once per
+	// attempt.
+	transport = &tracingTransport{base: transport}
+	sampleRate := cfg.APILogSampleRate
+	if sampleRate == 0 {
+		sampleRate = config.DefaultAPILogSampleRate
+	}
+	transport = logging.NewTransport(transport, logger, sampleRate)
+	transport = &metricsTransport{base: transport}
+	transport = &budgetTransport{base: transport}
+	if cfg.APIRetries > 0 {
+		transport = newRetryTransport(transport, cfg.APIRetries, cfg.APIRetryBackoff, logger)
+	}
+	transport = newConditionalTransport(transport, logger)
+	if cfg.LenientDecoding {
+		transport = &lenientTransport{base: transport, logger: logger}
+	}
+	transport = &rawBodyTransport{base: transport}
+	transport = &correlationTransport{base: transport}
+	timeout := cfg.APITimeout
+	if timeout == 0 {
+		timeout = config.DefaultAPITimeout
+	}
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
 	}
 }
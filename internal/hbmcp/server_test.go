@@ -3,6 +3,7 @@ package hbmcp
 import (
 	"context"
 	"encoding/json"
+	"slices"
 	"testing"
 
 	"github.com/honeybadger-io/honeybadger-mcp-server/internal/config"
@@ -67,6 +68,146 @@ func TestAllToolsHaveTitleAndAnnotations(t *testing.T) {
 	}
 }
 
+// TestToolAnnotations pins the hints every tool advertises. mcp.NewTool
+// defaults to a destructive, non-idempotent, open-world tool, so a tool
+// that forgets a hint is advertised wrongly rather than without one; a new
+// tool has to be added here. Tools that only talk to the Honeybadger API
+// are closed-world; those that reach other sites, or email someone, are
+// open-world.
+func TestToolAnnotations(t *testing.T) {
+	type hints struct{ readOnly, destructive, idempotent, openWorld bool }
+	want := map[string]hints{
+		"list_alarms":                   {true, false, true, false},
+		"get_alarm":                     {true, false, true, false},
+		"get_alarm_history":             {true, false, true, false},
+		"evaluate_alarm_query":          {true, false, true, false},
+		"resolve_backtrace_source":      {true, false, true, true},
+		"list_check_ins":                {true, false, true, false},
+		"get_check_in":                  {true, false, true, false},
+		"list_dashboards":               {true, false, true, false},
+		"get_dashboard":                 {true, false, true, false},
+		"build_insights_widget":         {true, false, true, false},
+		"check_connection":              {true, false, true, false},
+		"whoami":                        {true, false, true, false},
+		"get_server_info":               {true, false, true, false},
+		"list_faults":                   {true, false, true, false},
+		"get_fault":                     {true, false, true, false},
+		"list_fault_notices":            {true, false, true, false},
+		"list_fault_affected_users":     {true, false, true, false},
+		"search_user_impact":            {true, false, true, false},
+		"get_fault_counts":              {true, false, true, false},
+		"get_fault_occurrence_counts":   {true, false, true, false},
+		"get_account_fault_counts":      {true, false, true, false},
+		"export_fault_graph":            {true, false, true, false},
+		"aggregate_fault_notices":       {true, false, true, false},
+		"summarize_notice_patterns":     {true, false, true, false},
+		"get_fault_context_keys":        {true, false, true, false},
+		"compare_fault_counts":          {true, false, true, false},
+		"compare_fault_notices":         {true, false, true, false},
+		"analyze_fault_trend":           {true, false, true, false},
+		"find_similar_faults":           {true, false, true, false},
+		"search_project_notices":        {true, false, true, false},
+		"query_insights":                {true, false, true, false},
+		"query_insights_batch":          {true, false, true, false},
+		"list_insights_query_history":   {true, false, true, false},
+		"validate_insights_query":       {true, false, true, false},
+		"get_insights_schema":           {true, false, true, false},
+		"stats":                         {true, false, true, false},
+		"list_projects":                 {true, false, true, false},
+		"get_project":                   {true, false, true, false},
+		"find_project_by_token":         {true, false, true, false},
+		"get_project_settings_diff":     {true, false, true, false},
+		"get_project_occurrence_counts": {true, false, true, false},
+		"get_project_integrations":      {true, false, true, false},
+		"test_project_integration":      {false, false, false, true},
+		"get_project_report":            {true, false, true, false},
+		"generate_error_digest":         {true, false, true, false},
+		"get_reference":                 {true, false, true, false},
+		"check_source_maps":             {true, false, true, false},
+		"list_status_pages":             {true, false, true, false},
+		"get_status_page":               {true, false, true, false},
+		"create_status_page":            {false, true, false, false},
+		"update_status_page":            {false, true, true, false},
+		"delete_status_page":            {false, true, true, false},
+		"list_streams":                  {true, false, true, false},
+		"list_team_invitations":         {true, false, true, false},
+		"list_account_users":            {true, false, true, false},
+		"invite_account_user":           {false, true, false, true},
+		"remove_account_user":           {false, true, true, false},
+		"resolve_user":                  {true, false, true, false},
+		"search_tools":                  {true, false, true, false},
+		"watch_faults":                  {true, false, false, false},
+		"unwatch_faults":                {true, false, true, false},
+		"set_default_project":           {true, false, true, false},
+		"get_default_project":           {true, false, true, false},
+		"create_alarm":                  {false, true, false, false},
+		"update_alarm":                  {false, true, true, false},
+		"get_alarm_notifications":       {true, false, true, false},
+		"update_alarm_notifications":    {false, true, true, false},
+		"delete_alarm":                  {false, true, true, false},
+		"create_check_in":               {false, true, false, false},
+		"update_check_in":               {false, true, true, false},
+		"delete_check_in":               {false, true, true, false},
+		"create_dashboard":              {false, true, false, false},
+		"update_dashboard":              {false, true, true, false},
+		"delete_dashboard":              {false, true, true, false},
+		"update_fault":                  {false, true, true, false},
+		"create_project":                {false, true, false, false},
+		"setup_project":                 {false, true, false, false},
+		"get_install_instructions":      {true, false, true, false},
+		"update_project":                {false, true, true, false},
+		"delete_project":                {false, true, true, false},
+		"raw_api_request":               {false, true, false, false},
+		"upload_source_map":             {false, true, true, false},
+		"create_team_invitation":        {false, true, false, true},
+		"delete_team_invitation":        {false, true, true, false},
+		"invoke_tool":                   {false, true, false, true},
+		"export_fault_notices":          {false, false, false, false},
+	}
+
+	cfg := &config.Config{
+		AuthToken:     "test-token",
+		APIURL:        "https://api.honeybadger.io/v2",
+		LogLevel:      "info",
+		TransportMode: config.TransportStdio,
+		RawAPI:        true,
+	}
+	s := NewServer(cfg, "test")
+	resp := s.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`))
+	respBytes, err := json.Marshal(resp)
+	if err != nil {
+		t.Fatalf("failed to marshal tools/list response: %v", err)
+	}
+	var parsed struct {
+		Result struct {
+			Tools []mcp.Tool `json:"tools"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(respBytes, &parsed); err != nil {
+		t.Fatalf("failed to unmarshal tools/list response: %v", err)
+	}
+
+	if len(parsed.Result.Tools) == 0 {
+		t.Fatal("tools/list returned no tools")
+	}
+	for _, tool := range parsed.Result.Tools {
+		expected, ok := want[tool.Name]
+		if !ok {
+			t.Errorf("tool %q has no expected annotations; add it to this test", tool.Name)
+			continue
+		}
+		a := tool.Annotations
+		if a.ReadOnlyHint == nil || a.DestructiveHint == nil || a.IdempotentHint == nil || a.OpenWorldHint == nil {
+			t.Errorf("tool %q is missing a hint: %+v", tool.Name, a)
+			continue
+		}
+		got := hints{*a.ReadOnlyHint, *a.DestructiveHint, *a.IdempotentHint, *a.OpenWorldHint}
+		if got != expected {
+			t.Errorf("tool %q advertises %+v, want %+v", tool.Name, got, expected)
+		}
+	}
+}
+
 func TestNewServer(t *testing.T) {
 	cfg := &config.Config{
 		AuthToken: "test-token",
@@ -155,6 +296,42 @@ func TestNewServerWithCatalog(t *testing.T) {
 	}
 }
 
+func TestNewServerWithCatalog_ToolSelection(t *testing.T) {
+	cfg := &config.Config{
+		AuthToken:     "test-token",
+		APIURL:        "https://api.honeybadger.io/v2",
+		LogLevel:      "info",
+		TransportMode: config.TransportStdio,
+		EnabledTools:  []string{"list_*", "get_fault"},
+		DisabledTools: []string{"list_fault_*"},
+	}
+
+	s, catalog := NewServerWithCatalog(cfg, "test")
+
+	var names []string
+	for _, tool := range catalog {
+		names = append(names, tool.Name)
+		if !cfg.ToolEnabled(tool.Name) {
+			t.Errorf("catalog includes disabled tool %q", tool.Name)
+		}
+	}
+	for _, want := range []string{"list_projects", "list_faults", "get_fault"} {
+		if !slices.Contains(names, want) {
+			t.Errorf("catalog missing enabled tool %q: %v", want, names)
+		}
+	}
+	if slices.Contains(names, "search_tools") {
+		t.Error("search_tools should not be registered when it doesn't match enabled tools")
+	}
+
+	// Disabled tools must not be callable, not just hidden from tools/list.
+	callMsg := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"delete_project","arguments":{"id":1}}}`
+	resp := s.HandleMessage(context.Background(), []byte(callMsg))
+	if _, ok := resp.(mcp.JSONRPCError); !ok {
+		t.Errorf("expected JSON-RPC error calling a disabled tool, got %T", resp)
+	}
+}
+
 func TestEffectiveReadOnly(t *testing.T) {
 	withClaims := func(scopes ...string) context.Context {
 		return WithClaims(context.Background(), &Claims{Scopes: scopes})
@@ -208,6 +385,18 @@ func TestEffectiveReadOnly(t *testing.T) {
 			cfg:  &config.Config{TransportMode: config.TransportHTTP, ReadOnly: false},
 			want: true,
 		},
+		{
+			name: "http + --stdio, stdio call + read-only flag → read-only",
+			ctx:  WithStdioTransport(context.Background()),
+			cfg:  &config.Config{TransportMode: config.TransportHTTP, ServeStdio: true, ReadOnly: true},
+			want: true,
+		},
+		{
+			name: "http + --stdio, stdio call without claims + no read-only flag → not read-only",
+			ctx:  WithStdioTransport(context.Background()),
+			cfg:  &config.Config{TransportMode: config.TransportHTTP, ServeStdio: true, ReadOnly: false},
+			want: false,
+		},
 	}
 	for _, c := range cases {
 		t.Run(c.name, func(t *testing.T) {
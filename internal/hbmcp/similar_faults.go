@@ -0,0 +1,243 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	similarInResolved = "resolved"
+	similarInProjects = "other_projects"
+	similarInAll      = "all"
+
+	defaultSimilarLimit = 10
+)
+
+// How much each part of the signature adds to a match's score. Every
+// candidate shares the class, since that is what's searched for.
+const (
+	similarClassWeight     = 0.5
+	similarComponentWeight = 0.2
+	similarMessageWeight   = 0.3
+)
+
+type faultSignature struct {
+	ProjectID int    `json:"project_id"`
+	FaultID   int    `json:"fault_id"`
+	Class     string `json:"class"`
+	Component string `json:"component,omitempty"`
+	Message   string `json:"message"`
+}
+
+type similarFault struct {
+	ProjectID    int        `json:"project_id"`
+	ProjectName  string     `json:"project_name,omitempty"`
+	FaultID      int        `json:"fault_id"`
+	Class        string     `json:"class"`
+	Component    string     `json:"component,omitempty"`
+	Message      string     `json:"message"`
+	Resolved     bool       `json:"resolved"`
+	NoticesCount int        `json:"notices_count"`
+	LastNoticeAt *time.Time `json:"last_notice_at,omitempty"`
+	URL          string     `json:"url,omitempty"`
+	Score        float64    `json:"score"`
+	Matched      []string   `json:"matched"`
+}
+
+type similarFaults struct {
+	Fault             faultSignature `json:"fault"`
+	Similar           []similarFault `json:"similar"`
+	ProjectsSearched  int            `json:"projects_searched"`
+	CandidatesScanned int            `json:"candidates_scanned"`
+}
+
+func handleFindSimilarFaults(ctx context.Context, client *hbapi.Client, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	projectID := req.GetInt("project_id", 0)
+	if projectID == 0 {
+		return mcp.NewToolResultError("project_id is required"), nil
+	}
+
+	faultID := req.GetInt("fault_id", 0)
+	if faultID == 0 {
+		return mcp.NewToolResultError("fault_id is required"), nil
+	}
+
+	searchIn := req.GetString("search_in", similarInAll)
+	if searchIn != similarInResolved && searchIn != similarInProjects && searchIn != similarInAll {
+		return mcp.NewToolResultError("search_in must be 'resolved', 'other_projects', or 'all'"), nil
+	}
+
+	limit := req.GetInt("limit", defaultSimilarLimit)
+	if limit < 1 {
+		return mcp.NewToolResultError("limit must be at least 1"), nil
+	}
+
+	fault, err := client.Faults.Get(ctx, projectID, faultID)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to get fault: %v", err)), nil
+	}
+	signature := faultSignature{
+		ProjectID: projectID,
+		FaultID:   faultID,
+		Class:     fault.Klass,
+		Component: fault.Component,
+		Message:   fault.Message,
+	}
+
+	type target struct {
+		id   int
+		name string
+		q    string
+	}
+	classQuery := "class:" + strconv.Quote(fault.Klass)
+	var targets []target
+	if searchIn != similarInProjects {
+		targets = append(targets, target{id: projectID, q: classQuery + " is:resolved"})
+	}
+
+	var notes toolNotes
+	if searchIn != similarInResolved {
+		var projects *hbapi.ProjectsResponse
+		if accountID := req.GetString("account_id", ""); accountID != "" {
+			projects, err = client.Projects.ListByAccountID(ctx, accountID)
+		} else {
+			projects, err = client.Projects.ListAll(ctx)
+		}
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to list projects: %v", err)), nil
+		}
+		if projects.Links.Next != "" {
+			notes.warnf("only the first %d projects were searched; pass account_id to narrow the list", len(projects.Results))
+		}
+		for _, p := range projects.Results {
+			if p.ID == projectID {
+				if len(targets) > 0 {
+					targets[0].name = p.Name
+				}
+				continue
+			}
+			if projectAllowed(ctx, p.ID) {
+				targets = append(targets, target{id: p.ID, name: p.Name, q: classQuery})
+			}
+		}
+	}
+
+	response := similarFaults{Fault: signature, Similar: []similarFault{}}
+	words := messageWords(fault.Message)
+	err = eachProject(targets, func(t target) (int, string) { return t.id, t.name }, &notes, func(t target) error {
+		// The API returns at most 25 faults a page; the most frequent are
+		// the likeliest to have been investigated before.
+		faults, err := client.Faults.List(ctx, t.id, hbapi.FaultListOptions{Q: t.q, Order: "frequent", Limit: 25})
+		if err != nil {
+			return err
+		}
+		response.ProjectsSearched++
+		if faults.Links.Next != "" {
+			notes.warnf("project %d has more than %d faults of class %s; only the most frequent were compared", t.id, len(faults.Results), fault.Klass)
+		}
+		for _, f := range faults.Results {
+			if f.ID == faultID || f.Klass != fault.Klass {
+				continue
+			}
+			response.CandidatesScanned++
+			response.Similar = append(response.Similar, scoreSimilarFault(fault, words, f, t.id, t.name))
+		}
+		return nil
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to list faults: %v", err)), nil
+	}
+
+	sort.SliceStable(response.Similar, func(i, j int) bool {
+		a, b := response.Similar[i], response.Similar[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.Resolved != b.Resolved {
+			return a.Resolved
+		}
+		return a.NoticesCount > b.NoticesCount
+	})
+	if len(response.Similar) > limit {
+		response.Similar = response.Similar[:limit]
+	}
+
+	// Return JSON response
+	jsonBytes, err := json.Marshal(response)
+	if err != nil {
+		return mcp.NewToolResultError("Failed to marshal response"), nil
+	}
+
+	return withNotes(mcp.NewToolResultText(string(jsonBytes)), &notes), nil
+}
+
+// scoreSimilarFault rates how closely candidate matches fault: the class,
+// the component, and the overlap between their messages' words.
+func scoreSimilarFault(fault *hbapi.Fault, words map[string]bool, candidate hbapi.Fault, projectID int, projectName string) similarFault {
+	match := similarFault{
+		ProjectID:    projectID,
+		ProjectName:  projectName,
+		FaultID:      candidate.ID,
+		Class:        candidate.Klass,
+		Component:    candidate.Component,
+		Message:      candidate.Message,
+		Resolved:     candidate.Resolved,
+		NoticesCount: candidate.NoticesCount,
+		LastNoticeAt: candidate.LastNoticeAt,
+		URL:          candidate.URL,
+		Score:        similarClassWeight,
+		Matched:      []string{"class"},
+	}
+	if fault.Component != "" && candidate.Component == fault.Component {
+		match.Score += similarComponentWeight
+		match.Matched = append(match.Matched, "component")
+	}
+	if overlap := wordOverlap(words, messageWords(candidate.Message)); overlap > 0 {
+		match.Score += similarMessageWeight * overlap
+		if overlap >= 0.5 {
+			match.Matched = append(match.Matched, "message")
+		}
+	}
+	match.Score = math.Round(match.Score*100) / 100
+	return match
+}
+
+// messageWords returns the words of an error message that describe the
+// error rather than one occurrence of it: words containing digits, such
+// as IDs, counts, and addresses, are left out.
+func messageWords(message string) map[string]bool {
+	words := map[string]bool{}
+	for _, w := range strings.FieldsFunc(strings.ToLower(message), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if !strings.ContainsFunc(w, unicode.IsDigit) {
+			words[w] = true
+		}
+	}
+	return words
+}
+
+// wordOverlap is the Jaccard index of two sets of words: the share of
+// words in either that are in both.
+func wordOverlap(a, b map[string]bool) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for w := range a {
+		if b[w] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
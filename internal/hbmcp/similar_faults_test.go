@@ -0,0 +1,194 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/honeybadger-io/honeybadger-mcp-server/internal/config"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func similarFaultsServer(t *testing.T, queries map[string]string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v2/projects/1/faults/10":
+			_, _ = w.Write([]byte(`{"id": 10, "project_id": 1, "klass": "ActiveRecord::RecordNotFound", "component": "users", "message": "Couldn't find User with 'id'=42"}`))
+		case "/v2/projects":
+			_, _ = w.Write([]byte(`{"results": [{"id": 1, "name": "Web"}, {"id": 2, "name": "API"}, {"id": 3, "name": "Locked"}], "links": {}}`))
+		case "/v2/projects/1/faults":
+			queries["1"] = r.URL.Query().Get("q")
+			_, _ = w.Write([]byte(`{"results": [
+				{"id": 10, "klass": "ActiveRecord::RecordNotFound", "component": "users", "message": "Couldn't find User with 'id'=42"},
+				{"id": 11, "klass": "ActiveRecord::RecordNotFound", "component": "users", "message": "Couldn't find User with 'id'=7", "resolved": true, "notices_count": 3}
+			], "links": {}}`))
+		case "/v2/projects/2/faults":
+			queries["2"] = r.URL.Query().Get("q")
+			_, _ = w.Write([]byte(`{"results": [
+				{"id": 20, "klass": "ActiveRecord::RecordNotFound", "component": "orders", "message": "Couldn't find Order with 'id'=9", "notices_count": 50},
+				{"id": 21, "klass": "ActiveRecord::RecordNotFoundError", "component": "users", "message": "Couldn't find User"}
+			], "links": {}}`))
+		case "/v2/projects/3/faults":
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors": "Forbidden"}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestHandleFindSimilarFaults(t *testing.T) {
+	queries := map[string]string{}
+	server := similarFaultsServer(t, queries)
+	defer server.Close()
+
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"project_id": float64(1), "fault_id": float64(10)}
+
+	result, err := handleFindSimilarFaults(context.Background(), client, req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("unexpected error result: %s", getResultText(result))
+	}
+
+	if queries["1"] != `class:"ActiveRecord::RecordNotFound" is:resolved` {
+		t.Errorf("expected the project's resolved faults to be searched, got q=%q", queries["1"])
+	}
+	if queries["2"] != `class:"ActiveRecord::RecordNotFound"` {
+		t.Errorf("expected other projects to be searched by class, got q=%q", queries["2"])
+	}
+
+	var response similarFaults
+	if err := json.Unmarshal([]byte(getResultText(result)), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if response.Fault.Class != "ActiveRecord::RecordNotFound" || response.Fault.Component != "users" {
+		t.Errorf("unexpected signature: %+v", response.Fault)
+	}
+	if response.ProjectsSearched != 2 || response.CandidatesScanned != 2 {
+		t.Errorf("expected 2 projects and 2 candidates, got %d and %d", response.ProjectsSearched, response.CandidatesScanned)
+	}
+	if len(response.Similar) != 2 {
+		t.Fatalf("expected 2 similar faults, got %+v", response.Similar)
+	}
+
+	best := response.Similar[0]
+	if best.FaultID != 11 || best.ProjectName != "Web" || !best.Resolved || best.Score != 1 {
+		t.Errorf("expected the resolved fault with the same signature first, got %+v", best)
+	}
+	if strings.Join(best.Matched, ",") != "class,component,message" {
+		t.Errorf("expected class, component, and message to match, got %v", best.Matched)
+	}
+	other := response.Similar[1]
+	if other.FaultID != 20 || other.ProjectID != 2 || other.Score >= best.Score || strings.Join(other.Matched, ",") != "class,message" {
+		t.Errorf("expected the other project's fault second, got %+v", other)
+	}
+
+	notes := getResultNotes(t, result)
+	if len(notes.Warnings) != 1 || !strings.Contains(notes.Warnings[0], "skipped project 3") {
+		t.Errorf("expected a warning for the inaccessible project, got %v", notes.Warnings)
+	}
+}
+
+func TestHandleFindSimilarFaults_SearchIn(t *testing.T) {
+	queries := map[string]string{}
+	server := similarFaultsServer(t, queries)
+	defer server.Close()
+
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"project_id": float64(1), "fault_id": float64(10), "search_in": "resolved"}
+
+	result, _ := handleFindSimilarFaults(context.Background(), client, req)
+	var response similarFaults
+	if err := json.Unmarshal([]byte(getResultText(result)), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if response.ProjectsSearched != 1 || len(response.Similar) != 1 || response.Similar[0].FaultID != 11 {
+		t.Errorf("expected only the project's resolved faults, got %+v", response)
+	}
+	if _, ok := queries["2"]; ok {
+		t.Error("expected other projects not to be searched")
+	}
+
+	clear(queries)
+	req.Params.Arguments = map[string]any{"project_id": float64(1), "fault_id": float64(10), "search_in": "other_projects", "limit": float64(1)}
+	result, _ = handleFindSimilarFaults(context.Background(), client, req)
+	if err := json.Unmarshal([]byte(getResultText(result)), &response); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if len(response.Similar) != 1 || response.Similar[0].FaultID != 20 {
+		t.Errorf("expected the one best match from other projects, got %+v", response.Similar)
+	}
+	if _, ok := queries["1"]; ok {
+		t.Error("expected the fault's own project not to be searched")
+	}
+}
+
+func TestHandleFindSimilarFaults_Scope(t *testing.T) {
+	queries := map[string]string{}
+	server := similarFaultsServer(t, queries)
+	defer server.Close()
+
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+	cfg := &config.Config{AllowedProjectIDs: []int{1, 3}}
+	handler := scopeProjects(staticConfig(cfg))(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return handleFindSimilarFaults(ctx, client, req)
+	})
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"project_id": float64(1), "fault_id": float64(10)}
+
+	if _, err := handler(context.Background(), req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := queries["2"]; ok {
+		t.Error("expected projects outside the scope not to be searched")
+	}
+}
+
+func TestHandleFindSimilarFaults_Validation(t *testing.T) {
+	client := hbapi.NewClient()
+	tests := []struct {
+		name string
+		args map[string]any
+		want string
+	}{
+		{"missing project_id", map[string]any{"fault_id": float64(10)}, "project_id is required"},
+		{"missing fault_id", map[string]any{"project_id": float64(1)}, "fault_id is required"},
+		{"bad search_in", map[string]any{"project_id": float64(1), "fault_id": float64(10), "search_in": "everywhere"}, "search_in must be"},
+		{"bad limit", map[string]any{"project_id": float64(1), "fault_id": float64(10), "limit": float64(0)}, "limit must be at least 1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := mcp.CallToolRequest{}
+			req.Params.Arguments = tt.args
+			result, _ := handleFindSimilarFaults(context.Background(), client, req)
+			if !result.IsError || !strings.Contains(getResultText(result), tt.want) {
+				t.Errorf("expected error containing %q, got %q", tt.want, getResultText(result))
+			}
+		})
+	}
+}
+
+func TestWordOverlap(t *testing.T) {
+	a := messageWords("Couldn't find User with 'id'=42")
+	if a["42"] || !a["user"] {
+		t.Errorf("expected words without digits, got %v", a)
+	}
+	if got := wordOverlap(a, messageWords("Couldn't find User with 'id'=7")); got != 1 {
+		t.Errorf("expected messages differing only in IDs to match fully, got %v", got)
+	}
+	if got := wordOverlap(a, messageWords("undefined method for nil")); got != 0 {
+		t.Errorf("expected unrelated messages not to overlap, got %v", got)
+	}
+}
@@ -0,0 +1,378 @@
+package hbmcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+
+	"github.com/honeybadger-io/honeybadger-mcp-server/internal/config"
+)
+
+const (
+	sourceMapsSymbolicated = "symbolicated"
+	sourceMapsPartly       = "partly_symbolicated"
+	sourceMapsMinified     = "minified"
+	sourceMapsNoJavaScript = "no_javascript_frames"
+
+	sourceMapUploadPath = "/v1/source_maps"
+
+	// maxSourceMapBytes caps each file upload_source_map reads into memory.
+	maxSourceMapBytes = 50 << 20
+
+	// A frame this far into a line of JavaScript is in minified code:
+	// people don't write lines that long, bundlers do.
+	minifiedColumn = 500
+)
+
+// sourceMapCheck is the result of check_source_maps.
+type sourceMapCheck struct {
+	ProjectID          int            `json:"project_id"`
+	FaultID            int            `json:"fault_id"`
+	NoticeID           string         `json:"notice_id"`
+	Revision           string         `json:"revision,omitempty"`
+	Status             string         `json:"status"`
+	SymbolicatedFrames int            `json:"symbolicated_frames"`
+	MinifiedFrames     int            `json:"minified_frames"`
+	MinifiedFiles      []minifiedFile `json:"minified_files,omitempty"`
+}
+
+// minifiedFile is a file minified frames point into: the minified_url a
+// source map for it must be uploaded with.
+type minifiedFile struct {
+	URL    string `json:"url"`
+	Frames int    `json:"frames"`
+}
+
+// sourceMapUpload is the result of upload_source_map.
+type sourceMapUpload struct {
+	ProjectID      int    `json:"project_id"`
+	Revision       string `json:"revision"`
+	MinifiedURL    string `json:"minified_url"`
+	SourceMapBytes int    `json:"source_map_bytes"`
+	MinifiedBytes  int    `json:"minified_bytes,omitempty"`
+}
+
+// sourceMapUploader posts source maps to Honeybadger's upload endpoint,
+// which is part of the reporting API rather than the Data API hbapi
+// covers, and authenticates with a project's API key.
+type sourceMapUploader struct {
+	client   *http.Client
+	endpoint string
+}
+
+func newSourceMapUploader(cfg *config.Config, logger *slog.Logger) *sourceMapUploader {
+	return &sourceMapUploader{
+		client:   newOutboundHTTPClient(cfg, logger),
+		endpoint: sourceMapEndpoint(cfg.APIURL),
+	}
+}
+
+// sourceMapEndpoint returns the upload URL for an API URL. Honeybadger
+// serves uploads from the api host next to the app host (api.honeybadger.io
+// for app.honeybadger.io, eu-api for eu-app); other hosts, such as
+// self-hosted proxies, are assumed to serve both.
+func sourceMapEndpoint(apiURL string) string {
+	u, err := url.Parse(apiURL)
+	if err != nil || u.Host == "" {
+		return strings.TrimRight(apiURL, "/") + sourceMapUploadPath
+	}
+	if rest, ok := strings.CutPrefix(u.Host, "app."); ok {
+		u.Host = "api." + rest
+	} else if rest, ok := strings.CutPrefix(u.Host, "eu-app."); ok {
+		u.Host = "eu-api." + rest
+	}
+	u.Path = sourceMapUploadPath
+	return u.String()
+}
+
+// RegisterSourceMapTools registers the check_source_maps and
+// upload_source_map tools
+func RegisterSourceMapTools(r *toolRegistrar, clientFor ClientFactory, current func() *config.Config, uploader *sourceMapUploader) {
+	// check_source_maps tool
+	r.AddTool(
+		mcp.NewTool("check_source_maps",
+			mcp.WithTitleAnnotation("Check Source Maps"),
+			mcp.WithDescription("Check whether a JavaScript fault's backtrace was symbolicated with a source map, using its latest notice. Returns status ('symbolicated', 'partly_symbolicated', 'minified', or 'no_javascript_frames'), the notice's revision, and each minified file's URL with its frame count. Honeybadger's API can't list uploaded source maps, so this is how to tell whether one matched: a minified frame means no source map was found for that file and revision. Upload one with upload_source_map, then check a notice reported after the upload."),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithOutputSchema[sourceMapCheck](),
+			mcp.WithNumber("project_id",
+				mcp.Required(),
+				mcp.Description("The ID of the project containing the fault"),
+				mcp.Min(1),
+			),
+			mcp.WithNumber("fault_id",
+				mcp.Required(),
+				mcp.Description("The ID of the fault to check"),
+				mcp.Min(1),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleCheckSourceMaps(ctx, clientFor(ctx), req)
+		},
+	)
+
+	// upload_source_map tool
+	r.AddTool(
+		mcp.NewTool("upload_source_map",
+			mcp.WithTitleAnnotation("Upload Source Map"),
+			mcp.WithDescription("Upload a source map from a local file so Honeybadger can symbolicate minified JavaScript backtraces. Maps apply to notices reported with the same revision for the same minified_url, from when the upload finishes; notices already reported are not reprocessed. Only available when the server runs over stdio, since the files are read from the server's machine."),
+			mcp.WithReadOnlyHintAnnotation(false),
+			mcp.WithDestructiveHintAnnotation(true),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithNumber("project_id",
+				mcp.Required(),
+				mcp.Description("The ID of the project the source map is for"),
+				mcp.Min(1),
+			),
+			mcp.WithString("revision",
+				mcp.Required(),
+				mcp.Description("The revision of the deployed code, as the JavaScript notifier reports it (check_source_maps shows a notice's revision)"),
+			),
+			mcp.WithString("minified_url",
+				mcp.Required(),
+				mcp.Description("The URL of the minified file the map is for, as it appears in backtraces. '*' may stand for the scheme and host, e.g. 'http*://cdn.example.com/app.min.js'."),
+			),
+			mcp.WithString("source_map_path",
+				mcp.Required(),
+				mcp.Description("Absolute path of the source map file"),
+			),
+			mcp.WithString("minified_file_path",
+				mcp.Description("Absolute path of the minified file. Optional, but Honeybadger recommends it so frames can show the minified source too."),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if CallTransport(ctx, current()) == config.TransportHTTP {
+				return mcp.NewToolResultError("upload_source_map reads files from the server's filesystem, so it is only available over stdio"), nil
+			}
+			return handleUploadSourceMap(ctx, clientFor(ctx), uploader, req)
+		},
+	)
+}
+
+func handleCheckSourceMaps(ctx context.Context, client *hbapi.Client, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	projectID := req.GetInt("project_id", 0)
+	if projectID == 0 {
+		return mcp.NewToolResultError("project_id is required"), nil
+	}
+
+	faultID := req.GetInt("fault_id", 0)
+	if faultID == 0 {
+		return mcp.NewToolResultError("fault_id is required"), nil
+	}
+
+	response, err := client.Faults.ListNotices(ctx, projectID, faultID, hbapi.FaultListNoticesOptions{Limit: 1})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to list fault notices: %v", err)), nil
+	}
+	if len(response.Results) == 0 {
+		return mcp.NewToolResultError("No notices found for this fault"), nil
+	}
+	notice := response.Results[0]
+
+	check := sourceMapCheck{ProjectID: projectID, FaultID: faultID, NoticeID: notice.ID}
+	if notice.Environment.Revision != nil {
+		check.Revision = *notice.Environment.Revision
+	}
+	files := map[string]int{}
+	for _, frame := range notice.Backtrace {
+		switch frameKind(frame) {
+		case sourceMapsSymbolicated:
+			check.SymbolicatedFrames++
+		case sourceMapsMinified:
+			check.MinifiedFrames++
+			if files[frame.File] == 0 {
+				check.MinifiedFiles = append(check.MinifiedFiles, minifiedFile{URL: frame.File})
+			}
+			files[frame.File]++
+		}
+	}
+	for i := range check.MinifiedFiles {
+		check.MinifiedFiles[i].Frames = files[check.MinifiedFiles[i].URL]
+	}
+
+	var notes toolNotes
+	switch {
+	case check.MinifiedFrames == 0 && check.SymbolicatedFrames == 0:
+		check.Status = sourceMapsNoJavaScript
+	case check.MinifiedFrames == 0:
+		check.Status = sourceMapsSymbolicated
+	case check.SymbolicatedFrames == 0:
+		check.Status = sourceMapsMinified
+	default:
+		check.Status = sourceMapsPartly
+	}
+	if check.MinifiedFrames > 0 && check.Revision == "" {
+		notes.warnf("the notice has no revision, so no source map can match it; configure the JavaScript notifier's revision to match the one maps are uploaded with")
+	}
+
+	// Return JSON response
+	jsonBytes, err := json.Marshal(check)
+	if err != nil {
+		return mcp.NewToolResultError("Failed to marshal response"), nil
+	}
+
+	return withNotes(mcp.NewToolResultText(string(jsonBytes)), &notes), nil
+}
+
+// frameKind classifies a backtrace frame as symbolicated (in original
+// source), minified (in a minified JavaScript file), or neither ("") when
+// it isn't JavaScript or can't be told apart.
+func frameKind(frame hbapi.BacktraceEntry) string {
+	file := frame.File
+	if u, err := url.Parse(file); err == nil && u.Scheme != "" {
+		if u.Scheme == "webpack" {
+			return sourceMapsSymbolicated
+		}
+		file = u.Path
+	}
+	switch strings.ToLower(path.Ext(file)) {
+	case ".ts", ".tsx", ".jsx", ".vue", ".svelte", ".coffee":
+		return sourceMapsSymbolicated
+	case ".js", ".mjs", ".cjs":
+	default:
+		return ""
+	}
+	if strings.Contains(path.Base(file), ".min.") || (frame.Column != nil && int(*frame.Column) >= minifiedColumn) {
+		return sourceMapsMinified
+	}
+	return sourceMapsSymbolicated
+}
+
+func handleUploadSourceMap(ctx context.Context, client *hbapi.Client, uploader *sourceMapUploader, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	projectID := req.GetInt("project_id", 0)
+	if projectID == 0 {
+		return mcp.NewToolResultError("project_id is required"), nil
+	}
+
+	revision := req.GetString("revision", "")
+	if revision == "" {
+		return mcp.NewToolResultError("revision is required"), nil
+	}
+
+	minifiedURL := req.GetString("minified_url", "")
+	if minifiedURL == "" {
+		return mcp.NewToolResultError("minified_url is required"), nil
+	}
+
+	sourceMapPath := req.GetString("source_map_path", "")
+	if sourceMapPath == "" {
+		return mcp.NewToolResultError("source_map_path is required"), nil
+	}
+	sourceMap, err := readUploadFile(sourceMapPath)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to read source map: %v", err)), nil
+	}
+	var minifiedFile []byte
+	minifiedPath := req.GetString("minified_file_path", "")
+	if minifiedPath != "" {
+		if minifiedFile, err = readUploadFile(minifiedPath); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to read minified file: %v", err)), nil
+		}
+	}
+
+	// Uploads authenticate with the project's API key, not the auth token.
+	project, err := client.Projects.Get(ctx, projectID)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to get project: %v", err)), nil
+	}
+	if project.Token == "" {
+		return mcp.NewToolResultError("Failed to upload source map: the API returned no API key for this project"), nil
+	}
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	_ = w.WriteField("api_key", project.Token)
+	_ = w.WriteField("revision", revision)
+	_ = w.WriteField("minified_url", minifiedURL)
+	if err := writeUploadFile(w, "source_map", sourceMapPath, sourceMap); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to upload source map: %v", err)), nil
+	}
+	if minifiedPath != "" {
+		if err := writeUploadFile(w, "minified_file", minifiedPath, minifiedFile); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to upload source map: %v", err)), nil
+		}
+	}
+	if err := w.Close(); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to upload source map: %v", err)), nil
+	}
+
+	if err := uploader.upload(ctx, w.FormDataContentType(), &body); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to upload source map: %v", err)), nil
+	}
+
+	upload := sourceMapUpload{
+		ProjectID:      projectID,
+		Revision:       revision,
+		MinifiedURL:    minifiedURL,
+		SourceMapBytes: len(sourceMap),
+		MinifiedBytes:  len(minifiedFile),
+	}
+
+	// Return JSON response
+	jsonBytes, err := json.Marshal(upload)
+	if err != nil {
+		return mcp.NewToolResultError("Failed to marshal response"), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonBytes)), nil
+}
+
+func (u *sourceMapUploader) upload(ctx context.Context, contentType string, body io.Reader) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.endpoint, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode >= 400 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// readUploadFile reads a file to upload, refusing relative paths and
+// files over maxSourceMapBytes.
+func readUploadFile(name string) ([]byte, error) {
+	if !filepath.IsAbs(name) {
+		return nil, fmt.Errorf("path must be absolute, got %q", name)
+	}
+	info, err := os.Stat(name)
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() > maxSourceMapBytes {
+		return nil, fmt.Errorf("%s is %d bytes; the limit is %d", name, info.Size(), maxSourceMapBytes)
+	}
+	return os.ReadFile(name)
+}
+
+func writeUploadFile(w *multipart.Writer, field, name string, data []byte) error {
+	part, err := w.CreateFormFile(field, filepath.Base(name))
+	if err != nil {
+		return err
+	}
+	_, err = part.Write(data)
+	return err
+}
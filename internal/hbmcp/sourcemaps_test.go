@@ -0,0 +1,219 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestSourceMapEndpoint(t *testing.T) {
+	tests := map[string]string{
+		"https://app.honeybadger.io":     "https://api.honeybadger.io/v1/source_maps",
+		"https://eu-app.honeybadger.io/": "https://eu-api.honeybadger.io/v1/source_maps",
+		"http://localhost:8080":          "http://localhost:8080/v1/source_maps",
+	}
+	for apiURL, want := range tests {
+		if got := sourceMapEndpoint(apiURL); got != want {
+			t.Errorf("sourceMapEndpoint(%q) = %q, want %q", apiURL, got, want)
+		}
+	}
+}
+
+func TestFrameKind(t *testing.T) {
+	column := func(n int) *hbapi.Number {
+		c := hbapi.Number(n)
+		return &c
+	}
+	tests := []struct {
+		frame hbapi.BacktraceEntry
+		want  string
+	}{
+		{hbapi.BacktraceEntry{File: "https://cdn.example.com/app.min.js?v=3", Column: column(12)}, sourceMapsMinified},
+		{hbapi.BacktraceEntry{File: "https://cdn.example.com/main.4f2a.js", Column: column(18233)}, sourceMapsMinified},
+		{hbapi.BacktraceEntry{File: "webpack:///src/components/Cart.js", Column: column(14)}, sourceMapsSymbolicated},
+		{hbapi.BacktraceEntry{File: "src/checkout.ts"}, sourceMapsSymbolicated},
+		{hbapi.BacktraceEntry{File: "https://example.com/assets/app.js", Column: column(9)}, sourceMapsSymbolicated},
+		{hbapi.BacktraceEntry{File: "[PROJECT_ROOT]/app/models/user.rb"}, ""},
+	}
+	for _, tt := range tests {
+		if got := frameKind(tt.frame); got != tt.want {
+			t.Errorf("frameKind(%s) = %q, want %q", tt.frame.File, got, tt.want)
+		}
+	}
+}
+
+func TestHandleCheckSourceMaps(t *testing.T) {
+	notice := `{"results": [{"id": "n1", "environment": {"revision": %s}, "backtrace": [
+		{"file": "https://cdn.example.com/app.min.js", "number": "1", "column": "5120", "method": "a"},
+		{"file": "https://cdn.example.com/app.min.js", "number": "1", "column": "880", "method": "b"},
+		{"file": "webpack:///src/cart.js", "number": "42", "column": "7", "method": "addItem"},
+		{"file": "https://cdn.example.com/vendor.min.js", "number": "2", "column": "31", "method": "c"}
+	]}], "links": {}}`
+	tests := []struct {
+		name     string
+		revision string
+		warnings int
+	}{
+		{"with revision", `"abc123"`, 0},
+		{"without revision", `null`, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/v2/projects/1/faults/2/notices" || r.URL.Query().Get("limit") != "1" {
+					t.Errorf("unexpected request %s", r.URL)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(strings.Replace(notice, "%s", tt.revision, 1)))
+			}))
+			defer server.Close()
+
+			client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+			req := mcp.CallToolRequest{}
+			req.Params.Arguments = map[string]any{"project_id": float64(1), "fault_id": float64(2)}
+
+			result, err := handleCheckSourceMaps(context.Background(), client, req)
+			if err != nil || result.IsError {
+				t.Fatalf("unexpected error: %v %s", err, getResultText(result))
+			}
+			var check sourceMapCheck
+			if err := json.Unmarshal([]byte(getResultText(result)), &check); err != nil {
+				t.Fatalf("failed to parse response: %v", err)
+			}
+			if check.Status != sourceMapsPartly || check.SymbolicatedFrames != 1 || check.MinifiedFrames != 3 || check.NoticeID != "n1" {
+				t.Errorf("unexpected check: %+v", check)
+			}
+			if len(check.MinifiedFiles) != 2 || check.MinifiedFiles[0] != (minifiedFile{URL: "https://cdn.example.com/app.min.js", Frames: 2}) {
+				t.Errorf("expected minified files in backtrace order, got %+v", check.MinifiedFiles)
+			}
+			if got := len(getResultNotes(t, result).Warnings); got != tt.warnings {
+				t.Errorf("expected %d warnings, got %d", tt.warnings, got)
+			}
+		})
+	}
+}
+
+func TestHandleUploadSourceMap(t *testing.T) {
+	dir := t.TempDir()
+	mapPath := filepath.Join(dir, "app.min.js.map")
+	if err := os.WriteFile(mapPath, []byte(`{"version":3}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	minPath := filepath.Join(dir, "app.min.js")
+	if err := os.WriteFile(minPath, []byte(`console.log(1)`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	uploaded := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/projects/1":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"id": 1, "name": "Web", "token": "project-key"}`))
+		case sourceMapUploadPath:
+			if err := r.ParseMultipartForm(1 << 20); err != nil {
+				t.Errorf("failed to parse upload: %v", err)
+			}
+			for k, v := range r.MultipartForm.Value {
+				uploaded[k] = v[0]
+			}
+			for k, files := range r.MultipartForm.File {
+				f, _ := files[0].Open()
+				b, _ := io.ReadAll(f)
+				uploaded[k] = files[0].Filename + ":" + string(b)
+			}
+			w.WriteHeader(http.StatusCreated)
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+		}
+	}))
+	defer server.Close()
+
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+	uploader := &sourceMapUploader{client: server.Client(), endpoint: server.URL + sourceMapUploadPath}
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{
+		"project_id":         float64(1),
+		"revision":           "abc123",
+		"minified_url":       "https://cdn.example.com/app.min.js",
+		"source_map_path":    mapPath,
+		"minified_file_path": minPath,
+	}
+
+	result, err := handleUploadSourceMap(context.Background(), client, uploader, req)
+	if err != nil || result.IsError {
+		t.Fatalf("unexpected error: %v %s", err, getResultText(result))
+	}
+	want := map[string]string{
+		"api_key":       "project-key",
+		"revision":      "abc123",
+		"minified_url":  "https://cdn.example.com/app.min.js",
+		"source_map":    `app.min.js.map:{"version":3}`,
+		"minified_file": "app.min.js:console.log(1)",
+	}
+	for k, v := range want {
+		if uploaded[k] != v {
+			t.Errorf("expected %s=%q, got %q", k, v, uploaded[k])
+		}
+	}
+	var upload sourceMapUpload
+	if err := json.Unmarshal([]byte(getResultText(result)), &upload); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if upload.SourceMapBytes != 13 || upload.MinifiedBytes != 14 {
+		t.Errorf("unexpected upload: %+v", upload)
+	}
+}
+
+func TestHandleUploadSourceMap_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == sourceMapUploadPath {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_, _ = w.Write([]byte(`{"error": "revision is invalid"}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": 1, "token": "project-key"}`))
+	}))
+	defer server.Close()
+
+	mapPath := filepath.Join(t.TempDir(), "app.js.map")
+	if err := os.WriteFile(mapPath, []byte(`{}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+	uploader := &sourceMapUploader{client: server.Client(), endpoint: server.URL + sourceMapUploadPath}
+	args := func(sourceMapPath string) map[string]any {
+		return map[string]any{"project_id": float64(1), "revision": "r", "minified_url": "https://x/app.js", "source_map_path": sourceMapPath}
+	}
+
+	tests := []struct {
+		name string
+		args map[string]any
+		want string
+	}{
+		{"missing revision", map[string]any{"project_id": float64(1), "minified_url": "u", "source_map_path": mapPath}, "revision is required"},
+		{"relative path", args("app.js.map"), "path must be absolute"},
+		{"missing file", args(filepath.Join(t.TempDir(), "nope.map")), "Failed to read source map"},
+		{"rejected", args(mapPath), "422 Unprocessable Entity: {\"error\": \"revision is invalid\"}"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := mcp.CallToolRequest{}
+			req.Params.Arguments = tt.args
+			result, _ := handleUploadSourceMap(context.Background(), client, uploader, req)
+			if !result.IsError || !strings.Contains(getResultText(result), tt.want) {
+				t.Errorf("expected error containing %q, got %q", tt.want, getResultText(result))
+			}
+		})
+	}
+}
@@ -0,0 +1,322 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// RegisterStatusPageTools registers all status page-related MCP tools
+func RegisterStatusPageTools(r *toolRegistrar, clientFor ClientFactory) {
+	// list_status_pages tool
+	r.AddTool(
+		mcp.NewTool("list_status_pages",
+			mcp.WithTitleAnnotation("List Status Pages"),
+			mcp.WithDescription("List an account's status pages with the uptime sites and check-ins each one shows and their current state. Omit account_id to list the status pages of every account the token can access."),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithString("account_id",
+				mcp.Description("The ID of the account whose status pages to list (see check_connection for account IDs)"),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleListStatusPages(ctx, clientFor(ctx), req)
+		},
+	)
+
+	// get_status_page tool
+	r.AddTool(
+		mcp.NewTool("get_status_page",
+			mcp.WithTitleAnnotation("Get Status Page"),
+			mcp.WithDescription("Get a status page, including the current state of each uptime site and check-in it shows"),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithOutputSchema[hbapi.StatusPage](),
+			mcp.WithString("account_id",
+				mcp.Required(),
+				mcp.Description("The ID of the account the status page belongs to"),
+			),
+			mcp.WithString("status_page_id",
+				mcp.Required(),
+				mcp.Description("The ID of the status page"),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleGetStatusPage(ctx, clientFor(ctx), req)
+		},
+	)
+
+	// create_status_page tool
+	r.AddTool(
+		mcp.NewTool("create_status_page",
+			mcp.WithTitleAnnotation("Create Status Page"),
+			mcp.WithDescription("Create a status page for an account showing the current state of some of its uptime sites and check-ins. Check-in IDs come from list_check_ins; uptime site IDs can be found on existing status pages."),
+			mcp.WithReadOnlyHintAnnotation(false),
+			mcp.WithDestructiveHintAnnotation(true),
+			mcp.WithIdempotentHintAnnotation(false),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithString("account_id",
+				mcp.Required(),
+				mcp.Description("The ID of the account to create the status page in (see check_connection for account IDs)"),
+			),
+			mcp.WithString("name",
+				mcp.Required(),
+				mcp.Description("The name of the status page"),
+			),
+			mcp.WithString("domain",
+				mcp.Description("Optional custom domain to serve the status page on, e.g. 'status.example.com'"),
+			),
+			mcp.WithString("sites",
+				mcp.Description(statusPageSitesDescription),
+			),
+			mcp.WithString("check_ins",
+				mcp.Description(statusPageCheckInsDescription),
+			),
+			mcp.WithBoolean("hide_branding",
+				mcp.Description("Hide the Honeybadger branding on the page, where the plan allows it"),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleCreateStatusPage(ctx, clientFor(ctx), req)
+		},
+	)
+
+	// update_status_page tool
+	r.AddTool(
+		mcp.NewTool("update_status_page",
+			mcp.WithTitleAnnotation("Update Status Page"),
+			mcp.WithDescription("Update a status page. Only the provided fields are changed; sites and check_ins, when given, replace the lists the page shows."),
+			mcp.WithReadOnlyHintAnnotation(false),
+			mcp.WithDestructiveHintAnnotation(true),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithString("account_id",
+				mcp.Required(),
+				mcp.Description("The ID of the account the status page belongs to"),
+			),
+			mcp.WithString("status_page_id",
+				mcp.Required(),
+				mcp.Description("The ID of the status page to update"),
+			),
+			mcp.WithString("name",
+				mcp.Description("The name of the status page"),
+			),
+			mcp.WithString("domain",
+				mcp.Description("Custom domain to serve the status page on, e.g. 'status.example.com'"),
+			),
+			mcp.WithString("sites",
+				mcp.Description(statusPageSitesDescription),
+			),
+			mcp.WithString("check_ins",
+				mcp.Description(statusPageCheckInsDescription),
+			),
+			mcp.WithBoolean("hide_branding",
+				mcp.Description("Hide the Honeybadger branding on the page, where the plan allows it"),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleUpdateStatusPage(ctx, clientFor(ctx), req)
+		},
+	)
+
+	// delete_status_page tool
+	r.AddTool(
+		mcp.NewTool("delete_status_page",
+			mcp.WithTitleAnnotation("Delete Status Page"),
+			mcp.WithDescription("Delete a status page. Its public URL stops working; the sites and check-ins it showed are kept."),
+			mcp.WithReadOnlyHintAnnotation(false),
+			mcp.WithDestructiveHintAnnotation(true),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithString("account_id",
+				mcp.Required(),
+				mcp.Description("The ID of the account the status page belongs to"),
+			),
+			mcp.WithString("status_page_id",
+				mcp.Required(),
+				mcp.Description("The ID of the status page to delete"),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleDeleteStatusPage(ctx, clientFor(ctx), req)
+		},
+	)
+}
+
+const (
+	statusPageSitesDescription    = `JSON array of the uptime sites the page shows, in order, e.g. [{"site_id": "abc", "display_name": "API"}]. Each takes site_id and optional display_name, description, and position.`
+	statusPageCheckInsDescription = `JSON array of the check-ins the page shows, in order, e.g. [{"check_in_id": "def", "display_name": "Nightly backup"}]. Each takes check_in_id and optional display_name, description, and position.`
+)
+
+func handleListStatusPages(ctx context.Context, client *hbapi.Client, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	accountIDs := []string{}
+	if accountID := req.GetString("account_id", ""); accountID != "" {
+		accountIDs = append(accountIDs, accountID)
+	} else {
+		accounts, err := client.Accounts.List(ctx)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to list accounts: %v", err)), nil
+		}
+		for _, a := range accounts {
+			accountIDs = append(accountIDs, a.ID)
+		}
+	}
+
+	var notes toolNotes
+	statusPages := []hbapi.StatusPage{}
+	for _, accountID := range accountIDs {
+		pages, err := client.StatusPages.List(ctx, accountID)
+		if err != nil {
+			if len(accountIDs) == 1 {
+				return mcp.NewToolResultError(fmt.Sprintf("Failed to list status pages: %v", err)), nil
+			}
+			// Accounts without status pages enabled refuse the request;
+			// that shouldn't hide the others' pages.
+			notes.warnf("skipped account %s: %v", accountID, err)
+			continue
+		}
+		statusPages = append(statusPages, pages...)
+	}
+
+	// Return JSON response
+	jsonBytes, err := json.Marshal(statusPages)
+	if err != nil {
+		return mcp.NewToolResultError("Failed to marshal response"), nil
+	}
+
+	return withNotes(mcp.NewToolResultText(string(jsonBytes)), &notes), nil
+}
+
+func handleGetStatusPage(ctx context.Context, client *hbapi.Client, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	accountID := req.GetString("account_id", "")
+	if accountID == "" {
+		return mcp.NewToolResultError("account_id is required"), nil
+	}
+
+	statusPageID := req.GetString("status_page_id", "")
+	if statusPageID == "" {
+		return mcp.NewToolResultError("status_page_id is required"), nil
+	}
+
+	statusPage, err := client.StatusPages.Get(ctx, accountID, statusPageID)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to get status page: %v", err)), nil
+	}
+
+	// Return JSON response
+	jsonBytes, err := json.Marshal(statusPage)
+	if err != nil {
+		return mcp.NewToolResultError("Failed to marshal response"), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonBytes)), nil
+}
+
+// statusPageParams reads the fields create_status_page and
+// update_status_page share.
+func statusPageParams(req mcp.CallToolRequest) (hbapi.StatusPageParams, *mcp.CallToolResult) {
+	params := hbapi.StatusPageParams{Name: req.GetString("name", "")}
+	if domain := req.GetString("domain", ""); domain != "" {
+		params.Domain = &domain
+	}
+	if sitesJSON := req.GetString("sites", ""); sitesJSON != "" {
+		if err := json.Unmarshal([]byte(sitesJSON), &params.Sites); err != nil {
+			return params, mcp.NewToolResultError(fmt.Sprintf("Failed to parse sites JSON: %v", err))
+		}
+		for i, site := range params.Sites {
+			if site.SiteID == "" {
+				return params, mcp.NewToolResultError(fmt.Sprintf("sites[%d] needs a site_id", i))
+			}
+		}
+	}
+	if checkInsJSON := req.GetString("check_ins", ""); checkInsJSON != "" {
+		if err := json.Unmarshal([]byte(checkInsJSON), &params.CheckIns); err != nil {
+			return params, mcp.NewToolResultError(fmt.Sprintf("Failed to parse check_ins JSON: %v", err))
+		}
+		for i, checkIn := range params.CheckIns {
+			if checkIn.CheckInID == "" {
+				return params, mcp.NewToolResultError(fmt.Sprintf("check_ins[%d] needs a check_in_id", i))
+			}
+		}
+	}
+	if hideBranding, ok := req.GetArguments()["hide_branding"].(bool); ok {
+		params.HideBranding = &hideBranding
+	}
+	return params, nil
+}
+
+func handleCreateStatusPage(ctx context.Context, client *hbapi.Client, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	accountID := req.GetString("account_id", "")
+	if accountID == "" {
+		return mcp.NewToolResultError("account_id is required"), nil
+	}
+
+	params, errResult := statusPageParams(req)
+	if errResult != nil {
+		return errResult, nil
+	}
+	if params.Name == "" {
+		return mcp.NewToolResultError("name is required"), nil
+	}
+
+	statusPage, err := client.StatusPages.Create(ctx, accountID, params)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to create status page: %v", err)), nil
+	}
+
+	// Return JSON response
+	jsonBytes, err := json.Marshal(statusPage)
+	if err != nil {
+		return mcp.NewToolResultError("Failed to marshal response"), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonBytes)), nil
+}
+
+func handleUpdateStatusPage(ctx context.Context, client *hbapi.Client, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	accountID := req.GetString("account_id", "")
+	if accountID == "" {
+		return mcp.NewToolResultError("account_id is required"), nil
+	}
+
+	statusPageID := req.GetString("status_page_id", "")
+	if statusPageID == "" {
+		return mcp.NewToolResultError("status_page_id is required"), nil
+	}
+
+	params, errResult := statusPageParams(req)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	if err := client.StatusPages.Update(ctx, accountID, statusPageID, params); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to update status page: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Status page %s successfully updated", statusPageID)), nil
+}
+
+func handleDeleteStatusPage(ctx context.Context, client *hbapi.Client, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	accountID := req.GetString("account_id", "")
+	if accountID == "" {
+		return mcp.NewToolResultError("account_id is required"), nil
+	}
+
+	statusPageID := req.GetString("status_page_id", "")
+	if statusPageID == "" {
+		return mcp.NewToolResultError("status_page_id is required"), nil
+	}
+
+	if err := client.StatusPages.Delete(ctx, accountID, statusPageID); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to delete status page: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Status page %s deleted successfully", statusPageID)), nil
+}
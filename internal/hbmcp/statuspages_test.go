@@ -0,0 +1,217 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestHandleListStatusPages(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v2/accounts":
+			_, _ = w.Write([]byte(`{"results": [{"id": "abc", "name": "Acme"}, {"id": "def", "name": "Side Project"}]}`))
+		case "/v2/accounts/abc/status_pages":
+			_, _ = w.Write([]byte(`{"results": [{"id": "sp1", "name": "Acme Status", "account_id": "abc", "sites": [{"site_id": "s1", "state": "up"}]}]}`))
+		case "/v2/accounts/def/status_pages":
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors": "Status pages are not enabled"}`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+
+	t.Run("all accounts", func(t *testing.T) {
+		req := mcp.CallToolRequest{}
+		result, err := handleListStatusPages(context.Background(), client, req)
+		if err != nil {
+			t.Fatalf("handleListStatusPages() error = %v", err)
+		}
+		if result.IsError {
+			t.Fatalf("expected successful result, got error: %s", getResultText(result))
+		}
+		var pages []hbapi.StatusPage
+		if err := json.Unmarshal([]byte(getResultText(result)), &pages); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		if len(pages) != 1 || pages[0].ID != "sp1" || pages[0].Sites[0].State != "up" {
+			t.Errorf("unexpected status pages: %+v", pages)
+		}
+		notes := getResultNotes(t, result)
+		if len(notes.Warnings) != 1 || !strings.Contains(notes.Warnings[0], "skipped account def") {
+			t.Errorf("expected a warning about account def, got %v", notes.Warnings)
+		}
+	})
+
+	t.Run("one account", func(t *testing.T) {
+		req := mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{"account_id": "def"}}}
+		result, err := handleListStatusPages(context.Background(), client, req)
+		if err != nil {
+			t.Fatalf("handleListStatusPages() error = %v", err)
+		}
+		if !result.IsError || !strings.Contains(getResultText(result), "not enabled") {
+			t.Errorf("expected the API error, got %s", getResultText(result))
+		}
+	})
+}
+
+func TestHandleGetStatusPage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/accounts/abc/status_pages/sp1" {
+			t.Errorf("expected path /v2/accounts/abc/status_pages/sp1, got %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id": "sp1", "name": "Acme Status", "check_ins": [{"check_in_id": "c1", "state": "down"}]}`))
+	}))
+	defer server.Close()
+
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+	req := mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"account_id":     "abc",
+		"status_page_id": "sp1",
+	}}}
+
+	result, err := handleGetStatusPage(context.Background(), client, req)
+	if err != nil {
+		t.Fatalf("handleGetStatusPage() error = %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected successful result, got error: %s", getResultText(result))
+	}
+	if !strings.Contains(getResultText(result), `"state":"down"`) {
+		t.Errorf("expected check-in state in result, got %s", getResultText(result))
+	}
+}
+
+func TestHandleCreateStatusPage(t *testing.T) {
+	var body map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v2/accounts/abc/status_pages" {
+			t.Errorf("expected POST /v2/accounts/abc/status_pages, got %s %s", r.Method, r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("failed to decode request body: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id": "sp2", "name": "Acme Status", "account_id": "abc"}`))
+	}))
+	defer server.Close()
+
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+	req := mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"account_id":    "abc",
+		"name":          "Acme Status",
+		"sites":         `[{"site_id": "s1", "display_name": "API"}]`,
+		"check_ins":     `[{"check_in_id": "c1"}]`,
+		"hide_branding": true,
+	}}}
+
+	result, err := handleCreateStatusPage(context.Background(), client, req)
+	if err != nil {
+		t.Fatalf("handleCreateStatusPage() error = %v", err)
+	}
+	if result.IsError || !strings.Contains(getResultText(result), `"id":"sp2"`) {
+		t.Fatalf("expected the created status page, got %s", getResultText(result))
+	}
+	got, _ := json.Marshal(body)
+	want := `{"status_page":{"check_ins":[{"check_in_id":"c1"}],"hide_branding":true,"name":"Acme Status","sites":[{"display_name":"API","site_id":"s1"}]}}`
+	if string(got) != want {
+		t.Errorf("request body = %s, want %s", got, want)
+	}
+}
+
+func TestHandleCreateStatusPage_InvalidArgs(t *testing.T) {
+	client := hbapi.NewClient().WithBaseURL("http://127.0.0.1:0").WithAuthToken("test-token")
+	tests := []struct {
+		name string
+		args map[string]interface{}
+		want string
+	}{
+		{"missing account_id", map[string]interface{}{"name": "Status"}, "account_id is required"},
+		{"missing name", map[string]interface{}{"account_id": "abc"}, "name is required"},
+		{"bad sites", map[string]interface{}{"account_id": "abc", "name": "Status", "sites": `{"site_id": "s1"}`}, "Failed to parse sites JSON"},
+		{"site without ID", map[string]interface{}{"account_id": "abc", "name": "Status", "sites": `[{"display_name": "API"}]`}, "sites[0] needs a site_id"},
+		{"check-in without ID", map[string]interface{}{"account_id": "abc", "name": "Status", "check_ins": `[{}]`}, "check_ins[0] needs a check_in_id"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, _ := handleCreateStatusPage(context.Background(), client, mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: tt.args}})
+			if !result.IsError || !strings.Contains(getResultText(result), tt.want) {
+				t.Errorf("expected error containing %q, got %q", tt.want, getResultText(result))
+			}
+		})
+	}
+}
+
+func TestHandleUpdateStatusPage(t *testing.T) {
+	var body map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/v2/accounts/abc/status_pages/sp1" {
+			t.Errorf("expected PUT /v2/accounts/abc/status_pages/sp1, got %s %s", r.Method, r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("failed to decode request body: %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+	req := mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"account_id":     "abc",
+		"status_page_id": "sp1",
+		"domain":         "status.example.com",
+	}}}
+
+	result, err := handleUpdateStatusPage(context.Background(), client, req)
+	if err != nil {
+		t.Fatalf("handleUpdateStatusPage() error = %v", err)
+	}
+	if result.IsError || getResultText(result) != "Status page sp1 successfully updated" {
+		t.Fatalf("unexpected result %s", getResultText(result))
+	}
+	// Only the fields passed are sent.
+	if got, _ := json.Marshal(body); string(got) != `{"status_page":{"domain":"status.example.com"}}` {
+		t.Errorf("unexpected request body %s", got)
+	}
+}
+
+func TestHandleDeleteStatusPage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete || r.URL.Path != "/v2/accounts/abc/status_pages/sp1" {
+			t.Errorf("expected DELETE /v2/accounts/abc/status_pages/sp1, got %s %s", r.Method, r.URL.Path)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+	req := mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"account_id":     "abc",
+		"status_page_id": "sp1",
+	}}}
+
+	result, err := handleDeleteStatusPage(context.Background(), client, req)
+	if err != nil {
+		t.Fatalf("handleDeleteStatusPage() error = %v", err)
+	}
+	if result.IsError || getResultText(result) != "Status page sp1 deleted successfully" {
+		t.Errorf("unexpected result %s", getResultText(result))
+	}
+
+	result, _ = handleDeleteStatusPage(context.Background(), client, mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{"account_id": "abc"}}})
+	if !result.IsError || !strings.Contains(getResultText(result), "status_page_id is required") {
+		t.Errorf("expected a missing status_page_id error, got %s", getResultText(result))
+	}
+}
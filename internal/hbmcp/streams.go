@@ -18,6 +18,8 @@ func RegisterStreamTools(r *toolRegistrar, clientFor ClientFactory) {
 			mcp.WithDescription("List Insights data streams for a Honeybadger project. Streams partition Insights event data (e.g. default vs internal)."),
 			mcp.WithReadOnlyHintAnnotation(true),
 			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
 			mcp.WithNumber("project_id",
 				mcp.Required(),
 				mcp.Description("The ID of the project to list streams for"),
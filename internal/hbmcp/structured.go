@@ -0,0 +1,78 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// hasOutputSchema reports whether the tool's definition declares an output
+// schema, with mcp.WithOutputSchema.
+func hasOutputSchema(tool mcp.Tool) bool {
+	return tool.OutputSchema.Type != ""
+}
+
+// loosenOutputSchema drops the required lists and closed objects from a
+// schema generated from a Go type. Results grow fields the type doesn't
+// know about (resource_uri on trimmed notices, enrich's extras) and lose
+// ones to the fields argument, and a schema that forbids either would fail
+// clients that validate the result.
+func loosenOutputSchema(tool *mcp.Tool) {
+	if !hasOutputSchema(*tool) {
+		return
+	}
+	tool.OutputSchema.Required = nil
+	tool.OutputSchema.AdditionalProperties = nil
+	for _, v := range tool.OutputSchema.Properties {
+		loosenSchema(v)
+	}
+	for _, v := range tool.OutputSchema.Defs {
+		loosenSchema(v)
+	}
+}
+
+func loosenSchema(v any) {
+	switch v := v.(type) {
+	case map[string]any:
+		delete(v, "required")
+		if v["additionalProperties"] == false {
+			delete(v, "additionalProperties")
+		}
+		for _, child := range v {
+			loosenSchema(child)
+		}
+	case []any:
+		for _, child := range v {
+			loosenSchema(child)
+		}
+	}
+}
+
+// structuredResults returns a successful result's JSON object as its
+// structured content too, for tools that declare an output schema, so
+// clients that support typed tool output can validate and render it. The
+// text stays for clients that don't. A result that isn't a JSON object,
+// such as an ascii_chart rendering, is left as text alone.
+func structuredResults(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result, err := next(ctx, req)
+		if err != nil || result == nil || result.IsError || result.StructuredContent != nil || len(result.Content) == 0 {
+			return result, err
+		}
+		text, ok := result.Content[0].(mcp.TextContent)
+		if !ok {
+			return result, nil
+		}
+		dec := json.NewDecoder(strings.NewReader(text.Text))
+		dec.UseNumber()
+		var v map[string]any
+		if err := dec.Decode(&v); err != nil || dec.More() {
+			return result, nil
+		}
+		result.StructuredContent = v
+		return result, nil
+	}
+}
@@ -0,0 +1,104 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/honeybadger-io/honeybadger-mcp-server/internal/config"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestOutputSchemas(t *testing.T) {
+	cfg := &config.Config{
+		AuthToken:     "test-token",
+		APIURL:        "https://api.honeybadger.io/v2",
+		LogLevel:      "info",
+		TransportMode: config.TransportStdio,
+	}
+	s := NewServer(cfg, "test")
+	resp := s.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`))
+	respBytes, err := json.Marshal(resp)
+	if err != nil {
+		t.Fatalf("failed to marshal tools/list response: %v", err)
+	}
+	var parsed struct {
+		Result struct {
+			Tools []map[string]json.RawMessage `json:"tools"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(respBytes, &parsed); err != nil {
+		t.Fatalf("failed to unmarshal tools/list response: %v", err)
+	}
+
+	schemas := map[string]string{}
+	for _, tool := range parsed.Result.Tools {
+		var name string
+		_ = json.Unmarshal(tool["name"], &name)
+		if schema, ok := tool["outputSchema"]; ok {
+			schemas[name] = string(schema)
+		}
+	}
+	for _, name := range []string{"list_faults", "get_fault", "get_fault_counts", "list_projects", "get_alarm"} {
+		schema, ok := schemas[name]
+		if !ok {
+			t.Errorf("expected %s to declare an output schema", name)
+			continue
+		}
+		if !strings.Contains(schema, `"type":"object"`) || strings.Contains(schema, `"required":["`) || strings.Contains(schema, `"additionalProperties":false`) {
+			t.Errorf("expected a loose object schema for %s, got %s", name, schema)
+		}
+	}
+	if !strings.Contains(schemas["get_fault_counts"], `"total"`) {
+		t.Errorf("expected get_fault_counts' schema to describe its fields, got %s", schemas["get_fault_counts"])
+	}
+}
+
+func TestStructuredResults(t *testing.T) {
+	s := server.NewMCPServer("test", "1.0.0")
+	r := newToolRegistrar(s)
+	body := `{"total": 3, "environments": [], "assignees": []}`
+	r.AddTool(mcp.NewTool("get_fault_counts", mcp.WithOutputSchema[hbapi.FaultCounts]()), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText(body), nil
+	})
+	r.AddTool(mcp.NewTool("plain"), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText(body), nil
+	})
+
+	call := func(name string, args map[string]any) *mcp.CallToolResult {
+		t.Helper()
+		handler := s.GetTool(name).Handler
+		req := mcp.CallToolRequest{}
+		req.Params.Name = name
+		req.Params.Arguments = args
+		result, err := handler(context.Background(), req)
+		if err != nil || result.IsError {
+			t.Fatalf("unexpected error: %v %s", err, getResultText(result))
+		}
+		return result
+	}
+
+	result := call("get_fault_counts", nil)
+	structured, ok := result.StructuredContent.(map[string]any)
+	if !ok || structured["total"] != json.Number("3") || getResultText(result) != body {
+		t.Errorf("expected the JSON as structured content and text, got %#v %s", result.StructuredContent, getResultText(result))
+	}
+
+	// The structured content follows the fields selection.
+	result = call("get_fault_counts", map[string]any{fieldsArg: "total"})
+	if structured, _ := result.StructuredContent.(map[string]any); len(structured) != 1 || structured["total"] != json.Number("3") {
+		t.Errorf("expected structured content shaped by fields, got %#v", result.StructuredContent)
+	}
+
+	if result := call("plain", nil); result.StructuredContent != nil {
+		t.Errorf("expected no structured content without an output schema, got %#v", result.StructuredContent)
+	}
+
+	body = "Project 1 occurrences: ..."
+	if result := call("get_fault_counts", nil); result.StructuredContent != nil {
+		t.Errorf("expected no structured content for a text result, got %#v", result.StructuredContent)
+	}
+}
@@ -0,0 +1,251 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// bytesPerToken is the rough ratio tokens are estimated by. JSON runs
+	// close to it for most tokenizers.
+	bytesPerToken = 4
+
+	// summaryTopItems is how many leading items of each list a summary
+	// keeps.
+	summaryTopItems = 5
+
+	// summaryItemKeys bounds the item keys listed for each list.
+	summaryItemKeys = 30
+
+	// summaryPreviewBytes is how much of a result that isn't JSON a
+	// summary keeps.
+	summaryPreviewBytes = 2000
+)
+
+// unsummarizedTools return text that is only useful whole.
+var unsummarizedTools = map[string]bool{
+	"get_reference": true,
+}
+
+// narrowingArgs are arguments that shrink a result, in the order they're
+// suggested when a tool takes them.
+var narrowingArgs = []string{
+	"limit", "page_token", "q", "created_after", "created_before", "occurred_after", "occurred_before",
+	"ts", "environment", "max_notices", "top", "app_trace_only", "max_frames", "exclude_fields", "max_notice_bytes",
+}
+
+// resultSummary stands in for a result over the token limit.
+type resultSummary struct {
+	EstimatedTokens int    `json:"estimated_tokens"`
+	MaxTokens       int    `json:"max_result_tokens"`
+	Summary         any    `json:"summary"`
+	Next            string `json:"next"`
+}
+
+// estimateTokens estimates the tokens a result's text costs.
+func estimateTokens(result *mcp.CallToolResult) int {
+	n := 0
+	for _, c := range result.Content {
+		if text, ok := c.(mcp.TextContent); ok {
+			n += len(text.Text)
+		}
+	}
+	return (n + bytesPerToken - 1) / bytesPerToken
+}
+
+// summarizeResults replaces a successful result estimated at more than
+// maxTokens tokens with a summary of it: the counts and first few items of
+// its lists, and its other values shortened, with instructions for
+// retrieving the data with a narrower call. Notes and warnings after the
+// result are kept. An agent's context fills up on one oversized result
+// otherwise, usually before it has read any of it.
+func summarizeResults(maxTokens int) func(tool mcp.Tool, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(tool mcp.Tool, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		if unsummarizedTools[tool.Name] {
+			return next
+		}
+		return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			result, err := next(ctx, req)
+			if err != nil || result == nil || result.IsError || len(result.Content) == 0 {
+				return result, err
+			}
+			tokens := estimateTokens(result)
+			if tokens <= maxTokens {
+				return result, nil
+			}
+			text, ok := result.Content[0].(mcp.TextContent)
+			if !ok {
+				return result, nil
+			}
+
+			summary := resultSummary{EstimatedTokens: tokens, MaxTokens: maxTokens}
+			dec := json.NewDecoder(strings.NewReader(text.Text))
+			dec.UseNumber()
+			var v any
+			example := ""
+			if err := dec.Decode(&v); err != nil || dec.More() {
+				summary.Summary = map[string]any{
+					"preview": truncateLabel(text.Text, summaryPreviewBytes),
+					"lines":   strings.Count(text.Text, "\n") + 1,
+				}
+			} else {
+				summary.Summary = summarizeValue(v, 0)
+				example = fieldsExample(v)
+			}
+			summary.Next = summaryInstructions(tool, tokens, maxTokens, example)
+
+			jsonBytes, err := json.Marshal(map[string]any{"result_summary": summary})
+			if err != nil {
+				return result, nil
+			}
+			summarized := *result
+			summarized.Content = append([]mcp.Content{mcp.NewTextContent(string(jsonBytes))}, result.Content[1:]...)
+			summarized.StructuredContent = nil
+			return &summarized, nil
+		}
+	}
+}
+
+// summarizeValue shortens v: lists become their count, item keys, and
+// first few items, and objects below the second level their key count.
+func summarizeValue(v any, depth int) any {
+	switch v := v.(type) {
+	case map[string]any:
+		if depth >= 2 {
+			return fmt.Sprintf("object with %d keys", len(v))
+		}
+		out := make(map[string]any, len(v))
+		for k, child := range v {
+			out[k] = summarizeValue(child, depth+1)
+		}
+		return out
+	case []any:
+		list := map[string]any{"count": len(v)}
+		if keys := itemKeys(v); len(keys) > 0 {
+			list["item_keys"] = keys
+		}
+		top := make([]any, 0, min(len(v), summaryTopItems))
+		for _, item := range v[:min(len(v), summaryTopItems)] {
+			top = append(top, summarizeItem(item))
+		}
+		list["top"] = top
+		return list
+	case string:
+		return truncateLabel(v, maxPatternValue)
+	}
+	return v
+}
+
+// summarizeItem keeps the scalar fields of a list item and notes the size
+// of the rest.
+func summarizeItem(item any) any {
+	switch item := item.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(item))
+		for k, v := range item {
+			switch v := v.(type) {
+			case map[string]any:
+				out[k] = fmt.Sprintf("object with %d keys", len(v))
+			case []any:
+				out[k] = fmt.Sprintf("list of %d", len(v))
+			case string:
+				out[k] = truncateLabel(v, maxPatternValue)
+			default:
+				out[k] = v
+			}
+		}
+		return out
+	case []any:
+		return fmt.Sprintf("list of %d", len(item))
+	case string:
+		return truncateLabel(item, maxPatternValue)
+	}
+	return item
+}
+
+// itemKeys returns the keys found in a list's object items, sorted.
+func itemKeys(list []any) []string {
+	seen := map[string]bool{}
+	for _, item := range list {
+		if m, ok := item.(map[string]any); ok {
+			for k := range m {
+				seen[k] = true
+			}
+		}
+	}
+	keys := make([]string, 0, len(seen))
+	for k := range seen {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys[:min(len(keys), summaryItemKeys)]
+}
+
+// fieldsExample suggests a fields selection for v: a few keys of the
+// items of its largest list, or of v itself.
+func fieldsExample(v any) string {
+	pick := func(keys []string) string {
+		if i := slices.Index(keys, "id"); i > 0 {
+			keys = append([]string{"id"}, slices.Delete(slices.Clone(keys), i, i+1)...)
+		}
+		keys = keys[:min(len(keys), 4)]
+		if len(keys) == 1 {
+			return keys[0]
+		}
+		return "{" + strings.Join(keys, ",") + "}"
+	}
+	switch v := v.(type) {
+	case []any:
+		if keys := itemKeys(v); len(keys) > 0 {
+			return strings.Trim(pick(keys), "{}")
+		}
+	case map[string]any:
+		largest, size := "", 0
+		for k, child := range v {
+			if list, ok := child.([]any); ok && len(itemKeys(list)) > 0 && len(list) > size {
+				largest, size = k, len(list)
+			}
+		}
+		if largest != "" {
+			return largest + "[*]." + pick(itemKeys(v[largest].([]any)))
+		}
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		if len(keys) > 0 {
+			return strings.Trim(pick(keys), "{}")
+		}
+	}
+	return ""
+}
+
+// summaryInstructions tells the agent how to get the data a summary
+// stands in for.
+func summaryInstructions(tool mcp.Tool, tokens, maxTokens int, example string) string {
+	var ways []string
+	if _, ok := tool.InputSchema.Properties[fieldsArg]; ok && example != "" {
+		ways = append(ways, fmt.Sprintf("pass fields to select only what you need, e.g. fields %q", example))
+	}
+	var args []string
+	for _, name := range narrowingArgs {
+		if _, ok := tool.InputSchema.Properties[name]; ok {
+			args = append(args, name)
+		}
+	}
+	if len(args) > 0 {
+		ways = append(ways, "narrow the call with "+strings.Join(args, ", "))
+	}
+	next := fmt.Sprintf("The full result is about %d tokens, over this server's limit of %d, so only a summary is returned: the count, keys, and first %d items of each list, with long values shortened.", tokens, maxTokens, summaryTopItems)
+	if len(ways) == 0 {
+		return next
+	}
+	return next + fmt.Sprintf(" To get the data, call %s again and %s.", tool.Name, strings.Join(ways, ", or "))
+}
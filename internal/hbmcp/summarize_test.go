@@ -0,0 +1,139 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func noticesBody(n int) string {
+	notices := make([]string, n)
+	for i := range notices {
+		notices[i] = fmt.Sprintf(`{"id": "n%d", "message": "%s", "backtrace": [{"file": "app.rb"}, {"file": "lib.rb"}], "request": {"url": "/"}}`, i, strings.Repeat("x", 300))
+	}
+	return `{"results": [` + strings.Join(notices, ",") + `], "links": {"next": "/notices?page=2"}}`
+}
+
+func summarizedTool() mcp.Tool {
+	return mcp.NewTool("list_fault_notices",
+		mcp.WithNumber("limit"),
+		mcp.WithString("created_after"),
+		mcp.WithString(fieldsArg),
+	)
+}
+
+func TestSummarizeResults(t *testing.T) {
+	body := noticesBody(40)
+	handler := summarizeResults(1000)(summarizedTool(), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result := mcp.NewToolResultText(body)
+		addWarning(result, "the API was slow")
+		return result, nil
+	})
+	result, err := handler(context.Background(), mcp.CallToolRequest{})
+	if err != nil || result.IsError {
+		t.Fatalf("unexpected error: %v %s", err, getResultText(result))
+	}
+
+	var got struct {
+		Summary resultSummary `json:"result_summary"`
+	}
+	if err := json.Unmarshal([]byte(getResultText(result)), &got); err != nil {
+		t.Fatalf("expected a JSON summary, got %s", getResultText(result))
+	}
+	if got.Summary.EstimatedTokens < len(body)/bytesPerToken || got.Summary.MaxTokens != 1000 {
+		t.Errorf("unexpected estimate %d", got.Summary.EstimatedTokens)
+	}
+	results := got.Summary.Summary.(map[string]any)["results"].(map[string]any)
+	if results["count"] != float64(40) || len(results["top"].([]any)) != summaryTopItems {
+		t.Errorf("expected the count and first %d notices, got %v", summaryTopItems, results)
+	}
+	first := results["top"].([]any)[0].(map[string]any)
+	if first["backtrace"] != "list of 2" || first["request"] != "object with 1 keys" || len([]rune(first["message"].(string))) != maxPatternValue {
+		t.Errorf("expected nested values reduced and strings shortened, got %v", first)
+	}
+	for _, want := range []string{`fields "results[*].{id,backtrace,message,request}"`, "narrow the call with limit, created_after"} {
+		if !strings.Contains(got.Summary.Next, want) {
+			t.Errorf("expected %q in %s", want, got.Summary.Next)
+		}
+	}
+	if len(result.Content) != 2 || !strings.Contains(result.Content[1].(mcp.TextContent).Text, "the API was slow") {
+		t.Errorf("expected the warning to be kept, got %v", result.Content)
+	}
+}
+
+func TestSummarizeResults_PassesThrough(t *testing.T) {
+	tests := []struct {
+		name   string
+		tool   mcp.Tool
+		result *mcp.CallToolResult
+	}{
+		{"under the limit", summarizedTool(), mcp.NewToolResultText(noticesBody(1))},
+		{"error", summarizedTool(), mcp.NewToolResultError(noticesBody(40))},
+		{"reference", mcp.NewTool("get_reference"), mcp.NewToolResultText(strings.Repeat("# Docs\n", 2000))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := summarizeResults(1000)(tt.tool, func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				return tt.result, nil
+			})
+			result, _ := handler(context.Background(), mcp.CallToolRequest{})
+			if result != tt.result {
+				t.Errorf("expected the result unchanged, got %s", getResultText(result))
+			}
+		})
+	}
+}
+
+func TestSummarizeResults_Text(t *testing.T) {
+	text := strings.Repeat("digraph line\n", 1000)
+	handler := summarizeResults(100)(mcp.NewTool("get_fault_graph"), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText(text), nil
+	})
+	result, _ := handler(context.Background(), mcp.CallToolRequest{})
+	var got struct {
+		Summary resultSummary `json:"result_summary"`
+	}
+	if err := json.Unmarshal([]byte(getResultText(result)), &got); err != nil {
+		t.Fatalf("expected a JSON summary, got %s", getResultText(result))
+	}
+	summary := got.Summary.Summary.(map[string]any)
+	if summary["lines"] != float64(1001) || len([]rune(summary["preview"].(string))) != summaryPreviewBytes {
+		t.Errorf("expected a shortened preview and line count, got %v", summary)
+	}
+	if strings.Contains(got.Summary.Next, "To get the data") {
+		t.Errorf("expected no narrowing advice for a tool without narrowing arguments, got %s", got.Summary.Next)
+	}
+}
+
+func TestToolRegistrar_Summarize(t *testing.T) {
+	r := newToolRegistrar(server.NewMCPServer("test", "1.0.0"))
+	r.maxResultTokens = 1000
+	var names []string
+	for _, l := range r.layers() {
+		names = append(names, l.name)
+	}
+	if got := strings.Join(names, ","); !strings.HasPrefix(got, "recover,structured,summarize,fields,") {
+		t.Errorf("expected summarize between structured and fields, got %s", got)
+	}
+
+	// A fields selection narrows the result before the limit is checked.
+	r.AddTool(summarizedTool(), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText(noticesBody(40)), nil
+	})
+	handler := r.server.GetTool("list_fault_notices").Handler
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{fieldsArg: "results[*].id"}
+	result, err := handler(context.Background(), req)
+	if err != nil || strings.Contains(getResultText(result), "result_summary") {
+		t.Errorf("expected the narrowed result in full, got %s", getResultText(result))
+	}
+	req.Params.Arguments = map[string]any{}
+	if result, _ := handler(context.Background(), req); !strings.Contains(getResultText(result), "result_summary") {
+		t.Errorf("expected a summary, got %s", getResultText(result))
+	}
+}
@@ -0,0 +1,169 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/mail"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// RegisterTeamTools registers all team-related MCP tools
+func RegisterTeamTools(r *toolRegistrar, clientFor ClientFactory) {
+	// list_team_invitations tool
+	r.AddTool(
+		mcp.NewTool("list_team_invitations",
+			mcp.WithTitleAnnotation("List Team Invitations"),
+			mcp.WithDescription("List a team's invitations, pending and accepted (accepted_at is set once the invitee joins). Find team IDs in the teams field of get_project."),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithNumber("team_id",
+				mcp.Required(),
+				mcp.Description("The ID of the team to list invitations for"),
+				mcp.Min(1),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleListTeamInvitations(ctx, clientFor(ctx), req)
+		},
+	)
+
+	// create_team_invitation tool
+	r.AddTool(
+		mcp.NewTool("create_team_invitation",
+			mcp.WithTitleAnnotation("Create Team Invitation"),
+			mcp.WithDescription("Invite someone to a team by email. Honeybadger emails them a link to join, creating an account if they don't have one. Find team IDs in the teams field of get_project."),
+			mcp.WithReadOnlyHintAnnotation(false),
+			mcp.WithDestructiveHintAnnotation(true),
+			mcp.WithIdempotentHintAnnotation(false),
+			mcp.WithOpenWorldHintAnnotation(true),
+			mcp.WithNumber("team_id",
+				mcp.Required(),
+				mcp.Description("The ID of the team to invite to"),
+				mcp.Min(1),
+			),
+			mcp.WithString("email",
+				mcp.Required(),
+				mcp.Description("Email address to send the invitation to"),
+			),
+			mcp.WithBoolean("admin",
+				mcp.Description("Make the invitee a team admin (default false)"),
+			),
+			mcp.WithString("message",
+				mcp.Description("Optional personal message included in the invitation email"),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleCreateTeamInvitation(ctx, clientFor(ctx), req)
+		},
+	)
+
+	// delete_team_invitation tool
+	r.AddTool(
+		mcp.NewTool("delete_team_invitation",
+			mcp.WithTitleAnnotation("Delete Team Invitation"),
+			mcp.WithDescription("Delete a team invitation so its link can no longer be used to join. Doesn't remove someone who has already accepted."),
+			mcp.WithReadOnlyHintAnnotation(false),
+			mcp.WithDestructiveHintAnnotation(true),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithNumber("team_id",
+				mcp.Required(),
+				mcp.Description("The ID of the team the invitation belongs to"),
+				mcp.Min(1),
+			),
+			mcp.WithNumber("invitation_id",
+				mcp.Required(),
+				mcp.Description("The ID of the invitation to delete"),
+				mcp.Min(1),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return handleDeleteTeamInvitation(ctx, clientFor(ctx), req)
+		},
+	)
+}
+
+func handleListTeamInvitations(ctx context.Context, client *hbapi.Client, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	teamID := req.GetInt("team_id", 0)
+	if teamID == 0 {
+		return mcp.NewToolResultError("team_id is required"), nil
+	}
+
+	invitations, err := client.Teams.ListInvitations(ctx, teamID)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to list team invitations: %v", err)), nil
+	}
+
+	// Return JSON response
+	jsonBytes, err := json.Marshal(invitations)
+	if err != nil {
+		return mcp.NewToolResultError("Failed to marshal response"), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonBytes)), nil
+}
+
+func handleCreateTeamInvitation(ctx context.Context, client *hbapi.Client, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	teamID := req.GetInt("team_id", 0)
+	if teamID == 0 {
+		return mcp.NewToolResultError("team_id is required"), nil
+	}
+
+	email := req.GetString("email", "")
+	if email == "" {
+		return mcp.NewToolResultError("email is required"), nil
+	}
+	if addr, err := mail.ParseAddress(email); err != nil || addr.Address != email {
+		return mcp.NewToolResultError(fmt.Sprintf("invalid email %q", email)), nil
+	}
+
+	params := hbapi.TeamInvitationParams{Email: email}
+	if raw, ok := req.GetArguments()["admin"]; ok {
+		admin, ok := raw.(bool)
+		if !ok {
+			return mcp.NewToolResultError("admin must be a boolean"), nil
+		}
+		params.Admin = &admin
+	}
+	if message := req.GetString("message", ""); message != "" {
+		params.Message = &message
+	}
+
+	invitation, err := client.Teams.CreateInvitation(ctx, teamID, params)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to create team invitation: %v", err)), nil
+	}
+
+	// Return JSON response
+	jsonBytes, err := json.Marshal(invitation)
+	if err != nil {
+		return mcp.NewToolResultError("Failed to marshal response"), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonBytes)), nil
+}
+
+func handleDeleteTeamInvitation(ctx context.Context, client *hbapi.Client, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	args := req.GetArguments()
+
+	teamID, ok := requireID(args, "team_id")
+	if !ok {
+		return mcp.NewToolResultError("team_id must be a positive integer"), nil
+	}
+
+	invitationID, ok := requireID(args, "invitation_id")
+	if !ok {
+		return mcp.NewToolResultError("invitation_id must be a positive integer"), nil
+	}
+
+	if err := client.Teams.DeleteInvitation(ctx, teamID, invitationID); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to delete team invitation: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(fmt.Sprintf("Team invitation %d deleted successfully", invitationID)), nil
+}
@@ -0,0 +1,158 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestHandleListTeamInvitations(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Errorf("expected GET method, got %s", r.Method)
+		}
+		if r.URL.Path != "/v2/teams/7/team_invitations" {
+			t.Errorf("expected path /v2/teams/7/team_invitations, got %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"results": [{"id": 1, "email": "new@example.com", "admin": false, "created_at": "2024-01-01T00:00:00Z", "accepted_at": null}]}`))
+	}))
+	defer server.Close()
+
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Arguments: map[string]interface{}{"team_id": 7},
+		},
+	}
+
+	result, err := handleListTeamInvitations(context.Background(), client, req)
+	if err != nil {
+		t.Fatalf("handleListTeamInvitations() error = %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected successful result, got error: %s", getResultText(result))
+	}
+
+	var invitations []hbapi.TeamInvitation
+	if err := json.Unmarshal([]byte(getResultText(result)), &invitations); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if len(invitations) != 1 || invitations[0].Email != "new@example.com" {
+		t.Errorf("unexpected invitations: %+v", invitations)
+	}
+}
+
+func TestHandleCreateTeamInvitation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" {
+			t.Errorf("expected POST method, got %s", r.Method)
+		}
+		if r.URL.Path != "/v2/teams/7/team_invitations" {
+			t.Errorf("expected path /v2/teams/7/team_invitations, got %s", r.URL.Path)
+		}
+		var body map[string]map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("failed to decode request body: %v", err)
+		}
+		inv := body["team_invitation"]
+		if inv["email"] != "new@example.com" || inv["admin"] != true || inv["message"] != "Welcome aboard" {
+			t.Errorf("unexpected invitation params: %v", inv)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id": 2, "email": "new@example.com", "admin": true, "created_at": "2024-01-01T00:00:00Z"}`))
+	}))
+	defer server.Close()
+
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Arguments: map[string]interface{}{
+				"team_id": 7,
+				"email":   "new@example.com",
+				"admin":   true,
+				"message": "Welcome aboard",
+			},
+		},
+	}
+
+	result, err := handleCreateTeamInvitation(context.Background(), client, req)
+	if err != nil {
+		t.Fatalf("handleCreateTeamInvitation() error = %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected successful result, got error: %s", getResultText(result))
+	}
+	if !strings.Contains(getResultText(result), `"id":2`) {
+		t.Errorf("expected the created invitation, got %s", getResultText(result))
+	}
+}
+
+func TestHandleCreateTeamInvitation_InvalidArguments(t *testing.T) {
+	cases := []struct {
+		name string
+		args map[string]interface{}
+		want string
+	}{
+		{"missing email", map[string]interface{}{"team_id": 7}, "email is required"},
+		{"bad email", map[string]interface{}{"team_id": 7, "email": "not an email"}, "invalid email"},
+		{"display name", map[string]interface{}{"team_id": 7, "email": "New Hire <new@example.com>"}, "invalid email"},
+		{"non-boolean admin", map[string]interface{}{"team_id": 7, "email": "new@example.com", "admin": "yes"}, "admin must be a boolean"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: c.args}}
+			result, err := handleCreateTeamInvitation(context.Background(), hbapi.NewClient(), req)
+			if err != nil {
+				t.Fatalf("handleCreateTeamInvitation() error = %v", err)
+			}
+			if !result.IsError || !strings.Contains(getResultText(result), c.want) {
+				t.Errorf("expected error containing %q, got %s", c.want, getResultText(result))
+			}
+		})
+	}
+}
+
+func TestHandleDeleteTeamInvitation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "DELETE" {
+			t.Errorf("expected DELETE method, got %s", r.Method)
+		}
+		if r.URL.Path != "/v2/teams/7/team_invitations/2" {
+			t.Errorf("expected path /v2/teams/7/team_invitations/2, got %s", r.URL.Path)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Arguments: map[string]interface{}{"team_id": 7, "invitation_id": 2},
+		},
+	}
+
+	result, err := handleDeleteTeamInvitation(context.Background(), client, req)
+	if err != nil {
+		t.Fatalf("handleDeleteTeamInvitation() error = %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected successful result, got error: %s", getResultText(result))
+	}
+	if !strings.Contains(getResultText(result), "deleted successfully") {
+		t.Error("Result should contain success message")
+	}
+
+	req.Params.Arguments = map[string]interface{}{"team_id": 7, "invitation_id": 2.5}
+	result, _ = handleDeleteTeamInvitation(context.Background(), client, req)
+	if !result.IsError {
+		t.Error("expected a fractional invitation_id to be rejected")
+	}
+}
@@ -0,0 +1,51 @@
+package hbmcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	timeoutArg = "timeout_seconds"
+
+	// maxToolTimeout bounds timeout_seconds; it is a way to cut a call
+	// short, not to outlast the server's own limits.
+	maxToolTimeout = 600
+)
+
+// withTimeoutArg adds the timeout_seconds parameter every tool accepts.
+func withTimeoutArg(tool *mcp.Tool) {
+	mcp.WithNumber(timeoutArg,
+		mcp.Description(fmt.Sprintf("Optional limit on how long this call may take, in seconds (at most %d). API requests still in flight when it passes are canceled and the call fails. Each API request is also bound by the server's API timeout.", maxToolTimeout)),
+		mcp.Min(1),
+		mcp.Max(maxToolTimeout),
+	)(tool)
+}
+
+// toolTimeout gives a call with timeout_seconds a context with that
+// deadline, so hbapi cancels the underlying HTTP requests when it passes.
+func toolTimeout(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		raw, ok := req.GetArguments()[timeoutArg]
+		if !ok || raw == nil {
+			return next(ctx, req)
+		}
+		seconds, ok := raw.(float64)
+		if !ok || seconds <= 0 || seconds > maxToolTimeout {
+			return mcp.NewToolResultError(fmt.Sprintf("%s must be a number of seconds between 1 and %d", timeoutArg, maxToolTimeout)), nil
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, time.Duration(seconds*float64(time.Second)))
+		defer cancel()
+		result, err := next(ctx, req)
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) && (err != nil || result == nil || result.IsError) {
+			return mcp.NewToolResultError(fmt.Sprintf("Timed out after %vs (%s); retry with a longer timeout or a narrower request", seconds, timeoutArg)), nil
+		}
+		return result, err
+	}
+}
@@ -0,0 +1,81 @@
+package hbmcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestToolTimeout(t *testing.T) {
+	release := make(chan struct{})
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer api.Close()
+	defer close(release)
+	client := hbapi.NewClient().WithBaseURL(api.URL).WithAuthToken("test-token")
+
+	var deadline time.Time
+	handler := toolTimeout(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		deadline, _ = ctx.Deadline()
+		return handleQueryInsights(ctx, client, req)
+	})
+	call := func(args map[string]any) *mcp.CallToolResult {
+		t.Helper()
+		result, err := handler(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: args}})
+		if err != nil {
+			t.Fatalf("handler error = %v", err)
+		}
+		return result
+	}
+
+	start := time.Now()
+	result := call(map[string]any{"project_id": 1, "query": "fields @ts", "timeout_seconds": 0.2})
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("call took %v; expected the timeout to cancel the request", elapsed)
+	}
+	if !result.IsError || !strings.Contains(getResultText(result), "Timed out after 0.2s (timeout_seconds)") {
+		t.Errorf("expected a timeout error, got %q", getResultText(result))
+	}
+
+	for _, bad := range []any{0, -1, float64(maxToolTimeout + 1), "10"} {
+		deadline = time.Time{}
+		result := call(map[string]any{"project_id": 1, "query": "fields @ts", "timeout_seconds": bad})
+		if !result.IsError || !strings.Contains(getResultText(result), "timeout_seconds must be") || !deadline.IsZero() {
+			t.Errorf("timeout_seconds %v: expected a validation error before the handler ran, got %q", bad, getResultText(result))
+		}
+	}
+}
+
+func TestToolRegistrar_TimeoutArg(t *testing.T) {
+	s := server.NewMCPServer("test", "1.0.0", server.WithToolCapabilities(true))
+	r := newToolRegistrar(s)
+	r.AddTool(mcp.NewTool("example"), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if _, ok := ctx.Deadline(); !ok {
+			return mcp.NewToolResultError("no deadline"), nil
+		}
+		return mcp.NewToolResultText("ok"), nil
+	})
+
+	tool := s.GetTool("example")
+	if tool == nil {
+		t.Fatal("example tool not registered")
+	}
+	if _, ok := tool.Tool.InputSchema.Properties[timeoutArg]; !ok {
+		t.Errorf("expected %s in the tool's input schema", timeoutArg)
+	}
+	result, err := tool.Handler(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]any{timeoutArg: float64(30)}}})
+	if err != nil || result.IsError {
+		t.Errorf("expected the handler to run with a deadline, got %q, %v", getResultText(result), err)
+	}
+}
@@ -0,0 +1,363 @@
+package hbmcp
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// timestampHint is appended to the description of every time argument that
+// goes through resolveTimeWindow, so the accepted forms are documented once.
+const timestampHint = " (ISO 8601, relative like '-24h' or 'yesterday', or a phrase like 'last week', 'since Monday', 'yesterday 2pm-4pm')"
+
+// timezoneDescription documents the timezone parameter of the report and
+// count tools.
+const timezoneDescription = "IANA timezone identifier (e.g., 'America/New_York') that relative times such as 'yesterday' are resolved in and returned timestamps and day buckets are shown in (default UTC)"
+
+// timezoneArg reads the timezone argument, defaulting to UTC.
+func timezoneArg(req mcp.CallToolRequest) (*time.Location, error) {
+	name := strings.TrimSpace(req.GetString("timezone", ""))
+	if name == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: use an IANA name such as 'America/New_York' or 'UTC'", name)
+	}
+	return loc, nil
+}
+
+// timeRange is a resolved time expression. End is zero for open-ended
+// expressions ("since Monday", "-24h"), which only bound one side.
+type timeRange struct {
+	Start time.Time
+	End   time.Time
+}
+
+var (
+	clockPattern    = `\d{1,2}(?::\d{2})?\s*(?:am|pm)?`
+	daySpanPattern  = regexp.MustCompile(`^(.+?)\s+(?:from\s+|between\s+)?(` + clockPattern + `)\s*(?:-|to|until|and)\s*(` + clockPattern + `)$`)
+	dayClockPattern = regexp.MustCompile(`^(.+?)\s+(?:at\s+)?(` + clockPattern + `)$`)
+	lastPattern     = regexp.MustCompile(`^(?:last|past)\s+(?:(\d+)\s+)?(minute|hour|day|week|month)s?$`)
+	agoPattern      = regexp.MustCompile(`^(\d+)\s+(minute|hour|day|week|month)s?\s+ago$`)
+	rangePattern    = regexp.MustCompile(`^(?:from\s+|between\s+)?(.+?)\s+(?:to|until|and)\s+(.+)$`)
+)
+
+// parseTimeExpr resolves the time expressions agents write when filtering:
+//
+//   - points: RFC 3339, YYYY-MM-DD, now, today, yesterday, weekday names
+//     (the most recent one, today included; "last monday" excludes today),
+//     "-24h"/"-7d", "3 days ago",
+//     "last 24 hours", "past week", "this week", "this month"
+//   - open ranges: "since <point>"
+//   - bounded ranges: "<day> 2pm-4pm", "<day> from 09:00 to 17:30",
+//     "<point> to <point>", "between <point> and <point>"; an end point
+//     naming a day, like "2024-01-05" or "friday", includes that day
+//
+// Calendar words resolve to midnight in loc, and weeks start on Monday.
+// "last week" is the rolling past seven days, not the previous calendar week.
+func parseTimeExpr(expr string, now time.Time, loc *time.Location) (timeRange, error) {
+	s := strings.Join(strings.Fields(strings.ToLower(expr)), " ")
+	if s == "" {
+		return timeRange{}, fmt.Errorf("empty time expression")
+	}
+	// RFC 3339 keeps its case-sensitive form; ToLower would break the T and Z.
+	if t, err := time.Parse(time.RFC3339, strings.TrimSpace(expr)); err == nil {
+		return timeRange{Start: t}, nil
+	}
+	now = now.In(loc)
+
+	if rest, ok := strings.CutPrefix(s, "since "); ok {
+		start, err := parseTimePoint(rest, now, loc)
+		if err != nil {
+			return timeRange{}, err
+		}
+		return timeRange{Start: start}, nil
+	}
+
+	if m := daySpanPattern.FindStringSubmatch(s); m != nil {
+		if day, err := parseTimePoint(m[1], now, loc); err == nil && isMidnight(day) {
+			from, okFrom := parseClock(m[2])
+			to, okTo := parseClock(m[3])
+			if okFrom && okTo {
+				start, end := day.Add(from), day.Add(to)
+				if !end.After(start) {
+					return timeRange{}, fmt.Errorf("%q ends before it starts", expr)
+				}
+				return timeRange{Start: start, End: end}, nil
+			}
+		}
+	}
+
+	if m := rangePattern.FindStringSubmatch(s); m != nil {
+		start, errStart := parseTimePoint(m[1], now, loc)
+		end, errEnd := parseTimePoint(m[2], now, loc)
+		if errEnd == nil && isCalendarDay(m[2]) {
+			// "to Friday" takes in Friday.
+			end = end.AddDate(0, 0, 1)
+		}
+		if errStart == nil && errEnd == nil {
+			if !end.After(start) {
+				return timeRange{}, fmt.Errorf("%q ends before it starts", expr)
+			}
+			return timeRange{Start: start, End: end}, nil
+		}
+	}
+
+	start, err := parseTimePoint(s, now, loc)
+	if err != nil {
+		return timeRange{}, err
+	}
+	return timeRange{Start: start}, nil
+}
+
+// isCalendarDay reports whether the time point s names a whole day, such
+// as "2024-01-05", "yesterday", or "friday", rather than an instant.
+func isCalendarDay(s string) bool {
+	switch s {
+	case "today", "yesterday":
+		return true
+	}
+	if _, err := time.Parse(time.DateOnly, s); err == nil {
+		return true
+	}
+	_, ok := parseWeekday(strings.TrimPrefix(s, "last "))
+	return ok
+}
+
+// parseTimePoint resolves a single instant. s is already lowercased and
+// whitespace-normalized; now is already in loc.
+func parseTimePoint(s string, now time.Time, loc *time.Location) (time.Time, error) {
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+
+	switch s {
+	case "now":
+		return now, nil
+	case "today":
+		return midnight, nil
+	case "yesterday":
+		return midnight.AddDate(0, 0, -1), nil
+	case "this week":
+		return midnight.AddDate(0, 0, -daysSinceMonday(now.Weekday())), nil
+	case "this month":
+		return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, loc), nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, strings.ToUpper(s)); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation(time.DateOnly, s, loc); err == nil {
+		return t, nil
+	}
+	if wd, ok := parseWeekday(s); ok {
+		back := (int(now.Weekday()) - int(wd) + 7) % 7
+		return midnight.AddDate(0, 0, -back), nil
+	}
+	if rest, ok := strings.CutPrefix(s, "last "); ok {
+		if wd, ok := parseWeekday(rest); ok {
+			back := (int(now.Weekday())-int(wd)+6)%7 + 1
+			return midnight.AddDate(0, 0, -back), nil
+		}
+	}
+	if rest, ok := strings.CutPrefix(s, "-"); ok {
+		if d, ok := parseSpan(rest); ok {
+			return now.Add(-d), nil
+		}
+	}
+	if m := lastPattern.FindStringSubmatch(s); m != nil {
+		n := 1
+		if m[1] != "" {
+			n, _ = strconv.Atoi(m[1])
+		}
+		return subtractUnits(now, n, m[2]), nil
+	}
+	if m := agoPattern.FindStringSubmatch(s); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		return subtractUnits(now, n, m[2]), nil
+	}
+	if m := dayClockPattern.FindStringSubmatch(s); m != nil {
+		if day, err := parseTimePoint(m[1], now, loc); err == nil && isMidnight(day) {
+			if offset, ok := parseClock(m[2]); ok {
+				return day.Add(offset), nil
+			}
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("unrecognized time %q", s)
+}
+
+func subtractUnits(now time.Time, n int, unit string) time.Time {
+	switch unit {
+	case "minute":
+		return now.Add(-time.Duration(n) * time.Minute)
+	case "hour":
+		return now.Add(-time.Duration(n) * time.Hour)
+	case "day":
+		return now.AddDate(0, 0, -n)
+	case "week":
+		return now.AddDate(0, 0, -7*n)
+	default: // month
+		return now.AddDate(0, -n, 0)
+	}
+}
+
+// parseClock parses "2pm", "2:30pm", "14:00", or "9" into an offset from
+// midnight.
+func parseClock(s string) (time.Duration, bool) {
+	s = strings.ReplaceAll(s, " ", "")
+	meridiem := ""
+	if strings.HasSuffix(s, "am") || strings.HasSuffix(s, "pm") {
+		meridiem, s = s[len(s)-2:], s[:len(s)-2]
+	}
+	hourStr, minStr, hasMin := strings.Cut(s, ":")
+	hour, err := strconv.Atoi(hourStr)
+	if err != nil {
+		return 0, false
+	}
+	minute := 0
+	if hasMin {
+		if minute, err = strconv.Atoi(minStr); err != nil || minute > 59 {
+			return 0, false
+		}
+	}
+	switch meridiem {
+	case "am", "pm":
+		if hour < 1 || hour > 12 {
+			return 0, false
+		}
+		hour %= 12
+		if meridiem == "pm" {
+			hour += 12
+		}
+	default:
+		if hour > 24 || (hour == 24 && minute > 0) {
+			return 0, false
+		}
+	}
+	return time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute, true
+}
+
+func parseWeekday(s string) (time.Weekday, bool) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		name := strings.ToLower(d.String())
+		if s == name || s == name[:3] {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+func daysSinceMonday(wd time.Weekday) int {
+	return (int(wd) + 6) % 7
+}
+
+func isMidnight(t time.Time) bool {
+	return t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 && t.Nanosecond() == 0
+}
+
+// parseSpan parses "<n><unit>" with a non-negative integer n and a unit of
+// s, m, h, d (24h), or w (7d). time.ParseDuration stops at hours, which is
+// too short for the day- and week-scale windows agents ask about.
+func parseSpan(s string) (time.Duration, bool) {
+	if len(s) < 2 {
+		return 0, false
+	}
+	n, err := strconv.Atoi(s[:len(s)-1])
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	var unit time.Duration
+	switch s[len(s)-1] {
+	case 's':
+		unit = time.Second
+	case 'm':
+		unit = time.Minute
+	case 'h':
+		unit = time.Hour
+	case 'd':
+		unit = 24 * time.Hour
+	case 'w':
+		unit = 7 * 24 * time.Hour
+	default:
+		return 0, false
+	}
+	return time.Duration(n) * unit, true
+}
+
+// timeWindow is the resolved pair of bounds for one lower/upper argument
+// pair (e.g. occurred_after/occurred_before). Resolved describes every bound
+// that came from something other than a literal RFC 3339 timestamp, keyed by
+// argument name, so the caller can echo the interpretation back via
+// toolNotes.addResolved.
+type timeWindow struct {
+	After    time.Time
+	Before   time.Time
+	Resolved map[string]string
+}
+
+// resolveTimeWindow parses the lower and upper time arguments of a tool.
+// When only the lower argument is given and it is a bounded range such as
+// "yesterday 2pm-4pm", its end fills the upper bound. Pass an empty upper
+// name for tools with a lower bound only. An unparseable argument is an
+// error rather than a silently dropped filter.
+func resolveTimeWindow(req mcp.CallToolRequest, lower, upper string, now time.Time, loc *time.Location) (timeWindow, error) {
+	w := timeWindow{Resolved: map[string]string{}}
+	sources := map[string]string{}
+
+	parse := func(name string) (timeRange, bool, error) {
+		if name == "" {
+			return timeRange{}, false, nil
+		}
+		raw := strings.TrimSpace(req.GetString(name, ""))
+		if raw == "" {
+			return timeRange{}, false, nil
+		}
+		r, err := parseTimeExpr(raw, now, loc)
+		if err != nil {
+			return timeRange{}, false, fmt.Errorf("invalid %s: %w", name, err)
+		}
+		if _, err := time.Parse(time.RFC3339, raw); err != nil {
+			sources[name] = raw
+		}
+		return r, true, nil
+	}
+
+	lo, hasLo, err := parse(lower)
+	if err != nil {
+		return w, err
+	}
+	hi, hasHi, err := parse(upper)
+	if err != nil {
+		return w, err
+	}
+
+	if hasLo {
+		w.After = lo.Start
+	}
+	switch {
+	case hasHi && !hi.End.IsZero():
+		w.Before = hi.End
+	case hasHi:
+		w.Before = hi.Start
+	case hasLo && upper != "" && !lo.End.IsZero():
+		w.Before = lo.End
+		sources[upper] = sources[lower]
+	}
+
+	if !w.After.IsZero() && !w.Before.IsZero() && !w.Before.After(w.After) {
+		return w, fmt.Errorf("%s must be after %s", upper, lower)
+	}
+
+	for name, raw := range sources {
+		t := w.After
+		if name == upper {
+			t = w.Before
+		}
+		w.Resolved[name] = fmt.Sprintf("%s (from %q)", t.In(loc).Format(time.RFC3339), raw)
+	}
+	return w, nil
+}
@@ -0,0 +1,228 @@
+package hbmcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestParseTimeExpr(t *testing.T) {
+	// A Friday afternoon
+	now := time.Date(2024, 3, 15, 14, 30, 0, 0, time.UTC)
+	day := func(d int) time.Time { return time.Date(2024, 3, d, 0, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		name  string
+		in    string
+		start time.Time
+		end   time.Time
+	}{
+		{"rfc3339", "2024-01-02T15:04:05Z", time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC), time.Time{}},
+		{"date", "2024-03-01", day(1), time.Time{}},
+		{"now", "now", now, time.Time{}},
+		{"today", "today", day(15), time.Time{}},
+		{"yesterday", "Yesterday", day(14), time.Time{}},
+		{"hours offset", "-24h", now.Add(-24 * time.Hour), time.Time{}},
+		{"days offset", "-7d", now.AddDate(0, 0, -7), time.Time{}},
+		{"surrounding whitespace", " -1h ", now.Add(-time.Hour), time.Time{}},
+		{"ago", "3 days ago", now.AddDate(0, 0, -3), time.Time{}},
+		{"last week", "last week", now.AddDate(0, 0, -7), time.Time{}},
+		{"past hours", "past 6 hours", now.Add(-6 * time.Hour), time.Time{}},
+		{"this week", "this week", day(11), time.Time{}},
+		{"this month", "this month", day(1), time.Time{}},
+		{"weekday includes today", "friday", day(15), time.Time{}},
+		{"last weekday excludes today", "last friday", day(8), time.Time{}},
+		{"since weekday", "since Monday", day(11), time.Time{}},
+		{"day and clock", "yesterday at 9am", day(14).Add(9 * time.Hour), time.Time{}},
+		{"clock span", "yesterday 2pm-4pm", day(14).Add(14 * time.Hour), day(14).Add(16 * time.Hour)},
+		{"24h clock span", "tuesday from 09:00 to 17:30", day(12).Add(9 * time.Hour), day(12).Add(17*time.Hour + 30*time.Minute)},
+		{"date range", "2024-01-01 to 2024-01-05", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 1, 6, 0, 0, 0, 0, time.UTC)},
+		{"between", "between monday and wednesday", day(11), day(14)},
+		{"through today", "yesterday to today", day(14), day(16)},
+		{"same day", "2024-03-12 to 2024-03-12", day(12), day(13)},
+		{"instant end", "monday to -1h", day(11), now.Add(-time.Hour)},
+		{"clock end", "monday to wednesday 9am", day(11), day(13).Add(9 * time.Hour)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseTimeExpr(tt.in, now, time.UTC)
+			if err != nil {
+				t.Fatalf("parseTimeExpr(%q) error = %v", tt.in, err)
+			}
+			if !got.Start.Equal(tt.start) || !got.End.Equal(tt.end) {
+				t.Errorf("parseTimeExpr(%q) = %v..%v, want %v..%v", tt.in, got.Start, got.End, tt.start, tt.end)
+			}
+		})
+	}
+}
+
+func TestParseTimeExpr_Invalid(t *testing.T) {
+	now := time.Date(2024, 3, 15, 14, 30, 0, 0, time.UTC)
+
+	for _, in := range []string{"", "24h", "-3y", "-1.5h", "last tuesday-ish", "yesterday 4pm-2pm", "2024-01-05 to 2024-01-01", "today 13pm"} {
+		if got, err := parseTimeExpr(in, now, time.UTC); err == nil {
+			t.Errorf("parseTimeExpr(%q) = %v, want error", in, got)
+		}
+	}
+}
+
+func TestParseTimeExpr_Location(t *testing.T) {
+	loc := time.FixedZone("UTC-5", -5*60*60)
+	// 02:00 UTC on the 15th is still the 14th in UTC-5
+	now := time.Date(2024, 3, 15, 2, 0, 0, 0, time.UTC)
+
+	got, err := parseTimeExpr("today", now, loc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2024, 3, 14, 5, 0, 0, 0, time.UTC); !got.Start.Equal(want) {
+		t.Errorf("today = %v, want %v", got.Start.UTC(), want)
+	}
+}
+
+func TestResolveTimeWindow(t *testing.T) {
+	now := time.Date(2024, 3, 15, 14, 30, 0, 0, time.UTC)
+	request := func(args map[string]interface{}) mcp.CallToolRequest {
+		return mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: args}}
+	}
+
+	t.Run("range in lower argument fills upper", func(t *testing.T) {
+		w, err := resolveTimeWindow(request(map[string]interface{}{"start": "yesterday 2pm-4pm"}), "start", "stop", now, time.UTC)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if w.After != time.Date(2024, 3, 14, 14, 0, 0, 0, time.UTC) || w.Before != time.Date(2024, 3, 14, 16, 0, 0, 0, time.UTC) {
+			t.Errorf("got %v..%v", w.After, w.Before)
+		}
+		if w.Resolved["stop"] != `2024-03-14T16:00:00Z (from "yesterday 2pm-4pm")` {
+			t.Errorf("unexpected resolved stop %q", w.Resolved["stop"])
+		}
+	})
+
+	t.Run("literal timestamps are not echoed", func(t *testing.T) {
+		w, err := resolveTimeWindow(request(map[string]interface{}{"start": "2024-03-01T00:00:00Z", "stop": "today"}), "start", "stop", now, time.UTC)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := w.Resolved["start"]; ok {
+			t.Error("literal start should not be echoed")
+		}
+		if w.Resolved["stop"] != `2024-03-15T00:00:00Z (from "today")` {
+			t.Errorf("unexpected resolved stop %q", w.Resolved["stop"])
+		}
+	})
+
+	t.Run("resolved in timezone", func(t *testing.T) {
+		ny, err := time.LoadLocation("America/New_York")
+		if err != nil {
+			t.Skip(err)
+		}
+		w, err := resolveTimeWindow(request(map[string]interface{}{"start": "today"}), "start", "stop", now, ny)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if w.Resolved["start"] != `2024-03-15T00:00:00-04:00 (from "today")` {
+			t.Errorf("unexpected resolved start %q", w.Resolved["start"])
+		}
+	})
+
+	t.Run("reversed bounds", func(t *testing.T) {
+		_, err := resolveTimeWindow(request(map[string]interface{}{"start": "today", "stop": "yesterday"}), "start", "stop", now, time.UTC)
+		if err == nil || !strings.Contains(err.Error(), "stop must be after start") {
+			t.Errorf("expected ordering error, got %v", err)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := resolveTimeWindow(request(map[string]interface{}{"start": "someday"}), "start", "stop", now, time.UTC)
+		if err == nil || !strings.Contains(err.Error(), "invalid start") {
+			t.Errorf("expected invalid start error, got %v", err)
+		}
+	})
+}
+
+func TestHandleListFaultNotices_ResolvedTimeRange(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"results": [], "links": {}}`))
+	}))
+	defer server.Close()
+
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Arguments: map[string]interface{}{
+				"project_id":    1,
+				"fault_id":      2,
+				"created_after": "yesterday 2pm-4pm",
+			},
+		},
+	}
+
+	result, err := handleListFaultNotices(context.Background(), client, req)
+	if err != nil {
+		t.Fatalf("handleListFaultNotices() error = %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got %s", getResultText(result))
+	}
+	if !strings.Contains(query, "created_after=") || !strings.Contains(query, "created_before=") {
+		t.Errorf("expected both bounds in query, got %q", query)
+	}
+	if len(result.Content) != 2 {
+		t.Fatalf("expected resolved range content block, got %d blocks", len(result.Content))
+	}
+
+	notes := getResultNotes(t, result)
+	if !strings.Contains(notes.ResolvedTimeRange["created_after"], "yesterday 2pm-4pm") {
+		t.Errorf("unexpected resolved range %v", notes.ResolvedTimeRange)
+	}
+}
+
+func TestHandleListFaults_InvalidTime(t *testing.T) {
+	client := hbapi.NewClient().WithBaseURL("http://unused.invalid").WithAuthToken("test-token")
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Arguments: map[string]interface{}{
+				"project_id":     1,
+				"occurred_after": "the other day",
+			},
+		},
+	}
+
+	result, err := handleListFaults(context.Background(), client, req)
+	if err != nil {
+		t.Fatalf("handleListFaults() error = %v", err)
+	}
+	if !result.IsError || !strings.Contains(getResultText(result), "invalid occurred_after") {
+		t.Errorf("expected invalid occurred_after error, got %s", getResultText(result))
+	}
+}
+
+func TestTimezoneArg(t *testing.T) {
+	for _, tt := range []struct {
+		timezone string
+		want     string
+		wantErr  bool
+	}{
+		{"", "UTC", false},
+		{" America/New_York ", "America/New_York", false},
+		{"Mars/Olympus", "", true},
+	} {
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = map[string]any{"timezone": tt.timezone}
+		loc, err := timezoneArg(req)
+		if (err != nil) != tt.wantErr || (err == nil && loc.String() != tt.want) {
+			t.Errorf("timezoneArg(%q) = %v, %v; want %s", tt.timezone, loc, err, tt.want)
+		}
+	}
+}
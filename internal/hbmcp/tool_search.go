@@ -1,9 +1,15 @@
 package hbmcp
 
 import (
+	"cmp"
 	"context"
+	"encoding/json"
 	"fmt"
+	"log/slog"
+	"math"
+	"slices"
 	"strings"
+	"unicode"
 
 	"github.com/honeybadger-io/honeybadger-mcp-server/internal/config"
 	"github.com/mark3labs/mcp-go/mcp"
@@ -19,6 +25,39 @@ type ToolInfo struct {
 type toolRegistrar struct {
 	server  *server.MCPServer
 	catalog []ToolInfo
+
+	// middleware are the layers NewServer adds between the fields layer
+	// and the built-in ones, the first outermost (see layers).
+	middleware []toolLayer
+
+	// logger receives what the layers log, such as recovered panics. It
+	// defaults to slog.Default().
+	logger *slog.Logger
+
+	// current, when set, is the live config write tools check so they
+	// refuse calls in read-only mode.
+	current func() *config.Config
+
+	// confirmations, when set, holds destructive tools' calls until they
+	// are confirmed (--confirm-destructive).
+	confirmations *confirmations
+
+	// creates, when set, answers a retried create call with the first
+	// one's result instead of creating again.
+	creates *createReplays
+
+	// results, when set, lets read-only tools reuse an earlier identical
+	// call's result when asked to.
+	results *resultCaches
+
+	// lookupNotFound has get_fault and get_project look for what the API
+	// couldn't find in the token's other projects and accounts
+	// (--not-found-lookup).
+	lookupNotFound bool
+
+	// maxResultTokens, when above zero, is the estimated size past which a
+	// result is replaced by a summary (--max-result-tokens).
+	maxResultTokens int
 }
 
 func newToolRegistrar(s *server.MCPServer) *toolRegistrar {
@@ -28,42 +67,207 @@ func newToolRegistrar(s *server.MCPServer) *toolRegistrar {
 }
 
 func (r *toolRegistrar) AddTool(tool mcp.Tool, handler server.ToolHandlerFunc) {
+	readOnly := isReadOnly(tool)
+	layers := r.layers()
+	for _, l := range layers {
+		if l.define != nil {
+			l.define(&tool)
+		}
+	}
+	for i := len(layers) - 1; i >= 0; i-- {
+		handler = layers[i].wrap(tool, handler)
+	}
 	r.server.AddTool(tool, handler)
 	r.catalog = append(r.catalog, ToolInfo{
 		Name:        tool.Name,
 		Description: tool.Description,
-		ReadOnly:    tool.Annotations.ReadOnlyHint != nil && *tool.Annotations.ReadOnlyHint,
+		ReadOnly:    readOnly,
 	})
 }
 
-func searchCatalog(catalog []ToolInfo, query string) []ToolInfo {
-	q := strings.ToLower(query)
-	var results []ToolInfo
+// searchResult is a catalog entry matched by a search, with its relevance
+// from 0 to 1.
+type searchResult struct {
+	ToolInfo
+	Score float64
+}
+
+// Per-term weights. A term's best match counts, so a term found in a
+// tool's name outranks one found only in its description.
+const (
+	nameTokenWeight = 3.0 // a word of the name, e.g. "faults" in list_faults
+	nameWeight      = 2.5 // part of the name
+	nameFuzzyWeight = 1.5 // a name word within a typo or two
+	descWordWeight  = 1.0 // a word of the description, or its start
+	descWeight      = 0.5 // part of the description
+	descFuzzyWeight = 0.4 // a description word within a typo or two
+)
+
+// searchStopWords are ignored in queries of more than just them, since
+// they'd match nearly every description.
+var searchStopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "by": true, "for": true, "in": true,
+	"of": true, "on": true, "or": true, "the": true, "to": true, "with": true,
+}
+
+// searchCatalog returns the tools matching any word of query, most
+// relevant first. Each word scores by where it's found, exact matches
+// above partial ones above near misses, and scores are summed over the
+// query's words, so a tool matching all of them ranks above one matching
+// some.
+func searchCatalog(catalog []ToolInfo, query string) []searchResult {
+	terms := searchTerms(query)
+	if len(terms) == 0 {
+		return nil
+	}
+	exact := strings.Join(terms, "_")
+
+	var results []searchResult
 	for _, t := range catalog {
-		if strings.Contains(strings.ToLower(t.Name), q) ||
-			strings.Contains(strings.ToLower(t.Description), q) {
-			results = append(results, t)
+		name := strings.ToLower(t.Name)
+		nameTokens := strings.Split(name, "_")
+		desc := strings.ToLower(t.Description)
+		descWords := strings.FieldsFunc(desc, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+
+		total := 0.0
+		for _, term := range terms {
+			total += termScore(term, name, nameTokens, desc, descWords)
+		}
+		if total == 0 {
+			continue
+		}
+		score := total / (nameTokenWeight * float64(len(terms)))
+		if name == exact {
+			score = 1
 		}
+		results = append(results, searchResult{ToolInfo: t, Score: math.Round(score*100) / 100})
 	}
+	slices.SortStableFunc(results, func(a, b searchResult) int {
+		return cmp.Compare(b.Score, a.Score)
+	})
 	return results
 }
 
+// searchTerms splits a query into lowercase words, treating underscores
+// and hyphens as spaces so tool names can be searched for as typed.
+func searchTerms(query string) []string {
+	words := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return unicode.IsSpace(r) || r == '_' || r == '-'
+	})
+	var terms []string
+	for _, w := range words {
+		if !searchStopWords[w] {
+			terms = append(terms, w)
+		}
+	}
+	if len(terms) == 0 {
+		return words
+	}
+	return terms
+}
+
+func termScore(term, name string, nameTokens []string, desc string, descWords []string) float64 {
+	switch {
+	case slices.Contains(nameTokens, term):
+		return nameTokenWeight
+	case strings.Contains(name, term):
+		return nameWeight
+	case fuzzyContains(nameTokens, term):
+		return nameFuzzyWeight
+	}
+	for _, w := range descWords {
+		if strings.HasPrefix(w, term) {
+			return descWordWeight
+		}
+	}
+	switch {
+	case strings.Contains(desc, term):
+		return descWeight
+	case fuzzyContains(descWords, term):
+		return descFuzzyWeight
+	}
+	return 0
+}
+
+// fuzzyContains reports whether a word is within a typo of term: one edit
+// for terms of five or more letters, two for eight or more. Shorter terms
+// must match exactly, since one edit turns them into too many other words.
+func fuzzyContains(words []string, term string) bool {
+	maxEdits := 0
+	switch n := len(term); {
+	case n >= 8:
+		maxEdits = 2
+	case n >= 5:
+		maxEdits = 1
+	default:
+		return false
+	}
+	for _, w := range words {
+		if editDistance(term, w, maxEdits) <= maxEdits {
+			return true
+		}
+	}
+	return false
+}
+
+// editDistance counts the insertions, deletions, substitutions, and
+// transpositions of adjacent letters that turn a into b, stopping early
+// once that is certain to exceed limit.
+func editDistance(a, b string, limit int) int {
+	if d := len(a) - len(b); d > limit || -d > limit {
+		return limit + 1
+	}
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		rowMin := curr[0]
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+			rowMin = min(rowMin, curr[j])
+		}
+		if rowMin > limit {
+			return limit + 1
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+	return prev[len(b)]
+}
+
 var searchToolInfo = ToolInfo{
 	Name:        "search_tools",
-	Description: "Search available Honeybadger tools by name or description. Use this to discover tools before calling them.",
+	Description: "Search available Honeybadger tools by name or description. Use this to discover tools before calling them. Results are ranked best first, each with a relevance score from 0 to 1; multi-word queries and small typos are handled.",
 	ReadOnly:    true,
 }
 
-func registerSearchTool(s *server.MCPServer, catalog []ToolInfo, cfg *config.Config) {
+// registerSearchTool searches catalog, which may include tools the current
+// config disables; those are filtered out per query so a reload takes
+// effect immediately.
+func registerSearchTool(s *server.MCPServer, catalog []ToolInfo, current func() *config.Config) {
 	s.AddTool(
 		mcp.NewTool(searchToolInfo.Name,
 			mcp.WithTitleAnnotation("Search Tools"),
 			mcp.WithDescription(searchToolInfo.Description),
 			mcp.WithReadOnlyHintAnnotation(true),
 			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
 			mcp.WithString("query",
 				mcp.Required(),
-				mcp.Description("Search query to match against tool names and descriptions"),
+				mcp.Description("Words to match against tool names and descriptions, e.g. 'resolve fault'"),
 			),
 		),
 		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -72,10 +276,17 @@ func registerSearchTool(s *server.MCPServer, catalog []ToolInfo, cfg *config.Con
 				return mcp.NewToolResultError("query is required"), nil
 			}
 
-			searchable := catalog
-			if EffectiveReadOnly(ctx, cfg) {
-				searchable = filterReadOnlyCatalog(catalog)
+			cfg := current()
+			var searchable []ToolInfo
+			for _, t := range catalog {
+				if cfg.ToolEnabled(t.Name) {
+					searchable = append(searchable, t)
+				}
+			}
+			if hidesWriteTools(ctx, cfg) {
+				searchable = filterReadOnlyCatalog(searchable)
 			}
+			unavailable := EffectiveReadOnly(ctx, cfg) && !hidesWriteTools(ctx, cfg)
 
 			matches := searchCatalog(searchable, query)
 			if len(matches) == 0 {
@@ -91,13 +302,100 @@ func registerSearchTool(s *server.MCPServer, catalog []ToolInfo, cfg *config.Con
 				if m.ReadOnly {
 					readOnlyStr = "yes"
 				}
-				fmt.Fprintf(&sb, "Name: %s\nDescription: %s\nRead-only: %s", m.Name, m.Description, readOnlyStr)
+				fmt.Fprintf(&sb, "Name: %s\nDescription: %s\nRead-only: %s\nScore: %.2f", m.Name, m.Description, readOnlyStr, m.Score)
+				if unavailable && !m.ReadOnly {
+					sb.WriteString("\nAvailable: no, the server is read-only")
+				}
+				// Deferred tools aren't in tools/list, so the schema
+				// needed to call them through invoke_tool comes from here.
+				if cfg.DeferTools {
+					if tool := s.GetTool(m.Name); tool != nil {
+						schema, err := json.Marshal(tool.Tool.InputSchema)
+						if err == nil {
+							fmt.Fprintf(&sb, "\nInput schema: %s", schema)
+						}
+					}
+				}
 			}
 			return mcp.NewToolResultText(sb.String()), nil
 		},
 	)
 }
 
+var invokeToolInfo = ToolInfo{
+	Name:        "invoke_tool",
+	Description: "Call a Honeybadger tool by name. Find tools and their input schemas with search_tools first, then pass the tool name and its arguments here.",
+	ReadOnly:    false,
+}
+
+// registerInvokeTool registers invoke_tool, the single entry point to every
+// other tool when tools are deferred. Read-only mode is enforced here as
+// well as in search_tools, since the tool filter can't see through it.
+func registerInvokeTool(s *server.MCPServer, current func() *config.Config) {
+	s.AddTool(
+		mcp.NewTool(invokeToolInfo.Name,
+			mcp.WithTitleAnnotation("Invoke Tool"),
+			mcp.WithDescription(invokeToolInfo.Description),
+			mcp.WithReadOnlyHintAnnotation(false),
+			mcp.WithDestructiveHintAnnotation(true),
+			mcp.WithIdempotentHintAnnotation(false),
+			mcp.WithOpenWorldHintAnnotation(true),
+			mcp.WithString("name",
+				mcp.Required(),
+				mcp.Description("Name of the tool to call, as returned by search_tools"),
+			),
+			mcp.WithObject("arguments",
+				mcp.Description("Arguments for the tool, matching the input schema returned by search_tools"),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			name := strings.TrimSpace(req.GetString("name", ""))
+			if name == "" {
+				return mcp.NewToolResultError("name is required"), nil
+			}
+			if name == searchToolInfo.Name || name == invokeToolInfo.Name {
+				return mcp.NewToolResultError(fmt.Sprintf("%s can be called directly", name)), nil
+			}
+
+			tool := s.GetTool(name)
+			if tool == nil {
+				return mcp.NewToolResultError(fmt.Sprintf("Unknown tool %q. Use search_tools to find available tools.", name)), nil
+			}
+			readOnly := tool.Tool.Annotations.ReadOnlyHint != nil && *tool.Tool.Annotations.ReadOnlyHint
+			if cfg := current(); !readOnly && EffectiveReadOnly(ctx, cfg) {
+				return readOnlyError(ctx, cfg, name), nil
+			}
+
+			args, _ := req.GetArguments()["arguments"].(map[string]any)
+			inner := mcp.CallToolRequest{}
+			inner.Params.Name = name
+			inner.Params.Arguments = args
+			return tool.Handler(ctx, inner)
+		},
+	)
+}
+
+// deferredToolView returns what tools/list shows when tools are deferred.
+// In read-only mode invoke_tool can only reach read-only tools, so its
+// annotations are adjusted to say so.
+func deferredToolView(tools []mcp.Tool, readOnly bool) []mcp.Tool {
+	var visible []mcp.Tool
+	for _, tool := range tools {
+		switch tool.Name {
+		case searchToolInfo.Name:
+			visible = append(visible, tool)
+		case invokeToolInfo.Name:
+			if readOnly {
+				tool.Annotations.ReadOnlyHint = mcp.ToBoolPtr(true)
+				tool.Annotations.DestructiveHint = mcp.ToBoolPtr(false)
+				tool.Annotations.IdempotentHint = mcp.ToBoolPtr(true)
+			}
+			visible = append(visible, tool)
+		}
+	}
+	return visible
+}
+
 func filterReadOnlyCatalog(catalog []ToolInfo) []ToolInfo {
 	var filtered []ToolInfo
 	for _, t := range catalog {
@@ -3,6 +3,8 @@ package hbmcp
 import (
 	"context"
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 
@@ -195,6 +197,85 @@ func TestSearchCatalog_EmptyCatalog(t *testing.T) {
 	}
 }
 
+func TestSearchCatalog_Ranking(t *testing.T) {
+	catalog := []ToolInfo{
+		{Name: "list_faults", Description: "Get a list of faults for a project"},
+		{Name: "get_fault_counts", Description: "Get fault counts, optionally filtered"},
+		{Name: "resolve_fault", Description: "Mark a fault as resolved"},
+		{Name: "list_dashboards", Description: "List Insights dashboards for a project"},
+		{Name: "create_dashboard", Description: "Create an Insights dashboard"},
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{"name before description", "resolve", []string{"resolve_fault"}},
+		{"whole name", "list_faults", []string{"list_faults", "list_dashboards", "get_fault_counts", "resolve_fault"}},
+		{"all words before some", "create dashboard", []string{"create_dashboard", "list_dashboards"}},
+		{"stop words ignored", "list of dashboards", []string{"list_dashboards", "list_faults", "create_dashboard"}},
+		{"typo", "dashbaord", []string{"list_dashboards", "create_dashboard"}},
+		{"transposed typo", "reslove", []string{"resolve_fault"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := searchCatalog(catalog, tt.query)
+			var names []string
+			for _, r := range results {
+				names = append(names, r.Name)
+			}
+			if strings.Join(names, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("searchCatalog(%q) = %v, want %v", tt.query, names, tt.want)
+			}
+			for i := 1; i < len(results); i++ {
+				if results[i].Score > results[i-1].Score {
+					t.Errorf("results not sorted by score: %v", results)
+				}
+			}
+		})
+	}
+}
+
+func TestSearchCatalog_Scores(t *testing.T) {
+	catalog := []ToolInfo{
+		{Name: "list_faults", Description: "Get a list of faults for a project"},
+		{Name: "list_projects", Description: "List all projects"},
+	}
+
+	results := searchCatalog(catalog, "list faults")
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	if results[0].Name != "list_faults" || results[0].Score != 1 {
+		t.Errorf("expected list_faults to score 1, got %+v", results[0])
+	}
+	if results[1].Score <= 0 || results[1].Score >= 1 {
+		t.Errorf("expected a partial score for list_projects, got %+v", results[1])
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b  string
+		limit int
+		want  int
+	}{
+		{"fault", "fault", 2, 0},
+		{"falt", "fault", 2, 1},
+		{"fualt", "fault", 2, 1},
+		{"faults", "fault", 2, 1},
+		{"dashbaord", "dashboards", 2, 2},
+		{"project", "deploy", 2, 3},
+		{"a", "abcdef", 2, 3},
+	}
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b, tt.limit); got != tt.want {
+			t.Errorf("editDistance(%q, %q, %d) = %d, want %d", tt.a, tt.b, tt.limit, got, tt.want)
+		}
+	}
+}
+
 func TestRegisterSearchTool(t *testing.T) {
 	catalog := []ToolInfo{
 		{Name: "list_projects", Description: "List all Honeybadger projects", ReadOnly: true},
@@ -202,7 +283,7 @@ func TestRegisterSearchTool(t *testing.T) {
 	}
 
 	s := server.NewMCPServer("test", "1.0.0")
-	registerSearchTool(s, catalog, &config.Config{ReadOnly: false, TransportMode: config.TransportStdio})
+	registerSearchTool(s, catalog, staticConfig(&config.Config{ReadOnly: false, TransportMode: config.TransportStdio}))
 
 	// Verify search_tools is registered by calling it through HandleMessage
 	callMsg := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"search_tools","arguments":{"query":"list"}}}`
@@ -228,7 +309,7 @@ func TestRegisterSearchTool_NoMatches(t *testing.T) {
 	}
 
 	s := server.NewMCPServer("test", "1.0.0")
-	registerSearchTool(s, catalog, &config.Config{ReadOnly: false, TransportMode: config.TransportStdio})
+	registerSearchTool(s, catalog, staticConfig(&config.Config{ReadOnly: false, TransportMode: config.TransportStdio}))
 
 	callMsg := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"search_tools","arguments":{"query":"nonexistent"}}}`
 	resp := s.HandleMessage(context.Background(), []byte(callMsg))
@@ -252,7 +333,7 @@ func TestRegisterSearchTool_ReadOnlyMode(t *testing.T) {
 	}
 
 	s := server.NewMCPServer("test", "1.0.0")
-	registerSearchTool(s, catalog, &config.Config{ReadOnly: true, TransportMode: config.TransportStdio})
+	registerSearchTool(s, catalog, staticConfig(&config.Config{ReadOnly: true, TransportMode: config.TransportStdio}))
 
 	// Search for "project" - should only return read-only tools
 	callMsg := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"search_tools","arguments":{"query":"project"}}}`
@@ -294,3 +375,73 @@ func TestFilterReadOnlyCatalog(t *testing.T) {
 		}
 	}
 }
+
+func TestDeferTools(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"results": [{"id": 1, "name": "Web"}], "links": {}}`))
+	}))
+	defer api.Close()
+
+	cfg := &config.Config{
+		AuthToken:     "test-token",
+		APIURL:        api.URL,
+		LogLevel:      "info",
+		ReadOnly:      true,
+		TransportMode: config.TransportStdio,
+		DeferTools:    true,
+	}
+	s := NewServer(cfg, "test")
+
+	call := func(msg string) string {
+		t.Helper()
+		respBytes, err := json.Marshal(s.HandleMessage(context.Background(), []byte(msg)))
+		if err != nil {
+			t.Fatalf("failed to marshal response: %v", err)
+		}
+		return string(respBytes)
+	}
+
+	var listed struct {
+		Result struct {
+			Tools []mcp.Tool `json:"tools"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal([]byte(call(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`)), &listed); err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, tool := range listed.Result.Tools {
+		names = append(names, tool.Name)
+		if tool.Name == "invoke_tool" && (tool.Annotations.ReadOnlyHint == nil || !*tool.Annotations.ReadOnlyHint) {
+			t.Error("invoke_tool should be advertised as read-only in read-only mode")
+		}
+	}
+	if strings.Join(names, ",") != "invoke_tool,search_tools" {
+		t.Errorf("expected only invoke_tool and search_tools, got %v", names)
+	}
+
+	search := call(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"search_tools","arguments":{"query":"get_fault"}}}`)
+	if !strings.Contains(search, `Input schema: {\"properties\":{\"cache\"`) || !strings.Contains(search, `\"fault_id\":{`) {
+		t.Errorf("expected input schema in search results, got: %s", search)
+	}
+
+	invoked := call(`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"invoke_tool","arguments":{"name":"list_projects","arguments":{}}}}`)
+	if !strings.Contains(invoked, `\"name\":\"Web\"`) {
+		t.Errorf("expected list_projects result through invoke_tool, got: %s", invoked)
+	}
+
+	blocked := call(`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"invoke_tool","arguments":{"name":"delete_project","arguments":{"id":1}}}}`)
+	if !strings.Contains(blocked, "not available in read-only mode") {
+		t.Errorf("expected read-only rejection, got: %s", blocked)
+	}
+
+	unknown := call(`{"jsonrpc":"2.0","id":5,"method":"tools/call","params":{"name":"invoke_tool","arguments":{"name":"nope"}}}`)
+	if !strings.Contains(unknown, "Unknown tool") {
+		t.Errorf("expected unknown tool error, got: %s", unknown)
+	}
+}
+
+func staticConfig(cfg *config.Config) func() *config.Config {
+	return func() *config.Config { return cfg }
+}
@@ -0,0 +1,77 @@
+package hbmcp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Spans go to the global tracer provider, which is a no-op unless
+// telemetry.Setup installed an exporter.
+const tracerName = "github.com/honeybadger-io/honeybadger-mcp-server/internal/hbmcp"
+
+// tracingTransport records a client span for every Honeybadger API request.
+// The span is a child of the tool call span via the request context.
+type tracingTransport struct {
+	base http.RoundTripper
+}
+
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, span := otel.Tracer(tracerName).Start(req.Context(), "HTTP "+req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", req.Method),
+			attribute.String("server.address", req.URL.Hostname()),
+			attribute.String("url.path", req.URL.Path),
+		),
+	)
+	defer span.End()
+
+	resp, err := t.base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	if resp.StatusCode >= 400 {
+		span.SetStatus(codes.Error, resp.Status)
+	}
+	return resp, nil
+}
+
+// traceToolCalls wraps every tool handler in a span carrying the tool name,
+// the call's correlation ID, the project_id argument when there is one, and
+// whether the result was an error.
+func traceToolCalls(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx, span := otel.Tracer(tracerName).Start(ctx, "tool "+req.Params.Name,
+			trace.WithAttributes(attribute.String("mcp.tool.name", req.Params.Name)),
+		)
+		defer span.End()
+		if id := correlationID(ctx); id != "" {
+			span.SetAttributes(attribute.String("mcp.correlation_id", id))
+		}
+		if projectID := req.GetInt("project_id", 0); projectID != 0 {
+			span.SetAttributes(attribute.Int("honeybadger.project_id", projectID))
+		}
+
+		result, err := next(ctx, req)
+		switch {
+		case err != nil:
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		case result != nil && result.IsError:
+			span.SetAttributes(attribute.Bool("mcp.tool.is_error", true))
+			span.SetStatus(codes.Error, fmt.Sprintf("tool %s returned an error result", req.Params.Name))
+		}
+		return result, err
+	}
+}
@@ -0,0 +1,86 @@
+package hbmcp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+func spanAttr(span sdktrace.ReadOnlySpan, key attribute.Key) attribute.Value {
+	for _, kv := range span.Attributes() {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestTraceToolCalls(t *testing.T) {
+	recorder := recordSpans(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"errors": "Not found"}`))
+	}))
+	defer server.Close()
+
+	client := hbapi.NewClient().
+		WithBaseURL(server.URL).
+		WithAuthToken("test-token").
+		WithHTTPClient(&http.Client{Transport: &tracingTransport{base: http.DefaultTransport}})
+
+	handler := traceToolCalls(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return handleListStreams(ctx, client, req)
+	})
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "list_streams"
+	req.Params.Arguments = map[string]interface{}{"project_id": 42}
+
+	if _, err := handler(context.Background(), req); err != nil {
+		t.Fatalf("handler error = %v", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected API and tool spans, got %d", len(spans))
+	}
+	apiSpan, toolSpan := spans[0], spans[1]
+
+	if toolSpan.Name() != "tool list_streams" {
+		t.Errorf("unexpected tool span name %q", toolSpan.Name())
+	}
+	if got := spanAttr(toolSpan, "honeybadger.project_id").AsInt64(); got != 42 {
+		t.Errorf("project_id attribute = %d, want 42", got)
+	}
+	if toolSpan.Status().Code != codes.Error {
+		t.Error("tool span should be marked as an error for an error result")
+	}
+
+	if apiSpan.Parent().SpanID() != toolSpan.SpanContext().SpanID() {
+		t.Error("API span should be a child of the tool span")
+	}
+	if got := spanAttr(apiSpan, "http.response.status_code").AsInt64(); got != http.StatusNotFound {
+		t.Errorf("status code attribute = %d, want 404", got)
+	}
+	if got := spanAttr(apiSpan, "url.path").AsString(); got != "/v2/projects/42/streams" {
+		t.Errorf("url.path attribute = %q", got)
+	}
+}
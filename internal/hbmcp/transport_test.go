@@ -0,0 +1,110 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/pem"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/honeybadger-io/honeybadger-mcp-server/internal/config"
+)
+
+func TestNewBaseTransport_Default(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	if got := newBaseTransport(&config.Config{}, logger); got != http.DefaultTransport {
+		t.Error("expected http.DefaultTransport without proxy or CA bundle")
+	}
+}
+
+func TestNewBaseTransport_Proxy(t *testing.T) {
+	var proxied atomic.Int32
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// A forward proxy receives the absolute target URL.
+		if r.URL.Host == "api.honeybadger.invalid" {
+			proxied.Add(1)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"results": []}`))
+	}))
+	defer proxy.Close()
+
+	cfg := &config.Config{APIURL: "http://api.honeybadger.invalid", AuthToken: "test-token", ProxyURL: proxy.URL}
+	client := NewClientFactory(cfg, slog.New(slog.NewTextHandler(io.Discard, nil)))(context.Background())
+
+	if _, err := client.Streams.List(context.Background(), 1); err != nil {
+		t.Fatalf("request through proxy failed: %v", err)
+	}
+	if proxied.Load() != 1 {
+		t.Errorf("expected the request to go through the proxy, got %d", proxied.Load())
+	}
+}
+
+func TestNewBaseTransport_CABundle(t *testing.T) {
+	api := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"results": []}`))
+	}))
+	defer api.Close()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cfg := &config.Config{APIURL: api.URL, AuthToken: "test-token"}
+	if _, err := NewClientFactory(cfg, logger)(context.Background()).Streams.List(context.Background(), 1); err == nil {
+		t.Fatal("expected the test server's certificate to be untrusted without a CA bundle")
+	}
+
+	bundle := filepath.Join(t.TempDir(), "ca.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: api.Certificate().Raw})
+	if err := os.WriteFile(bundle, certPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg.CABundlePath = bundle
+	if _, err := NewClientFactory(cfg, logger)(context.Background()).Streams.List(context.Background(), 1); err != nil {
+		t.Fatalf("expected the CA bundle to be trusted: %v", err)
+	}
+}
+
+func TestNewClientFactory_AuthStyle(t *testing.T) {
+	var got atomic.Value
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got.Store(r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"results": []}`))
+	}))
+	defer api.Close()
+
+	basic := func(user, pass string) string {
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(user+":"+pass))
+	}
+	tests := []struct {
+		name      string
+		transport string
+		style     string
+		want      string
+	}{
+		{"stdio default", config.TransportStdio, "", basic("test-token", "")},
+		{"basic password", config.TransportStdio, config.AuthStyleBasicPassword, basic("", "test-token")},
+		{"bearer", config.TransportStdio, config.AuthStyleBearer, "Bearer test-token"},
+		{"http default", config.TransportHTTP, "", "Bearer caller-token"},
+		{"http basic password", config.TransportHTTP, config.AuthStyleBasicPassword, basic("", "caller-token")},
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{APIURL: api.URL, AuthToken: "test-token", TransportMode: tt.transport, AuthStyle: tt.style}
+			ctx := WithAuthToken(context.Background(), "caller-token")
+			if _, err := NewClientFactory(cfg, logger)(ctx).Streams.List(ctx, 1); err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			if got.Load() != tt.want {
+				t.Errorf("Authorization = %q, want %q", got.Load(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,344 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	defaultTrendWindow      = 24 * time.Hour
+	maxTrendWindow          = 30 * 24 * time.Hour
+	defaultTrendSpikeFactor = 3.0
+	// maxTrendBuckets keeps the series small enough to reason about;
+	// smaller buckets over a long window are rejected.
+	maxTrendBuckets = 500
+	// maxTrendNotices bounds how many notices a fault trend counts (80
+	// pages). Beyond it the oldest buckets undercount, with a warning.
+	maxTrendNotices = 2000
+	// minSpikeCount keeps a handful of occurrences against a near-zero
+	// baseline from reading as a spike.
+	minSpikeCount = 5
+	// trendChangeThreshold is the change between the window's halves
+	// reported as increasing or decreasing rather than steady.
+	trendChangeThreshold = 0.5
+)
+
+// occurrenceTrend is the result of analyze_fault_trend: a verdict plus the
+// numbers behind it. Counts are per bucket.
+type occurrenceTrend struct {
+	Scope        string       `json:"scope"` // "fault" or "project"
+	Status       string       `json:"status"`
+	Verdict      string       `json:"verdict"`
+	WindowStart  time.Time    `json:"window_start"`
+	WindowEnd    time.Time    `json:"window_end"`
+	Bucket       string       `json:"bucket"`
+	Total        int64        `json:"total"`
+	FirstSeen    *time.Time   `json:"first_seen,omitempty"`
+	LastSeen     *time.Time   `json:"last_seen,omitempty"`
+	Baseline     float64      `json:"baseline"`
+	Peak         *trendPoint  `json:"peak,omitempty"`
+	RateOfChange *float64     `json:"rate_of_change,omitempty"`
+	Spikes       []trendSpike `json:"spikes"`
+
+	Series []hbapi.ProjectOccurrenceCount `json:"series,omitempty"`
+}
+
+type trendPoint struct {
+	At    time.Time `json:"at"`
+	Count int64     `json:"count"`
+}
+
+// trendSpike is a run of consecutive buckets above the spike threshold.
+// End is the end of its last bucket.
+type trendSpike struct {
+	Start time.Time  `json:"start"`
+	End   time.Time  `json:"end"`
+	Peak  trendPoint `json:"peak"`
+}
+
+// trendBucket picks a bucket size giving a day's window hourly points and
+// longer windows a few dozen points.
+func trendBucket(window time.Duration) time.Duration {
+	switch {
+	case window <= 2*24*time.Hour:
+		return time.Hour
+	case window <= 14*24*time.Hour:
+		return 6 * time.Hour
+	default:
+		return 24 * time.Hour
+	}
+}
+
+// trendSeries returns zero-filled counts for every bucket from start
+// (aligned down to the bucket size, in UTC) through end.
+func trendSeries(start, end time.Time, bucket time.Duration) []hbapi.ProjectOccurrenceCount {
+	size := int64(bucket / time.Second)
+	first := start.Unix() - ((start.Unix()%size)+size)%size
+	var series []hbapi.ProjectOccurrenceCount
+	for ts := first; ts <= end.Unix(); ts += size {
+		series = append(series, hbapi.ProjectOccurrenceCount{ts, 0})
+	}
+	return series
+}
+
+// addToSeries adds count at t to the bucket containing it, ignoring times
+// outside the series.
+func addToSeries(series []hbapi.ProjectOccurrenceCount, bucket time.Duration, t time.Time, count int64) {
+	if len(series) == 0 {
+		return
+	}
+	i := (t.Unix() - series[0][0]) / int64(bucket/time.Second)
+	if i >= 0 && i < int64(len(series)) {
+		series[i][1] += count
+	}
+}
+
+// analyzeTrend fills in everything derived from the series. A fault's
+// first and last seen come from the fault itself; a project's from the
+// series, so they are bounded by the window.
+func analyzeTrend(trend *occurrenceTrend, series []hbapi.ProjectOccurrenceCount, bucket time.Duration, factor float64, now time.Time) {
+	at := func(ts int64) time.Time { return time.Unix(ts, 0).UTC() }
+
+	values := make([]int64, len(series))
+	for i, c := range series {
+		values[i] = c[1]
+		trend.Total += c[1]
+		if c[1] > 0 {
+			if trend.Peak == nil || c[1] > trend.Peak.Count {
+				trend.Peak = &trendPoint{At: at(c[0]), Count: c[1]}
+			}
+			if trend.Scope == "project" {
+				if trend.FirstSeen == nil {
+					t := at(c[0])
+					trend.FirstSeen = &t
+				}
+				t := at(c[0]).Add(bucket)
+				if t.After(now) {
+					t = now
+				}
+				trend.LastSeen = &t
+			}
+		}
+	}
+
+	// The median resists the spikes being looked for; sparse series have
+	// a zero median, so fall back to the mean.
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	if len(sorted) > 0 {
+		trend.Baseline = float64(sorted[len(sorted)/2])
+	}
+	if trend.Baseline == 0 && len(values) > 0 {
+		trend.Baseline = float64(trend.Total) / float64(len(values))
+	}
+	threshold := max(factor*max(trend.Baseline, 1), minSpikeCount)
+
+	trend.Spikes = []trendSpike{}
+	for i := 0; i < len(series); i++ {
+		if float64(series[i][1]) < threshold {
+			continue
+		}
+		spike := trendSpike{Start: at(series[i][0]), Peak: trendPoint{At: at(series[i][0]), Count: series[i][1]}}
+		for ; i < len(series) && float64(series[i][1]) >= threshold; i++ {
+			if series[i][1] > spike.Peak.Count {
+				spike.Peak = trendPoint{At: at(series[i][0]), Count: series[i][1]}
+			}
+			spike.End = at(series[i][0]).Add(bucket)
+		}
+		trend.Spikes = append(trend.Spikes, spike)
+	}
+
+	half := len(values) / 2
+	var older, newer int64
+	for i, v := range values {
+		if i < len(values)-half {
+			older += v
+		} else {
+			newer += v
+		}
+	}
+	if older > 0 {
+		change := float64(newer-older) / float64(older)
+		trend.RateOfChange = &change
+	}
+
+	perBucket := "/" + formatSpan(bucket)
+	clock := func(t time.Time) string { return trendClock(t, now) }
+	ongoing := len(trend.Spikes) > 0 && !trend.Spikes[len(trend.Spikes)-1].End.Before(now.Truncate(bucket))
+	switch {
+	case trend.Total == 0:
+		trend.Status = "quiet"
+		trend.Verdict = fmt.Sprintf("no occurrences since %s", clock(trend.WindowStart))
+	case trend.Scope == "fault" && trend.FirstSeen != nil && !trend.FirstSeen.Before(trend.WindowStart):
+		trend.Status = "new"
+		trend.Verdict = fmt.Sprintf("new: first seen %s, %d occurrences since", clock(*trend.FirstSeen), trend.Total)
+	case ongoing:
+		spike := trend.Spikes[len(trend.Spikes)-1]
+		trend.Status = "spiking"
+		trend.Verdict = fmt.Sprintf("spiking since %s: peak %d%s vs baseline %s%s", clock(spike.Start), spike.Peak.Count, perBucket, formatRate(trend.Baseline), perBucket)
+	case trend.LastSeen != nil && now.Sub(*trend.LastSeen) > (now.Sub(trend.WindowStart))/4:
+		trend.Status = "stopped"
+		trend.Verdict = fmt.Sprintf("stopped: last seen %s", clock(*trend.LastSeen))
+	case len(trend.Spikes) > 0:
+		spike := trend.Spikes[len(trend.Spikes)-1]
+		trend.Status = "recovered"
+		trend.Verdict = fmt.Sprintf("spiked %s to %s (peak %d%s), now back near baseline %s%s", clock(spike.Start), clock(spike.End), spike.Peak.Count, perBucket, formatRate(trend.Baseline), perBucket)
+	case trend.RateOfChange != nil && *trend.RateOfChange >= trendChangeThreshold:
+		trend.Status = "increasing"
+		trend.Verdict = fmt.Sprintf("increasing: %d occurrences in the second half of the window vs %d in the first (+%.0f%%)", newer, older, *trend.RateOfChange*100)
+	case trend.RateOfChange != nil && *trend.RateOfChange <= -trendChangeThreshold:
+		trend.Status = "decreasing"
+		trend.Verdict = fmt.Sprintf("decreasing: %d occurrences in the second half of the window vs %d in the first (%.0f%%)", newer, older, *trend.RateOfChange*100)
+	default:
+		trend.Status = "steady"
+		trend.Verdict = fmt.Sprintf("steady: about %s%s, %d occurrences in the window", formatRate(trend.Baseline), perBucket, trend.Total)
+	}
+}
+
+// trendClock formats t compactly: time of day for today, with the date
+// otherwise.
+func trendClock(t, now time.Time) string {
+	t, now = t.UTC(), now.UTC()
+	if t.YearDay() == now.YearDay() && t.Year() == now.Year() {
+		return t.Format("15:04 UTC")
+	}
+	return t.Format("Jan 2 15:04 UTC")
+}
+
+func formatRate(v float64) string {
+	if v == float64(int64(v)) {
+		return fmt.Sprintf("%d", int64(v))
+	}
+	return fmt.Sprintf("%.1f", v)
+}
+
+// formatSpan renders a bucket size the way parseSpan reads it.
+func formatSpan(d time.Duration) string {
+	switch {
+	case d%(7*24*time.Hour) == 0:
+		return fmt.Sprintf("%dw", d/(7*24*time.Hour))
+	case d%(24*time.Hour) == 0:
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	default:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	}
+}
+
+func handleAnalyzeFaultTrend(ctx context.Context, client *hbapi.Client, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return analyzeFaultTrend(ctx, client, req, time.Now())
+}
+
+func analyzeFaultTrend(ctx context.Context, client *hbapi.Client, req mcp.CallToolRequest, now time.Time) (*mcp.CallToolResult, error) {
+	projectID := req.GetInt("project_id", 0)
+	if projectID == 0 {
+		return mcp.NewToolResultError("project_id is required"), nil
+	}
+	faultID := req.GetInt("fault_id", 0)
+
+	window := defaultTrendWindow
+	if raw := req.GetString("window", ""); raw != "" {
+		var ok bool
+		window, ok = parseSpan(raw)
+		if !ok || window < time.Hour || window > maxTrendWindow {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid window %q: use a span like '6h', '24h', or '7d' (1h to 30d)", raw)), nil
+		}
+	}
+	bucket := trendBucket(window)
+	if raw := req.GetString("bucket", ""); raw != "" {
+		var ok bool
+		bucket, ok = parseSpan(raw)
+		if !ok || bucket < time.Minute {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid bucket %q: use a size like '15m', '1h', or '1d' (minimum 1m)", raw)), nil
+		}
+	}
+	if window/bucket > maxTrendBuckets {
+		return mcp.NewToolResultError(fmt.Sprintf("bucket %s is too small for window %s: at most %d buckets", formatSpan(bucket), formatSpan(window), maxTrendBuckets)), nil
+	}
+	factor := req.GetFloat("spike_factor", defaultTrendSpikeFactor)
+	if factor <= 1 {
+		return mcp.NewToolResultError("spike_factor must be greater than 1"), nil
+	}
+
+	var notes toolNotes
+	now = now.UTC()
+	trend := occurrenceTrend{
+		WindowStart: now.Add(-window),
+		WindowEnd:   now,
+		Bucket:      formatSpan(bucket),
+	}
+	series := trendSeries(trend.WindowStart, now, bucket)
+
+	if faultID > 0 {
+		trend.Scope = "fault"
+		if req.GetString("environment", "") != "" {
+			notes.warnf("environment ignored: it only applies to project trends")
+		}
+		fault, err := client.Faults.Get(ctx, projectID, faultID)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to get fault: %v", err)), nil
+		}
+		firstSeen := fault.CreatedAt.UTC()
+		trend.FirstSeen = &firstSeen
+		if fault.LastNoticeAt != nil {
+			lastSeen := fault.LastNoticeAt.UTC()
+			trend.LastSeen = &lastSeen
+		}
+
+		var counted int
+		var oldest time.Time
+		exhausted, err := walkFaultNotices(ctx, client, projectID, faultID, timeWindow{After: trend.WindowStart}, maxTrendNotices, func(n hbapi.Notice) error {
+			addToSeries(series, bucket, n.CreatedAt, 1)
+			counted++
+			oldest = n.CreatedAt
+			return nil
+		})
+		if err != nil {
+			if counted == 0 {
+				return mcp.NewToolResultError(fmt.Sprintf("Failed to list fault notices: %v", err)), nil
+			}
+			notes.warnf("stopped after %d notices: %v; counts before %s are incomplete", counted, err, oldest.UTC().Format(time.RFC3339))
+		} else if !exhausted {
+			notes.warnf("counted the newest %d notices; counts before %s are incomplete. Narrow the window for an exact trend", counted, oldest.UTC().Format(time.RFC3339))
+		}
+	} else {
+		trend.Scope = "project"
+		period := "hour"
+		if bucket%(24*time.Hour) == 0 {
+			period = "day"
+		}
+		counts, err := client.Projects.GetOccurrenceCounts(ctx, projectID, hbapi.ProjectGetOccurrenceCountsOptions{
+			Period:      period,
+			Environment: req.GetString("environment", ""),
+		})
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to get occurrence counts: %v", err)), nil
+		}
+		for _, c := range counts {
+			addToSeries(series, bucket, time.Unix(c[0], 0), c[1])
+		}
+		if len(counts) > 0 && time.Unix(counts[0][0], 0).After(trend.WindowStart.Add(bucket)) {
+			notes.warnf("occurrence counts only cover since %s; earlier buckets are zero", time.Unix(counts[0][0], 0).UTC().Format(time.RFC3339))
+		}
+	}
+
+	analyzeTrend(&trend, series, bucket, factor, now)
+	if req.GetBool("include_series", false) {
+		trend.Series = series
+	}
+
+	// Return JSON response
+	jsonBytes, err := json.Marshal(trend)
+	if err != nil {
+		return mcp.NewToolResultError("Failed to marshal response"), nil
+	}
+
+	return withNotes(mcp.NewToolResultText(string(jsonBytes)), &notes), nil
+}
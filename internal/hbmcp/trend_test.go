@@ -0,0 +1,154 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// hourlySeries builds a series of hourly buckets ending at the bucket
+// containing now.
+func hourlySeries(now time.Time, counts ...int64) []hbapi.ProjectOccurrenceCount {
+	end := now.Truncate(time.Hour)
+	series := make([]hbapi.ProjectOccurrenceCount, len(counts))
+	for i, c := range counts {
+		series[i] = hbapi.ProjectOccurrenceCount{end.Add(-time.Duration(len(counts)-1-i) * time.Hour).Unix(), c}
+	}
+	return series
+}
+
+func TestAnalyzeTrend(t *testing.T) {
+	now := time.Date(2024, 1, 2, 15, 30, 0, 0, time.UTC)
+	longAgo := now.Add(-30 * 24 * time.Hour)
+
+	cases := []struct {
+		name        string
+		scope       string
+		firstSeen   *time.Time
+		counts      []int64
+		wantStatus  string
+		wantVerdict string
+	}{
+		{"quiet", "project", nil, []int64{0, 0, 0, 0}, "quiet", "no occurrences since"},
+		{"new fault", "fault", &now, []int64{0, 0, 0, 3}, "new", "new: first seen 15:30 UTC, 3 occurrences since"},
+		{"spiking", "fault", &longAgo, []int64{2, 3, 2, 2, 3, 2, 20, 40}, "spiking", "spiking since 14:00 UTC: peak 40/1h vs baseline 3/1h"},
+		{"recovered", "project", nil, []int64{2, 3, 30, 2, 3, 2, 2, 3}, "recovered", "spiked 10:00 UTC to 11:00 UTC (peak 30/1h)"},
+		{"stopped", "project", nil, []int64{4, 4, 4, 4, 0, 0, 0, 0}, "stopped", "stopped: last seen 12:00 UTC"},
+		{"increasing", "project", nil, []int64{2, 2, 2, 2, 4, 4, 4, 4}, "increasing", "+100%"},
+		{"decreasing", "project", nil, []int64{4, 4, 4, 4, 2, 2, 1, 1}, "decreasing", "6 occurrences in the second half of the window vs 16 in the first (-62%)"},
+		{"steady", "project", nil, []int64{3, 4, 3, 4, 3, 4, 3, 4}, "steady", "steady: about 4/1h"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			series := hourlySeries(now, c.counts...)
+			trend := &occurrenceTrend{
+				Scope:       c.scope,
+				FirstSeen:   c.firstSeen,
+				WindowStart: time.Unix(series[0][0], 0).UTC(),
+				WindowEnd:   now,
+			}
+			analyzeTrend(trend, series, time.Hour, defaultTrendSpikeFactor, now)
+			if trend.Status != c.wantStatus {
+				t.Errorf("expected status %q, got %q (%s)", c.wantStatus, trend.Status, trend.Verdict)
+			}
+			if !strings.Contains(trend.Verdict, c.wantVerdict) {
+				t.Errorf("expected verdict containing %q, got %q", c.wantVerdict, trend.Verdict)
+			}
+		})
+	}
+}
+
+func TestAnalyzeFaultTrend_Fault(t *testing.T) {
+	now := time.Date(2024, 1, 2, 15, 30, 0, 0, time.UTC)
+	// Two notices an hour for a day, then 30 in the current hour.
+	var notices []hbapi.Notice
+	for i := 0; i < 30; i++ {
+		notices = append(notices, hbapi.Notice{ID: fmt.Sprintf("s%d", i), CreatedAt: now.Add(-time.Duration(i+1) * time.Minute)})
+	}
+	for h := 2; h < 24; h++ {
+		for j := 0; j < 2; j++ {
+			notices = append(notices, hbapi.Notice{ID: fmt.Sprintf("b%d-%d", h, j), CreatedAt: now.Add(-time.Duration(h)*time.Hour - time.Duration(j)*time.Minute)})
+		}
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if !strings.HasSuffix(r.URL.Path, "/notices") {
+			_ = json.NewEncoder(w).Encode(hbapi.Fault{ID: 2, CreatedAt: now.Add(-90 * 24 * time.Hour)})
+			return
+		}
+		var before time.Time
+		if raw := r.URL.Query().Get("created_before"); raw != "" {
+			var secs int64
+			_, _ = fmt.Sscan(raw, &secs)
+			before = time.Unix(secs, 0)
+		}
+		var page hbapi.FaultNoticesResponse
+		for _, n := range notices {
+			if len(page.Results) == maxPageLimit {
+				page.Links.Next = "/next"
+				break
+			}
+			if before.IsZero() || n.CreatedAt.Before(before) {
+				page.Results = append(page.Results, n)
+			}
+		}
+		_ = json.NewEncoder(w).Encode(page)
+	}))
+	defer server.Close()
+
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+	req := mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: map[string]interface{}{
+		"project_id": 1,
+		"fault_id":   2,
+	}}}
+	result, err := analyzeFaultTrend(context.Background(), client, req, now)
+	if err != nil {
+		t.Fatalf("analyzeFaultTrend() error = %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("expected success, got %s", getResultText(result))
+	}
+
+	var trend occurrenceTrend
+	if err := json.Unmarshal([]byte(getResultText(result)), &trend); err != nil {
+		t.Fatalf("failed to unmarshal result: %v", err)
+	}
+	if trend.Scope != "fault" || trend.Total != int64(len(notices)) {
+		t.Errorf("expected %d fault occurrences, got %+v", len(notices), trend)
+	}
+	if trend.Status != "spiking" || !strings.Contains(trend.Verdict, "spiking since 15:00 UTC") {
+		t.Errorf("expected a spike since 15:00 UTC, got %q", trend.Verdict)
+	}
+	if trend.Series != nil {
+		t.Error("expected no series without include_series")
+	}
+}
+
+func TestAnalyzeFaultTrend_InvalidArguments(t *testing.T) {
+	cases := []struct {
+		args    map[string]interface{}
+		wantErr string
+	}{
+		{map[string]interface{}{"window": "forever"}, "invalid window"},
+		{map[string]interface{}{"window": "60d"}, "invalid window"},
+		{map[string]interface{}{"window": "7d", "bucket": "1m"}, "too small"},
+		{map[string]interface{}{"spike_factor": 1}, "spike_factor"},
+	}
+	for _, c := range cases {
+		c.args["project_id"] = 1
+		req := mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: c.args}}
+		result, _ := analyzeFaultTrend(context.Background(), hbapi.NewClient(), req, time.Now())
+		if !result.IsError || !strings.Contains(getResultText(result), c.wantErr) {
+			t.Errorf("%v: expected error containing %q, got %s", c.args, c.wantErr, getResultText(result))
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package hbmcp
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	defaultUserImpactWindow = 7 * 24 * time.Hour
+	// defaultUserSearchField is what the user is searched by in projects
+	// without a user search field setting.
+	defaultUserSearchField = "context.user_email"
+)
+
+// userImpact is the result of search_user_impact.
+type userImpact struct {
+	User   string            `json:"user"`
+	Start  time.Time         `json:"start"`
+	Stop   time.Time         `json:"stop"`
+	Faults []userImpactFault `json:"faults"`
+}
+
+// userImpactFault is a fault the user hit. UserNotices is how many of the
+// fault's notices the affected users list attributes to the user; it's
+// omitted when the fault matched the search but the list doesn't name
+// the user.
+type userImpactFault struct {
+	ProjectID    int        `json:"project_id"`
+	ProjectName  string     `json:"project_name"`
+	FaultID      int        `json:"fault_id"`
+	Klass        string     `json:"klass"`
+	Message      string     `json:"message"`
+	Environment  string     `json:"environment"`
+	Resolved     bool       `json:"resolved"`
+	LastNoticeAt *time.Time `json:"last_notice_at"`
+	UserNotices  *int       `json:"user_notices,omitempty"`
+	URL          string     `json:"url"`
+}
+
+// userSearchProject is a project to search along with its user search
+// field setting, which hbapi's Project type leaves out.
+type userSearchProject struct {
+	ID              int    `json:"id"`
+	Name            string `json:"name"`
+	UserSearchField string `json:"user_search_field"`
+}
+
+func handleSearchUserImpact(ctx context.Context, client *hbapi.Client, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	user := strings.TrimSpace(req.GetString("user", ""))
+	if user == "" {
+		return mcp.NewToolResultError("user is required"), nil
+	}
+
+	now := time.Now()
+	window, err := resolveTimeWindow(req, "occurred_after", "occurred_before", now, time.UTC)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	var notes toolNotes
+	notes.addResolved(window.Resolved)
+	if window.Before.IsZero() {
+		window.Before = now
+	}
+	if window.After.IsZero() {
+		window.After = window.Before.Add(-defaultUserImpactWindow)
+	}
+
+	projects, errResult := userSearchProjects(ctx, client, req.GetInt("project_id", 0), req.GetString("account_id", ""), &notes)
+	if errResult != nil {
+		return errResult, nil
+	}
+
+	result := userImpact{User: user, Start: window.After, Stop: window.Before, Faults: []userImpactFault{}}
+	field := req.GetString("search_field", "")
+	err = eachProject(projects, func(p userSearchProject) (int, string) { return p.ID, p.Name }, &notes, func(p userSearchProject) error {
+		f := cmp.Or(field, p.UserSearchField, defaultUserSearchField)
+		faults, err := client.Faults.List(ctx, p.ID, hbapi.FaultListOptions{
+			Q:              fmt.Sprintf("%s:%q", f, user),
+			OccurredAfter:  window.After,
+			OccurredBefore: window.Before,
+			Order:          "recent",
+			Limit:          maxPageLimit,
+		})
+		if err != nil {
+			return err
+		}
+		if faults.Links.Next != "" {
+			notes.warnf("project %d (%s) has more than %d matching faults; only the most recent are listed", p.ID, p.Name, len(faults.Results))
+		}
+		result.Faults = append(result.Faults, userImpactFaults(ctx, client, p, user, faults.Results, &notes)...)
+		return nil
+	})
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to search faults: %v", err)), nil
+	}
+	slices.SortStableFunc(result.Faults, func(a, b userImpactFault) int {
+		var an, bn int
+		if a.UserNotices != nil {
+			an = *a.UserNotices
+		}
+		if b.UserNotices != nil {
+			bn = *b.UserNotices
+		}
+		return cmp.Compare(bn, an)
+	})
+
+	// Return JSON response
+	jsonBytes, err := json.Marshal(result)
+	if err != nil {
+		return mcp.NewToolResultError("Failed to marshal response"), nil
+	}
+
+	return withNotes(mcp.NewToolResultText(string(jsonBytes)), &notes), nil
+}
+
+// userSearchProjects returns the project to search, or with no projectID
+// every project in scope, optionally narrowed to one account. The error
+// result is non-nil when they can't be fetched.
+func userSearchProjects(ctx context.Context, client *hbapi.Client, projectID int, accountID string, notes *toolNotes) ([]userSearchProject, *mcp.CallToolResult) {
+	raw := &rawBody{}
+	rctx := context.WithValue(ctx, rawBodyKey{}, raw)
+	if projectID != 0 {
+		project, err := client.Projects.Get(rctx, projectID)
+		if err != nil {
+			return nil, mcp.NewToolResultError(fmt.Sprintf("Failed to get project: %v", err))
+		}
+		p := userSearchProject{ID: project.ID, Name: project.Name}
+		if body, ok := raw.load(); ok {
+			_ = json.Unmarshal(body, &p)
+		}
+		return []userSearchProject{p}, nil
+	}
+
+	var response *hbapi.ProjectsResponse
+	var err error
+	if accountID != "" {
+		response, err = client.Projects.ListByAccountID(rctx, accountID)
+	} else {
+		response, err = client.Projects.ListAll(rctx)
+	}
+	if err != nil {
+		return nil, mcp.NewToolResultError(fmt.Sprintf("Failed to list projects: %v", err))
+	}
+	if response.Links.Next != "" {
+		notes.warnf("only the first %d projects were searched; pass account_id to narrow the list", len(response.Results))
+	}
+	var listed struct {
+		Results []userSearchProject `json:"results"`
+	}
+	if body, ok := raw.load(); ok {
+		_ = json.Unmarshal(body, &listed)
+	}
+	fields := map[int]string{}
+	for _, p := range listed.Results {
+		fields[p.ID] = p.UserSearchField
+	}
+	var projects []userSearchProject
+	for _, p := range response.Results {
+		if projectAllowed(ctx, p.ID) {
+			projects = append(projects, userSearchProject{ID: p.ID, Name: p.Name, UserSearchField: fields[p.ID]})
+		}
+	}
+	return projects, nil
+}
+
+// userImpactFaults looks up how many of each fault's notices are the
+// user's, at most enrichConcurrency faults at a time.
+func userImpactFaults(ctx context.Context, client *hbapi.Client, project userSearchProject, user string, faults []hbapi.Fault, notes *toolNotes) []userImpactFault {
+	results := make([]userImpactFault, len(faults))
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var failed int
+	sem := make(chan struct{}, enrichConcurrency)
+	for i, f := range faults {
+		results[i] = userImpactFault{
+			ProjectID:    project.ID,
+			ProjectName:  project.Name,
+			FaultID:      f.ID,
+			Klass:        f.Klass,
+			Message:      f.Message,
+			Environment:  f.Environment,
+			Resolved:     f.Resolved,
+			LastNoticeAt: f.LastNoticeAt,
+			URL:          f.URL,
+		}
+		wg.Go(func() {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			users, err := client.Faults.ListAffectedUsers(ctx, project.ID, f.ID, hbapi.FaultListAffectedUsersOptions{Q: user})
+			if err != nil {
+				mu.Lock()
+				failed++
+				mu.Unlock()
+				return
+			}
+			for _, u := range users {
+				if strings.EqualFold(u.User, user) {
+					count := u.Count
+					results[i].UserNotices = &count
+					return
+				}
+			}
+		})
+	}
+	wg.Wait()
+	if failed > 0 {
+		notes.warnf("user_notices left out for %d faults in project %d (%s): the affected users lookup failed", failed, project.ID, project.Name)
+	}
+	return results
+}
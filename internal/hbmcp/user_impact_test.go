@@ -0,0 +1,118 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestHandleSearchUserImpact(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v2/projects":
+			_, _ = w.Write([]byte(`{"results": [
+				{"id": 1, "name": "Shop", "user_search_field": "context.email"},
+				{"id": 2, "name": "Admin"}
+			], "links": {}}`))
+		case "/v2/projects/1/faults":
+			if q := r.URL.Query().Get("q"); q != `context.email:"ann@example.com"` {
+				t.Errorf("expected the project's user search field, got %q", q)
+			}
+			_, _ = w.Write([]byte(`{"results": [{"id": 10, "klass": "RuntimeError"}, {"id": 11, "klass": "KeyError"}], "links": {}}`))
+		case "/v2/projects/2/faults":
+			if q := r.URL.Query().Get("q"); q != `context.user_email:"ann@example.com"` {
+				t.Errorf("expected the default user search field, got %q", q)
+			}
+			_, _ = w.Write([]byte(`{"results": [{"id": 20, "klass": "ArgumentError"}], "links": {}}`))
+		case "/v2/projects/1/faults/10/affected_users":
+			_, _ = w.Write([]byte(`[{"user": "ann@example.com.au", "count": 9}, {"user": "Ann@example.com", "count": 2}]`))
+		case "/v2/projects/1/faults/11/affected_users":
+			_, _ = w.Write([]byte(`[]`))
+		case "/v2/projects/2/faults/20/affected_users":
+			_, _ = w.Write([]byte(`[{"user": "ann@example.com", "count": 5}]`))
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := hbapi.NewClient().
+		WithBaseURL(server.URL).
+		WithAuthToken("test-token").
+		WithHTTPClient(&http.Client{Transport: &rawBodyTransport{base: http.DefaultTransport}})
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"user": "ann@example.com"}
+
+	result, err := handleSearchUserImpact(context.Background(), client, req)
+	if err != nil || result.IsError {
+		t.Fatalf("unexpected error: %v %s", err, getResultText(result))
+	}
+	var impact userImpact
+	if err := json.Unmarshal([]byte(getResultText(result)), &impact); err != nil {
+		t.Fatalf("failed to parse response: %v", err)
+	}
+	if len(impact.Faults) != 3 {
+		t.Fatalf("expected 3 faults, got %+v", impact.Faults)
+	}
+	var got []string
+	for _, f := range impact.Faults {
+		count := "-"
+		if f.UserNotices != nil {
+			count = strconv.Itoa(*f.UserNotices)
+		}
+		got = append(got, f.Klass+":"+count)
+	}
+	if want := "ArgumentError:5,RuntimeError:2,KeyError:-"; strings.Join(got, ",") != want {
+		t.Errorf("expected faults by the user's notices, got %s want %s", strings.Join(got, ","), want)
+	}
+}
+
+func TestHandleSearchUserImpact_SearchField(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v2/projects/1":
+			_, _ = w.Write([]byte(`{"id": 1, "name": "Shop", "user_search_field": "context.email"}`))
+		case "/v2/projects/1/faults":
+			if q := r.URL.Query().Get("q"); q != `context.user_id:"42"` {
+				t.Errorf("expected search_field to win, got %q", q)
+			}
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors": "forbidden"}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := hbapi.NewClient().
+		WithBaseURL(server.URL).
+		WithAuthToken("test-token").
+		WithHTTPClient(&http.Client{Transport: &rawBodyTransport{base: http.DefaultTransport}})
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"user": "42", "project_id": float64(1), "search_field": "context.user_id"}
+
+	result, _ := handleSearchUserImpact(context.Background(), client, req)
+	if !result.IsError || !strings.Contains(getResultText(result), "Failed to search faults") {
+		t.Errorf("expected a search error, got %q", getResultText(result))
+	}
+}
+
+func TestHandleSearchUserImpact_MissingUser(t *testing.T) {
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]any{"user": " "}
+	result, _ := handleSearchUserImpact(context.Background(), hbapi.NewClient(), req)
+	if !result.IsError || !strings.Contains(getResultText(result), "user is required") {
+		t.Errorf("expected user is required, got %q", getResultText(result))
+	}
+}
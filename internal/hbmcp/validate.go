@@ -0,0 +1,100 @@
+package hbmcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+var validationPrinter = message.NewPrinter(language.English)
+
+// validateArguments checks a call's arguments against the tool's input
+// schema before the handler runs, so a call with a missing, mistyped, or
+// out-of-range argument fails with an error naming it instead of reaching
+// the API. It runs after coerceArguments, so numbers and booleans sent as
+// strings have already been converted. Arguments the schema doesn't list
+// are left to the handler. A schema that
+// doesn't compile leaves the tool unchecked rather than unusable.
+func validateArguments(tool mcp.Tool, next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	schema, err := compileInputSchema(tool)
+	if err != nil {
+		return next
+	}
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, err := validationArgs(req.GetArguments())
+		if err == nil {
+			err = schema.Validate(args)
+		}
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %s", validationMessage(err))), nil
+		}
+		return next(ctx, req)
+	}
+}
+
+func compileInputSchema(tool mcp.Tool) (*jsonschema.Schema, error) {
+	raw := tool.RawInputSchema
+	if len(raw) == 0 {
+		var err error
+		if raw, err = json.Marshal(tool.InputSchema); err != nil {
+			return nil, err
+		}
+	}
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(raw))
+	if err != nil {
+		return nil, err
+	}
+	url := "mem:///tools/" + tool.Name + ".json"
+	c := jsonschema.NewCompiler()
+	if err := c.AddResource(url, doc); err != nil {
+		return nil, err
+	}
+	return c.Compile(url)
+}
+
+// validationArgs returns the arguments in the form the validator expects.
+func validationArgs(args map[string]any) (any, error) {
+	if args == nil {
+		args = map[string]any{}
+	}
+	raw, err := json.Marshal(args)
+	if err != nil {
+		return nil, err
+	}
+	return jsonschema.UnmarshalJSON(bytes.NewReader(raw))
+}
+
+// validationMessage flattens a validation error into one line naming each
+// failing argument, e.g. "project_id: minimum: got 0, want 1".
+func validationMessage(err error) string {
+	var verr *jsonschema.ValidationError
+	if !errors.As(err, &verr) {
+		return err.Error()
+	}
+	var parts []string
+	var walk func(*jsonschema.ValidationError)
+	walk = func(e *jsonschema.ValidationError) {
+		if len(e.Causes) > 0 {
+			for _, c := range e.Causes {
+				walk(c)
+			}
+			return
+		}
+		msg := e.ErrorKind.LocalizedString(validationPrinter)
+		if len(e.InstanceLocation) > 0 {
+			msg = strings.Join(e.InstanceLocation, ".") + ": " + msg
+		}
+		parts = append(parts, msg)
+	}
+	walk(verr)
+	return strings.Join(parts, "; ")
+}
@@ -0,0 +1,86 @@
+package hbmcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/honeybadger-io/honeybadger-mcp-server/internal/config"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestValidateArguments(t *testing.T) {
+	s := server.NewMCPServer("test", "1.0.0")
+	r := newToolRegistrar(s)
+	calls := 0
+	r.AddTool(mcp.NewTool("get_thing",
+		mcp.WithTitleAnnotation("Get Thing"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithNumber("project_id", mcp.Required(), mcp.Min(1)),
+		mcp.WithString("period", mcp.Enum("hour", "day")),
+		mcp.WithBoolean("latest"),
+	), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		calls++
+		return mcp.NewToolResultText(`{"ok": true}`), nil
+	})
+	handler := s.GetTool("get_thing").Handler
+
+	tests := []struct {
+		name    string
+		args    map[string]any
+		wantErr string
+	}{
+		{"valid", map[string]any{"project_id": 1, "period": "day", "latest": true}, ""},
+		{"numeric and boolean strings", map[string]any{"project_id": "12", "latest": "true"}, ""},
+		{"unknown arguments pass", map[string]any{"project_id": 1, "extra": "x"}, ""},
+		{"missing required", map[string]any{}, "missing property 'project_id'"},
+		{"below minimum", map[string]any{"project_id": 0}, "project_id: minimum: got 0, want 1"},
+		{"not in enum", map[string]any{"project_id": 1, "period": "year"}, "period: value must be one of"},
+		{"wrong type", map[string]any{"project_id": "abc"}, `project_id must be a number, got string "abc"`},
+		{"timeout argument checked too", map[string]any{"project_id": 1, "timeout_seconds": 0}, "timeout_seconds: minimum"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls = 0
+			req := mcp.CallToolRequest{}
+			req.Params.Arguments = tt.args
+			result, err := handler(context.Background(), req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr == "" {
+				if result.IsError || calls != 1 {
+					t.Errorf("expected the handler to run, got %s", getResultText(result))
+				}
+				return
+			}
+			if !result.IsError || !strings.Contains(getResultText(result), tt.wantErr) || calls != 0 {
+				t.Errorf("expected an error containing %q without running the handler, got %s", tt.wantErr, getResultText(result))
+			}
+		})
+	}
+}
+
+func TestToolRegistrarLayers(t *testing.T) {
+	r := newToolRegistrar(server.NewMCPServer("test", "1.0.0"))
+	r.middleware = append(r.middleware, middlewareLayer("raw_body", rawBodyFallback))
+	var names []string
+	for _, l := range r.layers() {
+		names = append(names, l.name)
+	}
+	if got := strings.Join(names, ","); got != "recover,structured,fields,coerce,validate,raw_body,timeout" {
+		t.Errorf("unexpected layers %s", got)
+	}
+
+	r.current = staticConfig(&config.Config{})
+	r.confirmations = newConfirmations()
+	names = nil
+	for _, l := range r.layers() {
+		names = append(names, l.name)
+	}
+	if got := strings.Join(names, ","); got != "recover,structured,fields,coerce,validate,raw_body,read_only,confirm,timeout" {
+		t.Errorf("unexpected layers %s", got)
+	}
+}
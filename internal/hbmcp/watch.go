@@ -0,0 +1,352 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime/debug"
+	"strconv"
+	"sync"
+	"time"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	defaultWatchInterval = time.Minute
+	minWatchInterval     = 30 * time.Second
+	defaultWatchDuration = time.Hour
+	maxWatchDuration     = 24 * time.Hour
+	maxWatchesPerSession = 5
+
+	// maxWatchPages is how many pages of faults one poll reads, so a burst
+	// of errors doesn't leave a watch paging through the API every poll.
+	maxWatchPages = 4
+
+	// watchLogger names the source of watch notifications. They are sent
+	// as notifications/message so clients that surface server log
+	// messages show them without knowing about this server.
+	watchLogger = "honeybadger.watch_faults"
+)
+
+// faultWatches tracks the watch_faults pollers of every session. Each watch
+// is a goroutine that lives until unwatch_faults, its duration runs out,
+// or its session goes away.
+type faultWatches struct {
+	logger *slog.Logger
+
+	mu        sync.Mutex
+	nextID    int
+	bySession map[string]map[string]*faultWatch
+}
+
+type faultWatch struct {
+	ID        string    `json:"watch_id"`
+	ProjectID int       `json:"project_id"`
+	Query     string    `json:"q,omitempty"`
+	Interval  string    `json:"interval"`
+	ExpiresAt time.Time `json:"expires_at"`
+
+	cancel context.CancelFunc
+}
+
+// watchedFault is one entry of a watch notification.
+type watchedFault struct {
+	Event        string     `json:"event"` // "new" or "reoccurred"
+	ID           int        `json:"id"`
+	Klass        string     `json:"klass"`
+	Message      string     `json:"message"`
+	Component    string     `json:"component,omitempty"`
+	Environment  string     `json:"environment,omitempty"`
+	NoticesCount int        `json:"notices_count"`
+	LastNoticeAt *time.Time `json:"last_notice_at,omitempty"`
+	URL          string     `json:"url,omitempty"`
+}
+
+func newFaultWatches(logger *slog.Logger) *faultWatches {
+	return &faultWatches{logger: logger, bySession: map[string]map[string]*faultWatch{}}
+}
+
+// RegisterWatchTools registers the watch_faults and unwatch_faults tools
+func RegisterWatchTools(r *toolRegistrar, clientFor ClientFactory, watches *faultWatches) {
+	// watch_faults tool
+	r.AddTool(
+		mcp.NewTool("watch_faults",
+			mcp.WithTitleAnnotation("Watch Faults"),
+			mcp.WithDescription("Start polling a project for faults that are new or occur again, and push each batch to this session as a notifications/message log notification (logger \""+watchLogger+"\"). Returns a watch_id for unwatch_faults. Needs a session that stays connected, such as stdio, so it isn't available over stateless http; watches end when the session does."),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(false),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithNumber("project_id",
+				mcp.Required(),
+				mcp.Description("The ID of the project to watch"),
+				mcp.Min(1),
+			),
+			mcp.WithString("q",
+				mcp.Description("Search string to filter faults (see the errors reference topic for the search query syntax), e.g. '-is:resolved environment:production'"),
+			),
+			mcp.WithNumber("interval_seconds",
+				mcp.Description("Seconds between polls (default 60, min 30)"),
+				mcp.Min(30),
+			),
+			mcp.WithNumber("duration_minutes",
+				mcp.Description("Minutes until the watch stops on its own (default 60, max 1440)"),
+				mcp.Min(1),
+				mcp.Max(1440),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return watches.handleWatchFaults(ctx, clientFor(ctx), req)
+		},
+	)
+
+	// unwatch_faults tool
+	r.AddTool(
+		mcp.NewTool("unwatch_faults",
+			mcp.WithTitleAnnotation("Unwatch Faults"),
+			mcp.WithDescription("Stop a watch started by watch_faults, or every watch in this session when watch_id is omitted. Returns the watches that were stopped."),
+			mcp.WithReadOnlyHintAnnotation(true),
+			mcp.WithDestructiveHintAnnotation(false),
+			mcp.WithIdempotentHintAnnotation(true),
+			mcp.WithOpenWorldHintAnnotation(false),
+			mcp.WithString("watch_id",
+				mcp.Description("The watch_id returned by watch_faults"),
+			),
+		),
+		func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return watches.handleUnwatchFaults(ctx, req)
+		},
+	)
+}
+
+func (ws *faultWatches) handleWatchFaults(ctx context.Context, client *hbapi.Client, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	projectID := req.GetInt("project_id", 0)
+	if projectID == 0 {
+		return mcp.NewToolResultError("project_id is required"), nil
+	}
+
+	srv := server.ServerFromContext(ctx)
+	session := server.ClientSessionFromContext(ctx)
+	if srv == nil || session == nil {
+		return mcp.NewToolResultError("watch_faults needs an MCP session to send notifications to"), nil
+	}
+	// Stateless http sessions end with the request, so there would be no
+	// one left to notify.
+	if session.SessionID() == "" {
+		return mcp.NewToolResultError("watch_faults needs a session that stays connected, and this server's http mode is stateless: poll with list_faults instead"), nil
+	}
+
+	interval := time.Duration(req.GetInt("interval_seconds", 0)) * time.Second
+	if interval == 0 {
+		interval = defaultWatchInterval
+	}
+	if interval < minWatchInterval {
+		return mcp.NewToolResultError(fmt.Sprintf("interval_seconds must be at least %d", int(minWatchInterval.Seconds()))), nil
+	}
+	duration := time.Duration(req.GetInt("duration_minutes", 0)) * time.Minute
+	if duration == 0 {
+		duration = defaultWatchDuration
+	}
+	if duration > maxWatchDuration {
+		return mcp.NewToolResultError(fmt.Sprintf("duration_minutes must be at most %d", int(maxWatchDuration.Minutes()))), nil
+	}
+
+	now := time.Now()
+	w := &faultWatch{
+		ProjectID: projectID,
+		Query:     req.GetString("q", ""),
+		Interval:  interval.String(),
+		ExpiresAt: now.Add(duration).UTC(),
+	}
+	// The watch outlives this request, so it gets its own context; the
+	// client already carries the caller's credentials.
+	pollCtx, cancel := context.WithDeadline(context.Background(), now.Add(duration))
+	w.cancel = cancel
+	if err := ws.add(session.SessionID(), w); err != nil {
+		cancel()
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	go ws.run(pollCtx, srv, session.SessionID(), client, w, interval, now)
+
+	// Return JSON response
+	jsonBytes, err := json.Marshal(w)
+	if err != nil {
+		return mcp.NewToolResultError("Failed to marshal response"), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonBytes)), nil
+}
+
+func (ws *faultWatches) handleUnwatchFaults(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	session := server.ClientSessionFromContext(ctx)
+	if session == nil {
+		return mcp.NewToolResultError("unwatch_faults needs an MCP session"), nil
+	}
+
+	watchID := req.GetString("watch_id", "")
+	stopped := ws.stop(session.SessionID(), watchID)
+	if watchID != "" && len(stopped) == 0 {
+		return mcp.NewToolResultError(fmt.Sprintf("No watch %q in this session", watchID)), nil
+	}
+
+	// Return JSON response
+	jsonBytes, err := json.Marshal(map[string]any{"stopped": stopped})
+	if err != nil {
+		return mcp.NewToolResultError("Failed to marshal response"), nil
+	}
+
+	return mcp.NewToolResultText(string(jsonBytes)), nil
+}
+
+func (ws *faultWatches) add(sessionID string, w *faultWatch) error {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	if len(ws.bySession[sessionID]) >= maxWatchesPerSession {
+		return fmt.Errorf("this session already has %d watches; stop one with unwatch_faults first", maxWatchesPerSession)
+	}
+	ws.nextID++
+	w.ID = "watch-" + strconv.Itoa(ws.nextID)
+	if ws.bySession[sessionID] == nil {
+		ws.bySession[sessionID] = map[string]*faultWatch{}
+	}
+	ws.bySession[sessionID][w.ID] = w
+	return nil
+}
+
+// stop cancels the session's watch with watchID, or all of its watches when
+// watchID is empty, and returns the ones it stopped.
+func (ws *faultWatches) stop(sessionID, watchID string) []*faultWatch {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	stopped := []*faultWatch{}
+	for id, w := range ws.bySession[sessionID] {
+		if watchID != "" && id != watchID {
+			continue
+		}
+		if w.cancel != nil {
+			w.cancel()
+		}
+		delete(ws.bySession[sessionID], id)
+		stopped = append(stopped, w)
+	}
+	if len(ws.bySession[sessionID]) == 0 {
+		delete(ws.bySession, sessionID)
+	}
+	return stopped
+}
+
+// stopSession ends every watch of a session that has gone away.
+func (ws *faultWatches) stopSession(sessionID string) {
+	ws.stop(sessionID, "")
+}
+
+func (ws *faultWatches) run(ctx context.Context, srv *server.MCPServer, sessionID string, client *hbapi.Client, w *faultWatch, interval time.Duration, since time.Time) {
+	defer ws.stop(sessionID, w.ID)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				_ = notifyWatch(srv, sessionID, "info", map[string]any{"watch_id": w.ID, "expired": true})
+			}
+			return
+		case <-ticker.C:
+		}
+
+		polledAt := time.Now()
+		faults, complete, err := pollWatchedFaults(ctx, client, w, since)
+		if err != nil {
+			ws.logger.Warn("Fault watch poll failed", "watch_id", w.ID, "project_id", w.ProjectID, "error", err)
+			continue
+		}
+		since = polledAt
+		if len(faults) == 0 {
+			continue
+		}
+		data := map[string]any{
+			"watch_id":   w.ID,
+			"project_id": w.ProjectID,
+			"faults":     faults,
+		}
+		if !complete {
+			data["warning"] = fmt.Sprintf("more faults occurred since the last poll than the %d most recent listed; see list_faults for the rest", len(faults))
+		}
+		err = notifyWatch(srv, sessionID, "warning", data)
+		if errors.Is(err, server.ErrSessionNotFound) {
+			return
+		}
+	}
+}
+
+// pollWatchedFaults lists the faults with notices since the last poll,
+// reading up to maxWatchPages pages, and reports whether it got them all.
+// A fault created since then is new; any other fault has reoccurred. A
+// panic fails the poll rather than crashing the server, since no tool
+// call is there to recover it.
+func pollWatchedFaults(ctx context.Context, client *hbapi.Client, w *faultWatch, since time.Time) (faults []watchedFault, complete bool, err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			faults, complete, err = nil, false, fmt.Errorf("panic: %v\n%s", v, debug.Stack())
+		}
+	}()
+	var results []hbapi.Fault
+	for page := 1; page <= maxWatchPages && !complete; page++ {
+		response, err := client.Faults.List(ctx, w.ProjectID, hbapi.FaultListOptions{
+			Q:             w.Query,
+			OccurredAfter: since,
+			Order:         "recent",
+			Limit:         maxPageLimit,
+			Page:          page,
+		})
+		if err != nil {
+			return nil, false, err
+		}
+		results = append(results, response.Results...)
+		complete = response.Links.Next == ""
+	}
+
+	// A fault that occurs again mid-poll moves to the first page, so one
+	// may turn up twice.
+	seen := map[int]bool{}
+	faults = make([]watchedFault, 0, len(results))
+	for _, f := range results {
+		if seen[f.ID] {
+			continue
+		}
+		seen[f.ID] = true
+		event := "reoccurred"
+		if f.CreatedAt.After(since) {
+			event = "new"
+		}
+		faults = append(faults, watchedFault{
+			Event:        event,
+			ID:           f.ID,
+			Klass:        f.Klass,
+			Message:      truncateLabel(f.Message, 200),
+			Component:    f.Component,
+			Environment:  f.Environment,
+			NoticesCount: f.NoticesCount,
+			LastNoticeAt: f.LastNoticeAt,
+			URL:          f.URL,
+		})
+	}
+	return faults, complete, nil
+}
+
+// notifyWatch sends a notifications/message to one session. It bypasses
+// the session's logging level: the client asked for these by calling
+// watch_faults, and the default level would drop them.
+func notifyWatch(srv *server.MCPServer, sessionID, level string, data map[string]any) error {
+	return srv.SendNotificationToSpecificClient(sessionID, string(mcp.MethodNotificationMessage), map[string]any{
+		"level":  level,
+		"logger": watchLogger,
+		"data":   data,
+	})
+}
@@ -0,0 +1,195 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	hbapi "github.com/honeybadger-io/api-go"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const watchFaultsJSON = `{"results": [
+	{"id": 1, "klass": "NewError", "message": "just started", "created_at": "2024-01-02T00:10:00Z", "notices_count": 1},
+	{"id": 2, "klass": "OldError", "message": "back again", "created_at": "2023-06-01T00:00:00Z", "notices_count": 99}
+], "links": {}}`
+
+func TestPollWatchedFaults(t *testing.T) {
+	since := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if got := query.Get("occurred_after"); got != "1704153600" {
+			t.Errorf("expected occurred_after=1704153600, got %s", got)
+		}
+		if got := query.Get("q"); got != "-is:resolved" {
+			t.Errorf("expected q=-is:resolved, got %s", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(watchFaultsJSON))
+	}))
+	defer server.Close()
+
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+	faults, complete, err := pollWatchedFaults(context.Background(), client, &faultWatch{ProjectID: 42, Query: "-is:resolved"}, since)
+	if err != nil {
+		t.Fatalf("pollWatchedFaults() error = %v", err)
+	}
+	if len(faults) != 2 || !complete {
+		t.Fatalf("expected 2 faults and the poll complete, got %d %v", len(faults), complete)
+	}
+	if faults[0].Event != "new" || faults[1].Event != "reoccurred" {
+		t.Errorf("expected new then reoccurred, got %s, %s", faults[0].Event, faults[1].Event)
+	}
+}
+
+func TestPollWatchedFaults_Pages(t *testing.T) {
+	var pages []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		pages = append(pages, page)
+		w.Header().Set("Content-Type", "application/json")
+		// Every page is full, and fault 1 occurs again between the first
+		// two.
+		id, _ := strconv.Atoi(page)
+		_, _ = fmt.Fprintf(w, `{"results": [{"id": %d}, {"id": 1}], "links": {"next": "/next"}}`, id*10)
+	}))
+	defer server.Close()
+
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+	faults, complete, err := pollWatchedFaults(context.Background(), client, &faultWatch{ProjectID: 42}, time.Now())
+	if err != nil {
+		t.Fatalf("pollWatchedFaults() error = %v", err)
+	}
+	if complete || len(pages) != maxWatchPages {
+		t.Errorf("expected %d pages and the poll incomplete, got %v %v", maxWatchPages, pages, complete)
+	}
+	if len(faults) != maxWatchPages+1 {
+		t.Errorf("expected %d distinct faults, got %v", maxWatchPages+1, faults)
+	}
+}
+
+func TestFaultWatches_Run(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(watchFaultsJSON))
+	}))
+	defer server.Close()
+
+	s, _, _ := NewReloadableServer(reloadTestConfig(), "test")
+	session := newTestSession(t, s)
+	client := hbapi.NewClient().WithBaseURL(server.URL).WithAuthToken("test-token")
+
+	watches := newFaultWatches(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	w := &faultWatch{ProjectID: 42}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	w.cancel = cancel
+	if err := watches.add(session.SessionID(), w); err != nil {
+		t.Fatalf("add() error = %v", err)
+	}
+	go watches.run(ctx, s, session.SessionID(), client, w, 10*time.Millisecond, time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC))
+
+	select {
+	case note := <-session.notifications:
+		if note.Method != string(mcp.MethodNotificationMessage) {
+			t.Fatalf("expected %s, got %s", mcp.MethodNotificationMessage, note.Method)
+		}
+		params := note.Params.AdditionalFields
+		if params["logger"] != watchLogger {
+			t.Errorf("expected logger %s, got %v", watchLogger, params["logger"])
+		}
+		data, _ := params["data"].(map[string]any)
+		if faults, _ := data["faults"].([]watchedFault); len(faults) != 2 {
+			t.Errorf("expected 2 faults in notification, got %v", data["faults"])
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected a watch notification")
+	}
+
+	if stopped := watches.stop(session.SessionID(), w.ID); len(stopped) != 1 {
+		t.Errorf("expected to stop 1 watch, got %d", len(stopped))
+	}
+	if ctx.Err() == nil {
+		t.Error("stopping a watch should cancel its poller")
+	}
+}
+
+func TestWatchFaults_Tools(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"results": [], "links": {}}`))
+	}))
+	defer server.Close()
+
+	cfg := reloadTestConfig()
+	cfg.APIURL = server.URL
+	s, _, _ := NewReloadableServer(cfg, "test")
+	session := newTestSession(t, s)
+	ctx := s.WithContext(context.Background(), session)
+
+	call := func(name string, args map[string]any) *mcp.CallToolResult {
+		t.Helper()
+		params, _ := json.Marshal(map[string]any{"name": name, "arguments": args})
+		msg := `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":` + string(params) + `}`
+		resp, ok := s.HandleMessage(ctx, []byte(msg)).(mcp.JSONRPCResponse)
+		if !ok {
+			t.Fatalf("expected JSON-RPC response calling %s", name)
+		}
+		result, ok := resp.Result.(*mcp.CallToolResult)
+		if !ok {
+			t.Fatalf("expected *mcp.CallToolResult, got %T", resp.Result)
+		}
+		return result
+	}
+
+	// Stateless http has no session to send notifications to later.
+	stateless := s.WithContext(context.Background(), &testSession{id: ""})
+	params := `{"name": "watch_faults", "arguments": {"project_id": 42}}`
+	resp, _ := s.HandleMessage(stateless, []byte(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":`+params+`}`)).(mcp.JSONRPCResponse)
+	if result, _ := resp.Result.(*mcp.CallToolResult); result == nil || !result.IsError || !strings.Contains(getResultText(result), "stateless") {
+		t.Errorf("expected watch_faults refused without a session ID, got %v", resp.Result)
+	}
+
+	result := call("watch_faults", map[string]any{"project_id": 42, "interval_seconds": 10})
+	if !result.IsError || !strings.Contains(getResultText(result), "interval_seconds: minimum: got 10, want 30") {
+		t.Errorf("expected interval error, got %q", getResultText(result))
+	}
+
+	var ids []string
+	for range maxWatchesPerSession {
+		result = call("watch_faults", map[string]any{"project_id": 42})
+		if result.IsError {
+			t.Fatalf("watch_faults failed: %s", getResultText(result))
+		}
+		var w faultWatch
+		if err := json.Unmarshal([]byte(getResultText(result)), &w); err != nil {
+			t.Fatalf("failed to unmarshal watch: %v", err)
+		}
+		ids = append(ids, w.ID)
+	}
+	if result = call("watch_faults", map[string]any{"project_id": 42}); !result.IsError {
+		t.Error("expected an error past the per-session watch limit")
+	}
+
+	result = call("unwatch_faults", map[string]any{"watch_id": ids[0]})
+	if result.IsError || !strings.Contains(getResultText(result), ids[0]) {
+		t.Errorf("expected %s to be stopped, got %q", ids[0], getResultText(result))
+	}
+	if result = call("unwatch_faults", map[string]any{"watch_id": ids[0]}); !result.IsError {
+		t.Error("expected an error stopping a watch twice")
+	}
+
+	// Ending the session stops the rest.
+	s.UnregisterSession(ctx, session.SessionID())
+	result = call("unwatch_faults", map[string]any{})
+	if strings.Contains(getResultText(result), "watch-") {
+		t.Errorf("expected no watches left after the session ended, got %q", getResultText(result))
+	}
+}
@@ -0,0 +1,240 @@
+package hbmcp
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/honeybadger-io/honeybadger-mcp-server/internal/config"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// webhookEventsURI lists the webhook payloads received most recently.
+	webhookEventsURI = "honeybadger://webhooks/events"
+	// webhookEventTemplate addresses one received payload in full.
+	webhookEventTemplate = "honeybadger://webhooks/events/{event_id}"
+
+	// maxWebhookEvents is how many payloads are kept for the resources.
+	maxWebhookEvents = 100
+	// maxWebhookBytes bounds a payload; Honeybadger's are a few KB.
+	maxWebhookBytes = 1 << 20
+
+	// webhookLogger names the source of webhook notifications, sent as
+	// notifications/message like watch_faults' so clients that surface
+	// server log messages show them.
+	webhookLogger = "honeybadger.webhook"
+
+	// WebhookSecretHeader carries the shared secret when it isn't in the
+	// URL's token parameter.
+	WebhookSecretHeader = "X-Honeybadger-Webhook-Secret"
+)
+
+// WebhookReceiver accepts the payloads of a Honeybadger webhook
+// integration and republishes each to every connected MCP session, as a
+// notifications/message and through the honeybadger://webhooks/events
+// resources, so an agent can react to a new fault without polling.
+type WebhookReceiver struct {
+	srv     *server.MCPServer
+	current func() *config.Config
+	secret  string
+	logger  *slog.Logger
+
+	mu     sync.Mutex
+	nextID int
+	events []webhookEvent // oldest first
+}
+
+// webhookEvent is one received payload with the parts agents look at
+// first pulled out of it.
+type webhookEvent struct {
+	ID          string          `json:"event_id"`
+	ReceivedAt  time.Time       `json:"received_at"`
+	Event       string          `json:"event"`
+	Message     string          `json:"message,omitempty"`
+	ProjectID   int             `json:"project_id,omitempty"`
+	ProjectName string          `json:"project_name,omitempty"`
+	FaultID     int             `json:"fault_id,omitempty"`
+	Klass       string          `json:"klass,omitempty"`
+	URL         string          `json:"url,omitempty"`
+	ResourceURI string          `json:"resource_uri"`
+	Payload     json.RawMessage `json:"payload,omitempty"`
+}
+
+// webhookPayload is the part of Honeybadger's webhook body the summary is
+// made from; the rest is passed on as received.
+type webhookPayload struct {
+	Event   string `json:"event"`
+	Message string `json:"message"`
+	Project *struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	} `json:"project"`
+	Fault *struct {
+		ID    int    `json:"id"`
+		Klass string `json:"klass"`
+		URL   string `json:"url"`
+	} `json:"fault"`
+}
+
+// NewWebhookReceiver returns a receiver that republishes to s, and
+// registers the resources it serves received payloads from. Payloads for
+// projects outside the project scope are dropped. With a secret, a request
+// must carry it in the token query parameter or WebhookSecretHeader.
+func NewWebhookReceiver(s *server.MCPServer, current func() *config.Config, secret string, logger *slog.Logger) *WebhookReceiver {
+	wr := &WebhookReceiver{srv: s, current: current, secret: secret, logger: logger}
+	s.AddResource(
+		mcp.NewResource(webhookEventsURI, "Recent webhook events",
+			mcp.WithResourceDescription(fmt.Sprintf("Summaries of the last %d Honeybadger webhook payloads this server received, newest first", maxWebhookEvents)),
+			mcp.WithMIMEType("application/json"),
+		),
+		func(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			return wr.readEvents(req)
+		},
+	)
+	s.AddResourceTemplate(
+		mcp.NewResourceTemplate(webhookEventTemplate, "Webhook event",
+			mcp.WithTemplateDescription("One received Honeybadger webhook payload in full"),
+			mcp.WithTemplateMIMEType("application/json"),
+		),
+		func(ctx context.Context, req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			return wr.readEvent(req)
+		},
+	)
+	return wr
+}
+
+func (wr *WebhookReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if wr.secret != "" {
+		given := r.URL.Query().Get("token")
+		if given == "" {
+			given = r.Header.Get(WebhookSecretHeader)
+		}
+		if subtle.ConstantTimeCompare([]byte(given), []byte(wr.secret)) != 1 {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBytes))
+	if err != nil {
+		http.Error(w, "payload too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	var payload webhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil || payload.Event == "" {
+		http.Error(w, "expected a Honeybadger webhook payload with an event", http.StatusBadRequest)
+		return
+	}
+	if payload.Project != nil && !wr.current().ProjectAllowed(payload.Project.ID) {
+		wr.logger.Debug("Dropping webhook for a project outside the scope", "project_id", payload.Project.ID, "event", payload.Event)
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	event := wr.add(payload, body, time.Now())
+	wr.logger.Info("Webhook received", "event", event.Event, "event_id", event.ID, "project_id", event.ProjectID, "fault_id", event.FaultID)
+	wr.publish(event)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// add keeps the payload, dropping the oldest beyond maxWebhookEvents, and
+// returns its event.
+func (wr *WebhookReceiver) add(payload webhookPayload, body []byte, now time.Time) webhookEvent {
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+	wr.nextID++
+	id := strconv.Itoa(wr.nextID)
+	event := webhookEvent{
+		ID:          id,
+		ReceivedAt:  now.UTC(),
+		Event:       payload.Event,
+		Message:     payload.Message,
+		ResourceURI: webhookEventsURI + "/" + id,
+		Payload:     body,
+	}
+	if payload.Project != nil {
+		event.ProjectID, event.ProjectName = payload.Project.ID, payload.Project.Name
+	}
+	if payload.Fault != nil {
+		event.FaultID, event.Klass, event.URL = payload.Fault.ID, payload.Fault.Klass, payload.Fault.URL
+	}
+	wr.events = append(wr.events, event)
+	if len(wr.events) > maxWebhookEvents {
+		wr.events = slices.Delete(wr.events, 0, len(wr.events)-maxWebhookEvents)
+	}
+	return event
+}
+
+// publish tells every session about event, and that the events list
+// changed.
+func (wr *WebhookReceiver) publish(event webhookEvent) {
+	summary := event
+	summary.Payload = nil
+	wr.srv.SendNotificationToAllClients(string(mcp.MethodNotificationMessage), map[string]any{
+		"level":  "info",
+		"logger": webhookLogger,
+		"data":   summary,
+	})
+	wr.srv.SendNotificationToAllClients(mcp.MethodNotificationResourceUpdated, map[string]any{
+		"uri": webhookEventsURI,
+	})
+}
+
+func (wr *WebhookReceiver) readEvents(req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	wr.mu.Lock()
+	summaries := make([]webhookEvent, 0, len(wr.events))
+	for _, e := range slices.Backward(wr.events) {
+		e.Payload = nil
+		summaries = append(summaries, e)
+	}
+	wr.mu.Unlock()
+
+	jsonBytes, err := json.Marshal(map[string][]webhookEvent{"events": summaries})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal events: %w", err)
+	}
+	return []mcp.ResourceContents{mcp.TextResourceContents{URI: req.Params.URI, MIMEType: "application/json", Text: string(jsonBytes)}}, nil
+}
+
+func (wr *WebhookReceiver) readEvent(req mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	var id string
+	switch v := req.Params.Arguments["event_id"].(type) {
+	case string:
+		id = v
+	case []string:
+		if len(v) > 0 {
+			id = v[0]
+		}
+	}
+	wr.mu.Lock()
+	i := slices.IndexFunc(wr.events, func(e webhookEvent) bool { return e.ID == id })
+	var event webhookEvent
+	if i >= 0 {
+		event = wr.events[i]
+	}
+	wr.mu.Unlock()
+	if i < 0 {
+		return nil, fmt.Errorf("webhook event %s not found; only the last %d are kept", id, maxWebhookEvents)
+	}
+
+	jsonBytes, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal event: %w", err)
+	}
+	return []mcp.ResourceContents{mcp.TextResourceContents{URI: req.Params.URI, MIMEType: "application/json", Text: string(jsonBytes)}}, nil
+}
@@ -0,0 +1,94 @@
+package hbmcp
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/honeybadger-io/honeybadger-mcp-server/internal/config"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// webhookSession is a client session that records the notifications sent
+// to it.
+type webhookSession struct {
+	notifications chan mcp.JSONRPCNotification
+}
+
+func (s *webhookSession) SessionID() string { return "webhook-test" }
+func (s *webhookSession) NotificationChannel() chan<- mcp.JSONRPCNotification {
+	return s.notifications
+}
+func (s *webhookSession) Initialize()       {}
+func (s *webhookSession) Initialized() bool { return true }
+
+func TestWebhookReceiver(t *testing.T) {
+	s := server.NewMCPServer("test", "1.0.0", server.WithResourceCapabilities(true, false))
+	session := &webhookSession{notifications: make(chan mcp.JSONRPCNotification, 10)}
+	if err := s.RegisterSession(context.Background(), session); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{DeniedProjectIDs: []int{3}}
+	receiver := NewWebhookReceiver(s, staticConfig(cfg), "s3cret", slog.Default())
+
+	post := func(target, body string) int {
+		rec := httptest.NewRecorder()
+		receiver.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, target, strings.NewReader(body)))
+		return rec.Code
+	}
+	payload := `{"event": "occurred", "message": "[Shop/production] RuntimeError: boom", "project": {"id": 1, "name": "Shop"}, "fault": {"id": 7, "klass": "RuntimeError", "url": "https://app.honeybadger.io/projects/1/faults/7"}}`
+
+	if code := post("/webhooks/honeybadger", payload); code != http.StatusUnauthorized {
+		t.Errorf("expected a request without the secret refused, got %d", code)
+	}
+	if code := post("/webhooks/honeybadger?token=s3cret", `{"message": "no event"}`); code != http.StatusBadRequest {
+		t.Errorf("expected a payload without an event rejected, got %d", code)
+	}
+	if code := post("/webhooks/honeybadger?token=s3cret", strings.Replace(payload, `"id": 1,`, `"id": 3,`, 1)); code != http.StatusAccepted {
+		t.Errorf("expected a payload outside the scope accepted and dropped, got %d", code)
+	}
+	if code := post("/webhooks/honeybadger?token=s3cret", payload); code != http.StatusAccepted {
+		t.Fatalf("expected the payload accepted, got %d", code)
+	}
+
+	var methods []string
+	for len(session.notifications) > 0 {
+		n := <-session.notifications
+		methods = append(methods, n.Method)
+		if n.Method == string(mcp.MethodNotificationMessage) {
+			if data := fmt.Sprint(n.Params.AdditionalFields["data"]); !strings.Contains(data, "RuntimeError") || !strings.Contains(data, webhookEventsURI+"/1") {
+				t.Errorf("unexpected notification data %s", data)
+			}
+		}
+	}
+	if strings.Join(methods, ",") != "notifications/message,notifications/resources/updated" {
+		t.Errorf("expected one event published, got %v", methods)
+	}
+
+	read := func(uri string) mcp.JSONRPCMessage {
+		return s.HandleMessage(context.Background(), []byte(fmt.Sprintf(`{"jsonrpc":"2.0","id":1,"method":"resources/read","params":{"uri":%q}}`, uri)))
+	}
+	resp, ok := read(webhookEventsURI).(mcp.JSONRPCResponse)
+	if !ok {
+		t.Fatalf("expected a result, got %#v", read(webhookEventsURI))
+	}
+	text := resp.Result.(mcp.ReadResourceResult).Contents[0].(mcp.TextResourceContents).Text
+	if !strings.Contains(text, `"fault_id":7`) || strings.Contains(text, `"payload"`) || strings.Count(text, "event_id") != 1 {
+		t.Errorf("expected the one event summarized, got %s", text)
+	}
+	resp, ok = read(webhookEventsURI + "/1").(mcp.JSONRPCResponse)
+	if !ok {
+		t.Fatalf("expected a result, got %#v", read(webhookEventsURI+"/1"))
+	}
+	if text := resp.Result.(mcp.ReadResourceResult).Contents[0].(mcp.TextResourceContents).Text; !strings.Contains(text, `"payload":{"event":"occurred"`) {
+		t.Errorf("expected the full payload, got %s", text)
+	}
+	if _, ok := read(webhookEventsURI + "/2").(mcp.JSONRPCError); !ok {
+		t.Error("expected an error reading an unknown event")
+	}
+}
@@ -0,0 +1,201 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const widgetTypeInsightsVis = "insights_vis"
+
+// widgetTypes are the widget types a dashboard accepts.
+var widgetTypes = []string{widgetTypeInsightsVis, "alarms", "errors", "deployments", "checkins", "uptime"}
+
+// gridKeys are a widget's grid position fields: column and row of its top
+// left corner, then its width and height in grid units.
+var gridKeys = []string{"x", "y", "w", "h"}
+
+// widgetsDescription documents the widgets argument of create_dashboard and
+// update_dashboard.
+const widgetsDescription = "JSON array of widget objects. The dashboards reference topic has the full widget schema and examples; build_insights_widget builds insights_vis widgets for you. Each widget needs: type (insights_vis, alarms, errors, deployments, checkins, uptime), and optionally: grid ({x,y,w,h}), presentation ({title, subtitle}), config (type-specific settings). For insights_vis widgets, config must include query (BadgerQL string) and should include vis ({view, chart_config}). Widgets are checked against this shape before the dashboard is sent."
+
+// parseWidgets decodes and validates a widgets argument.
+func parseWidgets(widgetsJSON string) ([]map[string]interface{}, *mcp.CallToolResult) {
+	var widgets []map[string]interface{}
+	if err := json.Unmarshal([]byte(widgetsJSON), &widgets); err != nil {
+		return nil, mcp.NewToolResultError(fmt.Sprintf("Failed to parse widgets JSON: %v", err))
+	}
+	if err := validateWidgets(widgets); err != nil {
+		return nil, mcp.NewToolResultError(fmt.Sprintf("Invalid widgets: %v", err))
+	}
+	return widgets, nil
+}
+
+// validateWidgets checks widgets against the dashboard widget schema,
+// reporting every problem found rather than just the first. Fields it
+// doesn't know are left for the API to judge.
+func validateWidgets(widgets []map[string]interface{}) error {
+	var errs []error
+	for i, w := range widgets {
+		for _, problem := range widgetProblems(w) {
+			errs = append(errs, fmt.Errorf("widget %d: %s", i, problem))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func widgetProblems(w map[string]interface{}) []string {
+	if w == nil {
+		return []string{"must be an object"}
+	}
+
+	var problems []string
+	widgetType, _ := w["type"].(string)
+	if !slices.Contains(widgetTypes, widgetType) {
+		problems = append(problems, fmt.Sprintf("type must be one of %s, got %v", strings.Join(widgetTypes, ", "), jsonValue(w["type"])))
+	}
+
+	if raw, ok := w["grid"]; ok {
+		grid, ok := raw.(map[string]interface{})
+		if !ok {
+			problems = append(problems, "grid must be an object")
+		}
+		for _, key := range gridKeys {
+			v, ok := grid[key]
+			if !ok {
+				continue
+			}
+			least := 0.0
+			if key == "w" || key == "h" {
+				least = 1
+			}
+			if n, ok := v.(float64); !ok || n < least || n != math.Trunc(n) {
+				problems = append(problems, fmt.Sprintf("grid.%s must be an integer of at least %d, got %v", key, int(least), jsonValue(v)))
+			}
+		}
+	}
+
+	if raw, ok := w["presentation"]; ok {
+		presentation, ok := raw.(map[string]interface{})
+		if !ok {
+			problems = append(problems, "presentation must be an object")
+		}
+		for _, key := range []string{"title", "subtitle"} {
+			if v, ok := presentation[key]; ok {
+				if _, ok := v.(string); !ok {
+					problems = append(problems, fmt.Sprintf("presentation.%s must be a string", key))
+				}
+			}
+		}
+	}
+
+	config, hasConfig := w["config"].(map[string]interface{})
+	if _, ok := w["config"]; ok && !hasConfig {
+		problems = append(problems, "config must be an object")
+	}
+	if widgetType == widgetTypeInsightsVis {
+		if query, _ := config["query"].(string); strings.TrimSpace(query) == "" {
+			problems = append(problems, "config.query must be a BadgerQL query for insights_vis widgets")
+		}
+		if raw, ok := config["vis"]; ok {
+			vis, ok := raw.(map[string]interface{})
+			if !ok {
+				problems = append(problems, "config.vis must be an object")
+			}
+			if view, ok := vis["view"]; ok {
+				if s, _ := view.(string); s == "" {
+					problems = append(problems, "config.vis.view must be a non-empty string")
+				}
+			}
+			if chartConfig, ok := vis["chart_config"]; ok {
+				if _, ok := chartConfig.(map[string]interface{}); !ok {
+					problems = append(problems, "config.vis.chart_config must be an object")
+				}
+			}
+		}
+	}
+	return problems
+}
+
+// jsonValue renders v as it appeared in the JSON, for error messages.
+func jsonValue(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+func handleBuildInsightsWidget(_ context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	query := req.GetString("query", "")
+	if strings.TrimSpace(query) == "" {
+		return mcp.NewToolResultError("query is required"), nil
+	}
+
+	view := req.GetString("view", "")
+	if view == "" {
+		return mcp.NewToolResultError("view is required"), nil
+	}
+
+	vis := map[string]interface{}{"view": view}
+	if chartConfigJSON := req.GetString("chart_config", ""); chartConfigJSON != "" {
+		var chartConfig map[string]interface{}
+		if err := json.Unmarshal([]byte(chartConfigJSON), &chartConfig); err != nil || chartConfig == nil {
+			return mcp.NewToolResultError("chart_config must be a JSON object"), nil
+		}
+		vis["chart_config"] = chartConfig
+	}
+
+	widget := map[string]interface{}{
+		"type":   widgetTypeInsightsVis,
+		"config": map[string]interface{}{"query": query, "vis": vis},
+	}
+
+	presentation := map[string]interface{}{}
+	for _, key := range []string{"title", "subtitle"} {
+		if v := req.GetString(key, ""); v != "" {
+			presentation[key] = v
+		}
+	}
+	if len(presentation) > 0 {
+		widget["presentation"] = presentation
+	}
+
+	args := req.GetArguments()
+	grid := map[string]interface{}{}
+	for _, key := range gridKeys {
+		if v, ok := args[key]; ok {
+			grid[key] = v
+		}
+	}
+	switch len(grid) {
+	case 0:
+	case len(gridKeys):
+		widget["grid"] = grid
+	default:
+		return mcp.NewToolResultError("give all of x, y, w, and h to position the widget, or none to leave placement to the dashboard"), nil
+	}
+
+	// Arguments built in Go rather than decoded from JSON can carry ints;
+	// round-trip so validation sees what the API will.
+	raw, err := json.Marshal(widget)
+	if err != nil {
+		return mcp.NewToolResultError("Failed to marshal response"), nil
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return mcp.NewToolResultError("Failed to marshal response"), nil
+	}
+	if problems := widgetProblems(decoded); len(problems) > 0 {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid widget: %s", strings.Join(problems, "; "))), nil
+	}
+
+	// Return JSON response
+	return mcp.NewToolResultText(string(raw)), nil
+}
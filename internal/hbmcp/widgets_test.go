@@ -0,0 +1,199 @@
+package hbmcp
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestValidateWidgets(t *testing.T) {
+	tests := []struct {
+		name    string
+		widgets string
+		want    []string
+	}{
+		{
+			name:    "valid widgets",
+			widgets: `[{"id": "w1", "type": "errors", "grid": {"x": 0, "y": 0, "w": 6, "h": 4}}, {"type": "insights_vis", "presentation": {"title": "Requests"}, "config": {"query": "stats count()", "vis": {"view": "line", "chart_config": {}}}}]`,
+		},
+		{
+			name:    "unknown type",
+			widgets: `[{"type": "graph"}]`,
+			want:    []string{`widget 0: type must be one of insights_vis, alarms, errors, deployments, checkins, uptime, got "graph"`},
+		},
+		{
+			name:    "missing type",
+			widgets: `[{}]`,
+			want:    []string{"widget 0: type must be one of"},
+		},
+		{
+			name:    "bad grid",
+			widgets: `[{"type": "errors", "grid": {"x": -1, "y": 1.5, "w": 0, "h": "4"}}]`,
+			want: []string{
+				"widget 0: grid.x must be an integer of at least 0, got -1",
+				"widget 0: grid.y must be an integer of at least 0, got 1.5",
+				"widget 0: grid.w must be an integer of at least 1, got 0",
+				`widget 0: grid.h must be an integer of at least 1, got "4"`,
+			},
+		},
+		{
+			name:    "bad presentation and config",
+			widgets: `[{"type": "errors", "presentation": {"title": 5}, "config": []}]`,
+			want:    []string{"widget 0: presentation.title must be a string", "widget 0: config must be an object"},
+		},
+		{
+			name:    "insights_vis without a query",
+			widgets: `[{"type": "errors"}, {"type": "insights_vis", "config": {"vis": {"view": "", "chart_config": "x"}}}]`,
+			want: []string{
+				"widget 1: config.query must be a BadgerQL query",
+				"widget 1: config.vis.view must be a non-empty string",
+				"widget 1: config.vis.chart_config must be an object",
+			},
+		},
+		{
+			name:    "null widget",
+			widgets: `[null]`,
+			want:    []string{"widget 0: must be an object"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var widgets []map[string]interface{}
+			if err := json.Unmarshal([]byte(tt.widgets), &widgets); err != nil {
+				t.Fatalf("bad test widgets: %v", err)
+			}
+			err := validateWidgets(widgets)
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Errorf("validateWidgets() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("validateWidgets() error = nil, want problems")
+			}
+			lines := strings.Split(err.Error(), "\n")
+			if len(lines) != len(tt.want) {
+				t.Fatalf("expected %d problems, got %q", len(tt.want), lines)
+			}
+			for i, want := range tt.want {
+				if !strings.HasPrefix(lines[i], want) {
+					t.Errorf("problem %d = %q, want prefix %q", i, lines[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestHandleCreateDashboard_InvalidWidgets(t *testing.T) {
+	req := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Arguments: map[string]interface{}{
+				"project_id": 123,
+				"title":      "Overview",
+				"widgets":    `[{"type": "insights_vis", "config": {}}]`,
+			},
+		},
+	}
+
+	// The client is never reached: invalid widgets are refused first.
+	result, err := handleCreateDashboard(context.Background(), nil, req)
+	if err != nil {
+		t.Fatalf("handleCreateDashboard() error = %v", err)
+	}
+	if !result.IsError || !strings.Contains(getResultText(result), "Invalid widgets: widget 0: config.query") {
+		t.Errorf("expected invalid widgets error, got %q", getResultText(result))
+	}
+}
+
+func TestHandleBuildInsightsWidget(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    map[string]interface{}
+		want    string
+		wantErr string
+	}{
+		{
+			name: "minimal",
+			args: map[string]interface{}{"query": "stats count()", "view": "line"},
+			want: `{"config":{"query":"stats count()","vis":{"view":"line"}},"type":"insights_vis"}`,
+		},
+		{
+			name: "all options",
+			args: map[string]interface{}{
+				"query":        "stats count() by bin(1h)",
+				"view":         "bar",
+				"chart_config": `{"stacked": true}`,
+				"title":        "Requests",
+				"subtitle":     "per hour",
+				"x":            float64(0),
+				"y":            float64(2),
+				"w":            float64(6),
+				"h":            float64(4),
+			},
+			want: `{"config":{"query":"stats count() by bin(1h)","vis":{"chart_config":{"stacked":true},"view":"bar"}},"grid":{"h":4,"w":6,"x":0,"y":2},"presentation":{"subtitle":"per hour","title":"Requests"},"type":"insights_vis"}`,
+		},
+		{
+			name:    "missing query",
+			args:    map[string]interface{}{"view": "line"},
+			wantErr: "query is required",
+		},
+		{
+			name:    "missing view",
+			args:    map[string]interface{}{"query": "stats count()"},
+			wantErr: "view is required",
+		},
+		{
+			name:    "chart_config not an object",
+			args:    map[string]interface{}{"query": "stats count()", "view": "line", "chart_config": `[1]`},
+			wantErr: "chart_config must be a JSON object",
+		},
+		{
+			name:    "partial grid",
+			args:    map[string]interface{}{"query": "stats count()", "view": "line", "x": float64(0), "w": float64(6)},
+			wantErr: "give all of x, y, w, and h",
+		},
+		{
+			name:    "invalid grid",
+			args:    map[string]interface{}{"query": "stats count()", "view": "line", "x": float64(0), "y": float64(0), "w": float64(0), "h": float64(4)},
+			wantErr: "Invalid widget: grid.w must be an integer of at least 1, got 0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := mcp.CallToolRequest{Params: mcp.CallToolParams{Arguments: tt.args}}
+			result, err := handleBuildInsightsWidget(context.Background(), req)
+			if err != nil {
+				t.Fatalf("handleBuildInsightsWidget() error = %v", err)
+			}
+			text := getResultText(result)
+			if tt.wantErr != "" {
+				if !result.IsError || !strings.Contains(text, tt.wantErr) {
+					t.Errorf("expected error containing %q, got %q", tt.wantErr, text)
+				}
+				return
+			}
+			if result.IsError {
+				t.Fatalf("unexpected error: %s", text)
+			}
+
+			var got, want map[string]interface{}
+			if err := json.Unmarshal([]byte(text), &got); err != nil {
+				t.Fatalf("failed to parse widget %q: %v", text, err)
+			}
+			_ = json.Unmarshal([]byte(tt.want), &want)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("widget = %s, want %s", text, tt.want)
+			}
+			if err := validateWidgets([]map[string]interface{}{got}); err != nil {
+				t.Errorf("built widget fails validation: %v", err)
+			}
+		})
+	}
+}
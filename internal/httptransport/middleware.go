@@ -63,3 +63,18 @@ func BearerFromRequest(r *http.Request) string {
 func HealthHandler(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 }
+
+// ReadyHandler answers readiness probes: 200 while ready reports nil, 503
+// with the reason otherwise, so an orchestrator stops routing to an
+// instance that can't verify tokens or is draining for shutdown.
+func ReadyHandler(ready func() error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		if err := ready(); err != nil {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprintln(w, err)
+			return
+		}
+		fmt.Fprintln(w, "ok")
+	})
+}
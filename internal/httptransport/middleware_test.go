@@ -4,6 +4,7 @@ import (
 	"crypto/rand"
 	"crypto/rsa"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -200,6 +201,24 @@ func TestHealthHandler(t *testing.T) {
 	}
 }
 
+func TestReadyHandler(t *testing.T) {
+	var notReady error
+	handler := ReadyHandler(func() error { return notReady })
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/readyz", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200", rec.Code)
+	}
+
+	notReady = errors.New("shutting down")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/readyz", nil))
+	if rec.Code != http.StatusServiceUnavailable || !strings.Contains(rec.Body.String(), "shutting down") {
+		t.Errorf("got %d %q, want 503 with the reason", rec.Code, rec.Body.String())
+	}
+}
+
 // Regression guard: /healthz is reserved by main; the PRM path must not collide.
 func TestPRMPathNotHealthz(t *testing.T) {
 	if WellKnownPRMPath == "/healthz" {
@@ -1,6 +1,7 @@
 package logging
 
 import (
+	"context"
 	"log/slog"
 	"os"
 	"strings"
@@ -27,7 +28,7 @@ func SetupLogger(level string) *slog.Logger {
 		Level: slogLevel,
 	}
 
-	handler := slog.NewTextHandler(os.Stderr, opts)
+	handler := contextHandler{slog.NewTextHandler(os.Stderr, opts)}
 	logger := slog.New(handler)
 
 	// Set as default logger
@@ -51,3 +52,33 @@ func ParseLogLevel(level string) slog.Level {
 		return slog.LevelInfo
 	}
 }
+
+type contextAttrsKey struct{}
+
+// ContextWithAttrs returns ctx carrying attrs, which loggers from
+// SetupLogger add to every record logged with ctx (InfoContext and the
+// like), such as the correlation ID of the tool call a request is for.
+func ContextWithAttrs(ctx context.Context, attrs ...slog.Attr) context.Context {
+	prev, _ := ctx.Value(contextAttrsKey{}).([]slog.Attr)
+	return context.WithValue(ctx, contextAttrsKey{}, append(prev[:len(prev):len(prev)], attrs...))
+}
+
+// contextHandler adds the attrs from ContextWithAttrs to each record.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if attrs, ok := ctx.Value(contextAttrsKey{}).([]slog.Attr); ok {
+		r.AddAttrs(attrs...)
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
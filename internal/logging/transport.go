@@ -0,0 +1,111 @@
+package logging
+
+import (
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// secretParam matches query parameter names whose values aren't logged.
+var secretParam = regexp.MustCompile(`(?i)token|key|secret|password|signature`)
+
+// Transport logs each outbound request at debug level once its response
+// body has been read: method, path, status, duration, and bytes. Failed
+// requests (an error or a 4xx/5xx status) are always logged; successful
+// ones are sampled at sampleRate, so a busy server can keep debug logging
+// on. Query values that look like secrets are redacted, and headers are
+// never logged.
+type Transport struct {
+	base       http.RoundTripper
+	logger     *slog.Logger
+	sampleRate float64
+
+	// Overridable in tests; defaults to math/rand/v2.
+	random func() float64
+}
+
+// NewTransport returns a Transport logging the requests base makes to
+// logger. A sampleRate of 1 logs every request.
+func NewTransport(base http.RoundTripper, logger *slog.Logger, sampleRate float64) *Transport {
+	return &Transport{base: base, logger: logger, sampleRate: sampleRate, random: rand.Float64}
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	if !t.logger.Enabled(ctx, slog.LevelDebug) {
+		return t.base.RoundTrip(req)
+	}
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	attrs := []any{
+		"method", req.Method,
+		"path", RedactURL(req.URL),
+		"request_bytes", max(req.ContentLength, 0),
+	}
+	if err != nil {
+		t.logger.DebugContext(ctx, "API request failed", append(attrs, "duration", time.Since(start), "error", err)...)
+		return resp, err
+	}
+	if resp.StatusCode < 400 && t.random() >= t.sampleRate {
+		return resp, nil
+	}
+
+	// The response is logged when its body is closed, so the duration and
+	// bytes cover reading it, which is where a decode failure shows up.
+	resp.Body = &loggedBody{ReadCloser: resp.Body, done: func(n int64, readErr error) {
+		attrs = append(attrs, "status", resp.StatusCode, "content_type", resp.Header.Get("Content-Type"), "response_bytes", n, "duration", time.Since(start))
+		if readErr != nil && readErr != io.EOF {
+			attrs = append(attrs, "error", readErr)
+		}
+		t.logger.DebugContext(ctx, "API request", attrs...)
+	}}
+	return resp, nil
+}
+
+// loggedBody counts the bytes read from a response body and calls done
+// once, when it is closed.
+type loggedBody struct {
+	io.ReadCloser
+	n    int64
+	err  error
+	once sync.Once
+	done func(n int64, err error)
+}
+
+func (b *loggedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.n += int64(n)
+	if err != nil {
+		b.err = err
+	}
+	return n, err
+}
+
+func (b *loggedBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(func() { b.done(b.n, b.err) })
+	return err
+}
+
+// RedactURL returns u's path and query for logging, with the values of
+// parameters that look like secrets replaced by REDACTED.
+func RedactURL(u *url.URL) string {
+	if u.RawQuery == "" {
+		return u.Path
+	}
+	q := u.Query()
+	for name, values := range q {
+		if secretParam.MatchString(name) {
+			for i := range values {
+				values[i] = "REDACTED"
+			}
+		}
+	}
+	return u.Path + "?" + q.Encode()
+}
@@ -0,0 +1,70 @@
+package logging
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok": true}`))
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	transport := NewTransport(http.DefaultTransport, logger, 0.5)
+	sample := 0.9
+	transport.random = func() float64 { return sample }
+	client := &http.Client{Transport: transport}
+
+	get := func(path string) {
+		resp, err := client.Get(server.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, _ = io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+	}
+
+	get("/projects?auth_token=secret")
+	if buf.Len() != 0 {
+		t.Errorf("expected a successful request outside the sample left out, got %s", buf.String())
+	}
+
+	get("/missing")
+	if got := buf.String(); !strings.Contains(got, "status=404") || !strings.Contains(got, "path=/missing") {
+		t.Errorf("expected a failed request always logged, got %s", got)
+	}
+
+	buf.Reset()
+	sample = 0.1
+	get("/projects?auth_token=secret&page=2")
+	got := buf.String()
+	for _, want := range []string{"method=GET", "status=200", "response_bytes=12", "content_type=application/json", "auth_token=REDACTED"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in %s", want, got)
+		}
+	}
+	if strings.Contains(got, "secret") {
+		t.Errorf("expected the token redacted, got %s", got)
+	}
+}
+
+func TestRedactURL(t *testing.T) {
+	u, _ := url.Parse("https://api.example.com/v2/projects?api_key=abc&q=boom")
+	if got := RedactURL(u); got != "/v2/projects?api_key=REDACTED&q=boom" {
+		t.Errorf("RedactURL() = %q", got)
+	}
+}
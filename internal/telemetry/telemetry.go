@@ -0,0 +1,57 @@
+package telemetry
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+// ServiceName is reported when OTEL_SERVICE_NAME isn't set.
+const ServiceName = "honeybadger-mcp-server"
+
+// Enabled reports whether an OTLP endpoint is configured through the
+// standard OpenTelemetry environment variables and the SDK isn't disabled.
+func Enabled() bool {
+	if strings.EqualFold(os.Getenv("OTEL_SDK_DISABLED"), "true") {
+		return false
+	}
+	return os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" || os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") != ""
+}
+
+// Setup installs a global tracer provider that batches spans to the OTLP/HTTP
+// endpoint from the environment (OTEL_EXPORTER_OTLP_ENDPOINT, _HEADERS, etc.).
+// When tracing isn't enabled it does nothing and the global provider stays a
+// no-op. The returned shutdown flushes pending spans.
+func Setup(ctx context.Context, version string) (shutdown func(context.Context) error, err error) {
+	if !Enabled() {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, err
+	}
+	attrs := []attribute.KeyValue{semconv.ServiceVersion(version)}
+	// resource.Default already carries OTEL_SERVICE_NAME when it's set.
+	if os.Getenv("OTEL_SERVICE_NAME") == "" {
+		attrs = append(attrs, semconv.ServiceName(ServiceName))
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(attrs...))
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
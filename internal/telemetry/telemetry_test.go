@@ -0,0 +1,44 @@
+package telemetry
+
+import (
+	"context"
+	"testing"
+)
+
+func TestEnabled(t *testing.T) {
+	tests := []struct {
+		name     string
+		endpoint string
+		traces   string
+		disabled string
+		want     bool
+	}{
+		{"unconfigured", "", "", "", false},
+		{"endpoint", "http://localhost:4318", "", "", true},
+		{"traces endpoint", "", "http://localhost:4318/v1/traces", "", true},
+		{"sdk disabled", "http://localhost:4318", "", "true", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", tt.endpoint)
+			t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", tt.traces)
+			t.Setenv("OTEL_SDK_DISABLED", tt.disabled)
+			if got := Enabled(); got != tt.want {
+				t.Errorf("Enabled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetup_DisabledIsNoop(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+
+	shutdown, err := Setup(context.Background(), "test")
+	if err != nil {
+		t.Fatalf("Setup() error = %v", err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Errorf("shutdown() error = %v", err)
+	}
+}